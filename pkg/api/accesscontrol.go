@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/plugins"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/correlations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -141,6 +142,51 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		Grants: []string{string(org.RoleAdmin)},
 	}
 
+	correlationsReaderRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:correlations:reader",
+			DisplayName: "Correlations reader",
+			Description: "Read the correlations of all data sources.",
+			Group:       "Data sources",
+			Permissions: []ac.Permission{
+				{
+					Action: correlations.ActionRead,
+					Scope:  correlations.ScopeAll,
+				},
+			},
+		},
+		Grants: []string{string(org.RoleEditor)},
+	}
+
+	// when running oss or enterprise without a license all users should be able to read correlations, like data sources
+	if !hs.License.FeatureEnabled("accesscontrol.enforcement") {
+		correlationsReaderRole.Grants = []string{string(org.RoleViewer)}
+	}
+
+	correlationsWriterRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:correlations:writer",
+			DisplayName: "Correlations writer",
+			Description: "Create, update, delete or read the correlations of all data sources.",
+			Group:       "Data sources",
+			Permissions: ac.ConcatPermissions(correlationsReaderRole.Role.Permissions, []ac.Permission{
+				{
+					Action: correlations.ActionCreate,
+					Scope:  correlations.ScopeAll,
+				},
+				{
+					Action: correlations.ActionWrite,
+					Scope:  correlations.ScopeAll,
+				},
+				{
+					Action: correlations.ActionDelete,
+					Scope:  correlations.ScopeAll,
+				},
+			}),
+		},
+		Grants: []string{string(org.RoleAdmin)},
+	}
+
 	datasourcesIdReaderRole := ac.RoleRegistration{
 		Role: ac.RoleDTO{
 			Name:        "fixed:datasources.id:reader",
@@ -429,7 +475,7 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		annotationsReaderRole, dashboardAnnotationsWriterRole, annotationsWriterRole,
 		dashboardsCreatorRole, dashboardsReaderRole, dashboardsWriterRole,
 		foldersCreatorRole, foldersReaderRole, foldersWriterRole, apikeyReaderRole, apikeyWriterRole,
-		publicDashboardsWriterRole,
+		publicDashboardsWriterRole, correlationsReaderRole, correlationsWriterRole,
 	)
 }
 
@@ -1,11 +1,13 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	skv "github.com/grafana/grafana/pkg/services/secrets/kvstore"
 )
 
@@ -38,6 +40,21 @@ func (hs *HTTPServer) AdminReEncryptSecrets(c *models.ReqContext) response.Respo
 	return response.Respond(http.StatusOK, "Secrets re-encrypted successfully")
 }
 
+func (hs *HTTPServer) AdminReEncryptAlertingSecrets(c *models.ReqContext) response.Response {
+	if err := hs.secretsMigrator.ReEncryptAlertingSecrets(c.Req.Context()); err != nil {
+		if errors.Is(err, secrets.ErrReEncryptionInProgress) {
+			return response.Error(http.StatusConflict, "Alerting secrets re-encryption already in progress", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to re-encrypt alerting secrets", err)
+	}
+
+	return response.JSON(http.StatusAccepted, hs.secretsMigrator.AlertingSecretsReEncryptionProgress())
+}
+
+func (hs *HTTPServer) AdminGetAlertingSecretsReEncryptionProgress(c *models.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, hs.secretsMigrator.AlertingSecretsReEncryptionProgress())
+}
+
 func (hs *HTTPServer) AdminRollbackSecrets(c *models.ReqContext) response.Response {
 	success, err := hs.secretsMigrator.RollBackSecrets(c.Req.Context())
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/alerting"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/setting"
@@ -231,9 +232,15 @@ func (hs *HTTPServer) GetAlert(c *models.ReqContext) response.Response {
 }
 
 func (hs *HTTPServer) GetAlertNotifiers(ngalertEnabled bool) func(*models.ReqContext) response.Response {
-	return func(_ *models.ReqContext) response.Response {
+	return func(c *models.ReqContext) response.Response {
 		if ngalertEnabled {
-			return response.JSON(http.StatusOK, channels_config.GetAvailableNotifiers())
+			notifiers := make([]*channels_config.NotifierPlugin, 0)
+			for _, n := range channels_config.GetAvailableNotifiers() {
+				if channels.IsReceiverAvailable(hs.Cfg, n.Type, c.OrgID) {
+					notifiers = append(notifiers, n)
+				}
+			}
+			return response.JSON(http.StatusOK, notifiers)
 		}
 		// TODO(codesome): This wont be required in 8.0 since ngalert
 		// will be enabled by default with no disabling. This is to be removed later.
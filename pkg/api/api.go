@@ -42,6 +42,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	publicdashboardsapi "github.com/grafana/grafana/pkg/services/publicdashboards/api"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
@@ -142,11 +143,23 @@ func (hs *HTTPServer) registerRoutes() {
 
 	if hs.Features.IsEnabled(featuremgmt.FlagPublicDashboards) {
 		r.Get("/public-dashboards/:accessToken",
+			publicdashboardsapi.RejectBlockedClients(hs.PublicDashboardsApi.AbuseDetector, hs.PublicDashboardsApi.TrustedProxies),
+			publicdashboardsapi.RejectRestrictedCountries(hs.PublicDashboardsApi.PublicDashboardService, hs.PublicDashboardsApi.GeoIP),
+			publicdashboardsapi.RequiresExternalViewer(hs.PublicDashboardsApi.PublicDashboardService, hs.PublicDashboardsApi.JWTService,
+				hs.PublicDashboardsApi.ExternalViewerHeader, hs.PublicDashboardsApi.Log),
+			publicdashboardsapi.SetRobotsTagHeader(hs.PublicDashboardsApi.PublicDashboardService),
 			publicdashboardsapi.SetPublicDashboardFlag,
 			publicdashboardsapi.SetPublicDashboardOrgIdOnContext(hs.PublicDashboardsApi.PublicDashboardService),
 			publicdashboardsapi.CountPublicDashboardRequest(),
 			hs.Index,
 		)
+		r.Get("/public-playlists/:accessToken",
+			publicdashboardsapi.RejectBlockedClients(hs.PublicDashboardsApi.AbuseDetector, hs.PublicDashboardsApi.TrustedProxies),
+			publicdashboardsapi.SetRobotsTagHeader(hs.PublicDashboardsApi.PublicDashboardService),
+			publicdashboardsapi.SetPublicDashboardFlag,
+			publicdashboardsapi.SetPublicPlaylistOrgIdOnContext(hs.PublicDashboardsApi.PublicDashboardService),
+			hs.Index,
+		)
 	}
 
 	r.Get("/explore", authorize(func(c *models.ReqContext) {
@@ -533,7 +546,7 @@ func (hs *HTTPServer) registerRoutes() {
 
 		apiRoute.Group("/alert-notifications", func(alertNotifications routing.RouteRegister) {
 			alertNotifications.Get("/", routing.Wrap(hs.GetAlertNotifications))
-			alertNotifications.Post("/test", routing.Wrap(hs.NotificationTest))
+			alertNotifications.Post("/test", hs.RateLimits.Middleware(ratelimit.NotificationTests, ratelimit.ByUser), routing.Wrap(hs.NotificationTest))
 			alertNotifications.Post("/", routing.Wrap(hs.CreateAlertNotification))
 			alertNotifications.Put("/:notificationId", routing.Wrap(hs.UpdateAlertNotification))
 			alertNotifications.Get("/:notificationId", routing.Wrap(hs.GetAlertNotificationByID))
@@ -626,6 +639,8 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Post("/encryption/rotate-data-keys", reqGrafanaAdmin, routing.Wrap(hs.AdminRotateDataEncryptionKeys))
 		adminRoute.Post("/encryption/reencrypt-data-keys", reqGrafanaAdmin, routing.Wrap(hs.AdminReEncryptEncryptionKeys))
 		adminRoute.Post("/encryption/reencrypt-secrets", reqGrafanaAdmin, routing.Wrap(hs.AdminReEncryptSecrets))
+		adminRoute.Post("/encryption/reencrypt-alerting-secrets", reqGrafanaAdmin, routing.Wrap(hs.AdminReEncryptAlertingSecrets))
+		adminRoute.Get("/encryption/reencrypt-alerting-secrets", reqGrafanaAdmin, routing.Wrap(hs.AdminGetAlertingSecretsReEncryptionProgress))
 		adminRoute.Post("/encryption/rollback-secrets", reqGrafanaAdmin, routing.Wrap(hs.AdminRollbackSecrets))
 		adminRoute.Post("/encryption/migrate-secrets/to-plugin", reqGrafanaAdmin, routing.Wrap(hs.AdminMigrateSecretsToPlugin))
 		adminRoute.Post("/encryption/migrate-secrets/from-plugin", reqGrafanaAdmin, routing.Wrap(hs.AdminMigrateSecretsFromPlugin))
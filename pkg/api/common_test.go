@@ -45,6 +45,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
 	"github.com/grafana/grafana/pkg/services/preference/preftest"
 	"github.com/grafana/grafana/pkg/services/quota/quotaimpl"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/services/searchusers"
@@ -260,6 +261,7 @@ func setupAccessControlScenarioContext(t *testing.T, cfg *setting.Cfg, url strin
 		AccessControl:      accesscontrolmock.New().WithPermissions(permissions),
 		searchUsersService: searchusers.ProvideUsersService(filters.ProvideOSSSearchUserFilter(), usertest.NewUserServiceFake()),
 		ldapGroups:         ldap.ProvideGroupsService(),
+		RateLimits:         ratelimit.ProvideService(cfg, nil),
 	}
 
 	sc := setupScenarioContext(t, url)
@@ -425,6 +427,7 @@ func setupHTTPServerWithCfgDb(
 		orgService:        orgMock,
 		teamService:       teamService,
 		annotationsRepo:   annotationstest.NewFakeAnnotationsRepo(),
+		RateLimits:        ratelimit.ProvideService(cfg, nil),
 	}
 
 	for _, o := range options {
@@ -501,6 +504,7 @@ func SetupAPITestServer(t *testing.T, opts ...APITestServerOption) *webtest.Serv
 	for _, opt := range opts {
 		opt(hs)
 	}
+	hs.RateLimits = ratelimit.ProvideService(hs.Cfg, nil)
 
 	hs.registerRoutes()
 	s := webtest.NewServer(t, hs.RouteRegister)
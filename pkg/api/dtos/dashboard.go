@@ -35,6 +35,7 @@ type DashboardMeta struct {
 	PublicDashboardAccessToken string                `json:"publicDashboardAccessToken"`
 	PublicDashboardUID         string                `json:"publicDashboardUid"`
 	PublicDashboardEnabled     bool                  `json:"publicDashboardEnabled"`
+	PublicDashboardTermsOfUse  string                `json:"publicDashboardTermsOfUse,omitempty"`
 }
 type AnnotationPermission struct {
 	Dashboard    AnnotationActions `json:"dashboard"`
@@ -56,6 +56,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources/permissions"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/export"
+	"github.com/grafana/grafana/pkg/services/exportbundle"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/hooks"
 	"github.com/grafana/grafana/pkg/services/ldap"
@@ -78,6 +79,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/services/searchusers"
@@ -138,6 +140,8 @@ type HTTPServer struct {
 	ShortURLService              shorturls.Service
 	QueryHistoryService          queryhistory.Service
 	CorrelationsService          correlations.Service
+	ExportBundleService          exportbundle.Service
+	RateLimits                   ratelimit.Service
 	Live                         *live.GrafanaLive
 	LivePushGateway              *pushhttp.Gateway
 	ThumbService                 thumbs.Service
@@ -248,6 +252,7 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 	accesscontrolService accesscontrol.Service, dashboardThumbsService thumbs.DashboardThumbService, navTreeService navtree.Service,
 	annotationRepo annotations.Repository, tagService tag.Service, searchv2HTTPService searchV2.SearchHTTPService,
 	userAuthService userauth.Service, queryLibraryHTTPService querylibrary.HTTPService, queryLibraryService querylibrary.Service,
+	exportBundleService exportbundle.Service, rateLimits ratelimit.Service,
 ) (*HTTPServer, error) {
 	web.Env = cfg.Env
 	m := web.New()
@@ -278,6 +283,8 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 		ShortURLService:              shortURLService,
 		QueryHistoryService:          queryHistoryService,
 		CorrelationsService:          correlationsService,
+		ExportBundleService:          exportBundleService,
+		RateLimits:                   rateLimits,
 		Features:                     features,
 		ThumbService:                 thumbService,
 		StorageService:               storageService,
@@ -581,7 +588,11 @@ func (hs *HTTPServer) addMiddlewaresAndStaticRoutes() {
 
 	hs.mapStatic(m, hs.Cfg.StaticRootPath, "build", "public/build")
 	hs.mapStatic(m, hs.Cfg.StaticRootPath, "", "public", "/public/views/swagger.html")
-	hs.mapStatic(m, hs.Cfg.StaticRootPath, "robots.txt", "robots.txt")
+	if hs.Features.IsEnabled(featuremgmt.FlagPublicDashboards) && hs.Cfg.PublicDashboards.AllowIndexing {
+		m.Use(hs.publicDashboardsRobotsTxtHandler)
+	} else {
+		hs.mapStatic(m, hs.Cfg.StaticRootPath, "robots.txt", "robots.txt")
+	}
 
 	if hs.Cfg.ImageUploadProvider == "local" {
 		hs.mapStatic(m, hs.Cfg.ImagesDir, "", "/public/img/attachments")
@@ -653,6 +664,26 @@ func (hs *HTTPServer) healthzHandler(ctx *web.Context) {
 	}
 }
 
+// publicDashboardsRobotsTxtHandler serves a robots.txt letting search engines
+// crawl the public dashboards, used instead of the static robots.txt when the
+// instance allows public dashboards to be indexed. Public dashboards denying
+// indexing are served with a X-Robots-Tag: noindex header.
+func (hs *HTTPServer) publicDashboardsRobotsTxtHandler(ctx *web.Context) {
+	notHeadOrGet := ctx.Req.Method != http.MethodGet && ctx.Req.Method != http.MethodHead
+	if notHeadOrGet || ctx.Req.URL.Path != "/robots.txt" {
+		return
+	}
+
+	robotsTxt := fmt.Sprintf("User-agent: *\nAllow: %s/public-dashboards/\nDisallow: /\n", hs.Cfg.AppSubURL)
+
+	ctx.Resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	ctx.Resp.WriteHeader(200)
+	_, err := ctx.Resp.Write([]byte(robotsTxt))
+	if err != nil {
+		hs.log.Error("could not write to response", "err", err)
+	}
+}
+
 // apiHealthHandler will return ok if Grafana's web server is running and it
 // can access the database. If the database cannot be accessed it will return
 // http status code 503.
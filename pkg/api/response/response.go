@@ -225,11 +225,22 @@ func Error(status int, message string, err error) *NormalResponse {
 	return resp
 }
 
-// Err creates an error response based on an errutil.Error error.
+// Err creates an error response based on an errutil.Error error, or on
+// any other error implementing errutil.PublicErr.
 func Err(err error) *NormalResponse {
 	grafanaErr := &errutil.Error{}
 	if !errors.As(err, grafanaErr) {
-		return Error(http.StatusInternalServerError, "", fmt.Errorf("unexpected error type [%s]: %w", reflect.TypeOf(err), err))
+		var publicErr errutil.PublicErr
+		if !errors.As(err, &publicErr) {
+			return Error(http.StatusInternalServerError, "", fmt.Errorf("unexpected error type [%s]: %w", reflect.TypeOf(err), err))
+		}
+
+		public := publicErr.Public()
+		resp := JSON(public.StatusCode, public)
+		resp.errMessage = public.Message
+		resp.err = err
+
+		return resp
 	}
 
 	resp := JSON(grafanaErr.Reason.Status().HTTPStatus(), grafanaErr.Public())
@@ -239,7 +250,7 @@ func Err(err error) *NormalResponse {
 	return resp
 }
 
-// ErrOrFallback uses the information in an errutil.Error if available
+// ErrOrFallback uses the information in an errutil.PublicErr if available
 // and otherwise falls back to the status and message provided as
 // arguments.
 //
@@ -247,8 +258,8 @@ func Err(err error) *NormalResponse {
 // rename this to Error when we're confident that that would be safe to
 // do.
 func ErrOrFallback(status int, message string, err error) *NormalResponse {
-	grafanaErr := &errutil.Error{}
-	if errors.As(err, grafanaErr) {
+	var publicErr errutil.PublicErr
+	if errors.As(err, &publicErr) {
 		return Err(err)
 	}
 
@@ -2,6 +2,7 @@ package response
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -69,6 +70,23 @@ func TestErrors(t *testing.T) {
 			},
 			fallbackUseNew: true,
 		},
+		{
+			name: "public error with fallback to other error",
+
+			err:        fmt.Errorf("wrapped: %w", fakePublicErr{}),
+			statusCode: http.StatusBadRequest,
+			message:    genericErrorMessage,
+
+			legacyResponse: &NormalResponse{
+				status:     http.StatusBadRequest,
+				errMessage: genericErrorMessage,
+			},
+			newResponse: &NormalResponse{
+				status:     http.StatusConflict,
+				errMessage: "thing already exists",
+			},
+			fallbackUseNew: true,
+		},
 	}
 
 	compareResponses := func(expected *NormalResponse, actual *NormalResponse, compareErr bool) func(t *testing.T) {
@@ -125,3 +143,13 @@ func TestErrors(t *testing.T) {
 		)
 	}
 }
+
+type fakePublicErr struct{}
+
+func (fakePublicErr) Error() string {
+	return "thing already exists"
+}
+
+func (e fakePublicErr) Public() errutil.PublicError {
+	return errutil.PublicError{StatusCode: http.StatusConflict, MessageID: "thing.alreadyExists", Message: e.Error()}
+}
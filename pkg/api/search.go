@@ -24,6 +24,7 @@ func (hs *HTTPServer) Search(c *models.ReqContext) response.Response {
 	query := c.Query("query")
 	tags := c.QueryStrings("tag")
 	starred := c.Query("starred")
+	public := c.Query("public")
 	limit := c.QueryInt64("limit")
 	page := c.QueryInt64("page")
 	dashboardType := c.Query("type")
@@ -71,6 +72,7 @@ func (hs *HTTPServer) Search(c *models.ReqContext) response.Response {
 		Limit:         limit,
 		Page:          page,
 		IsStarred:     starred == "true",
+		IsPublic:      public == "true",
 		OrgId:         c.OrgID,
 		DashboardIds:  dbIDs,
 		DashboardUIDs: dbUIDs,
@@ -190,6 +192,10 @@ type SearchParams struct {
 	// in:query
 	// required: false
 	Starred bool `json:"starred"`
+	// Flag indicating if only dashboards with an enabled public dashboard should be returned
+	// in:query
+	// required: false
+	Public bool `json:"public"`
 	// Limit the number of returned results (max 5000)
 	// in:query
 	// required: false
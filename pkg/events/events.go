@@ -63,6 +63,16 @@ type DataSourceSecretDeleted struct {
 	OrgID     int64     `json:"org_id"`
 }
 
+// DataSourceUIDChanged is published when a data source is updated with another UID
+type DataSourceUIDChanged struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Name        string    `json:"name"`
+	ID          int64     `json:"id"`
+	PreviousUID string    `json:"previous_uid"`
+	UID         string    `json:"uid"`
+	OrgID       int64     `json:"org_id"`
+}
+
 type DataSourceCreated struct {
 	Timestamp time.Time `json:"timestamp"`
 	Name      string    `json:"name"`
@@ -78,3 +88,86 @@ type FolderTitleUpdated struct {
 	UID       string    `json:"uid"`
 	OrgID     int64     `json:"org_id"`
 }
+
+type DashboardDeleted struct {
+	Timestamp time.Time `json:"timestamp"`
+	Title     string    `json:"title"`
+	ID        int64     `json:"id"`
+	UID       string    `json:"uid"`
+	OrgID     int64     `json:"org_id"`
+}
+
+type PublicDashboardEnabled struct {
+	Timestamp    time.Time `json:"timestamp"`
+	UID          string    `json:"uid"`
+	DashboardUID string    `json:"dashboard_uid"`
+	OrgID        int64     `json:"org_id"`
+	UserID       int64     `json:"user_id"`
+}
+
+type PublicDashboardDisabled struct {
+	Timestamp    time.Time `json:"timestamp"`
+	UID          string    `json:"uid"`
+	DashboardUID string    `json:"dashboard_uid"`
+	OrgID        int64     `json:"org_id"`
+	UserID       int64     `json:"user_id"`
+}
+
+// PublicDashboardViewed is published every time an anonymous viewer loads a
+// public dashboard, for usage insights to cover public traffic
+type PublicDashboardViewed struct {
+	Timestamp    time.Time `json:"timestamp"`
+	UID          string    `json:"uid"`
+	DashboardUID string    `json:"dashboard_uid"`
+	OrgID        int64     `json:"org_id"`
+}
+
+// CorrelationResolved is published every time a user follows a correlation
+// link, for usage insights to cover correlations
+type CorrelationResolved struct {
+	Timestamp time.Time `json:"timestamp"`
+	UID       string    `json:"uid"`
+	SourceUID string    `json:"source_uid"`
+	TargetUID string    `json:"target_uid,omitempty"`
+	OrgID     int64     `json:"org_id"`
+	UserID    int64     `json:"user_id"`
+}
+
+// CorrelationCreated is published once a correlation is created, including by
+// bulk commands, imports and copies once all their correlations are committed.
+// SourceUID is empty and SourceType set for the correlations of a data source type.
+type CorrelationCreated struct {
+	Timestamp  time.Time `json:"timestamp"`
+	UID        string    `json:"uid"`
+	SourceUID  string    `json:"source_uid"`
+	SourceType string    `json:"source_type,omitempty"`
+	TargetUID  string    `json:"target_uid,omitempty"`
+	OrgID      int64     `json:"org_id"`
+	UserID     int64     `json:"user_id"`
+}
+
+// CorrelationUpdated is published once a correlation is updated or restored to
+// a previous version. Fields lists the changed fields, such as label or config.
+type CorrelationUpdated struct {
+	Timestamp  time.Time `json:"timestamp"`
+	UID        string    `json:"uid"`
+	SourceUID  string    `json:"source_uid"`
+	SourceType string    `json:"source_type,omitempty"`
+	TargetUID  string    `json:"target_uid,omitempty"`
+	OrgID      int64     `json:"org_id"`
+	UserID     int64     `json:"user_id"`
+	Fields     []string  `json:"fields"`
+	Restored   bool      `json:"restored"`
+}
+
+// CorrelationDeleted is published once a correlation is deleted. The
+// correlations deleted with their data source or dashboard aren't published
+// one by one, DataSourceDeleted and DashboardDeleted cover them.
+type CorrelationDeleted struct {
+	Timestamp  time.Time `json:"timestamp"`
+	UID        string    `json:"uid"`
+	SourceUID  string    `json:"source_uid"`
+	SourceType string    `json:"source_type,omitempty"`
+	OrgID      int64     `json:"org_id"`
+	UserID     int64     `json:"user_id"`
+}
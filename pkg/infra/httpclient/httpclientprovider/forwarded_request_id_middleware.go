@@ -0,0 +1,21 @@
+package httpclientprovider
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+)
+
+const ForwardedRequestIDMiddlewareName = "forwarded-request-id"
+
+// ForwardedRequestIDMiddleware middleware that sets the request ID header on
+// the outgoing request, so that it can be correlated with the request it was
+// made for.
+func ForwardedRequestIDMiddleware(headerName string, requestID string) httpclient.Middleware {
+	return httpclient.NamedMiddlewareFunc(ForwardedRequestIDMiddlewareName, func(opts httpclient.Options, next http.RoundTripper) http.RoundTripper {
+		return httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set(headerName, requestID)
+			return next.RoundTrip(req)
+		})
+	})
+}
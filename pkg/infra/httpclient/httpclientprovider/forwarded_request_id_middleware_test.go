@@ -0,0 +1,32 @@
+package httpclientprovider_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana/pkg/infra/httpclient/httpclientprovider"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForwardedRequestIDMiddleware(t *testing.T) {
+	ctx := &testContext{}
+	finalRoundTripper := ctx.createRoundTripper()
+	mw := httpclientprovider.ForwardedRequestIDMiddleware("X-Request-Id", "abc123")
+	rt := mw.CreateMiddleware(httpclient.Options{}, finalRoundTripper)
+	require.NotNil(t, rt)
+	middlewareName, ok := mw.(httpclient.MiddlewareName)
+	require.True(t, ok)
+	require.Equal(t, "forwarded-request-id", middlewareName.MiddlewareName())
+
+	req, err := http.NewRequest(http.MethodGet, "http://", nil)
+	require.NoError(t, err)
+	res, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	if res.Body != nil {
+		require.NoError(t, res.Body.Close())
+	}
+	require.ElementsMatch(t, []string{"final"}, ctx.callChain)
+	require.Equal(t, "abc123", ctx.req.Header.Get("X-Request-Id"))
+}
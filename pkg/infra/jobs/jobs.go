@@ -0,0 +1,128 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// Job is a function run at a fixed interval in the background.
+type Job struct {
+	// Name identifies the job in logs, and in the server lock electing the
+	// instance running it.
+	Name     string
+	Interval time.Duration
+	// AllInstances runs the job on every instance rather than on a single
+	// instance of the HA cluster, for jobs filling local caches.
+	AllInstances bool
+	Run          func(ctx context.Context) error
+}
+
+// Scheduler runs the registered jobs. In HA, the instances take a server
+// lock before every run so that each run of a job happens on a single
+// instance.
+type Scheduler interface {
+	// Register schedules the job. Jobs registered after the scheduler
+	// started are scheduled right away.
+	Register(job Job)
+}
+
+func ProvideService(serverLock *serverlock.ServerLockService, tracer tracing.Tracer) *JobScheduler {
+	return &JobScheduler{
+		serverLock: serverLock,
+		tracer:     tracer,
+		log:        log.New("jobs"),
+	}
+}
+
+type JobScheduler struct {
+	serverLock *serverlock.ServerLockService
+	tracer     tracing.Tracer
+	log        log.Logger
+
+	mu   sync.Mutex
+	jobs []Job
+	ctx  context.Context
+	wg   sync.WaitGroup
+}
+
+func (s *JobScheduler) Register(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = append(s.jobs, job)
+	if s.ctx != nil {
+		s.start(s.ctx, job)
+	}
+}
+
+// Run starts the registered jobs and waits for them to stop with the context.
+func (s *JobScheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	s.ctx = ctx
+	for _, job := range s.jobs {
+		s.start(ctx, job)
+	}
+	s.mu.Unlock()
+
+	<-ctx.Done()
+	s.wg.Wait()
+	return ctx.Err()
+}
+
+func (s *JobScheduler) start(ctx context.Context, job Job) {
+	if job.Interval <= 0 {
+		s.log.Debug("Job disabled", "job", job.Name)
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(job.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.execute(ctx, job)
+			}
+		}
+	}()
+}
+
+func (s *JobScheduler) execute(ctx context.Context, job Job) {
+	if job.AllInstances {
+		s.run(ctx, job)
+		return
+	}
+
+	// the lock expires a bit before the next tick, so that the instance
+	// running the job isn't skipped by its own previous run
+	err := s.serverLock.LockAndExecute(ctx, "job "+job.Name, job.Interval*9/10, func(ctx context.Context) {
+		s.run(ctx, job)
+	})
+	if err != nil {
+		s.log.Error("Failed to take the lock of job", "job", job.Name, "error", err)
+	}
+}
+
+func (s *JobScheduler) run(ctx context.Context, job Job) {
+	ctx, span := s.tracer.Start(ctx, "job "+job.Name)
+	defer span.End()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		span.RecordError(err)
+		s.log.Error("Job failed", "job", job.Name, "duration", time.Since(start), "error", err)
+		return
+	}
+	s.log.Debug("Job completed", "job", job.Name, "duration", time.Since(start))
+}
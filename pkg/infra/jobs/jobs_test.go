@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+func TestIntegrationJobScheduler(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	// two instances sharing the database
+	sqlStore := sqlstore.InitTestDB(t)
+	tracer := tracing.InitializeTracerForTest()
+	first := ProvideService(serverlock.ProvideService(sqlStore, tracer), tracer)
+	second := ProvideService(serverlock.ProvideService(sqlStore, tracer), tracer)
+
+	t.Run("runs a job on a single instance", func(t *testing.T) {
+		var runs int32
+		job := Job{Name: "single", Interval: time.Hour, Run: func(context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		}}
+
+		first.execute(context.Background(), job)
+		second.execute(context.Background(), job)
+		require.Equal(t, int32(1), atomic.LoadInt32(&runs))
+	})
+
+	t.Run("runs a job on all instances", func(t *testing.T) {
+		var runs int32
+		job := Job{Name: "all", Interval: time.Hour, AllInstances: true, Run: func(context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		}}
+
+		first.execute(context.Background(), job)
+		second.execute(context.Background(), job)
+		require.Equal(t, int32(2), atomic.LoadInt32(&runs))
+	})
+
+	t.Run("runs the jobs registered after it started at their interval", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- first.Run(ctx)
+		}()
+
+		ran := make(chan struct{}, 10)
+		first.Register(Job{Name: "ticking", Interval: 10 * time.Millisecond, AllInstances: true, Run: func(context.Context) error {
+			ran <- struct{}{}
+			return nil
+		}})
+
+		select {
+		case <-ran:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "the job didn't run")
+		}
+
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+	})
+}
@@ -0,0 +1,16 @@
+package jobstest
+
+import "github.com/grafana/grafana/pkg/infra/jobs"
+
+// FakeScheduler records the registered jobs without running them.
+type FakeScheduler struct {
+	Jobs []jobs.Job
+}
+
+func NewFakeScheduler() *FakeScheduler {
+	return &FakeScheduler{}
+}
+
+func (s *FakeScheduler) Register(job jobs.Job) {
+	s.Jobs = append(s.Jobs, job)
+}
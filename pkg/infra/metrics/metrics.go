@@ -108,6 +108,9 @@ var (
 
 	// MPublicDashboardDatasourceQuerySuccess is a metric counter for successful queries labelled by datasource
 	MPublicDashboardDatasourceQuerySuccess *prometheus.CounterVec
+
+	// MPublicDashboardBlockedClients is a metric counter for clients blocked after too many failed public dashboard lookups
+	MPublicDashboardBlockedClients prometheus.Counter
 )
 
 // Timers
@@ -429,6 +432,12 @@ func init() {
 		Namespace: ExporterName,
 	}, []string{"datasource", "status"}, map[string][]string{"status": pubdash.QueryResultStatuses})
 
+	MPublicDashboardBlockedClients = metricutil.NewCounterStartingAtZero(prometheus.CounterOpts{
+		Name:      "public_dashboard_blocked_clients_total",
+		Help:      "counter for clients blocked after too many failed public dashboard lookups",
+		Namespace: ExporterName,
+	})
+
 	MStatTotalDashboards = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name:      "stat_totals_dashboard",
 		Help:      "total amount of dashboards",
@@ -675,5 +684,6 @@ func initMetricVars() {
 		MStatTotalPublicDashboards,
 		MPublicDashboardRequestCount,
 		MPublicDashboardDatasourceQuerySuccess,
+		MPublicDashboardBlockedClients,
 	)
 }
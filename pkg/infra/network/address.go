@@ -3,7 +3,9 @@ package network
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"regexp"
+	"strings"
 )
 
 var reIPv4AndPort = regexp.MustCompile(`^(\d+\.\d+\.\d+\.\d+):\d+$`)
@@ -42,3 +44,78 @@ func GetIPFromAddress(input string) (net.IP, error) {
 
 	return nil, err
 }
+
+// ParseTrustedProxies parses a list of IP addresses and CIDR ranges, IP
+// addresses being ranges of a single address.
+func ParseTrustedProxies(values []string) ([]*net.IPNet, error) {
+	proxies := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("not a valid IP address or CIDR range: %q", value)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("not a valid IP address or CIDR range: %q", value)
+		}
+		proxies = append(proxies, ipNet)
+	}
+	return proxies, nil
+}
+
+// ClientIP returns the IP of the client of the request. The X-Real-IP and
+// X-Forwarded-For headers can be set by clients, so they are only used when
+// the request comes from one of the trusted proxies, otherwise the address of
+// the connection is the client.
+func ClientIP(req *http.Request, trustedProxies []*net.IPNet) string {
+	peer, err := GetIPFromAddress(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	if !isTrusted(peer, trustedProxies) {
+		return peer.String()
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(req.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+
+	// the rightmost address not added by a trusted proxy is the client, the
+	// ones at its left are set by the client
+	forwarded := strings.Split(req.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if ip == nil {
+			break
+		}
+		if !isTrusted(ip, trustedProxies) {
+			return ip.String()
+		}
+	}
+
+	return peer.String()
+}
+
+func isTrusted(ip net.IP, trustedProxies []*net.IPNet) bool {
+	for _, proxy := range trustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
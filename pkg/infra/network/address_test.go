@@ -2,6 +2,7 @@ package network
 
 import (
 	"net"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -80,3 +81,74 @@ func TestGetIPFromAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc       string
+		remoteAddr string
+		headers    map[string]string
+		exp        string
+	}{
+		{
+			desc:       "Connection address",
+			remoteAddr: "203.0.113.1:5000",
+			exp:        "203.0.113.1",
+		},
+		{
+			desc:       "Forwarded headers of untrusted clients are ignored",
+			remoteAddr: "203.0.113.1:5000",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.1", "X-Forwarded-For": "198.51.100.2"},
+			exp:        "203.0.113.1",
+		},
+		{
+			desc:       "X-Real-IP of trusted proxies",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.1"},
+			exp:        "198.51.100.1",
+		},
+		{
+			desc:       "Rightmost untrusted X-Forwarded-For address of trusted proxies",
+			remoteAddr: "192.168.1.1:5000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.9, 198.51.100.2, 10.1.1.1"},
+			exp:        "198.51.100.2",
+		},
+		{
+			desc:       "Trusted proxy without forwarded headers",
+			remoteAddr: "10.0.0.1:5000",
+			exp:        "10.0.0.1",
+		},
+		{
+			desc:       "Invalid forwarded headers of trusted proxies",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string]string{"X-Real-IP": "nope", "X-Forwarded-For": "nope"},
+			exp:        "10.0.0.1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			req.RemoteAddr = tc.remoteAddr
+			for name, value := range tc.headers {
+				req.Header.Set(name, value)
+			}
+
+			assert.Equal(t, tc.exp, ClientIP(req, proxies))
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", " 192.168.1.1 ", "::1", ""})
+	require.NoError(t, err)
+	require.Len(t, proxies, 3)
+	assert.Equal(t, "10.0.0.0/8", proxies[0].String())
+	assert.Equal(t, "192.168.1.1/32", proxies[1].String())
+	assert.Equal(t, "::1/128", proxies[2].String())
+
+	_, err = ParseTrustedProxies([]string{"nope"})
+	require.EqualError(t, err, `not a valid IP address or CIDR range: "nope"`)
+}
@@ -2,6 +2,11 @@ package models
 
 import (
 	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
 
 	"github.com/grafana/grafana/pkg/services/user"
 )
@@ -42,6 +47,33 @@ type SendWebhookSync struct {
 	HttpHeader  map[string]string
 	ContentType string
 	Validation  func(body []byte, statusCode int) error
+	// BodyReader streams the request body instead of Body, it is closed
+	// once sent, or once the webhook fails, if it is an io.Closer
+	BodyReader io.Reader
+	// Multipart writes a multipart/form-data request body while it is
+	// sent, instead of Body and BodyReader
+	Multipart func(w *multipart.Writer) error
+	// TLS configures the TLS connection to the webhook, for endpoints with
+	// internally signed certificates or requiring client certificates
+	TLS *httpclient.TLSOptions
+
+	// Result is the response of the webhook, set whenever one was received
+	// even if sending the webhook failed
+	Result *WebhookResponse
+}
+
+// WebhookResponseBodyLimit is the maximum size of the response body read,
+// returned to senders of webhooks and passed to their validation
+const WebhookResponseBodyLimit = 64 * 1024
+
+// WebhookResponse is the response of a webhook, for senders logging the
+// error message of an API or reading the IDs of the objects it created
+type WebhookResponse struct {
+	StatusCode int
+	Header     http.Header
+	// Body is truncated to WebhookResponseBodyLimit bytes
+	Body          []byte
+	BodyTruncated bool
 }
 
 type SendResetPasswordEmailCommand struct {
@@ -25,6 +25,7 @@ type FindPersistedDashboardsQuery struct {
 	OrgId         int64
 	SignedInUser  *user.SignedInUser
 	IsStarred     bool
+	IsPublic      bool
 	DashboardIds  []int64
 	DashboardUIDs []string
 	Type          string
@@ -58,6 +59,7 @@ type Hit struct {
 	Type         HitType  `json:"type"`
 	Tags         []string `json:"tags"`
 	IsStarred    bool     `json:"isStarred"`
+	IsPublic     bool     `json:"isPublic"`
 	FolderID     int64    `json:"folderId,omitempty"`
 	FolderUID    string   `json:"folderUid,omitempty"`
 	FolderTitle  string   `json:"folderTitle,omitempty"`
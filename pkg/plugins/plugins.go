@@ -142,6 +142,9 @@ type JSONData struct {
 	Streaming    bool            `json:"streaming"`
 	SDK          bool            `json:"sdk,omitempty"`
 
+	// CorrelationTargetSchema is the JSON schema of the target queries of the correlations to the data source
+	CorrelationTargetSchema json.RawMessage `json:"correlationTargetSchema,omitempty"`
+
 	// Backend (Datasource + Renderer + SecretsManager)
 	Executable string `json:"executable,omitempty"`
 }
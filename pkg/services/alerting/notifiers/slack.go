@@ -3,12 +3,10 @@ package notifiers
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -346,21 +344,7 @@ func (sn *SlackNotifier) sendRequest(ctx context.Context, data []byte) error {
 		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", sn.token))
 	}
 
-	netTransport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			Renegotiation: tls.RenegotiateFreelyAsClient,
-		},
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout: 30 * time.Second,
-		}).DialContext,
-		TLSHandshakeTimeout: 5 * time.Second,
-	}
-	netClient := &http.Client{
-		Timeout:   time.Second * 30,
-		Transport: netTransport,
-	}
-	resp, err := netClient.Do(request)
+	resp, err := notifications.NewOutboundClient().Do(request)
 	if err != nil {
 		return err
 	}
@@ -5,7 +5,28 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 )
 
+// Correlations are scoped by their source data source, e.g. datasources:uid:<uid>,
+// so that users can be allowed to manage the correlations of some data sources only.
+const (
+	ActionRead   = "correlations:read"
+	ActionCreate = "correlations:create"
+	ActionWrite  = "correlations:write"
+	ActionDelete = "correlations:delete"
+)
+
+var (
+	ScopeAll      = datasources.ScopeAll
+	ScopeProvider = datasources.ScopeProvider
+)
+
 var (
 	// ConfigurationPageAccess is used to protect the "Configure > correlations" tab access
-	ConfigurationPageAccess = accesscontrol.EvalPermission(datasources.ActionRead)
+	ConfigurationPageAccess = accesscontrol.EvalPermission(ActionRead)
 )
+
+// EvalManage evaluates the permission to run a create, write or delete action
+// on the correlations of the data sources in scope. Users who can write a data
+// source can manage its correlations as well.
+func EvalManage(action string, scopes ...string) accesscontrol.Evaluator {
+	return accesscontrol.EvalAny(accesscontrol.EvalPermission(action, scopes...), accesscontrol.EvalPermission(datasources.ActionWrite, scopes...))
+}
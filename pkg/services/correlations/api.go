@@ -3,6 +3,9 @@ package correlations
 import (
 	"errors"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
@@ -10,26 +13,59 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
+	"github.com/grafana/grafana/pkg/util/errutil"
 
 	"github.com/grafana/grafana/pkg/web"
 )
 
 func (s *CorrelationsService) registerAPIEndpoints() {
-	uidScope := datasources.ScopeProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+	uidScope := ScopeProvider.GetResourceScopeUID(ac.Parameter(":uid"))
 	authorize := ac.Middleware(s.AccessControl)
+	rateLimit := s.RateLimits.Middleware(ratelimit.Correlations, ratelimit.ByUser)
 
-	s.RouteRegister.Get("/api/datasources/correlations", middleware.ReqSignedIn, authorize(middleware.ReqSignedIn, ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.getCorrelationsHandler))
+	s.RouteRegister.Get("/api/datasources/correlations", middleware.ReqSignedIn, rateLimit, authorize(middleware.ReqSignedIn, ac.EvalPermission(ActionRead)), routing.Wrap(s.getCorrelationsHandler))
+	// the bulk and import handlers check the permission on the source data source of every correlation
+	s.RouteRegister.Post("/api/datasources/correlations/bulk", middleware.ReqSignedIn, rateLimit, authorize(middleware.ReqOrgAdmin, EvalManage(ActionCreate)), routing.Wrap(s.bulkCreateHandler))
+	s.RouteRegister.Get("/api/datasources/correlations/sources", middleware.ReqSignedIn, rateLimit, authorize(middleware.ReqSignedIn, ac.EvalPermission(ActionRead)), routing.Wrap(s.getCorrelationsBySourceUIDsHandler))
+	s.RouteRegister.Get("/api/datasources/correlations/search", middleware.ReqSignedIn, rateLimit, authorize(middleware.ReqSignedIn, ac.EvalPermission(ActionRead)), routing.Wrap(s.searchHandler))
+	s.RouteRegister.Get("/api/datasources/correlations/export", middleware.ReqSignedIn, rateLimit, authorize(middleware.ReqSignedIn, ac.EvalPermission(ActionRead)), routing.Wrap(s.exportHandler))
+	s.RouteRegister.Post("/api/datasources/correlations/import", middleware.ReqSignedIn, rateLimit, authorize(middleware.ReqOrgAdmin, EvalManage(ActionCreate)), routing.Wrap(s.importHandler))
+	s.RouteRegister.Post("/api/datasources/correlations/bulk/delete", middleware.ReqSignedIn, rateLimit, authorize(middleware.ReqOrgAdmin, EvalManage(ActionDelete)), routing.Wrap(s.bulkDeleteHandler))
+	// the cleanup deletes the orphaned correlations of every org
+	s.RouteRegister.Post("/api/datasources/correlations/cleanup", middleware.ReqGrafanaAdmin, routing.Wrap(s.cleanupHandler))
+	// copying correlations between orgs reads and writes outside of the org of the user
+	s.RouteRegister.Post("/api/datasources/correlations/copy", middleware.ReqGrafanaAdmin, routing.Wrap(s.copyHandler))
 
 	s.RouteRegister.Group("/api/datasources/uid/:uid/correlations", func(entities routing.RouteRegister) {
-		entities.Get("/", authorize(middleware.ReqSignedIn, ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.getCorrelationsBySourceUIDHandler))
-		entities.Post("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(s.createHandler))
+		entities.Get("/", authorize(middleware.ReqSignedIn, ac.EvalPermission(ActionRead)), routing.Wrap(s.getCorrelationsBySourceUIDHandler))
+		entities.Post("/", authorize(middleware.ReqOrgAdmin, EvalManage(ActionCreate, uidScope)), routing.Wrap(s.createHandler))
+		entities.Get("/targets", authorize(middleware.ReqSignedIn, ac.EvalPermission(ActionRead)), routing.Wrap(s.getCorrelationsByTargetUIDHandler))
+		// deleting the migrated derived fields also requires the permission to write the data source, checked by the handler
+		entities.Post("/migrate", authorize(middleware.ReqOrgAdmin, EvalManage(ActionCreate, uidScope)), routing.Wrap(s.migrateHandler))
 
 		entities.Group("/:correlationUID", func(entities routing.RouteRegister) {
-			entities.Get("/", authorize(middleware.ReqSignedIn, ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.getCorrelationHandler))
-			entities.Delete("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(s.deleteHandler))
-			entities.Patch("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(s.updateHandler))
+			entities.Get("/", authorize(middleware.ReqSignedIn, ac.EvalPermission(ActionRead)), routing.Wrap(s.getCorrelationHandler))
+			entities.Delete("/", authorize(middleware.ReqOrgAdmin, EvalManage(ActionDelete, uidScope)), routing.Wrap(s.deleteHandler))
+			entities.Patch("/", authorize(middleware.ReqOrgAdmin, EvalManage(ActionWrite, uidScope)), routing.Wrap(s.updateHandler))
+			entities.Put("/", authorize(middleware.ReqOrgAdmin, ac.EvalAll(EvalManage(ActionCreate, uidScope), EvalManage(ActionWrite, uidScope))), routing.Wrap(s.upsertHandler))
+			entities.Post("/resolutions", authorize(middleware.ReqSignedIn, ac.EvalPermission(ActionRead)), routing.Wrap(s.recordResolutionHandler))
+			entities.Get("/health", authorize(middleware.ReqSignedIn, ac.EvalPermission(ActionRead)), routing.Wrap(s.healthHandler))
+			entities.Get("/versions", authorize(middleware.ReqSignedIn, ac.EvalPermission(ActionRead)), routing.Wrap(s.getVersionsHandler))
+			entities.Post("/versions/:version/restore", authorize(middleware.ReqOrgAdmin, EvalManage(ActionWrite, uidScope)), routing.Wrap(s.restoreVersionHandler))
 		})
-	}, middleware.ReqSignedIn)
+	}, middleware.ReqSignedIn, rateLimit)
+
+	// the correlations of a data source type apply to all its data sources, so writing them requires the permission on all the data sources
+	s.RouteRegister.Group("/api/datasources/type/:type/correlations", func(entities routing.RouteRegister) {
+		entities.Get("/", authorize(middleware.ReqSignedIn, ac.EvalPermission(ActionRead)), routing.Wrap(s.getCorrelationsBySourceTypeHandler))
+		entities.Post("/", authorize(middleware.ReqOrgAdmin, EvalManage(ActionCreate, ScopeAll)), routing.Wrap(s.createBySourceTypeHandler))
+
+		entities.Group("/:correlationUID", func(entities routing.RouteRegister) {
+			entities.Delete("/", authorize(middleware.ReqOrgAdmin, EvalManage(ActionDelete, ScopeAll)), routing.Wrap(s.deleteBySourceTypeHandler))
+			entities.Patch("/", authorize(middleware.ReqOrgAdmin, EvalManage(ActionWrite, ScopeAll)), routing.Wrap(s.updateBySourceTypeHandler))
+		})
+	}, middleware.ReqSignedIn, rateLimit)
 }
 
 // swagger:route POST /datasources/uid/{sourceUID}/correlations correlations createCorrelation
@@ -42,26 +78,30 @@ func (s *CorrelationsService) registerAPIEndpoints() {
 // 401: unauthorisedError
 // 403: forbiddenError
 // 404: notFoundError
+// 409: conflictError
 // 500: internalServerError
 func (s *CorrelationsService) createHandler(c *models.ReqContext) response.Response {
 	cmd := CreateCorrelationCommand{}
 	if err := web.Bind(c.Req, &cmd); err != nil {
+		// binding validates the command
+		observeChange(opCreate, err)
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	cmd.SourceUID = web.Params(c.Req)[":uid"]
 	cmd.OrgId = c.OrgID
+	cmd.UserId = c.UserID
+	cmd.ValidateTarget = c.QueryBool("validateTarget")
+	cmd.SignedInUser = c.SignedInUser
+	cmd.Force = c.QueryBool("force")
+
+	if err := cmd.Validate(); err != nil {
+		observeChange(opCreate, err)
+		return response.ErrOrFallback(http.StatusBadRequest, "Invalid correlation", err)
+	}
 
 	correlation, err := s.CreateCorrelation(c.Req.Context(), cmd)
 	if err != nil {
-		if errors.Is(err, ErrSourceDataSourceDoesNotExists) || errors.Is(err, ErrTargetDataSourceDoesNotExists) {
-			return response.Error(http.StatusNotFound, "Data source not found", err)
-		}
-
-		if errors.Is(err, ErrSourceDataSourceReadOnly) {
-			return response.Error(http.StatusForbidden, "Data source is read only", err)
-		}
-
-		return response.Error(http.StatusInternalServerError, "Failed to add correlation", err)
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to add correlation", err)
 	}
 
 	return response.JSON(http.StatusOK, CreateCorrelationResponseBody{Result: correlation, Message: "Correlation created"})
@@ -75,6 +115,14 @@ type CreateCorrelationParams struct {
 	// in:path
 	// required:true
 	SourceUID string `json:"sourceUID"`
+	// Run the target query against the target data source and reject the correlation if it fails
+	// in:query
+	// required:false
+	ValidateTarget bool `json:"validateTarget"`
+	// Create the correlation even if one with the same source, target, field and target query exists
+	// in:query
+	// required:false
+	Force bool `json:"force"`
 }
 
 //swagger:response createCorrelationResponse
@@ -98,23 +146,12 @@ func (s *CorrelationsService) deleteHandler(c *models.ReqContext) response.Respo
 		UID:       web.Params(c.Req)[":correlationUID"],
 		SourceUID: web.Params(c.Req)[":uid"],
 		OrgId:     c.OrgID,
+		UserId:    c.UserID,
 	}
 
 	err := s.DeleteCorrelation(c.Req.Context(), cmd)
 	if err != nil {
-		if errors.Is(err, ErrSourceDataSourceDoesNotExists) {
-			return response.Error(http.StatusNotFound, "Data source not found", err)
-		}
-
-		if errors.Is(err, ErrCorrelationNotFound) {
-			return response.Error(http.StatusNotFound, "Correlation not found", err)
-		}
-
-		if errors.Is(err, ErrSourceDataSourceReadOnly) {
-			return response.Error(http.StatusForbidden, "Data source is read only", err)
-		}
-
-		return response.Error(http.StatusInternalServerError, "Failed to delete correlation", err)
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete correlation", err)
 	}
 
 	return response.JSON(http.StatusOK, DeleteCorrelationResponseBody{Message: "Correlation deleted"})
@@ -156,26 +193,14 @@ func (s *CorrelationsService) updateHandler(c *models.ReqContext) response.Respo
 	cmd.UID = web.Params(c.Req)[":correlationUID"]
 	cmd.SourceUID = web.Params(c.Req)[":uid"]
 	cmd.OrgId = c.OrgID
+	cmd.UserId = c.UserID
+	cmd.ValidateTarget = c.QueryBool("validateTarget")
+	cmd.SignedInUser = c.SignedInUser
+	cmd.MergeTarget = c.QueryBool("mergeTarget")
 
 	correlation, err := s.UpdateCorrelation(c.Req.Context(), cmd)
 	if err != nil {
-		if errors.Is(err, ErrUpdateCorrelationEmptyParams) {
-			return response.Error(http.StatusBadRequest, "At least one of label, description or config is required", err)
-		}
-
-		if errors.Is(err, ErrSourceDataSourceDoesNotExists) {
-			return response.Error(http.StatusNotFound, "Data source not found", err)
-		}
-
-		if errors.Is(err, ErrCorrelationNotFound) {
-			return response.Error(http.StatusNotFound, "Correlation not found", err)
-		}
-
-		if errors.Is(err, ErrSourceDataSourceReadOnly) {
-			return response.Error(http.StatusForbidden, "Data source is read only", err)
-		}
-
-		return response.Error(http.StatusInternalServerError, "Failed to update correlation", err)
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update correlation", err)
 	}
 
 	return response.JSON(http.StatusOK, UpdateCorrelationResponseBody{Message: "Correlation updated", Result: correlation})
@@ -191,6 +216,14 @@ type UpdateCorrelationParams struct {
 	CorrelationUID string `json:"correlationUID"`
 	// in: body
 	Body UpdateCorrelationCommand `json:"body"`
+	// Run the target query against the target data source and reject the correlation if it fails
+	// in:query
+	// required:false
+	ValidateTarget bool `json:"validateTarget"`
+	// Merge the target of the config into the current target, as a JSON merge patch, instead of replacing it
+	// in:query
+	// required:false
+	MergeTarget bool `json:"mergeTarget"`
 }
 
 //swagger:response updateCorrelationResponse
@@ -199,6 +232,81 @@ type UpdateCorrelationResponse struct {
 	Body UpdateCorrelationResponseBody `json:"body"`
 }
 
+// swagger:route PUT /datasources/uid/{sourceUID}/correlations/{correlationUID} correlations upsertCorrelation
+//
+// Creates or replaces a correlation.
+//
+// Creates the correlation with the UID if the source data source has none, and replaces all its fields otherwise.
+//
+// Responses:
+// 200: upsertCorrelationResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *CorrelationsService) upsertHandler(c *models.ReqContext) response.Response {
+	cmd := CreateCorrelationCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		// binding validates the command
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	uid := web.Params(c.Req)[":correlationUID"]
+	if cmd.UID != "" && cmd.UID != uid {
+		return response.Err(ErrInvalidUID.Errorf("correlation UID %q doesn't match the UID %q of the path", cmd.UID, uid))
+	}
+	cmd.UID = uid
+	cmd.SourceUID = web.Params(c.Req)[":uid"]
+	cmd.OrgId = c.OrgID
+	cmd.UserId = c.UserID
+	cmd.ValidateTarget = c.QueryBool("validateTarget")
+	cmd.SignedInUser = c.SignedInUser
+	cmd.Force = c.QueryBool("force")
+
+	if err := cmd.Validate(); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "Invalid correlation", err)
+	}
+
+	correlation, created, err := s.UpsertCorrelation(c.Req.Context(), cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to save correlation", err)
+	}
+
+	message := "Correlation updated"
+	if created {
+		message = "Correlation created"
+	}
+	return response.JSON(http.StatusOK, UpsertCorrelationResponseBody{Result: correlation, Created: created, Message: message})
+}
+
+// swagger:parameters upsertCorrelation
+type UpsertCorrelationParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"sourceUID"`
+	// in:path
+	// required:true
+	CorrelationUID string `json:"correlationUID"`
+	// in:body
+	// required:true
+	Body CreateCorrelationCommand `json:"body"`
+	// Run the target query against the target data source and reject the correlation if it fails
+	// in:query
+	// required:false
+	ValidateTarget bool `json:"validateTarget"`
+	// Create the correlation even if one with the same source, target, field and target query exists
+	// in:query
+	// required:false
+	Force bool `json:"force"`
+}
+
+//swagger:response upsertCorrelationResponse
+type UpsertCorrelationResponse struct {
+	// in: body
+	Body UpsertCorrelationResponseBody `json:"body"`
+}
+
 // swagger:route GET /datasources/uid/{sourceUID}/correlations/{correlationUID} correlations getCorrelation
 //
 // Gets a correlation.
@@ -210,21 +318,15 @@ type UpdateCorrelationResponse struct {
 // 500: internalServerError
 func (s *CorrelationsService) getCorrelationHandler(c *models.ReqContext) response.Response {
 	query := GetCorrelationQuery{
-		UID:       web.Params(c.Req)[":correlationUID"],
-		SourceUID: web.Params(c.Req)[":uid"],
-		OrgId:     c.OrgID,
+		UID:          web.Params(c.Req)[":correlationUID"],
+		SourceUID:    web.Params(c.Req)[":uid"],
+		OrgId:        c.OrgID,
+		SignedInUser: c.SignedInUser,
 	}
 
-	correlation, err := s.getCorrelation(c.Req.Context(), query)
+	correlation, err := s.GetCorrelation(c.Req.Context(), query)
 	if err != nil {
-		if errors.Is(err, ErrCorrelationNotFound) {
-			return response.Error(http.StatusNotFound, "Correlation not found", err)
-		}
-		if errors.Is(err, ErrSourceDataSourceDoesNotExists) {
-			return response.Error(http.StatusNotFound, "Source data source not found", err)
-		}
-
-		return response.Error(http.StatusInternalServerError, "Failed to get correlation", err)
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get correlation", err)
 	}
 
 	return response.JSON(http.StatusOK, correlation)
@@ -246,12 +348,66 @@ type GetCorrelationResponse struct {
 	Body Correlation `json:"body"`
 }
 
+// swagger:route GET /datasources/uid/{sourceUID}/correlations/{correlationUID}/health correlations getCorrelationHealth
+//
+// Check the health of a correlation.
+//
+// Checks that the source and target of the correlation still exist and that its config is valid, and optionally runs
+// its target query. The failed checks are listed in the report rather than failing the request.
+//
+// Responses:
+// 200: getCorrelationHealthResponse
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (s *CorrelationsService) healthHandler(c *models.ReqContext) response.Response {
+	query := CorrelationHealthQuery{
+		UID:            web.Params(c.Req)[":correlationUID"],
+		SourceUID:      web.Params(c.Req)[":uid"],
+		OrgId:          c.OrgID,
+		ValidateTarget: c.QueryBool("validateTarget"),
+		SignedInUser:   c.SignedInUser,
+	}
+
+	if _, err := s.GetCorrelation(c.Req.Context(), GetCorrelationQuery{UID: query.UID, SourceUID: query.SourceUID, OrgId: query.OrgId, SignedInUser: c.SignedInUser}); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get correlation", err)
+	}
+
+	health, err := s.CheckCorrelationHealth(c.Req.Context(), query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to check the health of the correlation", err)
+	}
+
+	return response.JSON(http.StatusOK, health)
+}
+
+// swagger:parameters getCorrelationHealth
+type GetCorrelationHealthParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"sourceUID"`
+	// in:path
+	// required:true
+	CorrelationUID string `json:"correlationUID"`
+	// Also run the target query of query correlations against the target data source
+	// in:query
+	// required:false
+	ValidateTarget bool `json:"validateTarget"`
+}
+
+//swagger:response getCorrelationHealthResponse
+type GetCorrelationHealthResponse struct {
+	// in: body
+	Body CorrelationHealth `json:"body"`
+}
+
 // swagger:route GET /datasources/uid/{sourceUID}/correlations correlations getCorrelationsBySourceUID
 //
 // Gets all correlations originating from the given data source.
 //
 // Responses:
 // 200: getCorrelationsBySourceUIDResponse
+// 400: badRequestError
 // 401: unauthorisedError
 // 404: notFoundError
 // 500: internalServerError
@@ -259,21 +415,12 @@ func (s *CorrelationsService) getCorrelationsBySourceUIDHandler(c *models.ReqCon
 	query := GetCorrelationsBySourceUIDQuery{
 		SourceUID: web.Params(c.Req)[":uid"],
 		OrgId:     c.OrgID,
+		Tags:      c.QueryStrings("tag"),
 	}
 
-	correlations, err := s.getCorrelationsBySourceUID(c.Req.Context(), query)
-	if err != nil {
-		if errors.Is(err, ErrCorrelationNotFound) {
-			return response.Error(http.StatusNotFound, "No correlation found", err)
-		}
-		if errors.Is(err, ErrSourceDataSourceDoesNotExists) {
-			return response.Error(http.StatusNotFound, "Source data source not found", err)
-		}
-
-		return response.Error(http.StatusInternalServerError, "Failed to get correlations", err)
-	}
-
-	return response.JSON(http.StatusOK, correlations)
+	return s.listResponse(c, "source", query.SourceUID, query.Tags, func() ([]Correlation, error) {
+		return s.getCorrelationsBySourceUID(c.Req.Context(), query)
+	})
 }
 
 // swagger:parameters getCorrelationsBySourceUID
@@ -281,6 +428,14 @@ type GetCorrelationsBySourceUIDParams struct {
 	// in:path
 	// required:true
 	DatasourceUID string `json:"sourceUID"`
+	// Only return the correlations with all the given tags
+	// in:query
+	// required:false
+	Tags []string `json:"tag"`
+	// Also return the name and type of the source and target data sources with datasources
+	// in:query
+	// required:false
+	Expand []string `json:"expand"`
 }
 
 //swagger:response getCorrelationsBySourceUIDResponse
@@ -289,34 +444,901 @@ type GetCorrelationsBySourceUIDResponse struct {
 	Body []Correlation `json:"body"`
 }
 
-// swagger:route GET /datasources/correlations correlations getCorrelations
+// swagger:route GET /datasources/correlations/sources correlations getCorrelationsBySourceUIDs
 //
-// Gets all correlations.
+// Gets the correlations of several source data sources in a single request.
+//
+// The correlations are keyed by the UID of their source data source, and include the correlations of the type of each data source.
 //
 // Responses:
-// 200: getCorrelationsResponse
+// 200: getCorrelationsBySourceUIDsResponse
+// 400: badRequestError
 // 401: unauthorisedError
 // 404: notFoundError
 // 500: internalServerError
-func (s *CorrelationsService) getCorrelationsHandler(c *models.ReqContext) response.Response {
-	query := GetCorrelationsQuery{
-		OrgId: c.OrgID,
+func (s *CorrelationsService) getCorrelationsBySourceUIDsHandler(c *models.ReqContext) response.Response {
+	query := GetCorrelationsBySourceUIDsQuery{
+		SourceUIDs:   c.QueryStrings("sourceUID"),
+		OrgId:        c.OrgID,
+		Tags:         c.QueryStrings("tag"),
+		SignedInUser: c.SignedInUser,
+	}
+	if err := query.Validate(); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "Invalid source data sources", err)
 	}
 
-	correlations, err := s.getCorrelations(c.Req.Context(), query)
+	expand, err := expandDataSources(c)
 	if err != nil {
-		if errors.Is(err, ErrCorrelationNotFound) {
-			return response.Error(http.StatusNotFound, "No correlation found", err)
+		return response.ErrOrFallback(http.StatusBadRequest, "Invalid expand parameter", err)
+	}
+
+	// the order of the UIDs doesn't change the response, so they don't change its cache key either
+	uids := append([]string{}, query.SourceUIDs...)
+	sort.Strings(uids)
+	return s.cachedResponse(c, "sources", strings.Join(uids, ","), query.Tags, expand, func() (interface{}, error) {
+		correlations, err := s.GetCorrelationsBySourceUIDs(c.Req.Context(), query)
+		if err != nil {
+			return nil, err
+		}
+		if !expand {
+			return correlations, nil
+		}
+		dataSources, err := s.dataSourcesByUID(c.Req.Context(), c.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		for _, list := range correlations {
+			withDataSources(list, dataSources)
 		}
+		return correlations, nil
+	})
+}
+
+// swagger:parameters getCorrelationsBySourceUIDs
+type GetCorrelationsBySourceUIDsParams struct {
+	// UIDs of the source data sources, at most 100
+	// in:query
+	// required:true
+	SourceUIDs []string `json:"sourceUID"`
+	// Only return the correlations with all the given tags
+	// in:query
+	// required:false
+	Tags []string `json:"tag"`
+	// Also return the name and type of the source and target data sources with datasources
+	// in:query
+	// required:false
+	Expand []string `json:"expand"`
+}
+
+//swagger:response getCorrelationsBySourceUIDsResponse
+type GetCorrelationsBySourceUIDsResponse struct {
+	// in: body
+	Body map[string][]Correlation `json:"body"`
+}
+
+// swagger:route GET /datasources/uid/{targetUID}/correlations/targets correlations getCorrelationsByTargetUID
+//
+// Gets all correlations pointing at the data source with the given UID.
+//
+// Useful to find the correlations affected before deleting or migrating a data source.
+//
+// Responses:
+// 200: getCorrelationsByTargetUIDResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (s *CorrelationsService) getCorrelationsByTargetUIDHandler(c *models.ReqContext) response.Response {
+	query := GetCorrelationsByTargetUIDQuery{
+		TargetUID: web.Params(c.Req)[":uid"],
+		OrgId:     c.OrgID,
+	}
+
+	return s.listResponse(c, "target", query.TargetUID, nil, func() ([]Correlation, error) {
+		return s.getCorrelationsByTargetUID(c.Req.Context(), query)
+	})
+}
+
+// swagger:parameters getCorrelationsByTargetUID
+type GetCorrelationsByTargetUIDParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"targetUID"`
+	// Also return the name and type of the source and target data sources with datasources
+	// in:query
+	// required:false
+	Expand []string `json:"expand"`
+}
+
+//swagger:response getCorrelationsByTargetUIDResponse
+type GetCorrelationsByTargetUIDResponse struct {
+	// in: body
+	Body []Correlation `json:"body"`
+}
 
-		return response.Error(http.StatusInternalServerError, "Failed to get correlations", err)
+// swagger:route GET /datasources/type/{sourceType}/correlations correlations getCorrelationsBySourceType
+//
+// Gets the correlations applying to all the data sources of the given plugin type.
+//
+// Responses:
+// 200: getCorrelationsBySourceTypeResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (s *CorrelationsService) getCorrelationsBySourceTypeHandler(c *models.ReqContext) response.Response {
+	query := GetCorrelationsBySourceTypeQuery{
+		SourceType: web.Params(c.Req)[":type"],
+		OrgId:      c.OrgID,
 	}
 
-	return response.JSON(http.StatusOK, correlations)
+	return s.listResponse(c, "type", query.SourceType, nil, func() ([]Correlation, error) {
+		return s.getCorrelationsBySourceType(c.Req.Context(), query)
+	})
 }
 
-//swagger:response getCorrelationsResponse
-type GetCorrelationsResponse struct {
+// swagger:parameters getCorrelationsBySourceType
+type GetCorrelationsBySourceTypeParams struct {
+	// in:path
+	// required:true
+	SourceType string `json:"sourceType"`
+	// Also return the name and type of the source and target data sources with datasources
+	// in:query
+	// required:false
+	Expand []string `json:"expand"`
+}
+
+//swagger:response getCorrelationsBySourceTypeResponse
+type GetCorrelationsBySourceTypeResponse struct {
 	// in: body
 	Body []Correlation `json:"body"`
 }
+
+// swagger:route POST /datasources/type/{sourceType}/correlations correlations createCorrelationBySourceType
+//
+// Add a correlation applying to all the data sources of the given plugin type.
+//
+// Responses:
+// 200: createCorrelationResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *CorrelationsService) createBySourceTypeHandler(c *models.ReqContext) response.Response {
+	cmd := CreateCorrelationCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		// binding validates the command
+		observeChange(opCreate, err)
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.SourceType = web.Params(c.Req)[":type"]
+	cmd.OrgId = c.OrgID
+	cmd.UserId = c.UserID
+	cmd.ValidateTarget = c.QueryBool("validateTarget")
+	cmd.SignedInUser = c.SignedInUser
+	cmd.Force = c.QueryBool("force")
+
+	correlation, err := s.CreateCorrelation(c.Req.Context(), cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to add correlation", err)
+	}
+
+	return response.JSON(http.StatusOK, CreateCorrelationResponseBody{Result: correlation, Message: "Correlation created"})
+}
+
+// swagger:parameters createCorrelationBySourceType
+type CreateCorrelationBySourceTypeParams struct {
+	// in:body
+	// required:true
+	Body CreateCorrelationCommand `json:"body"`
+	// in:path
+	// required:true
+	SourceType string `json:"sourceType"`
+	// Run the target query against the target data source and reject the correlation if it fails
+	// in:query
+	// required:false
+	ValidateTarget bool `json:"validateTarget"`
+	// Create the correlation even if one with the same source, target, field and target query exists
+	// in:query
+	// required:false
+	Force bool `json:"force"`
+}
+
+// swagger:route PATCH /datasources/type/{sourceType}/correlations/{correlationUID} correlations updateCorrelationBySourceType
+//
+// Updates a correlation applying to all the data sources of the given plugin type.
+//
+// Responses:
+// 200: updateCorrelationResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *CorrelationsService) updateBySourceTypeHandler(c *models.ReqContext) response.Response {
+	cmd := UpdateCorrelationCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	cmd.UID = web.Params(c.Req)[":correlationUID"]
+	cmd.SourceType = web.Params(c.Req)[":type"]
+	cmd.OrgId = c.OrgID
+	cmd.UserId = c.UserID
+	cmd.ValidateTarget = c.QueryBool("validateTarget")
+	cmd.SignedInUser = c.SignedInUser
+	cmd.MergeTarget = c.QueryBool("mergeTarget")
+
+	correlation, err := s.UpdateCorrelation(c.Req.Context(), cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update correlation", err)
+	}
+
+	return response.JSON(http.StatusOK, UpdateCorrelationResponseBody{Message: "Correlation updated", Result: correlation})
+}
+
+// swagger:parameters updateCorrelationBySourceType
+type UpdateCorrelationBySourceTypeParams struct {
+	// in:path
+	// required:true
+	SourceType string `json:"sourceType"`
+	// in:path
+	// required:true
+	CorrelationUID string `json:"correlationUID"`
+	// in: body
+	Body UpdateCorrelationCommand `json:"body"`
+	// Run the target query against the target data source and reject the correlation if it fails
+	// in:query
+	// required:false
+	ValidateTarget bool `json:"validateTarget"`
+	// Merge the target of the config into the current target, as a JSON merge patch, instead of replacing it
+	// in:query
+	// required:false
+	MergeTarget bool `json:"mergeTarget"`
+}
+
+// swagger:route DELETE /datasources/type/{sourceType}/correlations/{correlationUID} correlations deleteCorrelationBySourceType
+//
+// Delete a correlation applying to all the data sources of the given plugin type.
+//
+// Responses:
+// 200: deleteCorrelationResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *CorrelationsService) deleteBySourceTypeHandler(c *models.ReqContext) response.Response {
+	cmd := DeleteCorrelationCommand{
+		UID:        web.Params(c.Req)[":correlationUID"],
+		SourceType: web.Params(c.Req)[":type"],
+		OrgId:      c.OrgID,
+		UserId:     c.UserID,
+	}
+
+	err := s.DeleteCorrelation(c.Req.Context(), cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete correlation", err)
+	}
+
+	return response.JSON(http.StatusOK, DeleteCorrelationResponseBody{Message: "Correlation deleted"})
+}
+
+// swagger:parameters deleteCorrelationBySourceType
+type DeleteCorrelationBySourceTypeParams struct {
+	// in:path
+	// required:true
+	SourceType string `json:"sourceType"`
+	// in:path
+	// required:true
+	CorrelationUID string `json:"correlationUID"`
+}
+
+// swagger:route GET /datasources/correlations correlations getCorrelations
+//
+// Gets all correlations.
+//
+// Responses:
+// 200: getCorrelationsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (s *CorrelationsService) getCorrelationsHandler(c *models.ReqContext) response.Response {
+	query := GetCorrelationsQuery{
+		OrgId:     c.OrgID,
+		WithUsage: true,
+		Tags:      c.QueryStrings("tag"),
+	}
+
+	return s.listResponse(c, "all", "", query.Tags, func() ([]Correlation, error) {
+		return s.getCorrelations(c.Req.Context(), query)
+	})
+}
+
+// swagger:parameters getCorrelations
+type GetCorrelationsParams struct {
+	// Only return the correlations with all the given tags
+	// in:query
+	// required:false
+	Tags []string `json:"tag"`
+	// Also return the name and type of the source and target data sources with datasources
+	// in:query
+	// required:false
+	Expand []string `json:"expand"`
+}
+
+//swagger:response getCorrelationsResponse
+type GetCorrelationsResponse struct {
+	// in: body
+	Body []Correlation `json:"body"`
+}
+
+// swagger:route GET /datasources/correlations/search correlations searchCorrelations
+//
+// Search the correlations by their label, description and target.
+//
+// Matches in the label rank first, then matches in the target, such as its query, and in the description.
+//
+// Responses:
+// 200: searchCorrelationsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (s *CorrelationsService) searchHandler(c *models.ReqContext) response.Response {
+	query := SearchCorrelationsQuery{
+		OrgId:        c.OrgID,
+		Query:        strings.TrimSpace(c.Query("query")),
+		Limit:        c.QueryInt("limit"),
+		SignedInUser: c.SignedInUser,
+	}
+	if err := query.Validate(); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "Invalid search", err)
+	}
+
+	results, err := s.SearchCorrelations(c.Req.Context(), query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to search correlations", err)
+	}
+
+	return response.JSON(http.StatusOK, results)
+}
+
+// swagger:parameters searchCorrelations
+type SearchCorrelationsParams struct {
+	// Text searched in the label, description and target of the correlations, case-insensitively
+	// in:query
+	// required:true
+	Query string `json:"query"`
+	// Maximum number of results, 20 by default and at most 100
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+}
+
+//swagger:response searchCorrelationsResponse
+type SearchCorrelationsResponse struct {
+	// in: body
+	Body []CorrelationSearchResult `json:"body"`
+}
+
+// swagger:route POST /datasources/uid/{sourceUID}/correlations/{correlationUID}/resolutions correlations recordCorrelationResolution
+//
+// Record that a correlation link was followed.
+//
+// Clients call it when the user follows the link of the correlation, so that usage insights cover correlations.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (s *CorrelationsService) recordResolutionHandler(c *models.ReqContext) response.Response {
+	cmd := RecordCorrelationResolutionCommand{
+		UID:       web.Params(c.Req)[":correlationUID"],
+		SourceUID: web.Params(c.Req)[":uid"],
+		OrgId:     c.OrgID,
+		UserId:    c.UserID,
+	}
+
+	if err := s.RecordResolution(c.Req.Context(), cmd); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to record correlation resolution", err)
+	}
+
+	return response.Success("Correlation resolution recorded")
+}
+
+// swagger:parameters recordCorrelationResolution
+type RecordCorrelationResolutionParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"sourceUID"`
+	// in:path
+	// required:true
+	CorrelationUID string `json:"correlationUID"`
+}
+
+// swagger:route POST /datasources/correlations/bulk correlations bulkCreateCorrelations
+//
+// Add a batch of correlations.
+//
+// The correlations are created in a single transaction: if any of them fails, none is created and
+// the response lists every failed correlation with its position in the batch.
+//
+// Responses:
+// 200: bulkCreateCorrelationsResponse
+// 400: bulkCreateCorrelationsResponse
+// 401: unauthorisedError
+// 403: bulkCreateCorrelationsResponse
+// 404: bulkCreateCorrelationsResponse
+// 409: bulkCreateCorrelationsResponse
+// 500: internalServerError
+func (s *CorrelationsService) bulkCreateHandler(c *models.ReqContext) response.Response {
+	cmd := BulkCreateCorrelationsCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgId = c.OrgID
+	cmd.UserId = c.UserID
+	cmd.Force = c.QueryBool("force")
+
+	sourceUIDs := make([]string, 0, len(cmd.Correlations))
+	for _, item := range cmd.Correlations {
+		sourceUIDs = append(sourceUIDs, item.SourceUID)
+	}
+	denied, err := s.checkBulkPermissions(c, ActionCreate, sourceUIDs, nil)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to check permissions", err)
+	}
+	if len(denied) > 0 {
+		return response.JSON(http.StatusForbidden, BulkCreateCorrelationsResponseBody{Message: "No correlations created", Errors: denied})
+	}
+
+	created, err := s.BulkCreateCorrelations(c.Req.Context(), cmd)
+	if err != nil {
+		var bulkErr BulkCorrelationsError
+		if errors.As(err, &bulkErr) {
+			return response.JSON(bulkErr.StatusCode(), BulkCreateCorrelationsResponseBody{Message: "No correlations created", Errors: bulkErr.Errors})
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to add correlations", err)
+	}
+
+	return response.JSON(http.StatusOK, BulkCreateCorrelationsResponseBody{Result: created, Message: "Correlations created"})
+}
+
+// swagger:parameters bulkCreateCorrelations
+type BulkCreateCorrelationsParams struct {
+	// in:body
+	// required:true
+	Body BulkCreateCorrelationsCommand `json:"body"`
+	// Create the correlations even if ones with the same source, target, field and target query exist
+	// in:query
+	// required:false
+	Force bool `json:"force"`
+}
+
+//swagger:response bulkCreateCorrelationsResponse
+type BulkCreateCorrelationsResponse struct {
+	// in: body
+	Body BulkCreateCorrelationsResponseBody `json:"body"`
+}
+
+// swagger:route POST /datasources/correlations/bulk/delete correlations bulkDeleteCorrelations
+//
+// Delete a batch of correlations.
+//
+// The correlations are deleted in a single transaction: if any of them fails, none is deleted and
+// the response lists every failed correlation with its position in the batch.
+//
+// Responses:
+// 200: bulkDeleteCorrelationsResponse
+// 400: bulkDeleteCorrelationsResponse
+// 401: unauthorisedError
+// 403: bulkDeleteCorrelationsResponse
+// 404: bulkDeleteCorrelationsResponse
+// 500: internalServerError
+func (s *CorrelationsService) bulkDeleteHandler(c *models.ReqContext) response.Response {
+	cmd := BulkDeleteCorrelationsCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgId = c.OrgID
+	cmd.UserId = c.UserID
+
+	sourceUIDs := make([]string, 0, len(cmd.Correlations))
+	uids := make([]string, 0, len(cmd.Correlations))
+	for _, item := range cmd.Correlations {
+		sourceUIDs = append(sourceUIDs, item.SourceUID)
+		uids = append(uids, item.UID)
+	}
+	denied, err := s.checkBulkPermissions(c, ActionDelete, sourceUIDs, uids)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to check permissions", err)
+	}
+	if len(denied) > 0 {
+		return response.JSON(http.StatusForbidden, BulkDeleteCorrelationsResponseBody{Message: "No correlations deleted", Errors: denied})
+	}
+
+	if err := s.BulkDeleteCorrelations(c.Req.Context(), cmd); err != nil {
+		var bulkErr BulkCorrelationsError
+		if errors.As(err, &bulkErr) {
+			return response.JSON(bulkErr.StatusCode(), BulkDeleteCorrelationsResponseBody{Message: "No correlations deleted", Errors: bulkErr.Errors})
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete correlations", err)
+	}
+
+	return response.JSON(http.StatusOK, BulkDeleteCorrelationsResponseBody{Message: "Correlations deleted"})
+}
+
+// swagger:parameters bulkDeleteCorrelations
+type BulkDeleteCorrelationsParams struct {
+	// in:body
+	// required:true
+	Body BulkDeleteCorrelationsCommand `json:"body"`
+}
+
+//swagger:response bulkDeleteCorrelationsResponse
+type BulkDeleteCorrelationsResponse struct {
+	// in: body
+	Body BulkDeleteCorrelationsResponseBody `json:"body"`
+}
+
+// swagger:route POST /datasources/correlations/cleanup correlations cleanupCorrelations
+//
+// Delete the orphaned correlations.
+//
+// Deletes the correlations of every organization whose source or target data source doesn't exist
+// anymore, as the cleanup job does every hour. Only Grafana admins can run it.
+//
+// Responses:
+// 200: cleanupCorrelationsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *CorrelationsService) cleanupHandler(c *models.ReqContext) response.Response {
+	deleted, err := s.deleteOrphanedCorrelations(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to delete orphaned correlations", err)
+	}
+
+	s.log.Info("Deleted correlations of deleted data sources", "count", deleted, "userId", c.UserID)
+	return response.JSON(http.StatusOK, CleanupCorrelationsResponseBody{Message: "Orphaned correlations deleted", Deleted: deleted})
+}
+
+//swagger:response cleanupCorrelationsResponse
+type CleanupCorrelationsResponse struct {
+	// in: body
+	Body CleanupCorrelationsResponseBody `json:"body"`
+}
+
+// swagger:route GET /datasources/uid/{sourceUID}/correlations/{correlationUID}/versions correlations getCorrelationVersions
+//
+// Gets the versions of a correlation, latest first.
+//
+// Responses:
+// 200: getCorrelationVersionsResponse
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (s *CorrelationsService) getVersionsHandler(c *models.ReqContext) response.Response {
+	query := GetCorrelationVersionsQuery{
+		UID:          web.Params(c.Req)[":correlationUID"],
+		SourceUID:    web.Params(c.Req)[":uid"],
+		OrgId:        c.OrgID,
+		SignedInUser: c.SignedInUser,
+	}
+
+	versions, err := s.GetCorrelationVersions(c.Req.Context(), query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get correlation versions", err)
+	}
+
+	return response.JSON(http.StatusOK, versions)
+}
+
+// swagger:parameters getCorrelationVersions
+type GetCorrelationVersionsParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"sourceUID"`
+	// in:path
+	// required:true
+	CorrelationUID string `json:"correlationUID"`
+}
+
+//swagger:response getCorrelationVersionsResponse
+type GetCorrelationVersionsResponse struct {
+	// in: body
+	Body []CorrelationVersion `json:"body"`
+}
+
+// swagger:route POST /datasources/uid/{sourceUID}/correlations/{correlationUID}/versions/{version}/restore correlations restoreCorrelationVersion
+//
+// Restores a previous version of a correlation.
+//
+// The label, description and config of the version are restored, and recorded as a new version.
+//
+// Responses:
+// 200: updateCorrelationResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *CorrelationsService) restoreVersionHandler(c *models.ReqContext) response.Response {
+	version, err := strconv.Atoi(web.Params(c.Req)[":version"])
+	if err != nil || version < 1 {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	cmd := RestoreCorrelationVersionCommand{
+		UID:       web.Params(c.Req)[":correlationUID"],
+		SourceUID: web.Params(c.Req)[":uid"],
+		OrgId:     c.OrgID,
+		Version:   version,
+		UserId:    c.UserID,
+	}
+
+	correlation, err := s.RestoreCorrelationVersion(c.Req.Context(), cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to restore correlation version", err)
+	}
+
+	return response.JSON(http.StatusOK, UpdateCorrelationResponseBody{Message: "Correlation version restored", Result: correlation})
+}
+
+// swagger:parameters restoreCorrelationVersion
+type RestoreCorrelationVersionParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"sourceUID"`
+	// in:path
+	// required:true
+	CorrelationUID string `json:"correlationUID"`
+	// in:path
+	// required:true
+	Version int64 `json:"version"`
+}
+
+// swagger:route GET /datasources/correlations/export correlations exportCorrelations
+//
+// Export all correlations.
+//
+// The data sources are referenced by name, so that the bundle can be imported into another organization or instance.
+//
+// Responses:
+// 200: exportCorrelationsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *CorrelationsService) exportHandler(c *models.ReqContext) response.Response {
+	bundle, err := s.ExportCorrelations(c.Req.Context(), ExportCorrelationsQuery{OrgId: c.OrgID, SignedInUser: c.SignedInUser})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to export correlations", err)
+	}
+
+	return response.JSON(http.StatusOK, bundle)
+}
+
+//swagger:response exportCorrelationsResponse
+type ExportCorrelationsResponse struct {
+	// in: body
+	Body CorrelationsBundle `json:"body"`
+}
+
+// swagger:route POST /datasources/correlations/import correlations importCorrelations
+//
+// Import a bundle of correlations.
+//
+// The data sources of the bundle are matched by name, unless mapped to a data source UID. Like the bulk creation,
+// the correlations are created in a single transaction and the response lists every failed correlation.
+//
+// Responses:
+// 200: bulkCreateCorrelationsResponse
+// 400: bulkCreateCorrelationsResponse
+// 401: unauthorisedError
+// 403: bulkCreateCorrelationsResponse
+// 404: bulkCreateCorrelationsResponse
+// 500: internalServerError
+func (s *CorrelationsService) importHandler(c *models.ReqContext) response.Response {
+	cmd := ImportCorrelationsCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgId = c.OrgID
+	cmd.UserId = c.UserID
+
+	bulkCmd, failures, err := s.resolveImport(c.Req.Context(), cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to import correlations", err)
+	}
+	if len(failures) > 0 {
+		bulkErr := BulkCorrelationsError{Errors: failures}
+		return response.JSON(bulkErr.StatusCode(), BulkCreateCorrelationsResponseBody{Message: "No correlations imported", Errors: failures})
+	}
+	if len(bulkCmd.Correlations) == 0 {
+		return response.JSON(http.StatusOK, BulkCreateCorrelationsResponseBody{Result: []Correlation{}, Message: "Correlations imported"})
+	}
+
+	sourceUIDs := make([]string, 0, len(bulkCmd.Correlations))
+	for _, item := range bulkCmd.Correlations {
+		sourceUIDs = append(sourceUIDs, item.SourceUID)
+	}
+	denied, err := s.checkBulkPermissions(c, ActionCreate, sourceUIDs, nil)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to check permissions", err)
+	}
+	if len(denied) > 0 {
+		return response.JSON(http.StatusForbidden, BulkCreateCorrelationsResponseBody{Message: "No correlations imported", Errors: denied})
+	}
+
+	created, err := s.bulkCreateCorrelations(c.Req.Context(), bulkCmd)
+	if err != nil {
+		var bulkErr BulkCorrelationsError
+		if errors.As(err, &bulkErr) {
+			return response.JSON(bulkErr.StatusCode(), BulkCreateCorrelationsResponseBody{Message: "No correlations imported", Errors: bulkErr.Errors})
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to import correlations", err)
+	}
+
+	return response.JSON(http.StatusOK, BulkCreateCorrelationsResponseBody{Result: created, Message: "Correlations imported"})
+}
+
+// swagger:parameters importCorrelations
+type ImportCorrelationsParams struct {
+	// in:body
+	// required:true
+	Body ImportCorrelationsCommand `json:"body"`
+}
+
+// swagger:route POST /datasources/correlations/copy correlations copyCorrelations
+//
+// Copy correlations to another organization.
+//
+// The data sources of the correlations are matched by name in the destination organization, unless mapped to a
+// data source UID. The correlations whose data sources can't be matched are skipped and listed as unmapped, the
+// others are created in a single transaction. Only Grafana admins can copy correlations.
+//
+// Responses:
+// 200: copyCorrelationsResponse
+// 400: copyCorrelationsResponse
+// 401: unauthorisedError
+// 403: copyCorrelationsResponse
+// 404: copyCorrelationsResponse
+// 500: internalServerError
+func (s *CorrelationsService) copyHandler(c *models.ReqContext) response.Response {
+	cmd := CopyCorrelationsCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.UserId = c.UserID
+
+	created, unmapped, err := s.CopyCorrelations(c.Req.Context(), cmd)
+	if err != nil {
+		var bulkErr BulkCorrelationsError
+		if errors.As(err, &bulkErr) {
+			return response.JSON(bulkErr.StatusCode(), CopyCorrelationsResponseBody{Message: "No correlations copied", Errors: bulkErr.Errors})
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to copy correlations", err)
+	}
+
+	s.log.Info("Copied correlations", "fromOrgId", cmd.FromOrgId, "toOrgId", cmd.ToOrgId, "count", len(created), "unmapped", len(unmapped), "userId", c.UserID)
+	return response.JSON(http.StatusOK, CopyCorrelationsResponseBody{Result: created, Message: "Correlations copied", Unmapped: unmapped})
+}
+
+// swagger:parameters copyCorrelations
+type CopyCorrelationsParams struct {
+	// in:body
+	// required:true
+	Body CopyCorrelationsCommand `json:"body"`
+}
+
+//swagger:response copyCorrelationsResponse
+type CopyCorrelationsResponse struct {
+	// in: body
+	Body CopyCorrelationsResponseBody `json:"body"`
+}
+
+// swagger:route POST /datasources/uid/{sourceUID}/correlations/migrate correlations migrateDerivedFields
+//
+// Migrate the derived fields of a Loki data source to correlations.
+//
+// The derived fields linking to a data source become query correlations extracting the value of the log line with
+// the regular expression of the derived field. The derived fields linking to a URL, the ones that don't make a valid
+// correlation and the ones with an equivalent correlation are skipped and listed. The correlations are created in a
+// single transaction, and the migrated derived fields are optionally deleted from the data source in the same one.
+//
+// Responses:
+// 200: migrateDerivedFieldsResponse
+// 400: migrateDerivedFieldsResponse
+// 401: unauthorisedError
+// 403: migrateDerivedFieldsResponse
+// 404: migrateDerivedFieldsResponse
+// 500: internalServerError
+func (s *CorrelationsService) migrateHandler(c *models.ReqContext) response.Response {
+	cmd := MigrateDerivedFieldsCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.SourceUID = web.Params(c.Req)[":uid"]
+	cmd.OrgId = c.OrgID
+	cmd.UserId = c.UserID
+
+	if cmd.DeleteDerivedFields && !cmd.DryRun && !s.AccessControl.IsDisabled() {
+		evaluator := ac.EvalPermission(datasources.ActionWrite, datasources.ScopeProvider.GetResourceScopeUID(cmd.SourceUID))
+		ok, err := s.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, evaluator)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to check permissions", err)
+		}
+		if !ok {
+			return response.Error(http.StatusForbidden, "Permission denied: the derived fields of the data source can't be deleted", nil)
+		}
+	}
+
+	created, skipped, err := s.MigrateDerivedFields(c.Req.Context(), cmd)
+	if err != nil {
+		var bulkErr BulkCorrelationsError
+		if errors.As(err, &bulkErr) {
+			return response.JSON(bulkErr.StatusCode(), MigrateDerivedFieldsResponseBody{Message: "No derived fields migrated", Errors: bulkErr.Errors})
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to migrate derived fields", err)
+	}
+
+	if cmd.DryRun {
+		return response.JSON(http.StatusOK, MigrateDerivedFieldsResponseBody{Result: created, Message: "Derived fields to migrate", Skipped: skipped})
+	}
+	s.log.Info("Migrated derived fields", "sourceUID", cmd.SourceUID, "orgId", cmd.OrgId, "count", len(created), "skipped", len(skipped), "deleted", cmd.DeleteDerivedFields, "userId", c.UserID)
+	return response.JSON(http.StatusOK, MigrateDerivedFieldsResponseBody{Result: created, Message: "Derived fields migrated", Skipped: skipped})
+}
+
+// swagger:parameters migrateDerivedFields
+type MigrateDerivedFieldsParams struct {
+	// in:body
+	// required:true
+	Body MigrateDerivedFieldsCommand `json:"body"`
+	// in:path
+	// required:true
+	SourceUID string `json:"sourceUID"`
+}
+
+//swagger:response migrateDerivedFieldsResponse
+type MigrateDerivedFieldsResponse struct {
+	// in: body
+	Body MigrateDerivedFieldsResponseBody `json:"body"`
+}
+
+// checkBulkPermissions returns a failure for every correlation the user isn't
+// allowed the action on, given the source data source. uids is optional.
+func (s *CorrelationsService) checkBulkPermissions(c *models.ReqContext, action string, sourceUIDs []string, uids []string) ([]BulkCorrelationError, error) {
+	// without access control the route already requires the org admin role
+	if s.AccessControl.IsDisabled() {
+		return nil, nil
+	}
+
+	var denied []BulkCorrelationError
+	for i, sourceUID := range sourceUIDs {
+		evaluator := EvalManage(action, ScopeProvider.GetResourceScopeUID(sourceUID))
+		ok, err := s.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, evaluator)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			continue
+		}
+
+		failure := BulkCorrelationError{
+			Index:     i,
+			SourceUID: sourceUID,
+			PublicError: errutil.PublicError{
+				StatusCode: http.StatusForbidden,
+				MessageID:  "correlations.permissionDenied",
+				Message:    "Permission denied",
+			},
+		}
+		if uids != nil {
+			failure.UID = uids[i]
+		}
+		denied = append(denied, failure)
+	}
+	return denied, nil
+}
@@ -0,0 +1,101 @@
+package correlations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+)
+
+// auditLogger logs the changes of correlations under its own name so that they
+// can be filtered, e.g. with filters = correlations.audit:info
+var auditLogger = log.New("correlations.audit")
+
+// audit logs the change of a correlation and records it as an organization
+// annotation tagged with AuditAnnotationTag, so that admins can trace who
+// changed it. before is nil for created correlations and after is nil for
+// deleted ones. Failing to record the annotation doesn't fail the change.
+func (s CorrelationsService) audit(ctx context.Context, orgID, userID int64, action string, before, after *Correlation) {
+	correlation := after
+	if correlation == nil {
+		correlation = before
+	}
+
+	event := CorrelationAuditEvent{
+		OrgId:     orgID,
+		UserId:    userID,
+		UID:       correlation.UID,
+		SourceUID: correlation.SourceUID,
+		Action:    action,
+		Diff:      correlationDiff(before, after),
+	}
+
+	fields := make([]string, 0, len(event.Diff))
+	for field := range event.Diff {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	auditLogger.Info("Correlation "+action, "orgId", orgID, "userId", userID, "uid", event.UID, "sourceUID", event.SourceUID, "fields", strings.Join(fields, ","))
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		auditLogger.Error("Failed to encode correlation audit event", "uid", event.UID, "sourceUID", event.SourceUID, "error", err)
+		return
+	}
+	item := &annotations.Item{
+		OrgId:  orgID,
+		UserId: userID,
+		Text:   fmt.Sprintf("Correlation %q %s", correlation.Label, action),
+		Epoch:  time.Now().UnixNano() / int64(time.Millisecond),
+		Tags:   []string{AuditAnnotationTag, action},
+	}
+	if item.Data, err = simplejson.NewJson(data); err != nil {
+		auditLogger.Error("Failed to encode correlation audit event", "uid", event.UID, "sourceUID", event.SourceUID, "error", err)
+		return
+	}
+	if err := s.Annotations.Save(ctx, item); err != nil {
+		auditLogger.Error("Failed to save correlation audit annotation", "uid", event.UID, "sourceUID", event.SourceUID, "error", err)
+	}
+}
+
+// correlationDiff returns the fields that differ between the two states of a
+// correlation, either of which can be nil
+func correlationDiff(before, after *Correlation) map[string]CorrelationFieldChange {
+	fields := func(c *Correlation) map[string]interface{} {
+		if c == nil {
+			return map[string]interface{}{}
+		}
+		f := map[string]interface{}{
+			"label":       c.Label,
+			"description": c.Description,
+			"config":      c.Config,
+			"order":       c.Order,
+		}
+		if c.TargetUID != nil {
+			f["targetUID"] = *c.TargetUID
+		}
+		if len(c.Tags) > 0 {
+			f["tags"] = c.Tags
+		}
+		if c.Visibility != nil {
+			f["visibility"] = *c.Visibility
+		}
+		return f
+	}
+
+	prev, next := fields(before), fields(after)
+	diff := map[string]CorrelationFieldChange{}
+	for _, name := range []string{"label", "description", "targetUID", "config", "order", "tags", "visibility"} {
+		if !reflect.DeepEqual(prev[name], next[name]) {
+			diff[name] = CorrelationFieldChange{Old: prev[name], New: next[name]}
+		}
+	}
+	return diff
+}
@@ -0,0 +1,115 @@
+package correlations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+type publicError interface {
+	Public() errutil.PublicError
+}
+
+// bulkCreateCorrelations creates the correlations in a single transaction. Every
+// correlation is attempted so that all the failures are reported, unless a
+// database error leaves the transaction unusable.
+func (s CorrelationsService) bulkCreateCorrelations(ctx context.Context, cmd BulkCreateCorrelationsCommand) ([]Correlation, error) {
+	defer s.invalidateLists()
+
+	created := make([]Correlation, 0, len(cmd.Correlations))
+
+	err := s.SQLStore.InTransaction(ctx, func(ctx context.Context) error {
+		var failures []BulkCorrelationError
+		for i, item := range cmd.Correlations {
+			createCmd := item.CreateCorrelationCommand
+			createCmd.SourceUID = item.SourceUID
+			createCmd.OrgId = cmd.OrgId
+			createCmd.UserId = cmd.UserId
+			createCmd.Force = cmd.Force
+
+			err := createCmd.Validate()
+			if err == nil {
+				var correlation Correlation
+				if correlation, err = s.createCorrelation(ctx, createCmd); err == nil {
+					created = append(created, correlation)
+				}
+			}
+			if err != nil {
+				failure, recoverable := toBulkCorrelationError(i, item.SourceUID, "", err)
+				failures = append(failures, failure)
+				if !recoverable {
+					break
+				}
+			}
+		}
+
+		if len(failures) > 0 {
+			return BulkCorrelationsError{Errors: failures}
+		}
+		return nil
+	})
+	observeBulkChanges(opCreate, len(created), err)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// bulkDeleteCorrelations deletes the correlations in a single transaction, with
+// the same failure semantics as bulkCreateCorrelations.
+func (s CorrelationsService) bulkDeleteCorrelations(ctx context.Context, cmd BulkDeleteCorrelationsCommand) error {
+	defer s.invalidateLists()
+
+	err := s.SQLStore.InTransaction(ctx, func(ctx context.Context) error {
+		var failures []BulkCorrelationError
+		for i, item := range cmd.Correlations {
+			err := s.deleteCorrelation(ctx, DeleteCorrelationCommand{
+				UID:       item.UID,
+				SourceUID: item.SourceUID,
+				OrgId:     cmd.OrgId,
+				UserId:    cmd.UserId,
+			})
+			if err != nil {
+				failure, recoverable := toBulkCorrelationError(i, item.SourceUID, item.UID, err)
+				failures = append(failures, failure)
+				if !recoverable {
+					break
+				}
+			}
+		}
+
+		if len(failures) > 0 {
+			return BulkCorrelationsError{Errors: failures}
+		}
+		return nil
+	})
+	observeBulkChanges(opDelete, len(cmd.Correlations), err)
+	return err
+}
+
+// toBulkCorrelationError reports the failure of a correlation of a bulk command.
+// Only the errors with a public message are recoverable, any other error may
+// come from the database and is reported as an internal error.
+func toBulkCorrelationError(index int, sourceUID, uid string, err error) (BulkCorrelationError, bool) {
+	failure := BulkCorrelationError{
+		Index:     index,
+		SourceUID: sourceUID,
+		UID:       uid,
+	}
+
+	var pubErr publicError
+	if !errors.As(err, &pubErr) {
+		failure.PublicError = errutil.PublicError{
+			StatusCode: http.StatusInternalServerError,
+			MessageID:  "correlations.internal",
+			Message:    "Internal server error",
+		}
+		return failure, false
+	}
+
+	failure.PublicError = pubErr.Public()
+	return failure, true
+}
@@ -0,0 +1,217 @@
+package correlations
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+// exportCorrelations returns the correlations of the organization visible to
+// the user with their data sources resolved to names.
+func (s CorrelationsService) exportCorrelations(ctx context.Context, query ExportCorrelationsQuery) (CorrelationsBundle, error) {
+	correlations, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: query.OrgId, SignedInUser: query.SignedInUser})
+	if err != nil {
+		return CorrelationsBundle{}, err
+	}
+
+	dsQuery := &datasources.GetDataSourcesQuery{OrgId: query.OrgId}
+	if err := s.DataSourceService.GetDataSources(ctx, dsQuery); err != nil {
+		return CorrelationsBundle{}, err
+	}
+	names := make(map[string]string, len(dsQuery.Result))
+	for _, ds := range dsQuery.Result {
+		names[ds.Uid] = ds.Name
+	}
+
+	bundle := CorrelationsBundle{Correlations: make([]BundledCorrelation, 0, len(correlations))}
+	for _, correlation := range correlations {
+		sourceName, ok := names[correlation.SourceUID]
+		if !ok {
+			continue
+		}
+
+		bundled := BundledCorrelation{
+			SourceName:  sourceName,
+			Label:       correlation.Label,
+			Description: correlation.Description,
+			Config:      correlation.Config,
+			Order:       correlation.Order,
+			Tags:        correlation.Tags,
+		}
+		if correlation.TargetUID != nil {
+			targetName, ok := names[*correlation.TargetUID]
+			if !ok {
+				continue
+			}
+			bundled.TargetName = &targetName
+		}
+		bundle.Correlations = append(bundle.Correlations, bundled)
+	}
+
+	return bundle, nil
+}
+
+// resolveImport maps the data source names of the bundle to the data sources
+// of the organization, and returns a failure for every correlation referencing
+// a data source that doesn't exist.
+func (s CorrelationsService) resolveImport(ctx context.Context, cmd ImportCorrelationsCommand) (BulkCreateCorrelationsCommand, []BulkCorrelationError, error) {
+	dsQuery := &datasources.GetDataSourcesQuery{OrgId: cmd.OrgId}
+	if err := s.DataSourceService.GetDataSources(ctx, dsQuery); err != nil {
+		return BulkCreateCorrelationsCommand{}, nil, err
+	}
+	uids := make(map[string]string, len(dsQuery.Result))
+	for _, ds := range dsQuery.Result {
+		uids[ds.Name] = ds.Uid
+	}
+	resolve := func(name string) (string, bool) {
+		if uid, ok := cmd.DataSources[name]; ok {
+			return uid, true
+		}
+		uid, ok := uids[name]
+		return uid, ok
+	}
+
+	bulkCmd := BulkCreateCorrelationsCommand{OrgId: cmd.OrgId, UserId: cmd.UserId, Correlations: make([]BulkCreateCorrelationsItem, 0, len(cmd.Correlations))}
+	var failures []BulkCorrelationError
+	for i, bundled := range cmd.Correlations {
+		item := BulkCreateCorrelationsItem{
+			CreateCorrelationCommand: CreateCorrelationCommand{
+				Label:       bundled.Label,
+				Description: bundled.Description,
+				Config:      bundled.Config,
+				Order:       bundled.Order,
+				Tags:        bundled.Tags,
+			},
+		}
+
+		missing := ""
+		sourceUID, ok := resolve(bundled.SourceName)
+		if !ok {
+			missing = bundled.SourceName
+		}
+		item.SourceUID = sourceUID
+		if bundled.TargetName != nil {
+			targetUID, ok := resolve(*bundled.TargetName)
+			if !ok && missing == "" {
+				missing = *bundled.TargetName
+			}
+			item.TargetUID = &targetUID
+		}
+
+		if missing != "" {
+			failure, _ := toBulkCorrelationError(i, sourceUID, "", ErrBundleDataSourceNotFound.Errorf("data source %s not found", missing))
+			failure.Extra = map[string]interface{}{"name": missing}
+			failures = append(failures, failure)
+			continue
+		}
+		bulkCmd.Correlations = append(bulkCmd.Correlations, item)
+	}
+
+	return bulkCmd, failures, nil
+}
+
+func (s CorrelationsService) importCorrelations(ctx context.Context, cmd ImportCorrelationsCommand) ([]Correlation, error) {
+	bulkCmd, failures, err := s.resolveImport(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	if len(failures) > 0 {
+		return nil, BulkCorrelationsError{Errors: failures}
+	}
+	if len(bulkCmd.Correlations) == 0 {
+		return []Correlation{}, nil
+	}
+
+	return s.bulkCreateCorrelations(ctx, bulkCmd)
+}
+
+// copyCorrelations copies the correlations to another organization, matching
+// their data sources by name like an import. The correlations whose data sources
+// can't be matched are skipped and returned as unmapped.
+func (s CorrelationsService) copyCorrelations(ctx context.Context, cmd CopyCorrelationsCommand) ([]Correlation, []BulkCorrelationError, error) {
+	dsQuery := &datasources.GetDataSourcesQuery{OrgId: cmd.FromOrgId}
+	if err := s.DataSourceService.GetDataSources(ctx, dsQuery); err != nil {
+		return nil, nil, err
+	}
+	names := make(map[string]string, len(dsQuery.Result))
+	for _, ds := range dsQuery.Result {
+		names[ds.Uid] = ds.Name
+	}
+
+	bundle := CorrelationsBundle{Correlations: make([]BundledCorrelation, 0, len(cmd.Correlations))}
+	var failures []BulkCorrelationError
+	for i, item := range cmd.Correlations {
+		correlation, err := s.getCorrelation(ctx, GetCorrelationQuery{UID: item.UID, SourceUID: item.SourceUID, OrgId: cmd.FromOrgId})
+		if err != nil {
+			failure, recoverable := toBulkCorrelationError(i, item.SourceUID, item.UID, err)
+			failures = append(failures, failure)
+			if !recoverable {
+				break
+			}
+			continue
+		}
+
+		bundled := BundledCorrelation{
+			SourceName:  names[correlation.SourceUID],
+			Label:       correlation.Label,
+			Description: correlation.Description,
+			Config:      correlation.Config,
+			Order:       correlation.Order,
+			Tags:        correlation.Tags,
+		}
+		if correlation.TargetUID != nil {
+			targetName := names[*correlation.TargetUID]
+			bundled.TargetName = &targetName
+		}
+		bundle.Correlations = append(bundle.Correlations, bundled)
+	}
+	if len(failures) > 0 {
+		return nil, nil, BulkCorrelationsError{Errors: failures}
+	}
+
+	bulkCmd, unmapped, err := s.resolveImport(ctx, ImportCorrelationsCommand{
+		OrgId:              cmd.ToOrgId,
+		UserId:             cmd.UserId,
+		CorrelationsBundle: bundle,
+		DataSources:        cmd.DataSources,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the bundle follows the order of the command, report the copied
+	// correlations rather than the data sources they would be copied to
+	skipped := make(map[int]bool, len(unmapped))
+	for i := range unmapped {
+		skipped[unmapped[i].Index] = true
+		unmapped[i].SourceUID = cmd.Correlations[unmapped[i].Index].SourceUID
+		unmapped[i].UID = cmd.Correlations[unmapped[i].Index].UID
+	}
+	positions := make([]int, 0, len(bulkCmd.Correlations))
+	for i := range cmd.Correlations {
+		if !skipped[i] {
+			positions = append(positions, i)
+		}
+	}
+
+	if len(bulkCmd.Correlations) == 0 {
+		return []Correlation{}, unmapped, nil
+	}
+
+	created, err := s.bulkCreateCorrelations(ctx, bulkCmd)
+	if err != nil {
+		var bulkErr BulkCorrelationsError
+		if errors.As(err, &bulkErr) {
+			for i := range bulkErr.Errors {
+				bulkErr.Errors[i].Index = positions[bulkErr.Errors[i].Index]
+				bulkErr.Errors[i].SourceUID = cmd.Correlations[bulkErr.Errors[i].Index].SourceUID
+				bulkErr.Errors[i].UID = cmd.Correlations[bulkErr.Errors[i].Index].UID
+			}
+			return nil, nil, bulkErr
+		}
+		return nil, nil, err
+	}
+
+	return created, unmapped, nil
+}
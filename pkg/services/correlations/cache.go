@@ -0,0 +1,137 @@
+package correlations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+const (
+	// listCacheTTL bounds how long another instance of an HA setup serves the
+	// lists after they changed, since only the instance making the change
+	// invalidates its cache
+	listCacheTTL             = time.Minute
+	listCacheCleanupInterval = 5 * time.Minute
+)
+
+// cachedList is an encoded list of correlations and its ETag
+type cachedList struct {
+	data []byte
+	etag string
+}
+
+// listCacheKey identifies a list of correlations of an organization, either
+// all of them or the ones of a data source, filtered by the tags and with or
+// without their data sources, as seen by an audience
+func listCacheKey(orgID int64, list string, uid string, tags []string, expand bool, audience string) string {
+	return fmt.Sprintf("%d/%s/%s/%s/%t/%s", orgID, list, uid, strings.Join(normalizeTags(tags), ","), expand, audience)
+}
+
+// listResponse responds with the list of correlations of the organization,
+// loading and caching it if it's missing. It responds with 304 Not Modified
+// when the If-None-Match header of the request matches the ETag of the list.
+// The correlations include their data sources with expand=datasources, and
+// the ones that aren't visible to the user are left out.
+func (s *CorrelationsService) listResponse(c *models.ReqContext, name string, uid string, tags []string, load func() ([]Correlation, error)) response.Response {
+	expand, err := expandDataSources(c)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "Invalid expand parameter", err)
+	}
+
+	return s.cachedResponse(c, name, uid, tags, expand, func() (interface{}, error) {
+		correlations, err := load()
+		if err != nil {
+			return nil, err
+		}
+		correlations = visibleCorrelations(c.SignedInUser, correlations)
+		if !expand {
+			return correlations, nil
+		}
+		dataSources, err := s.dataSourcesByUID(c.Req.Context(), c.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		withDataSources(correlations, dataSources)
+		return correlations, nil
+	})
+}
+
+// cachedResponse is listResponse for any encoding of the correlations, such as
+// the correlations of several data sources keyed by their UID
+func (s *CorrelationsService) cachedResponse(c *models.ReqContext, name string, uid string, tags []string, expand bool, load func() (interface{}, error)) response.Response {
+	timer := prometheus.NewTimer(listDuration.WithLabelValues(name))
+	defer timer.ObserveDuration()
+
+	key := listCacheKey(c.OrgID, name, uid, tags, expand, audience(c.SignedInUser))
+	list, ok := s.getCachedList(key)
+	if !ok {
+		correlations, err := load()
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get correlations", err)
+		}
+
+		// use a configuration that's compatible with the standard library so
+		// that equal lists get the same ETag
+		data, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(correlations)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to encode correlations", err)
+		}
+		sum := sha256.Sum256(data)
+		list = cachedList{data: data, etag: `"` + hex.EncodeToString(sum[:16]) + `"`}
+		if s.listCache != nil {
+			s.listCache.SetDefault(key, list)
+		}
+	}
+
+	c.Resp.Header().Set("ETag", list.etag)
+	if etagMatches(c.Req.Header.Get("If-None-Match"), list.etag) {
+		return response.Respond(http.StatusNotModified, []byte{})
+	}
+	return response.Respond(http.StatusOK, list.data).SetHeader("Content-Type", "application/json")
+}
+
+// getCachedList returns the list of correlations cached under key
+func (s *CorrelationsService) getCachedList(key string) (cachedList, bool) {
+	if s.listCache == nil {
+		return cachedList{}, false
+	}
+
+	value, ok := s.listCache.Get(key)
+	if !ok {
+		return cachedList{}, false
+	}
+	return value.(cachedList), true
+}
+
+// invalidateLists drops the cached lists of correlations. Writes don't always
+// know the organizations they affect, and are rare compared to reads, so the
+// lists of every organization are dropped. Writes defer it, so that the lists
+// are dropped again once the outer transaction of nested writes ends.
+func (s CorrelationsService) invalidateLists() {
+	if s.listCache != nil {
+		s.listCache.Flush()
+	}
+}
+
+// etagMatches uses the weak comparison of If-None-Match, a list of ETags or *
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -2,38 +2,79 @@ package correlations
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/events"
 
+	"github.com/grafana/grafana/pkg/infra/jobs"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/orgpolicy"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
-func ProvideService(sqlStore *sqlstore.SQLStore, routeRegister routing.RouteRegister, ds datasources.DataSourceService, ac accesscontrol.AccessControl, bus bus.Bus) *CorrelationsService {
+func ProvideService(sqlStore *sqlstore.SQLStore, routeRegister routing.RouteRegister, ds datasources.DataSourceService, ac accesscontrol.AccessControl, bus bus.Bus, orgPolicies orgpolicy.Service, rateLimits ratelimit.Service, scheduler jobs.Scheduler, annotationsRepo annotations.Repository, queryService *query.Service, quotaService quota.Service, pluginStore plugins.Store, cfg *setting.Cfg) *CorrelationsService {
 	s := &CorrelationsService{
 		SQLStore:          sqlStore,
 		RouteRegister:     routeRegister,
 		log:               log.New("correlations"),
 		DataSourceService: ds,
 		AccessControl:     ac,
+		OrgPolicies:       orgPolicies,
+		RateLimits:        rateLimits,
+		Annotations:       annotationsRepo,
+		QueryService:      queryService,
+		QuotaService:      quotaService,
+		PluginStore:       pluginStore,
+		Cfg:               cfg,
+		bus:               bus,
+		listCache:         localcache.New(listCacheTTL, listCacheCleanupInterval),
 	}
 
 	s.registerAPIEndpoints()
+	s.registerJobs(scheduler)
 
 	bus.AddEventListener(s.handleDatasourceDeletion)
+	bus.AddEventListener(s.handleDatasourceUIDChange)
+	bus.AddEventListener(s.handleDashboardDeletion)
 
 	return s
 }
 
 type Service interface {
 	CreateCorrelation(ctx context.Context, cmd CreateCorrelationCommand) (Correlation, error)
+	UpdateCorrelation(ctx context.Context, cmd UpdateCorrelationCommand) (Correlation, error)
+	UpsertCorrelation(ctx context.Context, cmd CreateCorrelationCommand) (Correlation, bool, error)
+	GetCorrelation(ctx context.Context, cmd GetCorrelationQuery) (Correlation, error)
+	GetCorrelations(ctx context.Context, cmd GetCorrelationsQuery) ([]Correlation, error)
+	GetCorrelationsBySourceUID(ctx context.Context, cmd GetCorrelationsBySourceUIDQuery) ([]Correlation, error)
+	GetCorrelationsBySourceUIDs(ctx context.Context, cmd GetCorrelationsBySourceUIDsQuery) (map[string][]Correlation, error)
+	GetCorrelationsByTargetUID(ctx context.Context, cmd GetCorrelationsByTargetUIDQuery) ([]Correlation, error)
+	GetCorrelationsBySourceType(ctx context.Context, cmd GetCorrelationsBySourceTypeQuery) ([]Correlation, error)
+	SearchCorrelations(ctx context.Context, query SearchCorrelationsQuery) ([]CorrelationSearchResult, error)
 	DeleteCorrelation(ctx context.Context, cmd DeleteCorrelationCommand) error
+	BulkCreateCorrelations(ctx context.Context, cmd BulkCreateCorrelationsCommand) ([]Correlation, error)
+	BulkDeleteCorrelations(ctx context.Context, cmd BulkDeleteCorrelationsCommand) error
+	GetCorrelationVersions(ctx context.Context, query GetCorrelationVersionsQuery) ([]CorrelationVersion, error)
+	RestoreCorrelationVersion(ctx context.Context, cmd RestoreCorrelationVersionCommand) (Correlation, error)
+	ExportCorrelations(ctx context.Context, query ExportCorrelationsQuery) (CorrelationsBundle, error)
+	ImportCorrelations(ctx context.Context, cmd ImportCorrelationsCommand) ([]Correlation, error)
+	CopyCorrelations(ctx context.Context, cmd CopyCorrelationsCommand) ([]Correlation, []BulkCorrelationError, error)
+	MigrateDerivedFields(ctx context.Context, cmd MigrateDerivedFieldsCommand) ([]Correlation, []SkippedDerivedField, error)
 	DeleteCorrelationsBySourceUID(ctx context.Context, cmd DeleteCorrelationsBySourceUIDCommand) error
 	DeleteCorrelationsByTargetUID(ctx context.Context, cmd DeleteCorrelationsByTargetUIDCommand) error
+	UpdateCorrelationsSourceUID(ctx context.Context, cmd UpdateCorrelationsSourceUIDCommand) error
+	UpdateCorrelationsTargetUID(ctx context.Context, cmd UpdateCorrelationsTargetUIDCommand) error
 }
 
 type CorrelationsService struct {
@@ -42,30 +83,207 @@ type CorrelationsService struct {
 	log               log.Logger
 	DataSourceService datasources.DataSourceService
 	AccessControl     accesscontrol.AccessControl
+	OrgPolicies       orgpolicy.Service
+	RateLimits        ratelimit.Service
+	Annotations       annotations.Repository
+	QueryService      QueryService
+	QuotaService      quota.Service
+	PluginStore       plugins.Store
+	Cfg               *setting.Cfg
+	bus               bus.Bus
+	// listCache holds the encoded lists of correlations served by the API
+	listCache *localcache.CacheService
 }
 
 func (s CorrelationsService) CreateCorrelation(ctx context.Context, cmd CreateCorrelationCommand) (Correlation, error) {
-	return s.createCorrelation(ctx, cmd)
+	correlation, err := s.createCorrelation(ctx, cmd)
+	observeChange(opCreate, err)
+	return correlation, err
 }
 
 func (s CorrelationsService) DeleteCorrelation(ctx context.Context, cmd DeleteCorrelationCommand) error {
-	return s.deleteCorrelation(ctx, cmd)
+	err := s.deleteCorrelation(ctx, cmd)
+	observeChange(opDelete, err)
+	return err
+}
+
+// BulkCreateCorrelations creates all the correlations or none. When some of
+// them fail, the returned BulkCorrelationsError lists every failure.
+func (s CorrelationsService) BulkCreateCorrelations(ctx context.Context, cmd BulkCreateCorrelationsCommand) ([]Correlation, error) {
+	return s.bulkCreateCorrelations(ctx, cmd)
+}
+
+// BulkDeleteCorrelations deletes all the correlations or none. When some of
+// them fail, the returned BulkCorrelationsError lists every failure.
+func (s CorrelationsService) BulkDeleteCorrelations(ctx context.Context, cmd BulkDeleteCorrelationsCommand) error {
+	return s.bulkDeleteCorrelations(ctx, cmd)
+}
+
+// ExportCorrelations returns all the correlations of the organization as a
+// bundle, referencing the data sources by name.
+func (s CorrelationsService) ExportCorrelations(ctx context.Context, query ExportCorrelationsQuery) (CorrelationsBundle, error) {
+	return s.exportCorrelations(ctx, query)
+}
+
+// ImportCorrelations creates the correlations of the bundle, resolving the data
+// sources by name unless mapped by the command. Like BulkCreateCorrelations,
+// either all the correlations are created or none.
+func (s CorrelationsService) ImportCorrelations(ctx context.Context, cmd ImportCorrelationsCommand) ([]Correlation, error) {
+	return s.importCorrelations(ctx, cmd)
+}
+
+// CopyCorrelations copies the correlations to another organization, matching
+// their data sources by name unless mapped by the command. The correlations
+// whose data sources can't be matched are skipped and returned as unmapped,
+// the others are either all copied or none.
+func (s CorrelationsService) CopyCorrelations(ctx context.Context, cmd CopyCorrelationsCommand) ([]Correlation, []BulkCorrelationError, error) {
+	return s.copyCorrelations(ctx, cmd)
+}
+
+// MigrateDerivedFields creates the correlations equivalent to the derived
+// fields of a Loki data source that link to a data source, and returns the
+// skipped derived fields. The correlations are either all created or none.
+func (s CorrelationsService) MigrateDerivedFields(ctx context.Context, cmd MigrateDerivedFieldsCommand) ([]Correlation, []SkippedDerivedField, error) {
+	return s.migrateDerivedFields(ctx, cmd)
 }
 
 func (s CorrelationsService) UpdateCorrelation(ctx context.Context, cmd UpdateCorrelationCommand) (Correlation, error) {
-	return s.updateCorrelation(ctx, cmd)
+	correlation, err := s.updateCorrelation(ctx, cmd)
+	observeChange(opUpdate, err)
+	return correlation, err
 }
 
+// UpsertCorrelation creates the correlation with the UID of the command, or
+// replaces it when its source data source already has it. It tells whether the
+// correlation was created.
+func (s CorrelationsService) UpsertCorrelation(ctx context.Context, cmd CreateCorrelationCommand) (Correlation, bool, error) {
+	correlation, created, err := s.upsertCorrelation(ctx, cmd)
+	if created {
+		observeChange(opCreate, err)
+	} else {
+		observeChange(opUpdate, err)
+	}
+	return correlation, created, err
+}
+
+// GetCorrelation returns the correlation when it is visible to the user of the
+// query, and ErrCorrelationNotFound otherwise.
 func (s CorrelationsService) GetCorrelation(ctx context.Context, cmd GetCorrelationQuery) (Correlation, error) {
-	return s.getCorrelation(ctx, cmd)
+	correlation, err := s.getCorrelation(ctx, cmd)
+	if err != nil {
+		return Correlation{}, err
+	}
+	// the correlations that aren't visible to the user are hidden as if they didn't exist
+	if !visibleTo(cmd.SignedInUser, correlation) {
+		return Correlation{}, ErrCorrelationNotFound.Errorf("correlation %s is not visible to the user", cmd.UID)
+	}
+	return correlation, nil
 }
 
 func (s CorrelationsService) GetCorrelationsBySourceUID(ctx context.Context, cmd GetCorrelationsBySourceUIDQuery) ([]Correlation, error) {
-	return s.getCorrelationsBySourceUID(ctx, cmd)
+	correlations, err := s.getCorrelationsBySourceUID(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return visibleCorrelations(cmd.SignedInUser, correlations), nil
+}
+
+// GetCorrelationsBySourceUIDs returns the correlations of each of the source
+// data sources, keyed by their UID, in a single query
+func (s CorrelationsService) GetCorrelationsBySourceUIDs(ctx context.Context, cmd GetCorrelationsBySourceUIDsQuery) (map[string][]Correlation, error) {
+	correlations, err := s.getCorrelationsBySourceUIDs(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	for uid, list := range correlations {
+		correlations[uid] = visibleCorrelations(cmd.SignedInUser, list)
+	}
+	return correlations, nil
+}
+
+func (s CorrelationsService) GetCorrelationsByTargetUID(ctx context.Context, cmd GetCorrelationsByTargetUIDQuery) ([]Correlation, error) {
+	correlations, err := s.getCorrelationsByTargetUID(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return visibleCorrelations(cmd.SignedInUser, correlations), nil
+}
+
+// GetCorrelationsBySourceType returns the correlations applying to all the data
+// sources of the plugin type, which are also returned for each of the data sources.
+func (s CorrelationsService) GetCorrelationsBySourceType(ctx context.Context, cmd GetCorrelationsBySourceTypeQuery) ([]Correlation, error) {
+	correlations, err := s.getCorrelationsBySourceType(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return visibleCorrelations(cmd.SignedInUser, correlations), nil
 }
 
 func (s CorrelationsService) GetCorrelations(ctx context.Context, cmd GetCorrelationsQuery) ([]Correlation, error) {
-	return s.getCorrelations(ctx, cmd)
+	correlations, err := s.getCorrelations(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return visibleCorrelations(cmd.SignedInUser, correlations), nil
+}
+
+// SearchCorrelations returns the correlations of the organization visible to
+// the user whose label, description or target contains the query, the most
+// relevant first.
+func (s CorrelationsService) SearchCorrelations(ctx context.Context, query SearchCorrelationsQuery) ([]CorrelationSearchResult, error) {
+	return s.searchCorrelations(ctx, query)
+}
+
+// RecordResolution records the resolution of the correlation link for its daily usage,
+// and publishes it for usage insights.
+func (s CorrelationsService) RecordResolution(ctx context.Context, cmd RecordCorrelationResolutionCommand) error {
+	correlation, err := s.getCorrelation(ctx, GetCorrelationQuery{UID: cmd.UID, SourceUID: cmd.SourceUID, OrgId: cmd.OrgId})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.saveResolution(ctx, CorrelationResolution{
+		OrgId:          cmd.OrgId,
+		CorrelationUID: correlation.UID,
+		SourceUID:      correlation.SourceUID,
+		UserId:         cmd.UserId,
+		ResolvedAt:     now.Unix(),
+	}); err != nil {
+		return err
+	}
+
+	resolved := &events.CorrelationResolved{
+		Timestamp: now,
+		UID:       correlation.UID,
+		SourceUID: correlation.SourceUID,
+		OrgID:     cmd.OrgId,
+		UserID:    cmd.UserId,
+	}
+	if correlation.TargetUID != nil {
+		resolved.TargetUID = *correlation.TargetUID
+	}
+	return s.bus.Publish(ctx, resolved)
+}
+
+// GetCorrelationVersions returns the versions of the correlation, latest first,
+// or ErrCorrelationNotFound when the correlation isn't visible to the user.
+func (s CorrelationsService) GetCorrelationVersions(ctx context.Context, query GetCorrelationVersionsQuery) ([]CorrelationVersion, error) {
+	return s.getCorrelationVersions(ctx, query)
+}
+
+// RestoreCorrelationVersion restores the label, description and config of a
+// previous version of the correlation, recorded as a new version.
+func (s CorrelationsService) RestoreCorrelationVersion(ctx context.Context, cmd RestoreCorrelationVersionCommand) (Correlation, error) {
+	correlation, err := s.restoreCorrelationVersion(ctx, cmd)
+	observeChange(opUpdate, err)
+	return correlation, err
+}
+
+// GetCorrelationUsage returns the daily usage of the correlation, oldest day first.
+// Recent resolutions are only counted once aggregated by the background job.
+func (s CorrelationsService) GetCorrelationUsage(ctx context.Context, cmd GetCorrelationUsageQuery) ([]CorrelationUsage, error) {
+	return s.getCorrelationUsage(ctx, cmd)
 }
 
 func (s CorrelationsService) DeleteCorrelationsBySourceUID(ctx context.Context, cmd DeleteCorrelationsBySourceUIDCommand) error {
@@ -76,7 +294,17 @@ func (s CorrelationsService) DeleteCorrelationsByTargetUID(ctx context.Context,
 	return s.deleteCorrelationsByTargetUID(ctx, cmd)
 }
 
+func (s CorrelationsService) UpdateCorrelationsSourceUID(ctx context.Context, cmd UpdateCorrelationsSourceUIDCommand) error {
+	return s.updateCorrelationsSourceUID(ctx, cmd)
+}
+
+func (s CorrelationsService) UpdateCorrelationsTargetUID(ctx context.Context, cmd UpdateCorrelationsTargetUIDCommand) error {
+	return s.updateCorrelationsTargetUID(ctx, cmd)
+}
+
 func (s CorrelationsService) handleDatasourceDeletion(ctx context.Context, event *events.DataSourceDeleted) error {
+	defer s.invalidateLists()
+
 	return s.SQLStore.InTransaction(ctx, func(ctx context.Context) error {
 		if err := s.deleteCorrelationsBySourceUID(ctx, DeleteCorrelationsBySourceUIDCommand{
 			SourceUID: event.UID,
@@ -93,3 +321,30 @@ func (s CorrelationsService) handleDatasourceDeletion(ctx context.Context, event
 		return nil
 	})
 }
+
+func (s CorrelationsService) handleDatasourceUIDChange(ctx context.Context, event *events.DataSourceUIDChanged) error {
+	defer s.invalidateLists()
+
+	return s.SQLStore.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.updateCorrelationsSourceUID(ctx, UpdateCorrelationsSourceUIDCommand{
+			OrgId:       event.OrgID,
+			PreviousUID: event.PreviousUID,
+			SourceUID:   event.UID,
+		}); err != nil {
+			return err
+		}
+
+		return s.updateCorrelationsTargetUID(ctx, UpdateCorrelationsTargetUIDCommand{
+			OrgId:       event.OrgID,
+			PreviousUID: event.PreviousUID,
+			TargetUID:   event.UID,
+		})
+	})
+}
+
+func (s CorrelationsService) handleDashboardDeletion(ctx context.Context, event *events.DashboardDeleted) error {
+	return s.deleteCorrelationsByDashboardUID(ctx, DeleteCorrelationsByDashboardUIDCommand{
+		OrgId:        event.OrgID,
+		DashboardUID: event.UID,
+	})
+}
@@ -0,0 +1,2624 @@
+package correlations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/jobs/jobstest"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/annotations/annotationstest"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/orgpolicy"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCorrelationsTest stores a loki source and a tempo target data source in
+// org 1, along with the extra data sources, and returns a correlations service
+// using them. Tests override the fields of the service they depend on.
+func setupCorrelationsTest(t *testing.T, extra ...*datasources.DataSource) (*sqlstore.SQLStore, *datasources.DataSource, *datasources.DataSource, *CorrelationsService) {
+	t.Helper()
+	sqlStore := sqlstore.InitTestDB(t)
+
+	source := &datasources.DataSource{OrgId: 1, Uid: "source-uid", Name: "source", Type: "loki"}
+	target := &datasources.DataSource{OrgId: 1, Uid: "target-uid", Name: "target", Type: "tempo"}
+	dataSources := append([]*datasources.DataSource{source, target}, extra...)
+	err := sqlStore.WithDbSession(context.Background(), func(session *sqlstore.DBSession) error {
+		for _, ds := range dataSources {
+			ds.Created, ds.Updated = time.Now(), time.Now()
+			if _, err := session.Insert(ds); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	return sqlStore, source, target, &CorrelationsService{
+		SQLStore:          sqlStore,
+		QuotaService:      quotatest.NewQuotaServiceFake(),
+		Cfg:               setting.NewCfg(),
+		Annotations:       annotationstest.NewFakeAnnotationsRepo(),
+		DataSourceService: &fakeDatasources.FakeDataSourceService{DataSources: dataSources},
+		OrgPolicies:       &orgpolicy.OrgPolicyService{SQLStore: sqlStore},
+		log:               log.NewNopLogger(),
+	}
+}
+
+func TestIntegrationRecordResolution(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore, source, target, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	targetUID := target.Uid
+	err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		_, err := session.Insert(&Correlation{UID: "correlation-uid", SourceUID: source.Uid, TargetUID: &targetUID, Config: CorrelationConfig{Type: ConfigTypeQuery}})
+		return err
+	})
+	require.NoError(t, err)
+
+	eventBus := bus.ProvideBus(tracing.InitializeTracerForTest())
+	var resolved []*events.CorrelationResolved
+	eventBus.AddEventListener(func(_ context.Context, e *events.CorrelationResolved) error {
+		resolved = append(resolved, e)
+		return nil
+	})
+
+	s.bus = eventBus
+
+	t.Run("publishes the resolution", func(t *testing.T) {
+		err := s.RecordResolution(ctx, RecordCorrelationResolutionCommand{UID: "correlation-uid", SourceUID: source.Uid, OrgId: 1, UserId: 2})
+		require.NoError(t, err)
+
+		require.Len(t, resolved, 1)
+		require.Equal(t, "correlation-uid", resolved[0].UID)
+		require.Equal(t, "source-uid", resolved[0].SourceUID)
+		require.Equal(t, "target-uid", resolved[0].TargetUID)
+		require.Equal(t, int64(2), resolved[0].UserID)
+	})
+
+	t.Run("fails for unknown correlations", func(t *testing.T) {
+		err := s.RecordResolution(ctx, RecordCorrelationResolutionCommand{UID: "unknown", SourceUID: source.Uid, OrgId: 1})
+		require.ErrorIs(t, err, ErrCorrelationNotFound)
+		require.Len(t, resolved, 1)
+	})
+}
+
+func TestIntegrationTransformations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore, source, target, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	transformations := Transformations{
+		{Type: TransformationLogfmt, Field: "traceId"},
+		{Type: TransformationMapping, Mapping: map[string]string{"error": "level=error"}},
+	}
+	created, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+		SourceUID: source.Uid,
+		OrgId:     1,
+		TargetUID: &target.Uid,
+		Config:    CorrelationConfig{Field: CorrelationFields{"message"}, Type: ConfigTypeQuery, Transformations: transformations},
+	})
+	require.NoError(t, err)
+
+	t.Run("stores the transformations", func(t *testing.T) {
+		correlation, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: created.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, transformations, correlation.Config.Transformations)
+	})
+
+	t.Run("updates the transformations", func(t *testing.T) {
+		updated := Transformations{{Type: TransformationRegex, Expression: `traceId=(\w+)`}}
+		_, err := s.UpdateCorrelation(ctx, UpdateCorrelationCommand{
+			UID:       created.UID,
+			SourceUID: source.Uid,
+			OrgId:     1,
+			Config:    &CorrelationConfigUpdateDTO{Transformations: &updated},
+		})
+		require.NoError(t, err)
+
+		correlation, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: created.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, updated, correlation.Config.Transformations)
+		require.Equal(t, CorrelationFields{"message"}, correlation.Config.Field)
+	})
+
+	t.Run("rejects invalid transformations", func(t *testing.T) {
+		invalid := Transformations{{Type: TransformationRegex, Expression: "(unclosed"}}
+		_, err := s.UpdateCorrelation(ctx, UpdateCorrelationCommand{
+			UID:       created.UID,
+			SourceUID: source.Uid,
+			OrgId:     1,
+			Config:    &CorrelationConfigUpdateDTO{Transformations: &invalid},
+		})
+		require.ErrorIs(t, err, ErrInvalidTransformation)
+	})
+
+	t.Run("updates the matchers", func(t *testing.T) {
+		matchers := CorrelationMatchers{{Field: "service", Operator: MatcherEquals, Value: "checkout"}}
+		_, err := s.UpdateCorrelation(ctx, UpdateCorrelationCommand{
+			UID:       created.UID,
+			SourceUID: source.Uid,
+			OrgId:     1,
+			Config:    &CorrelationConfigUpdateDTO{Matchers: &matchers},
+		})
+		require.NoError(t, err)
+
+		correlation, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: created.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, matchers, correlation.Config.Matchers)
+	})
+
+	t.Run("rejects invalid matchers", func(t *testing.T) {
+		invalid := CorrelationMatchers{{Field: "service", Operator: "contains", Value: "checkout"}}
+		_, err := s.UpdateCorrelation(ctx, UpdateCorrelationCommand{
+			UID:       created.UID,
+			SourceUID: source.Uid,
+			OrgId:     1,
+			Config:    &CorrelationConfigUpdateDTO{Matchers: &invalid},
+		})
+		require.ErrorIs(t, err, ErrInvalidMatcher)
+	})
+
+	t.Run("stores several fields", func(t *testing.T) {
+		fields := CorrelationFields{"job", "namespace"}
+		_, err := s.UpdateCorrelation(ctx, UpdateCorrelationCommand{
+			UID:       created.UID,
+			SourceUID: source.Uid,
+			OrgId:     1,
+			Config:    &CorrelationConfigUpdateDTO{Field: &fields},
+		})
+		require.NoError(t, err)
+
+		correlation, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: created.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, fields, correlation.Config.Field)
+	})
+
+	t.Run("reads the single field of existing correlations", func(t *testing.T) {
+		err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+			_, err := session.Exec("UPDATE correlation SET config = ? WHERE uid = ?", `{"type":"query","field":"message","target":{}}`, created.UID)
+			return err
+		})
+		require.NoError(t, err)
+
+		correlation, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: created.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, CorrelationFields{"message"}, correlation.Config.Field)
+	})
+}
+
+func TestIntegrationJobs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore, source, target, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	targetUID, deletedUID := target.Uid, "deleted-uid"
+	err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		_, err := session.Insert(
+			&Correlation{UID: "correlation-uid", SourceUID: source.Uid, TargetUID: &targetUID, Config: CorrelationConfig{Type: ConfigTypeQuery}},
+			&Correlation{UID: "external-uid", SourceUID: source.Uid, Config: CorrelationConfig{Type: ConfigTypeExternal}},
+			&Correlation{UID: "orphaned-target", SourceUID: source.Uid, TargetUID: &deletedUID, Config: CorrelationConfig{Type: ConfigTypeQuery}},
+			&Correlation{UID: "orphaned-source", SourceUID: deletedUID, TargetUID: &targetUID, Config: CorrelationConfig{Type: ConfigTypeQuery}},
+		)
+		return err
+	})
+	require.NoError(t, err)
+
+	s.bus = bus.ProvideBus(tracing.InitializeTracerForTest())
+
+	t.Run("registers the jobs", func(t *testing.T) {
+		scheduler := jobstest.NewFakeScheduler()
+		s.registerJobs(scheduler)
+		require.Len(t, scheduler.Jobs, 2)
+		for _, job := range scheduler.Jobs {
+			require.False(t, job.AllInstances)
+		}
+	})
+
+	t.Run("deletes the correlations of deleted data sources", func(t *testing.T) {
+		require.NoError(t, s.cleanupOrphanedCorrelations(ctx))
+
+		var uids []string
+		err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+			return session.Table("correlation").Cols("uid").Asc("uid").Find(&uids)
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"correlation-uid", "external-uid"}, uids)
+	})
+
+	t.Run("aggregates the resolutions into the daily usage", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			require.NoError(t, s.RecordResolution(ctx, RecordCorrelationResolutionCommand{UID: "correlation-uid", SourceUID: source.Uid, OrgId: 1, UserId: 2}))
+		}
+		require.NoError(t, s.RecordResolution(ctx, RecordCorrelationResolutionCommand{UID: "external-uid", SourceUID: source.Uid, OrgId: 1, UserId: 2}))
+
+		aggregated, err := s.aggregateResolutions(ctx, 2)
+		require.NoError(t, err)
+		require.Equal(t, 2, aggregated)
+		require.NoError(t, s.aggregateUsage(ctx))
+
+		today := time.Now().UTC().Format("2006-01-02")
+		usage, err := s.GetCorrelationUsage(ctx, GetCorrelationUsageQuery{UID: "correlation-uid", SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, usage, 1)
+		require.Equal(t, today, usage[0].Day)
+		require.Equal(t, int64(3), usage[0].Resolutions)
+
+		usage, err = s.GetCorrelationUsage(ctx, GetCorrelationUsageQuery{UID: "external-uid", SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, usage, 1)
+		require.Equal(t, int64(1), usage[0].Resolutions)
+
+		aggregated, err = s.aggregateResolutions(ctx, 2)
+		require.NoError(t, err)
+		require.Zero(t, aggregated)
+	})
+
+	t.Run("sums up the usage of every correlation", func(t *testing.T) {
+		before := time.Now().Unix()
+		require.NoError(t, s.RecordResolution(ctx, RecordCorrelationResolutionCommand{UID: "correlation-uid", SourceUID: source.Uid, OrgId: 1, UserId: 2}))
+		require.NoError(t, s.aggregateUsage(ctx))
+
+		correlations, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 1, WithUsage: true})
+		require.NoError(t, err)
+		require.Len(t, correlations, 2)
+		for _, correlation := range correlations {
+			require.NotNil(t, correlation.Usage)
+			switch correlation.UID {
+			case "correlation-uid":
+				require.Equal(t, int64(4), correlation.Usage.Resolutions)
+				require.GreaterOrEqual(t, correlation.Usage.LastResolvedAt, before)
+			case "external-uid":
+				require.Equal(t, int64(1), correlation.Usage.Resolutions)
+				require.NotZero(t, correlation.Usage.LastResolvedAt)
+			}
+		}
+
+		correlations, err = s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 1})
+		require.NoError(t, err)
+		require.Nil(t, correlations[0].Usage)
+	})
+
+	t.Run("fails to get the usage of unknown correlations", func(t *testing.T) {
+		_, err := s.GetCorrelationUsage(ctx, GetCorrelationUsageQuery{UID: "orphaned-target", SourceUID: source.Uid, OrgId: 1})
+		require.ErrorIs(t, err, ErrCorrelationNotFound)
+	})
+}
+
+func TestIntegrationProvisionedCorrelations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, source, _, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	created, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+		UID:         "provisioned-uid",
+		SourceUID:   source.Uid,
+		OrgId:       1,
+		Label:       "Tickets",
+		Config:      CorrelationConfig{Field: CorrelationFields{"ticket"}, Type: ConfigTypeExternal, Target: map[string]interface{}{"url": "https://tickets.example.com/${value}"}},
+		Provisioned: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "provisioned-uid", created.UID)
+
+	label := "Updated"
+	update := UpdateCorrelationCommand{UID: created.UID, SourceUID: source.Uid, OrgId: 1, Label: &label}
+	remove := DeleteCorrelationCommand{UID: created.UID, SourceUID: source.Uid, OrgId: 1}
+
+	t.Run("can't be edited outside of provisioning", func(t *testing.T) {
+		_, err := s.UpdateCorrelation(ctx, update)
+		require.ErrorIs(t, err, ErrCorrelationReadOnly)
+
+		require.ErrorIs(t, s.DeleteCorrelation(ctx, remove), ErrCorrelationReadOnly)
+	})
+
+	t.Run("can be edited by provisioning", func(t *testing.T) {
+		update.SkipReadOnlyCheck = true
+		updated, err := s.UpdateCorrelation(ctx, update)
+		require.NoError(t, err)
+		require.Equal(t, "Updated", updated.Label)
+		require.True(t, updated.Provisioned)
+
+		remove.SkipReadOnlyCheck = true
+		require.NoError(t, s.DeleteCorrelation(ctx, remove))
+	})
+}
+
+func TestIntegrationClientSpecifiedUID(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	otherSource := &datasources.DataSource{OrgId: 1, Uid: "other-source-uid", Name: "other source", Type: "loki"}
+	_, source, _, s := setupCorrelationsTest(t, otherSource)
+	ctx := context.Background()
+
+	cmd := CreateCorrelationCommand{
+		UID:       "tickets",
+		SourceUID: source.Uid,
+		OrgId:     1,
+		Label:     "Tickets",
+		Config:    CorrelationConfig{Field: CorrelationFields{"ticket"}, Type: ConfigTypeExternal, Target: map[string]interface{}{"url": "https://tickets.example.com/${value}"}},
+	}
+
+	t.Run("creates the correlation with the UID", func(t *testing.T) {
+		created, err := s.CreateCorrelation(ctx, cmd)
+		require.NoError(t, err)
+		require.Equal(t, "tickets", created.UID)
+
+		found, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: "tickets", SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, "Tickets", found.Label)
+	})
+
+	t.Run("rejects a UID already used by the source data source", func(t *testing.T) {
+		_, err := s.CreateCorrelation(ctx, cmd)
+		require.ErrorIs(t, err, ErrCorrelationUIDExists)
+	})
+
+	t.Run("accepts the same UID for another source data source", func(t *testing.T) {
+		other := cmd
+		other.SourceUID = otherSource.Uid
+		created, err := s.CreateCorrelation(ctx, other)
+		require.NoError(t, err)
+		require.Equal(t, "tickets", created.UID)
+	})
+
+	t.Run("reads the UID from the request body", func(t *testing.T) {
+		var decoded CreateCorrelationCommand
+		require.NoError(t, json.Unmarshal([]byte(`{"uid": "tickets", "label": "Tickets"}`), &decoded))
+		require.Equal(t, "tickets", decoded.UID)
+	})
+}
+
+func TestIntegrationUpsertCorrelation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	otherTarget := &datasources.DataSource{OrgId: 1, Uid: "other-target-uid", Name: "other target", Type: "tempo"}
+	_, source, target, s := setupCorrelationsTest(t, otherTarget)
+	ctx := context.Background()
+
+	targetUID := target.Uid
+	cmd := CreateCorrelationCommand{
+		UID:         "logs-to-traces",
+		SourceUID:   source.Uid,
+		OrgId:       1,
+		TargetUID:   &targetUID,
+		Label:       "logs to traces",
+		Description: "from the app logs",
+		Tags:        []string{"app"},
+		Config:      CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery, Target: map[string]interface{}{"query": "${traceId}"}},
+	}
+	versions := func(t *testing.T) int {
+		t.Helper()
+		versions, err := s.GetCorrelationVersions(ctx, GetCorrelationVersionsQuery{UID: cmd.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		return len(versions)
+	}
+
+	t.Run("creates the correlation if it's missing", func(t *testing.T) {
+		correlation, created, err := s.UpsertCorrelation(ctx, cmd)
+		require.NoError(t, err)
+		require.True(t, created)
+		require.Equal(t, cmd.UID, correlation.UID)
+
+		found, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: cmd.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, "logs to traces", found.Label)
+		require.Equal(t, []string{"app"}, found.Tags)
+	})
+
+	t.Run("leaves the correlation as is if nothing changed", func(t *testing.T) {
+		before := versions(t)
+		correlation, created, err := s.UpsertCorrelation(ctx, cmd)
+		require.NoError(t, err)
+		require.False(t, created)
+		require.Equal(t, "logs to traces", correlation.Label)
+		require.Equal(t, before, versions(t))
+	})
+
+	t.Run("replaces all the fields of the correlation", func(t *testing.T) {
+		before := versions(t)
+		otherTargetUID := otherTarget.Uid
+		replacement := cmd
+		replacement.TargetUID = &otherTargetUID
+		replacement.Label = "logs to other traces"
+		replacement.Description = ""
+		replacement.Tags = nil
+		replacement.Config = CorrelationConfig{Field: CorrelationFields{"spanId"}, Type: ConfigTypeQuery, Target: map[string]interface{}{"query": "${spanId}"}}
+
+		correlation, created, err := s.UpsertCorrelation(ctx, replacement)
+		require.NoError(t, err)
+		require.False(t, created)
+		require.Equal(t, before+1, versions(t))
+
+		found, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: cmd.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, correlation.Label, found.Label)
+		require.Equal(t, otherTarget.Uid, *found.TargetUID)
+		require.Equal(t, "logs to other traces", found.Label)
+		require.Empty(t, found.Description)
+		require.Empty(t, found.Tags)
+		require.Equal(t, CorrelationFields{"spanId"}, found.Config.Field)
+	})
+
+	t.Run("removes the target of the correlation", func(t *testing.T) {
+		external := cmd
+		external.TargetUID = nil
+		external.Config = CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeExternal, Target: map[string]interface{}{"url": "https://traces.example.com/${traceId}"}}
+		_, _, err := s.UpsertCorrelation(ctx, external)
+		require.NoError(t, err)
+
+		found, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: cmd.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Nil(t, found.TargetUID)
+		require.Equal(t, ConfigTypeExternal, found.Config.Type)
+	})
+
+	t.Run("rejects a replacement with a missing target data source", func(t *testing.T) {
+		missingUID := "missing-uid"
+		replacement := cmd
+		replacement.TargetUID = &missingUID
+		_, created, err := s.UpsertCorrelation(ctx, replacement)
+		require.ErrorIs(t, err, ErrTargetDataSourceDoesNotExists)
+		require.False(t, created)
+	})
+
+	t.Run("doesn't replace provisioned correlations", func(t *testing.T) {
+		provisioned := cmd
+		provisioned.UID = "provisioned"
+		provisioned.Provisioned = true
+		_, err := s.CreateCorrelation(ctx, provisioned)
+		require.NoError(t, err)
+
+		replacement := provisioned
+		replacement.Provisioned = false
+		replacement.Label = "replaced"
+		_, _, err = s.UpsertCorrelation(ctx, replacement)
+		require.ErrorIs(t, err, ErrCorrelationReadOnly)
+	})
+}
+
+func TestIntegrationDuplicateCorrelations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	otherTarget := &datasources.DataSource{OrgId: 1, Uid: "other-target-uid", Name: "other target", Type: "tempo"}
+	_, source, target, s := setupCorrelationsTest(t, otherTarget)
+	ctx := context.Background()
+
+	s.Cfg.Correlations.RejectDuplicates = true
+	targetUID := target.Uid
+	cmd := CreateCorrelationCommand{
+		SourceUID: source.Uid,
+		OrgId:     1,
+		TargetUID: &targetUID,
+		Label:     "logs to traces",
+		Config: CorrelationConfig{
+			Field:  CorrelationFields{"traceId", "app"},
+			Type:   ConfigTypeQuery,
+			Target: map[string]interface{}{"query": "${traceId}", "queryType": "traceql", "limit": 20},
+		},
+	}
+	existing, err := s.CreateCorrelation(ctx, cmd)
+	require.NoError(t, err)
+
+	t.Run("rejects a correlation with the same source, target, field and target query", func(t *testing.T) {
+		duplicate := cmd
+		duplicate.Label = "another label"
+		duplicate.Config.Field = CorrelationFields{"app", "traceId"}
+		duplicate.Config.Target = map[string]interface{}{"limit": 20, "queryType": "traceql", "query": "${traceId}"}
+		_, err := s.CreateCorrelation(ctx, duplicate)
+		require.ErrorIs(t, err, ErrCorrelationDuplicate)
+		var grafanaErr errutil.Error
+		require.ErrorAs(t, err, &grafanaErr)
+		require.Equal(t, http.StatusConflict, grafanaErr.Public().StatusCode)
+		require.Equal(t, existing.UID, grafanaErr.Public().Extra["uid"])
+	})
+
+	t.Run("accepts a correlation with another target, field or target query", func(t *testing.T) {
+		otherTargetUID := otherTarget.Uid
+		withOtherTarget := cmd
+		withOtherTarget.TargetUID = &otherTargetUID
+		withOtherField := cmd
+		withOtherField.Config.Field = CorrelationFields{"traceId"}
+		withOtherQuery := cmd
+		withOtherQuery.Config.Target = map[string]interface{}{"query": "${traceId}", "queryType": "traceql", "limit": 50}
+
+		for _, other := range []CreateCorrelationCommand{withOtherTarget, withOtherField, withOtherQuery} {
+			_, err := s.CreateCorrelation(ctx, other)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("creates a duplicate when forced", func(t *testing.T) {
+		forced := cmd
+		forced.Force = true
+		_, err := s.CreateCorrelation(ctx, forced)
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects duplicates among the correlations of a data source type", func(t *testing.T) {
+		byType := cmd
+		byType.SourceUID = ""
+		byType.SourceType = "loki"
+		_, err := s.CreateCorrelation(ctx, byType)
+		require.NoError(t, err)
+
+		_, err = s.CreateCorrelation(ctx, byType)
+		require.ErrorIs(t, err, ErrCorrelationDuplicate)
+	})
+
+	t.Run("rejects duplicates in a batch", func(t *testing.T) {
+		item := BulkCreateCorrelationsItem{SourceUID: source.Uid, CreateCorrelationCommand: cmd}
+		item.Config.Field = CorrelationFields{"traceId", "spanId"}
+		_, err := s.BulkCreateCorrelations(ctx, BulkCreateCorrelationsCommand{OrgId: 1, Correlations: []BulkCreateCorrelationsItem{item, item}})
+		var bulkErr BulkCorrelationsError
+		require.ErrorAs(t, err, &bulkErr)
+		require.Len(t, bulkErr.Errors, 1)
+		require.Equal(t, 1, bulkErr.Errors[0].Index)
+		require.Equal(t, http.StatusConflict, bulkErr.StatusCode())
+
+		created, err := s.BulkCreateCorrelations(ctx, BulkCreateCorrelationsCommand{OrgId: 1, Force: true, Correlations: []BulkCreateCorrelationsItem{item, item}})
+		require.NoError(t, err)
+		require.Len(t, created, 2)
+	})
+
+	t.Run("accepts duplicates unless they're rejected", func(t *testing.T) {
+		s.Cfg = setting.NewCfg()
+		_, err := s.CreateCorrelation(ctx, cmd)
+		require.NoError(t, err)
+	})
+}
+
+func TestIntegrationGetCorrelationsByTargetUID(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	other := &datasources.DataSource{OrgId: 1, Uid: "other-uid", Name: "other", Type: "tempo"}
+	sqlStore, source, target, s := setupCorrelationsTest(t, other)
+	ctx := context.Background()
+
+	targetUID, otherUID := target.Uid, other.Uid
+	err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		_, err := session.Insert(
+			&Correlation{UID: "to-target", SourceUID: source.Uid, TargetUID: &targetUID, Config: CorrelationConfig{Type: ConfigTypeQuery}},
+			&Correlation{UID: "to-itself", SourceUID: target.Uid, TargetUID: &targetUID, Config: CorrelationConfig{Type: ConfigTypeQuery}},
+			&Correlation{UID: "to-other", SourceUID: source.Uid, TargetUID: &otherUID, Config: CorrelationConfig{Type: ConfigTypeQuery}},
+			&Correlation{UID: "external", SourceUID: source.Uid, Config: CorrelationConfig{Type: ConfigTypeExternal}},
+			&Correlation{UID: "orphaned-source", SourceUID: "deleted-uid", TargetUID: &targetUID, Config: CorrelationConfig{Type: ConfigTypeQuery}},
+		)
+		return err
+	})
+	require.NoError(t, err)
+
+	t.Run("returns the correlations pointing at the data source", func(t *testing.T) {
+		correlations, err := s.GetCorrelationsByTargetUID(ctx, GetCorrelationsByTargetUIDQuery{TargetUID: target.Uid, OrgId: 1})
+		require.NoError(t, err)
+
+		uids := make([]string, 0, len(correlations))
+		for _, c := range correlations {
+			uids = append(uids, c.UID)
+		}
+		require.ElementsMatch(t, []string{"to-target", "to-itself"}, uids)
+	})
+
+	t.Run("returns no correlations if none points at the data source", func(t *testing.T) {
+		correlations, err := s.GetCorrelationsByTargetUID(ctx, GetCorrelationsByTargetUIDQuery{TargetUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Empty(t, correlations)
+	})
+
+	t.Run("fails if the data source does not exist", func(t *testing.T) {
+		_, err := s.GetCorrelationsByTargetUID(ctx, GetCorrelationsByTargetUIDQuery{TargetUID: "deleted-uid", OrgId: 1})
+		require.ErrorIs(t, err, ErrTargetDataSourceDoesNotExists)
+	})
+}
+
+func TestIntegrationCorrelationOrder(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, source, target, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	for _, c := range []struct {
+		uid   string
+		order int
+	}{{"second", 2}, {"first", 1}, {"unordered", 0}, {"third", 3}} {
+		_, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+			UID:       c.uid,
+			SourceUID: source.Uid,
+			TargetUID: &target.Uid,
+			OrgId:     1,
+			Label:     c.uid,
+			Config:    CorrelationConfig{Type: ConfigTypeQuery},
+			Order:     c.order,
+		})
+		require.NoError(t, err)
+	}
+	uids := func(correlations []Correlation) []string {
+		uids := make([]string, 0, len(correlations))
+		for _, c := range correlations {
+			uids = append(uids, c.UID)
+		}
+		return uids
+	}
+
+	t.Run("lists the correlations in ascending order", func(t *testing.T) {
+		bySource, err := s.GetCorrelationsBySourceUID(ctx, GetCorrelationsBySourceUIDQuery{SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, []string{"unordered", "first", "second", "third"}, uids(bySource))
+
+		byTarget, err := s.GetCorrelationsByTargetUID(ctx, GetCorrelationsByTargetUIDQuery{TargetUID: target.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, []string{"unordered", "first", "second", "third"}, uids(byTarget))
+
+		all, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, []string{"unordered", "first", "second", "third"}, uids(all))
+	})
+
+	t.Run("updates the order", func(t *testing.T) {
+		order := 4
+		updated, err := s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: "unordered", SourceUID: source.Uid, OrgId: 1, Order: &order})
+		require.NoError(t, err)
+		require.Equal(t, 4, updated.Order)
+		require.Equal(t, "unordered", updated.Label)
+
+		bySource, err := s.GetCorrelationsBySourceUID(ctx, GetCorrelationsBySourceUIDQuery{SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, []string{"first", "second", "third", "unordered"}, uids(bySource))
+	})
+
+	t.Run("resets the order", func(t *testing.T) {
+		order := 0
+		updated, err := s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: "third", SourceUID: source.Uid, OrgId: 1, Order: &order})
+		require.NoError(t, err)
+		require.Zero(t, updated.Order)
+
+		correlation, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: "third", SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Zero(t, correlation.Order)
+	})
+}
+
+func TestIntegrationCorrelationTags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore, source, _, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	for _, c := range []struct {
+		uid  string
+		tags []string
+	}{{"billing", []string{"observability", "billing", "billing"}}, {"observability", []string{"observability"}}, {"untagged", nil}} {
+		_, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+			UID:       c.uid,
+			SourceUID: source.Uid,
+			OrgId:     1,
+			Label:     c.uid,
+			Tags:      c.tags,
+			Config:    CorrelationConfig{Field: CorrelationFields{"ticket"}, Type: ConfigTypeExternal, Target: map[string]interface{}{"url": "https://tickets.example.com/${ticket}"}},
+		})
+		require.NoError(t, err)
+	}
+	uids := func(correlations []Correlation) []string {
+		uids := make([]string, 0, len(correlations))
+		for _, c := range correlations {
+			uids = append(uids, c.UID)
+		}
+		return uids
+	}
+
+	t.Run("returns the sorted tags without duplicates", func(t *testing.T) {
+		correlation, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: "billing", SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, []string{"billing", "observability"}, correlation.Tags)
+
+		correlation, err = s.GetCorrelation(ctx, GetCorrelationQuery{UID: "untagged", SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Nil(t, correlation.Tags)
+	})
+
+	t.Run("filters the lists by all the tags", func(t *testing.T) {
+		all, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 1, Tags: []string{"observability"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"billing", "observability"}, uids(all))
+
+		all, err = s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 1, Tags: []string{"observability", "billing"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"billing"}, uids(all))
+
+		bySource, err := s.GetCorrelationsBySourceUID(ctx, GetCorrelationsBySourceUIDQuery{SourceUID: source.Uid, OrgId: 1, Tags: []string{"billing"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"billing"}, uids(bySource))
+		require.Equal(t, []string{"billing", "observability"}, bySource[0].Tags)
+
+		bySource, err = s.GetCorrelationsBySourceUID(ctx, GetCorrelationsBySourceUIDQuery{SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, bySource, 3)
+
+		all, err = s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 2, Tags: []string{"billing"}})
+		require.NoError(t, err)
+		require.Empty(t, all)
+	})
+
+	t.Run("replaces the tags on update", func(t *testing.T) {
+		tags := []string{"billing"}
+		updated, err := s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: "observability", SourceUID: source.Uid, OrgId: 1, Tags: &tags})
+		require.NoError(t, err)
+		require.Equal(t, tags, updated.Tags)
+
+		label := "renamed"
+		updated, err = s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: "observability", SourceUID: source.Uid, OrgId: 1, Label: &label})
+		require.NoError(t, err)
+		require.Equal(t, tags, updated.Tags)
+
+		invalid := []string{""}
+		_, err = s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: "observability", SourceUID: source.Uid, OrgId: 1, Tags: &invalid})
+		require.ErrorIs(t, err, ErrInvalidTag)
+
+		all, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 1, Tags: []string{"billing"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"billing", "observability"}, uids(all))
+	})
+
+	t.Run("deletes the tags with the correlation", func(t *testing.T) {
+		err := s.DeleteCorrelation(ctx, DeleteCorrelationCommand{UID: "billing", SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+
+		err = sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+			count, err := session.Count(&CorrelationTag{CorrelationUID: "billing"})
+			require.Zero(t, count)
+			return err
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestIntegrationSearchCorrelations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	otherOrgSource := &datasources.DataSource{OrgId: 2, Uid: "other-source-uid", Name: "source", Type: "loki"}
+	_, source, target, s := setupCorrelationsTest(t, otherOrgSource)
+	ctx := context.Background()
+
+	for _, c := range []struct {
+		uid         string
+		sourceUID   string
+		orgID       int64
+		label       string
+		description string
+		expr        string
+	}{
+		{"checkout-errors", source.Uid, 1, "Checkout errors", "Errors of the checkout service", `rate(errors{job="checkout"}[5m])`},
+		{"checkout", source.Uid, 1, "checkout", "", `up{job="api"}`},
+		{"latency", source.Uid, 1, "Latency", "", `histogram_quantile(0.99, latency{job="checkout"})`},
+		{"billing", source.Uid, 1, "Billing", "Invoices of the checkout", `up{job="billing"}`},
+		{"unrelated", source.Uid, 1, "Unrelated", "", `up{job="search"}`},
+		{"other-org", otherOrgSource.Uid, 2, "Checkout", "", `up{job="checkout"}`},
+	} {
+		targetUID := target.Uid
+		_, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+			UID:         c.uid,
+			SourceUID:   c.sourceUID,
+			OrgId:       c.orgID,
+			TargetUID:   &targetUID,
+			Label:       c.label,
+			Description: c.description,
+			Config:      CorrelationConfig{Field: CorrelationFields{"job"}, Type: ConfigTypeQuery, Target: map[string]interface{}{"expr": c.expr}},
+		})
+		require.NoError(t, err)
+	}
+	uids := func(results []CorrelationSearchResult) []string {
+		uids := make([]string, 0, len(results))
+		for _, r := range results {
+			uids = append(uids, r.UID)
+		}
+		return uids
+	}
+
+	t.Run("ranks the correlations of the org by relevance", func(t *testing.T) {
+		results, err := s.SearchCorrelations(ctx, SearchCorrelationsQuery{OrgId: 1, Query: "Checkout"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"checkout", "checkout-errors", "latency", "billing"}, uids(results))
+		require.Equal(t, scoreLabelExact, results[0].Score)
+		require.Equal(t, []string{"label"}, results[0].Matches)
+		require.Equal(t, []string{"label", "target", "description"}, results[1].Matches)
+		require.Equal(t, []string{"target"}, results[2].Matches)
+		require.Equal(t, []string{"description"}, results[3].Matches)
+	})
+
+	t.Run("finds the target queries containing quotes", func(t *testing.T) {
+		results, err := s.SearchCorrelations(ctx, SearchCorrelationsQuery{OrgId: 1, Query: `job="checkout"`})
+		require.NoError(t, err)
+		require.Equal(t, []string{"checkout-errors", "latency"}, uids(results))
+	})
+
+	t.Run("doesn't match the other fields of the config", func(t *testing.T) {
+		results, err := s.SearchCorrelations(ctx, SearchCorrelationsQuery{OrgId: 1, Query: "query"})
+		require.NoError(t, err)
+		require.Empty(t, results)
+	})
+
+	t.Run("limits the results", func(t *testing.T) {
+		results, err := s.SearchCorrelations(ctx, SearchCorrelationsQuery{OrgId: 1, Query: "checkout", Limit: 2})
+		require.NoError(t, err)
+		require.Equal(t, []string{"checkout", "checkout-errors"}, uids(results))
+	})
+}
+
+func TestIntegrationDashboardCorrelations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore, source, _, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		_, err := session.Insert(
+			&models.Dashboard{Uid: "checkout", OrgId: 1, Title: "Checkout", Slug: "checkout", Data: simplejson.New(), Created: time.Now(), Updated: time.Now()},
+			&models.Dashboard{Uid: "other-org", OrgId: 2, Title: "Other org", Slug: "other-org", Data: simplejson.New(), Created: time.Now(), Updated: time.Now()},
+		)
+		return err
+	})
+	require.NoError(t, err)
+
+	dashboardConfig := func(uid string) CorrelationConfig {
+		return CorrelationConfig{
+			Field:  CorrelationFields{"service"},
+			Type:   ConfigTypeDashboard,
+			Target: map[string]interface{}{"dashboardUID": uid, "panelId": float64(2), "variables": map[string]interface{}{"service": "${service}"}},
+		}
+	}
+
+	created, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{SourceUID: source.Uid, OrgId: 1, Label: "checkout", Config: dashboardConfig("checkout")})
+	require.NoError(t, err)
+
+	t.Run("fails if the dashboard does not exist in the organization", func(t *testing.T) {
+		_, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{SourceUID: source.Uid, OrgId: 1, Config: dashboardConfig("other-org")})
+		require.ErrorIs(t, err, ErrTargetDashboardDoesNotExists)
+
+		config := dashboardConfig("unknown")
+		_, err = s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: created.UID, SourceUID: source.Uid, OrgId: 1, Config: &CorrelationConfigUpdateDTO{Target: &config.Target}})
+		require.ErrorIs(t, err, ErrTargetDashboardDoesNotExists)
+	})
+
+	t.Run("deletes the correlations when the dashboard is deleted", func(t *testing.T) {
+		err := s.handleDashboardDeletion(ctx, &events.DashboardDeleted{UID: "checkout", OrgID: 2})
+		require.NoError(t, err)
+		_, err = s.GetCorrelation(ctx, GetCorrelationQuery{UID: created.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+
+		err = s.handleDashboardDeletion(ctx, &events.DashboardDeleted{UID: "checkout", OrgID: 1})
+		require.NoError(t, err)
+		_, err = s.GetCorrelation(ctx, GetCorrelationQuery{UID: created.UID, SourceUID: source.Uid, OrgId: 1})
+		require.ErrorIs(t, err, ErrCorrelationNotFound)
+	})
+}
+
+func TestIntegrationBulkCorrelations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	readOnly := &datasources.DataSource{OrgId: 1, Uid: "read-only-uid", Name: "read-only", Type: "loki", ReadOnly: true}
+	_, source, target, s := setupCorrelationsTest(t, readOnly)
+	ctx := context.Background()
+
+	targetUID := target.Uid
+	queryConfig := CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery, Target: map[string]interface{}{}}
+	item := func(sourceUID string, label string) BulkCreateCorrelationsItem {
+		return BulkCreateCorrelationsItem{
+			SourceUID:                sourceUID,
+			CreateCorrelationCommand: CreateCorrelationCommand{TargetUID: &targetUID, Label: label, Config: queryConfig},
+		}
+	}
+	countCorrelations := func(t *testing.T) int {
+		t.Helper()
+		correlations, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 1})
+		require.NoError(t, err)
+		return len(correlations)
+	}
+
+	t.Run("creates all the correlations", func(t *testing.T) {
+		created, err := s.BulkCreateCorrelations(ctx, BulkCreateCorrelationsCommand{OrgId: 1, Correlations: []BulkCreateCorrelationsItem{
+			item(source.Uid, "first"),
+			item(source.Uid, "second"),
+		}})
+		require.NoError(t, err)
+		require.Len(t, created, 2)
+		require.Equal(t, "first", created[0].Label)
+		require.Equal(t, "second", created[1].Label)
+		require.Equal(t, 2, countCorrelations(t))
+
+		t.Run("and deletes all of them", func(t *testing.T) {
+			err := s.BulkDeleteCorrelations(ctx, BulkDeleteCorrelationsCommand{OrgId: 1, Correlations: []BulkDeleteCorrelationsItem{
+				{SourceUID: source.Uid, UID: created[0].UID},
+				{SourceUID: source.Uid, UID: created[1].UID},
+			}})
+			require.NoError(t, err)
+			require.Equal(t, 0, countCorrelations(t))
+		})
+	})
+
+	t.Run("creates none of the correlations if any fails and reports every failure", func(t *testing.T) {
+		invalid := item(source.Uid, "invalid")
+		invalid.TargetUID = nil
+
+		_, err := s.BulkCreateCorrelations(ctx, BulkCreateCorrelationsCommand{OrgId: 1, Correlations: []BulkCreateCorrelationsItem{
+			item(source.Uid, "valid"),
+			item(readOnly.Uid, "read-only"),
+			invalid,
+			item("unknown-uid", "unknown"),
+		}})
+
+		var bulkErr BulkCorrelationsError
+		require.ErrorAs(t, err, &bulkErr)
+		require.Len(t, bulkErr.Errors, 3)
+		require.Equal(t, 1, bulkErr.Errors[0].Index)
+		require.Equal(t, "correlations.sourceDataSourceReadOnly", bulkErr.Errors[0].MessageID)
+		require.Equal(t, 2, bulkErr.Errors[1].Index)
+		require.Equal(t, "correlations.invalidTarget", bulkErr.Errors[1].MessageID)
+		require.Equal(t, 3, bulkErr.Errors[2].Index)
+		require.Equal(t, "unknown-uid", bulkErr.Errors[2].SourceUID)
+		require.Equal(t, "correlations.sourceDataSourceNotFound", bulkErr.Errors[2].MessageID)
+		require.Equal(t, 400, bulkErr.StatusCode())
+
+		require.Equal(t, 0, countCorrelations(t))
+	})
+
+	t.Run("deletes none of the correlations if any fails", func(t *testing.T) {
+		created, err := s.BulkCreateCorrelations(ctx, BulkCreateCorrelationsCommand{OrgId: 1, Correlations: []BulkCreateCorrelationsItem{
+			item(source.Uid, "kept"),
+		}})
+		require.NoError(t, err)
+
+		err = s.BulkDeleteCorrelations(ctx, BulkDeleteCorrelationsCommand{OrgId: 1, Correlations: []BulkDeleteCorrelationsItem{
+			{SourceUID: source.Uid, UID: created[0].UID},
+			{SourceUID: source.Uid, UID: "unknown"},
+		}})
+
+		var bulkErr BulkCorrelationsError
+		require.ErrorAs(t, err, &bulkErr)
+		require.Len(t, bulkErr.Errors, 1)
+		require.Equal(t, 1, bulkErr.Errors[0].Index)
+		require.Equal(t, "unknown", bulkErr.Errors[0].UID)
+		require.Equal(t, 404, bulkErr.StatusCode())
+
+		require.Equal(t, 1, countCorrelations(t))
+	})
+}
+
+func TestIntegrationExportImportCorrelations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	otherOrgSource := &datasources.DataSource{OrgId: 2, Uid: "other-source-uid", Name: "source", Type: "loki"}
+	otherOrgTarget := &datasources.DataSource{OrgId: 2, Uid: "other-target-uid", Name: "target prod", Type: "tempo"}
+	sqlStore, source, target, s := setupCorrelationsTest(t, otherOrgSource, otherOrgTarget)
+	ctx := context.Background()
+
+	targetUID := target.Uid
+	err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		_, err := session.Insert(
+			&Correlation{UID: "query", SourceUID: source.Uid, TargetUID: &targetUID, Label: "logs to traces", Config: CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery, Target: map[string]interface{}{}}},
+			&Correlation{UID: "external", SourceUID: source.Uid, Label: "tickets", Config: CorrelationConfig{Field: CorrelationFields{"ticket"}, Type: ConfigTypeExternal, Target: map[string]interface{}{"url": "https://tickets.example.com/${ticket}"}}},
+		)
+		return err
+	})
+	require.NoError(t, err)
+
+	var bundle CorrelationsBundle
+	t.Run("exports the correlations with the data source names", func(t *testing.T) {
+		bundle, err = s.ExportCorrelations(ctx, ExportCorrelationsQuery{OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, bundle.Correlations, 2)
+
+		byLabel := map[string]BundledCorrelation{}
+		for _, c := range bundle.Correlations {
+			byLabel[c.Label] = c
+		}
+		require.Equal(t, "source", byLabel["logs to traces"].SourceName)
+		require.Equal(t, "target", *byLabel["logs to traces"].TargetName)
+		require.Equal(t, "source", byLabel["tickets"].SourceName)
+		require.Nil(t, byLabel["tickets"].TargetName)
+	})
+
+	t.Run("fails to import if a data source can't be resolved", func(t *testing.T) {
+		_, err := s.ImportCorrelations(ctx, ImportCorrelationsCommand{OrgId: 2, CorrelationsBundle: bundle})
+
+		var bulkErr BulkCorrelationsError
+		require.ErrorAs(t, err, &bulkErr)
+		require.Len(t, bulkErr.Errors, 1)
+		require.Equal(t, "correlations.bundleDataSourceNotFound", bulkErr.Errors[0].MessageID)
+		require.Equal(t, "target", bulkErr.Errors[0].Extra["name"])
+
+		correlations, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 2})
+		require.NoError(t, err)
+		require.Empty(t, correlations)
+	})
+
+	t.Run("imports the correlations into another org with remapped data sources", func(t *testing.T) {
+		created, err := s.ImportCorrelations(ctx, ImportCorrelationsCommand{
+			OrgId:              2,
+			CorrelationsBundle: bundle,
+			DataSources:        map[string]string{"target": otherOrgTarget.Uid},
+		})
+		require.NoError(t, err)
+		require.Len(t, created, 2)
+
+		correlations, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 2})
+		require.NoError(t, err)
+		require.Len(t, correlations, 2)
+		for _, c := range correlations {
+			require.Equal(t, otherOrgSource.Uid, c.SourceUID)
+			if c.Label == "logs to traces" {
+				require.Equal(t, otherOrgTarget.Uid, *c.TargetUID)
+			} else {
+				require.Nil(t, c.TargetUID)
+			}
+		}
+	})
+}
+
+func TestIntegrationCopyCorrelations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	otherOrgSource := &datasources.DataSource{OrgId: 2, Uid: "other-source-uid", Name: "source", Type: "loki"}
+	otherOrgTarget := &datasources.DataSource{OrgId: 2, Uid: "other-target-uid", Name: "target prod", Type: "tempo"}
+	sqlStore, source, target, s := setupCorrelationsTest(t, otherOrgSource, otherOrgTarget)
+	ctx := context.Background()
+
+	targetUID := target.Uid
+	err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		_, err := session.Insert(
+			&Correlation{UID: "query", SourceUID: source.Uid, TargetUID: &targetUID, Label: "logs to traces", Config: CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery, Target: map[string]interface{}{}}},
+			&Correlation{UID: "external", SourceUID: source.Uid, Label: "tickets", Config: CorrelationConfig{Field: CorrelationFields{"ticket"}, Type: ConfigTypeExternal, Target: map[string]interface{}{"url": "https://tickets.example.com/${ticket}"}}},
+		)
+		return err
+	})
+	require.NoError(t, err)
+
+	selected := []CopyCorrelationsItem{{SourceUID: source.Uid, UID: "query"}, {SourceUID: source.Uid, UID: "external"}}
+
+	t.Run("fails if a correlation doesn't exist in the source org", func(t *testing.T) {
+		_, _, err := s.CopyCorrelations(ctx, CopyCorrelationsCommand{
+			FromOrgId:    1,
+			ToOrgId:      2,
+			Correlations: []CopyCorrelationsItem{selected[1], {SourceUID: source.Uid, UID: "unknown"}},
+		})
+
+		var bulkErr BulkCorrelationsError
+		require.ErrorAs(t, err, &bulkErr)
+		require.Len(t, bulkErr.Errors, 1)
+		require.Equal(t, 1, bulkErr.Errors[0].Index)
+		require.Equal(t, "correlations.notFound", bulkErr.Errors[0].MessageID)
+
+		correlations, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 2})
+		require.NoError(t, err)
+		require.Empty(t, correlations)
+	})
+
+	t.Run("copies the correlations whose data sources match and reports the others", func(t *testing.T) {
+		created, unmapped, err := s.CopyCorrelations(ctx, CopyCorrelationsCommand{FromOrgId: 1, ToOrgId: 2, Correlations: selected})
+		require.NoError(t, err)
+		require.Len(t, created, 1)
+		require.Equal(t, "tickets", created[0].Label)
+		require.Equal(t, otherOrgSource.Uid, created[0].SourceUID)
+
+		require.Len(t, unmapped, 1)
+		require.Equal(t, 0, unmapped[0].Index)
+		require.Equal(t, source.Uid, unmapped[0].SourceUID)
+		require.Equal(t, "query", unmapped[0].UID)
+		require.Equal(t, "target", unmapped[0].Extra["name"])
+	})
+
+	t.Run("copies the correlations with remapped data sources", func(t *testing.T) {
+		created, unmapped, err := s.CopyCorrelations(ctx, CopyCorrelationsCommand{
+			FromOrgId:    1,
+			ToOrgId:      2,
+			Correlations: selected[:1],
+			DataSources:  map[string]string{"target": otherOrgTarget.Uid},
+		})
+		require.NoError(t, err)
+		require.Empty(t, unmapped)
+		require.Len(t, created, 1)
+		require.Equal(t, otherOrgSource.Uid, created[0].SourceUID)
+		require.Equal(t, otherOrgTarget.Uid, *created[0].TargetUID)
+		require.NotEqual(t, "query", created[0].UID)
+
+		correlations, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, correlations, 2)
+	})
+}
+
+func TestIntegrationCorrelationVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore, source, target, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	targetUID := target.Uid
+	err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		// created before the version history
+		_, err := session.Insert(&Correlation{UID: "legacy", SourceUID: source.Uid, TargetUID: &targetUID, Label: "legacy", Config: CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery}})
+		return err
+	})
+	require.NoError(t, err)
+
+	label := func(l string) *string { return &l }
+
+	correlation, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+		SourceUID: source.Uid,
+		TargetUID: &targetUID,
+		OrgId:     1,
+		UserId:    1,
+		Label:     "first",
+		Config:    CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery},
+	})
+	require.NoError(t, err)
+
+	_, err = s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1, UserId: 2, Label: label("second")})
+	require.NoError(t, err)
+	_, err = s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1, UserId: 3, Label: label("third"), Description: label("description")})
+	require.NoError(t, err)
+
+	t.Run("records who changed what, latest first", func(t *testing.T) {
+		versions, err := s.GetCorrelationVersions(ctx, GetCorrelationVersionsQuery{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, versions, 3)
+
+		require.Equal(t, 3, versions[0].Version)
+		require.Equal(t, VersionActionUpdated, versions[0].Action)
+		require.Equal(t, "third", versions[0].Label)
+		require.Equal(t, "description", versions[0].Description)
+		require.Equal(t, int64(3), versions[0].CreatedBy)
+		require.Equal(t, "second", versions[1].Label)
+		require.Equal(t, int64(2), versions[1].CreatedBy)
+		require.Equal(t, VersionActionCreated, versions[2].Action)
+		require.Equal(t, "first", versions[2].Label)
+		require.Equal(t, int64(1), versions[2].CreatedBy)
+		require.False(t, versions[2].Created.IsZero())
+	})
+
+	t.Run("restores a version", func(t *testing.T) {
+		restored, err := s.RestoreCorrelationVersion(ctx, RestoreCorrelationVersionCommand{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1, Version: 1, UserId: 4})
+		require.NoError(t, err)
+		require.Equal(t, "first", restored.Label)
+		require.Equal(t, "", restored.Description)
+
+		versions, err := s.GetCorrelationVersions(ctx, GetCorrelationVersionsQuery{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, versions, 4)
+		require.Equal(t, VersionActionRestored, versions[0].Action)
+		require.Equal(t, 1, versions[0].RestoredFrom)
+		require.Equal(t, int64(4), versions[0].CreatedBy)
+	})
+
+	t.Run("fails to restore an unknown version", func(t *testing.T) {
+		_, err := s.RestoreCorrelationVersion(ctx, RestoreCorrelationVersionCommand{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1, Version: 10})
+		require.ErrorIs(t, err, ErrCorrelationVersionNotFound)
+	})
+
+	t.Run("keeps the state of correlations created before the history", func(t *testing.T) {
+		_, err := s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: "legacy", SourceUID: source.Uid, OrgId: 1, UserId: 2, Label: label("updated")})
+		require.NoError(t, err)
+
+		versions, err := s.GetCorrelationVersions(ctx, GetCorrelationVersionsQuery{UID: "legacy", SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		require.Equal(t, "updated", versions[0].Label)
+		require.Equal(t, "legacy", versions[1].Label)
+		require.Equal(t, int64(0), versions[1].CreatedBy)
+	})
+
+	t.Run("deletes the versions with the correlation", func(t *testing.T) {
+		err := s.DeleteCorrelation(ctx, DeleteCorrelationCommand{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+
+		err = s.DeleteCorrelationsBySourceUID(ctx, DeleteCorrelationsBySourceUIDCommand{SourceUID: source.Uid})
+		require.NoError(t, err)
+
+		err = sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+			count, err := session.Count(&CorrelationVersion{})
+			require.Zero(t, count)
+			return err
+		})
+		require.NoError(t, err)
+	})
+}
+
+type recordingAnnotations struct {
+	annotations.Repository
+	items []*annotations.Item
+}
+
+func (r *recordingAnnotations) Save(_ context.Context, item *annotations.Item) error {
+	r.items = append(r.items, item)
+	return nil
+}
+
+func TestIntegrationCorrelationAudit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, source, target, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	targetUID := target.Uid
+
+	audit := &recordingAnnotations{}
+	s.Annotations = audit
+	label := "Logs to Traces"
+
+	correlation, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+		SourceUID: source.Uid,
+		TargetUID: &targetUID,
+		OrgId:     1,
+		UserId:    1,
+		Label:     "logs to traces",
+		Config:    CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery},
+	})
+	require.NoError(t, err)
+	_, err = s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1, UserId: 2, Label: &label})
+	require.NoError(t, err)
+	err = s.DeleteCorrelation(ctx, DeleteCorrelationCommand{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1, UserId: 3})
+	require.NoError(t, err)
+
+	t.Run("records every change as an organization annotation", func(t *testing.T) {
+		require.Len(t, audit.items, 3)
+		for i, action := range []string{AuditActionCreated, AuditActionUpdated, AuditActionDeleted} {
+			item := audit.items[i]
+			require.Equal(t, int64(1), item.OrgId)
+			require.Equal(t, int64(i+1), item.UserId)
+			require.Zero(t, item.DashboardId)
+			require.Equal(t, []string{AuditAnnotationTag, action}, item.Tags)
+			require.Equal(t, correlation.UID, item.Data.Get("uid").MustString())
+			require.Equal(t, source.Uid, item.Data.Get("sourceUID").MustString())
+			require.Equal(t, action, item.Data.Get("action").MustString())
+		}
+	})
+
+	t.Run("records the changed fields", func(t *testing.T) {
+		created := audit.items[0].Data.Get("diff").MustMap()
+		require.Contains(t, created, "label")
+		require.Contains(t, created, "targetUID")
+		require.Contains(t, created, "config")
+		require.Contains(t, created, "description")
+
+		updated := audit.items[1].Data.Get("diff")
+		require.Len(t, updated.MustMap(), 1)
+		require.Equal(t, "logs to traces", updated.GetPath("label", "old").MustString())
+		require.Equal(t, "Logs to Traces", updated.GetPath("label", "new").MustString())
+
+		deleted := audit.items[2].Data.Get("diff")
+		require.Equal(t, "Logs to Traces", deleted.GetPath("label", "old").MustString())
+		require.Nil(t, deleted.GetPath("label", "new").Interface())
+	})
+
+	t.Run("doesn't record failed changes", func(t *testing.T) {
+		err := s.DeleteCorrelation(ctx, DeleteCorrelationCommand{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1, UserId: 3})
+		require.ErrorIs(t, err, ErrCorrelationNotFound)
+		require.Len(t, audit.items, 3)
+	})
+}
+
+type fakeQueryService struct {
+	queries []*simplejson.Json
+	err     error
+}
+
+func (f *fakeQueryService) QueryData(_ context.Context, _ *user.SignedInUser, _ bool, reqDTO dtos.MetricRequest, _ bool) (*backend.QueryDataResponse, error) {
+	f.queries = append(f.queries, reqDTO.Queries...)
+	resp := backend.NewQueryDataResponse()
+	resp.Responses[dryRunRefID] = backend.DataResponse{Error: f.err}
+	return resp, nil
+}
+
+func TestIntegrationTargetDryRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, source, target, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	queries := &fakeQueryService{}
+	s.QueryService = queries
+	signedInUser := &user.SignedInUser{UserID: 2, OrgID: 1}
+	cmd := CreateCorrelationCommand{
+		SourceUID: source.Uid,
+		TargetUID: &target.Uid,
+		OrgId:     1,
+		Label:     "rate",
+		Config: CorrelationConfig{
+			Field:  CorrelationFields{"job"},
+			Type:   ConfigTypeQuery,
+			Target: map[string]interface{}{"expr": `rate(requests{job="${job}"}[${__interval}])`},
+		},
+		ValidateTarget: true,
+		SignedInUser:   signedInUser,
+	}
+
+	t.Run("runs the target query with sample values", func(t *testing.T) {
+		_, err := s.CreateCorrelation(ctx, cmd)
+		require.NoError(t, err)
+
+		require.Len(t, queries.queries, 1)
+		require.Equal(t, `rate(requests{job="1"}[1m])`, queries.queries[0].Get("expr").MustString())
+		require.Equal(t, target.Uid, queries.queries[0].GetPath("datasource", "uid").MustString())
+		require.Equal(t, dryRunRefID, queries.queries[0].Get("refId").MustString())
+	})
+
+	t.Run("does not run the target query unless asked to", func(t *testing.T) {
+		noValidation := cmd
+		noValidation.ValidateTarget = false
+		_, err := s.CreateCorrelation(ctx, noValidation)
+		require.NoError(t, err)
+		require.Len(t, queries.queries, 1)
+	})
+
+	t.Run("rejects the correlations whose target query fails", func(t *testing.T) {
+		queries.err = errors.New("parse error: unexpected end of input")
+		t.Cleanup(func() { queries.err = nil })
+
+		_, err := s.CreateCorrelation(ctx, cmd)
+		require.ErrorIs(t, err, ErrInvalidTargetQuery)
+		var grafanaErr errutil.Error
+		require.ErrorAs(t, err, &grafanaErr)
+		require.Equal(t, "parse error: unexpected end of input", grafanaErr.Public().Extra["error"])
+
+		correlations, err := s.GetCorrelationsBySourceUID(ctx, GetCorrelationsBySourceUIDQuery{SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, correlations, 2)
+	})
+
+	t.Run("runs the updated target query", func(t *testing.T) {
+		noValidation := cmd
+		noValidation.ValidateTarget = false
+		created, err := s.CreateCorrelation(ctx, noValidation)
+		require.NoError(t, err)
+
+		queries.err = errors.New("parse error: unexpected end of input")
+		t.Cleanup(func() { queries.err = nil })
+		label := "updated"
+		newTarget := map[string]interface{}{"expr": "rate("}
+		_, err = s.UpdateCorrelation(ctx, UpdateCorrelationCommand{
+			UID:            created.UID,
+			SourceUID:      source.Uid,
+			OrgId:          1,
+			Label:          &label,
+			Config:         &CorrelationConfigUpdateDTO{Target: &newTarget},
+			ValidateTarget: true,
+			SignedInUser:   signedInUser,
+		})
+		require.ErrorIs(t, err, ErrInvalidTargetQuery)
+		require.Equal(t, "rate(", queries.queries[len(queries.queries)-1].Get("expr").MustString())
+
+		unchanged, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: created.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, "rate", unchanged.Label)
+	})
+
+	t.Run("runs and stores every target query of a list", func(t *testing.T) {
+		list := cmd
+		list.Label = "list"
+		list.Config.setTargets([]map[string]interface{}{
+			{"expr": `rate(requests{job="${job}"}[5m])`},
+			{"expr": `rate(errors{job="${job}"}[5m])`},
+		})
+		count := len(queries.queries)
+		created, err := s.CreateCorrelation(ctx, list)
+		require.NoError(t, err)
+
+		run := queries.queries[count:]
+		require.Len(t, run, 2)
+		require.Equal(t, dryRunRefID, run[0].Get("refId").MustString())
+		require.Equal(t, dryRunRefID+"-1", run[1].Get("refId").MustString())
+		require.Equal(t, `rate(errors{job="1"}[5m])`, run[1].Get("expr").MustString())
+
+		stored, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: created.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, list.Config.Targets, stored.Config.Targets)
+		require.Equal(t, list.Config.Targets[0], stored.Config.Target)
+	})
+
+	t.Run("does not run the target of external correlations", func(t *testing.T) {
+		external := cmd
+		external.TargetUID = nil
+		external.Config = CorrelationConfig{
+			Field:  CorrelationFields{"job"},
+			Type:   ConfigTypeExternal,
+			Target: map[string]interface{}{"url": "https://example.com/jobs/${job}"},
+		}
+		count := len(queries.queries)
+		_, err := s.CreateCorrelation(ctx, external)
+		require.NoError(t, err)
+		require.Len(t, queries.queries, count)
+	})
+}
+
+func TestIntegrationTargetSchema(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	// the tempo target of the setup has no schema
+	checked := &datasources.DataSource{OrgId: 1, Uid: "checked-uid", Name: "checked", Type: "prometheus"}
+	_, source, unchecked, s := setupCorrelationsTest(t, checked)
+	ctx := context.Background()
+
+	prometheus := plugins.PluginDTO{JSONData: plugins.JSONData{ID: "prometheus", Type: plugins.DataSource, CorrelationTargetSchema: []byte(`{
+		"type": "object",
+		"required": ["expr"],
+		"properties": {"expr": {"type": "string", "minLength": 1}}
+	}`)}}
+	tempo := plugins.PluginDTO{JSONData: plugins.JSONData{ID: "tempo", Type: plugins.DataSource}}
+	s.PluginStore = plugins.FakePluginStore{PluginList: []plugins.PluginDTO{prometheus, tempo}}
+	cmd := CreateCorrelationCommand{
+		SourceUID: source.Uid,
+		TargetUID: &checked.Uid,
+		OrgId:     1,
+		Label:     "rate",
+		Config: CorrelationConfig{
+			Field:  CorrelationFields{"job"},
+			Type:   ConfigTypeQuery,
+			Target: map[string]interface{}{"expr": `rate(requests{job="${job}"}[5m])`},
+		},
+	}
+
+	t.Run("accepts the targets matching the schema of the plugin", func(t *testing.T) {
+		_, err := s.CreateCorrelation(ctx, cmd)
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects the targets not matching the schema of the plugin", func(t *testing.T) {
+		invalid := cmd
+		invalid.Config.Target = map[string]interface{}{"query": "rate(requests[5m])"}
+		_, err := s.CreateCorrelation(ctx, invalid)
+		require.ErrorIs(t, err, ErrTargetSchemaMismatch)
+		var grafanaErr errutil.Error
+		require.ErrorAs(t, err, &grafanaErr)
+		require.Equal(t, "prometheus", grafanaErr.Public().Extra["plugin"])
+		require.Contains(t, grafanaErr.Public().Extra["reason"], "expr")
+	})
+
+	t.Run("rejects the updated targets not matching the schema of the plugin", func(t *testing.T) {
+		created, err := s.CreateCorrelation(ctx, cmd)
+		require.NoError(t, err)
+
+		newTarget := map[string]interface{}{"expr": 42}
+		_, err = s.UpdateCorrelation(ctx, UpdateCorrelationCommand{
+			UID:       created.UID,
+			SourceUID: source.Uid,
+			OrgId:     1,
+			Config:    &CorrelationConfigUpdateDTO{Target: &newTarget},
+		})
+		require.ErrorIs(t, err, ErrTargetSchemaMismatch)
+
+		unchanged, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: created.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, cmd.Config.Target, unchanged.Config.Target)
+	})
+
+	t.Run("accepts any target for the plugins without schema", func(t *testing.T) {
+		anyTarget := cmd
+		anyTarget.TargetUID = &unchecked.Uid
+		anyTarget.Config.Target = map[string]interface{}{"anything": true}
+		_, err := s.CreateCorrelation(ctx, anyTarget)
+		require.NoError(t, err)
+	})
+
+	t.Run("ignores the invalid schemas of plugins", func(t *testing.T) {
+		broken := prometheus
+		broken.CorrelationTargetSchema = []byte(`{"type": `)
+		withBrokenSchema := *s
+		withBrokenSchema.PluginStore = plugins.FakePluginStore{PluginList: []plugins.PluginDTO{broken}}
+
+		invalid := cmd
+		invalid.Config.Target = map[string]interface{}{"query": "rate(requests[5m])"}
+		_, err := withBrokenSchema.CreateCorrelation(ctx, invalid)
+		require.NoError(t, err)
+	})
+}
+
+type fakeQuotaService struct {
+	quota.Service
+	reached bool
+	target  string
+	params  *quota.ScopeParameters
+}
+
+func (f *fakeQuotaService) CheckQuotaReached(_ context.Context, target string, params *quota.ScopeParameters) (bool, error) {
+	f.target = target
+	f.params = params
+	return f.reached, nil
+}
+
+func TestIntegrationCorrelationQuota(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	other := &datasources.DataSource{OrgId: 1, Uid: "other-uid", Name: "other", Type: "loki"}
+	_, source, _, s := setupCorrelationsTest(t, other)
+	ctx := context.Background()
+
+	quotas := &fakeQuotaService{}
+	s.QuotaService = quotas
+	s.Cfg.Quota = setting.QuotaSettings{Enabled: true, DataSource: &setting.DataSourceQuota{Correlation: 1}}
+	external := func(sourceUID string) CreateCorrelationCommand {
+		return CreateCorrelationCommand{
+			SourceUID: sourceUID,
+			OrgId:     1,
+			UserId:    2,
+			Config: CorrelationConfig{
+				Field:  CorrelationFields{"ticket"},
+				Type:   ConfigTypeExternal,
+				Target: map[string]interface{}{"url": "https://tickets.example.com/${ticket}"},
+			},
+		}
+	}
+
+	t.Run("fails once the org reached its quota", func(t *testing.T) {
+		quotas.reached = true
+		t.Cleanup(func() { quotas.reached = false })
+
+		_, err := s.CreateCorrelation(ctx, external(source.Uid))
+		require.ErrorIs(t, err, ErrCorrelationsQuotaReached)
+		require.Equal(t, "correlation", quotas.target)
+		require.Equal(t, &quota.ScopeParameters{OrgID: 1, UserID: 2}, quotas.params)
+	})
+
+	t.Run("fails once the data source reached its quota", func(t *testing.T) {
+		_, err := s.CreateCorrelation(ctx, external(source.Uid))
+		require.NoError(t, err)
+
+		_, err = s.CreateCorrelation(ctx, external(source.Uid))
+		require.ErrorIs(t, err, ErrCorrelationsQuotaReached)
+
+		_, err = s.CreateCorrelation(ctx, external(other.Uid))
+		require.NoError(t, err)
+	})
+
+	t.Run("doesn't limit the provisioned correlations", func(t *testing.T) {
+		quotas.reached = true
+		t.Cleanup(func() { quotas.reached = false })
+
+		provisioned := external(source.Uid)
+		provisioned.Provisioned = true
+		_, err := s.CreateCorrelation(ctx, provisioned)
+		require.NoError(t, err)
+	})
+
+	t.Run("doesn't limit the data sources if quotas are disabled", func(t *testing.T) {
+		s.Cfg.Quota.Enabled = false
+		t.Cleanup(func() { s.Cfg.Quota.Enabled = true })
+
+		_, err := s.CreateCorrelation(ctx, external(other.Uid))
+		require.NoError(t, err)
+	})
+}
+
+func TestIntegrationDataSourceUIDChange(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	// a data source of another org still using the previous UID of other
+	shared := &datasources.DataSource{OrgId: 2, Uid: "shared-uid", Name: "shared", Type: "tempo"}
+	// the data sources are already stored with their new UID when the event is handled
+	sqlStore, renamed, other, s := setupCorrelationsTest(t, shared)
+	ctx := context.Background()
+
+	dataSources := s.DataSourceService.(*fakeDatasources.FakeDataSourceService)
+	dataSources.DataSources = append(dataSources.DataSources, &datasources.DataSource{OrgId: 1, Uid: "old-uid", Name: renamed.Name})
+	create := func(orgID int64, sourceUID, targetUID string) Correlation {
+		correlation, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+			SourceUID: sourceUID,
+			TargetUID: &targetUID,
+			OrgId:     orgID,
+			Config:    CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery},
+		})
+		require.NoError(t, err)
+		return correlation
+	}
+	fromRenamed := create(1, "old-uid", other.Uid)
+	toRenamed := create(1, other.Uid, "old-uid")
+	fromShared := create(2, shared.Uid, other.Uid)
+
+	t.Run("moves the correlations to the new UID", func(t *testing.T) {
+		err := s.handleDatasourceUIDChange(ctx, &events.DataSourceUIDChanged{OrgID: 1, ID: renamed.Id, PreviousUID: "old-uid", UID: renamed.Uid})
+		require.NoError(t, err)
+
+		correlation, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: fromRenamed.UID, SourceUID: renamed.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, other.Uid, *correlation.TargetUID)
+
+		correlation, err = s.GetCorrelation(ctx, GetCorrelationQuery{UID: toRenamed.UID, SourceUID: other.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, renamed.Uid, *correlation.TargetUID)
+	})
+
+	t.Run("moves the versions with the correlations", func(t *testing.T) {
+		versions, err := s.GetCorrelationVersions(ctx, GetCorrelationVersionsQuery{UID: fromRenamed.UID, SourceUID: renamed.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, versions, 1)
+
+		versions, err = s.GetCorrelationVersions(ctx, GetCorrelationVersionsQuery{UID: toRenamed.UID, SourceUID: other.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, versions, 1)
+		require.Equal(t, renamed.Uid, *versions[0].TargetUID)
+	})
+
+	t.Run("leaves the correlations alone if a data source still uses the previous UID", func(t *testing.T) {
+		err := s.handleDatasourceUIDChange(ctx, &events.DataSourceUIDChanged{OrgID: 1, ID: other.Id, PreviousUID: shared.Uid, UID: other.Uid})
+		require.NoError(t, err)
+
+		err = sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+			correlation := Correlation{}
+			found, err := session.Where("uid = ?", fromShared.UID).Get(&correlation)
+			require.True(t, found)
+			require.Equal(t, shared.Uid, correlation.SourceUID)
+			return err
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("leaves the correlations stored without their org alone if a data source still uses the previous UID", func(t *testing.T) {
+		err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+			_, err := session.Exec("UPDATE correlation SET org_id = 0 WHERE uid = ?", fromShared.UID)
+			return err
+		})
+		require.NoError(t, err)
+
+		err = s.handleDatasourceUIDChange(ctx, &events.DataSourceUIDChanged{OrgID: 1, ID: other.Id, PreviousUID: shared.Uid, UID: other.Uid})
+		require.NoError(t, err)
+
+		err = sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+			correlation := Correlation{}
+			found, err := session.Where("uid = ?", fromShared.UID).Get(&correlation)
+			require.True(t, found)
+			require.Equal(t, shared.Uid, correlation.SourceUID)
+			return err
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestIntegrationListCache(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, source, _, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	s.listCache = localcache.New(listCacheTTL, listCacheCleanupInterval)
+	create := func() Correlation {
+		correlation, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+			SourceUID: source.Uid,
+			OrgId:     1,
+			Config: CorrelationConfig{
+				Field:  CorrelationFields{"ticket"},
+				Type:   ConfigTypeExternal,
+				Target: map[string]interface{}{"url": "https://tickets.example.com/${ticket}"},
+			},
+		})
+		require.NoError(t, err)
+		return correlation
+	}
+	loads := 0
+	list := func(ifNoneMatch string) (int, string) {
+		req := httptest.NewRequest(http.MethodGet, "/api/datasources/correlations", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		c := &models.ReqContext{Context: &web.Context{Req: req, Resp: web.NewResponseWriter(req.Method, httptest.NewRecorder())}, SignedInUser: &user.SignedInUser{OrgID: 1}}
+		resp := s.listResponse(c, "all", "", nil, func() ([]Correlation, error) {
+			loads++
+			return s.getCorrelations(ctx, GetCorrelationsQuery{OrgId: 1})
+		})
+		return resp.Status(), c.Resp.Header().Get("ETag")
+	}
+
+	create()
+	status, etag := list("")
+	require.Equal(t, http.StatusOK, status)
+	require.NotEmpty(t, etag)
+
+	t.Run("serves the cached list", func(t *testing.T) {
+		status, cached := list("")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, etag, cached)
+		require.Equal(t, 1, loads)
+	})
+
+	t.Run("responds with 304 if the list didn't change", func(t *testing.T) {
+		status, _ := list(etag)
+		require.Equal(t, http.StatusNotModified, status)
+
+		status, _ = list(`"other", W/` + etag)
+		require.Equal(t, http.StatusNotModified, status)
+
+		status, _ = list(`"other"`)
+		require.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("drops the cached list when correlations are changed", func(t *testing.T) {
+		correlation := create()
+		status, changed := list(etag)
+		require.Equal(t, http.StatusOK, status)
+		require.NotEqual(t, etag, changed)
+		require.Equal(t, 2, loads)
+
+		err := s.DeleteCorrelation(ctx, DeleteCorrelationCommand{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		status, deleted := list(changed)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, etag, deleted)
+		require.Equal(t, 3, loads)
+	})
+}
+
+func TestIntegrationSourceTypeCorrelations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	otherLoki := &datasources.DataSource{OrgId: 1, Uid: "other-loki-uid", Name: "Other Loki", Type: "loki", ReadOnly: true}
+	otherOrgLoki := &datasources.DataSource{OrgId: 2, Uid: "loki-uid-2", Name: "Loki", Type: "loki"}
+	_, loki, tempo, s := setupCorrelationsTest(t, otherLoki, otherOrgLoki)
+	ctx := context.Background()
+
+	targetUID := tempo.Uid
+	create := func(sourceUID string, sourceType string, label string, order int) Correlation {
+		t.Helper()
+		correlation, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+			SourceUID:  sourceUID,
+			SourceType: sourceType,
+			OrgId:      1,
+			TargetUID:  &targetUID,
+			Label:      label,
+			Order:      order,
+			Config:     CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery, Target: map[string]interface{}{}},
+		})
+		require.NoError(t, err)
+		return correlation
+	}
+
+	byType := create("", "loki", "logs to traces", 1)
+	byDataSource := create(loki.Uid, "", "logs to traces of the app", 0)
+
+	t.Run("stores the correlations of a type without source data source", func(t *testing.T) {
+		require.Empty(t, byType.SourceUID)
+		require.Equal(t, "loki", byType.SourceType)
+
+		correlations, err := s.GetCorrelationsBySourceType(ctx, GetCorrelationsBySourceTypeQuery{SourceType: "loki", OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, correlations, 1)
+		require.Equal(t, byType.UID, correlations[0].UID)
+
+		correlations, err = s.GetCorrelationsBySourceType(ctx, GetCorrelationsBySourceTypeQuery{SourceType: "loki", OrgId: 2})
+		require.NoError(t, err)
+		require.Empty(t, correlations)
+	})
+
+	t.Run("returns the correlations of the type for each data source of the type", func(t *testing.T) {
+		correlations, err := s.GetCorrelationsBySourceUID(ctx, GetCorrelationsBySourceUIDQuery{SourceUID: loki.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, correlations, 2)
+		require.Equal(t, byDataSource.UID, correlations[0].UID)
+		require.Equal(t, byType.UID, correlations[1].UID)
+
+		correlations, err = s.GetCorrelationsBySourceUID(ctx, GetCorrelationsBySourceUIDQuery{SourceUID: otherLoki.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, correlations, 1)
+		require.Equal(t, byType.UID, correlations[0].UID)
+
+		correlations, err = s.GetCorrelationsBySourceUID(ctx, GetCorrelationsBySourceUIDQuery{SourceUID: tempo.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Empty(t, correlations)
+
+		correlations, err = s.GetCorrelationsBySourceUID(ctx, GetCorrelationsBySourceUIDQuery{SourceUID: otherOrgLoki.Uid, OrgId: 2})
+		require.NoError(t, err)
+		require.Empty(t, correlations)
+
+		correlation, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: byType.UID, SourceUID: otherLoki.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, "logs to traces", correlation.Label)
+
+		_, err = s.GetCorrelation(ctx, GetCorrelationQuery{UID: byType.UID, SourceUID: tempo.Uid, OrgId: 1})
+		require.ErrorIs(t, err, ErrCorrelationNotFound)
+	})
+
+	t.Run("lists the correlations of the types of the org and their targets", func(t *testing.T) {
+		correlations, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, correlations, 2)
+
+		correlations, err = s.GetCorrelationsByTargetUID(ctx, GetCorrelationsByTargetUIDQuery{TargetUID: tempo.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, correlations, 2)
+
+		correlations, err = s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 2})
+		require.NoError(t, err)
+		require.Empty(t, correlations)
+	})
+
+	t.Run("updates the correlations of a type", func(t *testing.T) {
+		label := "updated"
+		correlation, err := s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: byType.UID, SourceType: "loki", OrgId: 1, Label: &label})
+		require.NoError(t, err)
+		require.Equal(t, label, correlation.Label)
+
+		_, err = s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: byType.UID, SourceType: "tempo", OrgId: 1, Label: &label})
+		require.ErrorIs(t, err, ErrCorrelationNotFound)
+
+		_, err = s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: byType.UID, SourceType: "loki", OrgId: 2, Label: &label})
+		require.ErrorIs(t, err, ErrCorrelationNotFound)
+	})
+
+	t.Run("doesn't delete the correlations of a type as orphans", func(t *testing.T) {
+		_, err := s.deleteOrphanedCorrelations(ctx)
+		require.NoError(t, err)
+
+		correlations, err := s.GetCorrelationsBySourceType(ctx, GetCorrelationsBySourceTypeQuery{SourceType: "loki", OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, correlations, 1)
+	})
+
+	t.Run("deletes the correlations of a type", func(t *testing.T) {
+		err := s.DeleteCorrelation(ctx, DeleteCorrelationCommand{UID: byType.UID, SourceType: "loki", OrgId: 2})
+		require.ErrorIs(t, err, ErrCorrelationNotFound)
+
+		err = s.DeleteCorrelation(ctx, DeleteCorrelationCommand{UID: byType.UID, SourceType: "loki", OrgId: 1})
+		require.NoError(t, err)
+
+		correlations, err := s.GetCorrelationsBySourceUID(ctx, GetCorrelationsBySourceUIDQuery{SourceUID: loki.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, correlations, 1)
+		require.Equal(t, byDataSource.UID, correlations[0].UID)
+	})
+}
+
+func TestIntegrationCorrelationsBySourceUIDs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	otherLoki := &datasources.DataSource{OrgId: 1, Uid: "other-loki-uid", Name: "Other Loki", Type: "loki"}
+	prometheus := &datasources.DataSource{OrgId: 1, Uid: "prometheus-uid", Name: "Prometheus", Type: "prometheus"}
+	_, loki, tempo, s := setupCorrelationsTest(t, otherLoki, prometheus)
+	ctx := context.Background()
+
+	targetUID := tempo.Uid
+	create := func(sourceUID string, sourceType string, label string, tags []string) Correlation {
+		t.Helper()
+		correlation, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+			SourceUID:  sourceUID,
+			SourceType: sourceType,
+			OrgId:      1,
+			TargetUID:  &targetUID,
+			Label:      label,
+			Tags:       tags,
+			Config:     CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery, Target: map[string]interface{}{}},
+		})
+		require.NoError(t, err)
+		return correlation
+	}
+
+	fromLoki := create(loki.Uid, "", "logs to traces of the app", []string{"app"})
+	fromPrometheus := create(prometheus.Uid, "", "exemplars to traces", nil)
+	byType := create("", "loki", "logs to traces", nil)
+	create(tempo.Uid, "", "traces to traces", nil)
+
+	t.Run("returns the correlations of each source data source", func(t *testing.T) {
+		correlations, err := s.GetCorrelationsBySourceUIDs(ctx, GetCorrelationsBySourceUIDsQuery{SourceUIDs: []string{loki.Uid, otherLoki.Uid, prometheus.Uid}, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, correlations, 3)
+		require.ElementsMatch(t, []string{fromLoki.UID, byType.UID}, correlationUIDs(correlations[loki.Uid]))
+		require.Equal(t, []string{byType.UID}, correlationUIDs(correlations[otherLoki.Uid]))
+		require.Equal(t, []string{fromPrometheus.UID}, correlationUIDs(correlations[prometheus.Uid]))
+	})
+
+	t.Run("filters the correlations by tag", func(t *testing.T) {
+		correlations, err := s.GetCorrelationsBySourceUIDs(ctx, GetCorrelationsBySourceUIDsQuery{SourceUIDs: []string{loki.Uid, prometheus.Uid}, OrgId: 1, Tags: []string{"app"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{fromLoki.UID}, correlationUIDs(correlations[loki.Uid]))
+		require.NotNil(t, correlations[prometheus.Uid])
+		require.Empty(t, correlations[prometheus.Uid])
+	})
+
+	t.Run("fails when a source data source doesn't exist", func(t *testing.T) {
+		_, err := s.GetCorrelationsBySourceUIDs(ctx, GetCorrelationsBySourceUIDsQuery{SourceUIDs: []string{loki.Uid, "missing-uid"}, OrgId: 1})
+		require.ErrorIs(t, err, ErrSourceDataSourceDoesNotExists)
+	})
+
+	t.Run("validates the number of source data sources", func(t *testing.T) {
+		require.ErrorIs(t, GetCorrelationsBySourceUIDsQuery{OrgId: 1}.Validate(), ErrInvalidSourceUIDs)
+		require.ErrorIs(t, GetCorrelationsBySourceUIDsQuery{SourceUIDs: []string{""}, OrgId: 1}.Validate(), ErrInvalidSourceUIDs)
+		require.ErrorIs(t, GetCorrelationsBySourceUIDsQuery{SourceUIDs: make([]string, MaxSourceUIDs+1), OrgId: 1}.Validate(), ErrInvalidSourceUIDs)
+		require.NoError(t, GetCorrelationsBySourceUIDsQuery{SourceUIDs: []string{loki.Uid}, OrgId: 1}.Validate())
+	})
+}
+
+func correlationUIDs(correlations []Correlation) []string {
+	uids := make([]string, 0, len(correlations))
+	for _, correlation := range correlations {
+		uids = append(uids, correlation.UID)
+	}
+	return uids
+}
+
+func TestIntegrationExpandDataSources(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, loki, tempo, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	s.listCache = localcache.New(listCacheTTL, listCacheCleanupInterval)
+	targetUID := tempo.Uid
+	for _, cmd := range []CreateCorrelationCommand{
+		{SourceUID: loki.Uid, OrgId: 1, TargetUID: &targetUID, Label: "logs to traces"},
+		{SourceType: "loki", OrgId: 1, TargetUID: &targetUID, Label: "any logs to traces"},
+	} {
+		cmd.Config = CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery, Target: map[string]interface{}{}}
+		_, err := s.CreateCorrelation(ctx, cmd)
+		require.NoError(t, err)
+	}
+
+	list := func(target string) (int, []Correlation) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		c := &models.ReqContext{Context: &web.Context{Req: req, Resp: web.NewResponseWriter(req.Method, httptest.NewRecorder())}, SignedInUser: &user.SignedInUser{OrgID: 1}}
+		resp := s.listResponse(c, "all", "", nil, func() ([]Correlation, error) {
+			return s.getCorrelations(ctx, GetCorrelationsQuery{OrgId: 1})
+		})
+		var correlations []Correlation
+		if resp.Status() == http.StatusOK {
+			require.NoError(t, json.Unmarshal(resp.Body(), &correlations))
+		}
+		return resp.Status(), correlations
+	}
+	byLabel := func(correlations []Correlation) map[string]Correlation {
+		labels := make(map[string]Correlation, len(correlations))
+		for _, correlation := range correlations {
+			labels[correlation.Label] = correlation
+		}
+		return labels
+	}
+
+	t.Run("doesn't return the data sources by default", func(t *testing.T) {
+		status, correlations := list("/api/datasources/correlations")
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, correlations, 2)
+		for _, correlation := range correlations {
+			require.Nil(t, correlation.SourceDataSource)
+			require.Nil(t, correlation.TargetDataSource)
+		}
+	})
+
+	t.Run("returns the data sources with expand=datasources", func(t *testing.T) {
+		status, correlations := list("/api/datasources/correlations?expand=datasources")
+		require.Equal(t, http.StatusOK, status)
+		labels := byLabel(correlations)
+		require.Len(t, labels, 2)
+
+		fromLoki := labels["logs to traces"]
+		require.Equal(t, &CorrelationDataSource{UID: loki.Uid, Name: loki.Name, Type: loki.Type}, fromLoki.SourceDataSource)
+		require.Equal(t, &CorrelationDataSource{UID: tempo.Uid, Name: tempo.Name, Type: tempo.Type}, fromLoki.TargetDataSource)
+
+		byType := labels["any logs to traces"]
+		require.Nil(t, byType.SourceDataSource)
+		require.Equal(t, &CorrelationDataSource{UID: tempo.Uid, Name: tempo.Name, Type: tempo.Type}, byType.TargetDataSource)
+	})
+
+	t.Run("caches the expanded list separately", func(t *testing.T) {
+		_, correlations := list("/api/datasources/correlations")
+		require.Nil(t, correlations[0].TargetDataSource)
+		_, correlations = list("/api/datasources/correlations?expand=datasources")
+		require.NotNil(t, correlations[0].TargetDataSource)
+	})
+
+	t.Run("rejects unknown expand values", func(t *testing.T) {
+		status, _ := list("/api/datasources/correlations?expand=datasources,dashboards")
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func TestIntegrationCorrelationVisibility(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore, source, _, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	oncall := &models.Team{OrgId: 1, Name: "on-call", Created: time.Now(), Updated: time.Now()}
+	otherOrg := &models.Team{OrgId: 2, Name: "other", Created: time.Now(), Updated: time.Now()}
+	err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		_, err := session.Insert(oncall, otherOrg)
+		return err
+	})
+	require.NoError(t, err)
+
+	s.listCache = localcache.New(listCacheTTL, listCacheCleanupInterval)
+	create := func(label string, visibility *CorrelationVisibility) (Correlation, error) {
+		return s.CreateCorrelation(ctx, CreateCorrelationCommand{
+			SourceUID:  source.Uid,
+			OrgId:      1,
+			Label:      label,
+			Visibility: visibility,
+			Config: CorrelationConfig{
+				Field:  CorrelationFields{"ticket"},
+				Type:   ConfigTypeExternal,
+				Target: map[string]interface{}{"url": "https://tickets.example.com/${ticket}"},
+			},
+		})
+	}
+
+	_, err = create("public", nil)
+	require.NoError(t, err)
+	_, err = create("editors", &CorrelationVisibility{Roles: []org.RoleType{org.RoleEditor}})
+	require.NoError(t, err)
+	debugging, err := create("on-call", &CorrelationVisibility{Teams: []int64{oncall.Id}})
+	require.NoError(t, err)
+	require.Equal(t, &CorrelationVisibility{Teams: []int64{oncall.Id}}, debugging.Visibility)
+
+	list := func(u *user.SignedInUser) []string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/datasources/correlations", nil)
+		c := &models.ReqContext{Context: &web.Context{Req: req, Resp: web.NewResponseWriter(req.Method, httptest.NewRecorder())}, SignedInUser: u}
+		resp := s.listResponse(c, "all", "", nil, func() ([]Correlation, error) {
+			return s.getCorrelations(ctx, GetCorrelationsQuery{OrgId: 1})
+		})
+		require.Equal(t, http.StatusOK, resp.Status())
+		var correlations []Correlation
+		require.NoError(t, json.Unmarshal(resp.Body(), &correlations))
+		labels := make([]string, 0, len(correlations))
+		for _, correlation := range correlations {
+			labels = append(labels, correlation.Label)
+		}
+		return labels
+	}
+
+	t.Run("rejects invalid roles and teams of other organizations", func(t *testing.T) {
+		_, err := create("owners", &CorrelationVisibility{Roles: []org.RoleType{"Owner"}})
+		require.ErrorIs(t, err, ErrInvalidVisibility)
+
+		_, err = create("other org", &CorrelationVisibility{Teams: []int64{otherOrg.Id}})
+		require.ErrorIs(t, err, ErrInvalidVisibility)
+	})
+
+	t.Run("lists the correlations visible to the user", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"public"}, list(&user.SignedInUser{OrgID: 1, OrgRole: org.RoleViewer}))
+		require.ElementsMatch(t, []string{"public", "editors"}, list(&user.SignedInUser{OrgID: 1, OrgRole: org.RoleEditor}))
+		require.ElementsMatch(t, []string{"public", "on-call"}, list(&user.SignedInUser{OrgID: 1, OrgRole: org.RoleViewer, Teams: []int64{oncall.Id}}))
+		require.ElementsMatch(t, []string{"public", "editors", "on-call"}, list(&user.SignedInUser{OrgID: 1, OrgRole: org.RoleAdmin}))
+	})
+
+	t.Run("hides the correlations that aren't visible to the user", func(t *testing.T) {
+		get := func(u *user.SignedInUser) int {
+			req := httptest.NewRequest(http.MethodGet, "/api/datasources/uid/source-uid/correlations/"+debugging.UID, nil)
+			req = web.SetURLParams(req, map[string]string{":uid": source.Uid, ":correlationUID": debugging.UID})
+			c := &models.ReqContext{Context: &web.Context{Req: req, Resp: web.NewResponseWriter(req.Method, httptest.NewRecorder())}, SignedInUser: u}
+			return s.getCorrelationHandler(c).Status()
+		}
+		require.Equal(t, http.StatusNotFound, get(&user.SignedInUser{OrgID: 1, OrgRole: org.RoleEditor}))
+		require.Equal(t, http.StatusOK, get(&user.SignedInUser{OrgID: 1, OrgRole: org.RoleViewer, Teams: []int64{oncall.Id}}))
+	})
+
+	t.Run("applies the visibility to all the reads of the service", func(t *testing.T) {
+		editor := &user.SignedInUser{OrgID: 1, OrgRole: org.RoleEditor}
+		oncallViewer := &user.SignedInUser{OrgID: 1, OrgRole: org.RoleViewer, Teams: []int64{oncall.Id}}
+
+		_, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: debugging.UID, SourceUID: source.Uid, OrgId: 1, SignedInUser: editor})
+		require.ErrorIs(t, err, ErrCorrelationNotFound)
+		_, err = s.GetCorrelationVersions(ctx, GetCorrelationVersionsQuery{UID: debugging.UID, SourceUID: source.Uid, OrgId: 1, SignedInUser: editor})
+		require.ErrorIs(t, err, ErrCorrelationNotFound)
+		versions, err := s.GetCorrelationVersions(ctx, GetCorrelationVersionsQuery{UID: debugging.UID, SourceUID: source.Uid, OrgId: 1, SignedInUser: oncallViewer})
+		require.NoError(t, err)
+		require.Len(t, versions, 1)
+
+		bundle, err := s.ExportCorrelations(ctx, ExportCorrelationsQuery{OrgId: 1, SignedInUser: editor})
+		require.NoError(t, err)
+		exported := make([]string, 0, len(bundle.Correlations))
+		for _, correlation := range bundle.Correlations {
+			exported = append(exported, correlation.Label)
+		}
+		require.ElementsMatch(t, []string{"public", "editors"}, exported)
+
+		bySource, err := s.GetCorrelationsBySourceUID(ctx, GetCorrelationsBySourceUIDQuery{SourceUID: source.Uid, OrgId: 1, SignedInUser: oncallViewer})
+		require.NoError(t, err)
+		require.Len(t, bySource, 2)
+
+		// reads without a user only get the correlations visible to all
+		all, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, "public", all[0].Label)
+	})
+
+	t.Run("leaves the hidden correlations out of the search before its limit", func(t *testing.T) {
+		results, err := s.SearchCorrelations(ctx, SearchCorrelationsQuery{
+			OrgId:        1,
+			Query:        "tickets",
+			Limit:        2,
+			SignedInUser: &user.SignedInUser{OrgID: 1, OrgRole: org.RoleViewer, Teams: []int64{oncall.Id}},
+		})
+		require.NoError(t, err)
+		labels := make([]string, 0, len(results))
+		for _, result := range results {
+			labels = append(labels, result.Correlation.Label)
+		}
+		require.ElementsMatch(t, []string{"public", "on-call"}, labels)
+	})
+
+	t.Run("makes the correlation visible to all when the visibility is cleared", func(t *testing.T) {
+		var cmd UpdateCorrelationCommand
+		require.NoError(t, json.Unmarshal([]byte(`{"visibility":null}`), &cmd))
+		cmd.UID = debugging.UID
+		cmd.SourceUID = source.Uid
+		cmd.OrgId = 1
+		updated, err := s.UpdateCorrelation(ctx, cmd)
+		require.NoError(t, err)
+		require.Nil(t, updated.Visibility)
+
+		stored, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: debugging.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Nil(t, stored.Visibility)
+		require.ElementsMatch(t, []string{"public", "on-call"}, list(&user.SignedInUser{OrgID: 1, OrgRole: org.RoleViewer}))
+	})
+}
+
+func TestIntegrationCorrelationHealth(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore, source, target, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	queries := &fakeQueryService{}
+	s.QueryService = queries
+	dataSources := s.DataSourceService.(*fakeDatasources.FakeDataSourceService)
+	correlation, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+		SourceUID: source.Uid,
+		TargetUID: &target.Uid,
+		OrgId:     1,
+		Config: CorrelationConfig{
+			Field:  CorrelationFields{"traceId"},
+			Type:   ConfigTypeQuery,
+			Target: map[string]interface{}{"query": "${traceId}"},
+		},
+	})
+	require.NoError(t, err)
+
+	statuses := func(health CorrelationHealth) map[string]CorrelationHealthStatus {
+		byName := make(map[string]CorrelationHealthStatus, len(health.Checks))
+		for _, check := range health.Checks {
+			byName[check.Name] = check.Status
+		}
+		return byName
+	}
+	query := CorrelationHealthQuery{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1, SignedInUser: &user.SignedInUser{OrgID: 1}}
+
+	t.Run("reports a healthy correlation", func(t *testing.T) {
+		health, err := s.CheckCorrelationHealth(ctx, query)
+		require.NoError(t, err)
+		require.True(t, health.Healthy)
+		require.Equal(t, map[string]CorrelationHealthStatus{
+			HealthCheckSource:       HealthOK,
+			HealthCheckTarget:       HealthOK,
+			HealthCheckConfig:       HealthOK,
+			HealthCheckPlaceholders: HealthOK,
+			HealthCheckTargetQuery:  HealthSkipped,
+		}, statuses(health))
+		require.Empty(t, queries.queries)
+	})
+
+	t.Run("runs the target query with validateTarget", func(t *testing.T) {
+		queries.err = errors.New("parse error")
+		t.Cleanup(func() { queries.err = nil })
+
+		withQuery := query
+		withQuery.ValidateTarget = true
+		health, err := s.CheckCorrelationHealth(ctx, withQuery)
+		require.NoError(t, err)
+		require.False(t, health.Healthy)
+		require.Equal(t, HealthError, statuses(health)[HealthCheckTargetQuery])
+		require.Len(t, queries.queries, 1)
+	})
+
+	t.Run("reports the checks that fail", func(t *testing.T) {
+		dataSources.DataSources = []*datasources.DataSource{source}
+		t.Cleanup(func() { dataSources.DataSources = []*datasources.DataSource{source, target} })
+		err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+			_, err := session.Exec("UPDATE correlation SET config = ? WHERE uid = ?", `{"type":"query","field":["traceId"],"target":{"query":"${spanId}"}}`, correlation.UID)
+			return err
+		})
+		require.NoError(t, err)
+
+		withQuery := query
+		withQuery.ValidateTarget = true
+		health, err := s.CheckCorrelationHealth(ctx, withQuery)
+		require.NoError(t, err)
+		require.False(t, health.Healthy)
+		require.Equal(t, map[string]CorrelationHealthStatus{
+			HealthCheckSource:       HealthOK,
+			HealthCheckTarget:       HealthError,
+			HealthCheckConfig:       HealthOK,
+			HealthCheckPlaceholders: HealthError,
+			HealthCheckTargetQuery:  HealthSkipped,
+		}, statuses(health))
+		require.Equal(t, "correlations.targetDataSourceNotFound", health.Checks[1].MessageID)
+		require.Equal(t, "correlations.invalidPlaceholder", health.Checks[3].MessageID)
+	})
+
+	t.Run("fails for a missing correlation", func(t *testing.T) {
+		missing := query
+		missing.UID = "missing"
+		_, err := s.CheckCorrelationHealth(ctx, missing)
+		require.ErrorIs(t, err, ErrCorrelationNotFound)
+	})
+}
+
+func TestIntegrationMergeTarget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, source, target, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	correlation, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+		SourceUID: source.Uid,
+		TargetUID: &target.Uid,
+		OrgId:     1,
+		Config: CorrelationConfig{
+			Field:  CorrelationFields{"job"},
+			Type:   ConfigTypeQuery,
+			Target: map[string]interface{}{"expr": `rate(requests{job="${job}"}[5m])`, "interval": "1m", "instant": false},
+		},
+	})
+	require.NoError(t, err)
+
+	update := func(body string, merge bool) (Correlation, error) {
+		var cmd UpdateCorrelationCommand
+		require.NoError(t, json.Unmarshal([]byte(body), &cmd))
+		cmd.UID = correlation.UID
+		cmd.SourceUID = source.Uid
+		cmd.OrgId = 1
+		cmd.MergeTarget = merge
+		return s.UpdateCorrelation(ctx, cmd)
+	}
+
+	t.Run("changes a single parameter of the target query", func(t *testing.T) {
+		_, err := update(`{"config": {"target": {"interval": "30s", "instant": null}}}`, true)
+		require.NoError(t, err)
+
+		stored, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"expr": `rate(requests{job="${job}"}[5m])`, "interval": "30s"}, stored.Config.Target)
+
+		versions, err := s.GetCorrelationVersions(ctx, GetCorrelationVersionsQuery{UID: correlation.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+	})
+
+	t.Run("replaces the target without mergeTarget", func(t *testing.T) {
+		updated, err := update(`{"config": {"target": {"interval": "10s"}}}`, false)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"interval": "10s"}, updated.Config.Target)
+	})
+}
+
+func TestIntegrationMigrateDerivedFields(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	prometheus := &datasources.DataSource{OrgId: 1, Uid: "prometheus-uid", Name: "prometheus", Type: "prometheus"}
+	_, loki, tempo, s := setupCorrelationsTest(t, prometheus)
+	ctx := context.Background()
+
+	derivedFields := []interface{}{
+		map[string]interface{}{"name": "traceID", "matcherRegex": `traceID=(\w+)`, "url": "${__value.raw}", "datasourceUid": tempo.Uid, "urlDisplayLabel": "View trace"},
+		map[string]interface{}{"name": "ticket", "matcherRegex": `ticket=(\d+)`, "url": "https://tickets.example.com/${__value.raw}"},
+		map[string]interface{}{"name": "spanID", "matcherRegex": `spanID=(\w+)(?=\s)`, "url": "${__value.raw}", "datasourceUid": tempo.Uid},
+	}
+	loki.JsonData = simplejson.NewFromAny(map[string]interface{}{"maxLines": 1000, "derivedFields": derivedFields})
+	s.listCache = localcache.New(listCacheTTL, listCacheCleanupInterval)
+
+	t.Run("rejects data sources other than Loki", func(t *testing.T) {
+		_, _, err := s.MigrateDerivedFields(ctx, MigrateDerivedFieldsCommand{SourceUID: prometheus.Uid, OrgId: 1})
+		require.ErrorIs(t, err, ErrNotLokiDataSource)
+	})
+
+	t.Run("returns the correlations to create on dry runs", func(t *testing.T) {
+		planned, skipped, err := s.MigrateDerivedFields(ctx, MigrateDerivedFieldsCommand{SourceUID: loki.Uid, OrgId: 1, DryRun: true, DeleteDerivedFields: true})
+		require.NoError(t, err)
+		require.Len(t, planned, 1)
+		require.Equal(t, "traceID", planned[0].Label)
+		require.Equal(t, tempo.Uid, *planned[0].TargetUID)
+		require.Equal(t, CorrelationFields{"Line"}, planned[0].Config.Field)
+		require.Equal(t, Transformations{{Type: TransformationRegex, Expression: `traceID=(\w+)`}}, planned[0].Config.Transformations)
+		require.Equal(t, map[string]interface{}{"query": "${__value.raw}"}, planned[0].Config.Target)
+		require.Equal(t, "View trace", planned[0].Config.Display.Title)
+
+		require.Len(t, skipped, 2)
+		require.Equal(t, "ticket", skipped[0].Name)
+		require.Equal(t, 2, skipped[1].Index)
+		require.Contains(t, skipped[1].Reason, "invalid expression")
+
+		correlations, err := s.GetCorrelationsBySourceUID(ctx, GetCorrelationsBySourceUIDQuery{SourceUID: loki.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Empty(t, correlations)
+		require.Len(t, loki.JsonData.Get("derivedFields").MustArray(), 3)
+	})
+
+	var migrated Correlation
+	t.Run("creates the correlations of the derived fields linking to a data source", func(t *testing.T) {
+		created, skipped, err := s.MigrateDerivedFields(ctx, MigrateDerivedFieldsCommand{SourceUID: loki.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, created, 1)
+		require.Len(t, skipped, 2)
+		migrated = created[0]
+
+		require.Len(t, loki.JsonData.Get("derivedFields").MustArray(), 3)
+	})
+
+	t.Run("skips the derived fields already migrated and deletes them", func(t *testing.T) {
+		created, skipped, err := s.MigrateDerivedFields(ctx, MigrateDerivedFieldsCommand{SourceUID: loki.Uid, OrgId: 1, DeleteDerivedFields: true})
+		require.NoError(t, err)
+		require.Empty(t, created)
+		require.Len(t, skipped, 3)
+		require.Equal(t, migrated.UID, skipped[0].UID)
+
+		kept := loki.JsonData.Get("derivedFields").MustArray()
+		require.Len(t, kept, 2)
+		require.Equal(t, derivedFields[1], kept[0])
+		require.Equal(t, derivedFields[2], kept[1])
+		require.Equal(t, 1000, loki.JsonData.Get("maxLines").MustInt())
+	})
+}
+
+func TestIntegrationCorrelationsOfOrg(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	// data source UIDs are unique within an org only
+	otherSource := &datasources.DataSource{OrgId: 2, Uid: "source-uid", Name: "source", Type: "loki"}
+	otherTarget := &datasources.DataSource{OrgId: 2, Uid: "target-uid", Name: "target", Type: "tempo"}
+	sqlStore, shared, target, s := setupCorrelationsTest(t, otherSource, otherTarget)
+	ctx := context.Background()
+	create := func(orgID int64, label string) Correlation {
+		correlation, err := s.CreateCorrelation(ctx, CreateCorrelationCommand{
+			SourceUID: shared.Uid,
+			TargetUID: &target.Uid,
+			OrgId:     orgID,
+			Label:     label,
+			Config:    CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery, Target: map[string]interface{}{"query": label}},
+		})
+		require.NoError(t, err)
+		return correlation
+	}
+	ofOrg1 := create(1, "org 1")
+	create(2, "org 2")
+	legacy := create(1, "legacy")
+	// the correlations stored before their org get it from their data sources
+	err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		_, err := session.Exec("UPDATE correlation SET org_id = 0 WHERE uid = ?", legacy.UID)
+		return err
+	})
+	require.NoError(t, err)
+
+	labels := func(correlations []Correlation) []string {
+		labels := make([]string, 0, len(correlations))
+		for _, correlation := range correlations {
+			labels = append(labels, correlation.Label)
+		}
+		sort.Strings(labels)
+		return labels
+	}
+
+	t.Run("stores the correlations with their org", func(t *testing.T) {
+		require.Equal(t, int64(1), ofOrg1.OrgId)
+	})
+
+	t.Run("lists the correlations of the org", func(t *testing.T) {
+		correlations, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, []string{"legacy", "org 1"}, labels(correlations))
+
+		correlations, err = s.GetCorrelationsBySourceUID(ctx, GetCorrelationsBySourceUIDQuery{SourceUID: shared.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, []string{"legacy", "org 1"}, labels(correlations))
+
+		bySource, err := s.GetCorrelationsBySourceUIDs(ctx, GetCorrelationsBySourceUIDsQuery{SourceUIDs: []string{shared.Uid}, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, []string{"legacy", "org 1"}, labels(bySource[shared.Uid]))
+
+		correlations, err = s.GetCorrelationsByTargetUID(ctx, GetCorrelationsByTargetUIDQuery{TargetUID: target.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, []string{"legacy", "org 1"}, labels(correlations))
+
+		results, err := s.SearchCorrelations(ctx, SearchCorrelationsQuery{Query: "org", OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, "org 1", results[0].Label)
+	})
+
+	t.Run("lists the correlations stored without their org in the orgs of their data sources", func(t *testing.T) {
+		correlations, err := s.GetCorrelations(ctx, GetCorrelationsQuery{OrgId: 2})
+		require.NoError(t, err)
+		require.Equal(t, []string{"legacy", "org 2"}, labels(correlations))
+	})
+}
+
+func TestIntegrationCorrelationEvents(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore, source, target, s := setupCorrelationsTest(t)
+	ctx := context.Background()
+
+	// the bus of the test database is shared by the tests, so the events are only recorded during this test
+	var published []interface{}
+	recording := true
+	t.Cleanup(func() { recording = false })
+	record := func(e interface{}) error {
+		if recording {
+			published = append(published, e)
+		}
+		return nil
+	}
+	sqlStore.Bus().AddEventListener(func(_ context.Context, e *events.CorrelationCreated) error { return record(e) })
+	sqlStore.Bus().AddEventListener(func(_ context.Context, e *events.CorrelationUpdated) error { return record(e) })
+	sqlStore.Bus().AddEventListener(func(_ context.Context, e *events.CorrelationDeleted) error { return record(e) })
+
+	targetUID := target.Uid
+	create := CreateCorrelationCommand{
+		SourceUID: source.Uid,
+		TargetUID: &targetUID,
+		OrgId:     1,
+		UserId:    2,
+		Label:     "logs to traces",
+		Config:    CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery, Target: map[string]interface{}{}},
+	}
+
+	t.Run("publishes the lifecycle of a correlation", func(t *testing.T) {
+		published = nil
+		created, err := s.CreateCorrelation(ctx, create)
+		require.NoError(t, err)
+
+		label := "updated"
+		_, err = s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: created.UID, SourceUID: source.Uid, OrgId: 1, UserId: 3, Label: &label})
+		require.NoError(t, err)
+
+		_, err = s.RestoreCorrelationVersion(ctx, RestoreCorrelationVersionCommand{UID: created.UID, SourceUID: source.Uid, OrgId: 1, UserId: 3, Version: 1})
+		require.NoError(t, err)
+
+		require.NoError(t, s.DeleteCorrelation(ctx, DeleteCorrelationCommand{UID: created.UID, SourceUID: source.Uid, OrgId: 1, UserId: 4}))
+
+		require.Len(t, published, 4)
+
+		createdEvent, ok := published[0].(*events.CorrelationCreated)
+		require.True(t, ok)
+		require.Equal(t, created.UID, createdEvent.UID)
+		require.Equal(t, source.Uid, createdEvent.SourceUID)
+		require.Equal(t, target.Uid, createdEvent.TargetUID)
+		require.Equal(t, int64(1), createdEvent.OrgID)
+		require.Equal(t, int64(2), createdEvent.UserID)
+
+		updatedEvent, ok := published[1].(*events.CorrelationUpdated)
+		require.True(t, ok)
+		require.Equal(t, []string{"label"}, updatedEvent.Fields)
+		require.False(t, updatedEvent.Restored)
+		require.Equal(t, int64(3), updatedEvent.UserID)
+
+		restoredEvent, ok := published[2].(*events.CorrelationUpdated)
+		require.True(t, ok)
+		require.Equal(t, []string{"label"}, restoredEvent.Fields)
+		require.True(t, restoredEvent.Restored)
+
+		deletedEvent, ok := published[3].(*events.CorrelationDeleted)
+		require.True(t, ok)
+		require.Equal(t, created.UID, deletedEvent.UID)
+		require.Equal(t, int64(4), deletedEvent.UserID)
+	})
+
+	t.Run("doesn't publish the updates leaving the correlation unchanged", func(t *testing.T) {
+		created, err := s.CreateCorrelation(ctx, create)
+		require.NoError(t, err)
+		published = nil
+
+		updated, err := s.UpdateCorrelation(ctx, UpdateCorrelationCommand{UID: created.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, created.Label, updated.Label)
+		require.Empty(t, published)
+
+		versions, err := s.GetCorrelationVersions(ctx, GetCorrelationVersionsQuery{UID: created.UID, SourceUID: source.Uid, OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, versions, 1)
+	})
+
+	t.Run("doesn't publish the changes of failed bulk commands", func(t *testing.T) {
+		published = nil
+		_, err := s.BulkCreateCorrelations(ctx, BulkCreateCorrelationsCommand{OrgId: 1, Correlations: []BulkCreateCorrelationsItem{
+			{SourceUID: source.Uid, CreateCorrelationCommand: create},
+			{SourceUID: "unknown-uid", CreateCorrelationCommand: create},
+		}})
+		require.Error(t, err)
+		require.Empty(t, published)
+
+		created, err := s.BulkCreateCorrelations(ctx, BulkCreateCorrelationsCommand{OrgId: 1, Correlations: []BulkCreateCorrelationsItem{
+			{SourceUID: source.Uid, CreateCorrelationCommand: create},
+			{SourceUID: source.Uid, CreateCorrelationCommand: create},
+		}})
+		require.NoError(t, err)
+		require.Len(t, published, 2)
+		require.Equal(t, created[1].UID, published[1].(*events.CorrelationCreated).UID)
+	})
+}
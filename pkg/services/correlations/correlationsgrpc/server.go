@@ -0,0 +1,195 @@
+package correlationsgrpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/grpcserver"
+	grpccontext "github.com/grafana/grafana/pkg/services/grpcserver/context"
+	"github.com/grafana/grafana/pkg/services/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProvideServer registers the correlations service on the gRPC server. The
+// calls are authenticated by the gRPC server as a service account, which
+// needs the same permissions as through the HTTP API.
+func ProvideServer(grpcServerProvider grpcserver.Provider, correlationsService correlations.Service, accessControl accesscontrol.AccessControl, contextHandler grpccontext.ContextHandler) CorrelationsServer {
+	s := &server{
+		correlations:   correlationsService,
+		accessControl:  accessControl,
+		contextHandler: contextHandler,
+	}
+	RegisterCorrelationsServer(grpcServerProvider.GetServer(), s)
+	return s
+}
+
+type server struct {
+	correlations   correlations.Service
+	accessControl  accesscontrol.AccessControl
+	contextHandler grpccontext.ContextHandler
+}
+
+func (s *server) GetCorrelation(ctx context.Context, req *GetCorrelationRequest) (*Correlation, error) {
+	u, err := s.authorize(ctx, accesscontrol.EvalPermission(correlations.ActionRead))
+	if err != nil {
+		return nil, err
+	}
+
+	correlation, err := s.correlations.GetCorrelation(ctx, correlations.GetCorrelationQuery{
+		UID:          req.Uid,
+		SourceUID:    req.SourceUid,
+		OrgId:        u.OrgID,
+		SignedInUser: u,
+	})
+	if err != nil {
+		return nil, grpcserver.ErrorToStatus(err)
+	}
+
+	return toProto(correlation)
+}
+
+func (s *server) ListCorrelations(ctx context.Context, req *ListCorrelationsRequest) (*ListCorrelationsResponse, error) {
+	u, err := s.authorize(ctx, accesscontrol.EvalPermission(correlations.ActionRead))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []correlations.Correlation
+	switch {
+	case req.SourceUid != "" && req.TargetUid != "":
+		return nil, status.Error(codes.InvalidArgument, "source_uid and target_uid can't be combined")
+	case req.SourceUid != "":
+		result, err = s.correlations.GetCorrelationsBySourceUID(ctx, correlations.GetCorrelationsBySourceUIDQuery{SourceUID: req.SourceUid, OrgId: u.OrgID, SignedInUser: u})
+	case req.TargetUid != "":
+		result, err = s.correlations.GetCorrelationsByTargetUID(ctx, correlations.GetCorrelationsByTargetUIDQuery{TargetUID: req.TargetUid, OrgId: u.OrgID, SignedInUser: u})
+	default:
+		result, err = s.correlations.GetCorrelations(ctx, correlations.GetCorrelationsQuery{OrgId: u.OrgID, SignedInUser: u})
+	}
+	if err != nil {
+		return nil, grpcserver.ErrorToStatus(err)
+	}
+
+	res := &ListCorrelationsResponse{Correlations: make([]*Correlation, 0, len(result))}
+	for _, correlation := range result {
+		c, err := toProto(correlation)
+		if err != nil {
+			return nil, err
+		}
+		res.Correlations = append(res.Correlations, c)
+	}
+	return res, nil
+}
+
+func (s *server) CreateCorrelation(ctx context.Context, req *CreateCorrelationRequest) (*Correlation, error) {
+	u, err := s.authorize(ctx, sourceEvaluator(correlations.ActionCreate, req.SourceUid))
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := correlations.CreateCorrelationCommand{}
+	if err := json.Unmarshal(req.Body, &cmd); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid body: %s", err)
+	}
+	cmd.SourceUID = req.SourceUid
+	cmd.OrgId = u.OrgID
+	cmd.UserId = u.UserID
+
+	if err := cmd.Validate(); err != nil {
+		return nil, grpcserver.ErrorToStatus(err)
+	}
+
+	correlation, err := s.correlations.CreateCorrelation(ctx, cmd)
+	if err != nil {
+		return nil, grpcserver.ErrorToStatus(err)
+	}
+
+	return toProto(correlation)
+}
+
+func (s *server) UpdateCorrelation(ctx context.Context, req *UpdateCorrelationRequest) (*Correlation, error) {
+	u, err := s.authorize(ctx, sourceEvaluator(correlations.ActionWrite, req.SourceUid))
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := correlations.UpdateCorrelationCommand{}
+	if err := json.Unmarshal(req.Body, &cmd); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid body: %s", err)
+	}
+	cmd.UID = req.Uid
+	cmd.SourceUID = req.SourceUid
+	cmd.OrgId = u.OrgID
+	cmd.UserId = u.UserID
+
+	correlation, err := s.correlations.UpdateCorrelation(ctx, cmd)
+	if err != nil {
+		return nil, grpcserver.ErrorToStatus(err)
+	}
+
+	return toProto(correlation)
+}
+
+func (s *server) DeleteCorrelation(ctx context.Context, req *DeleteCorrelationRequest) (*DeleteCorrelationResponse, error) {
+	u, err := s.authorize(ctx, sourceEvaluator(correlations.ActionDelete, req.SourceUid))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.correlations.DeleteCorrelation(ctx, correlations.DeleteCorrelationCommand{
+		UID:       req.Uid,
+		SourceUID: req.SourceUid,
+		OrgId:     u.OrgID,
+		UserId:    u.UserID,
+	})
+	if err != nil {
+		return nil, grpcserver.ErrorToStatus(err)
+	}
+
+	return &DeleteCorrelationResponse{}, nil
+}
+
+// authorize returns the service account of the call if it has the permissions
+func (s *server) authorize(ctx context.Context, evaluator accesscontrol.Evaluator) (*user.SignedInUser, error) {
+	u := s.contextHandler.GetUser(ctx)
+	if u == nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+
+	ok, err := s.accessControl.Evaluate(ctx, u, evaluator)
+	if err != nil {
+		return nil, grpcserver.ErrorToStatus(err)
+	}
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	}
+
+	return u, nil
+}
+
+func sourceEvaluator(action string, sourceUID string) accesscontrol.Evaluator {
+	return correlations.EvalManage(action, correlations.ScopeProvider.GetResourceScopeUID(sourceUID))
+}
+
+func toProto(correlation correlations.Correlation) (*Correlation, error) {
+	config, err := json.Marshal(correlation.Config)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode the correlation config: %s", err)
+	}
+
+	c := &Correlation{
+		Uid:         correlation.UID,
+		SourceUid:   correlation.SourceUID,
+		Label:       correlation.Label,
+		Description: correlation.Description,
+		Config:      config,
+		Provisioned: correlation.Provisioned,
+	}
+	if correlation.TargetUID != nil {
+		c.TargetUid = *correlation.TargetUID
+	}
+	return c, nil
+}
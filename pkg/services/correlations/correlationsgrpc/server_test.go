@@ -0,0 +1,150 @@
+package correlationsgrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/annotations/annotationstest"
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/grpcserver/grpcservertest"
+	"github.com/grafana/grafana/pkg/services/orgpolicy"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationCorrelationsServer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := sqlstore.InitTestDB(t)
+	ctx := context.Background()
+
+	source := &datasources.DataSource{OrgId: 1, Uid: "source-uid", Name: "source", Type: "loki", Created: time.Now(), Updated: time.Now()}
+	target := &datasources.DataSource{OrgId: 1, Uid: "target-uid", Name: "target", Type: "tempo", Created: time.Now(), Updated: time.Now()}
+	err := sqlStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		_, err := session.Insert(source, target)
+		return err
+	})
+	require.NoError(t, err)
+
+	svc := correlations.CorrelationsService{
+		SQLStore:          sqlStore,
+		QuotaService:      quotatest.NewQuotaServiceFake(),
+		Cfg:               setting.NewCfg(),
+		Annotations:       annotationstest.NewFakeAnnotationsRepo(),
+		DataSourceService: &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{source, target}},
+		OrgPolicies:       &orgpolicy.OrgPolicyService{SQLStore: sqlStore},
+	}
+
+	newClient := func(t *testing.T, u *user.SignedInUser) CorrelationsClient {
+		srv := grpcservertest.NewServer(t, u)
+		ProvideServer(srv, svc, acimpl.ProvideAccessControl(setting.NewCfg()), srv.ContextHandler)
+		return NewCorrelationsClient(srv.Dial(t))
+	}
+
+	sourceScope := correlations.ScopeProvider.GetResourceScopeUID(source.Uid)
+	editor := newClient(t, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: {
+		correlations.ActionRead:   {correlations.ScopeAll},
+		correlations.ActionCreate: {sourceScope},
+		correlations.ActionWrite:  {sourceScope},
+		correlations.ActionDelete: {sourceScope},
+	}}})
+
+	var uid string
+	t.Run("creates a correlation", func(t *testing.T) {
+		created, err := editor.CreateCorrelation(ctx, &CreateCorrelationRequest{
+			SourceUid: source.Uid,
+			Body:      []byte(`{"targetUID": "target-uid", "label": "logs to traces", "config": {"field": "traceId", "type": "query", "target": {}}}`),
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, created.Uid)
+		require.Equal(t, source.Uid, created.SourceUid)
+		require.Equal(t, target.Uid, created.TargetUid)
+		require.Equal(t, "logs to traces", created.Label)
+		require.JSONEq(t, `{"field": ["traceId"], "type": "query", "target": {}}`, string(created.Config))
+		uid = created.Uid
+	})
+
+	t.Run("rejects an invalid correlation", func(t *testing.T) {
+		_, err := editor.CreateCorrelation(ctx, &CreateCorrelationRequest{
+			SourceUid: source.Uid,
+			Body:      []byte(`{"config": {"field": "traceId", "type": "query", "target": {}}}`),
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("gets a correlation", func(t *testing.T) {
+		c, err := editor.GetCorrelation(ctx, &GetCorrelationRequest{SourceUid: source.Uid, Uid: uid})
+		require.NoError(t, err)
+		require.Equal(t, uid, c.Uid)
+
+		_, err = editor.GetCorrelation(ctx, &GetCorrelationRequest{SourceUid: source.Uid, Uid: "unknown"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("lists the correlations", func(t *testing.T) {
+		for _, req := range []*ListCorrelationsRequest{{}, {SourceUid: source.Uid}, {TargetUid: target.Uid}} {
+			res, err := editor.ListCorrelations(ctx, req)
+			require.NoError(t, err)
+			require.Len(t, res.Correlations, 1)
+			require.Equal(t, uid, res.Correlations[0].Uid)
+		}
+
+		_, err := editor.ListCorrelations(ctx, &ListCorrelationsRequest{SourceUid: source.Uid, TargetUid: target.Uid})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("updates a correlation", func(t *testing.T) {
+		updated, err := editor.UpdateCorrelation(ctx, &UpdateCorrelationRequest{
+			SourceUid: source.Uid,
+			Uid:       uid,
+			Body:      []byte(`{"label": "updated"}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "updated", updated.Label)
+	})
+
+	t.Run("requires permissions on the source data source", func(t *testing.T) {
+		_, err := editor.CreateCorrelation(ctx, &CreateCorrelationRequest{
+			SourceUid: target.Uid,
+			Body:      []byte(`{"targetUID": "source-uid", "config": {"field": "traceId", "type": "query", "target": {}}}`),
+		})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		viewer := newClient(t, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: {
+			correlations.ActionRead: {correlations.ScopeAll},
+		}}})
+		_, err = viewer.GetCorrelation(ctx, &GetCorrelationRequest{SourceUid: source.Uid, Uid: uid})
+		require.NoError(t, err)
+		_, err = viewer.DeleteCorrelation(ctx, &DeleteCorrelationRequest{SourceUid: source.Uid, Uid: uid})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("deletes a correlation", func(t *testing.T) {
+		_, err := editor.DeleteCorrelation(ctx, &DeleteCorrelationRequest{SourceUid: source.Uid, Uid: uid})
+		require.NoError(t, err)
+
+		_, err = editor.GetCorrelation(ctx, &GetCorrelationRequest{SourceUid: source.Uid, Uid: uid})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("rejects unauthenticated calls", func(t *testing.T) {
+		_, err := newClient(t, nil).ListCorrelations(ctx, &ListCorrelationsRequest{})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		noPermissions := newClient(t, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: {}}})
+		_, err = noPermissions.ListCorrelations(ctx, &ListCorrelationsRequest{})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
@@ -2,44 +2,107 @@ package correlations
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/util"
 )
 
+// withExistingTarget filters out the correlations whose target data source, joined as dst, doesn't exist.
+// External correlations have no target data source.
+const withExistingTarget = "(correlation.target_uid IS NULL OR dst.uid IS NOT NULL)"
+
+// ofSourceType filters the correlations applying to all the data sources of a plugin type in an org.
+// They have no source data source, so they are stored with an empty source UID and their org.
+const ofSourceType = "(correlation.source_uid = '' AND correlation.org_id = ? AND correlation.source_type = ?)"
+
+// ofOrg narrows the correlations down to an org, so that the lists use the (org_id, source_uid) and
+// (org_id, target_uid) indexes. The correlations of data sources stored before their org keep org 0 when
+// their source UID doesn't tell it, the joins on their data sources then tell whether they belong to the org.
+const ofOrg = "correlation.org_id IN (0, ?)"
+
+// withExistingSource filters out the correlations whose source data source, joined as dss, doesn't exist,
+// keeping the correlations of the data source types of the org.
+const withExistingSource = "(dss.uid IS NOT NULL OR (correlation.source_uid = '' AND correlation.org_id = ?))"
+
 // createCorrelation adds a correlation
 func (s CorrelationsService) createCorrelation(ctx context.Context, cmd CreateCorrelationCommand) (Correlation, error) {
+	defer s.invalidateLists()
+
 	correlation := Correlation{
-		UID:         util.GenerateShortUID(),
+		UID:         cmd.UID,
 		SourceUID:   cmd.SourceUID,
 		TargetUID:   cmd.TargetUID,
+		OrgId:       cmd.OrgId,
 		Label:       cmd.Label,
 		Description: cmd.Description,
 		Config:      cmd.Config,
+		Order:       cmd.Order,
+		Provisioned: cmd.Provisioned,
+		Tags:        normalizeTags(cmd.Tags),
+		Visibility:  cmd.Visibility.normalize(),
+	}
+	if cmd.SourceType != "" {
+		correlation.SourceUID = ""
+		correlation.SourceType = cmd.SourceType
+	}
+	if correlation.UID == "" {
+		correlation.UID = util.GenerateShortUID()
+	}
+
+	if cmd.ValidateTarget {
+		if err := s.dryRunTarget(ctx, cmd.SignedInUser, correlation); err != nil {
+			return Correlation{}, err
+		}
+	}
+
+	if !cmd.Provisioned {
+		if err := s.checkQuota(ctx, cmd); err != nil {
+			return Correlation{}, err
+		}
 	}
 
 	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
 		var err error
 
-		query := &datasources.GetDataSourceQuery{
-			OrgId: cmd.OrgId,
-			Uid:   cmd.SourceUID,
+		if cmd.SourceType == "" {
+			if err = s.checkSourceDataSource(ctx, cmd.OrgId, cmd.SourceUID, cmd.SkipReadOnlyCheck); err != nil {
+				return err
+			}
 		}
-		if err = s.DataSourceService.GetDataSource(ctx, query); err != nil {
-			return ErrSourceDataSourceDoesNotExists
+
+		if cmd.TargetUID != nil {
+			if err = s.checkTarget(ctx, cmd.OrgId, *cmd.TargetUID, correlation.Config); err != nil {
+				return err
+			}
 		}
 
-		if !cmd.SkipReadOnlyCheck && query.Result.ReadOnly {
-			return ErrSourceDataSourceReadOnly
+		if err = s.checkDashboardTarget(session, cmd.OrgId, correlation.Config); err != nil {
+			return err
 		}
 
-		if cmd.TargetUID != nil {
-			if err = s.DataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{
-				OrgId: cmd.OrgId,
-				Uid:   *cmd.TargetUID,
-			}); err != nil {
-				return ErrTargetDataSourceDoesNotExists
+		if err = checkVisibility(session, cmd.OrgId, correlation.Visibility); err != nil {
+			return err
+		}
+
+		if cmd.UID != "" {
+			// the UID is unique per source data source, and among the correlations of data source types
+			exists, err := session.Where("uid = ? AND source_uid = ?", correlation.UID, correlation.SourceUID).Exist(&Correlation{})
+			if err != nil {
+				return err
+			}
+			if exists {
+				return ErrCorrelationUIDExists.Errorf("correlation %s already exists", correlation.UID)
+			}
+		}
+
+		if s.Cfg.Correlations.RejectDuplicates && !cmd.Force && !cmd.Provisioned {
+			if err = checkDuplicate(session, correlation); err != nil {
+				return err
 			}
 		}
 
@@ -48,145 +111,415 @@ func (s CorrelationsService) createCorrelation(ctx context.Context, cmd CreateCo
 			return err
 		}
 
-		return nil
+		if err = saveTags(session, cmd.OrgId, correlation); err != nil {
+			return err
+		}
+
+		publishChange(session, cmd.OrgId, cmd.UserId, AuditActionCreated, nil, &correlation)
+		return saveVersion(session, cmd.OrgId, correlation, VersionActionCreated, 0, cmd.UserId)
 	})
 
 	if err != nil {
 		return Correlation{}, err
 	}
 
+	s.audit(ctx, cmd.OrgId, cmd.UserId, AuditActionCreated, nil, &correlation)
+
 	return correlation, nil
 }
 
+// checkTarget checks that the target data source exists, that the policies of
+// the org allow it as a target, and that the target query matches its schema
+func (s CorrelationsService) checkTarget(ctx context.Context, orgID int64, targetUID string, config CorrelationConfig) error {
+	targetQuery := &datasources.GetDataSourceQuery{
+		OrgId: orgID,
+		Uid:   targetUID,
+	}
+	if err := s.DataSourceService.GetDataSource(ctx, targetQuery); err != nil {
+		return ErrTargetDataSourceDoesNotExists.Errorf("target data source %s does not exist: %w", targetUID, err)
+	}
+
+	if err := s.OrgPolicies.CheckCorrelationTarget(ctx, orgID, targetQuery.Result); err != nil {
+		return err
+	}
+
+	return s.checkTargetSchema(ctx, targetQuery.Result, config)
+}
+
 func (s CorrelationsService) deleteCorrelation(ctx context.Context, cmd DeleteCorrelationCommand) error {
-	return s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
-		query := &datasources.GetDataSourceQuery{
-			OrgId: cmd.OrgId,
-			Uid:   cmd.SourceUID,
-		}
-		if err := s.DataSourceService.GetDataSource(ctx, query); err != nil {
-			return ErrSourceDataSourceDoesNotExists
+	defer s.invalidateLists()
+
+	correlation := correlationKey(cmd.UID, cmd.SourceUID, cmd.OrgId, cmd.SourceType)
+	key := correlation
+
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		if cmd.SourceType == "" {
+			if err := s.checkSourceDataSource(ctx, cmd.OrgId, cmd.SourceUID, cmd.SkipReadOnlyCheck); err != nil {
+				return err
+			}
 		}
 
-		if query.Result.ReadOnly {
-			return ErrSourceDataSourceReadOnly
+		// fetch the correlation before deleting it to audit its deletion
+		found, err := session.Get(&correlation)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrCorrelationNotFound.Errorf("correlation %s not found", cmd.UID)
+		}
+		if !cmd.SkipReadOnlyCheck && correlation.Provisioned {
+			return ErrCorrelationReadOnly.Errorf("correlation %s is provisioned", cmd.UID)
 		}
 
-		deletedCount, err := session.Delete(&Correlation{UID: cmd.UID, SourceUID: cmd.SourceUID})
+		deletedCount, err := session.Delete(&key)
 		if deletedCount == 0 {
-			return ErrCorrelationNotFound
+			return ErrCorrelationNotFound.Errorf("correlation %s not found", cmd.UID)
 		}
-		return err
+		if err != nil {
+			return err
+		}
+
+		if _, err = session.Delete(&CorrelationTag{OrgId: cmd.OrgId, CorrelationUID: cmd.UID, SourceUID: key.SourceUID}); err != nil {
+			return err
+		}
+
+		if _, err = session.Delete(&CorrelationVersion{CorrelationUID: cmd.UID, SourceUID: key.SourceUID}); err != nil {
+			return err
+		}
+
+		publishChange(session, cmd.OrgId, cmd.UserId, AuditActionDeleted, &correlation, nil)
+		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	s.audit(ctx, cmd.OrgId, cmd.UserId, AuditActionDeleted, &correlation, nil)
+
+	return nil
 }
 
 func (s CorrelationsService) updateCorrelation(ctx context.Context, cmd UpdateCorrelationCommand) (Correlation, error) {
-	correlation := Correlation{
-		UID:       cmd.UID,
-		SourceUID: cmd.SourceUID,
-	}
+	defer s.invalidateLists()
 
-	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
-		query := &datasources.GetDataSourceQuery{
-			OrgId: cmd.OrgId,
-			Uid:   cmd.SourceUID,
+	correlation := correlationKey(cmd.UID, cmd.SourceUID, cmd.OrgId, cmd.SourceType)
+	var before Correlation
+
+	// the target query runs outside of the transaction as the query service reads the data sources itself
+	if cmd.ValidateTarget {
+		updated, err := s.getCorrelation(ctx, GetCorrelationQuery{UID: cmd.UID, SourceUID: cmd.SourceUID, SourceType: cmd.SourceType, OrgId: cmd.OrgId})
+		if err != nil {
+			return Correlation{}, err
 		}
-		if err := s.DataSourceService.GetDataSource(ctx, query); err != nil {
-			return ErrSourceDataSourceDoesNotExists
+		if err := cmd.checkMerge(updated); err != nil {
+			return Correlation{}, err
 		}
-
-		if query.Result.ReadOnly {
-			return ErrSourceDataSourceReadOnly
+		cmd.apply(&updated)
+		if err := validateConfig(updated.TargetUID, updated.Config); err != nil {
+			return Correlation{}, err
+		}
+		if err := s.dryRunTarget(ctx, cmd.SignedInUser, updated); err != nil {
+			return Correlation{}, err
 		}
+	}
 
-		if cmd.Label == nil && cmd.Description == nil && (cmd.Config == nil || (cmd.Config.Field == nil && cmd.Config.Target == nil && cmd.Config.Type == nil)) {
-			return ErrUpdateCorrelationEmptyParams
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		if cmd.SourceType == "" {
+			if err := s.checkSourceDataSource(ctx, cmd.OrgId, cmd.SourceUID, cmd.SkipReadOnlyCheck); err != nil {
+				return err
+			}
 		}
+
 		found, err := session.Get(&correlation)
 		if !found {
-			return ErrCorrelationNotFound
+			return ErrCorrelationNotFound.Errorf("correlation %s not found", cmd.UID)
 		}
 		if err != nil {
 			return err
 		}
+		if !cmd.SkipReadOnlyCheck && correlation.Provisioned {
+			return ErrCorrelationReadOnly.Errorf("correlation %s is provisioned", cmd.UID)
+		}
+		if err := loadCorrelationTags(session, cmd.OrgId, &correlation); err != nil {
+			return err
+		}
+		before = correlation
+		if cmd.isEmpty() {
+			return nil
+		}
+		if err := cmd.checkMerge(correlation); err != nil {
+			return err
+		}
+
+		// keep the state before the first update of the correlations created without history
+		versioned, err := hasVersions(session, correlation)
+		if err != nil {
+			return err
+		}
+		if !versioned {
+			if err := saveVersion(session, cmd.OrgId, correlation, VersionActionCreated, 0, 0); err != nil {
+				return err
+			}
+		}
+
+		cmd.apply(&correlation)
+
+		if err := validateConfig(correlation.TargetUID, correlation.Config); err != nil {
+			return err
+		}
+		if err := validateTags(correlation.Tags); err != nil {
+			return err
+		}
+		if err := s.checkDashboardTarget(session, cmd.OrgId, correlation.Config); err != nil {
+			return err
+		}
+		if err := checkVisibility(session, cmd.OrgId, correlation.Visibility); err != nil {
+			return err
+		}
+		if cmd.ReplaceTarget && correlation.TargetUID != nil {
+			if err := s.checkTarget(ctx, cmd.OrgId, *correlation.TargetUID, correlation.Config); err != nil {
+				return err
+			}
+		} else if cmd.Config != nil && correlation.TargetUID != nil {
+			targetQuery := &datasources.GetDataSourceQuery{OrgId: cmd.OrgId, Uid: *correlation.TargetUID}
+			if err := s.DataSourceService.GetDataSource(ctx, targetQuery); err == nil {
+				if err := s.checkTargetSchema(ctx, targetQuery.Result, correlation.Config); err != nil {
+					return err
+				}
+			}
+		}
 
+		// the checks above may query the database with the session, which resets the columns to update
 		if cmd.Label != nil {
-			correlation.Label = *cmd.Label
 			session.MustCols("label")
 		}
 		if cmd.Description != nil {
-			correlation.Description = *cmd.Description
 			session.MustCols("description")
 		}
+		if cmd.Order != nil {
+			session.MustCols("order")
+		}
 		if cmd.Config != nil {
 			session.MustCols("config")
-			if cmd.Config.Field != nil {
-				correlation.Config.Field = *cmd.Config.Field
+		}
+		if cmd.Visibility != nil {
+			if correlation.Visibility == nil {
+				session.SetExpr("visibility", "NULL")
+			} else {
+				session.MustCols("visibility")
 			}
-			if cmd.Config.Type != nil {
-				correlation.Config.Type = *cmd.Config.Type
+		}
+		if cmd.ReplaceTarget {
+			if correlation.TargetUID == nil {
+				// xorm skips the nil pointers, even when they're required
+				session.SetExpr("target_uid", "NULL")
+			} else {
+				session.MustCols("target_uid")
 			}
-			if cmd.Config.Target != nil {
-				correlation.Config.Target = *cmd.Config.Target
+		}
+		updateCount, err := session.Where("uid = ? AND source_uid = ? AND org_id = ? AND source_type = ?", correlation.UID, correlation.SourceUID, correlation.OrgId, correlation.SourceType).Limit(1).Update(correlation)
+		if updateCount == 0 {
+			return ErrCorrelationNotFound.Errorf("correlation %s not found", cmd.UID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if cmd.Tags != nil {
+			if err := saveTags(session, cmd.OrgId, correlation); err != nil {
+				return err
 			}
 		}
 
-		updateCount, err := session.Where("uid = ? AND source_uid = ?", correlation.UID, correlation.SourceUID).Limit(1).Update(correlation)
-		if updateCount == 0 {
-			return ErrCorrelationNotFound
+		auditAction := AuditActionUpdated
+		action := VersionActionUpdated
+		if cmd.RestoredFrom != 0 {
+			auditAction = AuditActionRestored
+			action = VersionActionRestored
 		}
-		return err
+		publishChange(session, cmd.OrgId, cmd.UserId, auditAction, &before, &correlation)
+		return saveVersion(session, cmd.OrgId, correlation, action, cmd.RestoredFrom, cmd.UserId)
 	})
 
 	if err != nil {
 		return Correlation{}, err
 	}
 
+	if cmd.isEmpty() {
+		return correlation, nil
+	}
+
+	auditAction := AuditActionUpdated
+	if cmd.RestoredFrom != 0 {
+		auditAction = AuditActionRestored
+	}
+	s.audit(ctx, cmd.OrgId, cmd.UserId, auditAction, &before, &correlation)
+
 	return correlation, nil
 }
 
+// getCorrelation returns the correlation of the source data source, or of its
+// type when the data source has no correlation with the UID. The correlations
+// of a data source type are returned directly when the query sets the type.
 func (s CorrelationsService) getCorrelation(ctx context.Context, cmd GetCorrelationQuery) (Correlation, error) {
-	correlation := Correlation{
-		UID:       cmd.UID,
-		SourceUID: cmd.SourceUID,
+	correlation := Correlation{}
+
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		sourceType := cmd.SourceType
+		if sourceType == "" {
+			query := &datasources.GetDataSourceQuery{
+				OrgId: cmd.OrgId,
+				Uid:   cmd.SourceUID,
+			}
+			if err := s.DataSourceService.GetDataSource(ctx, query); err != nil {
+				return ErrSourceDataSourceDoesNotExists.Errorf("source data source %s does not exist: %w", cmd.SourceUID, err)
+			}
+
+			found, err := session.Select("correlation.*").Join("", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Join("LEFT", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Where(withExistingTarget+" AND correlation.uid = ? AND correlation.source_uid = ?", cmd.UID, cmd.SourceUID).Get(&correlation)
+			if err != nil {
+				return err
+			}
+			if found {
+				return loadCorrelationTags(session, cmd.OrgId, &correlation)
+			}
+			sourceType = query.Result.Type
+		}
+
+		found, err := session.Select("correlation.*").Join("LEFT", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Where(withExistingTarget+" AND correlation.uid = ? AND "+ofSourceType, cmd.UID, cmd.OrgId, sourceType).Get(&correlation)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrCorrelationNotFound.Errorf("correlation %s not found", cmd.UID)
+		}
+		return loadCorrelationTags(session, cmd.OrgId, &correlation)
+	})
+
+	if err != nil {
+		return Correlation{}, err
 	}
 
+	return correlation, nil
+}
+
+func (s CorrelationsService) getCorrelationsBySourceUID(ctx context.Context, cmd GetCorrelationsBySourceUIDQuery) ([]Correlation, error) {
+	correlations := make([]Correlation, 0)
+
 	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
 		query := &datasources.GetDataSourceQuery{
 			OrgId: cmd.OrgId,
 			Uid:   cmd.SourceUID,
 		}
 		if err := s.DataSourceService.GetDataSource(ctx, query); err != nil {
-			return ErrSourceDataSourceDoesNotExists
+			return ErrSourceDataSourceDoesNotExists.Errorf("source data source %s does not exist: %w", cmd.SourceUID, err)
 		}
 
-		found, err := session.Select("correlation.*").Join("", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Join("", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Where("correlation.uid = ? AND correlation.source_uid = ?", correlation.UID, correlation.SourceUID).Get(&correlation)
-		if !found {
-			return ErrCorrelationNotFound
+		// the correlations of the type of the data source apply to it as well
+		session.Select("correlation.*").Join("LEFT", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Join("LEFT", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Where(withExistingTarget+" AND ((dss.uid IS NOT NULL AND "+ofOrg+" AND correlation.source_uid = ?) OR "+ofSourceType+")", cmd.OrgId, cmd.SourceUID, cmd.OrgId, query.Result.Type)
+		filterByTags(session, cmd.OrgId, cmd.Tags)
+		if err := session.Asc("correlation.order", "correlation.uid").Find(&correlations); err != nil {
+			return err
 		}
-		return err
+		return loadTags(session, cmd.OrgId, correlations)
 	})
 
 	if err != nil {
-		return Correlation{}, err
+		return []Correlation{}, err
 	}
 
-	return correlation, nil
+	return correlations, nil
 }
 
-func (s CorrelationsService) getCorrelationsBySourceUID(ctx context.Context, cmd GetCorrelationsBySourceUIDQuery) ([]Correlation, error) {
+func (s CorrelationsService) getCorrelationsBySourceUIDs(ctx context.Context, cmd GetCorrelationsBySourceUIDsQuery) (map[string][]Correlation, error) {
+	result := make(map[string][]Correlation, len(cmd.SourceUIDs))
+	uidsByType := make(map[string][]string)
+	uids := make([]interface{}, 0, len(cmd.SourceUIDs))
+	for _, uid := range cmd.SourceUIDs {
+		if _, ok := result[uid]; ok {
+			continue
+		}
+		query := &datasources.GetDataSourceQuery{
+			OrgId: cmd.OrgId,
+			Uid:   uid,
+		}
+		if err := s.DataSourceService.GetDataSource(ctx, query); err != nil {
+			return nil, ErrSourceDataSourceDoesNotExists.Errorf("source data source %s does not exist: %w", uid, err)
+		}
+		result[uid] = make([]Correlation, 0)
+		uidsByType[query.Result.Type] = append(uidsByType[query.Result.Type], uid)
+		uids = append(uids, uid)
+	}
+	types := make([]interface{}, 0, len(uidsByType))
+	for sourceType := range uidsByType {
+		types = append(types, sourceType)
+	}
+
+	correlations := make([]Correlation, 0)
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		// the correlations of the types of the data sources apply to them as well
+		args := append(append(append([]interface{}{cmd.OrgId}, uids...), cmd.OrgId), types...)
+		session.Select("correlation.*").Join("LEFT", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Join("LEFT", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).
+			Where(withExistingTarget+" AND ((dss.uid IS NOT NULL AND "+ofOrg+" AND correlation.source_uid IN ("+placeholders(len(uids))+")) OR (correlation.source_uid = '' AND correlation.org_id = ? AND correlation.source_type IN ("+placeholders(len(types))+")))", args...)
+		filterByTags(session, cmd.OrgId, cmd.Tags)
+		if err := session.Asc("correlation.order", "correlation.uid").Find(&correlations); err != nil {
+			return err
+		}
+		return loadTags(session, cmd.OrgId, correlations)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, correlation := range correlations {
+		if correlation.SourceUID != "" {
+			result[correlation.SourceUID] = append(result[correlation.SourceUID], correlation)
+			continue
+		}
+		for _, uid := range uidsByType[correlation.SourceType] {
+			result[uid] = append(result[uid], correlation)
+		}
+	}
+	return result, nil
+}
+
+// placeholders returns the placeholders of a list of n arguments of an IN condition
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func (s CorrelationsService) getCorrelationsByTargetUID(ctx context.Context, cmd GetCorrelationsByTargetUIDQuery) ([]Correlation, error) {
 	correlations := make([]Correlation, 0)
 
 	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
 		query := &datasources.GetDataSourceQuery{
 			OrgId: cmd.OrgId,
-			Uid:   cmd.SourceUID,
+			Uid:   cmd.TargetUID,
 		}
 		if err := s.DataSourceService.GetDataSource(ctx, query); err != nil {
-			return ErrSourceDataSourceDoesNotExists
+			return ErrTargetDataSourceDoesNotExists.Errorf("target data source %s does not exist: %w", cmd.TargetUID, err)
 		}
 
-		return session.Select("correlation.*").Join("", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Join("", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Where("correlation.source_uid = ?", cmd.SourceUID).Find(&correlations)
+		if err := session.Select("correlation.*").Join("LEFT", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Where(ofOrg+" AND "+withExistingSource+" AND correlation.target_uid = ?", cmd.OrgId, cmd.OrgId, cmd.TargetUID).Asc("correlation.order", "correlation.uid").Find(&correlations); err != nil {
+			return err
+		}
+		return loadTags(session, cmd.OrgId, correlations)
+	})
+
+	if err != nil {
+		return []Correlation{}, err
+	}
+
+	return correlations, nil
+}
+
+func (s CorrelationsService) getCorrelationsBySourceType(ctx context.Context, cmd GetCorrelationsBySourceTypeQuery) ([]Correlation, error) {
+	correlations := make([]Correlation, 0)
+
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		if err := session.Select("correlation.*").Join("LEFT", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Where(withExistingTarget+" AND "+ofSourceType, cmd.OrgId, cmd.SourceType).Asc("correlation.order", "correlation.uid").Find(&correlations); err != nil {
+			return err
+		}
+		return loadTags(session, cmd.OrgId, correlations)
 	})
 
 	if err != nil {
@@ -200,7 +533,30 @@ func (s CorrelationsService) getCorrelations(ctx context.Context, cmd GetCorrela
 	correlations := make([]Correlation, 0)
 
 	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
-		return session.Select("correlation.*").Join("", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Join("", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Find(&correlations)
+		session.Select("correlation.*").Join("LEFT", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Join("LEFT", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Where(ofOrg+" AND "+withExistingSource+" AND "+withExistingTarget, cmd.OrgId, cmd.OrgId)
+		filterByTags(session, cmd.OrgId, cmd.Tags)
+		if err := session.Asc("correlation.order", "correlation.uid").Find(&correlations); err != nil {
+			return err
+		}
+		if err := loadTags(session, cmd.OrgId, correlations); err != nil || !cmd.WithUsage {
+			return err
+		}
+
+		summaries := make([]CorrelationUsageSummary, 0)
+		if err := session.Where("org_id = ?", cmd.OrgId).Find(&summaries); err != nil {
+			return err
+		}
+		byCorrelation := make(map[[2]string]CorrelationUsageSummary, len(summaries))
+		for _, summary := range summaries {
+			byCorrelation[[2]string{summary.CorrelationUID, summary.SourceUID}] = summary
+		}
+
+		// correlations that were never followed get an empty usage
+		for i := range correlations {
+			summary := byCorrelation[[2]string{correlations[i].UID, correlations[i].SourceUID}]
+			correlations[i].Usage = &summary
+		}
+		return nil
 	})
 	if err != nil {
 		return []Correlation{}, err
@@ -210,15 +566,330 @@ func (s CorrelationsService) getCorrelations(ctx context.Context, cmd GetCorrela
 }
 
 func (s CorrelationsService) deleteCorrelationsBySourceUID(ctx context.Context, cmd DeleteCorrelationsBySourceUIDCommand) error {
+	defer s.invalidateLists()
+
 	return s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
-		_, err := session.Delete(&Correlation{SourceUID: cmd.SourceUID})
-		return err
+		if _, err := session.Delete(&Correlation{SourceUID: cmd.SourceUID}); err != nil {
+			return err
+		}
+		if err := deleteOrphanedVersions(session); err != nil {
+			return err
+		}
+		return deleteOrphanedTags(session)
 	})
 }
 
 func (s CorrelationsService) deleteCorrelationsByTargetUID(ctx context.Context, cmd DeleteCorrelationsByTargetUIDCommand) error {
+	defer s.invalidateLists()
+
 	return s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
-		_, err := session.Delete(&Correlation{TargetUID: &cmd.TargetUID})
+		if _, err := session.Delete(&Correlation{TargetUID: &cmd.TargetUID}); err != nil {
+			return err
+		}
+		if err := deleteOrphanedVersions(session); err != nil {
+			return err
+		}
+		return deleteOrphanedTags(session)
+	})
+}
+
+// correlationsBySourceTables are the tables referencing the correlations by their source UID, within an org
+var correlationsBySourceTables = []string{"correlation_version", "correlation_resolution", "correlation_usage", "correlation_usage_summary", "correlation_tag"}
+
+// updateCorrelationsSourceUID moves the correlations of the org to the new UID
+// of their source data source. The correlations stored without their org are
+// left alone when their previous source UID still exists, i.e. in another org.
+func (s CorrelationsService) updateCorrelationsSourceUID(ctx context.Context, cmd UpdateCorrelationsSourceUIDCommand) error {
+	defer s.invalidateLists()
+
+	return s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		if _, err := session.Exec("UPDATE correlation SET source_uid = ? WHERE source_uid = ? AND (org_id = ? OR (org_id = 0 AND source_uid NOT IN (SELECT uid FROM data_source)))", cmd.SourceUID, cmd.PreviousUID, cmd.OrgId); err != nil {
+			return err
+		}
+
+		for _, table := range correlationsBySourceTables {
+			if _, err := session.Exec(fmt.Sprintf("UPDATE %s SET source_uid = ? WHERE org_id = ? AND source_uid = ?", table), cmd.SourceUID, cmd.OrgId, cmd.PreviousUID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// updateCorrelationsTargetUID points the correlations of the org at the new UID
+// of their target data source, with the same rule as updateCorrelationsSourceUID
+// for the correlations stored without their org.
+func (s CorrelationsService) updateCorrelationsTargetUID(ctx context.Context, cmd UpdateCorrelationsTargetUIDCommand) error {
+	defer s.invalidateLists()
+
+	return s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		if _, err := session.Exec("UPDATE correlation SET target_uid = ? WHERE target_uid = ? AND (org_id = ? OR (org_id = 0 AND target_uid NOT IN (SELECT uid FROM data_source)))", cmd.TargetUID, cmd.PreviousUID, cmd.OrgId); err != nil {
+			return err
+		}
+
+		_, err := session.Exec("UPDATE correlation_version SET target_uid = ? WHERE org_id = ? AND target_uid = ?", cmd.TargetUID, cmd.OrgId, cmd.PreviousUID)
+		return err
+	})
+}
+
+// deleteCorrelationsByDashboardUID deletes the dashboard correlations of the
+// organization targeting the dashboard. Their target is stored in their config,
+// so the database narrows the correlations down to the ones whose config may
+// contain the dashboard UID, which are then checked.
+func (s CorrelationsService) deleteCorrelationsByDashboardUID(ctx context.Context, cmd DeleteCorrelationsByDashboardUIDCommand) error {
+	defer s.invalidateLists()
+
+	return s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		correlations := make([]Correlation, 0)
+		err := session.Select("correlation.*").Join("LEFT", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).
+			Where(ofOrg+" AND "+withExistingSource, cmd.OrgId, cmd.OrgId).
+			And("correlation.config "+s.SQLStore.GetDialect().LikeStr()+" ?", searchPattern(cmd.DashboardUID)).Find(&correlations)
+		if err != nil {
+			return err
+		}
+
+		for _, correlation := range correlations {
+			if correlation.Config.Type != ConfigTypeDashboard || dashboardUID(correlation.Config) != cmd.DashboardUID {
+				continue
+			}
+			key := correlationKey(correlation.UID, correlation.SourceUID, correlation.OrgId, correlation.SourceType)
+			if _, err := session.Delete(&key); err != nil {
+				return err
+			}
+		}
+		if err := deleteOrphanedVersions(session); err != nil {
+			return err
+		}
+		return deleteOrphanedTags(session)
+	})
+}
+
+// checkDashboardTarget checks that the dashboard targeted by dashboard
+// correlations exists in the organization
+func (s CorrelationsService) checkDashboardTarget(session *sqlstore.DBSession, orgID int64, config CorrelationConfig) error {
+	if config.Type != ConfigTypeDashboard {
+		return nil
+	}
+
+	uid := dashboardUID(config)
+	exists, err := session.Table("dashboard").Where("org_id = ? AND uid = ? AND is_folder = ?", orgID, uid, s.SQLStore.GetDialect().BooleanStr(false)).Exist()
+	if err != nil {
 		return err
+	}
+	if !exists {
+		return ErrTargetDashboardDoesNotExists.Errorf("target dashboard %s does not exist", uid)
+	}
+	return nil
+}
+
+func (s CorrelationsService) saveResolution(ctx context.Context, resolution CorrelationResolution) error {
+	return s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		_, err := session.Insert(&resolution)
+		return err
+	})
+}
+
+func (s CorrelationsService) getCorrelationUsage(ctx context.Context, cmd GetCorrelationUsageQuery) ([]CorrelationUsage, error) {
+	if _, err := s.getCorrelation(ctx, GetCorrelationQuery{UID: cmd.UID, SourceUID: cmd.SourceUID, OrgId: cmd.OrgId}); err != nil {
+		return nil, err
+	}
+
+	usage := make([]CorrelationUsage, 0)
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		return session.Where("org_id = ? AND correlation_uid = ? AND source_uid = ?", cmd.OrgId, cmd.UID, cmd.SourceUID).Asc("day").Find(&usage)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// deleteOrphanedCorrelations deletes the correlations whose source or target data source doesn't exist anymore,
+// which the data source deletion events may have missed. Returns the number of deleted correlations.
+func (s CorrelationsService) deleteOrphanedCorrelations(ctx context.Context) (int64, error) {
+	defer s.invalidateLists()
+
+	var deleted int64
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		res, err := session.Exec(`DELETE FROM correlation WHERE
+			(source_uid <> '' AND source_uid NOT IN (SELECT uid FROM data_source)) OR
+			(target_uid IS NOT NULL AND target_uid NOT IN (SELECT uid FROM data_source))`)
+		if err != nil {
+			return err
+		}
+
+		if deleted, err = res.RowsAffected(); err != nil {
+			return err
+		}
+		if err := deleteOrphanedVersions(session); err != nil {
+			return err
+		}
+		return deleteOrphanedTags(session)
+	})
+
+	return deleted, err
+}
+
+// aggregateResolutions adds up to limit recorded resolutions, oldest first, to the daily usage of their
+// correlations and deletes them. Returns the number of aggregated resolutions.
+func (s CorrelationsService) aggregateResolutions(ctx context.Context, limit int) (int, error) {
+	defer s.invalidateLists()
+
+	var aggregated int
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		resolutions := make([]CorrelationResolution, 0)
+		if err := session.Asc("id").Limit(limit).Find(&resolutions); err != nil {
+			return err
+		}
+		if len(resolutions) == 0 {
+			return nil
+		}
+
+		usage := make([]*CorrelationUsage, 0)
+		byDay := make(map[CorrelationUsage]*CorrelationUsage)
+		for _, r := range resolutions {
+			key := CorrelationUsage{
+				OrgId:          r.OrgId,
+				CorrelationUID: r.CorrelationUID,
+				SourceUID:      r.SourceUID,
+				Day:            time.Unix(r.ResolvedAt, 0).UTC().Format("2006-01-02"),
+			}
+			if u, ok := byDay[key]; ok {
+				u.Resolutions++
+				continue
+			}
+
+			u := key
+			u.Resolutions = 1
+			byDay[key] = &u
+			usage = append(usage, &u)
+		}
+
+		for _, u := range usage {
+			res, err := session.Exec("UPDATE correlation_usage SET resolutions = resolutions + ? WHERE correlation_uid = ? AND source_uid = ? AND day = ?",
+				u.Resolutions, u.CorrelationUID, u.SourceUID, u.Day)
+			if err != nil {
+				return err
+			}
+
+			updated, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if updated == 0 {
+				if _, err := session.Insert(u); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := addUsageSummaries(session, resolutions); err != nil {
+			return err
+		}
+
+		if _, err := session.Where("id <= ?", resolutions[len(resolutions)-1].Id).Delete(&CorrelationResolution{}); err != nil {
+			return err
+		}
+
+		aggregated = len(resolutions)
+		return nil
+	})
+
+	return aggregated, err
+}
+
+// addUsageSummaries adds the resolutions to the total usage of their correlations
+func addUsageSummaries(session *sqlstore.DBSession, resolutions []CorrelationResolution) error {
+	summaries := make([]*CorrelationUsageSummary, 0)
+	byCorrelation := make(map[CorrelationUsageSummary]*CorrelationUsageSummary)
+	for _, r := range resolutions {
+		key := CorrelationUsageSummary{OrgId: r.OrgId, CorrelationUID: r.CorrelationUID, SourceUID: r.SourceUID}
+		summary, ok := byCorrelation[key]
+		if !ok {
+			summary = &key
+			byCorrelation[key] = summary
+			summaries = append(summaries, summary)
+		}
+		summary.Resolutions++
+		if r.ResolvedAt > summary.LastResolvedAt {
+			summary.LastResolvedAt = r.ResolvedAt
+		}
+	}
+
+	for _, summary := range summaries {
+		res, err := session.Exec(`UPDATE correlation_usage_summary SET resolutions = resolutions + ?,
+			last_resolved_at = CASE WHEN last_resolved_at < ? THEN ? ELSE last_resolved_at END
+			WHERE correlation_uid = ? AND source_uid = ?`,
+			summary.Resolutions, summary.LastResolvedAt, summary.LastResolvedAt, summary.CorrelationUID, summary.SourceUID)
+		if err != nil {
+			return err
+		}
+
+		updated, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			if _, err := session.Insert(summary); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkSourceDataSource checks that the source data source exists and can have correlations added, updated or deleted
+func (s CorrelationsService) checkSourceDataSource(ctx context.Context, orgID int64, sourceUID string, skipReadOnlyCheck bool) error {
+	query := &datasources.GetDataSourceQuery{
+		OrgId: orgID,
+		Uid:   sourceUID,
+	}
+	if err := s.DataSourceService.GetDataSource(ctx, query); err != nil {
+		return ErrSourceDataSourceDoesNotExists.Errorf("source data source %s does not exist: %w", sourceUID, err)
+	}
+
+	if !skipReadOnlyCheck && query.Result.ReadOnly {
+		return ErrSourceDataSourceReadOnly.Errorf("source data source %s is read only", sourceUID)
+	}
+	return nil
+}
+
+// correlationKey identifies a correlation of a data source, or of a data source
+// type of the org when the type is set
+func correlationKey(uid string, sourceUID string, orgID int64, sourceType string) Correlation {
+	if sourceType == "" {
+		return Correlation{UID: uid, SourceUID: sourceUID}
+	}
+	return Correlation{UID: uid, OrgId: orgID, SourceType: sourceType}
+}
+
+// checkQuota fails if the org, or the source data source, reached its quota of
+// correlations. The quota of the data sources isn't handled by the quota
+// service as it only knows about the global, org and user scopes.
+func (s CorrelationsService) checkQuota(ctx context.Context, cmd CreateCorrelationCommand) error {
+	reached, err := s.QuotaService.CheckQuotaReached(ctx, "correlation", &quota.ScopeParameters{OrgID: cmd.OrgId, UserID: cmd.UserId})
+	if err != nil {
+		return err
+	}
+	if reached {
+		return ErrCorrelationsQuotaReached.Errorf("org %d reached its quota of correlations", cmd.OrgId)
+	}
+
+	// the correlations of a data source type don't count towards the quota of its data sources
+	if cmd.SourceType != "" || !s.Cfg.Quota.Enabled || s.Cfg.Quota.DataSource == nil || s.Cfg.Quota.DataSource.Correlation < 0 {
+		return nil
+	}
+	limit := s.Cfg.Quota.DataSource.Correlation
+	return s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		used, err := session.Table("correlation").Join("", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Where("correlation.source_uid = ?", cmd.SourceUID).Count()
+		if err != nil {
+			return err
+		}
+		if used >= limit {
+			return ErrCorrelationsQuotaReached.Errorf("data source %s reached its quota of %d correlations", cmd.SourceUID, limit)
+		}
+		return nil
 	})
 }
@@ -0,0 +1,114 @@
+package correlations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// QueryService runs the target queries of correlations, implemented by query.Service
+type QueryService interface {
+	QueryData(ctx context.Context, user *user.SignedInUser, skipCache bool, reqDTO dtos.MetricRequest, handleExpressions bool) (*backend.QueryDataResponse, error)
+}
+
+// sampleValue replaces the placeholders of the source values when running the
+// target query. It's a number so that it fits both string and numeric contexts.
+const sampleValue = "1"
+
+// sampleBuiltins replace the placeholders of the built-in variables
+var sampleBuiltins = map[string]string{
+	"__interval":      "1m",
+	"__interval_ms":   "60000",
+	"__rate_interval": "1m",
+	"__range":         "5m",
+	"__range_s":       "300",
+	"__range_ms":      "300000",
+}
+
+// dryRunRefID identifies the target query in the response of the data source,
+// followed by the index of the query for the other queries of a list
+const dryRunRefID = "correlation"
+
+// dryRunTarget runs the target queries of query correlations against their
+// target data source, with sample values for the placeholders, and fails if the
+// data source rejects one of them.
+func (s CorrelationsService) dryRunTarget(ctx context.Context, signedInUser *user.SignedInUser, correlation Correlation) error {
+	if correlation.Config.Type != ConfigTypeQuery || correlation.TargetUID == nil {
+		return nil
+	}
+
+	queries := []*simplejson.Json{}
+	refIDs := []string{}
+	for i, target := range correlation.Config.targetQueries() {
+		query, ok := withSampleValues(target).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		refID := dryRunRefID
+		if i > 0 {
+			refID = fmt.Sprintf("%s-%d", dryRunRefID, i)
+		}
+		query["refId"] = refID
+		query["datasource"] = map[string]interface{}{"uid": *correlation.TargetUID}
+		queries = append(queries, simplejson.NewFromAny(query))
+		refIDs = append(refIDs, refID)
+	}
+	if len(queries) == 0 {
+		return nil
+	}
+
+	resp, err := s.QueryService.QueryData(ctx, signedInUser, true, dtos.MetricRequest{
+		From:    "now-5m",
+		To:      "now",
+		Queries: queries,
+	}, false)
+	for _, refID := range refIDs {
+		if err != nil {
+			break
+		}
+		err = resp.Responses[refID].Error
+	}
+	if err != nil {
+		return ErrInvalidTargetQuery.Build(errutil.TemplateData{
+			Public: map[string]interface{}{"error": err.Error()},
+			Error:  err,
+		})
+	}
+	return nil
+}
+
+// withSampleValues returns a copy of the target with its placeholders replaced by sample values
+func withSampleValues(target interface{}) interface{} {
+	switch value := target.(type) {
+	case string:
+		return placeholderRegexp.ReplaceAllStringFunc(value, func(placeholder string) string {
+			name := strings.TrimSuffix(strings.TrimPrefix(placeholder, "${"), "}")
+			name, _, _ = strings.Cut(name, ":")
+			if sample, ok := sampleBuiltins[name]; ok {
+				return sample
+			}
+			return sampleValue
+		})
+	case map[string]interface{}:
+		sampled := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			sampled[key] = withSampleValues(item)
+		}
+		return sampled
+	case []interface{}:
+		sampled := make([]interface{}, len(value))
+		for i, item := range value {
+			sampled[i] = withSampleValues(item)
+		}
+		return sampled
+	default:
+		return target
+	}
+}
@@ -0,0 +1,73 @@
+package correlations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// checkDuplicate rejects a correlation with the same source, target, field and
+// target query as an existing one
+func checkDuplicate(session *sqlstore.DBSession, correlation Correlation) error {
+	hash, err := targetHash(correlation.Config)
+	if err != nil {
+		return err
+	}
+
+	query := session.Where("source_uid = ?", correlation.SourceUID)
+	if correlation.SourceUID == "" {
+		query = query.And("org_id = ? AND source_type = ?", correlation.OrgId, correlation.SourceType)
+	}
+	if correlation.TargetUID == nil {
+		query = query.And("target_uid IS NULL")
+	} else {
+		query = query.And("target_uid = ?", *correlation.TargetUID)
+	}
+	var candidates []Correlation
+	if err := query.Find(&candidates); err != nil {
+		return err
+	}
+
+	field := fieldKey(correlation.Config.Field)
+	for _, candidate := range candidates {
+		if fieldKey(candidate.Config.Field) != field {
+			continue
+		}
+		candidateHash, err := targetHash(candidate.Config)
+		if err != nil {
+			return err
+		}
+		if candidateHash == hash {
+			return ErrCorrelationDuplicate.Build(errutil.TemplateData{
+				Public: map[string]interface{}{"uid": candidate.UID},
+				Error:  fmt.Errorf("correlation %s has the same source, target, field and target query", candidate.UID),
+			})
+		}
+	}
+	return nil
+}
+
+// targetHash hashes the target queries of a correlation, so that a single
+// target and a list of it are the same. Maps are encoded with sorted keys, so
+// equal targets get the same hash whatever their key order.
+func targetHash(config CorrelationConfig) (string, error) {
+	data, err := json.Marshal(config.targetQueries())
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// fieldKey identifies the fields of a correlation whatever their order
+func fieldKey(fields CorrelationFields) string {
+	sorted := append([]string{}, fields...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "\x00")
+}
@@ -0,0 +1,64 @@
+package correlations
+
+import (
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// publishChange publishes events.CorrelationCreated, events.CorrelationUpdated
+// or events.CorrelationDeleted for the change of the correlation once the
+// transaction of the change, or of the bulk command it's part of, is committed.
+// action is one of the audit actions, before is nil for created correlations
+// and after is nil for deleted ones.
+func publishChange(session *sqlstore.DBSession, orgID, userID int64, action string, before, after *Correlation) {
+	now := time.Now()
+
+	switch action {
+	case AuditActionCreated:
+		session.PublishAfterCommit(&events.CorrelationCreated{
+			Timestamp:  now,
+			UID:        after.UID,
+			SourceUID:  after.SourceUID,
+			SourceType: after.SourceType,
+			TargetUID:  targetUID(after),
+			OrgID:      orgID,
+			UserID:     userID,
+		})
+	case AuditActionDeleted:
+		session.PublishAfterCommit(&events.CorrelationDeleted{
+			Timestamp:  now,
+			UID:        before.UID,
+			SourceUID:  before.SourceUID,
+			SourceType: before.SourceType,
+			OrgID:      orgID,
+			UserID:     userID,
+		})
+	default:
+		fields := make([]string, 0)
+		for field := range correlationDiff(before, after) {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		session.PublishAfterCommit(&events.CorrelationUpdated{
+			Timestamp:  now,
+			UID:        after.UID,
+			SourceUID:  after.SourceUID,
+			SourceType: after.SourceType,
+			TargetUID:  targetUID(after),
+			OrgID:      orgID,
+			UserID:     userID,
+			Fields:     fields,
+			Restored:   action == AuditActionRestored,
+		})
+	}
+}
+
+func targetUID(correlation *Correlation) string {
+	if correlation.TargetUID == nil {
+		return ""
+	}
+	return *correlation.TargetUID
+}
@@ -0,0 +1,62 @@
+package correlations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+// ExpandDataSources is the value of the expand parameter of the lists of
+// correlations adding the name and type of their data sources
+const ExpandDataSources = "datasources"
+
+// expandDataSources tells whether the request lists the correlations with
+// their data sources. The expand parameter can be repeated or comma-separated.
+func expandDataSources(c *models.ReqContext) (bool, error) {
+	expand := false
+	for _, values := range c.QueryStrings("expand") {
+		for _, value := range strings.Split(values, ",") {
+			switch strings.TrimSpace(value) {
+			case ExpandDataSources:
+				expand = true
+			case "":
+			default:
+				return false, ErrInvalidExpand.Errorf("unknown expand value %q", value)
+			}
+		}
+	}
+	return expand, nil
+}
+
+// dataSourcesByUID returns the data sources of the organization by UID, so
+// that expanding a list of correlations reads them once
+func (s CorrelationsService) dataSourcesByUID(ctx context.Context, orgID int64) (map[string]*datasources.DataSource, error) {
+	query := &datasources.GetDataSourcesQuery{OrgId: orgID}
+	if err := s.DataSourceService.GetDataSources(ctx, query); err != nil {
+		return nil, err
+	}
+
+	byUID := make(map[string]*datasources.DataSource, len(query.Result))
+	for _, ds := range query.Result {
+		byUID[ds.Uid] = ds
+	}
+	return byUID, nil
+}
+
+// withDataSources sets the source and target data sources of the correlations.
+// The correlations of a data source type have no source data source.
+func withDataSources(correlations []Correlation, dataSources map[string]*datasources.DataSource) {
+	for i := range correlations {
+		if ds, ok := dataSources[correlations[i].SourceUID]; ok {
+			correlations[i].SourceDataSource = &CorrelationDataSource{UID: ds.Uid, Name: ds.Name, Type: ds.Type}
+		}
+		if correlations[i].TargetUID == nil {
+			continue
+		}
+		if ds, ok := dataSources[*correlations[i].TargetUID]; ok {
+			correlations[i].TargetDataSource = &CorrelationDataSource{UID: ds.Uid, Name: ds.Name, Type: ds.Type}
+		}
+	}
+}
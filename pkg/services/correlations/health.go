@@ -0,0 +1,102 @@
+package correlations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// CheckCorrelationHealth checks that the source and target of the correlation
+// still exist and that its config is valid, as when it was saved, and
+// optionally runs its target query. The checks that fail are reported rather
+// than returned, only a missing correlation is an error.
+func (s CorrelationsService) CheckCorrelationHealth(ctx context.Context, query CorrelationHealthQuery) (CorrelationHealth, error) {
+	correlation, err := s.getCorrelation(ctx, GetCorrelationQuery{UID: query.UID, SourceUID: query.SourceUID, OrgId: query.OrgId})
+	if err != nil {
+		return CorrelationHealth{}, err
+	}
+
+	health := CorrelationHealth{UID: correlation.UID, SourceUID: correlation.SourceUID, Healthy: true, Checks: []CorrelationHealthCheck{}}
+	report := func(check CorrelationHealthCheck) {
+		if check.Status == HealthError {
+			health.Healthy = false
+		}
+		health.Checks = append(health.Checks, check)
+	}
+
+	if correlation.SourceUID == "" {
+		report(skippedCheck(HealthCheckSource, "the correlation applies to all the data sources of type %s", correlation.SourceType))
+	} else {
+		report(healthCheck(HealthCheckSource, s.checkSourceDataSource(ctx, query.OrgId, correlation.SourceUID, true)))
+	}
+
+	target := s.checkHealthTarget(ctx, query.OrgId, correlation)
+	report(target)
+	report(healthCheck(HealthCheckConfig, validateConfigOptions(correlation.TargetUID, correlation.Config)))
+	report(healthCheck(HealthCheckPlaceholders, validatePlaceholders(correlation.Config)))
+
+	switch {
+	case !query.ValidateTarget:
+		report(skippedCheck(HealthCheckTargetQuery, "the target query only runs with validateTarget"))
+	case correlation.Config.Type != ConfigTypeQuery || correlation.TargetUID == nil:
+		report(skippedCheck(HealthCheckTargetQuery, "the correlation has no target query"))
+	case target.Status != HealthOK:
+		report(skippedCheck(HealthCheckTargetQuery, "the target data source failed its check"))
+	default:
+		report(healthCheck(HealthCheckTargetQuery, s.dryRunTarget(ctx, query.SignedInUser, correlation)))
+	}
+
+	return health, nil
+}
+
+// checkHealthTarget checks the target data source of query correlations, and
+// the target dashboard of dashboard correlations. External correlations link
+// to a URL that isn't checked.
+func (s CorrelationsService) checkHealthTarget(ctx context.Context, orgID int64, correlation Correlation) CorrelationHealthCheck {
+	switch correlation.Config.Type {
+	case ConfigTypeExternal:
+		return skippedCheck(HealthCheckTarget, "the target URL of external correlations isn't checked")
+	case ConfigTypeDashboard:
+		err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+			return s.checkDashboardTarget(session, orgID, correlation.Config)
+		})
+		return healthCheck(HealthCheckTarget, err)
+	}
+
+	if correlation.TargetUID == nil {
+		return healthCheck(HealthCheckTarget, ErrInvalidTarget.Errorf("the correlation has no target data source"))
+	}
+	return healthCheck(HealthCheckTarget, s.checkTarget(ctx, orgID, *correlation.TargetUID, correlation.Config))
+}
+
+// healthCheck reports the error of a check, or that it passed
+func healthCheck(name string, err error) CorrelationHealthCheck {
+	if err == nil {
+		return CorrelationHealthCheck{Name: name, Status: HealthOK}
+	}
+
+	check := CorrelationHealthCheck{Name: name, Status: HealthError}
+	check.MessageID, check.Message = errorMessage(err)
+	return check
+}
+
+// errorMessage returns the message ID of Grafana errors, and the message of
+// the error without it
+func errorMessage(err error) (string, string) {
+	var grafanaErr errutil.Error
+	if !errors.As(err, &grafanaErr) {
+		return "", err.Error()
+	}
+	if grafanaErr.LogMessage == "" {
+		return grafanaErr.MessageID, err.Error()
+	}
+	return grafanaErr.MessageID, grafanaErr.LogMessage
+}
+
+// skippedCheck reports why a check was skipped
+func skippedCheck(name string, format string, args ...interface{}) CorrelationHealthCheck {
+	return CorrelationHealthCheck{Name: name, Status: HealthSkipped, Message: fmt.Sprintf(format, args...)}
+}
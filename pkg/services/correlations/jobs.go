@@ -0,0 +1,57 @@
+package correlations
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/jobs"
+)
+
+const (
+	orphanCleanupInterval    = time.Hour
+	usageAggregationInterval = 5 * time.Minute
+	// usageAggregationBatchSize bounds the number of resolutions aggregated in a single transaction
+	usageAggregationBatchSize = 1000
+)
+
+func (s CorrelationsService) registerJobs(scheduler jobs.Scheduler) {
+	scheduler.Register(jobs.Job{
+		Name:     "correlations orphan cleanup",
+		Interval: orphanCleanupInterval,
+		Run:      s.cleanupOrphanedCorrelations,
+	})
+
+	scheduler.Register(jobs.Job{
+		Name:     "correlations usage aggregation",
+		Interval: usageAggregationInterval,
+		Run:      s.aggregateUsage,
+	})
+}
+
+func (s CorrelationsService) cleanupOrphanedCorrelations(ctx context.Context) error {
+	deleted, err := s.deleteOrphanedCorrelations(ctx)
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		s.log.Info("Deleted correlations of deleted data sources", "count", deleted)
+	}
+	return nil
+}
+
+// aggregateUsage aggregates the recorded resolutions in batches, until all of them are aggregated.
+func (s CorrelationsService) aggregateUsage(ctx context.Context) error {
+	for ctx.Err() == nil {
+		aggregated, err := s.aggregateResolutions(ctx, usageAggregationBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if aggregated < usageAggregationBatchSize {
+			return nil
+		}
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,93 @@
+package correlations
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/infra/metrics/metricutil"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	opCreate = "create"
+	opUpdate = "update"
+	opDelete = "delete"
+)
+
+var (
+	changesCounter = metricutil.NewCounterVecStartingAtZero(
+		prometheus.CounterOpts{
+			Namespace: metrics.ExporterName,
+			Subsystem: "correlations",
+			Name:      "changes_total",
+			Help:      "A counter for the correlations created, updated and deleted",
+		},
+		[]string{"operation"},
+		map[string][]string{
+			"operation": {opCreate, opUpdate, opDelete},
+		},
+	)
+	validationFailuresCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.ExporterName,
+			Subsystem: "correlations",
+			Name:      "validation_failures_total",
+			Help:      "A counter for the correlations rejected as invalid, by the message ID of the error",
+		},
+		[]string{"operation", "reason"},
+	)
+	listDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metrics.ExporterName,
+			Subsystem: "correlations",
+			Name:      "list_duration_seconds",
+			Help:      "Histogram of the time taken to list correlations, including the cached lists",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"list"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		changesCounter,
+		validationFailuresCounter,
+		listDuration,
+	)
+}
+
+// observeChange counts the changed correlation, or the validation failure
+// when the change was rejected as invalid. Other errors aren't counted.
+func observeChange(operation string, err error) {
+	if err == nil {
+		changesCounter.WithLabelValues(operation).Inc()
+		return
+	}
+
+	var errutilErr errutil.Error
+	if errors.As(err, &errutilErr) && errutilErr.Reason.Status() == errutil.StatusBadRequest {
+		validationFailuresCounter.WithLabelValues(operation, errutilErr.MessageID).Inc()
+	}
+}
+
+// observeBulkChanges counts the correlations changed by a bulk command, or the
+// validation failures that rolled it back
+func observeBulkChanges(operation string, count int, err error) {
+	if err == nil {
+		changesCounter.WithLabelValues(operation).Add(float64(count))
+		return
+	}
+
+	var bulkErr BulkCorrelationsError
+	if !errors.As(err, &bulkErr) {
+		return
+	}
+	for _, failure := range bulkErr.Errors {
+		if failure.StatusCode == http.StatusBadRequest {
+			validationFailuresCounter.WithLabelValues(operation, failure.MessageID).Inc()
+		}
+	}
+}
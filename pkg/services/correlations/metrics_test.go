@@ -0,0 +1,52 @@
+package correlations
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+func TestObserveChange(t *testing.T) {
+	t.Run("counts the changes", func(t *testing.T) {
+		before := testutil.ToFloat64(changesCounter.WithLabelValues(opUpdate))
+		observeChange(opUpdate, nil)
+		require.Equal(t, before+1, testutil.ToFloat64(changesCounter.WithLabelValues(opUpdate)))
+	})
+
+	t.Run("counts the validation failures by reason", func(t *testing.T) {
+		failures := validationFailuresCounter.WithLabelValues(opCreate, "correlations.invalidConfigType")
+		changes := changesCounter.WithLabelValues(opCreate)
+		before, beforeChanges := testutil.ToFloat64(failures), testutil.ToFloat64(changes)
+
+		observeChange(opCreate, ErrInvalidConfigType.Errorf("invalid"))
+		require.Equal(t, before+1, testutil.ToFloat64(failures))
+
+		observeChange(opCreate, ErrCorrelationNotFound.Errorf("not found"))
+		observeChange(opCreate, errors.New("database error"))
+		require.Equal(t, before+1, testutil.ToFloat64(failures))
+		require.Equal(t, beforeChanges, testutil.ToFloat64(changes))
+	})
+
+	t.Run("counts the changes of bulk commands", func(t *testing.T) {
+		before := testutil.ToFloat64(changesCounter.WithLabelValues(opDelete))
+		observeBulkChanges(opDelete, 3, nil)
+		require.Equal(t, before+3, testutil.ToFloat64(changesCounter.WithLabelValues(opDelete)))
+	})
+
+	t.Run("counts the validation failures of bulk commands", func(t *testing.T) {
+		failures := validationFailuresCounter.WithLabelValues(opCreate, "correlations.invalidTarget")
+		before := testutil.ToFloat64(failures)
+
+		observeBulkChanges(opCreate, 0, BulkCorrelationsError{Errors: []BulkCorrelationError{
+			{Index: 0, PublicError: errutil.PublicError{StatusCode: http.StatusBadRequest, MessageID: "correlations.invalidTarget"}},
+			{Index: 1, PublicError: errutil.PublicError{StatusCode: http.StatusNotFound, MessageID: "correlations.sourceDataSourceNotFound"}},
+			{Index: 2, PublicError: errutil.PublicError{StatusCode: http.StatusBadRequest, MessageID: "correlations.invalidTarget"}},
+		}})
+		require.Equal(t, before+2, testutil.ToFloat64(failures))
+	})
+}
@@ -0,0 +1,203 @@
+package correlations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// lokiLineField is the field of the log lines in the frames of Loki, which the
+// derived fields match their regular expression against
+const lokiLineField = "Line"
+
+// derivedField is a derived field of the jsonData of a Loki data source
+type derivedField struct {
+	Name            string `json:"name"`
+	MatcherRegex    string `json:"matcherRegex"`
+	URL             string `json:"url"`
+	URLDisplayLabel string `json:"urlDisplayLabel"`
+	DatasourceUID   string `json:"datasourceUid"`
+}
+
+// correlation returns the command creating the correlation equivalent to the
+// derived field: the regular expression extracts the value of the log line,
+// and the URL is the query of the target data source, as for the internal
+// links of the derived fields
+func (f derivedField) correlation(sourceUID string) CreateCorrelationCommand {
+	targetUID := f.DatasourceUID
+	return CreateCorrelationCommand{
+		SourceUID:   sourceUID,
+		TargetUID:   &targetUID,
+		Label:       f.Name,
+		Description: fmt.Sprintf("Migrated from the derived field %s", f.Name),
+		Config: CorrelationConfig{
+			Type:            ConfigTypeQuery,
+			Field:           CorrelationFields{lokiLineField},
+			Transformations: Transformations{{Type: TransformationRegex, Expression: f.MatcherRegex}},
+			Target:          map[string]interface{}{"query": f.URL},
+			Display:         CorrelationDisplay{Title: f.URLDisplayLabel},
+		},
+	}
+}
+
+// migrateDerivedFields creates the correlations equivalent to the derived
+// fields of a Loki data source that link to a data source. The derived fields
+// linking to a URL, the ones that don't make a valid correlation and the ones
+// with an equivalent correlation are skipped, so that the migration can run
+// again. The migrated derived fields, and the ones with an equivalent
+// correlation, are optionally deleted from the data source in the same
+// transaction.
+func (s CorrelationsService) migrateDerivedFields(ctx context.Context, cmd MigrateDerivedFieldsCommand) ([]Correlation, []SkippedDerivedField, error) {
+	query := &datasources.GetDataSourceQuery{OrgId: cmd.OrgId, Uid: cmd.SourceUID}
+	if err := s.DataSourceService.GetDataSource(ctx, query); err != nil {
+		return nil, nil, ErrSourceDataSourceDoesNotExists.Errorf("source data source %s does not exist: %w", cmd.SourceUID, err)
+	}
+	ds := query.Result
+	if ds.Type != datasources.DS_LOKI {
+		return nil, nil, ErrNotLokiDataSource.Errorf("data source %s is of type %s", ds.Uid, ds.Type)
+	}
+	if ds.ReadOnly {
+		return nil, nil, ErrSourceDataSourceReadOnly.Errorf("source data source %s is read only", ds.Uid)
+	}
+
+	var raw []interface{}
+	if ds.JsonData != nil {
+		raw = ds.JsonData.Get("derivedFields").MustArray()
+	}
+
+	var skipped []SkippedDerivedField
+	bulkCmd := BulkCreateCorrelationsCommand{OrgId: cmd.OrgId, UserId: cmd.UserId}
+	// positions maps the correlations of the bulk command to their derived field
+	positions := make([]int, 0, len(raw))
+	migrated := make(map[int]bool, len(raw))
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		for i, item := range raw {
+			var field derivedField
+			if data, err := json.Marshal(item); err != nil || json.Unmarshal(data, &field) != nil {
+				skipped = append(skipped, SkippedDerivedField{Index: i, Reason: "invalid derived field"})
+				continue
+			}
+			if field.DatasourceUID == "" {
+				skipped = append(skipped, SkippedDerivedField{Index: i, Name: field.Name, Reason: "the derived field links to a URL rather than a data source"})
+				continue
+			}
+
+			createCmd := field.correlation(ds.Uid)
+			if err := createCmd.Validate(); err != nil {
+				_, reason := errorMessage(err)
+				skipped = append(skipped, SkippedDerivedField{Index: i, Name: field.Name, Reason: reason})
+				continue
+			}
+
+			err := checkDuplicate(session, Correlation{SourceUID: ds.Uid, OrgId: cmd.OrgId, TargetUID: createCmd.TargetUID, Config: createCmd.Config})
+			if err != nil {
+				if !errors.Is(err, ErrCorrelationDuplicate) {
+					return err
+				}
+				skip := SkippedDerivedField{Index: i, Name: field.Name, Reason: "an equivalent correlation exists"}
+				var grafanaErr errutil.Error
+				if errors.As(err, &grafanaErr) {
+					skip.UID, _ = grafanaErr.PublicPayload["uid"].(string)
+				}
+				skipped = append(skipped, skip)
+				migrated[i] = true
+				continue
+			}
+
+			bulkCmd.Correlations = append(bulkCmd.Correlations, BulkCreateCorrelationsItem{SourceUID: ds.Uid, CreateCorrelationCommand: createCmd})
+			positions = append(positions, i)
+			migrated[i] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cmd.DryRun {
+		planned := make([]Correlation, 0, len(bulkCmd.Correlations))
+		for _, item := range bulkCmd.Correlations {
+			planned = append(planned, Correlation{
+				SourceUID:   item.SourceUID,
+				TargetUID:   item.TargetUID,
+				OrgId:       cmd.OrgId,
+				Label:       item.Label,
+				Description: item.Description,
+				Config:      item.Config,
+			})
+		}
+		return planned, skipped, nil
+	}
+
+	created := []Correlation{}
+	err = s.SQLStore.InTransaction(ctx, func(ctx context.Context) error {
+		if len(bulkCmd.Correlations) > 0 {
+			var err error
+			if created, err = s.bulkCreateCorrelations(ctx, bulkCmd); err != nil {
+				return err
+			}
+		}
+		if !cmd.DeleteDerivedFields || len(migrated) == 0 {
+			return nil
+		}
+		return s.deleteDerivedFields(ctx, ds, raw, migrated)
+	})
+	if err != nil {
+		var bulkErr BulkCorrelationsError
+		if errors.As(err, &bulkErr) {
+			for i := range bulkErr.Errors {
+				bulkErr.Errors[i].Index = positions[bulkErr.Errors[i].Index]
+			}
+			return nil, nil, bulkErr
+		}
+		return nil, nil, err
+	}
+
+	return created, skipped, nil
+}
+
+// deleteDerivedFields removes the migrated derived fields from the data
+// source, keeping its other settings and its secrets
+func (s CorrelationsService) deleteDerivedFields(ctx context.Context, ds *datasources.DataSource, fields []interface{}, migrated map[int]bool) error {
+	kept := make([]interface{}, 0, len(fields)-len(migrated))
+	for i, field := range fields {
+		if !migrated[i] {
+			kept = append(kept, field)
+		}
+	}
+
+	jsonData := make(map[string]interface{}, len(ds.JsonData.MustMap()))
+	for key, value := range ds.JsonData.MustMap() {
+		jsonData[key] = value
+	}
+	jsonData["derivedFields"] = kept
+
+	err := s.DataSourceService.UpdateDataSource(ctx, &datasources.UpdateDataSourceCommand{
+		Id:              ds.Id,
+		OrgId:           ds.OrgId,
+		Uid:             ds.Uid,
+		Name:            ds.Name,
+		Type:            ds.Type,
+		Access:          ds.Access,
+		Url:             ds.Url,
+		User:            ds.User,
+		Database:        ds.Database,
+		BasicAuth:       ds.BasicAuth,
+		BasicAuthUser:   ds.BasicAuthUser,
+		WithCredentials: ds.WithCredentials,
+		IsDefault:       ds.IsDefault,
+		ReadOnly:        ds.ReadOnly,
+		JsonData:        simplejson.NewFromAny(jsonData),
+		Version:         ds.Version,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete the derived fields of data source %s: %w", ds.Uid, err)
+	}
+	return nil
+}
@@ -2,72 +2,600 @@ package correlations
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 var (
-	ErrSourceDataSourceReadOnly           = errors.New("source data source is read only")
-	ErrSourceDataSourceDoesNotExists      = errors.New("source data source does not exist")
-	ErrTargetDataSourceDoesNotExists      = errors.New("target data source does not exist")
-	ErrCorrelationFailedGenerateUniqueUid = errors.New("failed to generate unique correlation UID")
-	ErrCorrelationNotFound                = errors.New("correlation not found")
-	ErrUpdateCorrelationEmptyParams       = errors.New("not enough parameters to edit correlation")
-	ErrInvalidConfigType                  = errors.New("invalid correlation config type")
+	ErrSourceDataSourceReadOnly           = errutil.NewBase(errutil.StatusForbidden, "correlations.sourceDataSourceReadOnly", errutil.WithPublicMessage("Data source is read only"))
+	ErrSourceDataSourceDoesNotExists      = errutil.NewBase(errutil.StatusNotFound, "correlations.sourceDataSourceNotFound", errutil.WithPublicMessage("Data source not found"))
+	ErrTargetDataSourceDoesNotExists      = errutil.NewBase(errutil.StatusNotFound, "correlations.targetDataSourceNotFound", errutil.WithPublicMessage("Data source not found"))
+	ErrTargetDashboardDoesNotExists       = errutil.NewBase(errutil.StatusNotFound, "correlations.targetDashboardNotFound", errutil.WithPublicMessage("Dashboard not found"))
+	ErrCorrelationFailedGenerateUniqueUid = errutil.NewBase(errutil.StatusInternal, "correlations.failedGenerateUniqueUid")
+	ErrCorrelationNotFound                = errutil.NewBase(errutil.StatusNotFound, "correlations.notFound", errutil.WithPublicMessage("Correlation not found"))
+	ErrInvalidConfigType                  = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidConfigType", errutil.WithPublicMessage("Invalid correlation config type"))
+	ErrInvalidTarget                      = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidTarget", errutil.WithPublicMessage("Invalid correlation target"))
+	ErrInvalidTransformation              = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidTransformation", errutil.WithPublicMessage("Invalid correlation transformation"))
+	ErrInvalidMatcher                     = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidMatcher", errutil.WithPublicMessage("Invalid correlation matcher"))
+	ErrInvalidPlaceholder                 = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidPlaceholder").MustTemplate("Invalid placeholder {{ .Public.placeholder }} in the target: {{ .Public.reason }}", errutil.WithPublicFromLog())
+	ErrInvalidTargetQuery                 = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidTargetQuery").MustTemplate("The target data source rejected the target query: {{ .Public.error }}", errutil.WithPublicFromLog())
+	ErrCorrelationsQuotaReached           = errutil.NewBase(errutil.StatusForbidden, "correlations.quotaReached", errutil.WithPublicMessage("Quota reached"))
+	ErrCorrelationReadOnly                = errutil.NewBase(errutil.StatusForbidden, "correlations.readOnly", errutil.WithPublicMessage("Provisioned correlations can only be edited through provisioning"))
+	ErrInvalidDataSourceMapping           = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidDataSourceMapping", errutil.WithPublicMessage("Data sources must be mapped to a UID"))
+	ErrBundleDataSourceNotFound           = errutil.NewBase(errutil.StatusNotFound, "correlations.bundleDataSourceNotFound", errutil.WithPublicMessage("Data source of the bundle not found"))
+	ErrCorrelationVersionNotFound         = errutil.NewBase(errutil.StatusNotFound, "correlations.versionNotFound", errutil.WithPublicMessage("Correlation version not found"))
+	ErrInvalidCopyCommand                 = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidCopyCommand", errutil.WithPublicMessage("Correlations are copied between two different organizations"))
+	ErrInvalidBulkCommand                 = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidBulkCommand", errutil.WithPublicMessage(fmt.Sprintf("Bulk commands take between 1 and %d correlations", MaxBulkCorrelations)))
+	ErrInvalidTag                         = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidTag", errutil.WithPublicMessage(fmt.Sprintf("Tags must be between 1 and %d characters", MaxTagLength)))
+	ErrInvalidSearchQuery                 = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidSearchQuery", errutil.WithPublicMessage(fmt.Sprintf("Search queries must be between 1 and %d characters, and return at most %d results", MaxSearchQueryLength, MaxSearchResults)))
+	ErrInvalidSourceUIDs                  = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidSourceUIDs", errutil.WithPublicMessage(fmt.Sprintf("Between 1 and %d non-empty source data source UIDs are required", MaxSourceUIDs)))
+	ErrInvalidUID                         = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidUID", errutil.WithPublicMessage("UIDs are up to 40 letters, digits, dashes and underscores"))
+	ErrCorrelationUIDExists               = errutil.NewBase(errutil.StatusBadRequest, "correlations.uidExists", errutil.WithPublicMessage("A correlation with the same UID already exists"))
+	ErrInvalidExpand                      = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidExpand", errutil.WithPublicMessage(fmt.Sprintf("The lists of correlations can only be expanded with %q", ExpandDataSources)))
+	ErrCorrelationDuplicate               = errutil.NewBase(errutil.StatusConflict, "correlations.duplicate").MustTemplate("The correlation {{ .Public.uid }} has the same source, target, field and target query", errutil.WithPublicFromLog())
+	ErrNotLokiDataSource                  = errutil.NewBase(errutil.StatusBadRequest, "correlations.notLokiDataSource", errutil.WithPublicMessage("Only the derived fields of Loki data sources can be migrated"))
+	ErrInvalidVisibility                  = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidVisibility", errutil.WithPublicMessage("Correlations are visible to the Viewer, Editor or Admin roles, and to the teams of their organization"))
+	ErrInvalidDisplay                     = errutil.NewBase(errutil.StatusBadRequest, "correlations.invalidDisplay", errutil.WithPublicMessage("Invalid correlation display options"))
+	ErrTargetSchemaMismatch               = errutil.NewBase(errutil.StatusBadRequest, "correlations.targetSchemaMismatch").MustTemplate("The target query doesn't match the schema of the {{ .Public.plugin }} plugin: {{ .Public.reason }}", errutil.WithPublicFromLog())
 )
 
 type CorrelationConfigType string
 
 const (
+	// ConfigTypeQuery correlations run the target query against the target data source.
 	ConfigTypeQuery CorrelationConfigType = "query"
+	// ConfigTypeExternal correlations link to the URL of their target, where
+	// ${value} is replaced by the value of the first field and ${<field>} by the
+	// value of the field. They have no target data source.
+	ConfigTypeExternal CorrelationConfigType = "external"
+	// ConfigTypeDashboard correlations link to the dashboard, and optionally the
+	// panel, of their target, setting its variables from the fields of the source
+	// row. They have no target data source.
+	ConfigTypeDashboard CorrelationConfigType = "dashboard"
 )
 
 func (t CorrelationConfigType) Validate() error {
-	if t != ConfigTypeQuery {
-		return fmt.Errorf("%s: \"%s\"", ErrInvalidConfigType, t)
+	if t != ConfigTypeQuery && t != ConfigTypeExternal && t != ConfigTypeDashboard {
+		return ErrInvalidConfigType.Errorf("invalid correlation config type: \"%s\"", t)
+	}
+	return nil
+}
+
+type TransformationType string
+
+const (
+	// TransformationRegex extracts the first capture group, or the whole match, of the expression.
+	TransformationRegex TransformationType = "regex"
+	// TransformationLogfmt parses the value as logfmt and extracts the value of the field.
+	TransformationLogfmt TransformationType = "logfmt"
+	// TransformationMapping replaces the value with the one it maps to.
+	TransformationMapping TransformationType = "mapping"
+)
+
+// Transformation is applied to the value of the source field before it's injected into the target.
+// swagger:model
+type Transformation struct {
+	// Type of the transformation, either "regex", "logfmt" or "mapping"
+	// required:true
+	// example: regex
+	Type TransformationType `json:"type"`
+	// Regular expression of regex transformations
+	// example: traceId=(\w+)
+	Expression string `json:"expression,omitempty"`
+	// Key extracted by logfmt transformations, defaults to the first correlation field
+	// example: traceId
+	Field string `json:"field,omitempty"`
+	// Name of the variable holding the result, defaults to the first correlation field
+	// example: traceId
+	MapValue string `json:"mapValue,omitempty"`
+	// Values replacing the matching source values in mapping transformations
+	// example: { "error": "level=error" }
+	Mapping map[string]string `json:"mapping,omitempty"`
+}
+
+func (t Transformation) Validate() error {
+	switch t.Type {
+	case TransformationRegex:
+		if t.Expression == "" {
+			return ErrInvalidTransformation.Errorf("%s transformations must have an expression", t.Type)
+		}
+		if _, err := regexp.Compile(t.Expression); err != nil {
+			return ErrInvalidTransformation.Errorf("invalid expression of %s transformation: %w", t.Type, err)
+		}
+	case TransformationLogfmt:
+	case TransformationMapping:
+		if len(t.Mapping) == 0 {
+			return ErrInvalidTransformation.Errorf("%s transformations must have a mapping", t.Type)
+		}
+	default:
+		return ErrInvalidTransformation.Errorf("invalid transformation type: \"%s\"", t.Type)
+	}
+	return nil
+}
+
+// Transformations are applied in order, each one to the result of the previous one.
+type Transformations []Transformation
+
+func (t Transformations) Validate() error {
+	for _, transformation := range t {
+		if err := transformation.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type CorrelationMatcherOperator string
+
+const (
+	// MatcherEquals matches the rows where the field is equal to the value.
+	MatcherEquals CorrelationMatcherOperator = "equals"
+	// MatcherNotEquals matches the rows where the field is not equal to the value.
+	MatcherNotEquals CorrelationMatcherOperator = "notEquals"
+	// MatcherRegex matches the rows where the field matches the regular expression of the value.
+	MatcherRegex CorrelationMatcherOperator = "regex"
+	// MatcherNotRegex matches the rows where the field doesn't match the regular expression of the value.
+	MatcherNotRegex CorrelationMatcherOperator = "notRegex"
+)
+
+// CorrelationMatcher is a condition on a field of the source row.
+// swagger:model
+type CorrelationMatcher struct {
+	// Field of the source row the condition applies to
+	// required:true
+	// example: service
+	Field string `json:"field"`
+	// Operator of the condition, either "equals", "notEquals", "regex" or "notRegex"
+	// required:true
+	// example: equals
+	Operator CorrelationMatcherOperator `json:"operator"`
+	// Value compared to the field, or for regex operators, the regular expression it's matched against
+	// example: checkout
+	Value string `json:"value"`
+}
+
+func (m CorrelationMatcher) Validate() error {
+	if m.Field == "" {
+		return ErrInvalidMatcher.Errorf("matchers must have a field")
+	}
+	switch m.Operator {
+	case MatcherEquals, MatcherNotEquals:
+	case MatcherRegex, MatcherNotRegex:
+		if _, err := regexp.Compile(m.Value); err != nil {
+			return ErrInvalidMatcher.Errorf("invalid regular expression of %s matcher: %w", m.Operator, err)
+		}
+	default:
+		return ErrInvalidMatcher.Errorf("invalid matcher operator: \"%s\"", m.Operator)
+	}
+	return nil
+}
+
+// CorrelationMatchers must all match the source row for the correlation to apply to it.
+type CorrelationMatchers []CorrelationMatcher
+
+func (m CorrelationMatchers) Validate() error {
+	for _, matcher := range m {
+		if err := matcher.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type CorrelationOpenIn string
+
+const (
+	// OpenInSplitView opens the target next to the source, in the split view of Explore.
+	OpenInSplitView CorrelationOpenIn = "split"
+	// OpenInNewTab opens the target in a new browser tab.
+	OpenInNewTab CorrelationOpenIn = "newTab"
+)
+
+// MaxLinkTitleLength is the maximum length of the link title of a correlation
+const MaxLinkTitleLength = 100
+
+// iconRegexp matches the names of the icons of Grafana, such as external-link-alt
+var iconRegexp = regexp.MustCompile(`^[a-z0-9-]{1,50}$`)
+
+// CorrelationDisplay sets how the links of a correlation are shown and opened,
+// the frontend defaults applying to the options that aren't set.
+// swagger:model
+type CorrelationDisplay struct {
+	// Where the target opens, either "split" or "newTab"
+	// example: split
+	OpenIn CorrelationOpenIn `json:"openIn,omitempty"`
+	// Name of the icon of the link
+	// example: gf-traces
+	Icon string `json:"icon,omitempty"`
+	// Title of the link, with the same placeholders as the target
+	// example: Traces of ${traceId}
+	Title string `json:"title,omitempty"`
+}
+
+func (d CorrelationDisplay) Validate() error {
+	switch d.OpenIn {
+	case "", OpenInSplitView, OpenInNewTab:
+	default:
+		return ErrInvalidDisplay.Errorf("invalid display openIn: \"%s\"", d.OpenIn)
+	}
+	if d.Icon != "" && !iconRegexp.MatchString(d.Icon) {
+		return ErrInvalidDisplay.Errorf("invalid display icon: %q", d.Icon)
+	}
+	if len(d.Title) > MaxLinkTitleLength {
+		return ErrInvalidDisplay.Errorf("the link title is longer than %d characters", MaxLinkTitleLength)
+	}
+	return nil
+}
+
+// valueProperties are the properties of the ${__value} placeholder
+var valueProperties = map[string]bool{"raw": true, "text": true, "numeric": true, "time": true, "calc": true}
+
+// dataFieldRegexp matches the field referenced by ${__data.fields} placeholders,
+// either as __data.fields.name or __data.fields["name"]
+var dataFieldRegexp = regexp.MustCompile(`^__data\.fields(?:\.([^.\[]+)|\["([^"]+)"\])`)
+
+// validatePlaceholders checks that every placeholder of the target can be
+// resolved from the source row: ${value} and ${__value} take the value of the
+// first field, ${__data.fields.name} a declared field, and ${name} a declared
+// field or a variable set by the transformations. Other built-in variables,
+// such as ${__from}, are left to the frontend.
+func validatePlaceholders(config CorrelationConfig) error {
+	variables := make(map[string]bool, len(config.Field))
+	for _, field := range config.Field {
+		variables[field] = true
+	}
+	for _, transformation := range config.Transformations {
+		if transformation.MapValue != "" {
+			variables[transformation.MapValue] = true
+		}
+		if transformation.Type == TransformationLogfmt && transformation.Field != "" {
+			variables[transformation.Field] = true
+		}
+	}
+
+	for _, query := range config.targetQueries() {
+		for _, placeholder := range targetPlaceholders(query) {
+			if reason := placeholderError(placeholder, config.Field, variables); reason != "" {
+				return ErrInvalidPlaceholder.Build(errutil.TemplateData{
+					Public: map[string]interface{}{"placeholder": placeholder, "reason": reason},
+				})
+			}
+		}
+	}
+	for _, placeholder := range placeholderRegexp.FindAllString(config.Display.Title, -1) {
+		if reason := placeholderError(placeholder, config.Field, variables); reason != "" {
+			return ErrInvalidDisplay.Errorf("invalid placeholder %s in the link title: %s", placeholder, reason)
+		}
+	}
+	return nil
+}
+
+// placeholderError returns why the placeholder can't be resolved, or an empty
+// string if it can
+func placeholderError(placeholder string, fields CorrelationFields, variables map[string]bool) string {
+	name := strings.TrimSuffix(strings.TrimPrefix(placeholder, "${"), "}")
+	// drop the format, as in ${__value.raw:queryparam}
+	name, _, _ = strings.Cut(name, ":")
+
+	switch {
+	case name == "":
+		return "the placeholder is empty"
+	case name == "value" || name == "__value" || strings.HasPrefix(name, "__value."):
+		if len(fields) == 0 {
+			return "the correlation has no field to take the value of"
+		}
+		if property := strings.TrimPrefix(name, "__value."); property != name && !valueProperties[property] {
+			return fmt.Sprintf("unknown property %q of the value", property)
+		}
+	case strings.HasPrefix(name, "__data.fields"):
+		match := dataFieldRegexp.FindStringSubmatch(name)
+		if match == nil {
+			return "the placeholder doesn't reference a field by name"
+		}
+		field := match[1] + match[2]
+		for _, f := range fields {
+			if f == field {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%q is not a field of the correlation", field)
+	case strings.HasPrefix(name, "__"):
+	case !variables[name]:
+		return fmt.Sprintf("%q is neither a field of the correlation nor a variable set by its transformations", name)
+	}
+	return ""
+}
+
+// targetPlaceholders returns the placeholders of all the strings of the target,
+// in a stable order
+func targetPlaceholders(target interface{}) []string {
+	var placeholders []string
+	switch value := target.(type) {
+	case string:
+		placeholders = placeholderRegexp.FindAllString(value, -1)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			placeholders = append(placeholders, targetPlaceholders(value[key])...)
+		}
+	case []interface{}:
+		for _, item := range value {
+			placeholders = append(placeholders, targetPlaceholders(item)...)
+		}
+	}
+	return placeholders
+}
+
+// dashboardUID returns the UID of the dashboard targeted by dashboard correlations
+func dashboardUID(config CorrelationConfig) string {
+	uid, _ := config.Target["dashboardUID"].(string)
+	return uid
+}
+
+// placeholderRegexp matches the placeholders, such as ${value}, of the URL of external correlations.
+var placeholderRegexp = regexp.MustCompile(`\$\{[^}]*\}`)
+
+// validateConfig checks that the config is valid for a correlation with the target data source.
+func validateConfig(targetUID *string, config CorrelationConfig) error {
+	if err := validateConfigOptions(targetUID, config); err != nil {
+		return err
+	}
+	return validatePlaceholders(config)
+}
+
+// validateConfigOptions checks the config like validateConfig, except for its placeholders.
+func validateConfigOptions(targetUID *string, config CorrelationConfig) error {
+	if err := config.Type.Validate(); err != nil {
+		return err
 	}
+	if config.Targets != nil {
+		if config.Type != ConfigTypeQuery {
+			return ErrInvalidTarget.Errorf("only correlations of type \"%s\" can have a list of target queries", ConfigTypeQuery)
+		}
+		if len(config.Targets) == 0 {
+			return ErrInvalidTarget.Errorf("the list of target queries is empty")
+		}
+	}
+	if err := config.Transformations.Validate(); err != nil {
+		return err
+	}
+	if err := config.Matchers.Validate(); err != nil {
+		return err
+	}
+	if err := config.Display.Validate(); err != nil {
+		return err
+	}
+
+	switch config.Type {
+	case ConfigTypeQuery:
+		if targetUID == nil {
+			return ErrInvalidTarget.Errorf("correlations of type \"%s\" must have a targetUID", ConfigTypeQuery)
+		}
+	case ConfigTypeExternal:
+		if targetUID != nil {
+			return ErrInvalidTarget.Errorf("correlations of type \"%s\" can't have a targetUID", ConfigTypeExternal)
+		}
+		rawURL, _ := config.Target["url"].(string)
+		if rawURL == "" {
+			return ErrInvalidTarget.Errorf("correlations of type \"%s\" must have a target url", ConfigTypeExternal)
+		}
+		u, err := url.Parse(placeholderRegexp.ReplaceAllString(rawURL, "value"))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidTarget.Errorf("invalid target url of external correlation: %q", rawURL)
+		}
+	case ConfigTypeDashboard:
+		if targetUID != nil {
+			return ErrInvalidTarget.Errorf("correlations of type \"%s\" can't have a targetUID", ConfigTypeDashboard)
+		}
+		if dashboardUID(config) == "" {
+			return ErrInvalidTarget.Errorf("correlations of type \"%s\" must have a target dashboardUID", ConfigTypeDashboard)
+		}
+		if panelID, ok := config.Target["panelId"]; ok {
+			if id, isNumber := panelID.(float64); !isNumber || id < 1 || id != math.Trunc(id) {
+				return ErrInvalidTarget.Errorf("invalid target panelId of dashboard correlation: %v", panelID)
+			}
+		}
+		if rawVariables, ok := config.Target["variables"]; ok {
+			variables, isMap := rawVariables.(map[string]interface{})
+			if !isMap {
+				return ErrInvalidTarget.Errorf("target variables of dashboard correlations must be an object")
+			}
+			for name, value := range variables {
+				if _, isString := value.(string); !isString {
+					return ErrInvalidTarget.Errorf("invalid value of target variable %s of dashboard correlation", name)
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
+// CorrelationFields are the fields of the source results whose values are
+// injected into the target. A single field name is accepted for backward
+// compatibility, they are always encoded as a list.
+type CorrelationFields []string
+
+func (f *CorrelationFields) UnmarshalJSON(data []byte) error {
+	var field string
+	if err := json.Unmarshal(data, &field); err == nil {
+		*f = CorrelationFields{}
+		if field != "" {
+			*f = CorrelationFields{field}
+		}
+		return nil
+	}
+
+	var fields []string
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*f = CorrelationFields(fields)
+	return nil
+}
+
+func (f CorrelationFields) MarshalJSON() ([]byte, error) {
+	if f == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(f))
+}
+
 // swagger:model
 type CorrelationConfig struct {
-	// Field used to attach the correlation link
+	// Fields used to attach the correlation link, the link is attached to the
+	// first one and the transformations are applied to its value
 	// required:true
-	Field string `json:"field" binding:"Required"`
-	// Target type
+	// example: ["job", "namespace"]
+	Field CorrelationFields `json:"field" binding:"Required"`
+	// Target type, either "query", "external" or "dashboard"
 	// required:true
 	Type CorrelationConfigType `json:"type" binding:"Required"`
-	// Target data query
+	// Target data query, or list of target data queries of query correlations, for external correlations, the URL to link to as { url: "https://example.com/${value}" },
+	// and for dashboard correlations, the dashboard to link to as { dashboardUID: "abc", panelId: 2, variables: { service: "${service}" } }
 	// required:true
 	Target map[string]interface{} `json:"target" binding:"Required"`
+	// Target data queries when the target is a list, the first one being Target
+	Targets []map[string]interface{} `json:"-"`
+	// Transformations applied to the value of the field before it's injected into the target
+	// example: [{ type: "logfmt", field: "traceId" }]
+	Transformations Transformations `json:"transformations,omitempty"`
+	// Conditions on the source row, the correlation only applies to the rows matching all of them
+	// example: [{ field: "service", operator: "equals", value: "checkout" }]
+	Matchers CorrelationMatchers `json:"matchers,omitempty"`
+	// How the links of the correlation are shown and opened
+	// example: { openIn: "newTab", icon: "gf-traces", title: "Traces of ${traceId}" }
+	Display CorrelationDisplay `json:"display,omitempty"`
+}
+
+// UnmarshalJSON accepts a single target query or a list of them, which
+// MarshalJSON encodes back as a list
+func (c *CorrelationConfig) UnmarshalJSON(data []byte) error {
+	type plain CorrelationConfig
+	decoded := struct {
+		plain
+		Target json.RawMessage `json:"target"`
+	}{plain: plain(*c)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	config := CorrelationConfig(decoded.plain)
+	target, targets, err := decodeTarget(decoded.Target)
+	if err != nil {
+		return err
+	}
+	config.Target = target
+	if targets != nil {
+		config.setTargets(targets)
+	}
+	*c = config
+	return nil
+}
+
+// decodeTarget decodes a target, either a single query or a list of queries
+func decodeTarget(data json.RawMessage) (map[string]interface{}, []map[string]interface{}, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" || trimmed == "null" {
+		return nil, nil, nil
+	}
+	if strings.HasPrefix(trimmed, "[") {
+		targets := []map[string]interface{}{}
+		if err := json.Unmarshal(data, &targets); err != nil {
+			return nil, nil, err
+		}
+		return nil, targets, nil
+	}
+	var target map[string]interface{}
+	if err := json.Unmarshal(data, &target); err != nil {
+		return nil, nil, err
+	}
+	return target, nil, nil
+}
+
+// setTargets sets the list of target queries, and the first one as the target
+func (c *CorrelationConfig) setTargets(targets []map[string]interface{}) {
+	c.Targets = targets
+	c.Target = nil
+	if len(targets) > 0 {
+		c.Target = targets[0]
+	}
+}
+
+// targetQueries returns the target queries, the single target unless the target is a list
+func (c CorrelationConfig) targetQueries() []map[string]interface{} {
+	if c.Targets != nil {
+		return c.Targets
+	}
+	return []map[string]interface{}{c.Target}
 }
 
 func (c CorrelationConfig) MarshalJSON() ([]byte, error) {
-	target := c.Target
-	if target == nil {
+	var display *CorrelationDisplay
+	if c.Display != (CorrelationDisplay{}) {
+		display = &c.Display
+	}
+	var target interface{} = c.Target
+	if c.Targets != nil {
+		target = c.Targets
+	} else if c.Target == nil {
 		target = map[string]interface{}{}
 	}
+	configType := c.Type
+	if configType == "" {
+		configType = ConfigTypeQuery
+	}
 	return json.Marshal(struct {
-		Type   CorrelationConfigType  `json:"type"`
-		Field  string                 `json:"field"`
-		Target map[string]interface{} `json:"target"`
+		Type            CorrelationConfigType `json:"type"`
+		Field           CorrelationFields     `json:"field"`
+		Target          interface{}           `json:"target"`
+		Transformations Transformations       `json:"transformations,omitempty"`
+		Matchers        CorrelationMatchers   `json:"matchers,omitempty"`
+		Display         *CorrelationDisplay   `json:"display,omitempty"`
 	}{
-		Type:   ConfigTypeQuery,
-		Field:  c.Field,
-		Target: target,
+		Type:            configType,
+		Field:           c.Field,
+		Target:          target,
+		Transformations: c.Transformations,
+		Matchers:        c.Matchers,
+		Display:         display,
 	})
 }
 
 type CorrelationConfigUpdateDTO struct {
-	// Field used to attach the correlation link
+	// Fields used to attach the correlation link
 	// required:true
-	Field *string `json:"field"`
+	// example: ["job", "namespace"]
+	Field *CorrelationFields `json:"field"`
 	// Target type
 	// required:true
 	Type *CorrelationConfigType `json:"type"`
-	// Target data query
+	// Target data query, or list of target data queries
 	// required:true
 	Target *map[string]interface{} `json:"target"`
+	// Target data queries replacing the target when it's a list
+	Targets *[]map[string]interface{} `json:"-"`
+	// Transformations applied to the value of the field
+	Transformations *Transformations `json:"transformations"`
+	// Conditions on the source row
+	Matchers *CorrelationMatchers `json:"matchers"`
+	// How the links of the correlation are shown and opened
+	Display *CorrelationDisplay `json:"display"`
 }
 
 // Correlation is the model for correlations definitions
@@ -79,7 +607,13 @@ type Correlation struct {
 	// UID of the data source the correlation originates from
 	// example:d0oxYRg4z
 	SourceUID string `json:"sourceUID" xorm:"pk 'source_uid'"`
-	// UID of the data source the correlation points to
+	// Organization of the correlation. The correlations of data sources stored before it was set keep 0
+	// when their source UID exists in several orgs, or in none.
+	OrgId int64 `json:"-" xorm:"org_id"`
+	// Plugin type of the data sources the correlation originates from, set instead of the source UID
+	// example: loki
+	SourceType string `json:"sourceType,omitempty" xorm:"source_type"`
+	// UID of the data source the correlation points to, not set for external correlations
 	// example:PE1C5CBDA0504A6A3
 	TargetUID *string `json:"targetUID" xorm:"target_uid"`
 	// Label identifying the correlation
@@ -91,6 +625,66 @@ type Correlation struct {
 	// Correlation Configuration
 	// example: { field: "job", type: "query", target: { query: "job=app" } }
 	Config CorrelationConfig `json:"config" xorm:"jsonb config"`
+	// Position of the correlation among the correlations of its source data source, in ascending order
+	// example: 1
+	Order int `json:"order" xorm:"order"`
+	// Provisioned correlations can only be edited through provisioning
+	Provisioned bool `json:"provisioned" xorm:"provisioned"`
+	// Tags grouping the correlation
+	// example: ["observability"]
+	Tags []string `json:"tags,omitempty" xorm:"-"`
+	// Usage of the correlation, only returned when listing all the correlations
+	Usage *CorrelationUsageSummary `json:"usage,omitempty" xorm:"-"`
+	// Source data source, only returned by the lists with expand=datasources
+	SourceDataSource *CorrelationDataSource `json:"sourceDataSource,omitempty" xorm:"-"`
+	// Target data source, only returned by the lists with expand=datasources
+	TargetDataSource *CorrelationDataSource `json:"targetDataSource,omitempty" xorm:"-"`
+	// Roles and teams the correlation is restricted to, visible to all the users who can read its source when not set
+	Visibility *CorrelationVisibility `json:"visibility,omitempty" xorm:"jsonb visibility"`
+}
+
+// CorrelationVisibility restricts a correlation to the users with one of the
+// roles, or in one of the teams. Organization admins see all the correlations.
+// swagger:model
+type CorrelationVisibility struct {
+	// Roles the correlation is visible to, including the roles above them
+	// example: ["Editor"]
+	Roles []org.RoleType `json:"roles,omitempty"`
+	// IDs of the teams the correlation is visible to
+	// example: [3]
+	Teams []int64 `json:"teams,omitempty"`
+}
+
+func (v *CorrelationVisibility) Validate() error {
+	if v == nil {
+		return nil
+	}
+	for _, role := range v.Roles {
+		if !role.IsValid() {
+			return ErrInvalidVisibility.Errorf("invalid visibility role: \"%s\"", role)
+		}
+	}
+	return nil
+}
+
+// normalize returns nil for the visibilities that don't restrict the correlation
+func (v *CorrelationVisibility) normalize() *CorrelationVisibility {
+	if v == nil || (len(v.Roles) == 0 && len(v.Teams) == 0) {
+		return nil
+	}
+	return v
+}
+
+// CorrelationDataSource is the data source of a correlation, as returned by the
+// lists of correlations with expand=datasources
+// swagger:model
+type CorrelationDataSource struct {
+	// example: PE1C5CBDA0504A6A3
+	UID string `json:"uid"`
+	// example: Loki
+	Name string `json:"name"`
+	// example: loki
+	Type string `json:"type"`
 }
 
 // CreateCorrelationResponse is the response struct for CreateCorrelationCommand
@@ -105,10 +699,24 @@ type CreateCorrelationResponseBody struct {
 // swagger:model
 type CreateCorrelationCommand struct {
 	// UID of the data source for which correlation is created.
-	SourceUID         string `json:"-"`
+	SourceUID string `json:"-"`
+	// Plugin type of the data sources for which the correlation is created, instead of a single data source
+	SourceType        string `json:"-"`
 	OrgId             int64  `json:"-"`
 	SkipReadOnlyCheck bool   `json:"-"`
-	// Target data source UID to which the correlation is created
+	Provisioned       bool   `json:"-"`
+	// User creating the correlation, recorded in its version history
+	UserId int64 `json:"-"`
+	// Runs the target query against the target data source before creating the correlation
+	ValidateTarget bool `json:"-"`
+	// User running the target query when ValidateTarget is set
+	SignedInUser *user.SignedInUser `json:"-"`
+	// Creates the correlation even if an identical one exists when duplicates are rejected
+	Force bool `json:"-"`
+	// Optional UID of the created correlation, generated when empty
+	// example: logs-to-traces
+	UID string `json:"uid,omitempty"`
+	// Target data source UID to which the correlation is created, not set for external correlations
 	// example:PE1C5CBDA0504A6A3
 	TargetUID *string `json:"targetUID"`
 	// Optional label identifying the correlation
@@ -120,16 +728,28 @@ type CreateCorrelationCommand struct {
 	// Arbitrary configuration object handled in frontend
 	// example: { field: "job", type: "query", target: { query: "job=app" } }
 	Config CorrelationConfig `json:"config" binding:"Required"`
+	// Optional position of the correlation among the correlations of its source data source
+	// example: 1
+	Order int `json:"order"`
+	// Optional tags grouping the correlation
+	// example: ["observability"]
+	Tags []string `json:"tags"`
+	// Optional roles and teams the correlation is restricted to
+	// example: { roles: ["Editor"], teams: [3] }
+	Visibility *CorrelationVisibility `json:"visibility,omitempty"`
 }
 
 func (c CreateCorrelationCommand) Validate() error {
-	if err := c.Config.Type.Validate(); err != nil {
+	if c.UID != "" && (!util.IsValidShortUID(c.UID) || util.IsShortUIDTooLong(c.UID)) {
+		return ErrInvalidUID.Errorf("invalid correlation UID %q", c.UID)
+	}
+	if err := validateConfig(c.TargetUID, c.Config); err != nil {
 		return err
 	}
-	if c.TargetUID == nil && c.Config.Type == ConfigTypeQuery {
-		return fmt.Errorf("correlations of type \"%s\" must have a targetUID", ConfigTypeQuery)
+	if err := c.Visibility.Validate(); err != nil {
+		return err
 	}
-	return nil
+	return validateTags(c.Tags)
 }
 
 // swagger:model
@@ -143,7 +763,11 @@ type DeleteCorrelationCommand struct {
 	// UID of the correlation to be deleted.
 	UID       string
 	SourceUID string
-	OrgId     int64
+	// Plugin type of the correlation, when it applies to all the data sources of the type
+	SourceType        string
+	OrgId             int64
+	UserId            int64
+	SkipReadOnlyCheck bool
 }
 
 // swagger:model
@@ -153,22 +777,243 @@ type UpdateCorrelationResponseBody struct {
 	Message string `json:"message"`
 }
 
+// UpsertCorrelationResponseBody is the response of the creation or replacement of a correlation
+// swagger:model
+type UpsertCorrelationResponseBody struct {
+	Result Correlation `json:"result"`
+	// Whether the correlation was created, or replaced when false
+	// example: true
+	Created bool `json:"created"`
+	// example: Correlation created
+	Message string `json:"message"`
+}
+
 // UpdateCorrelationCommand is the command for updating a correlation
 type UpdateCorrelationCommand struct {
 	// UID of the correlation to be deleted.
 	UID       string `json:"-"`
 	SourceUID string `json:"-"`
-	OrgId     int64  `json:"-"`
+	// Plugin type of the correlation, when it applies to all the data sources of the type
+	SourceType        string `json:"-"`
+	OrgId             int64  `json:"-"`
+	SkipReadOnlyCheck bool   `json:"-"`
+	// User updating the correlation, recorded in its version history
+	UserId int64 `json:"-"`
+	// Version restored by the update, if any
+	RestoredFrom int `json:"-"`
+	// Runs the updated target query against the target data source before updating the correlation
+	ValidateTarget bool `json:"-"`
+	// User running the target query when ValidateTarget is set
+	SignedInUser *user.SignedInUser `json:"-"`
+	// Replaces the target data source with TargetUID, set by the replacements of correlations
+	ReplaceTarget bool `json:"-"`
+	// Target data source UID replacing the current one when ReplaceTarget is set, nil for external correlations
+	TargetUID *string `json:"-"`
+	// Merges the target of the config into the current target as a JSON merge patch, instead of replacing it
+	MergeTarget bool `json:"-"`
 
-	// Optional label identifying the correlation
+	// Optional label identifying the correlation, cleared when null
 	// example: My label
 	Label *string `json:"label"`
-	// Optional description of the correlation
+	// Optional description of the correlation, cleared when null
 	// example: Logs to Traces
 	Description *string `json:"description"`
 	// Correlation Configuration
 	// example: { field: "job", type: "query", target: { query: "job=app" } }
 	Config *CorrelationConfigUpdateDTO `json:"config"`
+	// Optional position of the correlation among the correlations of its source data source
+	// example: 1
+	Order *int `json:"order"`
+	// Optional tags replacing the tags of the correlation
+	// example: ["observability"]
+	Tags *[]string `json:"tags"`
+	// Optional roles and teams the correlation is restricted to, visible to all the users when null
+	// example: { roles: ["Editor"], teams: [3] }
+	Visibility *CorrelationVisibility `json:"visibility"`
+}
+
+// UnmarshalJSON tells the fields set to null, which the update clears, from the
+// missing fields, which it leaves unchanged.
+func (cmd *UpdateCorrelationCommand) UnmarshalJSON(data []byte) error {
+	type plain UpdateCorrelationCommand
+	decoded := plain(*cmd)
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	nulls, err := nullFields(data)
+	if err != nil {
+		return err
+	}
+
+	if nulls["label"] {
+		decoded.Label = new(string)
+	}
+	if nulls["description"] {
+		decoded.Description = new(string)
+	}
+	if nulls["order"] {
+		decoded.Order = new(int)
+	}
+	if nulls["tags"] {
+		decoded.Tags = &[]string{}
+	}
+	if nulls["visibility"] {
+		decoded.Visibility = &CorrelationVisibility{}
+	}
+	*cmd = UpdateCorrelationCommand(decoded)
+	return nil
+}
+
+// UnmarshalJSON clears the fields of the config set to null, like
+// UpdateCorrelationCommand. The validation of the updated config rejects the
+// empty values that aren't valid, such as an empty type.
+func (dto *CorrelationConfigUpdateDTO) UnmarshalJSON(data []byte) error {
+	type plain CorrelationConfigUpdateDTO
+	shadowed := struct {
+		plain
+		Target json.RawMessage `json:"target"`
+	}{plain: plain(*dto)}
+	if err := json.Unmarshal(data, &shadowed); err != nil {
+		return err
+	}
+	nulls, err := nullFields(data)
+	if err != nil {
+		return err
+	}
+
+	decoded := shadowed.plain
+	target, targets, err := decodeTarget(shadowed.Target)
+	if err != nil {
+		return err
+	}
+	if targets != nil {
+		decoded.Targets = &targets
+	} else if target != nil {
+		decoded.Target = &target
+	}
+
+	if nulls["field"] {
+		decoded.Field = &CorrelationFields{}
+	}
+	if nulls["type"] {
+		decoded.Type = new(CorrelationConfigType)
+	}
+	if nulls["target"] {
+		decoded.Target = &map[string]interface{}{}
+	}
+	if nulls["transformations"] {
+		decoded.Transformations = &Transformations{}
+	}
+	if nulls["matchers"] {
+		decoded.Matchers = &CorrelationMatchers{}
+	}
+	if nulls["display"] {
+		decoded.Display = &CorrelationDisplay{}
+	}
+	*dto = CorrelationConfigUpdateDTO(decoded)
+	return nil
+}
+
+// nullFields returns the fields of the JSON object set to null
+func nullFields(data []byte) (map[string]bool, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	nulls := make(map[string]bool)
+	for name, value := range fields {
+		if string(value) == "null" {
+			nulls[name] = true
+		}
+	}
+	return nulls, nil
+}
+
+// isEmpty tells whether the command leaves the correlation unchanged
+func (cmd UpdateCorrelationCommand) isEmpty() bool {
+	return cmd.Label == nil && cmd.Description == nil && cmd.Order == nil && cmd.Tags == nil && cmd.Visibility == nil && !cmd.ReplaceTarget &&
+		(cmd.Config == nil || (cmd.Config.Field == nil && cmd.Config.Target == nil && cmd.Config.Targets == nil && cmd.Config.Type == nil && cmd.Config.Transformations == nil && cmd.Config.Matchers == nil && cmd.Config.Display == nil))
+}
+
+// apply sets the fields of the correlation that the command updates
+func (cmd UpdateCorrelationCommand) apply(correlation *Correlation) {
+	if cmd.ReplaceTarget {
+		correlation.TargetUID = cmd.TargetUID
+	}
+	if cmd.Label != nil {
+		correlation.Label = *cmd.Label
+	}
+	if cmd.Description != nil {
+		correlation.Description = *cmd.Description
+	}
+	if cmd.Order != nil {
+		correlation.Order = *cmd.Order
+	}
+	if cmd.Tags != nil {
+		correlation.Tags = normalizeTags(*cmd.Tags)
+	}
+	if cmd.Visibility != nil {
+		correlation.Visibility = cmd.Visibility.normalize()
+	}
+	if cmd.Config != nil {
+		if cmd.Config.Field != nil {
+			correlation.Config.Field = *cmd.Config.Field
+		}
+		if cmd.Config.Type != nil {
+			correlation.Config.Type = *cmd.Config.Type
+		}
+		if cmd.Config.Target != nil {
+			target := *cmd.Config.Target
+			if cmd.MergeTarget {
+				target = mergeTarget(correlation.Config.Target, target)
+			}
+			correlation.Config.Target = target
+			correlation.Config.Targets = nil
+		}
+		if cmd.Config.Targets != nil && *cmd.Config.Targets != nil {
+			correlation.Config.setTargets(*cmd.Config.Targets)
+		}
+		if cmd.Config.Transformations != nil {
+			correlation.Config.Transformations = *cmd.Config.Transformations
+		}
+		if cmd.Config.Matchers != nil {
+			correlation.Config.Matchers = *cmd.Config.Matchers
+		}
+		if cmd.Config.Display != nil {
+			correlation.Config.Display = *cmd.Config.Display
+		}
+	}
+}
+
+// checkMerge checks that the target of the command can be merged into the one
+// of the correlation, which isn't possible for lists of target queries
+func (cmd UpdateCorrelationCommand) checkMerge(correlation Correlation) error {
+	if cmd.MergeTarget && cmd.Config != nil && cmd.Config.Target != nil && correlation.Config.Targets != nil {
+		return ErrInvalidTarget.Errorf("the target of correlation %s is a list of target queries, which can't be merged", correlation.UID)
+	}
+	return nil
+}
+
+// mergeTarget applies the patch to a copy of the target as a JSON merge patch
+// (RFC 7396): the objects are merged recursively, the null values remove their
+// key and the other values, including lists, replace the current ones.
+func mergeTarget(target map[string]interface{}, patch map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(target)+len(patch))
+	for key, value := range target {
+		merged[key] = value
+	}
+	for key, value := range patch {
+		switch patchValue := value.(type) {
+		case nil:
+			delete(merged, key)
+		case map[string]interface{}:
+			current, _ := merged[key].(map[string]interface{})
+			merged[key] = mergeTarget(current, patchValue)
+		default:
+			merged[key] = value
+		}
+	}
+	return merged
 }
 
 // GetCorrelationQuery is the query to retrieve a single correlation
@@ -177,18 +1022,178 @@ type GetCorrelationQuery struct {
 	UID string `json:"-"`
 	// UID of the source data source
 	SourceUID string `json:"-"`
-	OrgId     int64  `json:"-"`
+	// Plugin type of the correlation, when it applies to all the data sources of the type
+	SourceType string `json:"-"`
+	OrgId      int64  `json:"-"`
+	// User reading the correlations, who only gets the ones visible to them
+	SignedInUser *user.SignedInUser `json:"-"`
+}
+
+// RecordCorrelationResolutionCommand is the command for recording that a correlation link was followed
+type RecordCorrelationResolutionCommand struct {
+	UID       string
+	SourceUID string
+	OrgId     int64
+	UserId    int64
+}
+
+// CorrelationResolution is a single resolution of a correlation link, kept
+// until it's aggregated into the daily usage of the correlation
+type CorrelationResolution struct {
+	Id             int64  `xorm:"pk autoincr 'id'"`
+	OrgId          int64  `xorm:"org_id"`
+	CorrelationUID string `xorm:"correlation_uid"`
+	SourceUID      string `xorm:"source_uid"`
+	UserId         int64  `xorm:"user_id"`
+	ResolvedAt     int64  `xorm:"resolved_at"`
+}
+
+func (r CorrelationResolution) TableName() string {
+	return "correlation_resolution"
+}
+
+// CorrelationUsage is the number of resolutions of a correlation during a day
+type CorrelationUsage struct {
+	Id             int64  `json:"-" xorm:"pk autoincr 'id'"`
+	OrgId          int64  `json:"-" xorm:"org_id"`
+	CorrelationUID string `json:"correlationUID" xorm:"correlation_uid"`
+	SourceUID      string `json:"sourceUID" xorm:"source_uid"`
+	// UTC day of the resolutions, formatted as YYYY-MM-DD
+	Day         string `json:"day" xorm:"day"`
+	Resolutions int64  `json:"resolutions" xorm:"resolutions"`
+}
+
+func (u CorrelationUsage) TableName() string {
+	return "correlation_usage"
+}
+
+// CorrelationUsageSummary is the total usage of a correlation, updated when the
+// recorded resolutions are aggregated
+// swagger:model
+type CorrelationUsageSummary struct {
+	Id             int64  `json:"-" xorm:"pk autoincr 'id'"`
+	OrgId          int64  `json:"-" xorm:"org_id"`
+	CorrelationUID string `json:"-" xorm:"correlation_uid"`
+	SourceUID      string `json:"-" xorm:"source_uid"`
+	// Number of times the correlation link was followed
+	// example: 42
+	Resolutions int64 `json:"resolutions" xorm:"resolutions"`
+	// Unix timestamp of the last time the correlation link was followed, 0 if it never was
+	// example: 1666000000
+	LastResolvedAt int64 `json:"lastResolvedAt" xorm:"last_resolved_at"`
+}
+
+func (u CorrelationUsageSummary) TableName() string {
+	return "correlation_usage_summary"
+}
+
+// GetCorrelationUsageQuery is the query to retrieve the daily usage of a correlation
+type GetCorrelationUsageQuery struct {
+	UID       string
+	SourceUID string
+	OrgId     int64
 }
 
 // GetCorrelationsBySourceUIDQuery is the query to retrieve all correlations originating by the given Data Source
 type GetCorrelationsBySourceUIDQuery struct {
 	SourceUID string `json:"-"`
 	OrgId     int64  `json:"-"`
+	// Only the correlations with all the tags
+	Tags []string `json:"-"`
+	// User reading the correlations, who only gets the ones visible to them
+	SignedInUser *user.SignedInUser `json:"-"`
+}
+
+// MaxSourceUIDs is the maximum number of source data sources of a GetCorrelationsBySourceUIDsQuery
+const MaxSourceUIDs = 100
+
+// GetCorrelationsBySourceUIDsQuery is the query to retrieve the correlations originating by any of the given Data Sources
+type GetCorrelationsBySourceUIDsQuery struct {
+	SourceUIDs []string `json:"-"`
+	OrgId      int64    `json:"-"`
+	// Only the correlations with all the tags
+	Tags []string `json:"-"`
+	// User reading the correlations, who only gets the ones visible to them
+	SignedInUser *user.SignedInUser `json:"-"`
+}
+
+func (q GetCorrelationsBySourceUIDsQuery) Validate() error {
+	if len(q.SourceUIDs) == 0 || len(q.SourceUIDs) > MaxSourceUIDs {
+		return ErrInvalidSourceUIDs.Errorf("%d source data sources requested", len(q.SourceUIDs))
+	}
+	for _, uid := range q.SourceUIDs {
+		if uid == "" {
+			return ErrInvalidSourceUIDs.Errorf("empty source data source UID")
+		}
+	}
+	return nil
+}
+
+// GetCorrelationsBySourceTypeQuery is the query to retrieve the correlations applying to all the Data Sources of a plugin type
+type GetCorrelationsBySourceTypeQuery struct {
+	SourceType string `json:"-"`
+	OrgId      int64  `json:"-"`
+	// User reading the correlations, who only gets the ones visible to them
+	SignedInUser *user.SignedInUser `json:"-"`
+}
+
+// GetCorrelationsByTargetUIDQuery is the query to retrieve all correlations pointing at the given Data Source
+type GetCorrelationsByTargetUIDQuery struct {
+	TargetUID string `json:"-"`
+	OrgId     int64  `json:"-"`
+	// User reading the correlations, who only gets the ones visible to them
+	SignedInUser *user.SignedInUser `json:"-"`
 }
 
 // GetCorrelationsQuery is the query to retrieve all correlations
 type GetCorrelationsQuery struct {
 	OrgId int64 `json:"-"`
+	// Include the usage of the correlations
+	WithUsage bool `json:"-"`
+	// Only the correlations with all the tags
+	Tags []string `json:"-"`
+	// User reading the correlations, who only gets the ones visible to them
+	SignedInUser *user.SignedInUser `json:"-"`
+}
+
+const (
+	// MaxSearchQueryLength is the maximum length of a search query
+	MaxSearchQueryLength = 256
+	// DefaultSearchResults is the number of results returned by a search without limit
+	DefaultSearchResults = 20
+	// MaxSearchResults is the maximum number of results returned by a search
+	MaxSearchResults = 100
+)
+
+// SearchCorrelationsQuery is the query to search the correlations of an
+// organization by their label, description and target
+type SearchCorrelationsQuery struct {
+	OrgId int64 `json:"-"`
+	// Text searched in the correlations, case-insensitively
+	Query string `json:"-"`
+	// Maximum number of results, DefaultSearchResults when 0
+	Limit int `json:"-"`
+	// User reading the correlations, who only gets the ones visible to them
+	SignedInUser *user.SignedInUser `json:"-"`
+}
+
+func (q SearchCorrelationsQuery) Validate() error {
+	if q.Query == "" || len(q.Query) > MaxSearchQueryLength || q.Limit < 0 || q.Limit > MaxSearchResults {
+		return ErrInvalidSearchQuery.Errorf("invalid search query %q with limit %d", q.Query, q.Limit)
+	}
+	return nil
+}
+
+// CorrelationSearchResult is a correlation matching a search
+// swagger:model
+type CorrelationSearchResult struct {
+	Correlation
+	// Relevance of the correlation to the search, results are sorted by descending score
+	// example: 20
+	Score int `json:"score"`
+	// Fields of the correlation matching the search, among label, description and target
+	// example: ["target"]
+	Matches []string `json:"matches"`
 }
 
 type DeleteCorrelationsBySourceUIDCommand struct {
@@ -198,3 +1203,492 @@ type DeleteCorrelationsBySourceUIDCommand struct {
 type DeleteCorrelationsByTargetUIDCommand struct {
 	TargetUID string
 }
+
+// UpdateCorrelationsSourceUIDCommand moves the correlations, and their history
+// and usage, from the previous UID of their source data source to its new UID
+type UpdateCorrelationsSourceUIDCommand struct {
+	OrgId       int64
+	PreviousUID string
+	SourceUID   string
+}
+
+// UpdateCorrelationsTargetUIDCommand points the correlations, and their
+// history, at the new UID of their target data source
+type UpdateCorrelationsTargetUIDCommand struct {
+	OrgId       int64
+	PreviousUID string
+	TargetUID   string
+}
+
+type DeleteCorrelationsByDashboardUIDCommand struct {
+	OrgId        int64
+	DashboardUID string
+}
+
+// MaxBulkCorrelations is the maximum number of correlations of a bulk command
+const MaxBulkCorrelations = 500
+
+// BulkCreateCorrelationsCommand is the command for creating a batch of correlations
+// in a single transaction: either all of them are created, or none.
+// swagger:model
+type BulkCreateCorrelationsCommand struct {
+	OrgId  int64 `json:"-"`
+	UserId int64 `json:"-"`
+	// Creates the correlations even if identical ones exist when duplicates are rejected
+	Force bool `json:"-"`
+	// Correlations to create
+	// required:true
+	Correlations []BulkCreateCorrelationsItem `json:"correlations"`
+}
+
+// Validate only checks the size of the batch, the correlations are validated
+// one by one so that each failure is reported.
+func (c BulkCreateCorrelationsCommand) Validate() error {
+	return validateBulkSize(len(c.Correlations))
+}
+
+// BulkCreateCorrelationsItem is a correlation of a bulk create command
+// swagger:model
+type BulkCreateCorrelationsItem struct {
+	// UID of the data source the correlation originates from
+	// example:PE1C5CBDA0504A6A3
+	SourceUID string `json:"sourceUID"`
+	CreateCorrelationCommand
+}
+
+// BulkCreateCorrelationsResponseBody is the response struct for BulkCreateCorrelationsCommand
+// swagger:model
+type BulkCreateCorrelationsResponseBody struct {
+	// Created correlations, in the order of the command
+	Result []Correlation `json:"result"`
+	// example: Correlations created
+	Message string `json:"message"`
+	// Failed correlations, set when none were created
+	Errors []BulkCorrelationError `json:"errors,omitempty"`
+}
+
+// BulkDeleteCorrelationsCommand is the command for deleting a batch of correlations
+// in a single transaction: either all of them are deleted, or none.
+// swagger:model
+type BulkDeleteCorrelationsCommand struct {
+	OrgId  int64 `json:"-"`
+	UserId int64 `json:"-"`
+	// Correlations to delete
+	// required:true
+	Correlations []BulkDeleteCorrelationsItem `json:"correlations"`
+}
+
+func (c BulkDeleteCorrelationsCommand) Validate() error {
+	return validateBulkSize(len(c.Correlations))
+}
+
+// BulkDeleteCorrelationsItem identifies a correlation of a bulk delete command
+// swagger:model
+type BulkDeleteCorrelationsItem struct {
+	// UID of the data source the correlation originates from
+	// example:PE1C5CBDA0504A6A3
+	SourceUID string `json:"sourceUID"`
+	// UID of the correlation
+	// example:50xhMlg9k
+	UID string `json:"uid"`
+}
+
+// BulkDeleteCorrelationsResponseBody is the response struct for BulkDeleteCorrelationsCommand
+// swagger:model
+type BulkDeleteCorrelationsResponseBody struct {
+	// example: Correlations deleted
+	Message string `json:"message"`
+	// Failed correlations, set when none were deleted
+	Errors []BulkCorrelationError `json:"errors,omitempty"`
+}
+
+// CleanupCorrelationsResponseBody is the response struct for the cleanup of the orphaned correlations
+// swagger:model
+type CleanupCorrelationsResponseBody struct {
+	// example: Orphaned correlations deleted
+	Message string `json:"message"`
+	// Number of deleted correlations
+	// example: 2
+	Deleted int64 `json:"deleted"`
+}
+
+// BulkCorrelationError is the failure of a single correlation of a bulk command
+// swagger:model
+type BulkCorrelationError struct {
+	// Position of the correlation in the command
+	Index int `json:"index"`
+	// UID of the data source the correlation originates from
+	SourceUID string `json:"sourceUID"`
+	// UID of the correlation, not set for created correlations
+	UID string `json:"uid,omitempty"`
+	errutil.PublicError
+}
+
+// BulkCorrelationsError is returned by the bulk commands when some of the
+// correlations failed, in which case the whole batch is rolled back.
+type BulkCorrelationsError struct {
+	Errors []BulkCorrelationError
+}
+
+func (e BulkCorrelationsError) Error() string {
+	return fmt.Sprintf("%d of the correlations failed", len(e.Errors))
+}
+
+// StatusCode returns the status shared by all the failures, or 400 if they differ.
+func (e BulkCorrelationsError) StatusCode() int {
+	status := 0
+	for _, failure := range e.Errors {
+		if status != 0 && status != failure.StatusCode {
+			return http.StatusBadRequest
+		}
+		status = failure.StatusCode
+	}
+	if status == 0 {
+		return http.StatusBadRequest
+	}
+	return status
+}
+
+func validateBulkSize(size int) error {
+	if size == 0 {
+		return ErrInvalidBulkCommand.Errorf("no correlations")
+	}
+	if size > MaxBulkCorrelations {
+		return ErrInvalidBulkCommand.Errorf("%d correlations exceed the maximum of %d", size, MaxBulkCorrelations)
+	}
+	return nil
+}
+
+// CorrelationsBundle is the export of the correlations of an organization. The
+// data sources are referenced by name, so that the bundle can be imported into
+// another organization or instance.
+// swagger:model
+type CorrelationsBundle struct {
+	Correlations []BundledCorrelation `json:"correlations"`
+}
+
+// BundledCorrelation is a correlation of a bundle
+// swagger:model
+type BundledCorrelation struct {
+	// Name of the data source the correlation originates from
+	// example: Loki
+	SourceName string `json:"sourceName"`
+	// Name of the data source the correlation points to, not set for external correlations
+	// example: Tempo
+	TargetName *string `json:"targetName"`
+	// example: Logs to Traces
+	Label string `json:"label"`
+	// example: Logs to Traces
+	Description string `json:"description"`
+	// example: { field: "job", type: "query", target: { query: "job=app" } }
+	Config CorrelationConfig `json:"config"`
+	// example: 1
+	Order int `json:"order"`
+	// example: ["observability"]
+	Tags []string `json:"tags,omitempty"`
+}
+
+// ExportCorrelationsQuery is the query to export all the correlations of an organization
+type ExportCorrelationsQuery struct {
+	OrgId int64
+	// User exporting the correlations, who only gets the ones visible to them
+	SignedInUser *user.SignedInUser
+}
+
+// ImportCorrelationsCommand is the command for importing a bundle of correlations
+// in a single transaction: either all of them are created, or none.
+// swagger:model
+type ImportCorrelationsCommand struct {
+	OrgId  int64 `json:"-"`
+	UserId int64 `json:"-"`
+	CorrelationsBundle
+	// Optional mapping of the data source names of the bundle to the UIDs of the
+	// data sources to use instead. The other names are matched by name in the organization.
+	// example: { "Loki": "PE1C5CBDA0504A6A3" }
+	DataSources map[string]string `json:"dataSources"`
+}
+
+// Validate only checks the data source mapping, the correlations are validated
+// one by one so that each failure is reported.
+func (c ImportCorrelationsCommand) Validate() error {
+	return validateDataSourceMapping(c.DataSources)
+}
+
+func validateDataSourceMapping(dataSources map[string]string) error {
+	for name, uid := range dataSources {
+		if uid == "" {
+			return ErrInvalidDataSourceMapping.Errorf("data source %q is mapped to an empty UID", name)
+		}
+	}
+	return nil
+}
+
+// CopyCorrelationsCommand is the command for copying correlations to another
+// organization. Their data sources are matched by name in the destination
+// organization, and the correlations whose data sources can't be matched are
+// skipped. The others are copied in a single transaction: either all of them
+// are created, or none.
+// swagger:model
+type CopyCorrelationsCommand struct {
+	UserId int64 `json:"-"`
+	// ID of the organization to copy the correlations from
+	// required:true
+	// example: 1
+	FromOrgId int64 `json:"fromOrgId"`
+	// ID of the organization to copy the correlations to
+	// required:true
+	// example: 2
+	ToOrgId int64 `json:"toOrgId"`
+	// Correlations to copy
+	// required:true
+	Correlations []CopyCorrelationsItem `json:"correlations"`
+	// Optional mapping of the data source names of the source organization to the
+	// UIDs of the data sources of the destination organization to use instead.
+	// example: { "Loki": "PE1C5CBDA0504A6A3" }
+	DataSources map[string]string `json:"dataSources"`
+}
+
+func (c CopyCorrelationsCommand) Validate() error {
+	if c.FromOrgId <= 0 || c.ToOrgId <= 0 || c.FromOrgId == c.ToOrgId {
+		return ErrInvalidCopyCommand.Errorf("can't copy correlations from org %d to org %d", c.FromOrgId, c.ToOrgId)
+	}
+	if err := validateBulkSize(len(c.Correlations)); err != nil {
+		return err
+	}
+	return validateDataSourceMapping(c.DataSources)
+}
+
+// CopyCorrelationsItem identifies a correlation of a copy command
+// swagger:model
+type CopyCorrelationsItem struct {
+	// UID of the data source the correlation originates from
+	// example:PE1C5CBDA0504A6A3
+	SourceUID string `json:"sourceUID"`
+	// UID of the correlation
+	// example:50xhMlg9k
+	UID string `json:"uid"`
+}
+
+// CopyCorrelationsResponseBody is the response struct for CopyCorrelationsCommand
+// swagger:model
+type CopyCorrelationsResponseBody struct {
+	// Created correlations, in the order of the command
+	Result []Correlation `json:"result"`
+	// example: Correlations copied
+	Message string `json:"message"`
+	// Correlations that were skipped because their data sources don't match any
+	// data source of the destination organization
+	Unmapped []BulkCorrelationError `json:"unmapped,omitempty"`
+	// Failed correlations, set when none were copied
+	Errors []BulkCorrelationError `json:"errors,omitempty"`
+}
+
+// MigrateDerivedFieldsCommand is the command for migrating the derived fields
+// of a Loki data source to correlations. The derived fields linking to a data
+// source are migrated in a single transaction: either all of them are, or none.
+// swagger:model
+type MigrateDerivedFieldsCommand struct {
+	SourceUID string `json:"-"`
+	OrgId     int64  `json:"-"`
+	UserId    int64  `json:"-"`
+	// Returns the correlations the derived fields migrate to without creating them
+	// example: false
+	DryRun bool `json:"dryRun"`
+	// Deletes the migrated derived fields from the data source
+	// example: false
+	DeleteDerivedFields bool `json:"deleteDerivedFields"`
+}
+
+// SkippedDerivedField is a derived field the migration didn't create a correlation for
+// swagger:model
+type SkippedDerivedField struct {
+	// Position of the derived field in the data source
+	// example: 0
+	Index int `json:"index"`
+	// example: traceID
+	Name string `json:"name"`
+	// example: the derived field links to a URL rather than a data source
+	Reason string `json:"reason"`
+	// UID of the existing correlation equivalent to the derived field, which is
+	// deleted like the migrated ones
+	// example: 50xhMlg9k
+	UID string `json:"uid,omitempty"`
+}
+
+// MigrateDerivedFieldsResponseBody is the response struct for MigrateDerivedFieldsCommand
+// swagger:model
+type MigrateDerivedFieldsResponseBody struct {
+	// Created correlations, or the correlations to create on dry runs
+	Result []Correlation `json:"result"`
+	// example: Derived fields migrated
+	Message string `json:"message"`
+	// Derived fields that weren't migrated
+	Skipped []SkippedDerivedField `json:"skipped,omitempty"`
+	// Failed correlations, set when none were created. Their index is the
+	// position of their derived field in the data source.
+	Errors []BulkCorrelationError `json:"errors,omitempty"`
+}
+
+const (
+	VersionActionCreated  = "created"
+	VersionActionUpdated  = "updated"
+	VersionActionRestored = "restored"
+)
+
+// MaxTagLength is the maximum length of the tags of a correlation
+const MaxTagLength = 50
+
+// CorrelationTag is a tag of a correlation, stored with the org of the correlation
+type CorrelationTag struct {
+	Id             int64  `xorm:"pk autoincr 'id'"`
+	OrgId          int64  `xorm:"org_id"`
+	CorrelationUID string `xorm:"correlation_uid"`
+	SourceUID      string `xorm:"source_uid"`
+	Term           string `xorm:"term"`
+}
+
+func (t CorrelationTag) TableName() string {
+	return "correlation_tag"
+}
+
+// CorrelationVersion is a snapshot of a correlation taken every time it is
+// created or updated, with the user who changed it
+// swagger:model
+type CorrelationVersion struct {
+	Id    int64 `json:"-" xorm:"pk autoincr 'id'"`
+	OrgId int64 `json:"-" xorm:"org_id"`
+	// UID of the correlation
+	// example:50xhMlg9k
+	CorrelationUID string `json:"uid" xorm:"correlation_uid"`
+	// UID of the data source the correlation originates from
+	// example:d0oxYRg4z
+	SourceUID string `json:"sourceUID" xorm:"source_uid"`
+	// Version number, starting at 1
+	Version int `json:"version" xorm:"'version'"`
+	// Either "created", "updated" or "restored"
+	Action string `json:"action" xorm:"action"`
+	// Version restored by the change, set for the "restored" action
+	RestoredFrom int `json:"restoredFrom,omitempty" xorm:"restored_from"`
+
+	TargetUID   *string           `json:"targetUID" xorm:"target_uid"`
+	Label       string            `json:"label" xorm:"label"`
+	Description string            `json:"description" xorm:"description"`
+	Config      CorrelationConfig `json:"config" xorm:"jsonb config"`
+
+	// ID of the user who made the change, 0 for changes made by Grafana itself
+	CreatedBy int64     `json:"createdBy" xorm:"created_by"`
+	Created   time.Time `json:"created" xorm:"created"`
+}
+
+func (v CorrelationVersion) TableName() string {
+	return "correlation_version"
+}
+
+// GetCorrelationVersionsQuery is the query to retrieve the versions of a correlation, latest first
+type GetCorrelationVersionsQuery struct {
+	UID       string
+	SourceUID string
+	OrgId     int64
+	// User reading the versions, who only gets the ones of the correlations visible to them
+	SignedInUser *user.SignedInUser
+}
+
+// RestoreCorrelationVersionCommand is the command for restoring a previous version of a correlation.
+// The restore is recorded as a new version.
+type RestoreCorrelationVersionCommand struct {
+	UID       string
+	SourceUID string
+	OrgId     int64
+	Version   int
+	UserId    int64
+}
+
+const (
+	AuditActionCreated  = "created"
+	AuditActionUpdated  = "updated"
+	AuditActionRestored = "restored"
+	AuditActionDeleted  = "deleted"
+)
+
+// AuditAnnotationTag tags the organization annotations recording the changes of correlations
+const AuditAnnotationTag = "correlations"
+
+// CorrelationAuditEvent describes a change of a correlation, it is logged and
+// stored as the data of an organization annotation
+type CorrelationAuditEvent struct {
+	OrgId     int64  `json:"orgId"`
+	UserId    int64  `json:"userId"`
+	UID       string `json:"uid"`
+	SourceUID string `json:"sourceUID"`
+	Action    string `json:"action"`
+	// Changed fields of the correlation, by name
+	Diff map[string]CorrelationFieldChange `json:"diff,omitempty"`
+}
+
+type CorrelationFieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+type CorrelationHealthStatus string
+
+const (
+	// HealthOK checks passed.
+	HealthOK CorrelationHealthStatus = "ok"
+	// HealthError checks found a problem with the correlation.
+	HealthError CorrelationHealthStatus = "error"
+	// HealthSkipped checks don't apply to the correlation, or weren't requested.
+	HealthSkipped CorrelationHealthStatus = "skipped"
+)
+
+// Checks of the health of a correlation
+const (
+	HealthCheckSource       = "source"
+	HealthCheckTarget       = "target"
+	HealthCheckConfig       = "config"
+	HealthCheckPlaceholders = "placeholders"
+	HealthCheckTargetQuery  = "targetQuery"
+)
+
+// CorrelationHealthQuery is the query to check the health of a correlation
+type CorrelationHealthQuery struct {
+	UID       string
+	SourceUID string
+	OrgId     int64
+	// Runs the target query against the target data source
+	ValidateTarget bool
+	// User running the target query when ValidateTarget is set
+	SignedInUser *user.SignedInUser
+}
+
+// CorrelationHealth is the health report of a correlation
+// swagger:model
+type CorrelationHealth struct {
+	// example: 50xhMlg9k
+	UID string `json:"uid"`
+	// Empty for the correlations of a data source type
+	// example: d0oxYRg4z
+	SourceUID string `json:"sourceUID"`
+	// Whether none of the checks failed
+	// example: false
+	Healthy bool `json:"healthy"`
+	// Checks in the order they ran
+	Checks []CorrelationHealthCheck `json:"checks"`
+}
+
+// CorrelationHealthCheck is the result of a check of the health of a correlation
+// swagger:model
+type CorrelationHealthCheck struct {
+	// Name of the check, either "source", "target", "config", "placeholders" or "targetQuery"
+	// example: target
+	Name string `json:"name"`
+	// Status of the check, either "ok", "error" or "skipped"
+	// example: error
+	Status CorrelationHealthStatus `json:"status"`
+	// Message ID of the error of failed checks
+	// example: correlations.targetDataSourceNotFound
+	MessageID string `json:"messageId,omitempty"`
+	// Why the check failed or was skipped
+	// example: target data source PE1C5CBDA0504A6A3 does not exist
+	Message string `json:"message,omitempty"`
+}
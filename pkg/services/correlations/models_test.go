@@ -2,8 +2,11 @@ package correlations
 
 import (
 	"encoding/json"
+	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/grafana/grafana/pkg/util/errutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,7 +15,7 @@ func TestCorrelationModels(t *testing.T) {
 		t.Run("Successfully validates a correct create command", func(t *testing.T) {
 			targetUid := "targetUid"
 			config := &CorrelationConfig{
-				Field:  "field",
+				Field:  CorrelationFields{"field"},
 				Target: map[string]interface{}{},
 				Type:   ConfigTypeQuery,
 			}
@@ -26,9 +29,28 @@ func TestCorrelationModels(t *testing.T) {
 			require.NoError(t, cmd.Validate())
 		})
 
+		t.Run("Validates the format of the UID", func(t *testing.T) {
+			targetUid := "targetUid"
+			cmd := &CreateCorrelationCommand{
+				SourceUID: "some-uid",
+				OrgId:     1,
+				TargetUID: &targetUid,
+				Config:    CorrelationConfig{Field: CorrelationFields{"field"}, Target: map[string]interface{}{}, Type: ConfigTypeQuery},
+			}
+
+			cmd.UID = "logs-to-traces_1"
+			require.NoError(t, cmd.Validate())
+
+			cmd.UID = "logs to traces"
+			require.ErrorIs(t, cmd.Validate(), ErrInvalidUID)
+
+			cmd.UID = strings.Repeat("a", 41)
+			require.ErrorIs(t, cmd.Validate(), ErrInvalidUID)
+		})
+
 		t.Run("Fails if target UID is not set and config type = query", func(t *testing.T) {
 			config := &CorrelationConfig{
-				Field:  "field",
+				Field:  CorrelationFields{"field"},
 				Target: map[string]interface{}{},
 				Type:   ConfigTypeQuery,
 			}
@@ -43,7 +65,7 @@ func TestCorrelationModels(t *testing.T) {
 
 		t.Run("Fails if config type is unknown", func(t *testing.T) {
 			config := &CorrelationConfig{
-				Field:  "field",
+				Field:  CorrelationFields{"field"},
 				Target: map[string]interface{}{},
 				Type:   "unknown config type",
 			}
@@ -57,6 +79,226 @@ func TestCorrelationModels(t *testing.T) {
 		})
 	})
 
+	t.Run("CreateCorrelationCommand Validate tags", func(t *testing.T) {
+		config := CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeExternal, Target: map[string]interface{}{"url": "https://example.com/${value}"}}
+
+		cmd := &CreateCorrelationCommand{SourceUID: "some-uid", OrgId: 1, Config: config, Tags: []string{"observability", "billing"}}
+		require.NoError(t, cmd.Validate())
+
+		for _, tag := range []string{"", strings.Repeat("a", MaxTagLength+1)} {
+			cmd := &CreateCorrelationCommand{SourceUID: "some-uid", OrgId: 1, Config: config, Tags: []string{"observability", tag}}
+			require.ErrorIs(t, cmd.Validate(), ErrInvalidTag)
+		}
+	})
+
+	t.Run("SearchCorrelationsQuery Validate", func(t *testing.T) {
+		require.NoError(t, SearchCorrelationsQuery{OrgId: 1, Query: "checkout"}.Validate())
+		require.NoError(t, SearchCorrelationsQuery{OrgId: 1, Query: "checkout", Limit: MaxSearchResults}.Validate())
+
+		for _, query := range []SearchCorrelationsQuery{
+			{OrgId: 1},
+			{OrgId: 1, Query: strings.Repeat("a", MaxSearchQueryLength+1)},
+			{OrgId: 1, Query: "checkout", Limit: -1},
+			{OrgId: 1, Query: "checkout", Limit: MaxSearchResults + 1},
+		} {
+			require.ErrorIs(t, query.Validate(), ErrInvalidSearchQuery, query)
+		}
+	})
+
+	t.Run("CreateCorrelationCommand Validate for external correlations", func(t *testing.T) {
+		external := func(target map[string]interface{}) CorrelationConfig {
+			return CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeExternal, Target: target}
+		}
+
+		t.Run("Successfully validates a URL template", func(t *testing.T) {
+			cmd := &CreateCorrelationCommand{SourceUID: "some-uid", OrgId: 1, Config: external(map[string]interface{}{"url": "https://tickets.example.com/search?q=${value}"})}
+			require.NoError(t, cmd.Validate())
+		})
+
+		t.Run("Fails if target UID is set", func(t *testing.T) {
+			targetUid := "targetUid"
+			cmd := &CreateCorrelationCommand{SourceUID: "some-uid", OrgId: 1, TargetUID: &targetUid, Config: external(map[string]interface{}{"url": "https://example.com/${value}"})}
+			require.ErrorIs(t, cmd.Validate(), ErrInvalidTarget)
+		})
+
+		t.Run("Fails without a valid http URL", func(t *testing.T) {
+			for _, target := range []map[string]interface{}{
+				{},
+				{"url": 42},
+				{"url": "${value}"},
+				{"url": "javascript:alert(${value})"},
+			} {
+				cmd := &CreateCorrelationCommand{SourceUID: "some-uid", OrgId: 1, Config: external(target)}
+				require.ErrorIs(t, cmd.Validate(), ErrInvalidTarget, target)
+			}
+		})
+	})
+
+	t.Run("CreateCorrelationCommand Validate for dashboard correlations", func(t *testing.T) {
+		dashboard := func(target map[string]interface{}) CorrelationConfig {
+			return CorrelationConfig{Field: CorrelationFields{"service"}, Type: ConfigTypeDashboard, Target: target}
+		}
+
+		t.Run("Successfully validates a dashboard target", func(t *testing.T) {
+			for _, target := range []map[string]interface{}{
+				{"dashboardUID": "checkout"},
+				{"dashboardUID": "checkout", "panelId": float64(2), "variables": map[string]interface{}{"service": "${service}"}},
+			} {
+				cmd := &CreateCorrelationCommand{SourceUID: "some-uid", OrgId: 1, Config: dashboard(target)}
+				require.NoError(t, cmd.Validate(), target)
+			}
+		})
+
+		t.Run("Fails if target UID is set", func(t *testing.T) {
+			targetUid := "targetUid"
+			cmd := &CreateCorrelationCommand{SourceUID: "some-uid", OrgId: 1, TargetUID: &targetUid, Config: dashboard(map[string]interface{}{"dashboardUID": "checkout"})}
+			require.ErrorIs(t, cmd.Validate(), ErrInvalidTarget)
+		})
+
+		t.Run("Fails without a valid dashboard target", func(t *testing.T) {
+			for _, target := range []map[string]interface{}{
+				{},
+				{"dashboardUID": 42},
+				{"dashboardUID": "checkout", "panelId": float64(0)},
+				{"dashboardUID": "checkout", "panelId": 1.5},
+				{"dashboardUID": "checkout", "panelId": "2"},
+				{"dashboardUID": "checkout", "variables": "service=${service}"},
+				{"dashboardUID": "checkout", "variables": map[string]interface{}{"service": 42}},
+			} {
+				cmd := &CreateCorrelationCommand{SourceUID: "some-uid", OrgId: 1, Config: dashboard(target)}
+				require.ErrorIs(t, cmd.Validate(), ErrInvalidTarget, target)
+			}
+		})
+	})
+
+	t.Run("CreateCorrelationCommand Validate with transformations", func(t *testing.T) {
+		targetUid := "targetUid"
+		withTransformations := func(transformations ...Transformation) *CreateCorrelationCommand {
+			return &CreateCorrelationCommand{
+				SourceUID: "some-uid",
+				OrgId:     1,
+				TargetUID: &targetUid,
+				Config:    CorrelationConfig{Field: CorrelationFields{"message"}, Type: ConfigTypeQuery, Transformations: transformations},
+			}
+		}
+
+		t.Run("Successfully validates correct transformations", func(t *testing.T) {
+			cmd := withTransformations(
+				Transformation{Type: TransformationLogfmt, Field: "traceId"},
+				Transformation{Type: TransformationRegex, Expression: `^(\w+)-`, MapValue: "prefix"},
+				Transformation{Type: TransformationMapping, Mapping: map[string]string{"error": "level=error"}},
+			)
+			require.NoError(t, cmd.Validate())
+		})
+
+		t.Run("Fails for invalid transformations", func(t *testing.T) {
+			for _, transformation := range []Transformation{
+				{Type: "unknown"},
+				{Type: TransformationRegex},
+				{Type: TransformationRegex, Expression: "(unclosed"},
+				{Type: TransformationMapping},
+			} {
+				cmd := withTransformations(transformation)
+				require.ErrorIs(t, cmd.Validate(), ErrInvalidTransformation, transformation)
+			}
+		})
+	})
+
+	t.Run("CreateCorrelationCommand Validate with matchers", func(t *testing.T) {
+		targetUid := "targetUid"
+		withMatchers := func(matchers ...CorrelationMatcher) *CreateCorrelationCommand {
+			return &CreateCorrelationCommand{
+				SourceUID: "some-uid",
+				OrgId:     1,
+				TargetUID: &targetUid,
+				Config:    CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: ConfigTypeQuery, Matchers: matchers},
+			}
+		}
+
+		t.Run("Successfully validates correct matchers", func(t *testing.T) {
+			cmd := withMatchers(
+				CorrelationMatcher{Field: "service", Operator: MatcherEquals, Value: "checkout"},
+				CorrelationMatcher{Field: "namespace", Operator: MatcherNotEquals},
+				CorrelationMatcher{Field: "level", Operator: MatcherRegex, Value: "error|warn"},
+				CorrelationMatcher{Field: "job", Operator: MatcherNotRegex, Value: "^test-"},
+			)
+			require.NoError(t, cmd.Validate())
+		})
+
+		t.Run("Fails for invalid matchers", func(t *testing.T) {
+			for _, matcher := range []CorrelationMatcher{
+				{Operator: MatcherEquals, Value: "checkout"},
+				{Field: "service", Operator: "unknown"},
+				{Field: "service", Operator: MatcherRegex, Value: "(unclosed"},
+			} {
+				cmd := withMatchers(matcher)
+				require.ErrorIs(t, cmd.Validate(), ErrInvalidMatcher, matcher)
+			}
+		})
+	})
+
+	t.Run("CreateCorrelationCommand Validate with placeholders", func(t *testing.T) {
+		targetUid := "targetUid"
+		withQuery := func(query string, fields ...string) *CreateCorrelationCommand {
+			return &CreateCorrelationCommand{
+				SourceUID: "some-uid",
+				OrgId:     1,
+				TargetUID: &targetUid,
+				Config: CorrelationConfig{
+					Field:           fields,
+					Type:            ConfigTypeQuery,
+					Target:          map[string]interface{}{"expr": query, "queries": []interface{}{map[string]interface{}{"expr": "{job=\"app\"}"}}},
+					Transformations: Transformations{{Type: TransformationRegex, Expression: `(\w+)`, MapValue: "prefix"}, {Type: TransformationLogfmt, Field: "level"}},
+				},
+			}
+		}
+
+		t.Run("Successfully validates resolvable placeholders", func(t *testing.T) {
+			for _, query := range []string{
+				`{job="${value}"}`,
+				`{job="${__value.raw}", namespace="${namespace}"}`,
+				`{job="${__value.raw:queryparam}"} |= "${prefix}" |= "${level}"`,
+				`{namespace="${__data.fields.namespace}", job="${__data.fields["job"]}"}`,
+				`sum(rate(requests[${__interval}])) @ ${__to}`,
+			} {
+				require.NoError(t, withQuery(query, "job", "namespace").Validate(), query)
+			}
+		})
+
+		t.Run("Fails for unresolvable placeholders", func(t *testing.T) {
+			for _, tc := range []struct {
+				query       string
+				fields      []string
+				placeholder string
+			}{
+				{query: `{job="${value}"}`, placeholder: "${value}"},
+				{query: `{job="${__value.raw}"}`, placeholder: "${__value.raw}"},
+				{query: `{job="${__value.unknown}"}`, fields: []string{"job"}, placeholder: "${__value.unknown}"},
+				{query: `{job="${job}", namespace="${namespace}"}`, fields: []string{"job"}, placeholder: "${namespace}"},
+				{query: `{namespace="${__data.fields.namespace}"}`, fields: []string{"job"}, placeholder: "${__data.fields.namespace}"},
+				{query: `{job="${__data.fields[0]}"}`, fields: []string{"job"}, placeholder: "${__data.fields[0]}"},
+				{query: `{job="${}"}`, fields: []string{"job"}, placeholder: "${}"},
+			} {
+				err := withQuery(tc.query, tc.fields...).Validate()
+				require.ErrorIs(t, err, ErrInvalidPlaceholder, tc.query)
+
+				var grafanaErr errutil.Error
+				require.ErrorAs(t, err, &grafanaErr)
+				require.Equal(t, tc.placeholder, grafanaErr.Public().Extra["placeholder"])
+				require.Equal(t, http.StatusBadRequest, grafanaErr.Public().StatusCode)
+			}
+		})
+
+		t.Run("Checks the placeholders of external correlations", func(t *testing.T) {
+			cmd := &CreateCorrelationCommand{SourceUID: "some-uid", OrgId: 1, Config: CorrelationConfig{
+				Field:  CorrelationFields{"ticket"},
+				Type:   ConfigTypeExternal,
+				Target: map[string]interface{}{"url": "https://tickets.example.com/${ticket}/${comment}"},
+			}}
+			require.ErrorIs(t, cmd.Validate(), ErrInvalidPlaceholder)
+		})
+	})
+
 	t.Run("CorrelationConfigType Validate", func(t *testing.T) {
 		t.Run("Successfully validates a correct type", func(t *testing.T) {
 			type test struct {
@@ -66,6 +308,8 @@ func TestCorrelationModels(t *testing.T) {
 
 			tests := []test{
 				{input: "query", assertion: require.NoError},
+				{input: "external", assertion: require.NoError},
+				{input: "dashboard", assertion: require.NoError},
 				{input: "link", assertion: require.Error},
 			}
 
@@ -78,14 +322,342 @@ func TestCorrelationModels(t *testing.T) {
 	t.Run("CorrelationConfig JSON Marshaling", func(t *testing.T) {
 		t.Run("Applies a default empty object if target is not defined", func(t *testing.T) {
 			config := CorrelationConfig{
-				Field: "field",
+				Field: CorrelationFields{"field"},
 				Type:  ConfigTypeQuery,
 			}
 
 			data, err := json.Marshal(config)
 			require.NoError(t, err)
 
-			require.Equal(t, `{"type":"query","field":"field","target":{}}`, string(data))
+			require.Equal(t, `{"type":"query","field":["field"],"target":{}}`, string(data))
+		})
+
+		t.Run("Keeps the config type", func(t *testing.T) {
+			config := CorrelationConfig{
+				Field:  CorrelationFields{"field"},
+				Type:   ConfigTypeExternal,
+				Target: map[string]interface{}{"url": "https://example.com/${value}"},
+			}
+
+			data, err := json.Marshal(config)
+			require.NoError(t, err)
+
+			require.Equal(t, `{"type":"external","field":["field"],"target":{"url":"https://example.com/${value}"}}`, string(data))
+		})
+
+		t.Run("Keeps the transformations", func(t *testing.T) {
+			config := CorrelationConfig{
+				Field:           CorrelationFields{"message"},
+				Type:            ConfigTypeQuery,
+				Transformations: Transformations{{Type: TransformationLogfmt, Field: "traceId"}},
+			}
+
+			data, err := json.Marshal(config)
+			require.NoError(t, err)
+
+			require.Equal(t, `{"type":"query","field":["message"],"target":{},"transformations":[{"type":"logfmt","field":"traceId"}]}`, string(data))
+
+			var unmarshaled CorrelationConfig
+			require.NoError(t, json.Unmarshal(data, &unmarshaled))
+			require.Equal(t, config.Transformations, unmarshaled.Transformations)
+		})
+
+		t.Run("Keeps the matchers", func(t *testing.T) {
+			config := CorrelationConfig{
+				Field:    CorrelationFields{"traceId"},
+				Type:     ConfigTypeQuery,
+				Matchers: CorrelationMatchers{{Field: "service", Operator: MatcherEquals, Value: "checkout"}},
+			}
+
+			data, err := json.Marshal(config)
+			require.NoError(t, err)
+
+			require.Equal(t, `{"type":"query","field":["traceId"],"target":{},"matchers":[{"field":"service","operator":"equals","value":"checkout"}]}`, string(data))
+
+			var unmarshaled CorrelationConfig
+			require.NoError(t, json.Unmarshal(data, &unmarshaled))
+			require.Equal(t, config.Matchers, unmarshaled.Matchers)
+		})
+
+		t.Run("Encodes the fields as a list", func(t *testing.T) {
+			config := CorrelationConfig{
+				Type: ConfigTypeQuery,
+			}
+
+			data, err := json.Marshal(config)
+			require.NoError(t, err)
+
+			require.Equal(t, `{"type":"query","field":[],"target":{}}`, string(data))
+		})
+	})
+
+	t.Run("CorrelationConfig JSON Unmarshaling", func(t *testing.T) {
+		t.Run("Accepts a single field", func(t *testing.T) {
+			var config CorrelationConfig
+			require.NoError(t, json.Unmarshal([]byte(`{"type":"query","field":"job","target":{}}`), &config))
+			require.Equal(t, CorrelationFields{"job"}, config.Field)
+		})
+
+		t.Run("Accepts a list of fields", func(t *testing.T) {
+			var config CorrelationConfig
+			require.NoError(t, json.Unmarshal([]byte(`{"type":"query","field":["job","namespace"],"target":{}}`), &config))
+			require.Equal(t, CorrelationFields{"job", "namespace"}, config.Field)
+		})
+
+		t.Run("Decodes an empty field as no fields", func(t *testing.T) {
+			var config CorrelationConfig
+			require.NoError(t, json.Unmarshal([]byte(`{"type":"query","field":"","target":{}}`), &config))
+			require.Empty(t, config.Field)
+		})
+
+		t.Run("Fails if the fields are not strings", func(t *testing.T) {
+			var config CorrelationConfig
+			require.Error(t, json.Unmarshal([]byte(`{"type":"query","field":2,"target":{}}`), &config))
+		})
+
+		t.Run("Round-trips the display options", func(t *testing.T) {
+			var config CorrelationConfig
+			require.NoError(t, json.Unmarshal([]byte(`{"type":"query","field":["traceId"],"target":{},"display":{"openIn":"split","title":"Trace"}}`), &config))
+			require.Equal(t, CorrelationDisplay{OpenIn: OpenInSplitView, Title: "Trace"}, config.Display)
+
+			data, err := json.Marshal(config)
+			require.NoError(t, err)
+			require.Equal(t, `{"type":"query","field":["traceId"],"target":{},"display":{"openIn":"split","title":"Trace"}}`, string(data))
+		})
+
+		t.Run("Round-trips a single target", func(t *testing.T) {
+			var config CorrelationConfig
+			require.NoError(t, json.Unmarshal([]byte(`{"type":"query","field":["traceId"],"target":{"query":"${traceId}"}}`), &config))
+			require.Equal(t, map[string]interface{}{"query": "${traceId}"}, config.Target)
+			require.Nil(t, config.Targets)
+
+			data, err := json.Marshal(config)
+			require.NoError(t, err)
+			require.Equal(t, `{"type":"query","field":["traceId"],"target":{"query":"${traceId}"}}`, string(data))
+		})
+
+		t.Run("Round-trips a list of targets", func(t *testing.T) {
+			var config CorrelationConfig
+			require.NoError(t, json.Unmarshal([]byte(`{"type":"query","field":["traceId"],"target":[{"query":"${traceId}"},{"expr":"rate(${traceId})"}]}`), &config))
+			require.Equal(t, map[string]interface{}{"query": "${traceId}"}, config.Target)
+			require.Len(t, config.Targets, 2)
+			require.Len(t, config.targetQueries(), 2)
+
+			data, err := json.Marshal(config)
+			require.NoError(t, err)
+			require.Equal(t, `{"type":"query","field":["traceId"],"target":[{"query":"${traceId}"},{"expr":"rate(${traceId})"}]}`, string(data))
+		})
+
+		t.Run("Fails if the target is neither an object nor a list of objects", func(t *testing.T) {
+			var config CorrelationConfig
+			require.Error(t, json.Unmarshal([]byte(`{"type":"query","field":"job","target":"job"}`), &config))
+			require.Error(t, json.Unmarshal([]byte(`{"type":"query","field":"job","target":["job"]}`), &config))
+		})
+	})
+
+	t.Run("CreateCorrelationCommand Validate with display options", func(t *testing.T) {
+		cmd := func(display CorrelationDisplay) CreateCorrelationCommand {
+			return CreateCorrelationCommand{
+				SourceUID: "some-uid",
+				OrgId:     1,
+				Config: CorrelationConfig{
+					Field:   CorrelationFields{"traceId"},
+					Type:    ConfigTypeExternal,
+					Target:  map[string]interface{}{"url": "https://example.com/${traceId}"},
+					Display: display,
+				},
+			}
+		}
+
+		t.Run("Successfully validates correct display options", func(t *testing.T) {
+			require.NoError(t, cmd(CorrelationDisplay{}).Validate())
+			require.NoError(t, cmd(CorrelationDisplay{OpenIn: OpenInNewTab, Icon: "external-link-alt", Title: "Trace ${traceId}"}).Validate())
+		})
+
+		t.Run("Fails for invalid display options", func(t *testing.T) {
+			for _, display := range []CorrelationDisplay{
+				{OpenIn: "popup"},
+				{Icon: "<svg>"},
+				{Title: strings.Repeat("a", MaxLinkTitleLength+1)},
+				{Title: "Trace ${spanId}"},
+			} {
+				require.ErrorIs(t, cmd(display).Validate(), ErrInvalidDisplay)
+			}
+		})
+	})
+
+	t.Run("CreateCorrelationCommand Validate with a list of targets", func(t *testing.T) {
+		targetUid := "targetUid"
+		cmd := func(configType CorrelationConfigType, targets ...map[string]interface{}) CreateCorrelationCommand {
+			config := CorrelationConfig{Field: CorrelationFields{"traceId"}, Type: configType}
+			config.setTargets(append([]map[string]interface{}{}, targets...))
+			return CreateCorrelationCommand{SourceUID: "some-uid", OrgId: 1, TargetUID: &targetUid, Config: config}
+		}
+
+		t.Run("Successfully validates the target queries", func(t *testing.T) {
+			require.NoError(t, cmd(ConfigTypeQuery, map[string]interface{}{"query": "${traceId}"}, map[string]interface{}{"expr": "up"}).Validate())
+		})
+
+		t.Run("Checks the placeholders of every target query", func(t *testing.T) {
+			err := cmd(ConfigTypeQuery, map[string]interface{}{"query": "${traceId}"}, map[string]interface{}{"expr": "${spanId}"}).Validate()
+			require.ErrorIs(t, err, ErrInvalidPlaceholder)
+		})
+
+		t.Run("Fails for an empty list", func(t *testing.T) {
+			require.ErrorIs(t, cmd(ConfigTypeQuery).Validate(), ErrInvalidTarget)
+		})
+
+		t.Run("Fails for other types than query", func(t *testing.T) {
+			c := cmd(ConfigTypeExternal, map[string]interface{}{"url": "https://example.com/${traceId}"})
+			c.TargetUID = nil
+			require.ErrorIs(t, c.Validate(), ErrInvalidTarget)
+		})
+	})
+	t.Run("Bulk commands", func(t *testing.T) {
+		t.Run("Decodes the source of every correlation", func(t *testing.T) {
+			var cmd BulkCreateCorrelationsCommand
+			err := json.Unmarshal([]byte(`{"correlations": [{"sourceUID": "source-uid", "label": "label", "config": {"field": "traceId", "type": "query", "target": {}}}]}`), &cmd)
+			require.NoError(t, err)
+
+			require.Len(t, cmd.Correlations, 1)
+			require.Equal(t, "source-uid", cmd.Correlations[0].SourceUID)
+			require.Equal(t, "label", cmd.Correlations[0].Label)
+			require.Equal(t, ConfigTypeQuery, cmd.Correlations[0].Config.Type)
+		})
+
+		t.Run("Fails without correlations", func(t *testing.T) {
+			require.ErrorIs(t, BulkCreateCorrelationsCommand{}.Validate(), ErrInvalidBulkCommand)
+			require.ErrorIs(t, BulkDeleteCorrelationsCommand{}.Validate(), ErrInvalidBulkCommand)
+		})
+
+		t.Run("Fails with too many correlations", func(t *testing.T) {
+			cmd := BulkDeleteCorrelationsCommand{Correlations: make([]BulkDeleteCorrelationsItem, MaxBulkCorrelations+1)}
+			require.ErrorIs(t, cmd.Validate(), ErrInvalidBulkCommand)
+
+			cmd.Correlations = cmd.Correlations[:MaxBulkCorrelations]
+			require.NoError(t, cmd.Validate())
+		})
+
+		t.Run("Reports the status shared by the failures", func(t *testing.T) {
+			notFound := BulkCorrelationError{PublicError: errutil.PublicError{StatusCode: http.StatusNotFound}}
+			forbidden := BulkCorrelationError{PublicError: errutil.PublicError{StatusCode: http.StatusForbidden}}
+
+			require.Equal(t, http.StatusNotFound, BulkCorrelationsError{Errors: []BulkCorrelationError{notFound, notFound}}.StatusCode())
+			require.Equal(t, http.StatusBadRequest, BulkCorrelationsError{Errors: []BulkCorrelationError{notFound, forbidden}}.StatusCode())
+		})
+	})
+	t.Run("CopyCorrelationsCommand Validate", func(t *testing.T) {
+		items := []CopyCorrelationsItem{{SourceUID: "source-uid", UID: "uid"}}
+
+		t.Run("Successfully validates a copy to another org", func(t *testing.T) {
+			require.NoError(t, CopyCorrelationsCommand{FromOrgId: 1, ToOrgId: 2, Correlations: items}.Validate())
+		})
+
+		t.Run("Fails to copy within the same org", func(t *testing.T) {
+			require.ErrorIs(t, CopyCorrelationsCommand{FromOrgId: 1, ToOrgId: 1, Correlations: items}.Validate(), ErrInvalidCopyCommand)
+			require.ErrorIs(t, CopyCorrelationsCommand{FromOrgId: 1, Correlations: items}.Validate(), ErrInvalidCopyCommand)
+		})
+
+		t.Run("Fails without correlations", func(t *testing.T) {
+			require.ErrorIs(t, CopyCorrelationsCommand{FromOrgId: 1, ToOrgId: 2}.Validate(), ErrInvalidBulkCommand)
+		})
+
+		t.Run("Fails if a data source is mapped to an empty UID", func(t *testing.T) {
+			cmd := CopyCorrelationsCommand{FromOrgId: 1, ToOrgId: 2, Correlations: items, DataSources: map[string]string{"Loki": ""}}
+			require.ErrorIs(t, cmd.Validate(), ErrInvalidDataSourceMapping)
+		})
+	})
+
+	t.Run("UpdateCorrelationCommand JSON Unmarshaling", func(t *testing.T) {
+		t.Run("leaves the missing fields unchanged", func(t *testing.T) {
+			var cmd UpdateCorrelationCommand
+			require.NoError(t, json.Unmarshal([]byte(`{"label": "logs"}`), &cmd))
+			require.Equal(t, "logs", *cmd.Label)
+			require.Nil(t, cmd.Description)
+			require.Nil(t, cmd.Config)
+			require.Nil(t, cmd.Tags)
+		})
+
+		t.Run("clears the fields set to null", func(t *testing.T) {
+			var cmd UpdateCorrelationCommand
+			require.NoError(t, json.Unmarshal([]byte(`{"label": null, "description": null, "tags": null, "config": {"field": null, "matchers": null}}`), &cmd))
+			require.Equal(t, "", *cmd.Label)
+			require.Equal(t, "", *cmd.Description)
+			require.Empty(t, *cmd.Tags)
+			require.Empty(t, *cmd.Config.Field)
+			require.Empty(t, *cmd.Config.Matchers)
+			require.Nil(t, cmd.Config.Type)
+			require.Nil(t, cmd.Config.Target)
+			require.False(t, cmd.isEmpty())
+		})
+
+		t.Run("leaves the config unchanged when it's null", func(t *testing.T) {
+			var cmd UpdateCorrelationCommand
+			require.NoError(t, json.Unmarshal([]byte(`{"config": null}`), &cmd))
+			require.Nil(t, cmd.Config)
+			require.True(t, cmd.isEmpty())
+		})
+
+		t.Run("accepts a list of targets", func(t *testing.T) {
+			var cmd UpdateCorrelationCommand
+			require.NoError(t, json.Unmarshal([]byte(`{"config": {"target": [{"query": "a"}, {"query": "b"}]}}`), &cmd))
+			require.Nil(t, cmd.Config.Target)
+			require.Len(t, *cmd.Config.Targets, 2)
+
+			correlation := Correlation{Config: CorrelationConfig{Target: map[string]interface{}{"query": "c"}}}
+			cmd.apply(&correlation)
+			require.Equal(t, map[string]interface{}{"query": "a"}, correlation.Config.Target)
+			require.Len(t, correlation.Config.Targets, 2)
+
+			var single UpdateCorrelationCommand
+			require.NoError(t, json.Unmarshal([]byte(`{"config": {"target": {"query": "c"}}}`), &single))
+			single.apply(&correlation)
+			require.Equal(t, map[string]interface{}{"query": "c"}, correlation.Config.Target)
+			require.Nil(t, correlation.Config.Targets)
+		})
+
+		t.Run("clears the display options set to null", func(t *testing.T) {
+			var cmd UpdateCorrelationCommand
+			require.NoError(t, json.Unmarshal([]byte(`{"config": {"display": null}}`), &cmd))
+			require.False(t, cmd.isEmpty())
+
+			correlation := Correlation{Config: CorrelationConfig{Display: CorrelationDisplay{OpenIn: OpenInNewTab}}}
+			cmd.apply(&correlation)
+			require.Equal(t, CorrelationDisplay{}, correlation.Config.Display)
+		})
+
+		t.Run("merges the target with mergeTarget", func(t *testing.T) {
+			var cmd UpdateCorrelationCommand
+			require.NoError(t, json.Unmarshal([]byte(`{"config": {"target": {"limit": 50, "step": null, "labels": {"env": "prod"}, "filters": ["a"]}}}`), &cmd))
+			cmd.MergeTarget = true
+
+			target := map[string]interface{}{"query": "${traceId}", "limit": 20, "step": "1m", "labels": map[string]interface{}{"app": "checkout"}, "filters": []interface{}{"b", "c"}}
+			correlation := Correlation{Config: CorrelationConfig{Target: target}}
+			cmd.apply(&correlation)
+			require.Equal(t, map[string]interface{}{
+				"query":   "${traceId}",
+				"limit":   float64(50),
+				"labels":  map[string]interface{}{"app": "checkout", "env": "prod"},
+				"filters": []interface{}{"a"},
+			}, correlation.Config.Target)
+			require.Equal(t, "1m", target["step"], "the current target is left unchanged")
+		})
+
+		t.Run("rejects merging into a list of targets", func(t *testing.T) {
+			patch := map[string]interface{}{"limit": 50}
+			cmd := UpdateCorrelationCommand{MergeTarget: true, Config: &CorrelationConfigUpdateDTO{Target: &patch}}
+			correlation := Correlation{}
+			correlation.Config.setTargets([]map[string]interface{}{{"query": "a"}})
+			require.ErrorIs(t, cmd.checkMerge(correlation), ErrInvalidTarget)
+
+			cmd.MergeTarget = false
+			require.NoError(t, cmd.checkMerge(correlation))
+		})
+
+		t.Run("rejects a null type", func(t *testing.T) {
+			var cmd UpdateCorrelationCommand
+			require.NoError(t, json.Unmarshal([]byte(`{"config": {"type": null}}`), &cmd))
+			require.ErrorIs(t, cmd.Config.Type.Validate(), ErrInvalidConfigType)
 		})
 	})
 }
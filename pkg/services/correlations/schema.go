@@ -0,0 +1,65 @@
+package correlations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// checkTargetSchema validates the target queries of query correlations against
+// the schema registered by the plugin of their target data source, if any.
+func (s CorrelationsService) checkTargetSchema(ctx context.Context, ds *datasources.DataSource, config CorrelationConfig) error {
+	if config.Type != ConfigTypeQuery || ds == nil || s.PluginStore == nil {
+		return nil
+	}
+
+	plugin, exists := s.PluginStore.Plugin(ctx, ds.Type)
+	if !exists || len(plugin.CorrelationTargetSchema) == 0 {
+		return nil
+	}
+
+	schema := openapi3.NewSchema()
+	if err := json.Unmarshal(plugin.CorrelationTargetSchema, schema); err != nil {
+		// a broken schema is the plugin's fault, the correlations to its data sources are still accepted
+		s.log.Warn("Invalid correlation target schema", "plugin", plugin.ID, "error", err)
+		return nil
+	}
+
+	for _, query := range config.targetQueries() {
+		// the target goes through JSON first as the schema only knows of the JSON types, while provisioned targets come from YAML
+		raw, err := json.Marshal(query)
+		if err != nil {
+			return err
+		}
+		var target interface{}
+		if err := json.Unmarshal(raw, &target); err != nil {
+			return err
+		}
+
+		if err := schema.VisitJSON(target); err != nil {
+			return ErrTargetSchemaMismatch.Build(errutil.TemplateData{
+				Public: map[string]interface{}{"plugin": plugin.ID, "reason": schemaErrorReason(err)},
+				Error:  err,
+			})
+		}
+	}
+	return nil
+}
+
+// schemaErrorReason describes a validation error without the dump of the schema
+func schemaErrorReason(err error) string {
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return err.Error()
+	}
+	if path := schemaErr.JSONPointer(); len(path) > 0 {
+		return strings.Join(path, ".") + ": " + schemaErr.Reason
+	}
+	return schemaErr.Reason
+}
@@ -0,0 +1,137 @@
+package correlations
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// scores of the matches of a search, summed over the fields of a correlation
+const (
+	scoreLabelExact  = 100
+	scoreLabelPrefix = 50
+	scoreLabel       = 30
+	scoreTarget      = 20
+	scoreDescription = 10
+)
+
+// unsafeSearchCharacters are escaped in the config stored as JSON, or have a
+// meaning in the LIKE patterns of some databases
+var unsafeSearchCharacters = regexp.MustCompile(`[^a-zA-Z0-9 =:.,/-]+`)
+
+// searchPattern is a LIKE pattern matching at least the texts containing the
+// query, where the unsafe characters match anything. The results are then
+// checked with scoreCorrelation.
+func searchPattern(query string) string {
+	return "%" + unsafeSearchCharacters.ReplaceAllString(query, "%") + "%"
+}
+
+// searchCorrelations finds the correlations of the organization visible to the
+// user whose label, description or target contains the query, ranked by
+// scoreCorrelation. The database narrows the correlations down to the org and
+// to the ones that may match, and the ranking keeps the ones matching a
+// searched field.
+func (s CorrelationsService) searchCorrelations(ctx context.Context, query SearchCorrelationsQuery) ([]CorrelationSearchResult, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = DefaultSearchResults
+	}
+
+	correlations := make([]Correlation, 0)
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		like := s.SQLStore.GetDialect().LikeStr()
+		pattern := searchPattern(query.Query)
+		err := session.Select("correlation.*").Join("LEFT", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", query.OrgId).Join("LEFT", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", query.OrgId).
+			Where(ofOrg+" AND "+withExistingSource+" AND "+withExistingTarget, query.OrgId, query.OrgId).
+			And("(correlation.label "+like+" ? OR correlation.description "+like+" ? OR correlation.config "+like+" ?)", pattern, pattern, pattern).
+			Asc("correlation.order", "correlation.uid").Find(&correlations)
+		if err != nil {
+			return err
+		}
+		return loadTags(session, query.OrgId, correlations)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the hidden correlations are left out before the limit, so that they
+	// don't take the place of visible ones
+	correlations = visibleCorrelations(query.SignedInUser, correlations)
+
+	results := make([]CorrelationSearchResult, 0, len(correlations))
+	for _, correlation := range correlations {
+		if result := scoreCorrelation(correlation, query.Query); result.Score > 0 {
+			results = append(results, result)
+		}
+	}
+	// the correlations are already sorted by order, which breaks the ties
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// scoreCorrelation ranks the correlation for the query: matches in the label
+// rank first, especially when the label starts with or is the query, then
+// matches in the target and in the description
+func scoreCorrelation(correlation Correlation, query string) CorrelationSearchResult {
+	result := CorrelationSearchResult{Correlation: correlation, Matches: []string{}}
+	query = strings.ToLower(query)
+
+	label := strings.ToLower(correlation.Label)
+	switch {
+	case label == query:
+		result.Score += scoreLabelExact
+	case strings.HasPrefix(label, query):
+		result.Score += scoreLabelPrefix
+	case strings.Contains(label, query):
+		result.Score += scoreLabel
+	}
+	if result.Score > 0 {
+		result.Matches = append(result.Matches, "label")
+	}
+
+	for _, target := range correlation.Config.targetQueries() {
+		if targetContains(target, query) {
+			result.Score += scoreTarget
+			result.Matches = append(result.Matches, "target")
+			break
+		}
+	}
+
+	if strings.Contains(strings.ToLower(correlation.Description), query) {
+		result.Score += scoreDescription
+		result.Matches = append(result.Matches, "description")
+	}
+
+	return result
+}
+
+// targetContains returns whether a string of the target, such as its query or
+// URL, contains the lower case query
+func targetContains(value interface{}, query string) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(strings.ToLower(v), query)
+	case map[string]interface{}:
+		for _, item := range v {
+			if targetContains(item, query) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if targetContains(item, query) {
+				return true
+			}
+		}
+	}
+	return false
+}
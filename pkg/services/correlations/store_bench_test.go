@@ -0,0 +1,169 @@
+package correlations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	benchSourcesPerOrg = 100
+	benchTargetsPerOrg = 10
+	// benchOrgs store the same number of correlations, only the first one is queried
+	benchOrgs = 2
+	// benchInsertBatch keeps the inserts under the limit of variables of SQLite
+	benchInsertBatch = 50
+)
+
+func BenchmarkGetCorrelations1000(b *testing.B)  { benchmarkGetCorrelations(b, 1000) }
+func BenchmarkGetCorrelations10000(b *testing.B) { benchmarkGetCorrelations(b, 10000) }
+
+func BenchmarkGetCorrelationsBySourceUID1000(b *testing.B) {
+	benchmarkGetCorrelationsBySourceUID(b, 1000)
+}
+func BenchmarkGetCorrelationsBySourceUID10000(b *testing.B) {
+	benchmarkGetCorrelationsBySourceUID(b, 10000)
+}
+
+func BenchmarkGetCorrelationsByTargetUID1000(b *testing.B) {
+	benchmarkGetCorrelationsByTargetUID(b, 1000)
+}
+func BenchmarkGetCorrelationsByTargetUID10000(b *testing.B) {
+	benchmarkGetCorrelationsByTargetUID(b, 10000)
+}
+
+func BenchmarkSearchCorrelations1000(b *testing.B)  { benchmarkSearchCorrelations(b, 1000) }
+func BenchmarkSearchCorrelations10000(b *testing.B) { benchmarkSearchCorrelations(b, 10000) }
+
+func benchmarkGetCorrelations(b *testing.B, correlationsPerOrg int) {
+	s := setupCorrelationsBenchmark(b, correlationsPerOrg)
+	// We don't want to measure DB initialization
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		correlations, err := s.GetCorrelations(context.Background(), GetCorrelationsQuery{OrgId: 1})
+		require.NoError(b, err)
+		require.Len(b, correlations, correlationsPerOrg)
+	}
+}
+
+func benchmarkGetCorrelationsBySourceUID(b *testing.B, correlationsPerOrg int) {
+	s := setupCorrelationsBenchmark(b, correlationsPerOrg)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		correlations, err := s.GetCorrelationsBySourceUID(context.Background(), GetCorrelationsBySourceUIDQuery{SourceUID: benchSourceUID(0), OrgId: 1})
+		require.NoError(b, err)
+		require.Len(b, correlations, correlationsPerOrg/benchSourcesPerOrg)
+	}
+}
+
+func benchmarkGetCorrelationsByTargetUID(b *testing.B, correlationsPerOrg int) {
+	s := setupCorrelationsBenchmark(b, correlationsPerOrg)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		correlations, err := s.GetCorrelationsByTargetUID(context.Background(), GetCorrelationsByTargetUIDQuery{TargetUID: benchTargetUID(0), OrgId: 1})
+		require.NoError(b, err)
+		require.Len(b, correlations, correlationsPerOrg/benchTargetsPerOrg)
+	}
+}
+
+func benchmarkSearchCorrelations(b *testing.B, correlationsPerOrg int) {
+	s := setupCorrelationsBenchmark(b, correlationsPerOrg)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		results, err := s.SearchCorrelations(context.Background(), SearchCorrelationsQuery{Query: "correlation 7", OrgId: 1})
+		require.NoError(b, err)
+		require.NotEmpty(b, results)
+	}
+}
+
+func benchSourceUID(i int) string { return fmt.Sprintf("source-%d", i) }
+func benchTargetUID(i int) string { return fmt.Sprintf("target-%d", i) }
+
+// setupCorrelationsBenchmark stores the correlations of several orgs, spread
+// over their source and target data sources, with a tag each. The rows are
+// inserted directly, creating them one by one would take most of the run.
+func setupCorrelationsBenchmark(b *testing.B, correlationsPerOrg int) CorrelationsService {
+	b.Helper()
+	sqlStore := sqlstore.InitTestDB(b)
+
+	dataSources := make([]*datasources.DataSource, 0, benchOrgs*(benchSourcesPerOrg+benchTargetsPerOrg))
+	for org := int64(1); org <= benchOrgs; org++ {
+		for i := 0; i < benchSourcesPerOrg; i++ {
+			dataSources = append(dataSources, &datasources.DataSource{OrgId: org, Uid: benchSourceUID(i), Name: fmt.Sprintf("loki %d", i), Type: "loki", Created: time.Now(), Updated: time.Now()})
+		}
+		for i := 0; i < benchTargetsPerOrg; i++ {
+			dataSources = append(dataSources, &datasources.DataSource{OrgId: org, Uid: benchTargetUID(i), Name: fmt.Sprintf("tempo %d", i), Type: "tempo", Created: time.Now(), Updated: time.Now()})
+		}
+	}
+
+	err := sqlStore.WithDbSession(context.Background(), func(session *sqlstore.DBSession) error {
+		for _, ds := range dataSources {
+			if _, err := session.Insert(ds); err != nil {
+				return err
+			}
+		}
+
+		correlations := make([]Correlation, 0, benchInsertBatch)
+		tags := make([]CorrelationTag, 0, benchInsertBatch)
+		for org := int64(1); org <= benchOrgs; org++ {
+			for i := 0; i < correlationsPerOrg; i++ {
+				targetUID := benchTargetUID(i % benchTargetsPerOrg)
+				correlation := Correlation{
+					UID:         fmt.Sprintf("org-%d-%d", org, i),
+					SourceUID:   benchSourceUID(i % benchSourcesPerOrg),
+					TargetUID:   &targetUID,
+					OrgId:       org,
+					Label:       fmt.Sprintf("correlation %d", i),
+					Description: "Logs to Traces",
+					Config: CorrelationConfig{
+						Type:   ConfigTypeQuery,
+						Field:  CorrelationFields{"traceId"},
+						Target: map[string]interface{}{"query": fmt.Sprintf("${traceId} %d", i)},
+					},
+					Order: i,
+				}
+				correlations = append(correlations, correlation)
+				tags = append(tags, CorrelationTag{OrgId: org, CorrelationUID: correlation.UID, SourceUID: correlation.SourceUID, Term: fmt.Sprintf("tag-%d", i%10)})
+
+				if len(correlations) == benchInsertBatch {
+					if _, err := session.InsertMulti(&correlations); err != nil {
+						return err
+					}
+					if _, err := session.InsertMulti(&tags); err != nil {
+						return err
+					}
+					correlations = correlations[:0]
+					tags = tags[:0]
+				}
+			}
+		}
+		if len(correlations) > 0 {
+			if _, err := session.InsertMulti(&correlations); err != nil {
+				return err
+			}
+			if _, err := session.InsertMulti(&tags); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(b, err)
+
+	return CorrelationsService{
+		SQLStore:          sqlStore,
+		Cfg:               setting.NewCfg(),
+		DataSourceService: &fakeDatasources.FakeDataSourceService{DataSources: dataSources[:benchSourcesPerOrg+benchTargetsPerOrg]},
+	}
+}
@@ -0,0 +1,126 @@
+package correlations
+
+import (
+	"sort"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// maxTagSources is the number of source data sources up to which the tags of
+// a list of correlations are loaded for its data sources
+const maxTagSources = 100
+
+// withTag filters the correlations having a tag in an org
+const withTag = `EXISTS (SELECT 1 FROM correlation_tag AS ct WHERE ct.org_id = ? AND ct.correlation_uid = correlation.uid AND ct.source_uid = correlation.source_uid AND ct.term = ?)`
+
+func validateTags(tags []string) error {
+	for _, tag := range tags {
+		if tag == "" || len(tag) > MaxTagLength {
+			return ErrInvalidTag.Errorf("invalid tag %q", tag)
+		}
+	}
+	return nil
+}
+
+// normalizeTags sorts the tags and drops the duplicates
+func normalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// filterByTags restricts the correlations of the session to the ones with all the tags
+func filterByTags(session *sqlstore.DBSession, orgID int64, tags []string) {
+	for _, tag := range tags {
+		session.And(withTag, orgID, tag)
+	}
+}
+
+// saveTags replaces the stored tags of the correlation with its normalized tags
+func saveTags(session *sqlstore.DBSession, orgID int64, correlation Correlation) error {
+	if _, err := session.Delete(&CorrelationTag{OrgId: orgID, CorrelationUID: correlation.UID, SourceUID: correlation.SourceUID}); err != nil {
+		return err
+	}
+
+	for _, tag := range correlation.Tags {
+		if _, err := session.Insert(&CorrelationTag{OrgId: orgID, CorrelationUID: correlation.UID, SourceUID: correlation.SourceUID, Term: tag}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTags sets the tags of the correlations of the org, sorted by name
+func loadTags(session *sqlstore.DBSession, orgID int64, correlations []Correlation) error {
+	if len(correlations) == 0 {
+		return nil
+	}
+
+	session.Where("org_id = ?", orgID)
+	if len(correlations) == 1 {
+		session.And("correlation_uid = ?", correlations[0].UID)
+	} else if sources := tagSources(correlations); sources != nil {
+		session.And("source_uid IN ("+placeholders(len(sources))+")", sources...)
+	}
+	tags := make([]CorrelationTag, 0)
+	if err := session.Asc("term").Find(&tags); err != nil {
+		return err
+	}
+	byCorrelation := make(map[[2]string][]string, len(tags))
+	for _, tag := range tags {
+		key := [2]string{tag.CorrelationUID, tag.SourceUID}
+		byCorrelation[key] = append(byCorrelation[key], tag.Term)
+	}
+
+	for i := range correlations {
+		correlations[i].Tags = byCorrelation[[2]string{correlations[i].UID, correlations[i].SourceUID}]
+	}
+	return nil
+}
+
+// tagSources returns the source UIDs of the correlations, to load the tags of
+// their data sources only, or nil when there are too many of them and loading
+// the tags of the whole org is cheaper
+func tagSources(correlations []Correlation) []interface{} {
+	seen := make(map[string]bool)
+	sources := make([]interface{}, 0)
+	for _, correlation := range correlations {
+		if seen[correlation.SourceUID] {
+			continue
+		}
+		if len(sources) == maxTagSources {
+			return nil
+		}
+		seen[correlation.SourceUID] = true
+		sources = append(sources, correlation.SourceUID)
+	}
+	return sources
+}
+
+// loadCorrelationTags sets the tags of a correlation of the org
+func loadCorrelationTags(session *sqlstore.DBSession, orgID int64, correlation *Correlation) error {
+	loaded := []Correlation{*correlation}
+	if err := loadTags(session, orgID, loaded); err != nil {
+		return err
+	}
+	*correlation = loaded[0]
+	return nil
+}
+
+// deleteOrphanedTags deletes the tags of the correlations that don't exist anymore
+func deleteOrphanedTags(session *sqlstore.DBSession) error {
+	_, err := session.Exec(`DELETE FROM correlation_tag WHERE NOT EXISTS (
+		SELECT 1 FROM correlation WHERE correlation.uid = correlation_tag.correlation_uid AND correlation.source_uid = correlation_tag.source_uid)`)
+	return err
+}
@@ -0,0 +1,92 @@
+package correlations
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// upsertCorrelation creates the correlation when its source data source has
+// none with the UID, and replaces all its fields otherwise. A replacement that
+// changes nothing leaves the correlation as is, without a new version, so that
+// applying the same correlation again is a no-op. It tells whether the
+// correlation was created.
+func (s CorrelationsService) upsertCorrelation(ctx context.Context, cmd CreateCorrelationCommand) (Correlation, bool, error) {
+	defer s.invalidateLists()
+
+	var correlation Correlation
+	created := false
+	err := s.SQLStore.InTransaction(ctx, func(ctx context.Context) error {
+		existing := Correlation{UID: cmd.UID, SourceUID: cmd.SourceUID}
+		found := false
+		err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+			var err error
+			if found, err = session.Get(&existing); err != nil || !found {
+				return err
+			}
+			return loadCorrelationTags(session, cmd.OrgId, &existing)
+		})
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			created = true
+			correlation, err = s.createCorrelation(ctx, cmd)
+			return err
+		}
+
+		update := cmd.replacement()
+		replaced := existing
+		update.apply(&replaced)
+		if len(correlationDiff(&existing, &replaced)) == 0 {
+			// the empty update still checks that the correlation can be updated
+			update = UpdateCorrelationCommand{}
+		}
+		update.UID = cmd.UID
+		update.SourceUID = cmd.SourceUID
+		update.OrgId = cmd.OrgId
+		update.UserId = cmd.UserId
+		update.ValidateTarget = cmd.ValidateTarget
+		update.SignedInUser = cmd.SignedInUser
+		correlation, err = s.updateCorrelation(ctx, update)
+		return err
+	})
+	if err != nil {
+		return Correlation{}, created, err
+	}
+
+	return correlation, created, nil
+}
+
+// replacement is the update replacing all the fields of a correlation with the
+// ones of the command
+func (cmd CreateCorrelationCommand) replacement() UpdateCorrelationCommand {
+	tags := cmd.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	visibility := cmd.Visibility
+	if visibility == nil {
+		visibility = &CorrelationVisibility{}
+	}
+	config := cmd.Config
+	return UpdateCorrelationCommand{
+		ReplaceTarget: true,
+		TargetUID:     cmd.TargetUID,
+		Label:         &cmd.Label,
+		Description:   &cmd.Description,
+		Order:         &cmd.Order,
+		Tags:          &tags,
+		Visibility:    visibility,
+		Config: &CorrelationConfigUpdateDTO{
+			Field:           &config.Field,
+			Type:            &config.Type,
+			Target:          &config.Target,
+			Targets:         &config.Targets,
+			Transformations: &config.Transformations,
+			Matchers:        &config.Matchers,
+			Display:         &config.Display,
+		},
+	}
+}
@@ -0,0 +1,119 @@
+package correlations
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// saveVersion records the state of the correlation as its next version
+func saveVersion(session *sqlstore.DBSession, orgID int64, correlation Correlation, action string, restoredFrom int, userID int64) error {
+	latest := CorrelationVersion{}
+	found, err := session.Where("correlation_uid = ? AND source_uid = ?", correlation.UID, correlation.SourceUID).Desc("version").Get(&latest)
+	if err != nil {
+		return err
+	}
+
+	version := 1
+	if found {
+		version = latest.Version + 1
+	}
+
+	_, err = session.Insert(&CorrelationVersion{
+		OrgId:          orgID,
+		CorrelationUID: correlation.UID,
+		SourceUID:      correlation.SourceUID,
+		Version:        version,
+		Action:         action,
+		RestoredFrom:   restoredFrom,
+		TargetUID:      correlation.TargetUID,
+		Label:          correlation.Label,
+		Description:    correlation.Description,
+		Config:         correlation.Config,
+		CreatedBy:      userID,
+		Created:        time.Now(),
+	})
+	return err
+}
+
+// hasVersions reports whether the correlation has a version history, which
+// correlations created before it was introduced lack
+func hasVersions(session *sqlstore.DBSession, correlation Correlation) (bool, error) {
+	return session.Exist(&CorrelationVersion{CorrelationUID: correlation.UID, SourceUID: correlation.SourceUID})
+}
+
+// deleteOrphanedVersions deletes the versions of the correlations that don't exist anymore
+func deleteOrphanedVersions(session *sqlstore.DBSession) error {
+	_, err := session.Exec(`DELETE FROM correlation_version WHERE NOT EXISTS (
+		SELECT 1 FROM correlation WHERE correlation.uid = correlation_version.correlation_uid AND correlation.source_uid = correlation_version.source_uid)`)
+	return err
+}
+
+func (s CorrelationsService) getCorrelationVersions(ctx context.Context, query GetCorrelationVersionsQuery) ([]CorrelationVersion, error) {
+	// the versions are as restricted as the correlation
+	if _, err := s.GetCorrelation(ctx, GetCorrelationQuery{UID: query.UID, SourceUID: query.SourceUID, OrgId: query.OrgId, SignedInUser: query.SignedInUser}); err != nil {
+		return nil, err
+	}
+
+	versions := make([]CorrelationVersion, 0)
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		return session.Where("org_id = ? AND correlation_uid = ? AND source_uid = ?", query.OrgId, query.UID, query.SourceUID).Desc("version").Find(&versions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// restoreCorrelationVersion updates the correlation to the label, description
+// and config of the version. The target of a correlation can't change, so it
+// is kept as is.
+func (s CorrelationsService) restoreCorrelationVersion(ctx context.Context, cmd RestoreCorrelationVersionCommand) (Correlation, error) {
+	defer s.invalidateLists()
+
+	var restored Correlation
+	err := s.SQLStore.InTransaction(ctx, func(ctx context.Context) error {
+		version := CorrelationVersion{}
+		err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+			found, err := session.Where("org_id = ? AND correlation_uid = ? AND source_uid = ? AND version = ?", cmd.OrgId, cmd.UID, cmd.SourceUID, cmd.Version).Get(&version)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return ErrCorrelationVersionNotFound.Errorf("version %d of correlation %s not found", cmd.Version, cmd.UID)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		config := version.Config
+		restored, err = s.updateCorrelation(ctx, UpdateCorrelationCommand{
+			UID:          cmd.UID,
+			SourceUID:    cmd.SourceUID,
+			OrgId:        cmd.OrgId,
+			UserId:       cmd.UserId,
+			RestoredFrom: cmd.Version,
+			Label:        &version.Label,
+			Description:  &version.Description,
+			Config: &CorrelationConfigUpdateDTO{
+				Field:           &config.Field,
+				Type:            &config.Type,
+				Target:          &config.Target,
+				Targets:         &config.Targets,
+				Transformations: &config.Transformations,
+				Matchers:        &config.Matchers,
+				Display:         &config.Display,
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return Correlation{}, err
+	}
+
+	return restored, nil
+}
@@ -0,0 +1,112 @@
+package correlations
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// seesAllCorrelations tells whether the user sees the correlations whatever
+// their visibility, which organization admins need to manage them
+func seesAllCorrelations(u *user.SignedInUser) bool {
+	return u != nil && (u.IsGrafanaAdmin || u.OrgRole == org.RoleAdmin)
+}
+
+// visibleTo tells whether the correlation is visible to the user. The
+// correlations without visibility are visible to all the users who can read
+// their source, the others to the users with one of their roles, or one above
+// them, and to the members of their teams.
+func visibleTo(u *user.SignedInUser, correlation Correlation) bool {
+	visibility := correlation.Visibility.normalize()
+	if visibility == nil || seesAllCorrelations(u) {
+		return true
+	}
+	if u == nil {
+		return false
+	}
+
+	for _, role := range visibility.Roles {
+		if u.OrgRole.Includes(role) {
+			return true
+		}
+	}
+	for _, team := range visibility.Teams {
+		for _, userTeam := range u.Teams {
+			if team == userTeam {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// visibleCorrelations returns the correlations visible to the user
+func visibleCorrelations(u *user.SignedInUser, correlations []Correlation) []Correlation {
+	if seesAllCorrelations(u) {
+		return correlations
+	}
+
+	visible := make([]Correlation, 0, len(correlations))
+	for _, correlation := range correlations {
+		if visibleTo(u, correlation) {
+			visible = append(visible, correlation)
+		}
+	}
+	return visible
+}
+
+// audience identifies the users who see the same correlations as the user,
+// from their role and teams, so that the lists cached for one of them are
+// served to the others
+func audience(u *user.SignedInUser) string {
+	if seesAllCorrelations(u) {
+		return "*"
+	}
+	if u == nil {
+		return ""
+	}
+
+	teams := make([]int64, len(u.Teams))
+	copy(teams, u.Teams)
+	sort.Slice(teams, func(i, j int) bool { return teams[i] < teams[j] })
+	ids := make([]string, len(teams))
+	for i, team := range teams {
+		ids[i] = strconv.FormatInt(team, 10)
+	}
+	return fmt.Sprintf("%s:%s", u.OrgRole, strings.Join(ids, ","))
+}
+
+// checkVisibility checks that the roles the correlation is visible to are
+// valid, and that its teams belong to the organization
+func checkVisibility(session *sqlstore.DBSession, orgID int64, visibility *CorrelationVisibility) error {
+	if err := visibility.Validate(); err != nil {
+		return err
+	}
+	if visibility == nil || len(visibility.Teams) == 0 {
+		return nil
+	}
+
+	teams := make(map[int64]bool, len(visibility.Teams))
+	args := make([]interface{}, 0, len(visibility.Teams)+1)
+	args = append(args, orgID)
+	for _, team := range visibility.Teams {
+		if !teams[team] {
+			teams[team] = true
+			args = append(args, team)
+		}
+	}
+
+	count, err := session.Table("team").Where("org_id = ? AND id IN ("+placeholders(len(teams))+")", args...).Count()
+	if err != nil {
+		return err
+	}
+	if count != int64(len(teams)) {
+		return ErrInvalidVisibility.Errorf("some of the teams %v don't belong to organization %d", visibility.Teams, orgID)
+	}
+	return nil
+}
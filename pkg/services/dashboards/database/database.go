@@ -8,6 +8,7 @@ import (
 
 	"xorm.io/xorm"
 
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/models"
@@ -720,10 +721,11 @@ func (d *DashboardStore) deleteDashboard(cmd *models.DeleteDashboardCommand, ses
 		deletes = append(deletes, "DELETE FROM dashboard WHERE folder_id = ?")
 
 		var dashIds []struct {
-			Id  int64
-			Uid string
+			Id    int64
+			Uid   string
+			Title string
 		}
-		err := sess.SQL("SELECT id, uid FROM dashboard WHERE folder_id = ?", dashboard.Id).Find(&dashIds)
+		err := sess.SQL("SELECT id, uid, title FROM dashboard WHERE folder_id = ?", dashboard.Id).Find(&dashIds)
 		if err != nil {
 			return err
 		}
@@ -746,6 +748,14 @@ func (d *DashboardStore) deleteDashboard(cmd *models.DeleteDashboardCommand, ses
 			if err != nil {
 				return err
 			}
+
+			sess.PublishAfterCommit(&events.DashboardDeleted{
+				Timestamp: time.Now(),
+				Title:     dash.Title,
+				ID:        dash.Id,
+				UID:       dash.Uid,
+				OrgID:     dashboard.OrgId,
+			})
 		}
 
 		if len(dashIds) > 0 {
@@ -793,6 +803,14 @@ func (d *DashboardStore) deleteDashboard(cmd *models.DeleteDashboardCommand, ses
 		if err != nil {
 			return err
 		}
+
+		sess.PublishAfterCommit(&events.DashboardDeleted{
+			Timestamp: time.Now(),
+			Title:     dashboard.Title,
+			ID:        dashboard.Id,
+			UID:       dashboard.Uid,
+			OrgID:     dashboard.OrgId,
+		})
 	}
 
 	if err := d.deleteAlertDefinition(dashboard.Id, sess); err != nil {
@@ -953,6 +971,10 @@ func (d *DashboardStore) FindDashboards(ctx context.Context, query *models.FindP
 		filters = append(filters, searchstore.StarredFilter{UserId: query.SignedInUser.UserID})
 	}
 
+	if query.IsPublic {
+		filters = append(filters, searchstore.PublicFilter{Dialect: d.dialect})
+	}
+
 	if len(query.Title) > 0 {
 		filters = append(filters, searchstore.TitleFilter{Dialect: d.dialect, Title: query.Title})
 	}
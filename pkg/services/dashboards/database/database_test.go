@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -216,6 +217,25 @@ func TestIntegrationDashboardDataAccess(t *testing.T) {
 		require.False(t, query.Result.Updated.IsZero())
 	})
 
+	t.Run("Should publish the deletion of the dashboards", func(t *testing.T) {
+		setup()
+		var deleted []string
+		sqlStore.Bus().AddEventListener(func(_ context.Context, e *events.DashboardDeleted) error {
+			deleted = append(deleted, e.UID)
+			return nil
+		})
+
+		err := dashboardStore.DeleteDashboard(context.Background(), &models.DeleteDashboardCommand{Id: savedDash2.Id, OrgId: 1})
+		require.NoError(t, err)
+		require.Equal(t, []string{savedDash2.Uid}, deleted)
+
+		deleted = nil
+		err = dashboardStore.DeleteDashboard(context.Background(), &models.DeleteDashboardCommand{Id: savedFolder.Id, OrgId: 1, ForceDeleteFolderRules: true})
+		require.NoError(t, err)
+		require.Len(t, deleted, 2)
+		require.Contains(t, deleted, savedDash.Uid)
+	})
+
 	t.Run("Should be able to delete empty folder", func(t *testing.T) {
 		setup()
 		emptyFolder := insertTestDashboard(t, dashboardStore, "2 test dash folder", 1, 0, true, "prod", "webapp")
@@ -773,6 +793,7 @@ func makeQueryResult(query *models.FindPersistedDashboardsQuery, res []dashboard
 				FolderID:    item.FolderID,
 				FolderUID:   item.FolderUID,
 				FolderTitle: item.FolderTitle,
+				IsPublic:    item.IsPublic,
 				Tags:        []string{},
 			}
 
@@ -27,5 +27,6 @@ type DashboardSearchProjection struct {
 	FolderUID   string `xorm:"folder_uid"`
 	FolderSlug  string
 	FolderTitle string
+	IsPublic    bool
 	SortMeta    int64
 }
@@ -547,6 +547,7 @@ func makeQueryResult(query *models.FindPersistedDashboardsQuery, res []dashboard
 				FolderID:    item.FolderID,
 				FolderUID:   item.FolderUID,
 				FolderTitle: item.FolderTitle,
+				IsPublic:    item.IsPublic,
 				Tags:        []string{},
 			}
 
@@ -97,6 +97,9 @@ func (s *FakeDataSourceService) UpdateDataSource(ctx context.Context, cmd *datas
 			if cmd.Name != "" {
 				datasource.Name = cmd.Name
 			}
+			if cmd.JsonData != nil {
+				datasource.JsonData = cmd.JsonData
+			}
 			return nil
 		}
 	}
@@ -294,6 +294,14 @@ func (ss *SqlStore) UpdateDataSource(ctx context.Context, cmd *datasources.Updat
 		// secure json data to the unified secrets table.
 		sess.MustCols("secure_json_data")
 
+		// keep the previous UID to notify its change
+		previous := &datasources.DataSource{}
+		if cmd.Uid != "" {
+			if _, err := sess.Where("id=? and org_id=?", ds.Id, ds.OrgId).Cols("uid").Get(previous); err != nil {
+				return err
+			}
+		}
+
 		var updateSession *xorm.Session
 		if cmd.Version != 0 {
 			// the reason we allow cmd.version > db.version is make it possible for people to force
@@ -322,6 +330,17 @@ func (ss *SqlStore) UpdateDataSource(ctx context.Context, cmd *datasources.Updat
 			}
 		}
 
+		if previous.Uid != "" && previous.Uid != ds.Uid {
+			sess.PublishAfterCommit(&events.DataSourceUIDChanged{
+				Timestamp:   time.Now(),
+				Name:        ds.Name,
+				ID:          ds.Id,
+				PreviousUID: previous.Uid,
+				UID:         ds.Uid,
+				OrgID:       ds.OrgId,
+			})
+		}
+
 		cmd.Result = ds
 		return err
 	})
@@ -214,6 +214,54 @@ func TestIntegrationDataAccess(t *testing.T) {
 			err := ss.UpdateDataSource(context.Background(), cmd)
 			require.NoError(t, err)
 		})
+
+		t.Run("fires an event when the Uid changes", func(t *testing.T) {
+			db := sqlstore.InitTestDB(t)
+			ds := initDatasource(db)
+			ss := SqlStore{db: db}
+
+			var changed *events.DataSourceUIDChanged
+			db.Bus().AddEventListener(func(ctx context.Context, e *events.DataSourceUIDChanged) error {
+				changed = e
+				return nil
+			})
+
+			cmd := defaultUpdateDatasourceCommand
+			cmd.Id = ds.Id
+			cmd.Uid = "new-uid"
+			err := ss.UpdateDataSource(context.Background(), &cmd)
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool {
+				return assert.NotNil(t, changed)
+			}, time.Second, time.Millisecond)
+
+			require.Equal(t, ds.Id, changed.ID)
+			require.Equal(t, ds.OrgId, changed.OrgID)
+			require.Equal(t, ds.Uid, changed.PreviousUID)
+			require.Equal(t, "new-uid", changed.UID)
+		})
+
+		t.Run("does not fire an event when the Uid is not specified", func(t *testing.T) {
+			db := sqlstore.InitTestDB(t)
+			ds := initDatasource(db)
+			ss := SqlStore{db: db}
+
+			var called bool
+			db.Bus().AddEventListener(func(ctx context.Context, e *events.DataSourceUIDChanged) error {
+				called = true
+				return nil
+			})
+
+			cmd := defaultUpdateDatasourceCommand
+			cmd.Id = ds.Id
+			err := ss.UpdateDataSource(context.Background(), &cmd)
+			require.NoError(t, err)
+
+			require.Never(t, func() bool {
+				return called
+			}, time.Second, time.Millisecond)
+		})
 	})
 
 	t.Run("DeleteDataSourceById", func(t *testing.T) {
@@ -0,0 +1,106 @@
+package exportbundle
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *ExportBundleService) registerAPIEndpoints() {
+	authorize := ac.Middleware(s.AccessControl)
+	uidScope := dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+
+	s.RouteRegister.Group("/api/bundles", func(bundles routing.RouteRegister) {
+		bundles.Get("/dashboards/:uid", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(dashboards.ActionDashboardsRead, uidScope)), routing.Wrap(s.exportHandler))
+		bundles.Post("/import", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(dashboards.ActionDashboardsCreate)), routing.Wrap(s.importHandler))
+	}, middleware.ReqSignedIn)
+}
+
+// swagger:route GET /bundles/dashboards/{uid} export_bundles exportDashboardBundle
+//
+// Export a dashboard with its public dashboard configuration, the correlations of its data sources and the contact points of its alert rules.
+//
+// The bundle is signed with the export bundle signing key, and can be imported into the instances sharing it.
+//
+// Responses:
+// 200: exportDashboardBundleResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *ExportBundleService) exportHandler(c *models.ReqContext) response.Response {
+	bundle, err := s.Export(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return errorResponse("Failed to export dashboard bundle", err)
+	}
+
+	return response.JSON(http.StatusOK, bundle)
+}
+
+// swagger:route POST /bundles/import export_bundles importDashboardBundle
+//
+// Import a signed dashboard bundle.
+//
+// The dashboard with the same UID is overwritten. The other resources are created or updated, and the response reports those that failed to import.
+//
+// Responses:
+// 200: importDashboardBundleResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *ExportBundleService) importHandler(c *models.ReqContext) response.Response {
+	signed := SignedBundle{}
+	if err := web.Bind(c.Req, &signed); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	result, err := s.Import(c.Req.Context(), c.SignedInUser, signed)
+	if err != nil {
+		return errorResponse("Failed to import dashboard bundle", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// errorResponse returns the status of the dashboard errors, which aren't errutil errors.
+func errorResponse(message string, err error) response.Response {
+	var dashboardErr dashboards.DashboardErr
+	if errors.As(err, &dashboardErr) {
+		return response.Error(dashboardErr.StatusCode, dashboardErr.Error(), err)
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, message, err)
+}
+
+// swagger:parameters exportDashboardBundle
+type ExportDashboardBundleParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters importDashboardBundle
+type ImportDashboardBundleParams struct {
+	// in:body
+	// required:true
+	Body SignedBundle `json:"body"`
+}
+
+//swagger:response exportDashboardBundleResponse
+type ExportDashboardBundleResponse struct {
+	// in: body
+	Body SignedBundle `json:"body"`
+}
+
+//swagger:response importDashboardBundleResponse
+type ImportDashboardBundleResponse struct {
+	// in: body
+	Body ImportResult `json:"body"`
+}
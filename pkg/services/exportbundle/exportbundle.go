@@ -0,0 +1,375 @@
+package exportbundle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/ngalert"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	publicdashboardModels "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/queries"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// CorrelationStore reads and writes the correlations of the bundles.
+type CorrelationStore interface {
+	GetCorrelations(ctx context.Context, cmd correlations.GetCorrelationsQuery) ([]correlations.Correlation, error)
+	CreateCorrelation(ctx context.Context, cmd correlations.CreateCorrelationCommand) (correlations.Correlation, error)
+	UpdateCorrelation(ctx context.Context, cmd correlations.UpdateCorrelationCommand) (correlations.Correlation, error)
+}
+
+// ContactPointStore reads and writes the contact points of the bundles.
+type ContactPointStore interface {
+	GetDashboardContactPoints(ctx context.Context, orgID int64, dashboardUID string) ([]apimodels.EmbeddedContactPoint, error)
+	ImportContactPoint(ctx context.Context, orgID int64, contactPoint apimodels.EmbeddedContactPoint) (bool, error)
+}
+
+func ProvideService(cfg *setting.Cfg, routeRegister routing.RouteRegister, ac accesscontrol.AccessControl,
+	dashboardService dashboards.DashboardService, folderService folder.Service, publicDashboardService publicdashboards.Service,
+	correlationsService *correlations.CorrelationsService, ng *ngalert.AlertNG) *ExportBundleService {
+	s := &ExportBundleService{
+		Cfg:              cfg,
+		RouteRegister:    routeRegister,
+		AccessControl:    ac,
+		log:              log.New("exportbundle"),
+		dashboards:       dashboardService,
+		folders:          folderService,
+		publicDashboards: publicDashboardService,
+		correlations:     correlationsService,
+	}
+	// contact points are left out of the bundles when unified alerting is disabled
+	if ng != nil && ng.DashboardContactPoints != nil {
+		s.contactPoints = ng.DashboardContactPoints
+	}
+
+	s.registerAPIEndpoints()
+
+	return s
+}
+
+// Service exports a dashboard with its public dashboard configuration, the
+// correlations of its data sources and the contact points of its alert rules
+// into a signed bundle, and imports the bundles signed with the same key.
+type Service interface {
+	Export(ctx context.Context, u *user.SignedInUser, dashboardUID string) (SignedBundle, error)
+	Import(ctx context.Context, u *user.SignedInUser, signed SignedBundle) (ImportResult, error)
+}
+
+type ExportBundleService struct {
+	Cfg              *setting.Cfg
+	RouteRegister    routing.RouteRegister
+	AccessControl    accesscontrol.AccessControl
+	log              log.Logger
+	dashboards       dashboards.DashboardService
+	folders          folder.Service
+	publicDashboards publicdashboards.Service
+	correlations     CorrelationStore
+	contactPoints    ContactPointStore
+}
+
+var _ Service = (*ExportBundleService)(nil)
+
+func (s *ExportBundleService) Export(ctx context.Context, u *user.SignedInUser, dashboardUID string) (SignedBundle, error) {
+	q := models.GetDashboardQuery{Uid: dashboardUID, OrgId: u.OrgID}
+	if err := s.dashboards.GetDashboard(ctx, &q); err != nil {
+		return SignedBundle{}, err
+	}
+	dashboard := q.Result
+
+	bundle := Bundle{
+		Version:       BundleVersion,
+		ExportedAt:    time.Now(),
+		Dashboard:     dashboard.Data,
+		Correlations:  []correlations.Correlation{},
+		ContactPoints: []apimodels.EmbeddedContactPoint{},
+	}
+
+	if dashboard.FolderId != 0 {
+		f, err := s.folders.GetFolderByID(ctx, u, dashboard.FolderId, u.OrgID)
+		if err != nil {
+			return SignedBundle{}, err
+		}
+		bundle.FolderUID = f.Uid
+	}
+
+	pubdash, err := s.publicDashboards.GetPublicDashboardConfig(ctx, u.OrgID, dashboardUID)
+	if err != nil {
+		return SignedBundle{}, err
+	}
+	if pubdash != nil && pubdash.Uid != "" {
+		bundle.PublicDashboard = exportedPublicDashboard(pubdash)
+	}
+
+	datasourceUIDs := map[string]struct{}{}
+	for _, uid := range queries.GetUniqueDashboardDatasourceUids(dashboard.Data) {
+		datasourceUIDs[uid] = struct{}{}
+	}
+	all, err := s.correlations.GetCorrelations(ctx, correlations.GetCorrelationsQuery{OrgId: u.OrgID, SignedInUser: u})
+	if err != nil {
+		return SignedBundle{}, err
+	}
+	for _, c := range all {
+		_, fromDashboard := datasourceUIDs[c.SourceUID]
+		toDashboard := false
+		if c.TargetUID != nil {
+			_, toDashboard = datasourceUIDs[*c.TargetUID]
+		}
+		if fromDashboard || toDashboard {
+			bundle.Correlations = append(bundle.Correlations, c)
+		}
+	}
+
+	if s.contactPoints != nil {
+		contactPoints, err := s.contactPoints.GetDashboardContactPoints(ctx, u.OrgID, dashboardUID)
+		if err != nil {
+			return SignedBundle{}, err
+		}
+		for _, cp := range contactPoints {
+			// the provenance is decided by the instance importing the bundle
+			cp.Provenance = ""
+			bundle.ContactPoints = append(bundle.ContactPoints, cp)
+		}
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return SignedBundle{}, err
+	}
+	signature, err := sign(s.Cfg.ExportBundles.SigningKey, data)
+	if err != nil {
+		return SignedBundle{}, err
+	}
+
+	return SignedBundle{Bundle: data, Signature: signature}, nil
+}
+
+func (s *ExportBundleService) Import(ctx context.Context, u *user.SignedInUser, signed SignedBundle) (ImportResult, error) {
+	if err := verify(s.Cfg.ExportBundles.SigningKey, signed); err != nil {
+		return ImportResult{}, err
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(signed.Bundle, &bundle); err != nil {
+		return ImportResult{}, ErrInvalidBundle.Errorf("failed to read the bundle: %w", err)
+	}
+	if bundle.Version != BundleVersion {
+		return ImportResult{}, ErrUnsupportedBundleVersion.Errorf("unsupported bundle version %d", bundle.Version)
+	}
+	if bundle.Dashboard == nil {
+		return ImportResult{}, ErrInvalidBundle.Errorf("the bundle has no dashboard")
+	}
+
+	dashboard, created, err := s.importDashboard(ctx, u, bundle)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	result := ImportResult{
+		DashboardUID: dashboard.Uid,
+		Resources: []ImportedResource{
+			{Kind: ResourceDashboard, UID: dashboard.Uid, Name: dashboard.Title, Status: importStatus(created)},
+		},
+	}
+
+	if bundle.PublicDashboard != nil {
+		result.Resources = append(result.Resources, s.importPublicDashboard(ctx, u, dashboard.Uid, bundle.PublicDashboard))
+	}
+
+	if len(bundle.Correlations) > 0 {
+		existing, err := s.correlations.GetCorrelations(ctx, correlations.GetCorrelationsQuery{OrgId: u.OrgID, SignedInUser: u})
+		if err != nil {
+			return result, err
+		}
+		for _, c := range bundle.Correlations {
+			result.Resources = append(result.Resources, s.importCorrelation(ctx, u.OrgID, existing, c))
+		}
+	}
+
+	for _, cp := range bundle.ContactPoints {
+		imported := ImportedResource{Kind: ResourceContactPoint, UID: cp.UID, Name: cp.Name}
+		if s.contactPoints == nil {
+			imported.Status = ImportFailed
+			imported.Error = "unified alerting is disabled"
+		} else if created, err := s.contactPoints.ImportContactPoint(ctx, u.OrgID, cp); err != nil {
+			imported.Status = ImportFailed
+			imported.Error = err.Error()
+		} else {
+			imported.Status = importStatus(created)
+		}
+		result.Resources = append(result.Resources, imported)
+	}
+
+	for _, r := range result.Resources {
+		if r.Status == ImportFailed {
+			s.log.Warn("failed to import bundle resource", "org", u.OrgID, "dashboard", dashboard.Uid, "kind", r.Kind, "uid", r.UID, "error", r.Error)
+		}
+	}
+
+	return result, nil
+}
+
+// importDashboard saves the dashboard of the bundle, overwriting the dashboard
+// with the same UID. It is saved in the General folder when the folder of the
+// bundle doesn't exist.
+func (s *ExportBundleService) importDashboard(ctx context.Context, u *user.SignedInUser, bundle Bundle) (*models.Dashboard, bool, error) {
+	bundle.Dashboard.Del("id")
+	dashboard := models.NewDashboardFromJson(bundle.Dashboard)
+	dashboard.OrgId = u.OrgID
+
+	existing := models.GetDashboardQuery{Uid: dashboard.Uid, OrgId: u.OrgID}
+	created := dashboard.Uid == ""
+	if !created {
+		if err := s.dashboards.GetDashboard(ctx, &existing); err != nil {
+			if !errors.Is(err, dashboards.ErrDashboardNotFound) {
+				return nil, false, err
+			}
+			created = true
+		}
+	}
+
+	if bundle.FolderUID != "" {
+		f, err := s.folders.GetFolderByUID(ctx, u, u.OrgID, bundle.FolderUID)
+		switch {
+		case err == nil:
+			dashboard.FolderId = f.Id
+		case errors.Is(err, dashboards.ErrFolderNotFound):
+			s.log.Warn("folder of the imported dashboard not found, saving it in the General folder", "folder", bundle.FolderUID)
+		default:
+			return nil, false, err
+		}
+	}
+
+	saved, err := s.dashboards.SaveDashboard(ctx, &dashboards.SaveDashboardDTO{
+		OrgId:     u.OrgID,
+		User:      u,
+		Message:   "Imported from bundle",
+		Overwrite: true,
+		Dashboard: dashboard,
+	}, false)
+	if err != nil {
+		return nil, false, err
+	}
+	return saved, created, nil
+}
+
+// importPublicDashboard updates the public dashboard configuration of the
+// dashboard, or creates it with a new access token.
+func (s *ExportBundleService) importPublicDashboard(ctx context.Context, u *user.SignedInUser, dashboardUID string, pubdash *publicdashboardModels.PublicDashboard) ImportedResource {
+	imported := ImportedResource{Kind: ResourcePublicDashboard}
+
+	existing, err := s.publicDashboards.GetPublicDashboardConfig(ctx, u.OrgID, dashboardUID)
+	if err != nil {
+		imported.Status, imported.Error = ImportFailed, err.Error()
+		return imported
+	}
+	pubdash.Uid = ""
+	if existing != nil {
+		pubdash.Uid = existing.Uid
+	}
+	pubdash.DashboardUid = dashboardUID
+
+	saved, err := s.publicDashboards.SavePublicDashboardConfig(ctx, u, &publicdashboardModels.SavePublicDashboardConfigDTO{
+		DashboardUid:    dashboardUID,
+		OrgId:           u.OrgID,
+		UserId:          u.UserID,
+		PublicDashboard: pubdash,
+	})
+	if err != nil {
+		imported.Status, imported.Error = ImportFailed, err.Error()
+		return imported
+	}
+	imported.UID = saved.Uid
+	imported.Status = importStatus(pubdash.Uid == "")
+	return imported
+}
+
+// importCorrelation updates the correlation with the same source, target and
+// label, or creates it. Correlations get new UIDs when they are created.
+func (s *ExportBundleService) importCorrelation(ctx context.Context, orgID int64, existing []correlations.Correlation, c correlations.Correlation) ImportedResource {
+	imported := ImportedResource{Kind: ResourceCorrelation, Name: c.Label}
+
+	for _, e := range existing {
+		if e.SourceUID != c.SourceUID || e.Label != c.Label || !sameTarget(e.TargetUID, c.TargetUID) {
+			continue
+		}
+		updated, err := s.correlations.UpdateCorrelation(ctx, correlations.UpdateCorrelationCommand{
+			UID:         e.UID,
+			SourceUID:   e.SourceUID,
+			OrgId:       orgID,
+			Description: &c.Description,
+			Order:       &c.Order,
+			Config: &correlations.CorrelationConfigUpdateDTO{
+				Field:           &c.Config.Field,
+				Type:            &c.Config.Type,
+				Target:          &c.Config.Target,
+				Targets:         &c.Config.Targets,
+				Transformations: &c.Config.Transformations,
+				Matchers:        &c.Config.Matchers,
+				Display:         &c.Config.Display,
+			},
+		})
+		if err != nil {
+			imported.Status, imported.Error = ImportFailed, err.Error()
+			return imported
+		}
+		imported.UID, imported.Status = updated.UID, ImportUpdated
+		return imported
+	}
+
+	created, err := s.correlations.CreateCorrelation(ctx, correlations.CreateCorrelationCommand{
+		SourceUID:   c.SourceUID,
+		OrgId:       orgID,
+		TargetUID:   c.TargetUID,
+		Label:       c.Label,
+		Description: c.Description,
+		Config:      c.Config,
+		Order:       c.Order,
+	})
+	if err != nil {
+		imported.Status, imported.Error = ImportFailed, err.Error()
+		return imported
+	}
+	imported.UID, imported.Status = created.UID, ImportCreated
+	return imported
+}
+
+// exportedPublicDashboard returns the configuration of the public dashboard
+// that can be moved to another instance. The access token and the pinned
+// dashboard version only make sense in this instance.
+func exportedPublicDashboard(pubdash *publicdashboardModels.PublicDashboard) *publicdashboardModels.PublicDashboard {
+	return &publicdashboardModels.PublicDashboard{
+		IsEnabled:        pubdash.IsEnabled,
+		TimeSettings:     pubdash.TimeSettings,
+		MaskingRules:     pubdash.MaskingRules,
+		QueryFilters:     pubdash.QueryFilters,
+		AllowIndexing:    pubdash.AllowIndexing,
+		RequireChallenge: pubdash.RequireChallenge,
+		TermsOfUse:       pubdash.TermsOfUse,
+		ShareMode:        pubdash.ShareMode,
+		PrerenderEnabled: pubdash.PrerenderEnabled,
+	}
+}
+
+func sameTarget(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func importStatus(created bool) ImportStatus {
+	if created {
+		return ImportCreated
+	}
+	return ImportUpdated
+}
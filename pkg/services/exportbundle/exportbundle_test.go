@@ -0,0 +1,186 @@
+package exportbundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	publicdashboardModels "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCorrelationStore struct {
+	correlations []correlations.Correlation
+	created      []correlations.CreateCorrelationCommand
+	updated      []correlations.UpdateCorrelationCommand
+}
+
+func (f *fakeCorrelationStore) GetCorrelations(context.Context, correlations.GetCorrelationsQuery) ([]correlations.Correlation, error) {
+	return f.correlations, nil
+}
+
+func (f *fakeCorrelationStore) CreateCorrelation(_ context.Context, cmd correlations.CreateCorrelationCommand) (correlations.Correlation, error) {
+	f.created = append(f.created, cmd)
+	return correlations.Correlation{UID: "new-uid", SourceUID: cmd.SourceUID, TargetUID: cmd.TargetUID, Label: cmd.Label}, nil
+}
+
+func (f *fakeCorrelationStore) UpdateCorrelation(_ context.Context, cmd correlations.UpdateCorrelationCommand) (correlations.Correlation, error) {
+	f.updated = append(f.updated, cmd)
+	return correlations.Correlation{UID: cmd.UID, SourceUID: cmd.SourceUID}, nil
+}
+
+type fakeContactPointStore struct {
+	contactPoints []apimodels.EmbeddedContactPoint
+	imported      []apimodels.EmbeddedContactPoint
+}
+
+func (f *fakeContactPointStore) GetDashboardContactPoints(context.Context, int64, string) ([]apimodels.EmbeddedContactPoint, error) {
+	return f.contactPoints, nil
+}
+
+func (f *fakeContactPointStore) ImportContactPoint(_ context.Context, _ int64, cp apimodels.EmbeddedContactPoint) (bool, error) {
+	f.imported = append(f.imported, cp)
+	return true, nil
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func newTestService(t *testing.T, dashboardService dashboards.DashboardService, publicDashboardService publicdashboards.Service,
+	correlationStore CorrelationStore, contactPointStore ContactPointStore) *ExportBundleService {
+	t.Helper()
+	cfg := setting.NewCfg()
+	cfg.ExportBundles.SigningKey = "signing-key"
+	return &ExportBundleService{
+		Cfg:              cfg,
+		log:              log.NewNopLogger(),
+		dashboards:       dashboardService,
+		folders:          &foldertest.FakeService{ExpectedFolder: &models.Folder{Id: 3, Uid: "folder-uid"}},
+		publicDashboards: publicDashboardService,
+		correlations:     correlationStore,
+		contactPoints:    contactPointStore,
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	u := &user.SignedInUser{OrgID: 1, UserID: 2}
+
+	dashboardData := simplejson.NewFromAny(map[string]interface{}{
+		"id":    7,
+		"uid":   "dash-uid",
+		"title": "Service overview",
+		"panels": []interface{}{
+			map[string]interface{}{
+				"id":         1,
+				"datasource": map[string]interface{}{"uid": "ds-1"},
+				"targets":    []interface{}{map[string]interface{}{"refId": "A"}},
+			},
+		},
+	})
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*models.GetDashboardQuery")).Run(func(args mock.Arguments) {
+		q := args.Get(1).(*models.GetDashboardQuery)
+		q.Result = &models.Dashboard{Id: 7, Uid: "dash-uid", OrgId: 1, FolderId: 3, Data: dashboardData}
+	}).Return(nil)
+
+	publicDashboardService := publicdashboards.NewFakePublicDashboardService(t)
+	publicDashboardService.On("GetPublicDashboardConfig", mock.Anything, int64(1), "dash-uid").
+		Return(&publicdashboardModels.PublicDashboard{Uid: "pubdash-uid", AccessToken: "access-token", IsEnabled: true, TermsOfUse: "Be nice"}, nil)
+
+	correlationStore := &fakeCorrelationStore{correlations: []correlations.Correlation{
+		{UID: "from-dashboard", SourceUID: "ds-1", TargetUID: strPtr("ds-2"), Label: "Logs to traces"},
+		{UID: "to-dashboard", SourceUID: "ds-3", TargetUID: strPtr("ds-1"), Label: "Traces to logs"},
+		{UID: "external", SourceUID: "ds-1", Label: "Tickets", Config: correlations.CorrelationConfig{Type: correlations.ConfigTypeExternal}},
+		{UID: "unrelated", SourceUID: "ds-3", TargetUID: strPtr("ds-4"), Label: "Unrelated"},
+	}}
+
+	contactPointStore := &fakeContactPointStore{contactPoints: []apimodels.EmbeddedContactPoint{
+		{UID: "cp-uid", Name: "db pager", Type: "slack", Provenance: "api"},
+	}}
+
+	source := newTestService(t, dashboardService, publicDashboardService, correlationStore, contactPointStore)
+
+	signed, err := source.Export(ctx, u, "dash-uid")
+	require.NoError(t, err)
+
+	var bundle Bundle
+	require.NoError(t, json.Unmarshal(signed.Bundle, &bundle))
+	require.Equal(t, BundleVersion, bundle.Version)
+	require.Equal(t, "folder-uid", bundle.FolderUID)
+	require.Equal(t, "Be nice", bundle.PublicDashboard.TermsOfUse)
+	require.Empty(t, bundle.PublicDashboard.AccessToken)
+	require.Empty(t, bundle.PublicDashboard.Uid)
+	require.Len(t, bundle.Correlations, 3)
+	require.Len(t, bundle.ContactPoints, 1)
+	require.Empty(t, bundle.ContactPoints[0].Provenance)
+
+	t.Run("imports the bundle", func(t *testing.T) {
+		targetDashboards := dashboards.NewFakeDashboardService(t)
+		targetDashboards.On("GetDashboard", mock.Anything, mock.AnythingOfType("*models.GetDashboardQuery")).Return(dashboards.ErrDashboardNotFound)
+		targetDashboards.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), false).
+			Return(func(_ context.Context, dto *dashboards.SaveDashboardDTO, _ bool) *models.Dashboard {
+				require.True(t, dto.Overwrite)
+				require.Equal(t, int64(3), dto.Dashboard.FolderId)
+				require.Zero(t, dto.Dashboard.Id)
+				return dto.Dashboard
+			}, nil)
+
+		targetPublicDashboards := publicdashboards.NewFakePublicDashboardService(t)
+		targetPublicDashboards.On("GetPublicDashboardConfig", mock.Anything, int64(1), "dash-uid").
+			Return(&publicdashboardModels.PublicDashboard{}, nil)
+		targetPublicDashboards.On("SavePublicDashboardConfig", mock.Anything, u, mock.AnythingOfType("*models.SavePublicDashboardConfigDTO")).
+			Return(&publicdashboardModels.PublicDashboard{Uid: "new-pubdash-uid"}, nil)
+
+		targetCorrelations := &fakeCorrelationStore{correlations: []correlations.Correlation{
+			{UID: "existing", SourceUID: "ds-1", TargetUID: strPtr("ds-2"), Label: "Logs to traces"},
+		}}
+		targetContactPoints := &fakeContactPointStore{}
+
+		target := newTestService(t, targetDashboards, targetPublicDashboards, targetCorrelations, targetContactPoints)
+
+		result, err := target.Import(ctx, u, signed)
+		require.NoError(t, err)
+		require.Equal(t, "dash-uid", result.DashboardUID)
+		require.Len(t, result.Resources, 6)
+		require.Equal(t, ImportedResource{Kind: ResourceDashboard, UID: "dash-uid", Name: "Service overview", Status: ImportCreated}, result.Resources[0])
+		require.Equal(t, ImportedResource{Kind: ResourcePublicDashboard, UID: "new-pubdash-uid", Status: ImportCreated}, result.Resources[1])
+
+		require.Len(t, targetCorrelations.updated, 1)
+		require.Equal(t, "existing", targetCorrelations.updated[0].UID)
+		require.Len(t, targetCorrelations.created, 2)
+		require.Len(t, targetContactPoints.imported, 1)
+	})
+
+	t.Run("accepts indented bundles", func(t *testing.T) {
+		var indented bytes.Buffer
+		require.NoError(t, json.Indent(&indented, signed.Bundle, "", "  "))
+
+		require.NoError(t, verify("signing-key", SignedBundle{Bundle: indented.Bytes(), Signature: signed.Signature}))
+	})
+
+	t.Run("rejects modified bundles", func(t *testing.T) {
+		modified := bytes.Replace(signed.Bundle, []byte("Be nice"), []byte("Be bad"), 1)
+
+		_, err := source.Import(ctx, u, SignedBundle{Bundle: modified, Signature: signed.Signature})
+		require.ErrorIs(t, err, ErrInvalidBundleSignature)
+	})
+
+	t.Run("rejects bundles signed with another key", func(t *testing.T) {
+		require.ErrorIs(t, verify("other-key", signed), ErrInvalidBundleSignature)
+	})
+}
@@ -0,0 +1,90 @@
+package exportbundle
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/correlations"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	publicdashboardModels "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrInvalidBundle            = errutil.NewBase(errutil.StatusBadRequest, "exportbundle.invalid", errutil.WithPublicMessage("Invalid bundle"))
+	ErrInvalidBundleSignature   = errutil.NewBase(errutil.StatusBadRequest, "exportbundle.invalidSignature", errutil.WithPublicMessage("The bundle signature doesn't match its content"))
+	ErrUnsupportedBundleVersion = errutil.NewBase(errutil.StatusBadRequest, "exportbundle.unsupportedVersion", errutil.WithPublicMessage("Unsupported bundle version"))
+)
+
+// BundleVersion is the version of the bundle format written by this instance.
+const BundleVersion = 1
+
+// Bundle packages a dashboard with the resources depending on it, to promote
+// it from an environment to another.
+// swagger:model ExportBundle
+type Bundle struct {
+	// Version of the bundle format
+	// example: 1
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	// Dashboard JSON model
+	Dashboard *simplejson.Json `json:"dashboard"`
+	// UID of the folder of the dashboard, empty for the General folder
+	FolderUID string `json:"folderUid,omitempty"`
+	// Public sharing configuration of the dashboard, without its access token
+	PublicDashboard *publicdashboardModels.PublicDashboard `json:"publicDashboard,omitempty"`
+	// Correlations from or to the data sources queried by the dashboard
+	Correlations []correlations.Correlation `json:"correlations"`
+	// Contact points notified by the alert rules of the dashboard, with their
+	// secure settings redacted
+	ContactPoints []apimodels.EmbeddedContactPoint `json:"contactPoints"`
+}
+
+// SignedBundle is a bundle with the signature of its content, verified when it
+// is imported.
+// swagger:model SignedExportBundle
+type SignedBundle struct {
+	// Bundle is kept as exported, the signature is computed from its compacted JSON
+	Bundle json.RawMessage `json:"bundle"`
+	// Base64 encoded HMAC-SHA256 of the bundle
+	Signature string `json:"signature"`
+}
+
+// ResourceKind is the kind of a resource imported from a bundle.
+type ResourceKind string
+
+const (
+	ResourceDashboard       ResourceKind = "dashboard"
+	ResourcePublicDashboard ResourceKind = "publicDashboard"
+	ResourceCorrelation     ResourceKind = "correlation"
+	ResourceContactPoint    ResourceKind = "contactPoint"
+)
+
+// ImportStatus is the outcome of the import of a resource.
+type ImportStatus string
+
+const (
+	ImportCreated ImportStatus = "created"
+	ImportUpdated ImportStatus = "updated"
+	ImportFailed  ImportStatus = "failed"
+)
+
+// ImportedResource reports the import of a resource of a bundle
+type ImportedResource struct {
+	Kind ResourceKind `json:"kind"`
+	// UID of the resource in this instance
+	UID    string       `json:"uid,omitempty"`
+	Name   string       `json:"name,omitempty"`
+	Status ImportStatus `json:"status"`
+	// Reason the import failed
+	Error string `json:"error,omitempty"`
+}
+
+// ImportResult reports the import of a bundle. The dashboard is imported first,
+// the other resources are imported even if some of them fail.
+// swagger:model ImportExportBundleResult
+type ImportResult struct {
+	DashboardUID string             `json:"dashboardUid"`
+	Resources    []ImportedResource `json:"resources"`
+}
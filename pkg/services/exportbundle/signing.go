@@ -0,0 +1,48 @@
+package exportbundle
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// sign returns the base64 encoded signature of the bundle JSON.
+func sign(key string, bundle []byte) (string, error) {
+	sum, err := mac(key, bundle)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sum), nil
+}
+
+// verify checks that the signature was computed from the bundle JSON with the key.
+func verify(key string, signed SignedBundle) error {
+	expected, err := mac(key, signed.Bundle)
+	if err != nil {
+		return ErrInvalidBundle.Errorf("invalid bundle JSON: %w", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(signed.Signature)
+	if err != nil {
+		return ErrInvalidBundleSignature.Errorf("invalid signature encoding: %w", err)
+	}
+	if !hmac.Equal(signature, expected) {
+		return ErrInvalidBundleSignature.Errorf("the bundle signature doesn't match its content")
+	}
+	return nil
+}
+
+// mac computes the HMAC-SHA256 of the bundle JSON. The JSON is compacted
+// first, so that indenting the bundle file doesn't invalidate it.
+func mac(key string, bundle []byte) ([]byte, error) {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, bundle); err != nil {
+		return nil, err
+	}
+
+	h := hmac.New(sha256.New, []byte(key))
+	h.Write(compacted.Bytes())
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,61 @@
+package grpcserver
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type publicError interface {
+	Public() errutil.PublicError
+}
+
+// ErrorToStatus converts an error returned by a service to a gRPC status
+// error. Errors exposing a public message, such as the errutil errors, keep
+// their message and get the code matching their HTTP status. Any other error
+// is reported as an internal error, without relaying its message.
+func ErrorToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var pubErr publicError
+	if !errors.As(err, &pubErr) {
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	public := pubErr.Public()
+	return status.Error(codeFromHTTPStatus(public.StatusCode), public.Message)
+}
+
+func codeFromHTTPStatus(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,63 @@
+package grpcserver
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorToStatus(t *testing.T) {
+	notFound := errutil.NewBase(errutil.StatusNotFound, "test.notFound", errutil.WithPublicMessage("Thing not found"))
+
+	testCases := []struct {
+		desc        string
+		err         error
+		expectedMsg string
+		expected    codes.Code
+	}{
+		{
+			desc:     "nil error",
+			err:      nil,
+			expected: codes.OK,
+		},
+		{
+			desc:        "errutil error keeps its public message",
+			err:         fmt.Errorf("wrapped: %w", notFound.Errorf("thing %s does not exist", "secret")),
+			expected:    codes.NotFound,
+			expectedMsg: "Thing not found",
+		},
+		{
+			desc:        "status error is returned as is",
+			err:         status.Error(codes.PermissionDenied, "denied"),
+			expected:    codes.PermissionDenied,
+			expectedMsg: "denied",
+		},
+		{
+			desc:        "other errors are internal",
+			err:         errors.New("connection refused to 10.0.0.1"),
+			expected:    codes.Internal,
+			expectedMsg: "internal error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := ErrorToStatus(tc.err)
+			if tc.expected == codes.OK {
+				require.NoError(t, err)
+				return
+			}
+
+			s, ok := status.FromError(err)
+			require.True(t, ok)
+			require.Equal(t, tc.expected, s.Code())
+			require.Equal(t, tc.expectedMsg, s.Message())
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package grpcservertest
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/grpcserver"
+	grpccontext "github.com/grafana/grafana/pkg/services/grpcserver/context"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Server is an in-memory gRPC server authenticating every call as the user
+// it was created with, or rejecting them when there is none. Services get
+// registered on it like on the real server, before calling Dial.
+type Server struct {
+	grpcserver.Provider
+	ContextHandler grpccontext.ContextHandler
+
+	listener *bufconn.Listener
+}
+
+func NewServer(t testing.TB, u *user.SignedInUser) *Server {
+	t.Helper()
+
+	tracer := tracing.InitializeTracerForTest()
+	contextHandler := grpccontext.ProvideContextHandler(tracer)
+	provider, err := grpcserver.ProvideService(setting.NewCfg(), &fakeAuthenticator{user: u, contextHandler: contextHandler}, tracer)
+	require.NoError(t, err)
+
+	return &Server{
+		Provider:       provider,
+		ContextHandler: contextHandler,
+		listener:       bufconn.Listen(1024 * 1024),
+	}
+}
+
+// Dial starts serving the registered services and returns a client
+// connection to the server. Both are stopped when the test finishes.
+func (s *Server) Dial(t testing.TB) *grpc.ClientConn {
+	t.Helper()
+
+	go func() {
+		_ = s.GetServer().Serve(s.listener)
+	}()
+	t.Cleanup(s.GetServer().Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+type fakeAuthenticator struct {
+	user           *user.SignedInUser
+	contextHandler grpccontext.ContextHandler
+}
+
+func (a *fakeAuthenticator) Authenticate(ctx context.Context) (context.Context, error) {
+	if a.user == nil {
+		return ctx, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return a.contextHandler.SetUser(ctx, a.user), nil
+}
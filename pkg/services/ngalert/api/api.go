@@ -21,7 +21,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/orgpolicy"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -52,6 +54,7 @@ type Alertmanager interface {
 	// Receivers
 	GetReceivers(ctx context.Context) apimodels.Receivers
 	TestReceivers(ctx context.Context, c apimodels.TestReceiversConfigBodyParams) (*notifier.TestReceiversResult, error)
+	GetShadowNotifications(uid string) apimodels.ShadowNotifications
 }
 
 type AlertingStore interface {
@@ -78,10 +81,14 @@ type API struct {
 	AccessControl        accesscontrol.AccessControl
 	Policies             *provisioning.NotificationPolicyService
 	ContactPointService  *provisioning.ContactPointService
+	ContactPointHistory  ContactPointHistoryStore
+	LegacyNotifiers      *provisioning.LegacyNotifierMigrator
 	Templates            *provisioning.TemplateService
 	MuteTimings          *provisioning.MuteTimingService
 	AlertRules           *provisioning.AlertRuleService
 	AlertsRouter         *sender.AlertsRouter
+	OrgPolicies          orgpolicy.Service
+	RateLimits           ratelimit.Service
 }
 
 // RegisterAPIEndpoints registers API handlers
@@ -95,11 +102,14 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	evaluator := eval.NewEvaluator(api.Cfg, log.New("ngalert.eval"), api.DatasourceCache, api.ExpressionService)
 
 	// Register endpoints for proxying to Alertmanager-compatible backends.
-	api.RegisterAlertmanagerApiEndpoints(NewForkingAM(
-		api.DatasourceCache,
-		NewLotexAM(proxy, logger),
-		&AlertmanagerSrv{crypto: api.MultiOrgAlertmanager.Crypto, log: logger, ac: api.AccessControl, mam: api.MultiOrgAlertmanager},
-	), m)
+	api.RegisterAlertmanagerApiEndpoints(rateLimitedAlertmanagerApi{
+		AlertmanagerApi: NewForkingAM(
+			api.DatasourceCache,
+			NewLotexAM(proxy, logger),
+			&AlertmanagerSrv{crypto: api.MultiOrgAlertmanager.Crypto, log: logger, ac: api.AccessControl, mam: api.MultiOrgAlertmanager, orgPolicies: api.OrgPolicies},
+		),
+		rateLimits: api.RateLimits,
+	}, m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
 	api.RegisterPrometheusApiEndpoints(NewForkingProm(
 		api.DatasourceCache,
@@ -143,8 +153,11 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		log:                 logger,
 		policies:            api.Policies,
 		contactPointService: api.ContactPointService,
+		contactPointHistory: api.ContactPointHistory,
+		legacyNotifiers:     api.LegacyNotifiers,
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
 		alertRules:          api.AlertRules,
+		orgPolicies:         api.OrgPolicies,
 	}), m)
 }
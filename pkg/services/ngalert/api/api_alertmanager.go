@@ -17,6 +17,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/orgpolicy"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -30,6 +31,8 @@ type AlertmanagerSrv struct {
 	ac     accesscontrol.AccessControl
 	mam    *notifier.MultiOrgAlertmanager
 	crypto notifier.Crypto
+	// orgPolicies may be nil, in which case no org policy is enforced.
+	orgPolicies orgpolicy.Service
 }
 
 type UnknownReceiverError struct {
@@ -222,6 +225,9 @@ func (srv AlertmanagerSrv) RoutePostAlertingConfig(c *models.ReqContext, body ap
 			return ErrResp(http.StatusBadRequest, err, "")
 		}
 	}
+	if err := checkReceiversOrgPolicies(c.Req.Context(), srv.orgPolicies, c.OrgID, body.AlertmanagerConfig.Receivers); err != nil {
+		return ErrResp(http.StatusForbidden, err, "")
+	}
 	err = srv.mam.ApplyAlertmanagerConfiguration(c.Req.Context(), c.OrgID, body)
 	if err == nil {
 		return response.JSON(http.StatusAccepted, util.DynMap{"message": "configuration created"})
@@ -258,7 +264,20 @@ func (srv AlertmanagerSrv) RouteGetReceivers(c *models.ReqContext) response.Resp
 	return response.JSON(http.StatusOK, rcvs)
 }
 
+func (srv AlertmanagerSrv) RouteGetReceiverShadowNotifications(c *models.ReqContext, uid string) response.Response {
+	am, errResp := srv.AlertmanagerFor(c.OrgID)
+	if errResp != nil {
+		return errResp
+	}
+
+	return response.JSON(http.StatusOK, am.GetShadowNotifications(uid))
+}
+
 func (srv AlertmanagerSrv) RoutePostTestReceivers(c *models.ReqContext, body apimodels.TestReceiversConfigBodyParams) response.Response {
+	if err := checkReceiversOrgPolicies(c.Req.Context(), srv.orgPolicies, c.OrgID, body.Receivers); err != nil {
+		return ErrResp(http.StatusForbidden, err, "")
+	}
+
 	if err := srv.crypto.LoadSecureSettings(c.Req.Context(), c.OrgID, body.Receivers); err != nil {
 		var unknownReceiverError UnknownReceiverError
 		if errors.As(err, &unknownReceiverError) {
@@ -85,6 +85,9 @@ func checkContactPoints(currReceivers []*apimodels.GettableApiReceiver, newRecei
 			if contactPoint.DisableResolveMessage != postedContactPoint.DisableResolveMessage {
 				return editErr
 			}
+			if contactPoint.ShadowMode != postedContactPoint.ShadowMode {
+				return editErr
+			}
 			if contactPoint.Name != postedContactPoint.Name {
 				return editErr
 			}
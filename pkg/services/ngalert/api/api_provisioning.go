@@ -12,6 +12,7 @@ import (
 	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/orgpolicy"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -19,9 +20,13 @@ type ProvisioningSrv struct {
 	log                 log.Logger
 	policies            NotificationPolicyService
 	contactPointService ContactPointService
+	contactPointHistory ContactPointHistoryStore
+	legacyNotifiers     LegacyNotifierMigrator
 	templates           TemplateService
 	muteTimings         MuteTimingService
 	alertRules          AlertRuleService
+	// orgPolicies may be nil, in which case no org policy is enforced.
+	orgPolicies orgpolicy.Service
 }
 
 type ContactPointService interface {
@@ -31,6 +36,14 @@ type ContactPointService interface {
 	DeleteContactPoint(ctx context.Context, orgID int64, uid string) error
 }
 
+type LegacyNotifierMigrator interface {
+	MigrateLegacyNotifiers(ctx context.Context, orgID int64, opts provisioning.MigrateLegacyNotifiersOptions) (definitions.LegacyNotifierMigrationReport, error)
+}
+
+type ContactPointHistoryStore interface {
+	GetContactPointHistory(ctx context.Context, orgID int64, uid string) ([]alerting_models.ContactPointHistory, error)
+}
+
 type TemplateService interface {
 	GetTemplates(ctx context.Context, orgID int64) (map[string]string, error)
 	SetTemplate(ctx context.Context, orgID int64, tmpl definitions.MessageTemplate) (definitions.MessageTemplate, error)
@@ -106,7 +119,31 @@ func (srv *ProvisioningSrv) RouteGetContactPoints(c *models.ReqContext) response
 	return response.JSON(http.StatusOK, cps)
 }
 
+func (srv *ProvisioningSrv) RouteGetContactPointHistory(c *models.ReqContext, UID string) response.Response {
+	history, err := srv.contactPointHistory.GetContactPointHistory(c.Req.Context(), c.OrgID, UID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, history)
+}
+
+func (srv *ProvisioningSrv) RoutePostMigrateLegacyNotifiers(c *models.ReqContext) response.Response {
+	report, err := srv.legacyNotifiers.MigrateLegacyNotifiers(c.Req.Context(), c.OrgID, provisioning.MigrateLegacyNotifiersOptions{
+		DryRun: c.QueryBool("dryRun"),
+		Check: func(ctx context.Context, cp definitions.EmbeddedContactPoint) error {
+			return checkContactPointOrgPolicies(ctx, srv.orgPolicies, c.OrgID, cp.Type, cp.Settings)
+		},
+	})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
 func (srv *ProvisioningSrv) RoutePostContactPoint(c *models.ReqContext, cp definitions.EmbeddedContactPoint) response.Response {
+	if err := checkContactPointOrgPolicies(c.Req.Context(), srv.orgPolicies, c.OrgID, cp.Type, cp.Settings); err != nil {
+		return ErrResp(http.StatusForbidden, err, "")
+	}
 	// TODO: provenance is hardcoded for now, change it later to make it more flexible
 	contactPoint, err := srv.contactPointService.CreateContactPoint(c.Req.Context(), c.OrgID, cp, alerting_models.ProvenanceAPI)
 	if errors.Is(err, provisioning.ErrValidation) {
@@ -120,6 +157,9 @@ func (srv *ProvisioningSrv) RoutePostContactPoint(c *models.ReqContext, cp defin
 
 func (srv *ProvisioningSrv) RoutePutContactPoint(c *models.ReqContext, cp definitions.EmbeddedContactPoint, UID string) response.Response {
 	cp.UID = UID
+	if err := checkContactPointOrgPolicies(c.Req.Context(), srv.orgPolicies, c.OrgID, cp.Type, cp.Settings); err != nil {
+		return ErrResp(http.StatusForbidden, err, "")
+	}
 	err := srv.contactPointService.UpdateContactPoint(c.Req.Context(), c.OrgID, cp, alerting_models.ProvenanceAPI)
 	if errors.Is(err, provisioning.ErrValidation) {
 		return ErrResp(http.StatusBadRequest, err, "")
@@ -156,7 +156,8 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/alerts":
 		// additional authorization is done in the request handler
 		eval = ac.EvalAny(ac.EvalPermission(ac.ActionAlertingNotificationsWrite))
-	case http.MethodGet + "/api/alertmanager/grafana/config/api/v1/receivers":
+	case http.MethodGet + "/api/alertmanager/grafana/config/api/v1/receivers",
+		http.MethodGet + "/api/alertmanager/grafana/config/api/v1/receivers/{UID}/shadow-notifications":
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
 	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/receivers/test":
 		fallback = middleware.ReqEditorRole
@@ -194,6 +195,7 @@ func (api *API) authorize(method, path string) web.Handler {
 	// Grafana-only Provisioning Read Paths
 	case http.MethodGet + "/api/v1/provisioning/policies",
 		http.MethodGet + "/api/v1/provisioning/contact-points",
+		http.MethodGet + "/api/v1/provisioning/contact-points/{UID}/history",
 		http.MethodGet + "/api/v1/provisioning/templates",
 		http.MethodGet + "/api/v1/provisioning/templates/{name}",
 		http.MethodGet + "/api/v1/provisioning/mute-timings",
@@ -206,6 +208,7 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodPut + "/api/v1/provisioning/policies",
 		http.MethodDelete + "/api/v1/provisioning/policies",
 		http.MethodPost + "/api/v1/provisioning/contact-points",
+		http.MethodPost + "/api/v1/provisioning/contact-points/migrate-legacy",
 		http.MethodPut + "/api/v1/provisioning/contact-points/{UID}",
 		http.MethodDelete + "/api/v1/provisioning/contact-points/{UID}",
 		http.MethodPut + "/api/v1/provisioning/templates/{name}",
@@ -191,6 +191,10 @@ func (f *AlertmanagerApiHandler) handleRouteGetGrafanaReceivers(ctx *models.ReqC
 	return f.GrafanaSvc.RouteGetReceivers(ctx)
 }
 
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaReceiverShadowNotifications(ctx *models.ReqContext, uid string) response.Response {
+	return f.GrafanaSvc.RouteGetReceiverShadowNotifications(ctx, uid)
+}
+
 func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaReceivers(ctx *models.ReqContext, conf apimodels.TestReceiversConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx, conf)
 }
@@ -33,6 +33,7 @@ type AlertmanagerApi interface {
 	RouteGetGrafanaAMAlerts(*models.ReqContext) response.Response
 	RouteGetGrafanaAMStatus(*models.ReqContext) response.Response
 	RouteGetGrafanaAlertingConfig(*models.ReqContext) response.Response
+	RouteGetGrafanaReceiverShadowNotifications(*models.ReqContext) response.Response
 	RouteGetGrafanaReceivers(*models.ReqContext) response.Response
 	RouteGetGrafanaSilence(*models.ReqContext) response.Response
 	RouteGetGrafanaSilences(*models.ReqContext) response.Response
@@ -115,6 +116,11 @@ func (f *AlertmanagerApiHandler) RouteGetGrafanaAMStatus(ctx *models.ReqContext)
 func (f *AlertmanagerApiHandler) RouteGetGrafanaAlertingConfig(ctx *models.ReqContext) response.Response {
 	return f.handleRouteGetGrafanaAlertingConfig(ctx)
 }
+func (f *AlertmanagerApiHandler) RouteGetGrafanaReceiverShadowNotifications(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetGrafanaReceiverShadowNotifications(ctx, uIDParam)
+}
 func (f *AlertmanagerApiHandler) RouteGetGrafanaReceivers(ctx *models.ReqContext) response.Response {
 	return f.handleRouteGetGrafanaReceivers(ctx)
 }
@@ -334,6 +340,16 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/alertmanager/grafana/config/api/v1/receivers/{UID}/shadow-notifications"),
+			api.authorize(http.MethodGet, "/api/alertmanager/grafana/config/api/v1/receivers/{UID}/shadow-notifications"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/alertmanager/grafana/config/api/v1/receivers/{UID}/shadow-notifications",
+				srv.RouteGetGrafanaReceiverShadowNotifications,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/alertmanager/grafana/config/api/v1/receivers"),
 			api.authorize(http.MethodGet, "/api/alertmanager/grafana/config/api/v1/receivers"),
@@ -25,6 +25,7 @@ type ProvisioningApi interface {
 	RouteDeleteTemplate(*models.ReqContext) response.Response
 	RouteGetAlertRule(*models.ReqContext) response.Response
 	RouteGetAlertRuleGroup(*models.ReqContext) response.Response
+	RouteGetContactpointHistory(*models.ReqContext) response.Response
 	RouteGetContactpoints(*models.ReqContext) response.Response
 	RouteGetMuteTiming(*models.ReqContext) response.Response
 	RouteGetMuteTimings(*models.ReqContext) response.Response
@@ -33,6 +34,7 @@ type ProvisioningApi interface {
 	RouteGetTemplates(*models.ReqContext) response.Response
 	RoutePostAlertRule(*models.ReqContext) response.Response
 	RoutePostContactpoints(*models.ReqContext) response.Response
+	RoutePostMigrateLegacyNotifiers(*models.ReqContext) response.Response
 	RoutePostMuteTiming(*models.ReqContext) response.Response
 	RoutePutAlertRule(*models.ReqContext) response.Response
 	RoutePutAlertRuleGroup(*models.ReqContext) response.Response
@@ -74,6 +76,11 @@ func (f *ProvisioningApiHandler) RouteGetAlertRuleGroup(ctx *models.ReqContext)
 	groupParam := web.Params(ctx.Req)[":Group"]
 	return f.handleRouteGetAlertRuleGroup(ctx, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RouteGetContactpointHistory(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetContactpointHistory(ctx, uIDParam)
+}
 func (f *ProvisioningApiHandler) RouteGetContactpoints(ctx *models.ReqContext) response.Response {
 	return f.handleRouteGetContactpoints(ctx)
 }
@@ -112,6 +119,9 @@ func (f *ProvisioningApiHandler) RoutePostContactpoints(ctx *models.ReqContext)
 	}
 	return f.handleRoutePostContactpoints(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostMigrateLegacyNotifiers(ctx *models.ReqContext) response.Response {
+	return f.handleRoutePostMigrateLegacyNotifiers(ctx)
+}
 func (f *ProvisioningApiHandler) RoutePostMuteTiming(ctx *models.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.MuteTimeInterval{}
@@ -245,6 +255,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/contact-points/{UID}/history"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points/{UID}/history"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/contact-points/{UID}/history",
+				srv.RouteGetContactpointHistory,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/contact-points"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points"),
@@ -325,6 +345,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/contact-points/migrate-legacy"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/contact-points/migrate-legacy"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/contact-points/migrate-legacy",
+				srv.RoutePostMigrateLegacyNotifiers,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/mute-timings"),
 			api.authorize(http.MethodPost, "/api/v1/provisioning/mute-timings"),
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/orgpolicy"
+)
+
+// checkReceiversOrgPolicies returns an error when a contact point of the
+// receivers targets a host that the org policies deny.
+func checkReceiversOrgPolicies(ctx context.Context, policies orgpolicy.Service, orgID int64, receivers []*apimodels.PostableApiReceiver) error {
+	for _, r := range receivers {
+		for _, gr := range r.GrafanaManagedReceivers {
+			if err := checkContactPointOrgPolicies(ctx, policies, orgID, gr.Type, gr.Settings); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkContactPointOrgPolicies returns an error when the contact point targets
+// a host that the org policies deny.
+func checkContactPointOrgPolicies(ctx context.Context, policies orgpolicy.Service, orgID int64, integrationType string, settings *simplejson.Json) error {
+	if policies == nil || integrationType != "webhook" || settings == nil {
+		return nil
+	}
+	return policies.CheckWebhookURL(ctx, orgID, settings.Get("url").MustString())
+}
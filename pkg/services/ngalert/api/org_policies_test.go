@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/orgpolicy"
+)
+
+type fakeOrgPolicies struct {
+	orgpolicy.Service
+	checkedURLs []string
+}
+
+var errDeniedHost = errors.New("denied host")
+
+func (f *fakeOrgPolicies) CheckWebhookURL(_ context.Context, _ int64, rawURL string) error {
+	f.checkedURLs = append(f.checkedURLs, rawURL)
+	if rawURL == "https://example.com" {
+		return errDeniedHost
+	}
+	return nil
+}
+
+func TestCheckReceiversOrgPolicies(t *testing.T) {
+	receiver := func(integrationType, url string) *apimodels.PostableGrafanaReceiver {
+		return &apimodels.PostableGrafanaReceiver{
+			Type:     integrationType,
+			Settings: simplejson.NewFromAny(map[string]interface{}{"url": url}),
+		}
+	}
+	receivers := func(grs ...*apimodels.PostableGrafanaReceiver) []*apimodels.PostableApiReceiver {
+		return []*apimodels.PostableApiReceiver{{
+			PostableGrafanaReceivers: apimodels.PostableGrafanaReceivers{GrafanaManagedReceivers: grs},
+		}}
+	}
+
+	t.Run("checks the URL of webhook contact points only", func(t *testing.T) {
+		policies := &fakeOrgPolicies{}
+		err := checkReceiversOrgPolicies(context.Background(), policies, 1, receivers(
+			receiver("webhook", "https://hooks.internal.corp"),
+			receiver("slack", "https://example.com"),
+		))
+		require.NoError(t, err)
+		require.Equal(t, []string{"https://hooks.internal.corp"}, policies.checkedURLs)
+	})
+
+	t.Run("returns the policy violation", func(t *testing.T) {
+		err := checkReceiversOrgPolicies(context.Background(), &fakeOrgPolicies{}, 1, receivers(receiver("webhook", "https://example.com")))
+		require.ErrorIs(t, err, errDeniedHost)
+	})
+
+	t.Run("enforces nothing without policies", func(t *testing.T) {
+		require.NoError(t, checkReceiversOrgPolicies(context.Background(), nil, 1, receivers(receiver("webhook", "https://example.com"))))
+	})
+}
@@ -28,10 +28,18 @@ func (f *ProvisioningApiHandler) handleRouteGetContactpoints(ctx *models.ReqCont
 	return f.svc.RouteGetContactPoints(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetContactpointHistory(ctx *models.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetContactPointHistory(ctx, UID)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePostContactpoints(ctx *models.ReqContext, cp apimodels.EmbeddedContactPoint) response.Response {
 	return f.svc.RoutePostContactPoint(ctx, cp)
 }
 
+func (f *ProvisioningApiHandler) handleRoutePostMigrateLegacyNotifiers(ctx *models.ReqContext) response.Response {
+	return f.svc.RoutePostMigrateLegacyNotifiers(ctx)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePutContactpoint(ctx *models.ReqContext, cp apimodels.EmbeddedContactPoint, UID string) response.Response {
 	return f.svc.RoutePutContactPoint(ctx, cp, UID)
 }
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
+)
+
+// rateLimitedAlertmanagerApi rate limits the tests of receivers, which send
+// notifications to external services. The other routes are left alone.
+type rateLimitedAlertmanagerApi struct {
+	AlertmanagerApi
+	rateLimits ratelimit.Service
+}
+
+func (api rateLimitedAlertmanagerApi) RoutePostTestGrafanaReceivers(ctx *models.ReqContext) response.Response {
+	if res := api.rateLimits.Check(ctx, ratelimit.NotificationTests, ratelimit.ByUser); res != nil {
+		return res
+	}
+	return api.AlertmanagerApi.RoutePostTestGrafanaReceivers(ctx)
+}
+
+func (api rateLimitedAlertmanagerApi) RoutePostTestReceivers(ctx *models.ReqContext) response.Response {
+	if res := api.rateLimits.Check(ctx, ratelimit.NotificationTests, ratelimit.ByUser); res != nil {
+		return res
+	}
+	return api.AlertmanagerApi.RoutePostTestReceivers(ctx)
+}
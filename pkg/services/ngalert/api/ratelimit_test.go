@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// fakeRateLimits rejects every request checked against the rejected rate limit
+type fakeRateLimits struct {
+	rejected string
+	checked  []string
+}
+
+func (f *fakeRateLimits) Allow(context.Context, string, string) (bool, time.Duration, error) {
+	return true, 0, nil
+}
+
+func (f *fakeRateLimits) Check(_ *models.ReqContext, name string, _ ratelimit.KeyFunc) response.Response {
+	f.checked = append(f.checked, name)
+	if name == f.rejected {
+		return response.Error(http.StatusTooManyRequests, "Too many requests, try again later", nil)
+	}
+	return nil
+}
+
+func (f *fakeRateLimits) Middleware(string, ratelimit.KeyFunc) web.Handler {
+	return func(c *models.ReqContext) {}
+}
+
+// fakeAlertmanagerApi answers the tests of receivers, the other routes panic
+type fakeAlertmanagerApi struct {
+	AlertmanagerApi
+}
+
+func (fakeAlertmanagerApi) RoutePostTestGrafanaReceivers(*models.ReqContext) response.Response {
+	return response.Empty(http.StatusOK)
+}
+
+func (fakeAlertmanagerApi) RoutePostTestReceivers(*models.ReqContext) response.Response {
+	return response.Empty(http.StatusOK)
+}
+
+func TestRateLimitedAlertmanagerApi(t *testing.T) {
+	t.Run("rate limits the tests of receivers", func(t *testing.T) {
+		limits := &fakeRateLimits{rejected: ratelimit.NotificationTests}
+		api := rateLimitedAlertmanagerApi{AlertmanagerApi: fakeAlertmanagerApi{}, rateLimits: limits}
+
+		require.Equal(t, http.StatusTooManyRequests, api.RoutePostTestGrafanaReceivers(createRequestCtxInOrg(1)).Status())
+		require.Equal(t, http.StatusTooManyRequests, api.RoutePostTestReceivers(createRequestCtxInOrg(1)).Status())
+		require.Equal(t, []string{ratelimit.NotificationTests, ratelimit.NotificationTests}, limits.checked)
+	})
+
+	t.Run("tests the receivers within the rate limit", func(t *testing.T) {
+		limits := &fakeRateLimits{}
+		api := rateLimitedAlertmanagerApi{AlertmanagerApi: fakeAlertmanagerApi{}, rateLimits: limits}
+
+		require.Equal(t, http.StatusOK, api.RoutePostTestGrafanaReceivers(createRequestCtxInOrg(1)).Status())
+		require.Equal(t, http.StatusOK, api.RoutePostTestReceivers(createRequestCtxInOrg(1)).Status())
+	})
+}
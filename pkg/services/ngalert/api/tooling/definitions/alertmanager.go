@@ -19,6 +19,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -149,6 +150,14 @@ import (
 //     Responses:
 //       200: receivers
 
+// swagger:route GET /api/alertmanager/grafana/config/api/v1/receivers/{UID}/shadow-notifications alertmanager RouteGetGrafanaReceiverShadowNotifications
+//
+// Get the notifications a contact point in shadow mode would have sent, most recent first.
+//
+//     Responses:
+//       200: ShadowNotifications
+//       404: NotFound
+
 // swagger:route POST /api/alertmanager/grafana/config/api/v1/receivers/test alertmanager RoutePostTestGrafanaReceivers
 //
 // Test Grafana managed receivers without saving them.
@@ -295,6 +304,30 @@ type TestReceiverConfigResult struct {
 	Error  string `json:"error,omitempty"`
 }
 
+// swagger:parameters RouteGetGrafanaReceiverShadowNotifications
+type ShadowNotificationsParams struct {
+	// UID is the contact point unique identifier
+	// in:path
+	UID string
+}
+
+// swagger:model
+type ShadowNotifications []ShadowNotification
+
+// ShadowNotification is a notification that a contact point in shadow mode
+// rendered but didn't send.
+// swagger:model
+type ShadowNotification struct {
+	Receiver  string    `json:"receiver"`
+	UID       string    `json:"uid"`
+	Type      string    `json:"type"`
+	Alerts    int       `json:"alerts"`
+	Timestamp time.Time `json:"timestamp"`
+	// Deliveries are the requests or emails the contact point would have sent.
+	Deliveries []notifications.ShadowDelivery `json:"deliveries"`
+	Error      string                         `json:"error,omitempty"`
+}
+
 // swagger:parameters RouteCreateSilence RouteCreateGrafanaSilence
 type CreateSilenceParams struct {
 	// in:body
@@ -982,6 +1015,7 @@ type GettableGrafanaReceiver struct {
 	Name                  string            `json:"name"`
 	Type                  string            `json:"type"`
 	DisableResolveMessage bool              `json:"disableResolveMessage"`
+	ShadowMode            bool              `json:"shadowMode,omitempty"`
 	Settings              *simplejson.Json  `json:"settings"`
 	SecureFields          map[string]bool   `json:"secureFields"`
 	Provenance            models.Provenance `json:"provenance,omitempty"`
@@ -992,6 +1026,7 @@ type PostableGrafanaReceiver struct {
 	Name                  string            `json:"name"`
 	Type                  string            `json:"type"`
 	DisableResolveMessage bool              `json:"disableResolveMessage"`
+	ShadowMode            bool              `json:"shadowMode,omitempty"`
 	Settings              *simplejson.Json  `json:"settings"`
 	SecureSettings        map[string]string `json:"secureSettings"`
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
 )
@@ -15,6 +16,13 @@ import (
 //     Responses:
 //       200: ContactPoints
 
+// swagger:route GET /api/v1/provisioning/contact-points/{UID}/history provisioning stable RouteGetContactpointHistory
+//
+// Get the changes made to a contact point, most recent first.
+//
+//     Responses:
+//       200: ContactPointHistory
+
 // swagger:route POST /api/v1/provisioning/contact-points provisioning stable RoutePostContactpoints
 //
 // Create a contact point.
@@ -47,7 +55,14 @@ import (
 //     Responses:
 //       204: description: The contact point was deleted successfully.
 
-// swagger:parameters RoutePutContactpoint RouteDeleteContactpoints
+// swagger:route POST /api/v1/provisioning/contact-points/migrate-legacy provisioning stable RoutePostMigrateLegacyNotifiers
+//
+// Convert the notification channels of the legacy alerting into contact points.
+//
+//     Responses:
+//       200: LegacyNotifierMigrationReport
+
+// swagger:parameters RoutePutContactpoint RouteDeleteContactpoints RouteGetContactpointHistory
 type ContactPointUIDReference struct {
 	// UID is the contact point unique identifier
 	// in:path
@@ -71,6 +86,54 @@ type ContactPointPayload struct {
 // swagger:model
 type ContactPoints []EmbeddedContactPoint
 
+// swagger:model
+type ContactPointHistory []models.ContactPointHistory
+
+// swagger:parameters RoutePostMigrateLegacyNotifiers
+type MigrateLegacyNotifiersParams struct {
+	// Report what would be migrated without creating any contact point
+	// in: query
+	// required: false
+	DryRun bool `json:"dryRun"`
+}
+
+type LegacyNotifierMigrationStatus string
+
+const (
+	// LegacyNotifierMigrated is the status of a notification channel converted into a contact point.
+	LegacyNotifierMigrated LegacyNotifierMigrationStatus = "migrated"
+	// LegacyNotifierPending is the status of a notification channel that would be converted, in a dry run.
+	LegacyNotifierPending LegacyNotifierMigrationStatus = "pending"
+	// LegacyNotifierSkipped is the status of a notification channel that is already migrated or has no contact point type.
+	LegacyNotifierSkipped LegacyNotifierMigrationStatus = "skipped"
+	// LegacyNotifierFailed is the status of a notification channel whose settings aren't valid for its contact point type.
+	LegacyNotifierFailed LegacyNotifierMigrationStatus = "failed"
+)
+
+// swagger:model
+type LegacyNotifierMigrationReport struct {
+	DryRun    bool                      `json:"dryRun"`
+	Notifiers []LegacyNotifierMigration `json:"notifiers"`
+}
+
+// LegacyNotifierMigration is the outcome of the migration of a notification channel of the legacy alerting.
+type LegacyNotifierMigration struct {
+	ID   int64  `json:"id"`
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// example: migrated
+	Status LegacyNotifierMigrationStatus `json:"status"`
+	// Reason the notification channel was skipped or failed
+	Reason string `json:"reason,omitempty"`
+	// UID of the contact point created, or to be created, for the notification channel
+	ContactPointUID string `json:"contactPointUid,omitempty"`
+	// Settings of the notification channel that have no equivalent for the contact point
+	IgnoredSettings []string `json:"ignoredSettings,omitempty"`
+	// Behaviors of the notification channel that the contact point doesn't keep
+	Warnings []string `json:"warnings,omitempty"`
+}
+
 // EmbeddedContactPoint is the contact point type that is used
 // by grafanas embedded alertmanager implementation.
 // swagger:model
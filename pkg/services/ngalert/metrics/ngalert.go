@@ -63,6 +63,8 @@ type MultiOrgAlertmanager struct {
 	Registerer               prometheus.Registerer
 	ActiveConfigurations     prometheus.Gauge
 	DiscoveredConfigurations prometheus.Gauge
+	NotificationsQueued      *prometheus.GaugeVec
+	NotificationsShed        *prometheus.CounterVec
 	registries               *OrgRegistries
 }
 
@@ -250,6 +252,18 @@ func newMultiOrgAlertmanagerMetrics(r prometheus.Registerer) *MultiOrgAlertmanag
 			Name:      "active_configurations",
 			Help:      "The number of active Alertmanager configurations.",
 		}),
+		NotificationsQueued: promauto.With(r).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "notifications_queued",
+			Help:      "The number of notifications waiting for a free dispatch slot.",
+		}, []string{"priority"}),
+		NotificationsShed: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "notifications_shed_total",
+			Help:      "The total number of notifications not sent because the dispatch queue was full.",
+		}, []string{"integration"}),
 	}
 }
 
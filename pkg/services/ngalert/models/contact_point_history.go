@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+type ContactPointHistoryAction string
+
+const (
+	ContactPointCreated ContactPointHistoryAction = "created"
+	ContactPointUpdated ContactPointHistoryAction = "updated"
+	ContactPointDeleted ContactPointHistoryAction = "deleted"
+)
+
+// ContactPointHistory records a change to a Grafana managed contact point,
+// whichever API or provisioning file it was made with.
+type ContactPointHistory struct {
+	ID     int64                     `xorm:"pk autoincr 'id'" json:"id"`
+	OrgID  int64                     `xorm:"org_id" json:"orgId"`
+	UID    string                    `xorm:"uid" json:"uid"`
+	Name   string                    `json:"name"`
+	Type   string                    `json:"type"`
+	Action ContactPointHistoryAction `json:"action"`
+	// Changes holds the changed settings, which aren't secure, by name
+	Changes map[string]ContactPointSettingChange `json:"changes"`
+	// SecureSettingsChanged holds the names of the changed secure settings,
+	// whose values are never recorded
+	SecureSettingsChanged []string `json:"secureSettingsChanged"`
+	// UserID is the user who changed the contact point, 0 when it was
+	// changed by provisioning
+	UserID  int64     `xorm:"user_id" json:"userId"`
+	Created time.Time `json:"created"`
+}
+
+func (h ContactPointHistory) TableName() string {
+	return "alert_contact_point_history"
+}
+
+type ContactPointSettingChange struct {
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
@@ -34,7 +35,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/orgpolicy"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
@@ -62,6 +65,9 @@ func ProvideService(
 	bus bus.Bus,
 	accesscontrolService accesscontrol.Service,
 	annotationsRepo annotations.Repository,
+	tracer tracing.Tracer,
+	orgPolicies orgpolicy.Service,
+	rateLimits ratelimit.Service,
 ) (*AlertNG, error) {
 	ng := &AlertNG{
 		Cfg:                  cfg,
@@ -85,6 +91,9 @@ func ProvideService(
 		bus:                  bus,
 		accesscontrolService: accesscontrolService,
 		annotationsRepo:      annotationsRepo,
+		tracer:               tracer,
+		orgPolicies:          orgPolicies,
+		rateLimits:           rateLimits,
 	}
 
 	if ng.IsDisabled() {
@@ -124,9 +133,15 @@ type AlertNG struct {
 	// Alerting notification services
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	AlertsRouter         *sender.AlertsRouter
-	accesscontrol        accesscontrol.AccessControl
-	accesscontrolService accesscontrol.Service
-	annotationsRepo      annotations.Repository
+	// DashboardContactPoints exports and imports the contact points notified
+	// by the alert rules of dashboards
+	DashboardContactPoints *provisioning.DashboardContactPointService
+	accesscontrol          accesscontrol.AccessControl
+	accesscontrolService   accesscontrol.Service
+	annotationsRepo        annotations.Repository
+	tracer                 tracing.Tracer
+	orgPolicies            orgpolicy.Service
+	rateLimits             ratelimit.Service
 
 	bus bus.Bus
 }
@@ -146,7 +161,7 @@ func (ng *AlertNG) init() error {
 
 	decryptFn := ng.SecretsService.GetDecryptedValue
 	multiOrgMetrics := ng.Metrics.GetMultiOrgAlertmanagerMetrics()
-	ng.MultiOrgAlertmanager, err = notifier.NewMultiOrgAlertmanager(ng.Cfg, store, store, ng.KVStore, store, decryptFn, multiOrgMetrics, ng.NotificationService, log.New("ngalert.multiorg.alertmanager"), ng.SecretsService)
+	ng.MultiOrgAlertmanager, err = notifier.NewMultiOrgAlertmanager(ng.Cfg, store, store, ng.KVStore, store, decryptFn, multiOrgMetrics, ng.NotificationService, log.New("ngalert.multiorg.alertmanager"), ng.SecretsService, ng.tracer)
 	if err != nil {
 		return err
 	}
@@ -171,7 +186,7 @@ func (ng *AlertNG) init() error {
 	clk := clock.New()
 
 	alertsRouter := sender.NewAlertsRouter(ng.MultiOrgAlertmanager, store, clk, appUrl, ng.Cfg.UnifiedAlerting.DisabledOrgs,
-		ng.Cfg.UnifiedAlerting.AdminConfigPollInterval, ng.DataSourceService, ng.SecretsService)
+		ng.Cfg.UnifiedAlerting.AdminConfigPollInterval, ng.DataSourceService, ng.SecretsService, ng.tracer)
 
 	// Make sure we sync at least once as Grafana starts to get the router up and running before we start sending any alerts.
 	if err := alertsRouter.SyncAndApplyConfigFromDatabase(); err != nil {
@@ -188,6 +203,7 @@ func (ng *AlertNG) init() error {
 		RuleStore:   store,
 		Metrics:     ng.Metrics.GetSchedulerMetrics(),
 		AlertSender: alertsRouter,
+		Tracer:      ng.tracer,
 	}
 
 	historian := historian.NewAnnotationHistorian(ng.annotationsRepo, ng.dashboardService, ng.Log)
@@ -205,6 +221,8 @@ func (ng *AlertNG) init() error {
 	// Provisioning
 	policyService := provisioning.NewNotificationPolicyService(store, store, store, ng.Cfg.UnifiedAlerting, ng.Log)
 	contactPointService := provisioning.NewContactPointService(store, ng.SecretsService, store, store, ng.Log)
+	legacyNotifierMigrator := provisioning.NewLegacyNotifierMigrator(store, contactPointService, ng.SecretsService, ng.Log)
+	ng.DashboardContactPoints = provisioning.NewDashboardContactPointService(store, store, contactPointService)
 	templateService := provisioning.NewTemplateService(store, store, store, ng.Log)
 	muteTimingService := provisioning.NewMuteTimingService(store, store, store, ng.Log)
 	alertRuleService := provisioning.NewAlertRuleService(store, store, ng.QuotaService, store,
@@ -230,10 +248,14 @@ func (ng *AlertNG) init() error {
 		AccessControl:        ng.accesscontrol,
 		Policies:             policyService,
 		ContactPointService:  contactPointService,
+		ContactPointHistory:  store,
+		LegacyNotifiers:      legacyNotifierMigrator,
 		Templates:            templateService,
 		MuteTimings:          muteTimingService,
 		AlertRules:           alertRuleService,
 		AlertsRouter:         alertsRouter,
+		OrgPolicies:          ng.orgPolicies,
+		RateLimits:           ng.rateLimits,
 	}
 	api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -35,6 +35,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -120,6 +121,8 @@ type Alertmanager struct {
 
 	receivers []*notify.Receiver
 
+	shadowNotifications *shadowNotifications
+
 	// muteTimes is a map where the key is the name of the mute_time_interval
 	// and the value represents all configured time_interval(s)
 	muteTimes map[string][]timeinterval.TimeInterval
@@ -132,11 +135,13 @@ type Alertmanager struct {
 	configHash      [16]byte
 	orgID           int64
 
-	decryptFn channels.GetDecryptedValueFn
+	decryptFn       channels.GetDecryptedValueFn
+	tracer          tracing.Tracer
+	dispatchLimiter *dispatchLimiter
 }
 
 func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store AlertingStore, kvStore kvstore.KVStore,
-	peer ClusterPeer, decryptFn channels.GetDecryptedValueFn, ns notifications.Service, m *metrics.Alertmanager) (*Alertmanager, error) {
+	peer ClusterPeer, decryptFn channels.GetDecryptedValueFn, ns notifications.Service, m *metrics.Alertmanager, tracer tracing.Tracer, limiter *dispatchLimiter) (*Alertmanager, error) {
 	am := &Alertmanager{
 		Settings:            cfg,
 		stopc:               make(chan struct{}),
@@ -151,6 +156,9 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		NotificationService: ns,
 		orgID:               orgID,
 		decryptFn:           decryptFn,
+		tracer:              tracer,
+		dispatchLimiter:     limiter,
+		shadowNotifications: newShadowNotifications(),
 	}
 
 	am.fileStore = NewFileStore(am.orgID, kvStore, am.WorkingDirPath())
@@ -481,7 +489,14 @@ func (am *Alertmanager) buildReceiverIntegrations(receiver *apimodels.PostableAp
 		if err != nil {
 			return nil, err
 		}
-		integrations = append(integrations, notify.NewIntegration(n, n, r.Type, i))
+		var notifier notify.Notifier
+		if r.ShadowMode {
+			// Shadow notifications aren't sent, so they don't need a dispatch slot.
+			notifier = newShadowNotifier(am.shadowNotifications, r, n)
+		} else {
+			notifier = newLimitedNotifier(am.dispatchLimiter, r.Type, n)
+		}
+		integrations = append(integrations, notify.NewIntegration(newTracingNotifier(am.tracer, am.orgID, r, notifier), n, r.Type, i))
 	}
 	return integrations, nil
 }
@@ -526,6 +541,12 @@ func (am *Alertmanager) buildReceiverIntegration(r *apimodels.PostableGrafanaRec
 			Err:      fmt.Errorf("notifier %s is not supported", r.Type),
 		}
 	}
+	if !channels.IsReceiverAvailable(am.Settings, r.Type, am.orgID) {
+		return nil, InvalidReceiverError{
+			Receiver: r,
+			Err:      fmt.Errorf("notifier %s is not available in this organization yet", r.Type),
+		}
+	}
 	n, err := receiverFactory(factoryConfig)
 	if err != nil {
 		return nil, InvalidReceiverError{
@@ -67,6 +67,7 @@ func (moa *MultiOrgAlertmanager) GetAlertmanagerConfiguration(ctx context.Contex
 				Name:                  pr.Name,
 				Type:                  pr.Type,
 				DisableResolveMessage: pr.DisableResolveMessage,
+				ShadowMode:            pr.ShadowMode,
 				Settings:              pr.Settings,
 				SecureFields:          secureFields,
 			}
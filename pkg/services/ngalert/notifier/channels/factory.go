@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/prometheus/alertmanager/template"
 )
 
@@ -67,8 +68,31 @@ var receiverFactories = map[string]func(FactoryConfig) (NotificationChannel, err
 	"wecom":                   WeComFactory,
 }
 
+// receiverRollouts maps the receiver types being rolled out to the feature
+// toggle that makes them available to every org. Until their toggle is
+// enabled, they are only available to the orgs allowed in the
+// [unified_alerting.receiver_rollouts] section of the configuration.
+var receiverRollouts = map[string]string{}
+
 func Factory(receiverType string) (func(FactoryConfig) (NotificationChannel, error), bool) {
 	receiverType = strings.ToLower(receiverType)
 	factory, exists := receiverFactories[receiverType]
 	return factory, exists
 }
+
+// IsReceiverAvailable returns false for the receiver types being rolled out
+// that are not available to the org yet.
+func IsReceiverAvailable(cfg *setting.Cfg, receiverType string, orgID int64) bool {
+	receiverType = strings.ToLower(receiverType)
+	toggle, gated := receiverRollouts[receiverType]
+	if !gated {
+		return true
+	}
+
+	if cfg.IsFeatureToggleEnabled != nil && cfg.IsFeatureToggleEnabled(toggle) {
+		return true
+	}
+
+	_, allowed := cfg.UnifiedAlerting.ReceiverRollouts[receiverType][orgID]
+	return allowed
+}
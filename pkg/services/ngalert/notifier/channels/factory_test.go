@@ -0,0 +1,39 @@
+package channels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIsReceiverAvailable(t *testing.T) {
+	receiverRollouts["pilot"] = "alertingPilotChannel"
+	t.Cleanup(func() { delete(receiverRollouts, "pilot") })
+
+	newCfg := func(enabledToggle string, pilotOrgs ...int64) *setting.Cfg {
+		cfg := setting.NewCfg()
+		cfg.IsFeatureToggleEnabled = func(key string) bool { return key == enabledToggle }
+		cfg.UnifiedAlerting.ReceiverRollouts = map[string]map[int64]struct{}{"pilot": {}}
+		for _, orgID := range pilotOrgs {
+			cfg.UnifiedAlerting.ReceiverRollouts["pilot"][orgID] = struct{}{}
+		}
+		return cfg
+	}
+
+	t.Run("receivers that aren't rolled out are available to every org", func(t *testing.T) {
+		require.True(t, IsReceiverAvailable(setting.NewCfg(), "webhook", 1))
+	})
+
+	t.Run("receivers being rolled out are only available to the allowed orgs", func(t *testing.T) {
+		cfg := newCfg("", 2)
+		require.False(t, IsReceiverAvailable(cfg, "pilot", 1))
+		require.True(t, IsReceiverAvailable(cfg, "Pilot", 2))
+		require.False(t, IsReceiverAvailable(setting.NewCfg(), "pilot", 2))
+	})
+
+	t.Run("receivers being rolled out are available to every org once their toggle is enabled", func(t *testing.T) {
+		require.True(t, IsReceiverAvailable(newCfg("alertingPilotChannel"), "pilot", 1))
+	})
+}
@@ -3,12 +3,10 @@ package channels
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -195,21 +193,7 @@ var sendSlackRequest = func(request *http.Request, logger log.Logger) (retErr er
 		}
 	}()
 
-	netTransport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			Renegotiation: tls.RenegotiateFreelyAsClient,
-		},
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout: 30 * time.Second,
-		}).DialContext,
-		TLSHandshakeTimeout: 5 * time.Second,
-	}
-	netClient := &http.Client{
-		Timeout:   time.Second * 30,
-		Transport: netTransport,
-	}
-	resp, err := netClient.Do(request)
+	resp, err := notifications.NewOutboundClient().Do(request)
 	if err != nil {
 		return err
 	}
@@ -129,14 +129,16 @@ func resetTimeNow() {
 }
 
 type notificationServiceMock struct {
-	Webhook     models.SendWebhookSync
-	EmailSync   models.SendEmailCommandSync
-	Emailx      models.SendEmailCommand
-	ShouldError error
+	Webhook         models.SendWebhookSync
+	WebhookResponse *models.WebhookResponse
+	EmailSync       models.SendEmailCommandSync
+	Emailx          models.SendEmailCommand
+	ShouldError     error
 }
 
 func (ns *notificationServiceMock) SendWebhookSync(ctx context.Context, cmd *models.SendWebhookSync) error {
 	ns.Webhook = *cmd
+	cmd.Result = ns.WebhookResponse
 	return ns.ShouldError
 }
 func (ns *notificationServiceMock) SendEmailCommandHandlerSync(ctx context.Context, cmd *models.SendEmailCommandSync) error {
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
 	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
@@ -43,6 +44,25 @@ type webhookSettings struct {
 	// HTTP Basic Authentication.
 	User     string `json:"username,omitempty" yaml:"username,omitempty"`
 	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+
+	// TLS of the connection to the webhook, for endpoints behind a private PKI.
+	TLSSkipVerify bool   `json:"tlsSkipVerify,omitempty" yaml:"tlsSkipVerify,omitempty"`
+	TLSCACert     string `json:"tlsCACert,omitempty" yaml:"tlsCACert,omitempty"`
+	TLSClientCert string `json:"tlsClientCert,omitempty" yaml:"tlsClientCert,omitempty"`
+	TLSClientKey  string `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty"`
+}
+
+// tlsOptions returns the TLS configuration of the webhook, nil when it uses the default one
+func (s webhookSettings) tlsOptions() *httpclient.TLSOptions {
+	if !s.TLSSkipVerify && s.TLSCACert == "" && s.TLSClientCert == "" {
+		return nil
+	}
+	return &httpclient.TLSOptions{
+		CACertificate:      s.TLSCACert,
+		ClientCertificate:  s.TLSClientCert,
+		ClientKey:          s.TLSClientKey,
+		InsecureSkipVerify: s.TLSSkipVerify,
+	}
 }
 
 func buildWebhookSettings(factoryConfig FactoryConfig) (webhookSettings, error) {
@@ -59,13 +79,19 @@ func buildWebhookSettings(factoryConfig FactoryConfig) (webhookSettings, error)
 	}
 	settings.User = factoryConfig.DecryptFunc(context.Background(), factoryConfig.Config.SecureSettings, "username", settings.User)
 	settings.Password = factoryConfig.DecryptFunc(context.Background(), factoryConfig.Config.SecureSettings, "password", settings.Password)
-	settings.AuthorizationCredentials = factoryConfig.DecryptFunc(context.Background(), factoryConfig.Config.SecureSettings, "authorization_scheme", settings.AuthorizationCredentials)
+	settings.AuthorizationCredentials = factoryConfig.DecryptFunc(context.Background(), factoryConfig.Config.SecureSettings, "authorization_credentials", settings.AuthorizationCredentials)
 	if settings.AuthorizationCredentials != "" && settings.AuthorizationScheme == "" {
 		settings.AuthorizationScheme = "Bearer"
 	}
 	if settings.User != "" && settings.Password != "" && settings.AuthorizationScheme != "" && settings.AuthorizationCredentials != "" {
 		return settings, errors.New("both HTTP Basic Authentication and Authorization Header are set, only 1 is permitted")
 	}
+	settings.TLSCACert = factoryConfig.DecryptFunc(context.Background(), factoryConfig.Config.SecureSettings, "tlsCACert", settings.TLSCACert)
+	settings.TLSClientCert = factoryConfig.DecryptFunc(context.Background(), factoryConfig.Config.SecureSettings, "tlsClientCert", settings.TLSClientCert)
+	settings.TLSClientKey = factoryConfig.DecryptFunc(context.Background(), factoryConfig.Config.SecureSettings, "tlsClientKey", settings.TLSClientKey)
+	if (settings.TLSClientCert == "") != (settings.TLSClientKey == "") {
+		return settings, errors.New("both the TLS client certificate and key must be set")
+	}
 	return settings, err
 }
 
@@ -197,6 +223,7 @@ func (wn *WebhookNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool
 		Body:       string(body),
 		HttpMethod: wn.settings.HTTPMethod,
 		HttpHeader: headers,
+		TLS:        wn.settings.tlsOptions(),
 	}
 
 	if err := wn.ns.SendWebhookSync(ctx, cmd); err != nil {
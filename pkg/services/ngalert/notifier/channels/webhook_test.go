@@ -7,9 +7,11 @@ import (
 	"testing"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
 	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
@@ -27,9 +29,10 @@ func TestWebhookNotifier(t *testing.T) {
 	orgID := int64(1)
 
 	cases := []struct {
-		name     string
-		settings string
-		alerts   []*types.Alert
+		name           string
+		settings       string
+		secureSettings map[string]string
+		alerts         []*types.Alert
 
 		expMsg        *webhookMessage
 		expUrl        string
@@ -37,6 +40,7 @@ func TestWebhookNotifier(t *testing.T) {
 		expPassword   string
 		expHeaders    map[string]string
 		expHttpMethod string
+		expTLS        *httpclient.TLSOptions
 		expInitError  string
 		expMsgError   error
 	}{
@@ -234,6 +238,199 @@ func TestWebhookNotifier(t *testing.T) {
 			expHttpMethod: "POST",
 			expHeaders:    map[string]string{"Authorization": "Bearer mysecret"},
 		},
+		{
+			name: "with Authorization credentials in the secure settings",
+			settings: `{
+				"url": "http://localhost/test1",
+				"httpMethod": "POST",
+				"maxAlerts": 2
+			}`,
+			secureSettings: map[string]string{"authorization_credentials": "mysecret"},
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+						Annotations: model.LabelSet{"ann1": "annv1", "__dashboardUid__": "abcd", "__panelId__": "efgh"},
+					},
+				},
+			},
+			expMsg: &webhookMessage{
+				ExtendedData: &ExtendedData{
+					Receiver: "my_receiver",
+					Status:   "firing",
+					Alerts: ExtendedAlerts{
+						{
+							Status: "firing",
+							Labels: template.KV{
+								"alertname": "alert1",
+								"lbl1":      "val1",
+							},
+							Annotations: template.KV{
+								"ann1": "annv1",
+							},
+							Fingerprint:  "fac0861a85de433a",
+							DashboardURL: "http://localhost/d/abcd",
+							PanelURL:     "http://localhost/d/abcd?viewPanel=efgh",
+							SilenceURL:   "http://localhost/alerting/silence/new?alertmanager=grafana&matcher=alertname%3Dalert1&matcher=lbl1%3Dval1",
+						},
+					},
+					GroupLabels: template.KV{
+						"alertname": "",
+					},
+					CommonLabels: template.KV{
+						"alertname": "alert1",
+						"lbl1":      "val1",
+					},
+					CommonAnnotations: template.KV{
+						"ann1": "annv1",
+					},
+					ExternalURL: "http://localhost",
+				},
+				Version:  "1",
+				GroupKey: "alertname",
+				Title:    "[FIRING:1]  (val1)",
+				State:    "alerting",
+				Message:  "**Firing**\n\nValue: [no value]\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matcher=alertname%3Dalert1&matcher=lbl1%3Dval1\nDashboard: http://localhost/d/abcd\nPanel: http://localhost/d/abcd?viewPanel=efgh\n",
+				OrgID:    orgID,
+			},
+			expUrl:        "http://localhost/test1",
+			expHttpMethod: "POST",
+			expHeaders:    map[string]string{"Authorization": "Bearer mysecret"},
+		},
+		{
+			name: "with a custom Authorization scheme",
+			settings: `{
+				"url": "http://localhost/test1",
+				"authorization_scheme": "Api-Key",
+				"httpMethod": "POST",
+				"maxAlerts": 2
+			}`,
+			secureSettings: map[string]string{"authorization_credentials": "mysecret"},
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+						Annotations: model.LabelSet{"ann1": "annv1", "__dashboardUid__": "abcd", "__panelId__": "efgh"},
+					},
+				},
+			},
+			expMsg: &webhookMessage{
+				ExtendedData: &ExtendedData{
+					Receiver: "my_receiver",
+					Status:   "firing",
+					Alerts: ExtendedAlerts{
+						{
+							Status: "firing",
+							Labels: template.KV{
+								"alertname": "alert1",
+								"lbl1":      "val1",
+							},
+							Annotations: template.KV{
+								"ann1": "annv1",
+							},
+							Fingerprint:  "fac0861a85de433a",
+							DashboardURL: "http://localhost/d/abcd",
+							PanelURL:     "http://localhost/d/abcd?viewPanel=efgh",
+							SilenceURL:   "http://localhost/alerting/silence/new?alertmanager=grafana&matcher=alertname%3Dalert1&matcher=lbl1%3Dval1",
+						},
+					},
+					GroupLabels: template.KV{
+						"alertname": "",
+					},
+					CommonLabels: template.KV{
+						"alertname": "alert1",
+						"lbl1":      "val1",
+					},
+					CommonAnnotations: template.KV{
+						"ann1": "annv1",
+					},
+					ExternalURL: "http://localhost",
+				},
+				Version:  "1",
+				GroupKey: "alertname",
+				Title:    "[FIRING:1]  (val1)",
+				State:    "alerting",
+				Message:  "**Firing**\n\nValue: [no value]\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matcher=alertname%3Dalert1&matcher=lbl1%3Dval1\nDashboard: http://localhost/d/abcd\nPanel: http://localhost/d/abcd?viewPanel=efgh\n",
+				OrgID:    orgID,
+			},
+			expUrl:        "http://localhost/test1",
+			expHttpMethod: "POST",
+			expHeaders:    map[string]string{"Authorization": "Api-Key mysecret"},
+		},
+		{
+			name: "with TLS settings",
+			settings: `{
+				"url": "http://localhost/test1",
+				"tlsSkipVerify": true,
+				"httpMethod": "POST",
+				"maxAlerts": 2
+			}`,
+			secureSettings: map[string]string{"tlsCACert": "ca", "tlsClientCert": "cert", "tlsClientKey": "key"},
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+						Annotations: model.LabelSet{"ann1": "annv1", "__dashboardUid__": "abcd", "__panelId__": "efgh"},
+					},
+				},
+			},
+			expMsg: &webhookMessage{
+				ExtendedData: &ExtendedData{
+					Receiver: "my_receiver",
+					Status:   "firing",
+					Alerts: ExtendedAlerts{
+						{
+							Status: "firing",
+							Labels: template.KV{
+								"alertname": "alert1",
+								"lbl1":      "val1",
+							},
+							Annotations: template.KV{
+								"ann1": "annv1",
+							},
+							Fingerprint:  "fac0861a85de433a",
+							DashboardURL: "http://localhost/d/abcd",
+							PanelURL:     "http://localhost/d/abcd?viewPanel=efgh",
+							SilenceURL:   "http://localhost/alerting/silence/new?alertmanager=grafana&matcher=alertname%3Dalert1&matcher=lbl1%3Dval1",
+						},
+					},
+					GroupLabels: template.KV{
+						"alertname": "",
+					},
+					CommonLabels: template.KV{
+						"alertname": "alert1",
+						"lbl1":      "val1",
+					},
+					CommonAnnotations: template.KV{
+						"ann1": "annv1",
+					},
+					ExternalURL: "http://localhost",
+				},
+				Version:  "1",
+				GroupKey: "alertname",
+				Title:    "[FIRING:1]  (val1)",
+				State:    "alerting",
+				Message:  "**Firing**\n\nValue: [no value]\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matcher=alertname%3Dalert1&matcher=lbl1%3Dval1\nDashboard: http://localhost/d/abcd\nPanel: http://localhost/d/abcd?viewPanel=efgh\n",
+				OrgID:    orgID,
+			},
+			expUrl:        "http://localhost/test1",
+			expHttpMethod: "POST",
+			expHeaders:    map[string]string{},
+			expTLS: &httpclient.TLSOptions{
+				CACertificate:      "ca",
+				ClientCertificate:  "cert",
+				ClientKey:          "key",
+				InsecureSkipVerify: true,
+			},
+		},
+		{
+			name: "with a TLS client certificate without its key",
+			settings: `{
+				"url": "http://localhost/test1"
+			}`,
+			secureSettings: map[string]string{"tlsClientCert": "cert"},
+			expInitError:   "both the TLS client certificate and key must be set",
+		},
 		{
 			name: "with both HTTP basic auth and Authorization Header set",
 			settings: `{
@@ -246,6 +443,16 @@ func TestWebhookNotifier(t *testing.T) {
 			}`,
 			expInitError: "both HTTP Basic Authentication and Authorization Header are set, only 1 is permitted",
 		},
+		{
+			name: "with both HTTP basic auth and Authorization Header in the secure settings",
+			settings: `{
+				"url": "http://localhost/test1",
+				"username": "user1",
+				"httpMethod": "POST"
+			}`,
+			secureSettings: map[string]string{"password": "mysecret", "authorization_credentials": "mysecret"},
+			expInitError:   "both HTTP Basic Authentication and Authorization Header are set, only 1 is permitted",
+		},
 		{
 			name:         "Error in initing",
 			settings:     `{}`,
@@ -257,7 +464,13 @@ func TestWebhookNotifier(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			settingsJSON, err := simplejson.NewJson([]byte(c.settings))
 			require.NoError(t, err)
+			secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
 			secureSettings := make(map[string][]byte)
+			for key, value := range c.secureSettings {
+				encrypted, err := secretsService.Encrypt(context.Background(), []byte(value), secrets.WithoutScope())
+				require.NoError(t, err)
+				secureSettings[key] = encrypted
+			}
 
 			m := &NotificationChannelConfig{
 				OrgID:          orgID,
@@ -268,7 +481,6 @@ func TestWebhookNotifier(t *testing.T) {
 			}
 
 			webhookSender := mockNotificationService()
-			secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
 
 			fc := FactoryConfig{
 				Config:              m,
@@ -308,6 +520,7 @@ func TestWebhookNotifier(t *testing.T) {
 			require.Equal(t, c.expPassword, webhookSender.Webhook.Password)
 			require.Equal(t, c.expHttpMethod, webhookSender.Webhook.HttpMethod)
 			require.Equal(t, c.expHeaders, webhookSender.Webhook.HttpHeader)
+			require.Equal(t, c.expTLS, webhookSender.Webhook.TLS)
 		})
 	}
 }
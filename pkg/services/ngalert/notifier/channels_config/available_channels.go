@@ -680,6 +680,33 @@ func GetAvailableNotifiers() []*NotifierPlugin {
 					PropertyName: "authorization_credentials",
 					Secure:       true,
 				},
+				{
+					Label:        "TLS - Skip Verify",
+					Description:  "Skip the verification of the certificate of the webhook server. Not recommended.",
+					Element:      ElementTypeCheckbox,
+					PropertyName: "tlsSkipVerify",
+				},
+				{
+					Label:        "TLS - CA Certificate",
+					Description:  "PEM encoded certificate of the authority signing the certificate of the webhook server, for servers behind a private PKI.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsCACert",
+					Secure:       true,
+				},
+				{
+					Label:        "TLS - Client Certificate",
+					Description:  "PEM encoded certificate authenticating Grafana to the webhook server.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsClientCert",
+					Secure:       true,
+				},
+				{
+					Label:        "TLS - Client Key",
+					Description:  "PEM encoded key of the client certificate.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsClientKey",
+					Secure:       true,
+				},
 				{ // New in 8.0. TODO: How to enforce only numbers?
 					Label:        "Max Alerts",
 					Description:  "Max alerts to include in a notification. Remaining alerts in the same batch will be ignored above this number. 0 means no limit.",
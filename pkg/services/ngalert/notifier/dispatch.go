@@ -0,0 +1,147 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var ErrNotificationShed = errors.New("notification shed: too many notifications are waiting to be sent")
+
+// dispatchPriority is the priority class of the notifications sent by an integration.
+type dispatchPriority int
+
+const (
+	// priorityPaging notifications are delivered first and never shed.
+	priorityPaging dispatchPriority = iota
+	// priorityInformational notifications wait for the paging ones and are
+	// shed when too many of them are waiting.
+	priorityInformational
+)
+
+func (p dispatchPriority) String() string {
+	if p == priorityPaging {
+		return "paging"
+	}
+	return "informational"
+}
+
+// dispatchLimiter limits the number of notifications sent at the same time
+// by the Alertmanagers of all organizations. Under backlog, a free slot goes
+// to the oldest waiting paging notification before any informational one.
+type dispatchLimiter struct {
+	mtx         sync.Mutex
+	maxInFlight int64
+	maxQueued   int64
+	inFlight    int64
+	queues      [2][]chan struct{}
+
+	pagingTypes map[string]struct{}
+	metrics     *metrics.MultiOrgAlertmanager
+}
+
+// newDispatchLimiter returns nil when the number of notifications sent at the same time is not limited.
+func newDispatchLimiter(cfg setting.UnifiedAlertingDispatchSettings, m *metrics.MultiOrgAlertmanager) *dispatchLimiter {
+	if cfg.MaxConcurrentNotifications <= 0 {
+		return nil
+	}
+	return &dispatchLimiter{
+		maxInFlight: cfg.MaxConcurrentNotifications,
+		maxQueued:   cfg.MaxQueuedInformational,
+		pagingTypes: cfg.PagingTypes,
+		metrics:     m,
+	}
+}
+
+func (l *dispatchLimiter) priority(integrationType string) dispatchPriority {
+	if _, ok := l.pagingTypes[strings.ToLower(integrationType)]; ok {
+		return priorityPaging
+	}
+	return priorityInformational
+}
+
+// acquire waits for a free slot to send a notification. It returns ErrNotificationShed
+// without waiting if the notification is informational and too many are already waiting.
+func (l *dispatchLimiter) acquire(ctx context.Context, p dispatchPriority, integrationType string) error {
+	l.mtx.Lock()
+	if l.inFlight < l.maxInFlight {
+		l.inFlight++
+		l.mtx.Unlock()
+		return nil
+	}
+	if p == priorityInformational && int64(len(l.queues[p])) >= l.maxQueued {
+		l.mtx.Unlock()
+		l.metrics.NotificationsShed.WithLabelValues(integrationType).Inc()
+		return ErrNotificationShed
+	}
+	ready := make(chan struct{})
+	l.queues[p] = append(l.queues[p], ready)
+	l.metrics.NotificationsQueued.WithLabelValues(p.String()).Inc()
+	l.mtx.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		l.mtx.Lock()
+		for i, c := range l.queues[p] {
+			if c == ready {
+				l.queues[p] = append(l.queues[p][:i], l.queues[p][i+1:]...)
+				l.metrics.NotificationsQueued.WithLabelValues(p.String()).Dec()
+				l.mtx.Unlock()
+				return ctx.Err()
+			}
+		}
+		l.mtx.Unlock()
+		// The slot was handed over to us right as the context was done.
+		l.release()
+		return ctx.Err()
+	}
+}
+
+// release frees the slot of a sent notification, handing it over to the next one waiting.
+func (l *dispatchLimiter) release() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	for _, p := range []dispatchPriority{priorityPaging, priorityInformational} {
+		if len(l.queues[p]) == 0 {
+			continue
+		}
+		next := l.queues[p][0]
+		l.queues[p] = l.queues[p][1:]
+		l.metrics.NotificationsQueued.WithLabelValues(p.String()).Dec()
+		close(next)
+		return
+	}
+	l.inFlight--
+}
+
+// limitedNotifier sends the notifications of an integration once the dispatch limiter has a free slot.
+type limitedNotifier struct {
+	notify.Notifier
+	limiter         *dispatchLimiter
+	integrationType string
+}
+
+func newLimitedNotifier(limiter *dispatchLimiter, integrationType string, n notify.Notifier) notify.Notifier {
+	if limiter == nil {
+		return n
+	}
+	return &limitedNotifier{Notifier: n, limiter: limiter, integrationType: integrationType}
+}
+
+func (n *limitedNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	if err := n.limiter.acquire(ctx, n.limiter.priority(n.integrationType), n.integrationType); err != nil {
+		// Retrying a shed notification would only add to the backlog.
+		return !errors.Is(err, ErrNotificationShed), err
+	}
+	defer n.limiter.release()
+	return n.Notifier.Notify(ctx, alerts...)
+}
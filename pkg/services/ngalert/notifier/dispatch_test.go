@@ -0,0 +1,104 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func newTestDispatchLimiter(t *testing.T, maxConcurrent, maxQueued int64) *dispatchLimiter {
+	t.Helper()
+	m := metrics.NewNGAlert(prometheus.NewRegistry()).GetMultiOrgAlertmanagerMetrics()
+	l := newDispatchLimiter(setting.UnifiedAlertingDispatchSettings{
+		MaxConcurrentNotifications: maxConcurrent,
+		MaxQueuedInformational:     maxQueued,
+		PagingTypes:                map[string]struct{}{"pagerduty": {}},
+	}, m)
+	require.NotNil(t, l)
+	return l
+}
+
+func TestDispatchLimiter(t *testing.T) {
+	t.Run("is disabled without a concurrency limit", func(t *testing.T) {
+		require.Nil(t, newDispatchLimiter(setting.UnifiedAlertingDispatchSettings{}, nil))
+		n := notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) { return false, nil })
+		require.IsType(t, n, newLimitedNotifier(nil, "slack", n))
+	})
+
+	t.Run("classifies integrations by type", func(t *testing.T) {
+		l := newTestDispatchLimiter(t, 1, 1)
+		require.Equal(t, priorityPaging, l.priority("PagerDuty"))
+		require.Equal(t, priorityInformational, l.priority("slack"))
+	})
+
+	t.Run("hands the next slot to paging notifications first", func(t *testing.T) {
+		l := newTestDispatchLimiter(t, 1, 10)
+		ctx := context.Background()
+		require.NoError(t, l.acquire(ctx, priorityInformational, "slack"))
+
+		order := make(chan string, 2)
+		waitFor := func(p dispatchPriority, integrationType string) {
+			go func() {
+				require.NoError(t, l.acquire(ctx, p, integrationType))
+				order <- integrationType
+				l.release()
+			}()
+		}
+		waitFor(priorityInformational, "slack")
+		require.Eventually(t, func() bool { return queued(l, priorityInformational) == 1 }, time.Second, 10*time.Millisecond)
+		waitFor(priorityPaging, "pagerduty")
+		require.Eventually(t, func() bool { return queued(l, priorityPaging) == 1 }, time.Second, 10*time.Millisecond)
+
+		l.release()
+		require.Equal(t, "pagerduty", <-order)
+		require.Equal(t, "slack", <-order)
+		require.Eventually(t, func() bool { return inFlight(l) == 0 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("sheds informational notifications when the queue is full", func(t *testing.T) {
+		l := newTestDispatchLimiter(t, 1, 0)
+		require.NoError(t, l.acquire(context.Background(), priorityInformational, "slack"))
+
+		n := newLimitedNotifier(l, "slack", notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+			return false, nil
+		}))
+		retry, err := n.Notify(context.Background(), &types.Alert{})
+		require.ErrorIs(t, err, ErrNotificationShed)
+		require.False(t, retry)
+		require.Equal(t, 1.0, testutil.ToFloat64(l.metrics.NotificationsShed.WithLabelValues("slack")))
+	})
+
+	t.Run("gives up waiting when the context is done", func(t *testing.T) {
+		l := newTestDispatchLimiter(t, 1, 0)
+		require.NoError(t, l.acquire(context.Background(), priorityInformational, "slack"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, l.acquire(ctx, priorityPaging, "pagerduty"), context.DeadlineExceeded)
+		require.Equal(t, 0, queued(l, priorityPaging))
+		require.Equal(t, 0.0, testutil.ToFloat64(l.metrics.NotificationsQueued.WithLabelValues("paging")))
+
+		l.release()
+		require.Equal(t, int64(0), inFlight(l))
+	})
+}
+
+func queued(l *dispatchLimiter, p dispatchPriority) int {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return len(l.queues[p])
+}
+
+func inFlight(l *dispatchLimiter) int64 {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.inFlight
+}
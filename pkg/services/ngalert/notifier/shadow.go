@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+// maxShadowNotifications is the number of shadow notifications kept for each contact point.
+const maxShadowNotifications = 20
+
+// shadowNotifications keeps the latest notifications of the contact points in
+// shadow mode by UID. They're kept across configuration changes, but not restarts.
+type shadowNotifications struct {
+	mtx   sync.RWMutex
+	byUID map[string][]apimodels.ShadowNotification
+}
+
+func newShadowNotifications() *shadowNotifications {
+	return &shadowNotifications{byUID: map[string][]apimodels.ShadowNotification{}}
+}
+
+func (s *shadowNotifications) add(n apimodels.ShadowNotification) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	ns := append(s.byUID[n.UID], n)
+	if len(ns) > maxShadowNotifications {
+		ns = ns[len(ns)-maxShadowNotifications:]
+	}
+	s.byUID[n.UID] = ns
+}
+
+// get returns the notifications of the contact point, most recent first.
+func (s *shadowNotifications) get(uid string) apimodels.ShadowNotifications {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	ns := s.byUID[uid]
+	result := make(apimodels.ShadowNotifications, 0, len(ns))
+	for i := len(ns) - 1; i >= 0; i-- {
+		result = append(result, ns[i])
+	}
+	return result
+}
+
+// shadowNotifier renders the notifications of a contact point in shadow mode
+// and records what it would have sent instead of sending it.
+type shadowNotifier struct {
+	notify.Notifier
+	receiver *apimodels.PostableGrafanaReceiver
+	log      *shadowNotifications
+}
+
+func newShadowNotifier(log *shadowNotifications, receiver *apimodels.PostableGrafanaReceiver, n notify.Notifier) notify.Notifier {
+	return &shadowNotifier{Notifier: n, receiver: receiver, log: log}
+}
+
+func (n *shadowNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	var mtx sync.Mutex
+	var deliveries []notifications.ShadowDelivery
+	ctx = notifications.WithShadowRecorder(ctx, func(d notifications.ShadowDelivery) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		deliveries = append(deliveries, d)
+	})
+
+	_, err := n.Notifier.Notify(ctx, alerts...)
+
+	receiverName, _ := notify.ReceiverName(ctx)
+	sn := apimodels.ShadowNotification{
+		Receiver:   receiverName,
+		UID:        n.receiver.UID,
+		Type:       n.receiver.Type,
+		Alerts:     len(alerts),
+		Timestamp:  time.Now(),
+		Deliveries: deliveries,
+	}
+	if err != nil {
+		sn.Error = err.Error()
+	}
+	n.log.add(sn)
+
+	// Nothing was sent, so there is nothing to retry.
+	return false, err
+}
+
+// GetShadowNotifications returns the latest notifications of the contact point in shadow mode, most recent first.
+func (am *Alertmanager) GetShadowNotifications(uid string) apimodels.ShadowNotifications {
+	return am.shadowNotifications.get(uid)
+}
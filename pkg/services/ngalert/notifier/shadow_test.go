@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+func TestShadowNotifier(t *testing.T) {
+	receiver := &apimodels.PostableGrafanaReceiver{UID: "airflow-uid", Name: "airflow", Type: "webhook", ShadowMode: true}
+	ctx := notify.WithReceiverName(context.Background(), "airflow")
+
+	t.Run("records the notifications instead of sending them", func(t *testing.T) {
+		var sent bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sent = true
+		}))
+		t.Cleanup(server.Close)
+
+		log := newShadowNotifications()
+		n := newShadowNotifier(log, receiver, notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader(`{"conf":{}}`))
+			require.NoError(t, err)
+			res, err := notifications.NewOutboundClient().Do(req)
+			require.NoError(t, err)
+			return false, res.Body.Close()
+		}))
+
+		retry, err := n.Notify(ctx, &types.Alert{}, &types.Alert{})
+		require.NoError(t, err)
+		require.False(t, retry)
+		require.False(t, sent)
+
+		recorded := log.get("airflow-uid")
+		require.Len(t, recorded, 1)
+		require.Equal(t, "airflow", recorded[0].Receiver)
+		require.Equal(t, "webhook", recorded[0].Type)
+		require.Equal(t, 2, recorded[0].Alerts)
+		require.Len(t, recorded[0].Deliveries, 1)
+		require.Equal(t, `{"conf":{}}`, recorded[0].Deliveries[0].Body)
+	})
+
+	t.Run("records errors without retrying", func(t *testing.T) {
+		log := newShadowNotifications()
+		n := newShadowNotifier(log, receiver, notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+			return true, errors.New("failed to render")
+		}))
+
+		retry, err := n.Notify(ctx, &types.Alert{})
+		require.EqualError(t, err, "failed to render")
+		require.False(t, retry)
+		require.Equal(t, "failed to render", log.get("airflow-uid")[0].Error)
+	})
+
+	t.Run("keeps the latest notifications, most recent first", func(t *testing.T) {
+		log := newShadowNotifications()
+		for i := 0; i < maxShadowNotifications+5; i++ {
+			log.add(apimodels.ShadowNotification{UID: "airflow-uid", Alerts: i})
+		}
+
+		recorded := log.get("airflow-uid")
+		require.Len(t, recorded, maxShadowNotifications)
+		require.Equal(t, maxShadowNotifications+4, recorded[0].Alerts)
+		require.Equal(t, 5, recorded[maxShadowNotifications-1].Alerts)
+		require.Empty(t, log.get("other-uid"))
+	})
+}
@@ -0,0 +1,48 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// tracingNotifier traces the notifications sent by an integration, so that
+// the spans of the requests it makes, such as webhooks, are its children.
+type tracingNotifier struct {
+	notify.Notifier
+	tracer   tracing.Tracer
+	orgID    int64
+	receiver *apimodels.PostableGrafanaReceiver
+}
+
+func newTracingNotifier(tracer tracing.Tracer, orgID int64, receiver *apimodels.PostableGrafanaReceiver, n notify.Notifier) notify.Notifier {
+	return &tracingNotifier{Notifier: n, tracer: tracer, orgID: orgID, receiver: receiver}
+}
+
+func (n *tracingNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	ctx, span := n.tracer.Start(ctx, "notifier notify")
+	defer span.End()
+
+	receiverName, _ := notify.ReceiverName(ctx)
+	groupKey, _ := notify.GroupKey(ctx)
+	span.SetAttributes("org_id", n.orgID, attribute.Int64("org_id", n.orgID))
+	span.SetAttributes("receiver", receiverName, attribute.String("receiver", receiverName))
+	span.SetAttributes("integration_uid", n.receiver.UID, attribute.String("integration_uid", n.receiver.UID))
+	span.SetAttributes("integration_type", n.receiver.Type, attribute.String("integration_type", n.receiver.Type))
+	span.SetAttributes("group_key", groupKey, attribute.String("group_key", groupKey))
+	span.SetAttributes("alerts", len(alerts), attribute.Int("alerts", len(alerts)))
+
+	retry, err := n.Notifier.Notify(ctx, alerts...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes("retry", retry, attribute.Bool("retry", retry))
+	}
+	return retry, err
+}
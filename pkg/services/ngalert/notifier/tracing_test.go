@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+type notifierFunc func(ctx context.Context, alerts ...*types.Alert) (bool, error)
+
+func (f notifierFunc) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	return f(ctx, alerts...)
+}
+
+func TestTracingNotifier(t *testing.T) {
+	receiver := &apimodels.PostableGrafanaReceiver{UID: "airflow-uid", Name: "airflow", Type: "webhook"}
+	ctx := notify.WithReceiverName(context.Background(), "airflow")
+
+	t.Run("notifies with the context of the notification", func(t *testing.T) {
+		var receiverName string
+		var notified int
+		n := newTracingNotifier(tracing.InitializeTracerForTest(), 1, receiver, notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+			receiverName, _ = notify.ReceiverName(ctx)
+			notified = len(alerts)
+			return false, nil
+		}))
+
+		retry, err := n.Notify(ctx, &types.Alert{}, &types.Alert{})
+		require.NoError(t, err)
+		require.False(t, retry)
+		require.Equal(t, "airflow", receiverName)
+		require.Equal(t, 2, notified)
+	})
+
+	t.Run("returns the result of the notifier", func(t *testing.T) {
+		n := newTracingNotifier(tracing.InitializeTracerForTest(), 1, receiver, notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+			return true, errors.New("dag run failed")
+		}))
+
+		retry, err := n.Notify(ctx, &types.Alert{})
+		require.EqualError(t, err, "dag run failed")
+		require.True(t, retry)
+	})
+}
@@ -0,0 +1,118 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+)
+
+// DashboardContactPointService finds the contact points notified by the alert
+// rules of a dashboard, and imports them into other orgs or instances.
+type DashboardContactPointService struct {
+	ruleStore     RuleStore
+	amStore       AMConfigStore
+	contactPoints *ContactPointService
+}
+
+func NewDashboardContactPointService(ruleStore RuleStore, amStore AMConfigStore, contactPoints *ContactPointService) *DashboardContactPointService {
+	return &DashboardContactPointService{
+		ruleStore:     ruleStore,
+		amStore:       amStore,
+		contactPoints: contactPoints,
+	}
+}
+
+// GetDashboardContactPoints returns the contact points that the notification policies route the
+// alerts of the rules of the dashboard to, with their secure settings redacted. The alerts are
+// routed by the labels of the rules and their alert name, the reserved labels added during the
+// evaluation such as the folder title aren't known here.
+func (s *DashboardContactPointService) GetDashboardContactPoints(ctx context.Context, orgID int64, dashboardUID string) ([]apimodels.EmbeddedContactPoint, error) {
+	q := models.ListAlertRulesQuery{OrgID: orgID, DashboardUID: dashboardUID}
+	if err := s.ruleStore.ListAlertRules(ctx, &q); err != nil {
+		return nil, err
+	}
+	if len(q.Result) == 0 {
+		return []apimodels.EmbeddedContactPoint{}, nil
+	}
+
+	revision, err := getLastConfiguration(ctx, orgID, s.amStore)
+	if err != nil {
+		return nil, err
+	}
+	if revision.cfg.AlertmanagerConfig.Route == nil {
+		return nil, fmt.Errorf("no route present in current alertmanager config")
+	}
+
+	route := dispatch.NewRoute(revision.cfg.AlertmanagerConfig.Route.AsAMRoute(), nil)
+	receivers := map[string]struct{}{}
+	for _, rule := range q.Result {
+		labels := model.LabelSet{model.AlertNameLabel: model.LabelValue(rule.Title)}
+		for k, v := range rule.Labels {
+			labels[model.LabelName(k)] = model.LabelValue(v)
+		}
+		for _, r := range route.Match(labels) {
+			receivers[r.RouteOpts.Receiver] = struct{}{}
+		}
+	}
+
+	uids := map[string]struct{}{}
+	for _, r := range revision.cfg.AlertmanagerConfig.Receivers {
+		if _, ok := receivers[r.Name]; !ok {
+			continue
+		}
+		for _, gr := range r.GrafanaManagedReceivers {
+			uids[gr.UID] = struct{}{}
+		}
+	}
+
+	all, err := s.contactPoints.GetContactPoints(ctx, ContactPointQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	contactPoints := make([]apimodels.EmbeddedContactPoint, 0, len(uids))
+	for _, cp := range all {
+		if _, ok := uids[cp.UID]; ok {
+			contactPoints = append(contactPoints, cp)
+		}
+	}
+	return contactPoints, nil
+}
+
+// ImportContactPoint updates the contact point of the org with the same UID, or creates it
+// when there is none. Redacted secure settings keep the value of the updated contact point,
+// they have to be set to create one. It returns whether the contact point was created.
+func (s *DashboardContactPointService) ImportContactPoint(ctx context.Context, orgID int64, contactPoint apimodels.EmbeddedContactPoint) (bool, error) {
+	if contactPoint.UID != "" {
+		existing, err := s.contactPoints.GetContactPoints(ctx, ContactPointQuery{OrgID: orgID})
+		if err != nil {
+			return false, err
+		}
+		for _, cp := range existing {
+			if cp.UID == contactPoint.UID {
+				return false, s.contactPoints.UpdateContactPoint(ctx, orgID, contactPoint, models.ProvenanceNone)
+			}
+		}
+	}
+
+	if contactPoint.Settings != nil {
+		secretKeys, err := contactPoint.SecretKeys()
+		if err != nil {
+			return false, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+		}
+		for _, key := range secretKeys {
+			if contactPoint.Settings.Get(key).MustString() == apimodels.RedactedValue {
+				return false, fmt.Errorf("%w: the secure setting %s of contact point %s must be set to create it", ErrValidation, key, contactPoint.Name)
+			}
+		}
+	}
+
+	_, err := s.contactPoints.CreateContactPoint(ctx, orgID, contactPoint, models.ProvenanceNone)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,128 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/stretchr/testify/require"
+)
+
+const dashboardRoutingConfigJSON = `
+{
+	"alertmanager_config": {
+		"route": {
+			"receiver": "default",
+			"routes": [{
+				"receiver": "db pager",
+				"object_matchers": [["team", "=", "db"]]
+			}]
+		},
+		"receivers": [{
+			"name": "default",
+			"grafana_managed_receiver_configs": [{
+				"uid": "default-uid",
+				"name": "default",
+				"type": "email",
+				"settings": {
+					"addresses": "<example@email.com>"
+				}
+			}]
+		}, {
+			"name": "db pager",
+			"grafana_managed_receiver_configs": [{
+				"uid": "db-pager-uid",
+				"name": "db pager",
+				"type": "email",
+				"settings": {
+					"addresses": "<db@email.com>"
+				}
+			}]
+		}]
+	}
+}
+`
+
+type fakeDashboardRuleStore struct {
+	RuleStore
+	rules []*models.AlertRule
+}
+
+func (f *fakeDashboardRuleStore) ListAlertRules(_ context.Context, q *models.ListAlertRulesQuery) error {
+	for _, r := range f.rules {
+		if r.DashboardUID != nil && *r.DashboardUID == q.DashboardUID {
+			q.Result = append(q.Result, r)
+		}
+	}
+	return nil
+}
+
+func TestDashboardContactPointService(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	dashboardUID := "dash-uid"
+	createSut := func(rules ...*models.AlertRule) *DashboardContactPointService {
+		contactPoints := createContactPointServiceSut(secretsService)
+		contactPoints.amStore.(*fakeAMConfigStore).config.AlertmanagerConfiguration = dashboardRoutingConfigJSON
+		return NewDashboardContactPointService(&fakeDashboardRuleStore{rules: rules}, contactPoints.amStore, contactPoints)
+	}
+
+	t.Run("returns the contact points the rules of the dashboard are routed to", func(t *testing.T) {
+		sut := createSut(
+			&models.AlertRule{Title: "db down", DashboardUID: &dashboardUID, Labels: map[string]string{"team": "db"}},
+			&models.AlertRule{Title: "other dashboard", DashboardUID: new(string)},
+		)
+
+		cps, err := sut.GetDashboardContactPoints(ctx, 1, dashboardUID)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, "db-pager-uid", cps[0].UID)
+	})
+
+	t.Run("returns no contact points for dashboards without rules", func(t *testing.T) {
+		sut := createSut()
+
+		cps, err := sut.GetDashboardContactPoints(ctx, 1, dashboardUID)
+		require.NoError(t, err)
+		require.Empty(t, cps)
+	})
+
+	t.Run("updates the contact point with the same uid", func(t *testing.T) {
+		sut := createSut()
+		settings := simplejson.NewFromAny(map[string]interface{}{"addresses": "<new@email.com>"})
+
+		created, err := sut.ImportContactPoint(ctx, 1, definitions.EmbeddedContactPoint{UID: "db-pager-uid", Name: "db pager", Type: "email", Settings: settings})
+		require.NoError(t, err)
+		require.False(t, created)
+
+		cp, err := sut.contactPoints.getContactPointDecrypted(ctx, 1, "db-pager-uid")
+		require.NoError(t, err)
+		require.Equal(t, "<new@email.com>", cp.Settings.Get("addresses").MustString())
+	})
+
+	t.Run("creates missing contact points", func(t *testing.T) {
+		sut := createSut()
+		cp := createTestContactPoint()
+		cp.UID = "slack-uid"
+
+		created, err := sut.ImportContactPoint(ctx, 1, cp)
+		require.NoError(t, err)
+		require.True(t, created)
+	})
+
+	t.Run("doesn't create contact points with redacted secrets", func(t *testing.T) {
+		sut := createSut()
+		cp := createTestContactPoint()
+		cp.Settings.Set("token", definitions.RedactedValue)
+
+		_, err := sut.ImportContactPoint(ctx, 1, cp)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
@@ -0,0 +1,223 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	grafana_models "github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
+
+// LegacyNotificationStore reads the notification channels of the legacy alerting.
+type LegacyNotificationStore interface {
+	GetLegacyNotificationChannels(ctx context.Context, orgID int64) ([]*grafana_models.AlertNotification, error)
+}
+
+// LegacyNotifierMigrator converts the notification channels of the legacy
+// alerting into contact points, for the orgs that switched to unified
+// alerting without migrating them, or created them afterwards.
+type LegacyNotifierMigrator struct {
+	legacy        LegacyNotificationStore
+	contactPoints *ContactPointService
+	secrets       secrets.Service
+	log           log.Logger
+}
+
+func NewLegacyNotifierMigrator(legacy LegacyNotificationStore, contactPoints *ContactPointService,
+	secrets secrets.Service, log log.Logger) *LegacyNotifierMigrator {
+	return &LegacyNotifierMigrator{
+		legacy:        legacy,
+		contactPoints: contactPoints,
+		secrets:       secrets,
+		log:           log,
+	}
+}
+
+type MigrateLegacyNotifiersOptions struct {
+	// DryRun reports what would be migrated without creating any contact point.
+	DryRun bool
+	// Check, when set, is called with every contact point before it's created.
+	// The notification channel fails to migrate when it returns an error.
+	Check func(ctx context.Context, contactPoint apimodels.EmbeddedContactPoint) error
+}
+
+// MigrateLegacyNotifiers creates a contact point for every notification channel of the org
+// that has no contact point with the same name yet. The contact points aren't added to any
+// notification policy.
+func (m *LegacyNotifierMigrator) MigrateLegacyNotifiers(ctx context.Context, orgID int64, opts MigrateLegacyNotifiersOptions) (apimodels.LegacyNotifierMigrationReport, error) {
+	legacyChannels, err := m.legacy.GetLegacyNotificationChannels(ctx, orgID)
+	if err != nil {
+		return apimodels.LegacyNotifierMigrationReport{}, err
+	}
+
+	existing, err := m.contactPoints.GetContactPoints(ctx, ContactPointQuery{OrgID: orgID})
+	if err != nil {
+		return apimodels.LegacyNotifierMigrationReport{}, err
+	}
+	names := make(map[string]struct{}, len(existing))
+	uids := make(map[string]struct{}, len(existing))
+	for _, cp := range existing {
+		names[cp.Name] = struct{}{}
+		uids[cp.UID] = struct{}{}
+	}
+
+	report := apimodels.LegacyNotifierMigrationReport{
+		DryRun:    opts.DryRun,
+		Notifiers: make([]apimodels.LegacyNotifierMigration, 0, len(legacyChannels)),
+	}
+	for _, c := range legacyChannels {
+		result := apimodels.LegacyNotifierMigration{ID: c.Id, UID: c.Uid, Name: c.Name, Type: c.Type}
+		m.migrate(ctx, orgID, c, opts, names, uids, &result)
+		if result.Status == apimodels.LegacyNotifierFailed {
+			m.log.Warn("failed to migrate legacy notification channel", "org", orgID, "uid", c.Uid, "reason", result.Reason)
+		}
+		report.Notifiers = append(report.Notifiers, result)
+	}
+
+	return report, nil
+}
+
+func (m *LegacyNotifierMigrator) migrate(ctx context.Context, orgID int64, c *grafana_models.AlertNotification, opts MigrateLegacyNotifiersOptions,
+	names, uids map[string]struct{}, result *apimodels.LegacyNotifierMigration) {
+	if _, ok := channels.Factory(c.Type); !ok {
+		result.Status = apimodels.LegacyNotifierSkipped
+		result.Reason = fmt.Sprintf("there is no contact point type for %s notification channels", c.Type)
+		return
+	}
+	if _, ok := names[c.Name]; ok {
+		result.Status = apimodels.LegacyNotifierSkipped
+		result.Reason = fmt.Sprintf("a contact point named %s already exists", c.Name)
+		return
+	}
+
+	secureSettings, err := m.secrets.DecryptJsonData(ctx, c.SecureSettings)
+	if err != nil {
+		result.Status = apimodels.LegacyNotifierFailed
+		result.Reason = fmt.Sprintf("failed to decrypt the secure settings: %s", err)
+		return
+	}
+
+	settings, ignored, err := convertLegacySettings(c.Type, c.Settings, secureSettings)
+	if err != nil {
+		result.Status = apimodels.LegacyNotifierFailed
+		result.Reason = err.Error()
+		return
+	}
+	result.IgnoredSettings = ignored
+	result.Warnings = legacyChannelWarnings(c)
+
+	contactPoint := apimodels.EmbeddedContactPoint{
+		Name:                  c.Name,
+		Type:                  c.Type,
+		Settings:              settings,
+		DisableResolveMessage: c.DisableResolveMessage,
+	}
+	// keep the UID of the notification channel, unless another contact point uses it
+	if _, ok := uids[c.Uid]; !ok {
+		contactPoint.UID = c.Uid
+	}
+
+	if err := contactPoint.Valid(m.secrets.GetDecryptedValue); err != nil {
+		result.Status = apimodels.LegacyNotifierFailed
+		result.Reason = err.Error()
+		return
+	}
+	if opts.Check != nil {
+		if err := opts.Check(ctx, contactPoint); err != nil {
+			result.Status = apimodels.LegacyNotifierFailed
+			result.Reason = err.Error()
+			return
+		}
+	}
+
+	if opts.DryRun {
+		result.Status = apimodels.LegacyNotifierPending
+		result.ContactPointUID = contactPoint.UID
+		return
+	}
+
+	created, err := m.contactPoints.CreateContactPoint(ctx, orgID, contactPoint, models.ProvenanceNone)
+	if err != nil {
+		result.Status = apimodels.LegacyNotifierFailed
+		result.Reason = err.Error()
+		return
+	}
+	names[created.Name] = struct{}{}
+	uids[created.UID] = struct{}{}
+	result.Status = apimodels.LegacyNotifierMigrated
+	result.ContactPointUID = created.UID
+}
+
+// convertLegacySettings merges the settings and the decrypted secure settings of a
+// notification channel into the settings of a contact point of the same type.
+// It returns the names of the settings that the contact point type doesn't have.
+func convertLegacySettings(channelType string, settings *simplejson.Json, secureSettings map[string]string) (*simplejson.Json, []string, error) {
+	var known map[string]struct{}
+	for _, n := range channels_config.GetAvailableNotifiers() {
+		if strings.EqualFold(n.Type, channelType) {
+			known = make(map[string]struct{}, len(n.Options))
+			for _, o := range n.Options {
+				known[o.PropertyName] = struct{}{}
+			}
+			break
+		}
+	}
+	isKnown := func(key string) bool {
+		if known == nil {
+			return true
+		}
+		_, ok := known[key]
+		return ok
+	}
+
+	settingsMap := map[string]interface{}{}
+	if settings != nil {
+		var err error
+		if settingsMap, err = settings.Map(); err != nil {
+			return nil, nil, fmt.Errorf("invalid settings: %w", err)
+		}
+	}
+
+	converted := simplejson.New()
+	ignored := []string{}
+	for k, v := range settingsMap {
+		if !isKnown(k) {
+			ignored = append(ignored, k)
+			continue
+		}
+		converted.Set(k, v)
+	}
+	for k, v := range secureSettings {
+		if !isKnown(k) {
+			ignored = append(ignored, k)
+			continue
+		}
+		if v != "" {
+			converted.Set(k, v)
+		}
+	}
+	sort.Strings(ignored)
+
+	return converted, ignored, nil
+}
+
+// legacyChannelWarnings returns the behaviors of the notification channel that
+// contact points don't have.
+func legacyChannelWarnings(c *grafana_models.AlertNotification) []string {
+	var warnings []string
+	if c.SendReminder {
+		warnings = append(warnings, "reminders are replaced by the repeat interval of the notification policies")
+	}
+	if c.IsDefault {
+		warnings = append(warnings, "the contact point is not added to the default notification policy")
+	}
+	return warnings
+}
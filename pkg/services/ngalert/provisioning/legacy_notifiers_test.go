@@ -0,0 +1,102 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	grafana_models "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+type fakeLegacyNotificationStore struct {
+	channels []*grafana_models.AlertNotification
+}
+
+func (f *fakeLegacyNotificationStore) GetLegacyNotificationChannels(context.Context, int64) ([]*grafana_models.AlertNotification, error) {
+	return f.channels, nil
+}
+
+func TestLegacyNotifierMigrator(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	encryptedToken, err := secretsService.EncryptJsonData(ctx, map[string]string{"token": "secret-token"}, secrets.WithoutScope())
+	require.NoError(t, err)
+	legacy := &fakeLegacyNotificationStore{channels: []*grafana_models.AlertNotification{
+		{
+			Id: 1, Uid: "slack-uid", Name: "slack channel", Type: "slack", SendReminder: true,
+			Settings:       simplejson.NewFromAny(map[string]interface{}{"recipient": "#alerts", "uploadImage": true}),
+			SecureSettings: encryptedToken,
+		},
+		{Id: 2, Uid: "email-uid", Name: "email receiver", Type: "email", Settings: simplejson.New()},
+		{Id: 3, Uid: "airflow-uid", Name: "airflow", Type: "airflow", Settings: simplejson.New()},
+		{Id: 4, Uid: "webhook-uid", Name: "webhook", Type: "webhook", Settings: simplejson.New()},
+	}}
+
+	t.Run("reports what would be migrated in a dry run", func(t *testing.T) {
+		contactPoints := createContactPointServiceSut(secretsService)
+		sut := NewLegacyNotifierMigrator(legacy, contactPoints, secretsService, log.NewNopLogger())
+
+		report, err := sut.MigrateLegacyNotifiers(ctx, 1, MigrateLegacyNotifiersOptions{DryRun: true})
+		require.NoError(t, err)
+		require.True(t, report.DryRun)
+		require.Len(t, report.Notifiers, 4)
+
+		slack := report.Notifiers[0]
+		require.Equal(t, definitions.LegacyNotifierPending, slack.Status)
+		require.Equal(t, "slack-uid", slack.ContactPointUID)
+		require.Equal(t, []string{"uploadImage"}, slack.IgnoredSettings)
+		require.Len(t, slack.Warnings, 1)
+
+		require.Equal(t, definitions.LegacyNotifierSkipped, report.Notifiers[1].Status, "a contact point with the same name exists")
+		require.Equal(t, definitions.LegacyNotifierSkipped, report.Notifiers[2].Status, "there is no airflow contact point type")
+		require.Equal(t, definitions.LegacyNotifierFailed, report.Notifiers[3].Status, "the webhook has no URL")
+
+		cps, err := contactPoints.GetContactPoints(ctx, cpsQuery(1))
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+	})
+
+	t.Run("creates the contact points", func(t *testing.T) {
+		contactPoints := createContactPointServiceSut(secretsService)
+		sut := NewLegacyNotifierMigrator(legacy, contactPoints, secretsService, log.NewNopLogger())
+
+		report, err := sut.MigrateLegacyNotifiers(ctx, 1, MigrateLegacyNotifiersOptions{})
+		require.NoError(t, err)
+		require.Equal(t, definitions.LegacyNotifierMigrated, report.Notifiers[0].Status)
+
+		cp, err := contactPoints.getContactPointDecrypted(ctx, 1, "slack-uid")
+		require.NoError(t, err)
+		require.Equal(t, "slack channel", cp.Name)
+		require.Equal(t, "#alerts", cp.Settings.Get("recipient").MustString())
+		require.Equal(t, "secret-token", cp.Settings.Get("token").MustString())
+
+		report, err = sut.MigrateLegacyNotifiers(ctx, 1, MigrateLegacyNotifiersOptions{})
+		require.NoError(t, err)
+		require.Equal(t, definitions.LegacyNotifierSkipped, report.Notifiers[0].Status, "the channel is already migrated")
+	})
+
+	t.Run("fails the channels denied by the check", func(t *testing.T) {
+		sut := NewLegacyNotifierMigrator(legacy, createContactPointServiceSut(secretsService), secretsService, log.NewNopLogger())
+
+		report, err := sut.MigrateLegacyNotifiers(ctx, 1, MigrateLegacyNotifiersOptions{
+			DryRun: true,
+			Check: func(context.Context, definitions.EmbeddedContactPoint) error {
+				return errors.New("denied by policy")
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, definitions.LegacyNotifierFailed, report.Notifiers[0].Status)
+		require.Equal(t, "denied by policy", report.Notifiers[0].Reason)
+	})
+}
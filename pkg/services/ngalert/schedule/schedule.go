@@ -10,6 +10,7 @@ import (
 	prometheusModel "github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
@@ -22,6 +23,8 @@ import (
 	"github.com/grafana/grafana/pkg/util/ticker"
 
 	"github.com/benbjohnson/clock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -46,7 +49,7 @@ type ScheduleService interface {
 //
 //go:generate mockery --name AlertsSender --structname AlertsSenderMock --inpackage --filename alerts_sender_mock.go --with-expecter
 type AlertsSender interface {
-	Send(key ngmodels.AlertRuleKey, alerts definitions.PostableAlerts)
+	Send(ctx context.Context, key ngmodels.AlertRuleKey, alerts definitions.PostableAlerts)
 }
 
 // RulesStore is a store that provides alert rules for scheduling
@@ -91,6 +94,7 @@ type schedule struct {
 
 	alertsSender    AlertsSender
 	minRuleInterval time.Duration
+	tracer          tracing.Tracer
 
 	// schedulableAlertRules contains the alert rules that are considered for
 	// evaluation in the current tick. The evaluation of an alert rule in the
@@ -110,6 +114,7 @@ type SchedulerCfg struct {
 	RuleStore       RulesStore
 	Metrics         *metrics.Scheduler
 	AlertSender     AlertsSender
+	Tracer          tracing.Tracer
 }
 
 // NewScheduler returns a new schedule.
@@ -131,6 +136,7 @@ func NewScheduler(cfg SchedulerCfg, appURL *url.URL, stateManager *state.Manager
 		minRuleInterval:       cfg.Cfg.MinInterval,
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
+		tracer:                cfg.Tracer,
 	}
 
 	return &sch
@@ -319,7 +325,7 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		states := sch.stateManager.ResetStateByRuleUID(grafanaCtx, key)
 		expiredAlerts := FromAlertsStateToStoppedAlert(states, sch.appURL, sch.clock)
 		if len(expiredAlerts.PostableAlerts) > 0 {
-			sch.alertsSender.Send(key, expiredAlerts)
+			sch.alertsSender.Send(grafanaCtx, key, expiredAlerts)
 		}
 	}
 
@@ -327,6 +333,13 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		logger := logger.New("version", e.rule.Version, "attempt", attempt, "now", e.scheduledAt)
 		start := sch.clock.Now()
 
+		ctx, span := sch.tracer.Start(ctx, "alert rule execution")
+		defer span.End()
+		span.SetAttributes("rule_uid", key.UID, attribute.String("rule_uid", key.UID))
+		span.SetAttributes("org_id", key.OrgID, attribute.Int64("org_id", key.OrgID))
+		span.SetAttributes("rule_version", e.rule.Version, attribute.Int64("rule_version", e.rule.Version))
+		span.SetAttributes("attempt", attempt, attribute.Int64("attempt", attempt))
+
 		schedulerUser := &user.SignedInUser{
 			UserID:  -1,
 			Login:   "grafana_scheduler",
@@ -348,6 +361,12 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		if results.HasErrors() {
 			evalTotalFailures.Inc()
 			logger.Error("failed to evaluate alert rule", "results", results, "duration", dur)
+			span.SetStatus(codes.Error, "rule evaluation failed")
+			for _, result := range results {
+				if result.Error != nil {
+					span.RecordError(result.Error)
+				}
+			}
 		} else {
 			logger.Debug("alert rule evaluated", "results", results, "duration", dur)
 		}
@@ -357,8 +376,13 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		}
 		processedStates := sch.stateManager.ProcessEvalResults(ctx, e.scheduledAt, e.rule, results, sch.getRuleExtraLabels(e))
 		alerts := FromAlertStateToPostableAlerts(processedStates, sch.stateManager, sch.appURL)
+		span.AddEvents([]string{"message", "results", "alerts"}, []tracing.EventValue{
+			{Str: "results processed"},
+			{Num: int64(len(results))},
+			{Num: int64(len(alerts.PostableAlerts))},
+		})
 		if len(alerts.PostableAlerts) > 0 {
-			sch.alertsSender.Send(key, alerts)
+			sch.alertsSender.Send(ctx, key, alerts)
 		}
 	}
 
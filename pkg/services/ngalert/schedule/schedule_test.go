@@ -19,6 +19,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
@@ -119,6 +120,7 @@ func TestWarmStateCache(t *testing.T) {
 		Logger:    log.New("ngalert cache warming test"),
 		RuleStore: dbstore,
 		Metrics:   testMetrics.GetSchedulerMetrics(),
+		Tracer:    tracing.InitializeTracerForTest(),
 	}
 	st := state.NewManager(schedCfg.Logger, testMetrics.GetStateMetrics(), nil, dbstore, dbstore, &image.NoopImageService{}, clock.NewMock(), &state.FakeHistorian{})
 	st.Warm(ctx)
@@ -156,7 +158,7 @@ func TestAlertingTicker(t *testing.T) {
 	}
 
 	notifier := &schedule.AlertsSenderMock{}
-	notifier.EXPECT().Send(mock.Anything, mock.Anything).Return()
+	notifier.EXPECT().Send(mock.Anything, mock.Anything, mock.Anything).Return()
 
 	schedCfg := schedule.SchedulerCfg{
 		Cfg: cfg,
@@ -171,6 +173,7 @@ func TestAlertingTicker(t *testing.T) {
 		Logger:      log.New("ngalert schedule test"),
 		Metrics:     testMetrics.GetSchedulerMetrics(),
 		AlertSender: notifier,
+		Tracer:      tracing.InitializeTracerForTest(),
 	}
 	st := state.NewManager(schedCfg.Logger, testMetrics.GetStateMetrics(), nil, dbstore, dbstore, &image.NoopImageService{}, clock.NewMock(), &state.FakeHistorian{})
 	appUrl := &url.URL{
@@ -9,9 +9,12 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 
 	"github.com/grafana/grafana/pkg/api/datasource"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -45,11 +48,12 @@ type AlertsRouter struct {
 
 	datasourceService datasources.DataSourceService
 	secretService     secrets.Service
+	tracer            tracing.Tracer
 }
 
 func NewAlertsRouter(multiOrgNotifier *notifier.MultiOrgAlertmanager, store store.AdminConfigurationStore,
 	clk clock.Clock, appURL *url.URL, disabledOrgs map[int64]struct{}, configPollInterval time.Duration,
-	datasourceService datasources.DataSourceService, secretService secrets.Service) *AlertsRouter {
+	datasourceService datasources.DataSourceService, secretService secrets.Service, tracer tracing.Tracer) *AlertsRouter {
 	d := &AlertsRouter{
 		logger:           log.New("alerts-router"),
 		clock:            clk,
@@ -68,6 +72,7 @@ func NewAlertsRouter(multiOrgNotifier *notifier.MultiOrgAlertmanager, store stor
 
 		datasourceService: datasourceService,
 		secretService:     secretService,
+		tracer:            tracer,
 	}
 	return d
 }
@@ -254,12 +259,18 @@ func (d *AlertsRouter) buildExternalURL(ds *datasources.DataSource) (string, err
 		password, parsed.Host, parsed.Path, parsed.RawQuery), nil
 }
 
-func (d *AlertsRouter) Send(key models.AlertRuleKey, alerts definitions.PostableAlerts) {
+func (d *AlertsRouter) Send(ctx context.Context, key models.AlertRuleKey, alerts definitions.PostableAlerts) {
 	logger := d.logger.New("rule_uid", key.UID, "org", key.OrgID)
 	if len(alerts.PostableAlerts) == 0 {
 		logger.Debug("no alerts to notify about")
 		return
 	}
+
+	_, span := d.tracer.Start(ctx, "alerts router send")
+	defer span.End()
+	span.SetAttributes("rule_uid", key.UID, attribute.String("rule_uid", key.UID))
+	span.SetAttributes("org_id", key.OrgID, attribute.Int64("org_id", key.OrgID))
+	span.SetAttributes("alerts", len(alerts.PostableAlerts), attribute.Int("alerts", len(alerts.PostableAlerts)))
 	// Send alerts to local notifier if they need to be handled internally
 	// or if no external AMs have been discovered yet.
 	var localNotifierExist, externalNotifierExist bool
@@ -272,6 +283,7 @@ func (d *AlertsRouter) Send(key models.AlertRuleKey, alerts definitions.Postable
 			localNotifierExist = true
 			if err := n.PutAlerts(alerts); err != nil {
 				logger.Error("failed to put alerts in the local notifier", "count", len(alerts.PostableAlerts), "err", err)
+				span.RecordError(err)
 			}
 		} else {
 			if errors.Is(err, notifier.ErrNoAlertmanagerForOrg) {
@@ -293,8 +305,12 @@ func (d *AlertsRouter) Send(key models.AlertRuleKey, alerts definitions.Postable
 		externalNotifierExist = true
 	}
 
+	span.SetAttributes("local_notifier", localNotifierExist, attribute.Bool("local_notifier", localNotifierExist))
+	span.SetAttributes("external_notifier", externalNotifierExist, attribute.Bool("external_notifier", externalNotifierExist))
+
 	if !localNotifierExist && !externalNotifierExist {
 		logger.Error("no external or internal notifier - [%d] alerts not delivered", len(alerts.PostableAlerts))
+		span.SetStatus(codes.Error, "alerts not delivered")
 	}
 }
 
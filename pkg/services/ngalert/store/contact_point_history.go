@@ -0,0 +1,177 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	grafana_models "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// GetContactPointHistory returns the changes to a contact point, most recent first.
+func (st *DBstore) GetContactPointHistory(ctx context.Context, orgID int64, uid string) ([]models.ContactPointHistory, error) {
+	history := make([]models.ContactPointHistory, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("org_id = ? AND uid = ?", orgID, uid).Desc("id").Find(&history)
+	})
+	return history, err
+}
+
+// recordContactPointChanges records the changes to the contact points of the
+// org between its latest configuration and the one being saved.
+func (st DBstore) recordContactPointChanges(ctx context.Context, sess *sqlstore.DBSession, orgID int64, config string) error {
+	var previous models.AlertConfiguration
+	has, err := sess.Table("alert_configuration").Where("org_id = ?", orgID).Desc("id").Limit(1).Get(&previous)
+	if err != nil {
+		return err
+	}
+	// the first configuration of an org is the default one
+	if !has {
+		return nil
+	}
+
+	history, err := contactPointChanges(previous.AlertmanagerConfiguration, config)
+	if err != nil {
+		st.Logger.Warn("failed to compare contact points of am configs", "org", orgID, "err", err)
+		return nil
+	}
+	if len(history) == 0 {
+		return nil
+	}
+
+	userID := userIDFromContext(ctx)
+	now := time.Now()
+	for i := range history {
+		history[i].OrgID = orgID
+		history[i].UserID = userID
+		history[i].Created = now
+	}
+	_, err = sess.Insert(&history)
+	return err
+}
+
+// contactPointChanges returns the changes to the Grafana managed contact
+// points between two alertmanager configurations.
+func contactPointChanges(previous, current string) ([]models.ContactPointHistory, error) {
+	previousReceivers, err := grafanaReceivers(previous)
+	if err != nil {
+		return nil, err
+	}
+	currentReceivers, err := grafanaReceivers(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []models.ContactPointHistory
+	for uid, receiver := range currentReceivers {
+		previousReceiver, existed := previousReceivers[uid]
+		action := models.ContactPointUpdated
+		if !existed {
+			action = models.ContactPointCreated
+		}
+		if h := contactPointChange(action, previousReceiver, receiver); action == models.ContactPointCreated || len(h.Changes) > 0 || len(h.SecureSettingsChanged) > 0 {
+			history = append(history, h)
+		}
+	}
+	for uid, receiver := range previousReceivers {
+		if _, exists := currentReceivers[uid]; !exists {
+			history = append(history, contactPointChange(models.ContactPointDeleted, receiver, nil))
+		}
+	}
+
+	sort.Slice(history, func(i, j int) bool { return history[i].UID < history[j].UID })
+	return history, nil
+}
+
+// contactPointChange compares the settings of a contact point before and after
+// its change, either of which is nil when it was created or deleted.
+func contactPointChange(action models.ContactPointHistoryAction, previous, current *definitions.PostableGrafanaReceiver) models.ContactPointHistory {
+	h := models.ContactPointHistory{
+		Action:                action,
+		Changes:               map[string]models.ContactPointSettingChange{},
+		SecureSettingsChanged: []string{},
+	}
+
+	var previousFields, currentFields map[string]interface{}
+	var previousSecure, currentSecure map[string]string
+	if previous != nil {
+		h.UID, h.Name, h.Type = previous.UID, previous.Name, previous.Type
+		previousFields, previousSecure = receiverFields(previous), previous.SecureSettings
+	}
+	if current != nil {
+		h.UID, h.Name, h.Type = current.UID, current.Name, current.Type
+		currentFields, currentSecure = receiverFields(current), current.SecureSettings
+	}
+
+	for name, value := range currentFields {
+		if previousValue, ok := previousFields[name]; !ok || !reflect.DeepEqual(previousValue, value) {
+			h.Changes[name] = models.ContactPointSettingChange{Old: previousValue, New: value}
+		}
+	}
+	for name, value := range previousFields {
+		if _, ok := currentFields[name]; !ok {
+			h.Changes[name] = models.ContactPointSettingChange{Old: value}
+		}
+	}
+
+	for name, value := range currentSecure {
+		if previousValue, ok := previousSecure[name]; !ok || previousValue != value {
+			h.SecureSettingsChanged = append(h.SecureSettingsChanged, name)
+		}
+	}
+	for name := range previousSecure {
+		if _, ok := currentSecure[name]; !ok {
+			h.SecureSettingsChanged = append(h.SecureSettingsChanged, name)
+		}
+	}
+	sort.Strings(h.SecureSettingsChanged)
+
+	return h
+}
+
+// receiverFields returns the fields of a contact point that aren't secure,
+// with its settings prefixed by "settings."
+func receiverFields(receiver *definitions.PostableGrafanaReceiver) map[string]interface{} {
+	fields := map[string]interface{}{
+		"name":                  receiver.Name,
+		"type":                  receiver.Type,
+		"disableResolveMessage": receiver.DisableResolveMessage,
+		"shadowMode":            receiver.ShadowMode,
+	}
+	if receiver.Settings != nil {
+		settings, _ := receiver.Settings.Map()
+		for name, value := range settings {
+			fields["settings."+name] = value
+		}
+	}
+	return fields
+}
+
+func grafanaReceivers(config string) (map[string]*definitions.PostableGrafanaReceiver, error) {
+	var cfg definitions.PostableUserConfig
+	if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+		return nil, err
+	}
+
+	receivers := make(map[string]*definitions.PostableGrafanaReceiver)
+	for _, receiver := range cfg.AlertmanagerConfig.Receivers {
+		for _, r := range receiver.GrafanaManagedReceivers {
+			receivers[r.UID] = r
+		}
+	}
+	return receivers, nil
+}
+
+func userIDFromContext(ctx context.Context) int64 {
+	c, ok := ctxkey.Get(ctx).(*grafana_models.ReqContext)
+	if !ok || c == nil || c.SignedInUser == nil {
+		return 0
+	}
+	return c.UserID
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+const contactPointHistoryConfig = `{
+	"alertmanager_config": {
+		"route": {"receiver": "airflow"},
+		"receivers": [{
+			"name": "airflow",
+			"grafana_managed_receiver_configs": [%s]
+		}]
+	}
+}`
+
+func receiversConfig(receivers string) string {
+	return fmt.Sprintf(contactPointHistoryConfig, receivers)
+}
+
+func TestContactPointChanges(t *testing.T) {
+	webhook := `{"uid": "webhook", "name": "airflow", "type": "webhook", "settings": {"url": "http://airflow/api/v1/dags/remediate/dagRuns"}, "secureSettings": {"password": "c2VjcmV0"}}`
+	updatedWebhook := `{"uid": "webhook", "name": "airflow", "type": "webhook", "settings": {"url": "http://airflow/api/v1/dags/restart/dagRuns"}, "secureSettings": {"password": "b3RoZXI="}}`
+	email := `{"uid": "email", "name": "airflow", "type": "email", "settings": {"addresses": "oncall@example.com"}}`
+
+	t.Run("records created contact points", func(t *testing.T) {
+		history, err := contactPointChanges(receiversConfig(email), receiversConfig(email+","+webhook))
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.Equal(t, models.ContactPointCreated, history[0].Action)
+		require.Equal(t, "webhook", history[0].UID)
+		require.Equal(t, models.ContactPointSettingChange{New: "http://airflow/api/v1/dags/remediate/dagRuns"}, history[0].Changes["settings.url"])
+		require.Equal(t, []string{"password"}, history[0].SecureSettingsChanged)
+	})
+
+	t.Run("records the settings changed in updated contact points", func(t *testing.T) {
+		history, err := contactPointChanges(receiversConfig(email+","+webhook), receiversConfig(email+","+updatedWebhook))
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.Equal(t, models.ContactPointUpdated, history[0].Action)
+		require.Equal(t, map[string]models.ContactPointSettingChange{
+			"settings.url": {Old: "http://airflow/api/v1/dags/remediate/dagRuns", New: "http://airflow/api/v1/dags/restart/dagRuns"},
+		}, history[0].Changes)
+		require.Equal(t, []string{"password"}, history[0].SecureSettingsChanged)
+	})
+
+	t.Run("records deleted contact points", func(t *testing.T) {
+		history, err := contactPointChanges(receiversConfig(email+","+webhook), receiversConfig(email))
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.Equal(t, models.ContactPointDeleted, history[0].Action)
+		require.Equal(t, "webhook", history[0].UID)
+		require.Equal(t, models.ContactPointSettingChange{Old: "webhook"}, history[0].Changes["type"])
+	})
+
+	t.Run("records nothing when contact points are unchanged", func(t *testing.T) {
+		history, err := contactPointChanges(receiversConfig(email+","+webhook), receiversConfig(webhook+","+email))
+		require.NoError(t, err)
+		require.Empty(t, history)
+	})
+}
+
+func TestIntegrationContactPointHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := sqlstore.InitTestDB(t)
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Logger:   log.NewNopLogger(),
+	}
+
+	webhook := `{"uid": "webhook", "name": "airflow", "type": "webhook", "settings": {"url": "http://airflow"}}`
+	updatedWebhook := `{"uid": "webhook", "name": "airflow", "type": "webhook", "settings": {"url": "http://airflow:8080"}}`
+
+	save := func(t *testing.T, config string) {
+		t.Helper()
+		err := store.SaveAlertmanagerConfiguration(context.Background(), &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: config,
+			ConfigurationVersion:      "v1",
+			OrgID:                     1,
+		})
+		require.NoError(t, err)
+	}
+
+	save(t, receiversConfig(""))
+	save(t, receiversConfig(webhook))
+	save(t, receiversConfig(updatedWebhook))
+	save(t, receiversConfig(""))
+
+	history, err := store.GetContactPointHistory(context.Background(), 1, "webhook")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, models.ContactPointDeleted, history[0].Action)
+	require.Equal(t, models.ContactPointUpdated, history[1].Action)
+	require.Equal(t, models.ContactPointSettingChange{Old: "http://airflow", New: "http://airflow:8080"}, history[1].Changes["settings.url"])
+	require.Equal(t, models.ContactPointCreated, history[2].Action)
+	for _, h := range history {
+		require.Equal(t, int64(1), h.OrgID)
+		require.Equal(t, "airflow", h.Name)
+	}
+
+	history, err = store.GetContactPointHistory(context.Background(), 2, "webhook")
+	require.NoError(t, err)
+	require.Empty(t, history)
+}
@@ -0,0 +1,17 @@
+package store
+
+import (
+	"context"
+
+	grafana_models "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// GetLegacyNotificationChannels returns the notification channels of the legacy alerting of the org, by name.
+func (st *DBstore) GetLegacyNotificationChannels(ctx context.Context, orgID int64) ([]*grafana_models.AlertNotification, error) {
+	channels := make([]*grafana_models.AlertNotification, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Table("alert_notification").Where("org_id = ?", orgID).Asc("name").Find(&channels)
+	})
+	return channels, err
+}
@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/harness"
+)
+
+// TestIntegrationAlertNotificationFlow evaluates a rule against a fake data
+// source and sends the firing instances through the contact points to the
+// fake receivers.
+func TestIntegrationAlertNotificationFlow(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	fds := harness.NewFakeDataSources()
+	fds.SetFrames("ds1", "A", data.NewFrame("",
+		data.NewField("host", nil, []string{"web-1", "web-2"}),
+		data.NewField("cpu", nil, []float64{95, 20}),
+	))
+
+	cfg := &setting.Cfg{ExpressionsEnabled: true}
+	cacheService := &fakeDatasources.FakeCacheService{DataSources: []*datasources.DataSource{
+		{Id: 1, Uid: "ds1", OrgId: 1, Type: "testdata", JsonData: simplejson.New()},
+	}}
+	evaluator := eval.NewEvaluator(cfg, log.New("test"), cacheService, expr.ProvideService(cfg, fds, &fakeDatasources.FakeDataSourceService{}))
+
+	condition := models.Condition{
+		Condition: "B",
+		Data: []models.AlertQuery{
+			{
+				RefID:             "A",
+				DatasourceUID:     "ds1",
+				RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(5 * time.Minute)},
+				Model:             json.RawMessage(`{"refId":"A"}`),
+			},
+			{
+				RefID:         "B",
+				DatasourceUID: expr.DatasourceUID,
+				Model:         json.RawMessage(`{"refId":"B","type":"math","expression":"$A > 80"}`),
+			},
+		},
+	}
+
+	now := time.Now()
+	results := evaluator.ConditionEval(context.Background(), &user.SignedInUser{OrgID: 1}, condition, now)
+	require.Len(t, fds.Requests(), 1)
+
+	var alerts []*types.Alert
+	for _, result := range results {
+		require.NoError(t, result.Error)
+		if result.State != eval.Alerting {
+			continue
+		}
+		labels := model.LabelSet{"alertname": "HighCPU"}
+		for k, v := range result.Instance {
+			labels[model.LabelName(k)] = model.LabelValue(v)
+		}
+		alerts = append(alerts, &types.Alert{Alert: model.Alert{
+			Labels:      labels,
+			Annotations: model.LabelSet{"summary": "CPU usage is high"},
+			StartsAt:    now,
+		}})
+	}
+	require.Len(t, alerts, 1)
+
+	ns := newNotificationService(t)
+	tmpl := newNotificationTemplate(t)
+
+	t.Run("webhook contact point triggers an airflow dag", func(t *testing.T) {
+		airflow := harness.NewAirflowReceiver(t)
+		notifier := newNotifier(t, channels.WebHookFactory, "webhook", map[string]interface{}{
+			"url": airflow.URL() + "/api/v1/dags/remediation/dagRuns",
+		}, ns, tmpl)
+
+		ok, err := notifier.Notify(notificationContext(), alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		requests := airflow.WaitForRequests(t, 1, time.Second)
+		require.Equal(t, "/api/v1/dags/remediation/dagRuns", requests[0].Path)
+
+		var body struct {
+			Status string `json:"status"`
+			Alerts []struct {
+				Labels map[string]string `json:"labels"`
+			} `json:"alerts"`
+		}
+		require.NoError(t, requests[0].JSON(&body))
+		require.Equal(t, "firing", body.Status)
+		require.Len(t, body.Alerts, 1)
+		require.Equal(t, "web-1", body.Alerts[0].Labels["host"])
+	})
+
+	t.Run("slack contact point posts the message", func(t *testing.T) {
+		slack := harness.NewSlackReceiver(t)
+		notifier := newNotifier(t, channels.SlackFactory, "slack", map[string]interface{}{
+			"url": slack.URL(),
+		}, ns, tmpl)
+
+		ok, err := notifier.Notify(notificationContext(), alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		requests := slack.WaitForRequests(t, 1, time.Second)
+		var body struct {
+			Attachments []struct {
+				Title string `json:"title"`
+				Text  string `json:"text"`
+			} `json:"attachments"`
+		}
+		require.NoError(t, requests[0].JSON(&body))
+		require.Len(t, body.Attachments, 1)
+		require.Contains(t, body.Attachments[0].Title, "[FIRING:1]")
+		require.Contains(t, body.Attachments[0].Text, "host = web-1")
+	})
+
+	t.Run("failing receiver fails the notification", func(t *testing.T) {
+		slack := harness.NewSlackReceiver(t)
+		slack.FailWith(http.StatusBadRequest)
+		notifier := newNotifier(t, channels.SlackFactory, "slack", map[string]interface{}{
+			"url": slack.URL(),
+		}, ns, tmpl)
+
+		_, err := notifier.Notify(notificationContext(), alerts...)
+		require.Error(t, err)
+		require.Len(t, slack.Requests(), 1)
+	})
+}
+
+func newNotificationService(t *testing.T) *notifications.NotificationService {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.Smtp.FromAddress = "from@address.com"
+	ns, err := notifications.ProvideService(bus.ProvideBus(tracing.InitializeTracerForTest()), cfg, notifications.NewFakeMailer(), nil)
+	require.NoError(t, err)
+	return ns
+}
+
+func newNotificationTemplate(t *testing.T) *template.Template {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "default.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(channels.DefaultTemplateString), 0600))
+	tmpl, err := template.FromGlobs(path)
+	require.NoError(t, err)
+	tmpl.ExternalURL, err = url.Parse("http://localhost:3000")
+	require.NoError(t, err)
+	return tmpl
+}
+
+func newNotifier(t *testing.T, factory func(channels.FactoryConfig) (channels.NotificationChannel, error), channelType string,
+	settings map[string]interface{}, ns notifications.Service, tmpl *template.Template) channels.NotificationChannel {
+	t.Helper()
+
+	fc, err := channels.NewFactoryConfig(&channels.NotificationChannelConfig{
+		UID:      fmt.Sprintf("%s-uid", channelType),
+		Name:     channelType,
+		Type:     channelType,
+		Settings: simplejson.NewFromAny(settings),
+	}, ns, func(_ context.Context, _ map[string][]byte, _ string, fallback string) string {
+		return fallback
+	}, tmpl, nil)
+	require.NoError(t, err)
+
+	notifier, err := factory(fc)
+	require.NoError(t, err)
+	return notifier
+}
+
+func notificationContext() context.Context {
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": "HighCPU"})
+	return notify.WithReceiverName(ctx, "test")
+}
@@ -11,6 +11,8 @@ type NotificationServiceMock struct {
 	EmailSync   models.SendEmailCommandSync
 	Email       models.SendEmailCommand
 	ShouldError error
+	// WebhookResponse is set as the result of the sent webhooks
+	WebhookResponse *models.WebhookResponse
 
 	WebhookHandler   func(context.Context, *models.SendWebhookSync) error
 	EmailHandlerSync func(context.Context, *models.SendEmailCommandSync) error
@@ -19,6 +21,7 @@ type NotificationServiceMock struct {
 
 func (ns *NotificationServiceMock) SendWebhookSync(ctx context.Context, cmd *models.SendWebhookSync) error {
 	ns.Webhook = *cmd
+	cmd.Result = ns.WebhookResponse
 	if ns.WebhookHandler != nil {
 		return ns.WebhookHandler(ctx, cmd)
 	}
@@ -119,7 +119,7 @@ func (ns *NotificationService) GetMailer() Mailer {
 }
 
 func (ns *NotificationService) SendWebhookSync(ctx context.Context, cmd *models.SendWebhookSync) error {
-	return ns.sendWebRequestSync(ctx, &Webhook{
+	webhook := &Webhook{
 		Url:         cmd.Url,
 		User:        cmd.User,
 		Password:    cmd.Password,
@@ -128,7 +128,13 @@ func (ns *NotificationService) SendWebhookSync(ctx context.Context, cmd *models.
 		HttpHeader:  cmd.HttpHeader,
 		ContentType: cmd.ContentType,
 		Validation:  cmd.Validation,
-	})
+		BodyReader:  cmd.BodyReader,
+		Multipart:   cmd.Multipart,
+		TLS:         cmd.TLS,
+	}
+	err := ns.sendWebRequestSync(ctx, webhook)
+	cmd.Result = webhook.Result
+	return err
 }
 
 func subjectTemplateFunc(obj map[string]interface{}, value string) string {
@@ -153,6 +159,10 @@ func (ns *NotificationService) SendEmailCommandHandlerSync(ctx context.Context,
 		return err
 	}
 
+	if recordShadowEmail(ctx, message) {
+		return nil
+	}
+
 	_, err = ns.Send(message)
 	return err
 }
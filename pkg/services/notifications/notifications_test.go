@@ -79,6 +79,31 @@ func TestSendEmailSync(t *testing.T) {
 		require.Len(t, mailer.Sent, 1)
 	})
 
+	t.Run("When sending emails with a shadow recorder", func(t *testing.T) {
+		ns, mailer := createSut(t, bus)
+		cmd := &models.SendEmailCommandSync{
+			SendEmailCommand: models.SendEmailCommand{
+				Subject:     "subject",
+				To:          []string{"asdf@grafana.com"},
+				SingleEmail: false,
+				Template:    "welcome_on_signup",
+			},
+		}
+		var recorded []ShadowDelivery
+		ctx := WithShadowRecorder(context.Background(), func(d ShadowDelivery) {
+			recorded = append(recorded, d)
+		})
+
+		err := ns.SendEmailCommandHandlerSync(ctx, cmd)
+		require.NoError(t, err)
+
+		require.Empty(t, mailer.Sent)
+		require.Len(t, recorded, 1)
+		require.Equal(t, "subject", recorded[0].Subject)
+		require.Equal(t, []string{"asdf@grafana.com"}, recorded[0].To)
+		require.NotEmpty(t, recorded[0].Body)
+	})
+
 	t.Run("When using Multi Email mode with multiple recipients", func(t *testing.T) {
 		ns, mailer := createSut(t, bus)
 		cmd := &models.SendEmailCommandSync{
@@ -0,0 +1,199 @@
+package notifications
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	outboundRequestCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "grafana",
+			Name:      "notifications_outbound_request_total",
+			Help:      "A counter for outgoing notification requests",
+		},
+		[]string{"code", "method"},
+	)
+
+	outboundRequestHistogram = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "grafana",
+			Name:      "notifications_outbound_request_duration_seconds",
+			Help:      "histogram of durations of outgoing notification requests sent from Grafana",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 25},
+		}, []string{"code", "method"},
+	)
+
+	outboundRequestsInFlight = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "grafana",
+			Name:      "notifications_outbound_request_in_flight",
+			Help:      "A gauge of outgoing notification requests currently being sent by Grafana",
+		},
+	)
+
+	outboundRequestRetries = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "grafana",
+			Name:      "notifications_outbound_request_retries_total",
+			Help:      "A counter for retried outgoing notification requests",
+		},
+	)
+)
+
+const (
+	outboundTimeout    = 30 * time.Second
+	outboundMaxRetries = 2
+)
+
+// outboundRetryBackoff is the wait before the first retry, doubled for every
+// following retry. Stubbable by tests.
+var outboundRetryBackoff = 500 * time.Millisecond
+
+// outboundTransport is shared by every client sending notifications, so that
+// they pool their connections to the same services
+var outboundTransport = &http.Transport{
+	TLSClientConfig: &tls.Config{
+		Renegotiation: tls.RenegotiateFreelyAsClient,
+	},
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	TLSHandshakeTimeout: 5 * time.Second,
+	MaxIdleConns:        100,
+	MaxIdleConnsPerHost: 10,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+var outboundRoundTripper = instrumentOutbound(outboundTransport)
+
+// NewOutboundClient returns the client that the webhook sender and notifier
+// channels send notifications with. Its requests are measured, traced and
+// retried when the service is temporarily unavailable.
+func NewOutboundClient() *http.Client {
+	return &http.Client{
+		Timeout:   outboundTimeout,
+		Transport: outboundRoundTripper,
+	}
+}
+
+// instrumentOutbound wraps the transport with the metrics, tracing and retries
+// of outgoing notification requests. Requests recorded by a ShadowRecorder are
+// never sent, so they aren't measured either.
+func instrumentOutbound(transport http.RoundTripper) http.RoundTripper {
+	return shadowRoundTripper{next: promhttp.InstrumentRoundTripperInFlight(outboundRequestsInFlight,
+		promhttp.InstrumentRoundTripperCounter(outboundRequestCounter,
+			promhttp.InstrumentRoundTripperDuration(outboundRequestHistogram,
+				tracingRoundTripper{next: retryingRoundTripper{next: transport}})))}
+}
+
+type tracingRoundTripper struct {
+	next http.RoundTripper
+}
+
+// RoundTrip traces the request as a child of the span of its context, if any,
+// and propagates the trace to the notified service
+func (rt tracingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := trace.SpanFromContext(req.Context()).TracerProvider().Tracer("notifications").
+		Start(req.Context(), "HTTP Outgoing Notification", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(attribute.String("http.method", req.Method), attribute.String("http.host", req.URL.Host))
+
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	res, err := rt.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return res, err
+	}
+
+	span.SetAttributes(attribute.Int("http.status_code", res.StatusCode))
+	if res.StatusCode >= 400 {
+		span.SetStatus(codes.Error, fmt.Sprintf("error with HTTP status code %s", strconv.Itoa(res.StatusCode)))
+	}
+
+	return res, nil
+}
+
+type retryingRoundTripper struct {
+	next http.RoundTripper
+}
+
+// RoundTrip retries requests that the service didn't process, because it
+// couldn't be reached or was temporarily unavailable. Requests with streamed
+// bodies are never retried since their body can't be sent again.
+func (rt retryingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	backoff := outboundRetryBackoff
+	for attempt := 0; ; attempt++ {
+		res, err := rt.next.RoundTrip(req)
+		if attempt == outboundMaxRetries || !shouldRetryOutbound(res, err) || (req.Body != nil && req.GetBody == nil) {
+			return res, err
+		}
+
+		if res != nil {
+			_ = res.Body.Close()
+		}
+
+		if err := sleepWithContext(req.Context(), backoff); err != nil {
+			return nil, err
+		}
+		backoff *= 2
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		outboundRequestRetries.Inc()
+	}
+}
+
+// shouldRetryOutbound returns true when the service surely didn't process the
+// request, so that retrying it doesn't send the notification twice
+func shouldRetryOutbound(res *http.Response, err error) bool {
+	if err != nil {
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
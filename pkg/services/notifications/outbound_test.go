@@ -0,0 +1,120 @@
+package notifications
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboundClient(t *testing.T) {
+	originalBackoff := outboundRetryBackoff
+	outboundRetryBackoff = time.Millisecond
+	t.Cleanup(func() { outboundRetryBackoff = originalBackoff })
+
+	// newServer responds with the statuses in order, then with 200
+	newServer := func(t *testing.T, statuses ...int) (*httptest.Server, *[]string) {
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			if len(bodies) <= len(statuses) {
+				w.WriteHeader(statuses[len(bodies)-1])
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server, &bodies
+	}
+
+	t.Run("retries requests when the service is unavailable", func(t *testing.T) {
+		server, bodies := newServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+		retries := testutil.ToFloat64(outboundRequestRetries)
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("alert"))
+		require.NoError(t, err)
+		res, err := NewOutboundClient().Do(req)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, []string{"alert", "alert", "alert"}, *bodies)
+		assert.Equal(t, retries+2, testutil.ToFloat64(outboundRequestRetries))
+	})
+
+	t.Run("gives up after the maximum number of retries", func(t *testing.T) {
+		server, bodies := newServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("alert"))
+		require.NoError(t, err)
+		res, err := NewOutboundClient().Do(req)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		assert.Len(t, *bodies, outboundMaxRetries+1)
+	})
+
+	t.Run("doesn't retry requests the service may have processed", func(t *testing.T) {
+		server, bodies := newServer(t, http.StatusInternalServerError)
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("alert"))
+		require.NoError(t, err)
+		res, err := NewOutboundClient().Do(req)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		assert.Len(t, *bodies, 1)
+	})
+
+	t.Run("doesn't retry streamed requests", func(t *testing.T) {
+		server, bodies := newServer(t, http.StatusServiceUnavailable)
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, io.NopCloser(strings.NewReader("alert")))
+		require.NoError(t, err)
+		res, err := NewOutboundClient().Do(req)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		assert.Len(t, *bodies, 1)
+	})
+
+	t.Run("measures requests", func(t *testing.T) {
+		server, _ := newServer(t)
+		sent := testutil.ToFloat64(outboundRequestCounter.WithLabelValues("200", "post"))
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("alert"))
+		require.NoError(t, err)
+		res, err := NewOutboundClient().Do(req)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+
+		assert.Greater(t, testutil.ToFloat64(outboundRequestCounter.WithLabelValues("200", "post")), sent)
+	})
+
+	t.Run("records shadow requests instead of sending them", func(t *testing.T) {
+		server, bodies := newServer(t)
+		var recorded []ShadowDelivery
+		ctx := WithShadowRecorder(context.Background(), func(d ShadowDelivery) {
+			recorded = append(recorded, d)
+		})
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/token", strings.NewReader("alert"))
+		require.NoError(t, err)
+		res, err := NewOutboundClient().Do(req)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Empty(t, *bodies)
+		assert.Equal(t, []ShadowDelivery{{Method: http.MethodPost, Host: req.URL.Host, Body: "alert"}}, recorded)
+	})
+}
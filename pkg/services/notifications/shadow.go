@@ -0,0 +1,87 @@
+package notifications
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ShadowDelivery is a notification that was fully rendered but not sent,
+// because it was sent with a context carrying a ShadowRecorder.
+type ShadowDelivery struct {
+	// Method and Host of the request, for notifications sent over HTTP.
+	// The rest of the URL is omitted since it can hold secrets, such as tokens.
+	Method string `json:"method,omitempty"`
+	Host   string `json:"host,omitempty"`
+	// To and Subject of the email, for notifications sent by email.
+	To      []string `json:"to,omitempty"`
+	Subject string   `json:"subject,omitempty"`
+	Body    string   `json:"body"`
+}
+
+// ShadowRecorder records the notifications that would have been sent.
+type ShadowRecorder func(ShadowDelivery)
+
+type shadowRecorderKey struct{}
+
+// WithShadowRecorder returns a context with which the notifications are
+// recorded by the recorder instead of being sent.
+func WithShadowRecorder(ctx context.Context, rec ShadowRecorder) context.Context {
+	return context.WithValue(ctx, shadowRecorderKey{}, rec)
+}
+
+func shadowRecorderFromContext(ctx context.Context) (ShadowRecorder, bool) {
+	rec, ok := ctx.Value(shadowRecorderKey{}).(ShadowRecorder)
+	return rec, ok && rec != nil
+}
+
+type shadowRoundTripper struct {
+	next http.RoundTripper
+}
+
+// RoundTrip records the request instead of sending it when its context has a
+// ShadowRecorder, responding as if the service accepted it.
+func (rt shadowRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec, ok := shadowRecorderFromContext(req.Context())
+	if !ok {
+		return rt.next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	rec(ShadowDelivery{Method: req.Method, Host: req.URL.Host, Body: string(body)})
+
+	return &http.Response{
+		Status:     http.StatusText(http.StatusOK),
+		StatusCode: http.StatusOK,
+		Proto:      req.Proto,
+		ProtoMajor: req.ProtoMajor,
+		ProtoMinor: req.ProtoMinor,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+// recordShadowEmail records the email instead of sending it when the context
+// has a ShadowRecorder.
+func recordShadowEmail(ctx context.Context, msg *Message) bool {
+	rec, ok := shadowRecorderFromContext(ctx)
+	if !ok {
+		return false
+	}
+	body, ok := msg.Body["text/plain"]
+	if !ok {
+		body = msg.Body["text/html"]
+	}
+	rec(ShadowDelivery{To: msg.To, Subject: msg.Subject, Body: body})
+	return true
+}
@@ -1,15 +1,17 @@
 package notifications
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
 	"io"
-	"net"
+	"mime/multipart"
 	"net/http"
-	"time"
+	"strings"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+
+	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -25,6 +27,48 @@ type Webhook struct {
 	// Validation is a function that will validate the response body and statusCode of the webhook. Any returned error will cause the webhook request to be considered failed.
 	// This can be useful when a webhook service communicates failures in creative ways, such as using the response body instead of the status code.
 	Validation func(body []byte, statusCode int) error
+
+	// BodyReader streams the request body instead of Body, it is closed once sent, or once the webhook fails, if it is an io.Closer.
+	BodyReader io.Reader
+	// Multipart writes a multipart/form-data request body while it is sent, instead of Body and BodyReader,
+	// so that large files like alert images are never buffered. It must not change the boundary of the writer.
+	Multipart func(w *multipart.Writer) error
+
+	// TLS configures the TLS connection to the webhook, the default configuration is used when nil.
+	TLS *httpclient.TLSOptions
+
+	// Result is set to the response of the webhook once received.
+	Result *models.WebhookResponse
+}
+
+// closeBodyReader closes the body reader when the webhook fails before it is
+// sent, as it would have been once sent
+func (webhook *Webhook) closeBodyReader() {
+	if closer, ok := webhook.BodyReader.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+// requestBody returns the body of the webhook request along with its content type
+func (webhook *Webhook) requestBody() (io.Reader, string) {
+	switch {
+	case webhook.Multipart != nil:
+		pr, pw := io.Pipe()
+		w := multipart.NewWriter(pw)
+		go func() {
+			err := webhook.Multipart(w)
+			if err == nil {
+				err = w.Close()
+			}
+			// the error is returned to the client reading the body, which fails the request
+			_ = pw.CloseWithError(err)
+		}()
+		return pr, w.FormDataContentType()
+	case webhook.BodyReader != nil:
+		return webhook.BodyReader, webhook.ContentType
+	default:
+		return strings.NewReader(webhook.Body), webhook.ContentType
+	}
 }
 
 // WebhookClient exists to mock the client in tests.
@@ -32,19 +76,30 @@ type WebhookClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
-var netTransport = &http.Transport{
-	TLSClientConfig: &tls.Config{
-		Renegotiation: tls.RenegotiateFreelyAsClient,
-	},
-	Proxy: http.ProxyFromEnvironment,
-	Dial: (&net.Dialer{
-		Timeout: 30 * time.Second,
-	}).Dial,
-	TLSHandshakeTimeout: 5 * time.Second,
-}
-var netClient WebhookClient = &http.Client{
-	Timeout:   time.Second * 30,
-	Transport: netTransport,
+var netClient WebhookClient = NewOutboundClient()
+
+// webhookClient returns the client sending the webhook, with its own transport when it has a TLS configuration
+func webhookClient(webhook *Webhook) (WebhookClient, func(), error) {
+	client, ok := netClient.(*http.Client)
+	// clients mocked in tests are used as is
+	if webhook.TLS == nil || !ok {
+		return netClient, func() {}, nil
+	}
+
+	tlsConfig, err := httpclient.GetTLSConfig(httpclient.Options{TLS: webhook.TLS})
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid webhook TLS configuration: %w", err)
+	}
+	tlsConfig.Renegotiation = tls.RenegotiateFreelyAsClient
+
+	transport := outboundTransport.Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{
+		Timeout:       client.Timeout,
+		Transport:     instrumentOutbound(transport),
+		CheckRedirect: client.CheckRedirect,
+	}, transport.CloseIdleConnections, nil
 }
 
 func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *Webhook) error {
@@ -55,19 +110,32 @@ func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *
 	ns.log.Debug("Sending webhook", "url", webhook.Url, "http method", webhook.HttpMethod)
 
 	if webhook.HttpMethod != http.MethodPost && webhook.HttpMethod != http.MethodPut {
+		webhook.closeBodyReader()
 		return fmt.Errorf("webhook only supports HTTP methods PUT or POST")
 	}
 
-	request, err := http.NewRequestWithContext(ctx, webhook.HttpMethod, webhook.Url, bytes.NewReader([]byte(webhook.Body)))
+	client, closeClient, err := webhookClient(webhook)
 	if err != nil {
+		webhook.closeBodyReader()
 		return err
 	}
+	defer closeClient()
 
-	if webhook.ContentType == "" {
-		webhook.ContentType = "application/json"
+	reqBody, contentType := webhook.requestBody()
+	request, err := http.NewRequestWithContext(ctx, webhook.HttpMethod, webhook.Url, reqBody)
+	if err != nil {
+		// the client closes the body once sent, which also stops writing multipart bodies
+		if closer, ok := reqBody.(io.Closer); ok {
+			_ = closer.Close()
+		}
+		return err
+	}
+
+	if contentType == "" {
+		contentType = "application/json"
 	}
 
-	request.Header.Set("Content-Type", webhook.ContentType)
+	request.Header.Set("Content-Type", contentType)
 	request.Header.Set("User-Agent", "Grafana")
 
 	if webhook.User != "" && webhook.Password != "" {
@@ -78,7 +146,7 @@ func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *
 		request.Header.Set(k, v)
 	}
 
-	resp, err := netClient.Do(request)
+	resp, err := client.Do(request)
 	if err != nil {
 		return err
 	}
@@ -88,11 +156,25 @@ func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *
 		}
 	}()
 
-	body, err := io.ReadAll(resp.Body)
+	// the body is read up to one byte past the limit, so that misbehaving
+	// webhooks can't make Grafana buffer large responses, and validations
+	// get the truncated body too
+	body, err := io.ReadAll(io.LimitReader(resp.Body, models.WebhookResponseBodyLimit+1))
 	if err != nil {
 		return err
 	}
 
+	truncated := len(body) > models.WebhookResponseBodyLimit
+	if truncated {
+		body = body[:models.WebhookResponseBodyLimit]
+	}
+	webhook.Result = &models.WebhookResponse{
+		StatusCode:    resp.StatusCode,
+		Header:        resp.Header,
+		Body:          body,
+		BodyTruncated: truncated,
+	}
+
 	if webhook.Validation != nil {
 		err := webhook.Validation(body, resp.StatusCode)
 		if err != nil {
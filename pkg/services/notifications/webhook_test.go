@@ -0,0 +1,227 @@
+package notifications
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func TestSendWebhookSync(t *testing.T) {
+	ns := &NotificationService{log: log.New("notifications.test")}
+
+	t.Run("sends the body", func(t *testing.T) {
+		var contentType, body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentType = r.Header.Get("Content-Type")
+			b, _ := io.ReadAll(r.Body)
+			body = string(b)
+		}))
+		defer server.Close()
+
+		err := ns.SendWebhookSync(context.Background(), &models.SendWebhookSync{Url: server.URL, Body: `{"title":"alert"}`})
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", contentType)
+		assert.Equal(t, `{"title":"alert"}`, body)
+	})
+
+	t.Run("streams the body reader", func(t *testing.T) {
+		var contentType, body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentType = r.Header.Get("Content-Type")
+			b, _ := io.ReadAll(r.Body)
+			body = string(b)
+		}))
+		defer server.Close()
+
+		err := ns.SendWebhookSync(context.Background(), &models.SendWebhookSync{
+			Url:         server.URL,
+			BodyReader:  strings.NewReader("large payload"),
+			ContentType: "text/plain",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain", contentType)
+		assert.Equal(t, "large payload", body)
+	})
+
+	t.Run("closes the body reader when the webhook can't be sent", func(t *testing.T) {
+		body := &closeRecorder{Reader: strings.NewReader("large payload")}
+		err := ns.SendWebhookSync(context.Background(), &models.SendWebhookSync{
+			Url:        "https://example.com",
+			BodyReader: body,
+			TLS:        &httpclient.TLSOptions{CACertificate: "not a certificate"},
+		})
+		require.Error(t, err)
+		assert.True(t, body.closed)
+
+		body = &closeRecorder{Reader: strings.NewReader("large payload")}
+		err = ns.SendWebhookSync(context.Background(), &models.SendWebhookSync{
+			Url:        "https://example.com",
+			BodyReader: body,
+			HttpMethod: http.MethodGet,
+		})
+		require.Error(t, err)
+		assert.True(t, body.closed)
+	})
+
+	t.Run("sends multipart forms", func(t *testing.T) {
+		var message, filename, image string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			message = r.FormValue("message")
+			f, header, err := r.FormFile("image")
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			defer func() { _ = f.Close() }()
+			b, _ := io.ReadAll(f)
+			filename, image = header.Filename, string(b)
+		}))
+		defer server.Close()
+
+		err := ns.SendWebhookSync(context.Background(), &models.SendWebhookSync{
+			Url: server.URL,
+			Multipart: func(w *multipart.Writer) error {
+				if err := w.WriteField("message", "alert firing"); err != nil {
+					return err
+				}
+				part, err := w.CreateFormFile("image", "alert.png")
+				if err != nil {
+					return err
+				}
+				_, err = part.Write([]byte("image data"))
+				return err
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "alert firing", message)
+		assert.Equal(t, "alert.png", filename)
+		assert.Equal(t, "image data", image)
+	})
+
+	t.Run("fails when the multipart form can't be written", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.ReadAll(r.Body)
+		}))
+		defer server.Close()
+
+		err := ns.SendWebhookSync(context.Background(), &models.SendWebhookSync{
+			Url: server.URL,
+			Multipart: func(w *multipart.Writer) error {
+				return errors.New("image not found")
+			},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "image not found")
+	})
+}
+
+func TestSendWebhookSyncResult(t *testing.T) {
+	ns := &NotificationService{log: log.New("notifications.test")}
+
+	t.Run("returns the response of failed webhooks", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Request-Id", "abc")
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"detail":"DAG run already exists"}`))
+		}))
+		defer server.Close()
+
+		cmd := &models.SendWebhookSync{Url: server.URL}
+		err := ns.SendWebhookSync(context.Background(), cmd)
+		require.Error(t, err)
+		require.NotNil(t, cmd.Result)
+		assert.Equal(t, http.StatusConflict, cmd.Result.StatusCode)
+		assert.Equal(t, "abc", cmd.Result.Header.Get("X-Request-Id"))
+		assert.Equal(t, `{"detail":"DAG run already exists"}`, string(cmd.Result.Body))
+		assert.False(t, cmd.Result.BodyTruncated)
+	})
+
+	t.Run("truncates large response bodies", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(strings.Repeat("a", 10*models.WebhookResponseBodyLimit)))
+		}))
+		defer server.Close()
+
+		validated := 0
+		cmd := &models.SendWebhookSync{Url: server.URL, Validation: func(body []byte, _ int) error {
+			validated = len(body)
+			return nil
+		}}
+		err := ns.SendWebhookSync(context.Background(), cmd)
+		require.NoError(t, err)
+		assert.Len(t, cmd.Result.Body, models.WebhookResponseBodyLimit)
+		assert.True(t, cmd.Result.BodyTruncated)
+		assert.Equal(t, models.WebhookResponseBodyLimit, validated)
+	})
+
+	t.Run("has no result when no response was received", func(t *testing.T) {
+		cmd := &models.SendWebhookSync{Url: "http://localhost:0"}
+		err := ns.SendWebhookSync(context.Background(), cmd)
+		require.Error(t, err)
+		assert.Nil(t, cmd.Result)
+	})
+}
+
+func TestSendWebhookSyncTLS(t *testing.T) {
+	ns := &NotificationService{log: log.New("notifications.test")}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	t.Run("fails for internally signed certificates by default", func(t *testing.T) {
+		err := ns.SendWebhookSync(context.Background(), &models.SendWebhookSync{Url: server.URL})
+		require.Error(t, err)
+	})
+
+	t.Run("trusts the custom CA", func(t *testing.T) {
+		err := ns.SendWebhookSync(context.Background(), &models.SendWebhookSync{
+			Url: server.URL,
+			TLS: &httpclient.TLSOptions{CACertificate: caCert},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("skips verification when insecure", func(t *testing.T) {
+		err := ns.SendWebhookSync(context.Background(), &models.SendWebhookSync{
+			Url: server.URL,
+			TLS: &httpclient.TLSOptions{InsecureSkipVerify: true},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("fails for invalid CA certificates", func(t *testing.T) {
+		err := ns.SendWebhookSync(context.Background(), &models.SendWebhookSync{
+			Url: server.URL,
+			TLS: &httpclient.TLSOptions{CACertificate: "not a certificate"},
+		})
+		require.ErrorContains(t, err, "invalid webhook TLS configuration")
+	})
+}
+
+// closeRecorder records whether the reader was closed
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
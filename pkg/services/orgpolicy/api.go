@@ -0,0 +1,118 @@
+package orgpolicy
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *OrgPolicyService) registerAPIEndpoints() {
+	authorize := ac.Middleware(s.AccessControl)
+
+	s.RouteRegister.Group("/api/org/policies", func(policies routing.RouteRegister) {
+		policies.Get("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(ac.ActionOrgsRead)), routing.Wrap(s.getPoliciesHandler))
+		policies.Put("/:kind", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(ac.ActionOrgsWrite)), routing.Wrap(s.setPolicyHandler))
+		policies.Delete("/:kind", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(ac.ActionOrgsWrite)), routing.Wrap(s.deletePolicyHandler))
+	}, middleware.ReqSignedIn)
+}
+
+// swagger:route GET /org/policies org_policies getOrgPolicies
+//
+// Get the policies of the current organization.
+//
+// Responses:
+// 200: getOrgPoliciesResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *OrgPolicyService) getPoliciesHandler(c *models.ReqContext) response.Response {
+	policies, err := s.GetPolicies(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get policies", err)
+	}
+
+	return response.JSON(http.StatusOK, policies)
+}
+
+// swagger:route PUT /org/policies/{kind} org_policies setOrgPolicy
+//
+// Create or replace a policy of the current organization.
+//
+// Responses:
+// 200: setOrgPolicyResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *OrgPolicyService) setPolicyHandler(c *models.ReqContext) response.Response {
+	cmd := SetPolicyCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.Kind = Kind(web.Params(c.Req)[":kind"])
+	cmd.OrgID = c.OrgID
+
+	policy, err := s.SetPolicy(c.Req.Context(), cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to set policy", err)
+	}
+
+	return response.JSON(http.StatusOK, policy)
+}
+
+// swagger:route DELETE /org/policies/{kind} org_policies deleteOrgPolicy
+//
+// Delete a policy of the current organization.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *OrgPolicyService) deletePolicyHandler(c *models.ReqContext) response.Response {
+	err := s.DeletePolicy(c.Req.Context(), DeletePolicyCommand{
+		Kind:  Kind(web.Params(c.Req)[":kind"]),
+		OrgID: c.OrgID,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete policy", err)
+	}
+
+	return response.Success("Policy deleted")
+}
+
+// swagger:parameters setOrgPolicy
+type SetOrgPolicyParams struct {
+	// in:path
+	// required:true
+	Kind string `json:"kind"`
+	// in:body
+	// required:true
+	Body SetPolicyCommand `json:"body"`
+}
+
+// swagger:parameters deleteOrgPolicy
+type DeleteOrgPolicyParams struct {
+	// in:path
+	// required:true
+	Kind string `json:"kind"`
+}
+
+//swagger:response getOrgPoliciesResponse
+type GetOrgPoliciesResponse struct {
+	// in: body
+	Body []Policy `json:"body"`
+}
+
+//swagger:response setOrgPolicyResponse
+type SetOrgPolicyResponse struct {
+	// in: body
+	Body Policy `json:"body"`
+}
@@ -0,0 +1,81 @@
+package orgpolicy
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+const policyTable = "org_policy"
+
+func (s *OrgPolicyService) getPolicies(ctx context.Context, orgID int64) ([]Policy, error) {
+	policies := make([]Policy, 0)
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		return session.Table(policyTable).Where("org_id = ?", orgID).Asc("kind").Find(&policies)
+	})
+	return policies, err
+}
+
+// getPolicy returns the policy of the kind, or nil if the organization has none.
+func (s *OrgPolicyService) getPolicy(ctx context.Context, orgID int64, kind Kind) (*Policy, error) {
+	var policy Policy
+	var found bool
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		var err error
+		found, err = session.Table(policyTable).Where("org_id = ? AND kind = ?", orgID, kind).Get(&policy)
+		return err
+	})
+	if err != nil || !found {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// setPolicy creates the policy of the kind, or replaces its values if it exists
+func (s *OrgPolicyService) setPolicy(ctx context.Context, cmd SetPolicyCommand) (Policy, error) {
+	values := cmd.Values
+	if values == nil {
+		values = []string{}
+	}
+
+	var policy Policy
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		found, err := session.Table(policyTable).Where("org_id = ? AND kind = ?", cmd.OrgID, cmd.Kind).Get(&policy)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		policy.Values = values
+		policy.Updated = now
+		if found {
+			_, err = session.Table(policyTable).ID(policy.ID).Cols("value", "updated").Update(&policy)
+			return err
+		}
+
+		policy.OrgID = cmd.OrgID
+		policy.Kind = cmd.Kind
+		policy.Created = now
+		_, err = session.Table(policyTable).Insert(&policy)
+		return err
+	})
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return policy, nil
+}
+
+func (s *OrgPolicyService) deletePolicy(ctx context.Context, cmd DeletePolicyCommand) error {
+	return s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		deletedCount, err := session.Table(policyTable).Where("org_id = ? AND kind = ?", cmd.OrgID, cmd.Kind).Delete(&Policy{})
+		if err != nil {
+			return err
+		}
+		if deletedCount == 0 {
+			return ErrPolicyNotFound.Errorf("policy %s not found", cmd.Kind)
+		}
+		return nil
+	})
+}
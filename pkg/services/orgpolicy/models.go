@@ -0,0 +1,118 @@
+package orgpolicy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrPolicyNotFound     = errutil.NewBase(errutil.StatusNotFound, "orgpolicy.notFound", errutil.WithPublicMessage("Policy not found"))
+	ErrInvalidPolicyKind  = errutil.NewBase(errutil.StatusBadRequest, "orgpolicy.invalidKind", errutil.WithPublicMessage("Invalid policy kind"))
+	ErrInvalidPolicyValue = errutil.NewBase(errutil.StatusBadRequest, "orgpolicy.invalidValue", errutil.WithPublicMessage("Invalid policy value"))
+	ErrPolicyViolation    = errutil.NewBase(errutil.StatusForbidden, "orgpolicy.violation").MustTemplate(
+		"{{ .Public.Reason }} (policy {{ .Public.Kind }})",
+		errutil.WithPublic("{{ .Public.Reason }}, as required by the organization policy {{ .Public.Kind }}"),
+	)
+)
+
+// Kind is the kind of constraint a policy declares. An organization has at
+// most one policy of each kind.
+type Kind string
+
+const (
+	// KindPublicDashboardsDeniedDatasourceTypes lists the types of the data
+	// sources that public dashboards may not query.
+	KindPublicDashboardsDeniedDatasourceTypes Kind = "publicdashboards.deniedDatasourceTypes"
+	// KindNotificationsAllowedWebhookHosts lists the hosts that webhook
+	// contact points may target. A host starting with "*." matches any of
+	// its subdomains. When the policy is set, any other host is denied.
+	KindNotificationsAllowedWebhookHosts Kind = "notifications.allowedWebhookHosts"
+	// KindCorrelationsDeniedTargetTags lists the tags of the data sources
+	// that correlations may not target. Data sources are tagged by the
+	// "tags" string array of their JSON data.
+	KindCorrelationsDeniedTargetTags Kind = "correlations.deniedTargetTags"
+)
+
+var kinds = map[Kind]struct{}{
+	KindPublicDashboardsDeniedDatasourceTypes: {},
+	KindNotificationsAllowedWebhookHosts:      {},
+	KindCorrelationsDeniedTargetTags:          {},
+}
+
+func (k Kind) Validate() error {
+	if _, ok := kinds[k]; !ok {
+		return ErrInvalidPolicyKind.Errorf("invalid policy kind: \"%s\"", k)
+	}
+	return nil
+}
+
+// Policy is a constraint declared by the admins of an organization
+// swagger:model OrgPolicy
+type Policy struct {
+	ID    int64 `json:"-" xorm:"pk autoincr 'id'"`
+	OrgID int64 `json:"-" xorm:"org_id"`
+	// Kind of constraint
+	// example: notifications.allowedWebhookHosts
+	Kind Kind `json:"kind" xorm:"kind"`
+	// Values the constraint applies to, their meaning depends on the kind
+	// example: ["*.internal.corp"]
+	Values  []string  `json:"values" xorm:"value"`
+	Created time.Time `json:"created" xorm:"created"`
+	Updated time.Time `json:"updated" xorm:"updated"`
+}
+
+// SetPolicyCommand is the command for creating or replacing the policy of a kind
+// swagger:model
+type SetPolicyCommand struct {
+	Kind  Kind  `json:"-"`
+	OrgID int64 `json:"-"`
+	// Values the constraint applies to, their meaning depends on the kind
+	// example: ["*.internal.corp"]
+	Values []string `json:"values"`
+}
+
+func (c *SetPolicyCommand) Validate() error {
+	if err := c.Kind.Validate(); err != nil {
+		return err
+	}
+	for i, v := range c.Values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v == "" {
+			return ErrInvalidPolicyValue.Errorf("policy %s has an empty value", c.Kind)
+		}
+		if c.Kind == KindNotificationsAllowedWebhookHosts && strings.Contains(strings.TrimPrefix(v, "*."), "*") {
+			return ErrInvalidPolicyValue.Errorf("invalid host %q: only a leading \"*.\" wildcard is supported", v)
+		}
+		c.Values[i] = v
+	}
+	return nil
+}
+
+// DeletePolicyCommand is the command for deleting the policy of a kind
+type DeletePolicyCommand struct {
+	Kind  Kind
+	OrgID int64
+}
+
+// violation returns the error reported when a change is denied by the policy.
+func violation(kind Kind, format string, args ...interface{}) error {
+	return ErrPolicyViolation.Build(errutil.TemplateData{
+		Public: map[string]interface{}{
+			"Kind":   kind,
+			"Reason": fmt.Sprintf(format, args...),
+		},
+	})
+}
+
+// matchesHost reports whether the host matches the pattern, where a pattern
+// starting with "*." matches any subdomain of the rest of the pattern.
+func matchesHost(pattern, host string) bool {
+	host = strings.ToLower(host)
+	if suffix := strings.TrimPrefix(pattern, "*"); suffix != pattern {
+		return strings.HasSuffix(host, suffix) && len(host) > len(suffix)
+	}
+	return host == pattern
+}
@@ -0,0 +1,39 @@
+package orgpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetPolicyCommandValidate(t *testing.T) {
+	t.Run("Normalizes the values", func(t *testing.T) {
+		cmd := SetPolicyCommand{Kind: KindNotificationsAllowedWebhookHosts, Values: []string{" *.Internal.Corp "}}
+		require.NoError(t, cmd.Validate())
+		require.Equal(t, []string{"*.internal.corp"}, cmd.Values)
+	})
+
+	t.Run("Fails for an unknown kind", func(t *testing.T) {
+		cmd := SetPolicyCommand{Kind: "dashboards.unknown"}
+		require.ErrorIs(t, cmd.Validate(), ErrInvalidPolicyKind)
+	})
+
+	t.Run("Fails for an empty value", func(t *testing.T) {
+		cmd := SetPolicyCommand{Kind: KindCorrelationsDeniedTargetTags, Values: []string{"sensitive", " "}}
+		require.ErrorIs(t, cmd.Validate(), ErrInvalidPolicyValue)
+	})
+
+	t.Run("Fails for a host with a wildcard that isn't leading", func(t *testing.T) {
+		cmd := SetPolicyCommand{Kind: KindNotificationsAllowedWebhookHosts, Values: []string{"hooks.*.corp"}}
+		require.ErrorIs(t, cmd.Validate(), ErrInvalidPolicyValue)
+	})
+}
+
+func TestMatchesHost(t *testing.T) {
+	require.True(t, matchesHost("hooks.internal.corp", "Hooks.internal.corp"))
+	require.False(t, matchesHost("hooks.internal.corp", "other.internal.corp"))
+	require.True(t, matchesHost("*.internal.corp", "hooks.internal.corp"))
+	require.True(t, matchesHost("*.internal.corp", "a.b.internal.corp"))
+	require.False(t, matchesHost("*.internal.corp", "internal.corp"))
+	require.False(t, matchesHost("*.internal.corp", "evilinternal.corp"))
+}
@@ -0,0 +1,129 @@
+package orgpolicy
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+func ProvideService(sqlStore *sqlstore.SQLStore, routeRegister routing.RouteRegister, ac accesscontrol.AccessControl) *OrgPolicyService {
+	s := &OrgPolicyService{
+		SQLStore:      sqlStore,
+		RouteRegister: routeRegister,
+		log:           log.New("orgpolicy"),
+		AccessControl: ac,
+	}
+
+	s.registerAPIEndpoints()
+
+	return s
+}
+
+// Service manages the policies of the organizations and checks changes
+// against them. The Check methods return an ErrPolicyViolation error when
+// the change is denied.
+type Service interface {
+	GetPolicies(ctx context.Context, orgID int64) ([]Policy, error)
+	SetPolicy(ctx context.Context, cmd SetPolicyCommand) (Policy, error)
+	DeletePolicy(ctx context.Context, cmd DeletePolicyCommand) error
+
+	// CheckPublicDashboardDatasource checks that public dashboards may query the data source.
+	CheckPublicDashboardDatasource(ctx context.Context, orgID int64, ds *datasources.DataSource) error
+	// CheckWebhookURL checks that webhook contact points may target the URL.
+	CheckWebhookURL(ctx context.Context, orgID int64, rawURL string) error
+	// CheckCorrelationTarget checks that correlations may target the data source.
+	CheckCorrelationTarget(ctx context.Context, orgID int64, ds *datasources.DataSource) error
+}
+
+type OrgPolicyService struct {
+	SQLStore      *sqlstore.SQLStore
+	RouteRegister routing.RouteRegister
+	log           log.Logger
+	AccessControl accesscontrol.AccessControl
+}
+
+var _ Service = (*OrgPolicyService)(nil)
+
+func (s *OrgPolicyService) GetPolicies(ctx context.Context, orgID int64) ([]Policy, error) {
+	return s.getPolicies(ctx, orgID)
+}
+
+func (s *OrgPolicyService) SetPolicy(ctx context.Context, cmd SetPolicyCommand) (Policy, error) {
+	if err := cmd.Validate(); err != nil {
+		return Policy{}, err
+	}
+	return s.setPolicy(ctx, cmd)
+}
+
+func (s *OrgPolicyService) DeletePolicy(ctx context.Context, cmd DeletePolicyCommand) error {
+	if err := cmd.Kind.Validate(); err != nil {
+		return err
+	}
+	return s.deletePolicy(ctx, cmd)
+}
+
+func (s *OrgPolicyService) CheckPublicDashboardDatasource(ctx context.Context, orgID int64, ds *datasources.DataSource) error {
+	policy, err := s.getPolicy(ctx, orgID, KindPublicDashboardsDeniedDatasourceTypes)
+	if err != nil || policy == nil {
+		return err
+	}
+	return checkPublicDashboardDatasource(policy, ds)
+}
+
+func (s *OrgPolicyService) CheckWebhookURL(ctx context.Context, orgID int64, rawURL string) error {
+	policy, err := s.getPolicy(ctx, orgID, KindNotificationsAllowedWebhookHosts)
+	if err != nil || policy == nil {
+		return err
+	}
+	return checkWebhookURL(policy, rawURL)
+}
+
+func (s *OrgPolicyService) CheckCorrelationTarget(ctx context.Context, orgID int64, ds *datasources.DataSource) error {
+	policy, err := s.getPolicy(ctx, orgID, KindCorrelationsDeniedTargetTags)
+	if err != nil || policy == nil {
+		return err
+	}
+	return checkCorrelationTarget(policy, ds)
+}
+
+func checkPublicDashboardDatasource(policy *Policy, ds *datasources.DataSource) error {
+	for _, t := range policy.Values {
+		if strings.EqualFold(t, ds.Type) {
+			return violation(policy.Kind, "Public dashboards may not query data sources of type %s, such as %s", ds.Type, ds.Name)
+		}
+	}
+	return nil
+}
+
+func checkWebhookURL(policy *Policy, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return violation(policy.Kind, "Webhooks may only target URLs with an allowed host")
+	}
+	for _, pattern := range policy.Values {
+		if matchesHost(pattern, u.Hostname()) {
+			return nil
+		}
+	}
+	return violation(policy.Kind, "Webhooks may not target the host %s", u.Hostname())
+}
+
+func checkCorrelationTarget(policy *Policy, ds *datasources.DataSource) error {
+	if ds.JsonData == nil {
+		return nil
+	}
+	for _, tag := range ds.JsonData.Get("tags").MustStringArray() {
+		for _, denied := range policy.Values {
+			if strings.EqualFold(tag, denied) {
+				return violation(policy.Kind, "Correlations may not target data source %s tagged %s", ds.Name, tag)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package orgpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+func TestChecks(t *testing.T) {
+	t.Run("Public dashboards may not query denied data source types", func(t *testing.T) {
+		policy := &Policy{Kind: KindPublicDashboardsDeniedDatasourceTypes, Values: []string{"elasticsearch"}}
+		require.ErrorIs(t, checkPublicDashboardDatasource(policy, &datasources.DataSource{Name: "logs", Type: "elasticsearch"}), ErrPolicyViolation.Base)
+		require.NoError(t, checkPublicDashboardDatasource(policy, &datasources.DataSource{Name: "metrics", Type: "prometheus"}))
+	})
+
+	t.Run("Webhooks may only target allowed hosts", func(t *testing.T) {
+		policy := &Policy{Kind: KindNotificationsAllowedWebhookHosts, Values: []string{"*.internal.corp"}}
+		require.NoError(t, checkWebhookURL(policy, "https://hooks.internal.corp:8443/alerts"))
+		require.ErrorIs(t, checkWebhookURL(policy, "https://example.com/alerts"), ErrPolicyViolation.Base)
+		require.ErrorIs(t, checkWebhookURL(policy, "not a url"), ErrPolicyViolation.Base)
+	})
+
+	t.Run("Correlations may not target data sources with denied tags", func(t *testing.T) {
+		policy := &Policy{Kind: KindCorrelationsDeniedTargetTags, Values: []string{"sensitive"}}
+		tagged := &datasources.DataSource{Name: "audit", JsonData: simplejson.NewFromAny(map[string]interface{}{"tags": []interface{}{"team-a", "Sensitive"}})}
+		require.ErrorIs(t, checkCorrelationTarget(policy, tagged), ErrPolicyViolation.Base)
+		require.NoError(t, checkCorrelationTarget(policy, &datasources.DataSource{Name: "metrics", JsonData: simplejson.New()}))
+		require.NoError(t, checkCorrelationTarget(policy, &datasources.DataSource{Name: "legacy"}))
+	})
+}
+
+func TestIntegrationOrgPolicies(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	s := &OrgPolicyService{SQLStore: sqlstore.InitTestDB(t)}
+	ctx := context.Background()
+
+	t.Run("Nothing is denied without policies", func(t *testing.T) {
+		require.NoError(t, s.CheckWebhookURL(ctx, 1, "https://example.com"))
+		policies, err := s.GetPolicies(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, policies)
+	})
+
+	t.Run("Can set a policy", func(t *testing.T) {
+		policy, err := s.SetPolicy(ctx, SetPolicyCommand{OrgID: 1, Kind: KindNotificationsAllowedWebhookHosts, Values: []string{"hooks.internal.corp"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"hooks.internal.corp"}, policy.Values)
+		require.ErrorIs(t, s.CheckWebhookURL(ctx, 1, "https://example.com"), ErrPolicyViolation.Base)
+		require.NoError(t, s.CheckWebhookURL(ctx, 2, "https://example.com"))
+	})
+
+	t.Run("Can replace a policy", func(t *testing.T) {
+		_, err := s.SetPolicy(ctx, SetPolicyCommand{OrgID: 1, Kind: KindNotificationsAllowedWebhookHosts, Values: []string{"example.com"}})
+		require.NoError(t, err)
+		require.NoError(t, s.CheckWebhookURL(ctx, 1, "https://example.com"))
+
+		policies, err := s.GetPolicies(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, policies, 1)
+		require.Equal(t, []string{"example.com"}, policies[0].Values)
+	})
+
+	t.Run("Can delete a policy", func(t *testing.T) {
+		require.NoError(t, s.DeletePolicy(ctx, DeletePolicyCommand{OrgID: 1, Kind: KindNotificationsAllowedWebhookHosts}))
+		require.NoError(t, s.CheckWebhookURL(ctx, 1, "https://hooks.example.com"))
+		require.ErrorIs(t, s.DeletePolicy(ctx, DeletePolicyCommand{OrgID: 1, Kind: KindNotificationsAllowedWebhookHosts}), ErrPolicyNotFound)
+	})
+}
@@ -3,6 +3,8 @@ package alerting
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -65,10 +67,45 @@ type ContactPoint struct {
 }
 
 type ReceiverV1 struct {
-	UID                   values.StringValue `json:"uid" yaml:"uid"`
-	Type                  values.StringValue `json:"type" yaml:"type"`
-	Settings              values.JSONValue   `json:"settings" yaml:"settings"`
-	DisableResolveMessage values.BoolValue   `json:"disableResolveMessage" yaml:"disableResolveMessage"`
+	UID                   values.StringValue            `json:"uid" yaml:"uid"`
+	Type                  values.StringValue            `json:"type" yaml:"type"`
+	Settings              values.JSONValue              `json:"settings" yaml:"settings"`
+	SecureSettings        map[string]SecureSettingRefV1 `json:"secureSettings" yaml:"secureSettings"`
+	DisableResolveMessage values.BoolValue              `json:"disableResolveMessage" yaml:"disableResolveMessage"`
+}
+
+// SecureSettingRefV1 references the environment variable or the file that a
+// secure setting is read from, so that provisioning files don't hold secrets.
+type SecureSettingRefV1 struct {
+	Env  values.StringValue `json:"env" yaml:"env"`
+	File values.StringValue `json:"file" yaml:"file"`
+}
+
+func (ref SecureSettingRefV1) resolve() (string, error) {
+	env := strings.TrimSpace(ref.Env.Value())
+	file := strings.TrimSpace(ref.File.Value())
+	switch {
+	case env != "" && file != "":
+		return "", fmt.Errorf("only one of env or file can be set")
+	case env != "":
+		value, ok := os.LookupEnv(env)
+		if !ok || value == "" {
+			return "", fmt.Errorf("environment variable %s is not set", env)
+		}
+		return value, nil
+	case file != "":
+		content, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return "", fmt.Errorf("failed to read file: %w", err)
+		}
+		value := strings.TrimRight(string(content), "\r\n")
+		if value == "" {
+			return "", fmt.Errorf("file %s is empty", file)
+		}
+		return value, nil
+	default:
+		return "", fmt.Errorf("either env or file must be set")
+	}
 }
 
 func (config *ReceiverV1) mapToModel(name string) (definitions.EmbeddedContactPoint, error) {
@@ -92,6 +129,9 @@ func (config *ReceiverV1) mapToModel(name string) (definitions.EmbeddedContactPo
 		Provenance:            string(models.ProvenanceFile),
 		Settings:              settings,
 	}
+	if err := resolveSecureSettings(&cp, config.SecureSettings); err != nil {
+		return definitions.EmbeddedContactPoint{}, err
+	}
 	// As the values are not encrypted when coming from disk files,
 	// we can simply return the fallback for validation.
 	err := cp.Valid(func(_ context.Context, _ map[string][]byte, _, fallback string) string {
@@ -102,3 +142,34 @@ func (config *ReceiverV1) mapToModel(name string) (definitions.EmbeddedContactPo
 	}
 	return cp, nil
 }
+
+// resolveSecureSettings reads the secure settings of the contact point from
+// their references, so that they are encrypted like the secure settings set
+// inline.
+func resolveSecureSettings(cp *definitions.EmbeddedContactPoint, refs map[string]SecureSettingRefV1) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	secretKeys, err := cp.SecretKeys()
+	if err != nil {
+		return err
+	}
+	isSecretKey := make(map[string]bool, len(secretKeys))
+	for _, key := range secretKeys {
+		isSecretKey[key] = true
+	}
+	for key, ref := range refs {
+		if !isSecretKey[key] {
+			return fmt.Errorf("secure setting %s: not a secure setting of %s contact points", key, cp.Type)
+		}
+		if _, ok := cp.Settings.CheckGet(key); ok {
+			return fmt.Errorf("secure setting %s: already set in settings", key)
+		}
+		value, err := ref.resolve()
+		if err != nil {
+			return fmt.Errorf("secure setting %s: %w", key, err)
+		}
+		cp.Settings.Set(key, value)
+	}
+	return nil
+}
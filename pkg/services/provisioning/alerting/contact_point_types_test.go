@@ -1,6 +1,9 @@
 package alerting
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/grafana/grafana/pkg/services/provisioning/values"
@@ -61,6 +64,58 @@ func TestReceivers(t *testing.T) {
 	})
 }
 
+func TestReceiverSecureSettings(t *testing.T) {
+	secureSettings := func(t *testing.T, refs string) map[string]SecureSettingRefV1 {
+		t.Helper()
+		var secure map[string]SecureSettingRefV1
+		require.NoError(t, yaml.Unmarshal([]byte(refs), &secure))
+		return secure
+	}
+
+	t.Run("Secure settings should be read from environment variables", func(t *testing.T) {
+		t.Setenv("TEST_AM_PASSWORD", "secret")
+		cp := validReceiverV1(t)
+		cp.SecureSettings = secureSettings(t, `{"basicAuthPassword": {"env": "TEST_AM_PASSWORD"}}`)
+		model, err := cp.mapToModel("test")
+		require.NoError(t, err)
+		require.Equal(t, "secret", model.Settings.Get("basicAuthPassword").MustString())
+	})
+	t.Run("Secure settings should be read from files", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "password")
+		require.NoError(t, os.WriteFile(file, []byte("secret\n"), 0600))
+		cp := validReceiverV1(t)
+		cp.SecureSettings = secureSettings(t, fmt.Sprintf(`{"basicAuthPassword": {"file": %q}}`, file))
+		model, err := cp.mapToModel("test")
+		require.NoError(t, err)
+		require.Equal(t, "secret", model.Settings.Get("basicAuthPassword").MustString())
+	})
+	t.Run("Unset environment variables should error on mapping", func(t *testing.T) {
+		cp := validReceiverV1(t)
+		cp.SecureSettings = secureSettings(t, `{"basicAuthPassword": {"env": "TEST_AM_PASSWORD_NOT_SET"}}`)
+		_, err := cp.mapToModel("test")
+		require.ErrorContains(t, err, "environment variable TEST_AM_PASSWORD_NOT_SET is not set")
+	})
+	t.Run("Missing files should error on mapping", func(t *testing.T) {
+		cp := validReceiverV1(t)
+		cp.SecureSettings = secureSettings(t, `{"basicAuthPassword": {"file": "/does/not/exist"}}`)
+		_, err := cp.mapToModel("test")
+		require.Error(t, err)
+	})
+	t.Run("References to both an environment variable and a file should error on mapping", func(t *testing.T) {
+		cp := validReceiverV1(t)
+		cp.SecureSettings = secureSettings(t, `{"basicAuthPassword": {"env": "A", "file": "/b"}}`)
+		_, err := cp.mapToModel("test")
+		require.ErrorContains(t, err, "only one of env or file can be set")
+	})
+	t.Run("Settings that aren't secure should error on mapping", func(t *testing.T) {
+		t.Setenv("TEST_AM_URL", "http://test-url")
+		cp := validReceiverV1(t)
+		cp.SecureSettings = secureSettings(t, `{"url": {"env": "TEST_AM_URL"}}`)
+		_, err := cp.mapToModel("test")
+		require.ErrorContains(t, err, "not a secure setting")
+	})
+}
+
 func validReceiverV1(t *testing.T) ReceiverV1 {
 	t.Helper()
 	var (
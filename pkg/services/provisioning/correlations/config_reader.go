@@ -0,0 +1,101 @@
+package correlations
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type configReader struct {
+	log      log.Logger
+	orgStore utils.OrgStore
+}
+
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]*configs, error) {
+	var correlations []*configs
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("can't read correlation provisioning files from directory", "path", path, "error", err)
+		return correlations, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cfg, err := cr.parseCorrelationConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				correlations = append(correlations, cfg)
+			}
+		}
+	}
+
+	if err := cr.validate(ctx, correlations); err != nil {
+		return nil, err
+	}
+
+	return correlations, nil
+}
+
+func (cr *configReader) parseCorrelationConfig(path string, file fs.DirEntry) (*configs, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var v1 *configsV1
+	if err := yaml.Unmarshal(yamlFile, &v1); err != nil {
+		return nil, err
+	}
+
+	if v1 == nil {
+		return nil, nil
+	}
+	return v1.mapToCorrelationsFromConfig(v1.APIVersion), nil
+}
+
+// validate checks the required fields and the orgs of the correlations, and defaults their org to the main org.
+func (cr *configReader) validate(ctx context.Context, correlations []*configs) error {
+	for i := range correlations {
+		for index, c := range correlations[i].Correlations {
+			if c.UID == "" || c.SourceUID == "" {
+				return fmt.Errorf("correlation item %d in configuration doesn't contain required fields uid and sourceUID", index+1)
+			}
+
+			if c.OrgID == 0 {
+				c.OrgID = 1
+			}
+
+			if err := utils.CheckOrgExists(ctx, cr.orgStore, c.OrgID); err != nil {
+				return fmt.Errorf("failed to provision %q correlation: %w", c.UID, err)
+			}
+		}
+
+		for index, c := range correlations[i].DeleteCorrelations {
+			if c.UID == "" || c.SourceUID == "" {
+				return fmt.Errorf("deleted correlation item %d in configuration doesn't contain required fields uid and sourceUID", index+1)
+			}
+
+			if c.OrgID == 0 {
+				c.OrgID = 1
+			}
+		}
+	}
+
+	return nil
+}
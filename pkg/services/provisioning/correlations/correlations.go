@@ -0,0 +1,182 @@
+package correlations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type Store interface {
+	GetCorrelation(ctx context.Context, cmd correlations.GetCorrelationQuery) (correlations.Correlation, error)
+	CreateCorrelation(ctx context.Context, cmd correlations.CreateCorrelationCommand) (correlations.Correlation, error)
+	UpdateCorrelation(ctx context.Context, cmd correlations.UpdateCorrelationCommand) (correlations.Correlation, error)
+	DeleteCorrelation(ctx context.Context, cmd correlations.DeleteCorrelationCommand) error
+}
+
+// Provision scans a directory for provisioning config files
+// and provisions the correlations in those files.
+func Provision(ctx context.Context, configDirectory string, store Store, orgStore utils.OrgStore) error {
+	logger := log.New("provisioning.correlations")
+	cp := CorrelationProvisioner{
+		log:         logger,
+		cfgProvider: &configReader{log: logger, orgStore: orgStore},
+		store:       store,
+	}
+	return cp.applyChanges(ctx, configDirectory)
+}
+
+// CorrelationProvisioner is responsible for provisioning correlations based on
+// configuration read by the `configReader`. Provisioning is idempotent: the
+// correlations are identified by their UID and source data source, created
+// when missing and overwritten otherwise.
+type CorrelationProvisioner struct {
+	log         log.Logger
+	cfgProvider *configReader
+	store       Store
+}
+
+func (cp *CorrelationProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := cp.cfgProvider.readConfig(ctx, configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := cp.apply(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cp *CorrelationProvisioner) apply(ctx context.Context, cfg *configs) error {
+	for _, c := range cfg.DeleteCorrelations {
+		err := cp.store.DeleteCorrelation(ctx, correlations.DeleteCorrelationCommand{
+			UID:               c.UID,
+			SourceUID:         c.SourceUID,
+			OrgId:             c.OrgID,
+			SkipReadOnlyCheck: true,
+		})
+		if err != nil && !errors.Is(err, correlations.ErrCorrelationNotFound) && !errors.Is(err, correlations.ErrSourceDataSourceDoesNotExists) {
+			return fmt.Errorf("failed to delete correlation %q: %w", c.UID, err)
+		}
+		if err == nil {
+			cp.log.Info("deleted correlation based on configuration", "uid", c.UID, "sourceUID", c.SourceUID)
+		}
+	}
+
+	for _, c := range cfg.Correlations {
+		if err := cp.upsert(ctx, c); err != nil {
+			return fmt.Errorf("failed to provision correlation %q: %w", c.UID, err)
+		}
+	}
+
+	return nil
+}
+
+func (cp *CorrelationProvisioner) upsert(ctx context.Context, c *upsertCorrelationFromConfig) error {
+	createCmd, err := makeCreateCorrelationCommand(c)
+	if err != nil {
+		return err
+	}
+
+	// the provisioner manages the correlations whatever their visibility
+	provisioner := accesscontrol.BackgroundUser("correlation_provisioning", c.OrgID, org.RoleAdmin, nil)
+	existing, err := cp.store.GetCorrelation(ctx, correlations.GetCorrelationQuery{UID: c.UID, SourceUID: c.SourceUID, OrgId: c.OrgID, SignedInUser: provisioner})
+	if err != nil && !errors.Is(err, correlations.ErrCorrelationNotFound) {
+		return err
+	}
+
+	if err == nil {
+		if !existing.Provisioned || !sameTarget(existing.TargetUID, createCmd.TargetUID) {
+			// the target and the provisioned flag of a correlation can't be updated, recreate it instead
+			if err := cp.store.DeleteCorrelation(ctx, correlations.DeleteCorrelationCommand{
+				UID:               c.UID,
+				SourceUID:         c.SourceUID,
+				OrgId:             c.OrgID,
+				SkipReadOnlyCheck: true,
+			}); err != nil {
+				return err
+			}
+		} else {
+			cp.log.Debug("updating correlation from configuration", "uid", c.UID, "sourceUID", c.SourceUID)
+			_, err := cp.store.UpdateCorrelation(ctx, correlations.UpdateCorrelationCommand{
+				UID:               c.UID,
+				SourceUID:         c.SourceUID,
+				OrgId:             c.OrgID,
+				SkipReadOnlyCheck: true,
+				Label:             &createCmd.Label,
+				Description:       &createCmd.Description,
+				Order:             &createCmd.Order,
+				Config: &correlations.CorrelationConfigUpdateDTO{
+					Field:           &createCmd.Config.Field,
+					Type:            &createCmd.Config.Type,
+					Target:          &createCmd.Config.Target,
+					Targets:         &createCmd.Config.Targets,
+					Transformations: &createCmd.Config.Transformations,
+					Matchers:        &createCmd.Config.Matchers,
+					Display:         &createCmd.Config.Display,
+				},
+			})
+			return err
+		}
+	}
+
+	cp.log.Info("inserting correlation from configuration", "uid", c.UID, "sourceUID", c.SourceUID)
+	_, err = cp.store.CreateCorrelation(ctx, createCmd)
+	return err
+}
+
+func makeCreateCorrelationCommand(c *upsertCorrelationFromConfig) (correlations.CreateCorrelationCommand, error) {
+	cmd := correlations.CreateCorrelationCommand{
+		UID:               c.UID,
+		SourceUID:         c.SourceUID,
+		OrgId:             c.OrgID,
+		Label:             c.Label,
+		Description:       c.Description,
+		Order:             c.Order,
+		SkipReadOnlyCheck: true,
+		Provisioned:       true,
+	}
+
+	if c.TargetUID != "" {
+		targetUID := c.TargetUID
+		cmd.TargetUID = &targetUID
+	}
+
+	if c.Config != nil {
+		body, err := json.Marshal(c.Config)
+		if err != nil {
+			return correlations.CreateCorrelationCommand{}, err
+		}
+		if err := json.Unmarshal(body, &cmd.Config); err != nil {
+			return correlations.CreateCorrelationCommand{}, err
+		}
+	}
+
+	// correlations without config default to type="query", as in the data sources provisioning
+	if cmd.Config.Type == "" {
+		cmd.Config.Type = correlations.ConfigTypeQuery
+	}
+
+	if err := cmd.Validate(); err != nil {
+		return correlations.CreateCorrelationCommand{}, err
+	}
+
+	return cmd, nil
+}
+
+func sameTarget(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
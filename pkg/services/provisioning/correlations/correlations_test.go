@@ -0,0 +1,167 @@
+package correlations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/correlations"
+)
+
+var (
+	logger log.Logger = log.New("fake.log")
+
+	allProperties    = "testdata/all-properties"
+	noRequiredFields = "testdata/no-required-fields"
+	brokenYaml       = "testdata/broken-yaml"
+)
+
+func newTestProvisioner(store Store) CorrelationProvisioner {
+	return CorrelationProvisioner{
+		log:         logger,
+		cfgProvider: &configReader{log: logger, orgStore: &mockOrgStore{}},
+		store:       store,
+	}
+}
+
+func TestConfigReader(t *testing.T) {
+	t.Run("reads all properties", func(t *testing.T) {
+		cfgProvider := &configReader{log: logger, orgStore: &mockOrgStore{}}
+		cfgs, err := cfgProvider.readConfig(context.Background(), allProperties)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+
+		cfg := cfgs[0]
+		require.Equal(t, int64(1), cfg.APIVersion)
+		require.Len(t, cfg.Correlations, 2)
+		require.Equal(t, &upsertCorrelationFromConfig{
+			UID:         "logs-to-traces",
+			OrgID:       2,
+			SourceUID:   "loki",
+			TargetUID:   "tempo",
+			Label:       "Logs to traces",
+			Description: "Open the trace of the log line",
+			Order:       1,
+			Config: map[string]interface{}{
+				"type":   "query",
+				"field":  "traceId",
+				"target": map[string]interface{}{"query": "${value}"},
+			},
+		}, cfg.Correlations[0])
+		require.Equal(t, int64(1), cfg.Correlations[1].OrgID)
+		require.Equal(t, []*deleteCorrelationConfig{{UID: "old-correlation", OrgID: 1, SourceUID: "loki"}}, cfg.DeleteCorrelations)
+	})
+
+	t.Run("requires uid and sourceUID", func(t *testing.T) {
+		cfgProvider := &configReader{log: logger, orgStore: &mockOrgStore{}}
+		_, err := cfgProvider.readConfig(context.Background(), noRequiredFields)
+		require.ErrorContains(t, err, "required fields")
+	})
+
+	t.Run("fails on broken yaml", func(t *testing.T) {
+		cfgProvider := &configReader{log: logger, orgStore: &mockOrgStore{}}
+		_, err := cfgProvider.readConfig(context.Background(), brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("ignores a missing directory", func(t *testing.T) {
+		cfgProvider := &configReader{log: logger, orgStore: &mockOrgStore{}}
+		cfgs, err := cfgProvider.readConfig(context.Background(), "testdata/missing")
+		require.NoError(t, err)
+		require.Empty(t, cfgs)
+	})
+}
+
+func TestCorrelationProvisioner(t *testing.T) {
+	t.Run("creates missing correlations as provisioned", func(t *testing.T) {
+		store := &mockCorrelationsStore{}
+		cp := newTestProvisioner(store)
+		require.NoError(t, cp.applyChanges(context.Background(), allProperties))
+
+		require.Len(t, store.created, 2)
+		created := store.created[0]
+		require.Equal(t, "logs-to-traces", created.UID)
+		require.Equal(t, "tempo", *created.TargetUID)
+		require.True(t, created.Provisioned)
+		require.True(t, created.SkipReadOnlyCheck)
+		require.Equal(t, correlations.ConfigTypeQuery, created.Config.Type)
+		require.Equal(t, correlations.CorrelationFields{"traceId"}, created.Config.Field)
+		require.Nil(t, store.created[1].TargetUID)
+		require.Equal(t, correlations.ConfigTypeExternal, store.created[1].Config.Type)
+
+		require.Len(t, store.deleted, 1)
+		require.Equal(t, "old-correlation", store.deleted[0].UID)
+		require.True(t, store.deleted[0].SkipReadOnlyCheck)
+	})
+
+	t.Run("updates provisioned correlations", func(t *testing.T) {
+		tempo := "tempo"
+		store := &mockCorrelationsStore{items: []correlations.Correlation{
+			{UID: "logs-to-traces", SourceUID: "loki", TargetUID: &tempo, Label: "Old label", Provisioned: true},
+		}}
+		cp := newTestProvisioner(store)
+		require.NoError(t, cp.applyChanges(context.Background(), allProperties))
+
+		require.Len(t, store.updated, 1)
+		require.Equal(t, "Logs to traces", *store.updated[0].Label)
+		require.Equal(t, correlations.CorrelationFields{"traceId"}, *store.updated[0].Config.Field)
+		require.True(t, store.updated[0].SkipReadOnlyCheck)
+		require.Len(t, store.created, 1)
+		require.Equal(t, "logs-to-tickets", store.created[0].UID)
+	})
+
+	t.Run("recreates correlations with another target or not provisioned", func(t *testing.T) {
+		jaeger := "jaeger"
+		store := &mockCorrelationsStore{items: []correlations.Correlation{
+			{UID: "logs-to-traces", SourceUID: "loki", TargetUID: &jaeger, Provisioned: true},
+			{UID: "logs-to-tickets", SourceUID: "loki"},
+		}}
+		cp := newTestProvisioner(store)
+		require.NoError(t, cp.applyChanges(context.Background(), allProperties))
+
+		require.Empty(t, store.updated)
+		require.Len(t, store.deleted, 3)
+		require.Len(t, store.created, 2)
+	})
+}
+
+type mockOrgStore struct{}
+
+func (m *mockOrgStore) GetOrgById(c context.Context, cmd *models.GetOrgByIdQuery) error {
+	cmd.Result = &models.Org{Id: cmd.Id}
+	return nil
+}
+
+type mockCorrelationsStore struct {
+	items   []correlations.Correlation
+	created []correlations.CreateCorrelationCommand
+	updated []correlations.UpdateCorrelationCommand
+	deleted []correlations.DeleteCorrelationCommand
+}
+
+func (m *mockCorrelationsStore) GetCorrelation(_ context.Context, cmd correlations.GetCorrelationQuery) (correlations.Correlation, error) {
+	for _, c := range m.items {
+		if c.UID == cmd.UID && c.SourceUID == cmd.SourceUID {
+			return c, nil
+		}
+	}
+	return correlations.Correlation{}, correlations.ErrCorrelationNotFound.Errorf("not found")
+}
+
+func (m *mockCorrelationsStore) CreateCorrelation(_ context.Context, cmd correlations.CreateCorrelationCommand) (correlations.Correlation, error) {
+	m.created = append(m.created, cmd)
+	return correlations.Correlation{UID: cmd.UID, SourceUID: cmd.SourceUID}, nil
+}
+
+func (m *mockCorrelationsStore) UpdateCorrelation(_ context.Context, cmd correlations.UpdateCorrelationCommand) (correlations.Correlation, error) {
+	m.updated = append(m.updated, cmd)
+	return correlations.Correlation{UID: cmd.UID, SourceUID: cmd.SourceUID}, nil
+}
+
+func (m *mockCorrelationsStore) DeleteCorrelation(_ context.Context, cmd correlations.DeleteCorrelationCommand) error {
+	m.deleted = append(m.deleted, cmd)
+	return nil
+}
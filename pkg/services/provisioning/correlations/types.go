@@ -0,0 +1,86 @@
+package correlations
+
+import (
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// configs is the normalized data object for the correlations config files. Any config version should be
+// mappable to this type.
+type configs struct {
+	APIVersion int64
+
+	Correlations       []*upsertCorrelationFromConfig
+	DeleteCorrelations []*deleteCorrelationConfig
+}
+
+type upsertCorrelationFromConfig struct {
+	UID         string
+	OrgID       int64
+	SourceUID   string
+	TargetUID   string
+	Label       string
+	Description string
+	Order       int
+	Config      map[string]interface{}
+}
+
+type deleteCorrelationConfig struct {
+	UID       string
+	OrgID     int64
+	SourceUID string
+}
+
+// configsV1 is the mapping of the version 1 config files.
+type configsV1 struct {
+	APIVersion int64 `json:"apiVersion" yaml:"apiVersion"`
+
+	Correlations       []*upsertCorrelationFromConfigV1 `json:"correlations" yaml:"correlations"`
+	DeleteCorrelations []*deleteCorrelationConfigV1     `json:"deleteCorrelations" yaml:"deleteCorrelations"`
+}
+
+type upsertCorrelationFromConfigV1 struct {
+	UID         values.StringValue `json:"uid" yaml:"uid"`
+	OrgID       values.Int64Value  `json:"orgId" yaml:"orgId"`
+	SourceUID   values.StringValue `json:"sourceUID" yaml:"sourceUID"`
+	TargetUID   values.StringValue `json:"targetUID" yaml:"targetUID"`
+	Label       values.StringValue `json:"label" yaml:"label"`
+	Description values.StringValue `json:"description" yaml:"description"`
+	Order       values.IntValue    `json:"order" yaml:"order"`
+	Config      values.JSONValue   `json:"config" yaml:"config"`
+}
+
+type deleteCorrelationConfigV1 struct {
+	UID       values.StringValue `json:"uid" yaml:"uid"`
+	OrgID     values.Int64Value  `json:"orgId" yaml:"orgId"`
+	SourceUID values.StringValue `json:"sourceUID" yaml:"sourceUID"`
+}
+
+func (cfg *configsV1) mapToCorrelationsFromConfig(apiVersion int64) *configs {
+	r := &configs{APIVersion: apiVersion}
+	if cfg == nil {
+		return r
+	}
+
+	for _, c := range cfg.Correlations {
+		r.Correlations = append(r.Correlations, &upsertCorrelationFromConfig{
+			UID:         c.UID.Value(),
+			OrgID:       c.OrgID.Value(),
+			SourceUID:   c.SourceUID.Value(),
+			TargetUID:   c.TargetUID.Value(),
+			Label:       c.Label.Value(),
+			Description: c.Description.Value(),
+			Order:       c.Order.Value(),
+			Config:      c.Config.Value(),
+		})
+	}
+
+	for _, c := range cfg.DeleteCorrelations {
+		r.DeleteCorrelations = append(r.DeleteCorrelations, &deleteCorrelationConfig{
+			UID:       c.UID.Value(),
+			OrgID:     c.OrgID.Value(),
+			SourceUID: c.SourceUID.Value(),
+		})
+	}
+
+	return r
+}
@@ -268,6 +268,24 @@ func TestDatasourceAsConfig(t *testing.T) {
 			require.Equal(t, 0, len(correlationsStore.deletedByTargetUID))
 		})
 
+		t.Run("Updating existing datasource with a new UID moves its correlations to the new UID", func(t *testing.T) {
+			store := &spyStore{items: []*datasources.DataSource{{Name: "Graphite", OrgId: 1, Id: 1, Uid: "previous-uid"}}}
+			orgStore := &mockOrgStore{}
+			correlationsStore := &mockCorrelationsStore{}
+			dc := newDatasourceProvisioner(logger, store, correlationsStore, orgStore)
+			err := dc.applyChanges(context.Background(), oneDatasourceWithTwoCorrelations)
+			if err != nil {
+				t.Fatalf("applyChanges return an error %v", err)
+			}
+
+			require.Equal(t, 1, len(correlationsStore.deletedBySourceUID))
+			require.Equal(t, "graphite", correlationsStore.deletedBySourceUID[0].SourceUID)
+			require.Equal(t, 2, len(correlationsStore.created))
+			for _, created := range correlationsStore.created {
+				require.Equal(t, "graphite", created.SourceUID)
+			}
+		})
+
 		t.Run("Deleting datasource deletes existing correlations", func(t *testing.T) {
 			store := &spyStore{items: []*datasources.DataSource{{Name: "old-data-source", OrgId: 1, Id: 1, Uid: "some-uid"}}}
 			orgStore := &mockOrgStore{}
@@ -93,16 +93,22 @@ func (dc *DatasourceProvisioner) apply(ctx context.Context, cfg *configs) error
 				return err
 			}
 
+			// the correlations follow the data source when it's provisioned under a new UID
+			uid := cmd.Result.Uid
+			if updateCmd.Uid != "" {
+				uid = updateCmd.Uid
+			}
+
 			if len(ds.Correlations) > 0 {
 				if err := dc.correlationsStore.DeleteCorrelationsBySourceUID(ctx, correlations.DeleteCorrelationsBySourceUIDCommand{
-					SourceUID: cmd.Result.Uid,
+					SourceUID: uid,
 				}); err != nil {
 					return err
 				}
 			}
 
 			for _, correlation := range ds.Correlations {
-				if insertCorrelationCmd, err := makeCreateCorrelationCommand(correlation, cmd.Result.Uid, updateCmd.OrgId); err == nil {
+				if insertCorrelationCmd, err := makeCreateCorrelationCommand(correlation, uid, updateCmd.OrgId); err == nil {
 					correlationsToInsert = append(correlationsToInsert, insertCorrelationCmd)
 				} else {
 					dc.log.Error("failed to parse correlation", "correlation", correlation)
@@ -144,6 +150,7 @@ func makeCreateCorrelationCommand(correlation map[string]interface{}, SourceUID
 		Description:       correlation["description"].(string),
 		OrgId:             OrgId,
 		SkipReadOnlyCheck: true,
+		Provisioned:       true,
 	}
 
 	targetUID, ok := correlation["targetUID"].(string)
@@ -151,6 +158,10 @@ func makeCreateCorrelationCommand(correlation map[string]interface{}, SourceUID
 		createCommand.TargetUID = &targetUID
 	}
 
+	if order, ok := correlation["order"].(int); ok {
+		createCommand.Order = order
+	}
+
 	if correlation["config"] != nil {
 		jsonbody, err := json.Marshal(correlation["config"])
 		if err != nil {
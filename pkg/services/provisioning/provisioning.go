@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/pluginsettings"
 	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
+	prov_correlations "github.com/grafana/grafana/pkg/services/provisioning/correlations"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
 	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
@@ -63,6 +64,7 @@ func ProvideService(
 		newDashboardProvisioner:      dashboards.New,
 		provisionNotifiers:           notifiers.Provision,
 		provisionDatasources:         datasources.Provision,
+		provisionCorrelations:        prov_correlations.Provision,
 		provisionPlugins:             plugins.Provision,
 		provisionAlerting:            prov_alerting.Provision,
 		dashboardProvisioningService: dashboardProvisioningService,
@@ -84,6 +86,7 @@ type ProvisioningService interface {
 	registry.BackgroundService
 	RunInitProvisioners(ctx context.Context) error
 	ProvisionDatasources(ctx context.Context) error
+	ProvisionCorrelations(ctx context.Context) error
 	ProvisionPlugins(ctx context.Context) error
 	ProvisionNotifications(ctx context.Context) error
 	ProvisionDashboards(ctx context.Context) error
@@ -100,6 +103,7 @@ func NewProvisioningServiceImpl() *ProvisioningServiceImpl {
 		newDashboardProvisioner: dashboards.New,
 		provisionNotifiers:      notifiers.Provision,
 		provisionDatasources:    datasources.Provision,
+		provisionCorrelations:   prov_correlations.Provision,
 		provisionPlugins:        plugins.Provision,
 	}
 }
@@ -134,6 +138,7 @@ type ProvisioningServiceImpl struct {
 	dashboardProvisioner         dashboards.DashboardProvisioner
 	provisionNotifiers           func(context.Context, string, notifiers.Manager, org.Service, notifiers.SQLStore, encryption.Internal, *notifications.NotificationService) error
 	provisionDatasources         func(context.Context, string, datasources.Store, datasources.CorrelationsStore, utils.OrgStore) error
+	provisionCorrelations        func(context.Context, string, prov_correlations.Store, utils.OrgStore) error
 	provisionPlugins             func(context.Context, string, plugifaces.Store, pluginsettings.Service, org.Service) error
 	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) error
 	mutex                        sync.Mutex
@@ -154,6 +159,11 @@ func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) erro
 		return err
 	}
 
+	err = ps.ProvisionCorrelations(ctx)
+	if err != nil {
+		return err
+	}
+
 	err = ps.ProvisionPlugins(ctx)
 	if err != nil {
 		return err
@@ -214,6 +224,16 @@ func (ps *ProvisioningServiceImpl) ProvisionDatasources(ctx context.Context) err
 	return nil
 }
 
+func (ps *ProvisioningServiceImpl) ProvisionCorrelations(ctx context.Context) error {
+	correlationsPath := filepath.Join(ps.Cfg.ProvisioningPath, "correlations")
+	if err := ps.provisionCorrelations(ctx, correlationsPath, ps.correlationsService, ps.SQLStore); err != nil {
+		err = fmt.Errorf("%v: %w", "Correlation provisioning error", err)
+		ps.log.Error("Failed to provision correlations", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (ps *ProvisioningServiceImpl) ProvisionPlugins(ctx context.Context) error {
 	appPath := filepath.Join(ps.Cfg.ProvisioningPath, "plugins")
 	if err := ps.provisionPlugins(ctx, appPath, ps.pluginStore, ps.pluginsSettings, ps.orgService); err != nil {
@@ -5,6 +5,7 @@ import "context"
 type Calls struct {
 	RunInitProvisioners                 []interface{}
 	ProvisionDatasources                []interface{}
+	ProvisionCorrelations               []interface{}
 	ProvisionPlugins                    []interface{}
 	ProvisionNotifications              []interface{}
 	ProvisionDashboards                 []interface{}
@@ -18,6 +19,7 @@ type ProvisioningServiceMock struct {
 	Calls                                   *Calls
 	RunInitProvisionersFunc                 func(ctx context.Context) error
 	ProvisionDatasourcesFunc                func(ctx context.Context) error
+	ProvisionCorrelationsFunc               func(ctx context.Context) error
 	ProvisionPluginsFunc                    func() error
 	ProvisionNotificationsFunc              func() error
 	ProvisionDashboardsFunc                 func() error
@@ -48,6 +50,14 @@ func (mock *ProvisioningServiceMock) ProvisionDatasources(ctx context.Context) e
 	return nil
 }
 
+func (mock *ProvisioningServiceMock) ProvisionCorrelations(ctx context.Context) error {
+	mock.Calls.ProvisionCorrelations = append(mock.Calls.ProvisionCorrelations, nil)
+	if mock.ProvisionCorrelationsFunc != nil {
+		return mock.ProvisionCorrelationsFunc(ctx)
+	}
+	return nil
+}
+
 func (mock *ProvisioningServiceMock) ProvisionPlugins(ctx context.Context) error {
 	mock.Calls.ProvisionPlugins = append(mock.Calls.ProvisionPlugins, nil)
 	if mock.ProvisionPluginsFunc != nil {
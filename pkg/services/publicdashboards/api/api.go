@@ -2,30 +2,57 @@ package api
 
 import (
 	"errors"
+	"net"
 	"net/http"
+	"path"
 	"strconv"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/network"
 	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/geoip"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/abuse"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/challenge"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/requestid"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/signature"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 )
 
+// PrerenderedAtHeaderName is the response header holding the time a
+// pre-rendered query response was rendered at
+const PrerenderedAtHeaderName = "X-Grafana-Prerendered-At"
+
 type Api struct {
 	PublicDashboardService publicdashboards.Service
 	RouteRegister          routing.RouteRegister
 	AccessControl          accesscontrol.AccessControl
 	Features               *featuremgmt.FeatureManager
+	AbuseDetector          *abuse.Detector
+	TrustedProxies         []*net.IPNet
+	Signer                 *signature.Signer
+	Challenger             *challenge.Challenger
+	GeoIP                  geoip.Provider
+	JWTService             models.JWTService
+	ExternalViewerHeader   string
+	Bus                    bus.Bus
+	RateLimits             ratelimit.Service
 	Log                    log.Logger
 }
 
@@ -34,15 +61,33 @@ func ProvideApi(
 	rr routing.RouteRegister,
 	ac accesscontrol.AccessControl,
 	features *featuremgmt.FeatureManager,
+	cfg *setting.Cfg,
+	jwtService models.JWTService,
+	bus bus.Bus,
+	rateLimits ratelimit.Service,
 ) *Api {
+	signer := signature.NewSigner(cfg.SecretKey)
 	api := &Api{
 		PublicDashboardService: pd,
 		RouteRegister:          rr,
 		AccessControl:          ac,
 		Features:               features,
+		AbuseDetector:          abuse.NewDetector(abuse.DefaultMaxFailures, abuse.DefaultWindow, abuse.DefaultBlockDuration),
+		TrustedProxies:         cfg.TrustedProxies,
+		Signer:                 signer,
+		Challenger:             challenge.NewChallenger(signer, cfg.PublicDashboards.ChallengeDifficulty),
+		GeoIP:                  geoip.ProvideProvider(cfg.PublicDashboards.GeoIPCountryHeader),
+		ExternalViewerHeader:   cfg.PublicDashboards.ExternalViewerHeaderName,
+		Bus:                    bus,
+		RateLimits:             rateLimits,
 		Log:                    log.New("publicdashboards.api"),
 	}
 
+	// external viewers can only be verified when JWT authentication is set up
+	if cfg.JWTAuthEnabled {
+		api.JWTService = jwtService
+	}
+
 	// attach api if PublicDashboards feature flag is enabled
 	if features.IsEnabled(featuremgmt.FlagPublicDashboards) {
 		api.RegisterAPIEndpoints()
@@ -60,8 +105,26 @@ func (api *Api) RegisterAPIEndpoints() {
 	// circular dependency
 
 	// public endpoints
-	api.RouteRegister.Get("/api/public/dashboards/:accessToken", routing.Wrap(api.GetPublicDashboard))
-	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboard))
+	rejectBlocked := RejectBlockedClients(api.AbuseDetector, api.TrustedProxies)
+	rateLimit := api.RateLimits.Middleware(ratelimit.PublicDashboards, ratelimit.ByClientIP(api.TrustedProxies))
+	robotsTag := SetRobotsTagHeader(api.PublicDashboardService)
+	rejectCountries := RejectRestrictedCountries(api.PublicDashboardService, api.GeoIP)
+	externalViewer := RequiresExternalViewer(api.PublicDashboardService, api.JWTService, api.ExternalViewerHeader, api.Log)
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken", rejectBlocked, rateLimit, rejectCountries, externalViewer, robotsTag, routing.Wrap(api.GetPublicDashboard))
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken/challenge", rejectBlocked, rateLimit, rejectCountries, externalViewer, robotsTag, routing.Wrap(api.GetChallenge))
+	api.RouteRegister.Post("/api/public/dashboards/:accessToken/challenge", rejectBlocked, rateLimit, rejectCountries, externalViewer, robotsTag, routing.Wrap(api.SolveChallenge))
+	api.RouteRegister.Post("/api/public/dashboards/:accessToken/terms/acknowledge", rejectBlocked, rateLimit, rejectCountries, externalViewer, robotsTag, routing.Wrap(api.AcknowledgeTermsOfUse))
+	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", rejectBlocked, rateLimit, rejectCountries, externalViewer, robotsTag,
+		RequiresChallengePass(api.PublicDashboardService, api.Challenger, api.TrustedProxies),
+		RequiresTermsAcknowledgment(api.PublicDashboardService, api.Signer),
+		routing.Wrap(api.QueryPublicDashboard))
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken/panels/:panelId/alert-state", rejectBlocked, rateLimit, rejectCountries, externalViewer, robotsTag,
+		RequiresChallengePass(api.PublicDashboardService, api.Challenger, api.TrustedProxies),
+		RequiresTermsAcknowledgment(api.PublicDashboardService, api.Signer),
+		routing.Wrap(api.GetPanelAlertStates))
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken/health", rejectBlocked, rateLimit, rejectCountries, externalViewer, robotsTag, routing.Wrap(api.GetPublicDashboardHealth))
+	// the country and external viewer restrictions of a public playlist are the ones of its public dashboards
+	api.RouteRegister.Get("/api/public/playlists/:accessToken", rejectBlocked, rateLimit, rejectCountries, externalViewer, robotsTag, routing.Wrap(api.GetPublicPlaylist))
 
 	// Create/Update Public Dashboard
 	uidScope := dashboards.ScopeDashboardsProvider.GetResourceScopeUID(accesscontrol.Parameter(":uid"))
@@ -73,6 +136,55 @@ func (api *Api) RegisterAPIEndpoints() {
 	api.RouteRegister.Post("/api/dashboards/uid/:uid/public-config",
 		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
 		routing.Wrap(api.SavePublicDashboardConfig))
+
+	api.RouteRegister.Post("/api/dashboards/uid/:uid/public-config/clone/:sourceUid",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalAll(
+			accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope),
+			accesscontrol.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(accesscontrol.Parameter(":sourceUid"))),
+		)),
+		routing.Wrap(api.ClonePublicDashboardConfig))
+
+	api.RouteRegister.Get("/api/dashboards/uid/:uid/public-config/preflight",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.GetPublicDashboardPreflight))
+
+	api.RouteRegister.Get("/api/dashboards/uid/:uid/public-config/history",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.GetPublicDashboardHistory))
+
+	// Scoped access tokens of a public dashboard
+	api.RouteRegister.Get("/api/dashboards/uid/:uid/public-config/tokens",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.GetScopedAccessTokens))
+
+	api.RouteRegister.Post("/api/dashboards/uid/:uid/public-config/tokens",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.CreateScopedAccessToken))
+
+	api.RouteRegister.Delete("/api/dashboards/uid/:uid/public-config/tokens/:tokenUid",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.DeleteScopedAccessToken))
+
+	// Create/Update Public Playlist
+	api.RouteRegister.Get("/api/playlists/:uid/public-config", middleware.ReqSignedIn, routing.Wrap(api.GetPublicPlaylistConfig))
+
+	api.RouteRegister.Post("/api/playlists/:uid/public-config",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite)),
+		routing.Wrap(api.SavePublicPlaylistConfig))
+
+	// Org wide public dashboards settings
+	api.RouteRegister.Get("/api/dashboards/public-settings",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite)),
+		routing.Wrap(api.GetOrgSettings))
+
+	api.RouteRegister.Post("/api/dashboards/public-settings",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite)),
+		routing.Wrap(api.SaveOrgSettings))
+
+	// Pull the public dashboards of a folder or tag from public view at once
+	api.RouteRegister.Post("/api/dashboards/public-config/bulk-disable",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite)),
+		routing.Wrap(api.BulkDisablePublicDashboards))
 }
 
 // Gets public dashboard
@@ -86,6 +198,7 @@ func (api *Api) GetPublicDashboard(c *models.ReqContext) response.Response {
 	)
 
 	if err != nil {
+		api.recordFailedLookup(c, err)
 		return api.handleError(http.StatusInternalServerError, "failed to get public dashboard", err)
 	}
 
@@ -104,11 +217,90 @@ func (api *Api) GetPublicDashboard(c *models.ReqContext) response.Response {
 		FolderId:                   dash.FolderId,
 		PublicDashboardAccessToken: pubdash.AccessToken,
 		PublicDashboardUID:         pubdash.Uid,
+		PublicDashboardTermsOfUse:  pubdash.TermsOfUse,
 	}
 
 	dto := dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}
 
-	return response.JSON(http.StatusOK, dto)
+	api.publishViewed(c, pubdash)
+
+	// the dashboard changes with either its own or the public dashboard
+	// configuration updates
+	lastModified := dash.Updated
+	if pubdash.UpdatedAt.After(lastModified) {
+		lastModified = pubdash.UpdatedAt
+	}
+
+	return conditionalJSON(c, http.StatusOK, dto, lastModified)
+}
+
+// Gets a proof-of-work challenge to solve before querying the public dashboard
+// GET /api/public/dashboards/:accessToken/challenge
+func (api *Api) GetChallenge(c *models.ReqContext) response.Response {
+	accessToken := web.Params(c.Req)[":accessToken"]
+
+	if _, err := api.PublicDashboardService.ChallengeRequired(c.Req.Context(), accessToken); err != nil {
+		api.recordFailedLookup(c, err)
+		return api.handleError(http.StatusInternalServerError, "failed to get public dashboard", err)
+	}
+
+	ch, err := api.Challenger.New(accessToken, network.ClientIP(c.Req, api.TrustedProxies))
+	if err != nil {
+		return api.handleError(http.StatusInternalServerError, "failed to create challenge", err)
+	}
+
+	return response.JSON(http.StatusOK, ch)
+}
+
+// Checks the solution of a challenge and sets the pass cookie letting the
+// client query the public dashboard for the rest of its session
+// POST /api/public/dashboards/:accessToken/challenge
+func (api *Api) SolveChallenge(c *models.ReqContext) response.Response {
+	accessToken := web.Params(c.Req)[":accessToken"]
+
+	dto := SolveChallengeDTO{}
+	if err := web.Bind(c.Req, &dto); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	clientIP := network.ClientIP(c.Req, api.TrustedProxies)
+	pass, err := api.Challenger.Solve(accessToken, clientIP, dto.Token, dto.Solution)
+	if err != nil {
+		api.AbuseDetector.RecordFailure(clientIP)
+		return api.handleError(http.StatusBadRequest, "failed to solve challenge", ErrPublicDashboardInvalidChallenge)
+	}
+
+	writePublicDashboardCookie(c, accessToken, ChallengePassCookieName, pass)
+
+	return response.Success("Challenge solved")
+}
+
+// Acknowledges the terms of use of the public dashboard and sets the
+// acknowledgment cookie letting the client query it for the rest of its
+// session
+// POST /api/public/dashboards/:accessToken/terms/acknowledge
+func (api *Api) AcknowledgeTermsOfUse(c *models.ReqContext) response.Response {
+	accessToken := web.Params(c.Req)[":accessToken"]
+
+	terms, err := api.PublicDashboardService.GetTermsOfUse(c.Req.Context(), accessToken)
+	if err != nil {
+		api.recordFailedLookup(c, err)
+		return api.handleError(http.StatusInternalServerError, "failed to get public dashboard", err)
+	}
+
+	writePublicDashboardCookie(c, accessToken, TermsAcknowledgmentCookieName, api.Signer.Sign(termsAcknowledgment(accessToken, terms)))
+
+	return response.Success("Terms of use acknowledged")
+}
+
+// writes a session cookie scoped to the api of the public dashboard, so that
+// the cookies of several public dashboards don't overwrite each other
+func writePublicDashboardCookie(c *models.ReqContext, accessToken string, name string, value string) {
+	cookies.WriteCookie(c.Resp, name, value, 0, func() cookies.CookieOptions {
+		options := cookies.NewCookieOptions()
+		options.Path = path.Join(options.Path, "/api/public/dashboards", accessToken)
+		return options
+	})
 }
 
 // Gets public dashboard configuration for dashboard
@@ -153,11 +345,201 @@ func (api *Api) SavePublicDashboardConfig(c *models.ReqContext) response.Respons
 	return response.JSON(http.StatusOK, pubdash)
 }
 
+// Clones the public dashboard configuration of the source dashboard onto the
+// dashboard, with a fresh access token
+// POST /api/dashboards/uid/:uid/public-config/clone/:sourceUid
+func (api *Api) ClonePublicDashboardConfig(c *models.ReqContext) response.Response {
+	dashboardUid := web.Params(c.Req)[":uid"]
+	sourceUid := web.Params(c.Req)[":sourceUid"]
+	if !util.IsValidShortUID(dashboardUid) || !util.IsValidShortUID(sourceUid) {
+		return api.handleError(http.StatusBadRequest, "invalid dashboard uid", dashboards.ErrDashboardIdentifierNotSet)
+	}
+
+	dto := ClonePublicDashboardConfigDTO{
+		SourceDashboardUid: sourceUid,
+		DashboardUid:       dashboardUid,
+		OrgId:              c.OrgID,
+		UserId:             c.UserID,
+	}
+
+	pubdash, err := api.PublicDashboardService.ClonePublicDashboardConfig(c.Req.Context(), c.SignedInUser, &dto)
+	if err != nil {
+		return api.handleError(http.StatusInternalServerError, "failed to clone public dashboard configuration", err)
+	}
+
+	return response.JSON(http.StatusOK, pubdash)
+}
+
+// Reports what would be unsupported or degraded if the dashboard was shared
+// publicly
+// GET /api/dashboards/uid/:uid/public-config/preflight
+func (api *Api) GetPublicDashboardPreflight(c *models.ReqContext) response.Response {
+	preflight, err := api.PublicDashboardService.GetPublicDashboardPreflight(c.Req.Context(), c.OrgID, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return api.handleError(http.StatusInternalServerError, "failed to check public dashboard support", err)
+	}
+	return response.JSON(http.StatusOK, preflight)
+}
+
+// Gets the configuration history of the public dashboard of a dashboard
+// GET /api/dashboards/uid/:uid/public-config/history
+func (api *Api) GetPublicDashboardHistory(c *models.ReqContext) response.Response {
+	history, err := api.PublicDashboardService.GetPublicDashboardHistory(c.Req.Context(), c.OrgID, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return api.handleError(http.StatusInternalServerError, "failed to get public dashboard history", err)
+	}
+	return response.JSON(http.StatusOK, history)
+}
+
+// Gets the scoped access tokens of the public dashboard of a dashboard
+// GET /api/dashboards/uid/:uid/public-config/tokens
+func (api *Api) GetScopedAccessTokens(c *models.ReqContext) response.Response {
+	scopedTokens, err := api.PublicDashboardService.GetScopedAccessTokens(c.Req.Context(), c.OrgID, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return api.handleError(http.StatusInternalServerError, "failed to get scoped access tokens", err)
+	}
+	return response.JSON(http.StatusOK, scopedTokens)
+}
+
+// Creates a scoped access token for the public dashboard of a dashboard
+// POST /api/dashboards/uid/:uid/public-config/tokens
+func (api *Api) CreateScopedAccessToken(c *models.ReqContext) response.Response {
+	scopedToken := &ScopedAccessToken{}
+	if err := web.Bind(c.Req, scopedToken); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	// Always set the orgID and userID from the session
+	dto := SaveScopedAccessTokenDTO{
+		UserId:            c.UserID,
+		OrgId:             c.OrgID,
+		DashboardUid:      web.Params(c.Req)[":uid"],
+		ScopedAccessToken: scopedToken,
+	}
+
+	scopedToken, err := api.PublicDashboardService.CreateScopedAccessToken(c.Req.Context(), c.SignedInUser, &dto)
+	if err != nil {
+		return api.handleError(http.StatusInternalServerError, "failed to create scoped access token", err)
+	}
+
+	return response.JSON(http.StatusOK, scopedToken)
+}
+
+// Revokes a scoped access token of the public dashboard of a dashboard
+// DELETE /api/dashboards/uid/:uid/public-config/tokens/:tokenUid
+func (api *Api) DeleteScopedAccessToken(c *models.ReqContext) response.Response {
+	err := api.PublicDashboardService.DeleteScopedAccessToken(c.Req.Context(), c.OrgID, web.Params(c.Req)[":uid"], web.Params(c.Req)[":tokenUid"])
+	if err != nil {
+		return api.handleError(http.StatusInternalServerError, "failed to delete scoped access token", err)
+	}
+
+	return response.Success("Scoped access token deleted")
+}
+
+// Gets public playlist configuration for playlist
+// GET /api/playlists/:uid/public-config
+func (api *Api) GetPublicPlaylistConfig(c *models.ReqContext) response.Response {
+	ppc, err := api.PublicDashboardService.GetPublicPlaylistConfig(c.Req.Context(), c.OrgID, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return api.handleError(http.StatusInternalServerError, "failed to get public playlist config", err)
+	}
+	return response.JSON(http.StatusOK, ppc)
+}
+
+// Sets public playlist configuration for playlist
+// POST /api/playlists/:uid/public-config
+func (api *Api) SavePublicPlaylistConfig(c *models.ReqContext) response.Response {
+	pubplaylist := &PublicPlaylist{}
+	if err := web.Bind(c.Req, pubplaylist); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	// Always set the orgID and userID from the session
+	pubplaylist.OrgId = c.OrgID
+	dto := SavePublicPlaylistConfigDTO{
+		UserId:         c.UserID,
+		OrgId:          c.OrgID,
+		PlaylistUid:    web.Params(c.Req)[":uid"],
+		PublicPlaylist: pubplaylist,
+	}
+
+	pubplaylist, err := api.PublicDashboardService.SavePublicPlaylistConfig(c.Req.Context(), c.SignedInUser, &dto)
+	if err != nil {
+		return api.handleError(http.StatusInternalServerError, "failed to save public playlist configuration", err)
+	}
+
+	return response.JSON(http.StatusOK, pubplaylist)
+}
+
+// Gets public playlist
+// GET /api/public/playlists/:accessToken
+func (api *Api) GetPublicPlaylist(c *models.ReqContext) response.Response {
+	pubplaylist, err := api.PublicDashboardService.GetPublicPlaylist(c.Req.Context(), web.Params(c.Req)[":accessToken"])
+	if err != nil {
+		api.recordFailedLookup(c, err)
+		return api.handleError(http.StatusInternalServerError, "failed to get public playlist", err)
+	}
+
+	return response.JSON(http.StatusOK, pubplaylist)
+}
+
+// Gets the public dashboards settings of the org
+// GET /api/dashboards/public-settings
+func (api *Api) GetOrgSettings(c *models.ReqContext) response.Response {
+	settings, err := api.PublicDashboardService.GetOrgSettings(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return api.handleError(http.StatusInternalServerError, "failed to get public dashboards settings", err)
+	}
+	return response.JSON(http.StatusOK, settings)
+}
+
+// Sets the public dashboards settings of the org
+// POST /api/dashboards/public-settings
+func (api *Api) SaveOrgSettings(c *models.ReqContext) response.Response {
+	settings := &OrgSettings{}
+	if err := web.Bind(c.Req, settings); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	dto := SaveOrgSettingsDTO{
+		OrgId:    c.OrgID,
+		UserId:   c.UserID,
+		Settings: settings,
+	}
+
+	settings, err := api.PublicDashboardService.SaveOrgSettings(c.Req.Context(), &dto)
+	if err != nil {
+		return api.handleError(http.StatusInternalServerError, "failed to save public dashboards settings", err)
+	}
+
+	return response.JSON(http.StatusOK, settings)
+}
+
+// Disables, or deletes, the public dashboards of every dashboard in a folder
+// or with a tag
+// POST /api/dashboards/public-config/bulk-disable
+func (api *Api) BulkDisablePublicDashboards(c *models.ReqContext) response.Response {
+	dto := &BulkDisablePublicDashboardsDTO{}
+	if err := web.Bind(c.Req, dto); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	dto.OrgId = c.OrgID
+	dto.UserId = c.UserID
+
+	result, err := api.PublicDashboardService.BulkDisablePublicDashboards(c.Req.Context(), c.SignedInUser, dto)
+	if err != nil {
+		return api.handleError(http.StatusInternalServerError, "failed to disable public dashboards", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
 // QueryPublicDashboard returns all results for a given panel on a public dashboard
 // POST /api/public/dashboard/:accessToken/panels/:panelId/query
 func (api *Api) QueryPublicDashboard(c *models.ReqContext) response.Response {
 	panelId, err := strconv.ParseInt(web.Params(c.Req)[":panelId"], 10, 64)
 	if err != nil {
+		api.AbuseDetector.RecordFailure(network.ClientIP(c.Req, api.TrustedProxies))
 		return response.Error(http.StatusBadRequest, "invalid panel ID", err)
 	}
 
@@ -166,14 +548,106 @@ func (api *Api) QueryPublicDashboard(c *models.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
-	resp, err := api.PublicDashboardService.GetQueryDataResponse(c.Req.Context(), c.SkipCache, reqDTO, panelId, web.Params(c.Req)[":accessToken"])
+	// pre-rendered responses are served regardless of the requested interval
+	// and cache skipping, so that traffic spikes don't reach the data sources
+	prerendered, err := api.PublicDashboardService.GetPrerenderedQueryDataResponse(c.Req.Context(), panelId, web.Params(c.Req)[":accessToken"])
 	if err != nil {
+		api.recordFailedLookup(c, err)
 		return api.handleError(http.StatusInternalServerError, "error running public dashboard panel queries", err)
 	}
+	if prerendered != nil {
+		c.Resp.Header().Set(requestid.HeaderName, prerendered.RequestID)
+		c.Resp.Header().Set(PrerenderedAtHeaderName, prerendered.RenderedAt.UTC().Format(http.TimeFormat))
+		c.Resp.Header().Set("Age", strconv.FormatInt(int64(time.Since(prerendered.RenderedAt).Seconds()), 10))
+		// a pre-rendered response is identified by the request ID it was
+		// rendered with, so it doesn't need to be encoded to be validated
+		if notModified(c, `"`+prerendered.RequestID+`"`, prerendered.RenderedAt) {
+			return notModifiedResponse()
+		}
+		return toJsonStreamingResponse(api.Features, prerendered.Response)
+	}
+
+	// the request ID is returned even when the query fails, so that viewers
+	// can report it for blank panels
+	requestID := requestid.New()
+	c.Resp.Header().Set(requestid.HeaderName, requestID)
+	ctx := requestid.WithRequestID(c.Req.Context(), requestID)
+
+	resp, err := api.PublicDashboardService.GetQueryDataResponse(ctx, c.SkipCache, reqDTO, panelId, web.Params(c.Req)[":accessToken"])
+	if err != nil {
+		api.recordFailedLookup(c, err)
+		return api.handleError(http.StatusInternalServerError, "error running public dashboard panel queries", err)
+	}
+
+	// failed queries are streamed as before since they are never cached
+	if status := queryDataResponseStatus(api.Features, resp); status == http.StatusOK {
+		return conditionalJSON(c, status, resp, time.Time{})
+	}
 
 	return toJsonStreamingResponse(api.Features, resp)
 }
 
+// Gets the alert state counts of a public dashboard panel
+// GET /api/public/dashboards/:accessToken/panels/:panelId/alert-state
+func (api *Api) GetPanelAlertStates(c *models.ReqContext) response.Response {
+	panelId, err := strconv.ParseInt(web.Params(c.Req)[":panelId"], 10, 64)
+	if err != nil {
+		api.AbuseDetector.RecordFailure(network.ClientIP(c.Req, api.TrustedProxies))
+		return response.Error(http.StatusBadRequest, "invalid panel ID", err)
+	}
+
+	states, err := api.PublicDashboardService.GetPanelAlertStates(c.Req.Context(), web.Params(c.Req)[":accessToken"], panelId)
+	if err != nil {
+		api.recordFailedLookup(c, err)
+		return api.handleError(http.StatusInternalServerError, "failed to get public dashboard panel alert state", err)
+	}
+
+	return response.JSON(http.StatusOK, states)
+}
+
+// Gets the health of a public dashboard, responds with 503 when the public
+// dashboard is disabled, expired or any of its data sources is unreachable so
+// that external monitoring can alert on it
+// GET /api/public/dashboards/:accessToken/health
+func (api *Api) GetPublicDashboardHealth(c *models.ReqContext) response.Response {
+	health, err := api.PublicDashboardService.GetPublicDashboardHealth(c.Req.Context(), web.Params(c.Req)[":accessToken"])
+	if err != nil {
+		api.recordFailedLookup(c, err)
+		return api.handleError(http.StatusInternalServerError, "failed to get public dashboard health", err)
+	}
+
+	if !health.Healthy {
+		return response.JSON(http.StatusServiceUnavailable, health)
+	}
+
+	return response.JSON(http.StatusOK, health)
+}
+
+// records a failure for the client when the request looked up an access token
+// or panel that doesn't exist, so that clients probing for them get blocked
+func (api *Api) recordFailedLookup(c *models.ReqContext, err error) {
+	if errors.Is(err, ErrPublicDashboardNotFound) || errors.Is(err, ErrPublicDashboardPanelNotFound) || errors.Is(err, ErrPublicPlaylistNotFound) {
+		api.AbuseDetector.RecordFailure(network.ClientIP(c.Req, api.TrustedProxies))
+	}
+}
+
+// publishes the view of the public dashboard for usage insights. Views aren't
+// published when the api was built without a bus.
+func (api *Api) publishViewed(c *models.ReqContext, pubdash *PublicDashboard) {
+	if api.Bus == nil {
+		return
+	}
+	err := api.Bus.Publish(c.Req.Context(), &events.PublicDashboardViewed{
+		Timestamp:    time.Now(),
+		UID:          pubdash.Uid,
+		DashboardUID: pubdash.DashboardUid,
+		OrgID:        pubdash.OrgId,
+	})
+	if err != nil {
+		api.Log.Warn("failed to publish public dashboard view", "uid", pubdash.Uid, "error", err)
+	}
+}
+
 // util to help us unpack dashboard and publicdashboard errors or use default http code and message
 // we should look to do some future refactoring of these errors as publicdashboard err is the same as a dashboarderr, just defined in a
 // different package.
@@ -184,7 +658,7 @@ func (api *Api) handleError(code int, message string, err error) response.Respon
 
 	// handle public dashboard error
 	if ok := errors.As(err, &publicDashboardErr); ok {
-		return response.Error(publicDashboardErr.StatusCode, publicDashboardErr.Error(), publicDashboardErr)
+		return response.Err(publicDashboardErr)
 	}
 
 	// handle dashboard errors as well
@@ -198,6 +672,12 @@ func (api *Api) handleError(code int, message string, err error) response.Respon
 
 // Copied from pkg/api/metrics.go
 func toJsonStreamingResponse(features *featuremgmt.FeatureManager, qdr *backend.QueryDataResponse) response.Response {
+	return response.JSONStreaming(queryDataResponseStatus(features, qdr), qdr)
+}
+
+// queryDataResponseStatus returns the status code of a query data response,
+// which isn't 200 when any of its queries failed
+func queryDataResponseStatus(features *featuremgmt.FeatureManager, qdr *backend.QueryDataResponse) int {
 	statusWhenError := http.StatusBadRequest
 	if features.IsEnabled(featuremgmt.FlagDatasourceQueryMultiStatus) {
 		statusWhenError = http.StatusMultiStatus
@@ -210,5 +690,5 @@ func toJsonStreamingResponse(features *featuremgmt.FeatureManager, qdr *backend.
 		}
 	}
 
-	return response.JSONStreaming(statusCode, qdr)
+	return statusCode
 }
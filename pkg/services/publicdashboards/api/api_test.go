@@ -1,13 +1,18 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -18,25 +23,41 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/jobs/jobstest"
 	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	dashboardStore "github.com/grafana/grafana/pkg/services/dashboards/database"
+	"github.com/grafana/grafana/pkg/services/dashboardversion/dashverimpl"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	datasourcesService "github.com/grafana/grafana/pkg/services/datasources/service"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/librarypanels"
+	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/playlist/playlistimpl"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
 
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	publicdashboardsStore "github.com/grafana/grafana/pkg/services/publicdashboards/database"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/requestid"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	publicdashboardsService "github.com/grafana/grafana/pkg/services/publicdashboards/service"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/harness"
+	"github.com/grafana/grafana/pkg/util/errutil"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -138,15 +159,75 @@ func TestAPIGetPublicDashboard(t *testing.T) {
 				assert.Equal(t, false, dashResp.Meta.CanDelete)
 				assert.Equal(t, false, dashResp.Meta.CanSave)
 			} else {
-				var errResp struct {
-					Error string `json:"error"`
-				}
+				var errResp errutil.PublicError
 				err := json.Unmarshal(response.Body.Bytes(), &errResp)
 				require.NoError(t, err)
-				assert.Equal(t, test.Err.Error(), errResp.Error)
+				assert.Equal(t, ErrPublicDashboardNotFound.Public(), errResp)
 			}
 		})
 	}
+
+	t.Run("It responds 304 when the public dashboard wasn't modified", func(t *testing.T) {
+		updated := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("GetPublicDashboard", mock.Anything, mock.AnythingOfType("string")).
+			Return(&PublicDashboard{UpdatedAt: updated.Add(-time.Hour)}, &models.Dashboard{Updated: updated, Data: simplejson.New()}, nil)
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+		testServer := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser)
+
+		response := callAPI(testServer, http.MethodGet, "/api/public/dashboards/"+accessToken, nil, t)
+		require.Equal(t, http.StatusOK, response.Code)
+		etag := response.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+		assert.Equal(t, updated.Format(http.TimeFormat), response.Header().Get("Last-Modified"))
+
+		conditionalHeaders := []http.Header{
+			{"If-None-Match": []string{etag}},
+			{"If-None-Match": []string{`"other", W/` + etag}},
+			{"If-Modified-Since": []string{updated.Format(http.TimeFormat)}},
+		}
+		for _, header := range conditionalHeaders {
+			response = callConditionalAPI(testServer, http.MethodGet, "/api/public/dashboards/"+accessToken, header, nil, t)
+			assert.Equal(t, http.StatusNotModified, response.Code)
+			assert.Empty(t, response.Body.String())
+		}
+
+		modifiedHeaders := []http.Header{
+			{"If-None-Match": []string{`"other"`}},
+			{"If-Modified-Since": []string{updated.Add(-time.Second).Format(http.TimeFormat)}},
+			// If-None-Match takes precedence over If-Modified-Since
+			{"If-None-Match": []string{`"other"`}, "If-Modified-Since": []string{updated.Format(http.TimeFormat)}},
+		}
+		for _, header := range modifiedHeaders {
+			response = callConditionalAPI(testServer, http.MethodGet, "/api/public/dashboards/"+accessToken, header, nil, t)
+			assert.Equal(t, http.StatusOK, response.Code)
+			assert.NotEmpty(t, response.Body.String())
+		}
+	})
+
+	t.Run("It publishes the view of the public dashboard", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("GetPublicDashboard", mock.Anything, mock.AnythingOfType("string")).
+			Return(&PublicDashboard{Uid: "pubdash-uid", DashboardUid: DashboardUid, OrgId: 1}, &models.Dashboard{Data: simplejson.New()}, nil)
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+
+		var viewed []*events.PublicDashboardViewed
+		eventBus := bus.ProvideBus(tracing.InitializeTracerForTest())
+		eventBus.AddEventListener(func(_ context.Context, e *events.PublicDashboardViewed) error {
+			viewed = append(viewed, e)
+			return nil
+		})
+		testServer := setupTestServerWithBus(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser, eventBus)
+
+		response := callAPI(testServer, http.MethodGet, "/api/public/dashboards/"+accessToken, nil, t)
+		require.Equal(t, http.StatusOK, response.Code)
+		require.Len(t, viewed, 1)
+		assert.Equal(t, "pubdash-uid", viewed[0].UID)
+		assert.Equal(t, DashboardUid, viewed[0].DashboardUID)
+		assert.Equal(t, int64(1), viewed[0].OrgID)
+	})
 }
 
 func TestAPIGetPublicDashboardConfig(t *testing.T) {
@@ -365,7 +446,642 @@ func TestApiSavePublicDashboardConfig(t *testing.T) {
 	}
 }
 
+func TestAPISaveOrgSettings(t *testing.T) {
+	testCases := []struct {
+		Name                 string
+		User                 *user.SignedInUser
+		AccessControlEnabled bool
+		ShouldCallService    bool
+		ExpectedHttpResponse int
+	}{
+		{
+			Name:                 "returns 200 when settings are saved",
+			User:                 userAdmin,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 200 when settings are saved RBAC on",
+			User:                 userAdminRBAC,
+			AccessControlEnabled: true,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 403 when no permissions",
+			User:                 userViewer,
+			ExpectedHttpResponse: http.StatusForbidden,
+		},
+		{
+			Name:                 "returns 403 when no permissions RBAC on",
+			User:                 userViewerRBAC,
+			AccessControlEnabled: true,
+			ExpectedHttpResponse: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			settings := &OrgSettings{AllowedDatasources: &DatasourceAllowlist{Uids: []string{"ds1"}}}
+			service := publicdashboards.NewFakePublicDashboardService(t)
+			if test.ShouldCallService {
+				service.On("SaveOrgSettings", mock.Anything, mock.AnythingOfType("*models.SaveOrgSettingsDTO")).
+					Return(settings, nil)
+			}
+
+			cfg := setting.NewCfg()
+			cfg.RBACEnabled = test.AccessControlEnabled
+
+			testServer := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, test.User)
+
+			response := callAPI(testServer, http.MethodPost, "/api/dashboards/public-settings",
+				strings.NewReader(`{ "allowedDatasources": { "uids": ["ds1"] } }`), t)
+			assert.Equal(t, test.ExpectedHttpResponse, response.Code)
+
+			if response.Code == http.StatusOK {
+				val, err := json.Marshal(settings)
+				require.NoError(t, err)
+				assert.Equal(t, string(val), response.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIBulkDisablePublicDashboards(t *testing.T) {
+	testCases := []struct {
+		Name                 string
+		User                 *user.SignedInUser
+		AccessControlEnabled bool
+		ServiceErr           error
+		ShouldCallService    bool
+		ExpectedHttpResponse int
+	}{
+		{
+			Name:                 "returns 200 when public dashboards are disabled",
+			User:                 userAdmin,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 200 when public dashboards are disabled RBAC on",
+			User:                 userAdminRBAC,
+			AccessControlEnabled: true,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 400 when neither a folder nor a tag is set",
+			User:                 userAdmin,
+			ServiceErr:           ErrPublicDashboardBulkSelectorRequired,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusBadRequest,
+		},
+		{
+			Name:                 "returns 403 when no permissions",
+			User:                 userViewer,
+			ExpectedHttpResponse: http.StatusForbidden,
+		},
+		{
+			Name:                 "returns 403 when no permissions RBAC on",
+			User:                 userViewerRBAC,
+			AccessControlEnabled: true,
+			ExpectedHttpResponse: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			service := publicdashboards.NewFakePublicDashboardService(t)
+			if test.ShouldCallService {
+				var result *BulkDisablePublicDashboardsResult
+				if test.ServiceErr == nil {
+					result = &BulkDisablePublicDashboardsResult{DashboardUids: []string{"dash1", "dash2"}}
+				}
+				service.On("BulkDisablePublicDashboards", mock.Anything, mock.Anything, &BulkDisablePublicDashboardsDTO{
+					Tag:    "payments",
+					Delete: true,
+					OrgId:  1,
+					UserId: test.User.UserID,
+				}).Return(result, test.ServiceErr)
+			}
+
+			cfg := setting.NewCfg()
+			cfg.RBACEnabled = test.AccessControlEnabled
+
+			testServer := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, test.User)
+
+			response := callAPI(testServer, http.MethodPost, "/api/dashboards/public-config/bulk-disable",
+				strings.NewReader(`{ "tag": "payments", "delete": true }`), t)
+			assert.Equal(t, test.ExpectedHttpResponse, response.Code)
+
+			if response.Code == http.StatusOK {
+				assert.JSONEq(t, `{"dashboardUids":["dash1","dash2"]}`, response.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPISavePublicPlaylistConfig(t *testing.T) {
+	testCases := []struct {
+		Name                 string
+		User                 *user.SignedInUser
+		AccessControlEnabled bool
+		ShouldCallService    bool
+		ExpectedHttpResponse int
+	}{
+		{
+			Name:                 "returns 200 when public playlist is saved",
+			User:                 userAdmin,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 200 when public playlist is saved RBAC on",
+			User:                 userAdminRBAC,
+			AccessControlEnabled: true,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 403 when no permissions",
+			User:                 userViewer,
+			ExpectedHttpResponse: http.StatusForbidden,
+		},
+		{
+			Name:                 "returns 403 when no permissions RBAC on",
+			User:                 userViewerRBAC,
+			AccessControlEnabled: true,
+			ExpectedHttpResponse: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			pubplaylist := &PublicPlaylist{Uid: "pubplaylist", PlaylistUid: "playlist1", IsEnabled: true, AccessToken: validAccessToken}
+			service := publicdashboards.NewFakePublicDashboardService(t)
+			if test.ShouldCallService {
+				service.On("SavePublicPlaylistConfig", mock.Anything, mock.Anything, mock.AnythingOfType("*models.SavePublicPlaylistConfigDTO")).
+					Return(pubplaylist, nil)
+			}
+
+			cfg := setting.NewCfg()
+			cfg.RBACEnabled = test.AccessControlEnabled
+
+			testServer := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, test.User)
+
+			response := callAPI(testServer, http.MethodPost, "/api/playlists/playlist1/public-config", strings.NewReader(`{ "isEnabled": true }`), t)
+			assert.Equal(t, test.ExpectedHttpResponse, response.Code)
+
+			if response.Code == http.StatusOK {
+				val, err := json.Marshal(pubplaylist)
+				require.NoError(t, err)
+				assert.Equal(t, string(val), response.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIGetPublicDashboardPreflight(t *testing.T) {
+	preflight := &PublicDashboardPreflight{
+		Shareable: true,
+		Issues: []PreflightIssue{
+			{PanelId: 2, PanelTitle: "Logs", Kind: PreflightIssueUnsupportedDatasource, Severity: PreflightSeverityUnsupported, Message: "Data source loki not found"},
+		},
+	}
+
+	testCases := []struct {
+		Name                 string
+		User                 *user.SignedInUser
+		AccessControlEnabled bool
+		ShouldCallService    bool
+		ExpectedHttpResponse int
+	}{
+		{
+			Name:                 "returns 200 with the preflight report",
+			User:                 userAdmin,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 200 with the preflight report RBAC on",
+			User:                 userAdminRBAC,
+			AccessControlEnabled: true,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 403 when no permissions",
+			User:                 userViewer,
+			ExpectedHttpResponse: http.StatusForbidden,
+		},
+		{
+			Name:                 "returns 403 when no permissions RBAC on",
+			User:                 userViewerRBAC,
+			AccessControlEnabled: true,
+			ExpectedHttpResponse: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			service := publicdashboards.NewFakePublicDashboardService(t)
+			if test.ShouldCallService {
+				service.On("GetPublicDashboardPreflight", mock.Anything, int64(1), "1").Return(preflight, nil)
+			}
+
+			cfg := setting.NewCfg()
+			cfg.RBACEnabled = test.AccessControlEnabled
+
+			testServer := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, test.User)
+
+			response := callAPI(testServer, http.MethodGet, "/api/dashboards/uid/1/public-config/preflight", nil, t)
+			assert.Equal(t, test.ExpectedHttpResponse, response.Code)
+
+			if response.Code == http.StatusOK {
+				val, err := json.Marshal(preflight)
+				require.NoError(t, err)
+				assert.JSONEq(t, string(val), response.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIClonePublicDashboardConfig(t *testing.T) {
+	pubdash := &PublicDashboard{Uid: "pubdash2", DashboardUid: "copy", IsEnabled: true, AccessToken: validAccessToken}
+	userAdminRBACWithRead := &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleAdmin, Login: "testAdminUserRBAC", Permissions: map[int64]map[string][]string{1: {
+		dashboards.ActionDashboardsPublicWrite: {dashboards.ScopeDashboardsAll},
+		dashboards.ActionDashboardsRead:        {dashboards.ScopeDashboardsAll},
+	}}}
+
+	testCases := []struct {
+		Name                 string
+		User                 *user.SignedInUser
+		AccessControlEnabled bool
+		ServiceErr           error
+		ShouldCallService    bool
+		ExpectedHttpResponse int
+	}{
+		{
+			Name:                 "returns 200 when public dashboard is cloned",
+			User:                 userAdmin,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 200 when public dashboard is cloned RBAC on",
+			User:                 userAdminRBACWithRead,
+			AccessControlEnabled: true,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 403 when the source dashboard can't be read RBAC on",
+			User:                 userAdminRBAC,
+			AccessControlEnabled: true,
+			ExpectedHttpResponse: http.StatusForbidden,
+		},
+		{
+			Name:                 "returns 403 when no permissions",
+			User:                 userViewer,
+			ExpectedHttpResponse: http.StatusForbidden,
+		},
+		{
+			Name:                 "returns 409 when the dashboard already has a public dashboard",
+			User:                 userAdmin,
+			ServiceErr:           ErrPublicDashboardAlreadyExists,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusConflict,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			service := publicdashboards.NewFakePublicDashboardService(t)
+			if test.ShouldCallService {
+				var result *PublicDashboard
+				if test.ServiceErr == nil {
+					result = pubdash
+				}
+				service.On("ClonePublicDashboardConfig", mock.Anything, mock.Anything, &ClonePublicDashboardConfigDTO{
+					SourceDashboardUid: "original",
+					DashboardUid:       "copy",
+					OrgId:              1,
+					UserId:             test.User.UserID,
+				}).Return(result, test.ServiceErr)
+			}
+
+			cfg := setting.NewCfg()
+			cfg.RBACEnabled = test.AccessControlEnabled
+
+			testServer := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, test.User)
+
+			response := callAPI(testServer, http.MethodPost, "/api/dashboards/uid/copy/public-config/clone/original", nil, t)
+			assert.Equal(t, test.ExpectedHttpResponse, response.Code)
+
+			if response.Code == http.StatusOK {
+				val, err := json.Marshal(pubdash)
+				require.NoError(t, err)
+				assert.Equal(t, string(val), response.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIScopedAccessTokens(t *testing.T) {
+	scopedToken := &ScopedAccessToken{Uid: "scoped1", PublicDashboardUid: "pubdash1", Name: "auditors", AccessToken: validAccessToken, PanelIds: PanelIds{2}}
+
+	testCases := []struct {
+		Name                 string
+		User                 *user.SignedInUser
+		AccessControlEnabled bool
+		ShouldCallService    bool
+		ExpectedHttpResponse int
+	}{
+		{
+			Name:                 "returns 200 when scoped access token is created",
+			User:                 userAdmin,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 200 when scoped access token is created RBAC on",
+			User:                 userAdminRBAC,
+			AccessControlEnabled: true,
+			ShouldCallService:    true,
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 403 when no permissions",
+			User:                 userViewer,
+			ExpectedHttpResponse: http.StatusForbidden,
+		},
+		{
+			Name:                 "returns 403 when no permissions RBAC on",
+			User:                 userViewerRBAC,
+			AccessControlEnabled: true,
+			ExpectedHttpResponse: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			service := publicdashboards.NewFakePublicDashboardService(t)
+			if test.ShouldCallService {
+				service.On("CreateScopedAccessToken", mock.Anything, mock.Anything, mock.AnythingOfType("*models.SaveScopedAccessTokenDTO")).
+					Return(scopedToken, nil)
+			}
+
+			cfg := setting.NewCfg()
+			cfg.RBACEnabled = test.AccessControlEnabled
+
+			testServer := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, test.User)
+
+			response := callAPI(testServer, http.MethodPost, "/api/dashboards/uid/1/public-config/tokens", strings.NewReader(`{ "name": "auditors", "panelIds": [2] }`), t)
+			assert.Equal(t, test.ExpectedHttpResponse, response.Code)
+
+			if response.Code == http.StatusOK {
+				val, err := json.Marshal(scopedToken)
+				require.NoError(t, err)
+				assert.Equal(t, string(val), response.Body.String())
+			}
+		})
+	}
+
+	t.Run("lists scoped access tokens", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("GetScopedAccessTokens", mock.Anything, userAdmin.OrgID, "1").Return([]*ScopedAccessToken{scopedToken}, nil)
+
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+
+		testServer := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, userAdmin)
+
+		response := callAPI(testServer, http.MethodGet, "/api/dashboards/uid/1/public-config/tokens", nil, t)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		val, err := json.Marshal([]*ScopedAccessToken{scopedToken})
+		require.NoError(t, err)
+		assert.Equal(t, string(val), response.Body.String())
+	})
+
+	t.Run("deletes a scoped access token", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("DeleteScopedAccessToken", mock.Anything, userAdmin.OrgID, "1", "scoped1").Return(nil)
+		service.On("DeleteScopedAccessToken", mock.Anything, userAdmin.OrgID, "1", "unknown").Return(ErrScopedAccessTokenNotFound)
+
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+
+		testServer := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, userAdmin)
+
+		response := callAPI(testServer, http.MethodDelete, "/api/dashboards/uid/1/public-config/tokens/scoped1", nil, t)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		response = callAPI(testServer, http.MethodDelete, "/api/dashboards/uid/1/public-config/tokens/unknown", nil, t)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestAPIGetPublicPlaylist(t *testing.T) {
+	setup := func(countryAllowed bool, externalViewerRequired bool) (*web.Mux, *publicdashboards.FakePublicDashboardService) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("IndexingAllowed", mock.Anything, validAccessToken).Return(false, nil).Maybe()
+		service.On("CountryAllowed", mock.Anything, validAccessToken, "").Return(countryAllowed, nil).Maybe()
+		service.On("ExternalViewerRequired", mock.Anything, validAccessToken).Return(externalViewerRequired, nil).Maybe()
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+
+		return setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser), service
+	}
+
+	t.Run("Returns the public playlist", func(t *testing.T) {
+		server, service := setup(true, false)
+		service.On("GetPublicPlaylist", mock.Anything, validAccessToken).Return(&PublicPlaylistDTO{
+			Name:     "NOC",
+			Interval: "5m",
+			Items:    []*PublicPlaylistItem{{AccessToken: "token", Title: "First"}},
+		}, nil)
+
+		resp := callAPI(server, http.MethodGet, "/api/public/playlists/"+validAccessToken, nil, t)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.JSONEq(t, `{"name":"NOC","interval":"5m","items":[{"accessToken":"token","title":"First"}]}`, resp.Body.String())
+		assert.Equal(t, "noindex, nofollow", resp.Header().Get("X-Robots-Tag"))
+	})
+
+	t.Run("Returns 404 when the public playlist is not found", func(t *testing.T) {
+		server, service := setup(true, false)
+		service.On("GetPublicPlaylist", mock.Anything, validAccessToken).Return(nil, ErrPublicPlaylistNotFound)
+
+		resp := callAPI(server, http.MethodGet, "/api/public/playlists/"+validAccessToken, nil, t)
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("Returns 451 when the public playlist is restricted in the country", func(t *testing.T) {
+		server, _ := setup(false, false)
+
+		resp := callAPI(server, http.MethodGet, "/api/public/playlists/"+validAccessToken, nil, t)
+		require.Equal(t, http.StatusUnavailableForLegalReasons, resp.Code)
+	})
+
+	t.Run("Returns 401 when a public dashboard of the playlist requires an external viewer", func(t *testing.T) {
+		server, _ := setup(true, true)
+
+		resp := callAPI(server, http.MethodGet, "/api/public/playlists/"+validAccessToken, nil, t)
+		require.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+}
+
 // `/public/dashboards/:uid/query“ endpoint test
+func TestAPIPublicDashboardChallenge(t *testing.T) {
+	setup := func(t *testing.T) *web.Mux {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("ChallengeRequired", mock.Anything, validAccessToken).Return(true, nil).Maybe()
+		service.On("GetTermsOfUse", mock.Anything, validAccessToken).Return("", nil).Maybe()
+		service.On("IndexingAllowed", mock.Anything, validAccessToken).Return(false, nil)
+		service.On("CountryAllowed", mock.Anything, validAccessToken, "").Return(true, nil)
+		service.On("ExternalViewerRequired", mock.Anything, validAccessToken).Return(false, nil)
+		service.On("GetPrerenderedQueryDataResponse", mock.Anything, int64(2), validAccessToken).Return(nil, nil).Maybe()
+		service.On("GetQueryDataResponse", mock.Anything, true, mock.Anything, int64(2), validAccessToken).
+			Return(&backend.QueryDataResponse{}, nil).Maybe()
+
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+		cfg.PublicDashboards.ChallengeDifficulty = 4
+
+		return setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser)
+	}
+
+	queryPath := fmt.Sprintf("/api/public/dashboards/%s/panels/2/query", validAccessToken)
+	challengePath := fmt.Sprintf("/api/public/dashboards/%s/challenge", validAccessToken)
+
+	t.Run("Returns 403 when querying without solving the challenge", func(t *testing.T) {
+		server := setup(t)
+		resp := callAPI(server, http.MethodPost, queryPath, strings.NewReader("{}"), t)
+		require.Equal(t, http.StatusForbidden, resp.Code)
+		require.JSONEq(t, `{"statusCode":403,"messageId":"publicdashboards.challengeRequired","message":"challenge required"}`, resp.Body.String())
+	})
+
+	t.Run("Returns 400 for an invalid challenge", func(t *testing.T) {
+		server := setup(t)
+		resp := callAPI(server, http.MethodPost, challengePath, strings.NewReader(`{"token":"invalid","solution":"1"}`), t)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("Can query after solving the challenge", func(t *testing.T) {
+		server := setup(t)
+		resp := callAPI(server, http.MethodGet, challengePath, nil, t)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var ch struct {
+			Token      string `json:"token"`
+			Difficulty int    `json:"difficulty"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &ch))
+		require.Equal(t, 4, ch.Difficulty)
+
+		solution := 0
+		for ; ; solution++ {
+			sum := sha256.Sum256([]byte(ch.Token + strconv.Itoa(solution)))
+			if sum[0]>>4 == 0 {
+				break
+			}
+		}
+
+		body, err := json.Marshal(SolveChallengeDTO{Token: ch.Token, Solution: strconv.Itoa(solution)})
+		require.NoError(t, err)
+		resp = callAPI(server, http.MethodPost, challengePath, bytes.NewReader(body), t)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		cookies := resp.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, ChallengePassCookieName, cookies[0].Name)
+		assert.Equal(t, "/api/public/dashboards/"+validAccessToken, cookies[0].Path)
+
+		req, err := http.NewRequest(http.MethodPost, queryPath, strings.NewReader("{}"))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(cookies[0])
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		// the pass is only valid for the client that solved the challenge
+		req, err = http.NewRequest(http.MethodPost, queryPath, strings.NewReader("{}"))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "203.0.113.1:5000"
+		req.AddCookie(cookies[0])
+		recorder = httptest.NewRecorder()
+		server.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+}
+
+func TestAPIPublicDashboardTermsOfUse(t *testing.T) {
+	setup := func(t *testing.T, terms *string) *web.Mux {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("ChallengeRequired", mock.Anything, validAccessToken).Return(false, nil).Maybe()
+		service.On("GetTermsOfUse", mock.Anything, validAccessToken).Return(func(context.Context, string) string { return *terms }, nil)
+		service.On("IndexingAllowed", mock.Anything, validAccessToken).Return(false, nil)
+		service.On("CountryAllowed", mock.Anything, validAccessToken, "").Return(true, nil)
+		service.On("ExternalViewerRequired", mock.Anything, validAccessToken).Return(false, nil)
+		service.On("GetPrerenderedQueryDataResponse", mock.Anything, int64(2), validAccessToken).Return(nil, nil).Maybe()
+		service.On("GetQueryDataResponse", mock.Anything, true, mock.Anything, int64(2), validAccessToken).
+			Return(&backend.QueryDataResponse{}, nil).Maybe()
+
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+
+		return setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser)
+	}
+
+	queryPath := fmt.Sprintf("/api/public/dashboards/%s/panels/2/query", validAccessToken)
+	acknowledgePath := fmt.Sprintf("/api/public/dashboards/%s/terms/acknowledge", validAccessToken)
+
+	query := func(server *web.Mux, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, queryPath, strings.NewReader("{}"))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("Can query public dashboards without terms of use", func(t *testing.T) {
+		terms := ""
+		resp := query(setup(t, &terms))
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Returns 403 when the terms of use are not acknowledged", func(t *testing.T) {
+		terms := "Do not share"
+		resp := query(setup(t, &terms))
+		require.Equal(t, http.StatusForbidden, resp.Code)
+		require.JSONEq(t, `{"statusCode":403,"messageId":"publicdashboards.termsNotAcknowledged","message":"terms of use not acknowledged"}`, resp.Body.String())
+	})
+
+	t.Run("Can query after acknowledging the terms of use until they change", func(t *testing.T) {
+		terms := "Do not share"
+		server := setup(t, &terms)
+
+		resp := callAPI(server, http.MethodPost, acknowledgePath, nil, t)
+		require.Equal(t, http.StatusOK, resp.Code)
+		cookies := resp.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, TermsAcknowledgmentCookieName, cookies[0].Name)
+
+		resp = query(server, cookies[0])
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		terms = "Do not share, ever"
+		resp = query(server, cookies[0])
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
+
 func TestAPIQueryPublicDashboard(t *testing.T) {
 	mockedResponse := &backend.QueryDataResponse{
 		Responses: map[string]backend.DataResponse{
@@ -420,6 +1136,7 @@ func TestAPIQueryPublicDashboard(t *testing.T) {
 
 	setup := func(enabled bool) (*web.Mux, *publicdashboards.FakePublicDashboardService) {
 		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("GetPrerenderedQueryDataResponse", mock.Anything, int64(2), "abc123").Return(nil, nil).Maybe()
 		cfg := setting.NewCfg()
 		cfg.RBACEnabled = false
 
@@ -475,12 +1192,166 @@ func TestAPIQueryPublicDashboard(t *testing.T) {
 		require.Equal(t, http.StatusOK, resp.Code)
 	})
 
+	t.Run("Propagates the request ID to the service and returns it", func(t *testing.T) {
+		server, fakeDashboardService := setup(true)
+		var requestID string
+		fakeDashboardService.On("GetQueryDataResponse", mock.Anything, true, mock.Anything, int64(2), "abc123").
+			Run(func(args mock.Arguments) { requestID = requestid.FromContext(args.Get(0).(context.Context)) }).
+			Return(mockedResponse, nil)
+
+		resp := callAPI(server, http.MethodPost, "/api/public/dashboards/abc123/panels/2/query", strings.NewReader("{}"), t)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.NotEmpty(t, requestID)
+		assert.Equal(t, requestID, resp.Header().Get(requestid.HeaderName))
+	})
+
 	t.Run("Status code is 500 when the query fails", func(t *testing.T) {
 		server, fakeDashboardService := setup(true)
 		fakeDashboardService.On("GetQueryDataResponse", mock.Anything, true, mock.Anything, int64(2), "abc123").Return(&backend.QueryDataResponse{}, fmt.Errorf("error"))
 
 		resp := callAPI(server, http.MethodPost, "/api/public/dashboards/abc123/panels/2/query", strings.NewReader("{}"), t)
 		require.Equal(t, http.StatusInternalServerError, resp.Code)
+		assert.NotEmpty(t, resp.Header().Get(requestid.HeaderName))
+	})
+
+	t.Run("Returns the pre-rendered query data with its freshness", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		renderedAt := time.Now().Add(-time.Minute)
+		service.On("GetPrerenderedQueryDataResponse", mock.Anything, int64(2), "abc123").
+			Return(&PrerenderedQueryDataResponse{Response: mockedResponse, RenderedAt: renderedAt, RequestID: "prerender-request"}, nil)
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+		server := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser)
+
+		resp := callAPI(server, http.MethodPost, "/api/public/dashboards/abc123/panels/2/query", strings.NewReader("{}"), t)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.JSONEq(t, expectedResponse, resp.Body.String())
+		assert.Equal(t, renderedAt.UTC().Format(http.TimeFormat), resp.Header().Get(PrerenderedAtHeaderName))
+		assert.Equal(t, "60", resp.Header().Get("Age"))
+		assert.Equal(t, "prerender-request", resp.Header().Get(requestid.HeaderName))
+		service.AssertNotCalled(t, "GetQueryDataResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Responds 304 when the query data wasn't modified", func(t *testing.T) {
+		server, fakeDashboardService := setup(true)
+		fakeDashboardService.On("GetQueryDataResponse", mock.Anything, true, mock.Anything, int64(2), "abc123").Return(mockedResponse, nil)
+
+		resp := callAPI(server, http.MethodPost, "/api/public/dashboards/abc123/panels/2/query", strings.NewReader("{}"), t)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.JSONEq(t, expectedResponse, resp.Body.String())
+		etag := resp.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+
+		resp = callConditionalAPI(server, http.MethodPost, "/api/public/dashboards/abc123/panels/2/query", http.Header{"If-None-Match": []string{etag}}, strings.NewReader("{}"), t)
+		assert.Equal(t, http.StatusNotModified, resp.Code)
+		assert.Empty(t, resp.Body.String())
+	})
+
+	t.Run("Responds 304 when the pre-rendered query data wasn't modified", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		renderedAt := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+		service.On("GetPrerenderedQueryDataResponse", mock.Anything, int64(2), "abc123").
+			Return(&PrerenderedQueryDataResponse{Response: mockedResponse, RenderedAt: renderedAt, RequestID: "prerender-request"}, nil)
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+		server := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser)
+
+		resp := callAPI(server, http.MethodPost, "/api/public/dashboards/abc123/panels/2/query", strings.NewReader("{}"), t)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, `"prerender-request"`, resp.Header().Get("ETag"))
+		assert.Equal(t, renderedAt.Format(http.TimeFormat), resp.Header().Get("Last-Modified"))
+
+		resp = callConditionalAPI(server, http.MethodPost, "/api/public/dashboards/abc123/panels/2/query", http.Header{"If-None-Match": []string{`"prerender-request"`}}, strings.NewReader("{}"), t)
+		assert.Equal(t, http.StatusNotModified, resp.Code)
+
+		resp = callConditionalAPI(server, http.MethodPost, "/api/public/dashboards/abc123/panels/2/query", http.Header{"If-Modified-Since": []string{renderedAt.Format(http.TimeFormat)}}, strings.NewReader("{}"), t)
+		assert.Equal(t, http.StatusNotModified, resp.Code)
+
+		resp = callConditionalAPI(server, http.MethodPost, "/api/public/dashboards/abc123/panels/2/query", http.Header{"If-None-Match": []string{`"previous-request"`}}, strings.NewReader("{}"), t)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		require.JSONEq(t, expectedResponse, resp.Body.String())
+	})
+}
+
+func TestAPIGetPublicDashboardHealth(t *testing.T) {
+	setup := func() (*web.Mux, *publicdashboards.FakePublicDashboardService) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("IndexingAllowed", mock.Anything, validAccessToken).Return(false, nil).Maybe()
+		service.On("CountryAllowed", mock.Anything, validAccessToken, "").Return(true, nil).Maybe()
+		service.On("ExternalViewerRequired", mock.Anything, validAccessToken).Return(false, nil).Maybe()
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+
+		return setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser), service
+	}
+
+	t.Run("Returns 200 when the public dashboard is healthy", func(t *testing.T) {
+		server, service := setup()
+		service.On("GetPublicDashboardHealth", mock.Anything, validAccessToken).
+			Return(&PublicDashboardHealth{Healthy: true, Enabled: true, DatasourcesReachable: true}, nil)
+
+		resp := callAPI(server, http.MethodGet, "/api/public/dashboards/"+validAccessToken+"/health", nil, t)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.JSONEq(t, `{"healthy":true,"enabled":true,"expired":false,"datasourcesReachable":true,"unreachableDatasources":0}`, resp.Body.String())
+	})
+
+	t.Run("Returns 503 when a data source is unreachable", func(t *testing.T) {
+		server, service := setup()
+		service.On("GetPublicDashboardHealth", mock.Anything, validAccessToken).
+			Return(&PublicDashboardHealth{Enabled: true, UnreachableDatasources: 1}, nil)
+
+		resp := callAPI(server, http.MethodGet, "/api/public/dashboards/"+validAccessToken+"/health", nil, t)
+		require.Equal(t, http.StatusServiceUnavailable, resp.Code)
+		require.JSONEq(t, `{"healthy":false,"enabled":true,"expired":false,"datasourcesReachable":false,"unreachableDatasources":1}`, resp.Body.String())
+	})
+
+	t.Run("Returns 404 when the public dashboard is not found", func(t *testing.T) {
+		server, service := setup()
+		service.On("GetPublicDashboardHealth", mock.Anything, validAccessToken).Return(nil, ErrPublicDashboardNotFound)
+
+		resp := callAPI(server, http.MethodGet, "/api/public/dashboards/"+validAccessToken+"/health", nil, t)
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
+
+func TestAPIGetPanelAlertStates(t *testing.T) {
+	setup := func() (*web.Mux, *publicdashboards.FakePublicDashboardService) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+
+		return setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser), service
+	}
+
+	t.Run("Returns the alert state counts of the panel", func(t *testing.T) {
+		server, service := setup()
+		service.On("GetPanelAlertStates", mock.Anything, "abc123", int64(2)).Return(&PanelAlertStates{Firing: 2, Ok: 3}, nil)
+
+		resp := callAPI(server, http.MethodGet, "/api/public/dashboards/abc123/panels/2/alert-state", nil, t)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.JSONEq(t, `{"firing":2,"pending":0,"ok":3,"noData":0,"error":0}`, resp.Body.String())
+	})
+
+	t.Run("Status code is 400 when the panel ID is invalid", func(t *testing.T) {
+		server, _ := setup()
+		resp := callAPI(server, http.MethodGet, "/api/public/dashboards/abc123/panels/notanumber/alert-state", nil, t)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("Status code is 403 when alert state is disabled", func(t *testing.T) {
+		server, service := setup()
+		service.On("GetPanelAlertStates", mock.Anything, "abc123", int64(2)).Return(nil, ErrPublicDashboardAlertStateDisabled)
+
+		resp := callAPI(server, http.MethodGet, "/api/public/dashboards/abc123/panels/2/alert-state", nil, t)
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("Status code is 404 when the panel is not found", func(t *testing.T) {
+		server, service := setup()
+		service.On("GetPanelAlertStates", mock.Anything, "abc123", int64(2)).Return(nil, ErrPublicDashboardPanelNotFound)
+
+		resp := callAPI(server, http.MethodGet, "/api/public/dashboards/abc123/panels/2/alert-state", nil, t)
+		require.Equal(t, http.StatusNotFound, resp.Code)
 	})
 }
 
@@ -544,7 +1415,9 @@ func TestIntegrationUnauthenticatedUserCanGetPubdashPanelQueryData(t *testing.T)
 	store := publicdashboardsStore.ProvideStore(db)
 	cfg := setting.NewCfg()
 	cfg.RBACEnabled = false
-	service := publicdashboardsService.ProvideService(cfg, store, qds)
+	libraryElements := libraryelements.ProvideService(cfg, db, routing.NewRouteRegister(), nil)
+	libraryPanels := librarypanels.ProvideService(cfg, db, routing.NewRouteRegister(), libraryElements)
+	service := publicdashboardsService.ProvideService(cfg, store, qds, dashverimpl.ProvideService(db), &fakeDatasources.FakeDataSourceService{}, libraryPanels, quotatest.NewQuotaServiceFake(), bus.ProvideBus(tracing.InitializeTracerForTest()), notifications.MockNotificationService(), playlistimpl.ProvideService(db, db.Cfg), plugins.FakePluginStore{}, nil, nil, jobstest.NewFakeScheduler())
 	pubdash, err := service.SavePublicDashboardConfig(context.Background(), &user.SignedInUser{}, savePubDashboardCmd)
 	require.NoError(t, err)
 
@@ -585,3 +1458,102 @@ func TestIntegrationUnauthenticatedUserCanGetPubdashPanelQueryData(t *testing.T)
 		resp.Body.String(),
 	)
 }
+
+func TestIntegrationPublicDashboardPanelQueryDataIsMasked(t *testing.T) {
+	db := sqlstore.InitTestDB(t)
+
+	fds := harness.NewFakeDataSources()
+	fds.SetFrames("ds1", "A", data.NewFrame("users",
+		data.NewField("email", nil, []string{"jane@example.com", "john@example.com"}),
+		data.NewField("country", nil, []string{"Sweden", "Norway"}),
+		data.NewField("logins", nil, []int64{12, 3}),
+	))
+
+	cacheService := datasourcesService.ProvideCacheService(localcache.ProvideService(), db)
+	qds := buildQueryDataService(t, cacheService, &fakePluginClient{QueryDataHandlerFunc: fds.QueryData}, db)
+	dsStore := datasourcesService.CreateStore(db, log.New("publicdashboards.test"))
+	err := dsStore.AddDataSource(context.Background(), &datasources.AddDataSourceCommand{
+		Uid:    "ds1",
+		OrgId:  1,
+		Name:   "users",
+		Type:   datasources.DS_MYSQL,
+		Access: datasources.DS_ACCESS_DIRECT,
+		Url:    "http://test",
+	})
+	require.NoError(t, err)
+
+	dashboardStoreService := dashboardStore.ProvideDashboardStore(db, featuremgmt.WithFeatures(), tagimpl.ProvideService(db, db.Cfg))
+	dashboard, err := dashboardStoreService.SaveDashboard(context.Background(), models.SaveDashboardCommand{
+		OrgId: 1,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{
+			"title": "users",
+			"panels": []map[string]interface{}{
+				{
+					"id": 1,
+					"targets": []map[string]interface{}{
+						{
+							"datasource": map[string]string{
+								"type": "mysql",
+								"uid":  "ds1",
+							},
+							"refId": "A",
+						},
+					},
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	store := publicdashboardsStore.ProvideStore(db)
+	cfg := setting.NewCfg()
+	cfg.RBACEnabled = false
+	libraryElements := libraryelements.ProvideService(cfg, db, routing.NewRouteRegister(), nil)
+	libraryPanels := librarypanels.ProvideService(cfg, db, routing.NewRouteRegister(), libraryElements)
+	service := publicdashboardsService.ProvideService(cfg, store, qds, dashverimpl.ProvideService(db), &fakeDatasources.FakeDataSourceService{}, libraryPanels, quotatest.NewQuotaServiceFake(), bus.ProvideBus(tracing.InitializeTracerForTest()), notifications.MockNotificationService(), playlistimpl.ProvideService(db, db.Cfg), plugins.FakePluginStore{}, nil, nil, jobstest.NewFakeScheduler())
+	pubdash, err := service.SavePublicDashboardConfig(context.Background(), &user.SignedInUser{}, &SavePublicDashboardConfigDTO{
+		DashboardUid: dashboard.Uid,
+		OrgId:        dashboard.OrgId,
+		PublicDashboard: &PublicDashboard{
+			IsEnabled: true,
+			MaskingRules: MaskingRules{
+				{FieldPattern: "^email$", Action: MaskActionRedact},
+				{FieldPattern: "^country$", Action: MaskActionTruncate, Length: 2},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	server := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, db, anonymousUser)
+
+	resp := callAPI(server, http.MethodPost,
+		fmt.Sprintf("/api/public/dashboards/%s/panels/1/query", pubdash.AccessToken),
+		strings.NewReader(`{}`),
+		t,
+	)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	// the data source was queried with the panel query
+	requests := fds.Requests()
+	require.Len(t, requests, 1)
+	require.Equal(t, "ds1", requests[0].PluginContext.DataSourceInstanceSettings.UID)
+
+	var body struct {
+		Results map[string]struct {
+			Frames []*data.Frame `json:"frames"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Results["A"].Frames, 1)
+
+	frame := body.Results["A"].Frames[0]
+	require.Len(t, frame.Fields, 3)
+	for i := 0; i < frame.Rows(); i++ {
+		_, ok := frame.Fields[0].ConcreteAt(i)
+		require.False(t, ok, "email should be redacted")
+	}
+	country, _ := frame.Fields[1].ConcreteAt(0)
+	require.Equal(t, "Sw", country)
+	logins, _ := frame.Fields[2].ConcreteAt(0)
+	require.Equal(t, int64(12), logins)
+}
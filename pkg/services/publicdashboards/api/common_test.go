@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
@@ -29,6 +30,7 @@ import (
 	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	datasourceService "github.com/grafana/grafana/pkg/services/datasources/service"
 	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 	"github.com/stretchr/testify/require"
@@ -41,6 +43,18 @@ func setupTestServer(
 	service publicdashboards.Service,
 	db *sqlstore.SQLStore,
 	user *user.SignedInUser,
+) *web.Mux {
+	return setupTestServerWithBus(t, cfg, features, service, db, user, nil)
+}
+
+func setupTestServerWithBus(
+	t *testing.T,
+	cfg *setting.Cfg,
+	features *featuremgmt.FeatureManager,
+	service publicdashboards.Service,
+	db *sqlstore.SQLStore,
+	user *user.SignedInUser,
+	bus bus.Bus,
 ) *web.Mux {
 	// build router to register routes
 	rr := routing.NewRouteRegister()
@@ -69,7 +83,7 @@ func setupTestServer(
 
 	// build api, this will mount the routes at the same time if
 	// featuremgmt.FlagPublicDashboard is enabled
-	ProvideApi(service, rr, ac, features)
+	ProvideApi(service, rr, ac, features, cfg, nil, bus, ratelimit.ProvideService(cfg, nil))
 
 	// connect routes to mux
 	rr.Register(m.Router)
@@ -104,6 +118,17 @@ func callAPI(server *web.Mux, method, path string, body io.Reader, t *testing.T)
 	return recorder
 }
 
+// callConditionalAPI calls the API with the conditional request headers
+func callConditionalAPI(server *web.Mux, method, path string, header http.Header, body io.Reader, t *testing.T) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, path, body)
+	require.NoError(t, err)
+	req.Header = header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, req)
+	return recorder
+}
+
 // helper to query.Service
 // allows us to stub the cache and plugin clients
 func buildQueryDataService(t *testing.T, cs datasources.CacheService, fpc *fakePluginClient, store *sqlstore.SQLStore) *query.Service {
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// conditionalJSON encodes the body and responds with 304 Not Modified when the
+// conditional request headers match its ETag or last modification time, so
+// that browsers and CDNs don't download unchanged public dashboards again
+func conditionalJSON(c *models.ReqContext, status int, body interface{}, lastModified time.Time) response.Response {
+	// use a configuration that's compatible with the standard library so
+	// that map keys are ordered and equal bodies get the same ETag
+	data, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(body)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to encode response", err)
+	}
+
+	if status == http.StatusOK && notModified(c, contentETag(data), lastModified) {
+		return notModifiedResponse()
+	}
+
+	return response.Respond(status, data).SetHeader("Content-Type", "application/json")
+}
+
+// notModifiedResponse responds with 304 Not Modified, which has no body
+func notModifiedResponse() response.Response {
+	return response.Respond(http.StatusNotModified, []byte{})
+}
+
+// contentETag returns a strong ETag identifying the content
+func contentETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified sets the ETag and Last-Modified headers of the response, and
+// returns true when the conditional request headers match them. If-None-Match
+// takes precedence over If-Modified-Since.
+func notModified(c *models.ReqContext, etag string, lastModified time.Time) bool {
+	header := c.Resp.Header()
+	header.Set("ETag", etag)
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if ifNoneMatch := c.Req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+
+	if ifModifiedSince := c.Req.Header.Get("If-Modified-Since"); ifModifiedSince != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !lastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// etagMatches uses the weak comparison of If-None-Match, a list of ETags or *
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -1,15 +1,36 @@
 package api
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"strings"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/infra/network"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/geoip"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/abuse"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/challenge"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/signature"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/tokens"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/web"
 )
 
+// ChallengePassCookieName is the cookie holding the pass given for solving the
+// challenge of a public dashboard
+const ChallengePassCookieName = "grafana_public_dashboard_pass"
+
+// TermsAcknowledgmentCookieName is the cookie holding the signed
+// acknowledgment of the terms of use of a public dashboard
+const TermsAcknowledgmentCookieName = "grafana_public_dashboard_terms"
+
 // Adds orgId to context based on org of public dashboard
 func SetPublicDashboardOrgIdOnContext(publicDashboardService publicdashboards.Service) func(c *models.ReqContext) {
 	return func(c *models.ReqContext) {
@@ -28,6 +49,23 @@ func SetPublicDashboardOrgIdOnContext(publicDashboardService publicdashboards.Se
 	}
 }
 
+// Adds orgId to context based on org of public playlist
+func SetPublicPlaylistOrgIdOnContext(publicDashboardService publicdashboards.Service) func(c *models.ReqContext) {
+	return func(c *models.ReqContext) {
+		accessToken, ok := web.Params(c.Req)[":accessToken"]
+		if !ok || !tokens.IsValidAccessToken(accessToken) {
+			return
+		}
+
+		orgId, err := publicDashboardService.GetPublicPlaylistOrgId(c.Req.Context(), accessToken)
+		if err != nil {
+			return
+		}
+
+		c.OrgID = orgId
+	}
+}
+
 // Adds public dashboard flag on context
 func SetPublicDashboardFlag(c *models.ReqContext) {
 	c.IsPublicDashboardView = true
@@ -61,8 +99,176 @@ func RequiresValidAccessToken(publicDashboardService publicdashboards.Service) f
 	}
 }
 
+// Adds a X-Robots-Tag header asking search engines not to index the public
+// dashboard, unless the public dashboard or the instance allows it
+func SetRobotsTagHeader(publicDashboardService publicdashboards.Service) func(c *models.ReqContext) {
+	return func(c *models.ReqContext) {
+		accessToken := web.Params(c.Req)[":accessToken"]
+		if tokens.IsValidAccessToken(accessToken) {
+			allowed, err := publicDashboardService.IndexingAllowed(c.Req.Context(), accessToken)
+			if err == nil && allowed {
+				return
+			}
+		}
+
+		c.Resp.Header().Set("X-Robots-Tag", "noindex, nofollow")
+	}
+}
+
+// Middleware to enforce that clients solved the challenge of public dashboards
+// requiring one before querying them. Passes are only valid for the client
+// they were given to, identified like by RejectBlockedClients.
+func RequiresChallengePass(publicDashboardService publicdashboards.Service, challenger *challenge.Challenger, trustedProxies []*net.IPNet) func(c *models.ReqContext) {
+	return func(c *models.ReqContext) {
+		accessToken := web.Params(c.Req)[":accessToken"]
+		if !tokens.IsValidAccessToken(accessToken) {
+			return
+		}
+
+		required, err := publicDashboardService.ChallengeRequired(c.Req.Context(), accessToken)
+		if errors.Is(err, ErrPublicDashboardNotFound) {
+			// unknown public dashboards are handled by the query handler
+			return
+		}
+		if err != nil {
+			c.JsonApiErr(http.StatusInternalServerError, "Failed to check whether the public dashboard requires a challenge", nil)
+			return
+		}
+		if !required {
+			return
+		}
+
+		clientIP := network.ClientIP(c.Req, trustedProxies)
+		if pass := c.GetCookie(ChallengePassCookieName); challenger.ValidPass(accessToken, clientIP, pass) {
+			return
+		}
+
+		c.JSON(ErrPublicDashboardChallengeRequired.StatusCode, ErrPublicDashboardChallengeRequired.Public())
+	}
+}
+
+// Middleware to enforce that clients acknowledged the terms of use of public
+// dashboards having them before querying them. Acknowledgments are bound to
+// the terms, changing them requires a new acknowledgment.
+func RequiresTermsAcknowledgment(publicDashboardService publicdashboards.Service, signer *signature.Signer) func(c *models.ReqContext) {
+	return func(c *models.ReqContext) {
+		accessToken := web.Params(c.Req)[":accessToken"]
+		if !tokens.IsValidAccessToken(accessToken) {
+			return
+		}
+
+		terms, err := publicDashboardService.GetTermsOfUse(c.Req.Context(), accessToken)
+		if err != nil || terms == "" {
+			// unknown public dashboards are handled by the query handler
+			return
+		}
+
+		acknowledgment, ok := signer.Verify(c.GetCookie(TermsAcknowledgmentCookieName))
+		if ok && acknowledgment == termsAcknowledgment(accessToken, terms) {
+			return
+		}
+
+		c.JSON(ErrPublicDashboardTermsNotAcknowledged.StatusCode, ErrPublicDashboardTermsNotAcknowledged.Public())
+	}
+}
+
+// Middleware to enforce that viewers of public dashboards shared with the
+// external SSO share mode are authenticated by the external identity provider.
+// Viewers present the JWT of the identity provider in the header, which is
+// verified with the JWT authentication settings without signing them in, and
+// their identity is recorded in the access log.
+func RequiresExternalViewer(publicDashboardService publicdashboards.Service, verifier models.JWTService, header string, logger log.Logger) func(c *models.ReqContext) {
+	return func(c *models.ReqContext) {
+		accessToken := web.Params(c.Req)[":accessToken"]
+		if !tokens.IsValidAccessToken(accessToken) {
+			return
+		}
+
+		required, err := publicDashboardService.ExternalViewerRequired(c.Req.Context(), accessToken)
+		if errors.Is(err, ErrPublicDashboardNotFound) {
+			// unknown public dashboards are handled by the handlers
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to check whether the public dashboard requires an external viewer", "error", err)
+			c.JsonApiErr(http.StatusInternalServerError, "Failed to check whether the public dashboard requires an external viewer", nil)
+			return
+		}
+		if !required {
+			return
+		}
+
+		token := strings.TrimPrefix(c.Req.Header.Get(header), "Bearer ")
+		if verifier == nil || header == "" || token == "" {
+			c.JSON(ErrPublicDashboardExternalViewerRequired.StatusCode, ErrPublicDashboardExternalViewerRequired.Public())
+			return
+		}
+
+		claims, err := verifier.Verify(c.Req.Context(), token)
+		subject, _ := claims["sub"].(string)
+		if err != nil || subject == "" {
+			c.JSON(ErrPublicDashboardExternalViewerRequired.StatusCode, ErrPublicDashboardExternalViewerRequired.Public())
+			return
+		}
+
+		email, _ := claims["email"].(string)
+		logger.Info("External viewer accessed public dashboard", "subject", subject, "email", email,
+			"method", c.Req.Method, "path", c.Req.URL.Path, "remote_addr", c.RemoteAddr())
+	}
+}
+
+// termsAcknowledgment is the payload of the acknowledgment of the terms of
+// use of a public dashboard
+func termsAcknowledgment(accessToken string, terms string) string {
+	sum := sha256.Sum256([]byte(terms))
+	return fmt.Sprintf("terms:%s:%s", accessToken, hex.EncodeToString(sum[:]))
+}
+
 func CountPublicDashboardRequest() func(c *models.ReqContext) {
 	return func(c *models.ReqContext) {
 		metrics.MPublicDashboardRequestCount.Inc()
 	}
 }
+
+// Middleware to reject the requests of clients blocked for probing public
+// dashboard access tokens or panel ids. Clients are identified by the address
+// of their connection, or the forwarded headers of the trusted proxies.
+func RejectBlockedClients(detector *abuse.Detector, trustedProxies []*net.IPNet) func(c *models.ReqContext) {
+	return func(c *models.ReqContext) {
+		if detector.IsBlocked(network.ClientIP(c.Req, trustedProxies)) {
+			c.JsonApiErr(http.StatusTooManyRequests, "Too many failed public dashboard requests, try again later", nil)
+		}
+	}
+}
+
+// Middleware to reject the requests of clients from countries the org of the
+// public dashboard doesn't serve
+func RejectRestrictedCountries(publicDashboardService publicdashboards.Service, provider geoip.Provider) func(c *models.ReqContext) {
+	return func(c *models.ReqContext) {
+		accessToken := web.Params(c.Req)[":accessToken"]
+		if !tokens.IsValidAccessToken(accessToken) {
+			return
+		}
+
+		country, err := provider.Country(c.Req)
+		if err != nil {
+			c.JsonApiErr(http.StatusInternalServerError, "Failed to resolve client country", nil)
+			return
+		}
+
+		allowed, err := publicDashboardService.CountryAllowed(c.Req.Context(), accessToken, country)
+		if errors.Is(err, ErrPublicDashboardNotFound) {
+			// unknown public dashboards are handled by the handlers
+			return
+		}
+		if err != nil {
+			c.JsonApiErr(http.StatusInternalServerError, "Failed to check the country restrictions of the public dashboard", nil)
+			return
+		}
+		if allowed {
+			return
+		}
+
+		c.JSON(ErrPublicDashboardUnavailableInCountry.StatusCode, ErrPublicDashboardUnavailableInCountry.Public())
+	}
+}
@@ -1,16 +1,22 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"errors"
 
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/network"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/abuse"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/tokens"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web"
 	"github.com/stretchr/testify/assert"
@@ -144,6 +150,52 @@ func TestSetPublicDashboardOrgIdOnContext(t *testing.T) {
 	}
 }
 
+func TestSetRobotsTagHeader(t *testing.T) {
+	tests := []struct {
+		Name            string
+		AccessToken     string
+		IndexingAllowed bool
+		ErrorResp       error
+		ExpectedHeader  string
+	}{
+		{
+			Name:            "Does not add header when indexing is allowed",
+			AccessToken:     validAccessToken,
+			IndexingAllowed: true,
+			ExpectedHeader:  "",
+		},
+		{
+			Name:            "Adds header when indexing is not allowed",
+			AccessToken:     validAccessToken,
+			IndexingAllowed: false,
+			ExpectedHeader:  "noindex, nofollow",
+		},
+		{
+			Name:           "Adds header with invalid accessToken",
+			AccessToken:    "invalidAccessToken",
+			ExpectedHeader: "noindex, nofollow",
+		},
+		{
+			Name:            "Adds header with error querying public dashboard",
+			AccessToken:     validAccessToken,
+			IndexingAllowed: true,
+			ErrorResp:       errors.New("database error of some sort"),
+			ExpectedHeader:  "noindex, nofollow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			publicdashboardService := &publicdashboards.FakePublicDashboardService{}
+			publicdashboardService.On("IndexingAllowed", mock.Anything, tt.AccessToken).Return(tt.IndexingAllowed, tt.ErrorResp)
+
+			params := map[string]string{":accessToken": tt.AccessToken}
+			_, resp := runMw(t, nil, "GET", "/public-dashboards/myaccesstoken", params, SetRobotsTagHeader(publicdashboardService))
+			assert.Equal(t, tt.ExpectedHeader, resp.Header().Get("X-Robots-Tag"))
+		})
+	}
+}
+
 func TestSetPublicDashboardFlag(t *testing.T) {
 	t.Run("Adds context.IsPublicDashboardView=true to request", func(t *testing.T) {
 		ctx := &models.ReqContext{}
@@ -152,6 +204,218 @@ func TestSetPublicDashboardFlag(t *testing.T) {
 	})
 }
 
+func TestRejectBlockedClients(t *testing.T) {
+	t.Run("Lets requests of clients that aren't blocked through", func(t *testing.T) {
+		detector := abuse.NewDetector(2, time.Minute, time.Minute)
+		detector.RecordFailure("")
+
+		_, resp := runMw(t, nil, "GET", "/api/public/dashboards/myAccesstoken", nil, RejectBlockedClients(detector, nil))
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Returns 429 when the client is blocked", func(t *testing.T) {
+		detector := abuse.NewDetector(2, time.Minute, time.Minute)
+		detector.RecordFailure("")
+		detector.RecordFailure("")
+
+		_, resp := runMw(t, nil, "GET", "/api/public/dashboards/myAccesstoken", nil, RejectBlockedClients(detector, nil))
+		assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	})
+
+	t.Run("Identifies clients by the forwarded headers of trusted proxies only", func(t *testing.T) {
+		detector := abuse.NewDetector(1, time.Minute, time.Minute)
+		detector.RecordFailure("198.51.100.1")
+		trustedProxies, err := network.ParseTrustedProxies([]string{"10.0.0.1"})
+		require.NoError(t, err)
+
+		request := func(remoteAddr string) func(c *models.ReqContext) {
+			return func(c *models.ReqContext) {
+				c.Req.RemoteAddr = remoteAddr
+				c.Req.Header.Set("X-Real-IP", "198.51.100.1")
+				RejectBlockedClients(detector, trustedProxies)(c)
+			}
+		}
+
+		_, resp := runMw(t, nil, "GET", "/api/public/dashboards/myAccesstoken", nil, request("203.0.113.1:5000"))
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		_, resp = runMw(t, nil, "GET", "/api/public/dashboards/myAccesstoken", nil, request("10.0.0.1:5000"))
+		assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	})
+}
+
+func TestRequiresExternalViewer(t *testing.T) {
+	verifier := &models.FakeJWTService{
+		VerifyProvider: func(_ context.Context, token string) (models.JWTClaims, error) {
+			switch token {
+			case "valid":
+				return models.JWTClaims{"sub": "alice", "email": "alice@example.com"}, nil
+			case "nosub":
+				return models.JWTClaims{}, nil
+			default:
+				return nil, errors.New("invalid token")
+			}
+		},
+	}
+
+	tests := []struct {
+		Name         string
+		AccessToken  string
+		Required     bool
+		ErrorResp    error
+		Verifier     models.JWTService
+		Token        string
+		ExpectedCode int
+	}{
+		{
+			Name:         "Lets viewers of public share mode through",
+			AccessToken:  validAccessToken,
+			Verifier:     verifier,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Lets verified external viewers through",
+			AccessToken:  validAccessToken,
+			Required:     true,
+			Verifier:     verifier,
+			Token:        "Bearer valid",
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Returns 401 without token",
+			AccessToken:  validAccessToken,
+			Required:     true,
+			Verifier:     verifier,
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name:         "Returns 401 with invalid token",
+			AccessToken:  validAccessToken,
+			Required:     true,
+			Verifier:     verifier,
+			Token:        "invalid",
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name:         "Returns 401 with token without subject",
+			AccessToken:  validAccessToken,
+			Required:     true,
+			Verifier:     verifier,
+			Token:        "nosub",
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name:         "Returns 401 when JWT authentication isn't configured",
+			AccessToken:  validAccessToken,
+			Required:     true,
+			Token:        "valid",
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name:         "Lets unknown public dashboards through",
+			AccessToken:  validAccessToken,
+			ErrorResp:    ErrPublicDashboardNotFound,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Returns 500 when the public dashboard can't be checked",
+			AccessToken:  validAccessToken,
+			ErrorResp:    errors.New("database error of some sort"),
+			Verifier:     verifier,
+			Token:        "Bearer valid",
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			publicdashboardService := &publicdashboards.FakePublicDashboardService{}
+			publicdashboardService.On("ExternalViewerRequired", mock.Anything, tt.AccessToken).Return(tt.Required, tt.ErrorResp)
+
+			ctx := &models.ReqContext{Context: &web.Context{}, SignedInUser: &user.SignedInUser{}}
+			mw := func(c *models.ReqContext) {
+				c.Req.Header.Set("X-External-Token", tt.Token)
+				RequiresExternalViewer(publicdashboardService, tt.Verifier, "X-External-Token", log.NewNopLogger())(c)
+			}
+
+			params := map[string]string{":accessToken": tt.AccessToken}
+			_, resp := runMw(t, ctx, "GET", "/api/public/dashboards/myaccesstoken", params, mw)
+			assert.Equal(t, tt.ExpectedCode, resp.Code)
+		})
+	}
+}
+
+type fakeGeoIPProvider struct {
+	country string
+	err     error
+}
+
+func (p fakeGeoIPProvider) Country(*http.Request) (string, error) {
+	return p.country, p.err
+}
+
+func TestRejectRestrictedCountries(t *testing.T) {
+	tests := []struct {
+		Name         string
+		AccessToken  string
+		Provider     fakeGeoIPProvider
+		Allowed      bool
+		ErrorResp    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Lets requests from allowed countries through",
+			AccessToken:  validAccessToken,
+			Provider:     fakeGeoIPProvider{country: "FR"},
+			Allowed:      true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Returns 451 for restricted countries",
+			AccessToken:  validAccessToken,
+			Provider:     fakeGeoIPProvider{country: "FR"},
+			Allowed:      false,
+			ExpectedCode: http.StatusUnavailableForLegalReasons,
+		},
+		{
+			Name:         "Returns 500 when the country can't be resolved",
+			AccessToken:  validAccessToken,
+			Provider:     fakeGeoIPProvider{err: errors.New("lookup failed")},
+			ExpectedCode: http.StatusInternalServerError,
+		},
+		{
+			Name:         "Lets unknown public dashboards through",
+			AccessToken:  validAccessToken,
+			Provider:     fakeGeoIPProvider{country: "FR"},
+			ErrorResp:    ErrPublicDashboardNotFound,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Returns 500 when the country restrictions can't be checked",
+			AccessToken:  validAccessToken,
+			Provider:     fakeGeoIPProvider{country: "FR"},
+			ErrorResp:    errors.New("database error of some sort"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+		{
+			Name:         "Lets invalid access tokens through",
+			AccessToken:  "invalidAccessToken",
+			ExpectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			publicdashboardService := &publicdashboards.FakePublicDashboardService{}
+			publicdashboardService.On("CountryAllowed", mock.Anything, tt.AccessToken, tt.Provider.country).Return(tt.Allowed, tt.ErrorResp)
+
+			params := map[string]string{":accessToken": tt.AccessToken}
+			_, resp := runMw(t, nil, "GET", "/api/public/dashboards/myaccesstoken", params, RejectRestrictedCountries(publicdashboardService, tt.Provider))
+			assert.Equal(t, tt.ExpectedCode, resp.Code)
+		})
+	}
+}
+
 // This is a helper to test middleware. It handles creating a
 // proper models.ReqContext, setting web parameters, executing middleware, and
 // returning a response. Response will default to result of
@@ -3,7 +3,9 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"time"
 
+	"github.com/grafana/grafana/pkg/coremodel/playlist"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/dashboards"
@@ -60,16 +62,40 @@ func (d *PublicDashboardStoreImpl) GetPublicDashboard(ctx context.Context, acces
 		return nil, nil, ErrPublicDashboardIdentifierNotSet
 	}
 
-	// get public dashboard
+	// get public dashboard, either by its own access token or by one of its
+	// scoped access tokens
 	pdRes := &PublicDashboard{AccessToken: accessToken}
 	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
 		has, err := sess.Get(pdRes)
 		if err != nil {
 			return err
 		}
+		if has {
+			return nil
+		}
+
+		scope := &ScopedAccessToken{AccessToken: accessToken}
+		has, err = sess.Get(scope)
+		if err != nil {
+			return err
+		}
+		if !has || !scope.IsUsable(time.Now()) {
+			return ErrPublicDashboardNotFound
+		}
+
+		pdRes = &PublicDashboard{Uid: scope.PublicDashboardUid}
+		has, err = sess.Get(pdRes)
+		if err != nil {
+			return err
+		}
 		if !has {
 			return ErrPublicDashboardNotFound
 		}
+
+		// never hand out the unrestricted access token to viewers of a
+		// scoped access token
+		pdRes.AccessToken = scope.AccessToken
+		pdRes.Scope = scope
 		return nil
 	})
 
@@ -171,7 +197,7 @@ func (d *PublicDashboardStoreImpl) SavePublicDashboardConfig(ctx context.Context
 	}
 
 	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
-		_, err := sess.UseBool("is_enabled").Insert(&cmd.PublicDashboard)
+		_, err := sess.UseBool("is_enabled", "require_challenge", "prerender_enabled").Insert(&cmd.PublicDashboard)
 		if err != nil {
 			return err
 		}
@@ -190,9 +216,27 @@ func (d *PublicDashboardStoreImpl) UpdatePublicDashboardConfig(ctx context.Conte
 			return err
 		}
 
-		_, err = sess.Exec("UPDATE dashboard_public SET is_enabled = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+		maskingRulesJSON, err := json.Marshal(cmd.PublicDashboard.MaskingRules)
+		if err != nil {
+			return err
+		}
+
+		queryFiltersJSON, err := json.Marshal(cmd.PublicDashboard.QueryFilters)
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Exec("UPDATE dashboard_public SET is_enabled = ?, time_settings = ?, dashboard_version = ?, masking_rules = ?, query_filters = ?, allow_indexing = ?, require_challenge = ?, terms_of_use = ?, share_mode = ?, prerender_enabled = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
 			cmd.PublicDashboard.IsEnabled,
 			string(timeSettingsJSON),
+			cmd.PublicDashboard.DashboardVersion,
+			string(maskingRulesJSON),
+			string(queryFiltersJSON),
+			cmd.PublicDashboard.AllowIndexing,
+			cmd.PublicDashboard.RequireChallenge,
+			cmd.PublicDashboard.TermsOfUse,
+			cmd.PublicDashboard.ShareMode,
+			cmd.PublicDashboard.PrerenderEnabled,
 			cmd.PublicDashboard.UpdatedBy,
 			cmd.PublicDashboard.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
 			cmd.PublicDashboard.Uid)
@@ -207,6 +251,65 @@ func (d *PublicDashboardStoreImpl) UpdatePublicDashboardConfig(ctx context.Conte
 	return err
 }
 
+// Retrieves the enabled public dashboards with pre-rendering enabled
+func (d *PublicDashboardStoreImpl) GetPrerenderedPublicDashboards(ctx context.Context) ([]*PublicDashboard, error) {
+	pubdashes := make([]*PublicDashboard, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("is_enabled = ? AND prerender_enabled = ?", true, true).Find(&pubdashes)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pubdashes, nil
+}
+
+// Retrieves the public dashboards of the dashboards in a folder or with a tag,
+// the folder takes precedence when both are set
+func (d *PublicDashboardStoreImpl) GetPublicDashboardsByFolderOrTag(ctx context.Context, orgId int64, folderUid string, tag string) ([]*PublicDashboard, error) {
+	var dashboards string
+	var args []interface{}
+	switch {
+	case folderUid != "":
+		dashboards = "SELECT uid FROM dashboard WHERE org_id = ? AND folder_id IN (SELECT id FROM dashboard WHERE org_id = ? AND uid = ? AND is_folder = ?)"
+		args = []interface{}{orgId, orgId, folderUid, true}
+	case tag != "":
+		dashboards = "SELECT dashboard.uid FROM dashboard INNER JOIN dashboard_tag ON dashboard_tag.dashboard_id = dashboard.id WHERE dashboard.org_id = ? AND dashboard_tag.term = ?"
+		args = []interface{}{orgId, tag}
+	default:
+		return nil, ErrPublicDashboardBulkSelectorRequired
+	}
+
+	pubdashes := make([]*PublicDashboard, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("org_id = ? AND dashboard_uid IN ("+dashboards+")", append([]interface{}{orgId}, args...)...).Asc("dashboard_uid").Find(&pubdashes)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pubdashes, nil
+}
+
+// Deletes a public dashboard along with its scoped access tokens, its history
+// is kept for auditing
+func (d *PublicDashboardStoreImpl) DeletePublicDashboard(ctx context.Context, uid string) error {
+	if uid == "" {
+		return ErrPublicDashboardIdentifierNotSet
+	}
+
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if _, err := sess.Exec("DELETE FROM dashboard_public_scoped_token WHERE public_dashboard_uid = ?", uid); err != nil {
+			return err
+		}
+
+		_, err := sess.Exec("DELETE FROM dashboard_public WHERE uid = ?", uid)
+		return err
+	})
+}
+
 // Responds true if public dashboard for a dashboard exists and isEnabled
 func (d *PublicDashboardStoreImpl) PublicDashboardEnabled(ctx context.Context, dashboardUid string) (bool, error) {
 	hasPublicDashboard := false
@@ -226,14 +329,14 @@ func (d *PublicDashboardStoreImpl) PublicDashboardEnabled(ctx context.Context, d
 	return hasPublicDashboard, err
 }
 
-// Responds true if accessToken exists and isEnabled. May be renamed in the
-// future
+// Responds true if accessToken, or the public dashboard of the scoped
+// accessToken, exists and isEnabled. May be renamed in the future
 func (d *PublicDashboardStoreImpl) AccessTokenExists(ctx context.Context, accessToken string) (bool, error) {
 	hasPublicDashboard := false
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
-		sql := "SELECT COUNT(*) FROM dashboard_public WHERE access_token=? AND is_enabled=true"
+		sql := "SELECT COUNT(*) FROM dashboard_public WHERE is_enabled=true AND (access_token=? OR uid IN (SELECT public_dashboard_uid FROM dashboard_public_scoped_token WHERE access_token=? AND is_disabled=false AND (expires IS NULL OR expires > ?)))"
 
-		result, err := dbSession.SQL(sql, accessToken).Count()
+		result, err := dbSession.SQL(sql, accessToken, accessToken, time.Now().Unix()).Count()
 		if err != nil {
 			return err
 		}
@@ -250,9 +353,9 @@ func (d *PublicDashboardStoreImpl) AccessTokenExists(ctx context.Context, access
 func (d *PublicDashboardStoreImpl) GetPublicDashboardOrgId(ctx context.Context, accessToken string) (int64, error) {
 	var orgId int64
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
-		sql := "SELECT org_id FROM dashboard_public WHERE access_token=? AND is_enabled=true"
+		sql := "SELECT org_id FROM dashboard_public WHERE is_enabled=true AND (access_token=? OR uid IN (SELECT public_dashboard_uid FROM dashboard_public_scoped_token WHERE access_token=? AND is_disabled=false AND (expires IS NULL OR expires > ?)))"
 
-		_, err := dbSession.SQL(sql, accessToken).Get(&orgId)
+		_, err := dbSession.SQL(sql, accessToken, accessToken, time.Now().Unix()).Get(&orgId)
 		if err != nil {
 			return err
 		}
@@ -262,3 +365,400 @@ func (d *PublicDashboardStoreImpl) GetPublicDashboardOrgId(ctx context.Context,
 
 	return orgId, err
 }
+
+// Persists a snapshot of a public dashboard configuration
+func (d *PublicDashboardStoreImpl) SavePublicDashboardHistory(ctx context.Context, cmd SavePublicDashboardHistoryCommand) error {
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.UseBool("is_enabled").Insert(&cmd.History)
+		return err
+	})
+}
+
+// Retrieves the configuration history of the public dashboard of a dashboard,
+// most recent first
+func (d *PublicDashboardStoreImpl) GetPublicDashboardHistory(ctx context.Context, orgId int64, dashboardUid string) ([]*PublicDashboardHistory, error) {
+	if dashboardUid == "" {
+		return nil, dashboards.ErrDashboardIdentifierNotSet
+	}
+
+	history := make([]*PublicDashboardHistory, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("org_id = ? AND dashboard_uid = ?", orgId, dashboardUid).Desc("id").Find(&history)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// Deletes the configuration history entries created before the time, returns
+// the number of deleted entries
+func (d *PublicDashboardStoreImpl) DeletePublicDashboardHistoryBefore(ctx context.Context, before time.Time) (int64, error) {
+	var affected int64
+	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var err error
+		affected, err = sess.Where("created_at < ?", before).Delete(&PublicDashboardHistory{})
+		return err
+	})
+
+	return affected, err
+}
+
+// Retrieves the public dashboards settings of an org. Returns default settings
+// when the org has not configured any.
+func (d *PublicDashboardStoreImpl) GetOrgSettings(ctx context.Context, orgId int64) (*OrgSettings, error) {
+	settings := &OrgSettings{OrgId: orgId}
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Get(settings)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// Persists the public dashboards settings of an org
+func (d *PublicDashboardStoreImpl) SaveOrgSettings(ctx context.Context, cmd SaveOrgSettingsCommand) error {
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		exists, err := sess.Exist(&OrgSettings{OrgId: cmd.Settings.OrgId})
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			_, err = sess.Insert(&cmd.Settings)
+			return err
+		}
+
+		_, err = sess.Where("org_id = ?", cmd.Settings.OrgId).AllCols().Update(&cmd.Settings)
+		return err
+	})
+}
+
+// Counts the alert instances of the unified alerting rules linked to a
+// dashboard panel by state
+func (d *PublicDashboardStoreImpl) GetUnifiedPanelAlertStates(ctx context.Context, dashboard *models.Dashboard, panelId int64) ([]AlertStateCount, error) {
+	counts := make([]AlertStateCount, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.SQL(`SELECT alert_instance.current_state AS state, COUNT(*) AS state_count
+			FROM alert_instance
+			INNER JOIN alert_rule ON alert_rule.org_id = alert_instance.rule_org_id AND alert_rule.uid = alert_instance.rule_uid
+			WHERE alert_rule.org_id = ? AND alert_rule.dashboard_uid = ? AND alert_rule.panel_id = ?
+			GROUP BY alert_instance.current_state`, dashboard.OrgId, dashboard.Uid, panelId).Find(&counts)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// Counts the legacy alerts of a dashboard panel by state
+func (d *PublicDashboardStoreImpl) GetLegacyPanelAlertStates(ctx context.Context, dashboard *models.Dashboard, panelId int64) ([]AlertStateCount, error) {
+	counts := make([]AlertStateCount, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.SQL(`SELECT state, COUNT(*) AS state_count
+			FROM alert
+			WHERE org_id = ? AND dashboard_id = ? AND panel_id = ?
+			GROUP BY state`, dashboard.OrgId, dashboard.Id, panelId).Find(&counts)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// Generates a new unique uid to retrieve a public playlist
+func (d *PublicDashboardStoreImpl) GenerateNewPublicPlaylistUid(ctx context.Context) (string, error) {
+	var uid string
+
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		for i := 0; i < 3; i++ {
+			uid = util.GenerateShortUID()
+
+			exists, err := sess.Get(&PublicPlaylist{Uid: uid})
+			if err != nil {
+				return err
+			}
+
+			if !exists {
+				return nil
+			}
+		}
+
+		return ErrPublicPlaylistFailedGenerateUniqueUid
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return uid, nil
+}
+
+// Retrieves public playlist by access token. Returns nil when no public
+// playlist has the access token.
+func (d *PublicDashboardStoreImpl) GetPublicPlaylist(ctx context.Context, accessToken string) (*PublicPlaylist, error) {
+	if accessToken == "" {
+		return nil, ErrPublicDashboardIdentifierNotSet
+	}
+
+	var found bool
+	ppRes := &PublicPlaylist{AccessToken: accessToken}
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var err error
+		found, err = sess.Get(ppRes)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return ppRes, nil
+}
+
+// Retrieves public playlist configuration by Uid
+func (d *PublicDashboardStoreImpl) GetPublicPlaylistByUid(ctx context.Context, uid string) (*PublicPlaylist, error) {
+	if uid == "" {
+		return nil, nil
+	}
+
+	var found bool
+	ppRes := &PublicPlaylist{Uid: uid}
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var err error
+		found, err = sess.Get(ppRes)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return ppRes, nil
+}
+
+// Retrieves the public playlist configuration of a playlist
+func (d *PublicDashboardStoreImpl) GetPublicPlaylistConfig(ctx context.Context, orgId int64, playlistUid string) (*PublicPlaylist, error) {
+	if playlistUid == "" {
+		return nil, ErrPublicDashboardIdentifierNotSet
+	}
+
+	ppRes := &PublicPlaylist{OrgId: orgId, PlaylistUid: playlistUid}
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Get(ppRes)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ppRes, nil
+}
+
+// Persists public playlist configuration
+func (d *PublicDashboardStoreImpl) SavePublicPlaylistConfig(ctx context.Context, cmd SavePublicPlaylistConfigCommand) error {
+	if cmd.PublicPlaylist.PlaylistUid == "" {
+		return ErrPublicDashboardIdentifierNotSet
+	}
+
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.UseBool("is_enabled").Insert(&cmd.PublicPlaylist)
+		return err
+	})
+}
+
+// Updates existing public playlist configuration
+func (d *PublicDashboardStoreImpl) UpdatePublicPlaylistConfig(ctx context.Context, cmd SavePublicPlaylistConfigCommand) error {
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Exec("UPDATE dashboard_public_playlist SET is_enabled = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+			cmd.PublicPlaylist.IsEnabled,
+			cmd.PublicPlaylist.UpdatedBy,
+			cmd.PublicPlaylist.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
+			cmd.PublicPlaylist.Uid)
+		return err
+	})
+}
+
+// Retrieves the enabled public dashboards of the dashboards matched by a
+// playlist item, ordered by dashboard title
+func (d *PublicDashboardStoreImpl) GetPlaylistItemPublicDashboards(ctx context.Context, orgId int64, itemType playlist.PlaylistItemType, value string) ([]*PublicPlaylistItem, error) {
+	sql := `SELECT dashboard_public.access_token, dashboard.title
+		FROM dashboard_public
+		INNER JOIN dashboard ON dashboard.org_id = dashboard_public.org_id AND dashboard.uid = dashboard_public.dashboard_uid
+		WHERE dashboard_public.org_id = ? AND dashboard_public.is_enabled = true`
+
+	switch itemType {
+	case playlist.PlaylistItemTypeDashboardByUid:
+		sql += " AND dashboard.uid = ?"
+	case playlist.PlaylistItemTypeDashboardById:
+		sql += " AND dashboard.id = ?"
+	case playlist.PlaylistItemTypeDashboardByTag:
+		sql += " AND dashboard.id IN (SELECT dashboard_id FROM dashboard_tag WHERE term = ?)"
+	default:
+		return nil, ErrPublicDashboardBadRequest
+	}
+	sql += " ORDER BY dashboard.title ASC"
+
+	items := make([]*PublicPlaylistItem, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.SQL(sql, orgId, value).Find(&items)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Generates a new unique uid to retrieve a scoped access token
+func (d *PublicDashboardStoreImpl) GenerateNewScopedAccessTokenUid(ctx context.Context) (string, error) {
+	var uid string
+
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		for i := 0; i < 3; i++ {
+			uid = util.GenerateShortUID()
+
+			exists, err := sess.Get(&ScopedAccessToken{Uid: uid})
+			if err != nil {
+				return err
+			}
+
+			if !exists {
+				return nil
+			}
+		}
+
+		return ErrScopedAccessTokenFailedGenerateUniqueUid
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return uid, nil
+}
+
+// Retrieves the scoped access tokens of a public dashboard, oldest first
+func (d *PublicDashboardStoreImpl) GetScopedAccessTokens(ctx context.Context, publicDashboardUid string) ([]*ScopedAccessToken, error) {
+	if publicDashboardUid == "" {
+		return nil, ErrPublicDashboardIdentifierNotSet
+	}
+
+	scopedTokens := make([]*ScopedAccessToken, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("public_dashboard_uid = ?", publicDashboardUid).Asc("created_at").Find(&scopedTokens)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return scopedTokens, nil
+}
+
+// Retrieves a scoped access token by its access token, whether it is usable
+// or not. Returns nil when it doesn't exist.
+func (d *PublicDashboardStoreImpl) GetScopedAccessToken(ctx context.Context, accessToken string) (*ScopedAccessToken, error) {
+	if accessToken == "" {
+		return nil, ErrPublicDashboardIdentifierNotSet
+	}
+
+	scopedToken := &ScopedAccessToken{AccessToken: accessToken}
+	var has bool
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var err error
+		has, err = sess.Get(scopedToken)
+		return err
+	})
+
+	if err != nil || !has {
+		return nil, err
+	}
+
+	return scopedToken, nil
+}
+
+// Persists a scoped access token
+func (d *PublicDashboardStoreImpl) SaveScopedAccessToken(ctx context.Context, cmd SaveScopedAccessTokenCommand) error {
+	if cmd.ScopedAccessToken.PublicDashboardUid == "" {
+		return ErrPublicDashboardIdentifierNotSet
+	}
+
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.UseBool("is_disabled").Insert(&cmd.ScopedAccessToken)
+		return err
+	})
+}
+
+// Deletes a scoped access token of a public dashboard
+func (d *PublicDashboardStoreImpl) DeleteScopedAccessToken(ctx context.Context, publicDashboardUid string, uid string) error {
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM dashboard_public_scoped_token WHERE public_dashboard_uid = ? AND uid = ?", publicDashboardUid, uid)
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return ErrScopedAccessTokenNotFound
+		}
+
+		return nil
+	})
+}
+
+// Adds a query and the bytes of its result to the usage of a scoped access
+// token, and disables the token when this exhausts one of its quotas
+func (d *PublicDashboardStoreImpl) RecordScopedAccessTokenUsage(ctx context.Context, uid string, bytes int64) error {
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Exec("UPDATE dashboard_public_scoped_token SET query_count = query_count + 1, byte_count = byte_count + ? WHERE uid = ?", bytes, uid)
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Exec(`UPDATE dashboard_public_scoped_token SET is_disabled = ?
+			WHERE uid = ? AND ((query_quota > 0 AND query_count >= query_quota) OR (byte_quota > 0 AND byte_count >= byte_quota))`, true, uid)
+		return err
+	})
+}
+
+// Disables the scoped access tokens expired at the time, returns the number of
+// disabled tokens
+func (d *PublicDashboardStoreImpl) DisableExpiredScopedAccessTokens(ctx context.Context, now time.Time) (int64, error) {
+	var affected int64
+	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("UPDATE dashboard_public_scoped_token SET is_disabled = ? WHERE is_disabled = ? AND expires IS NOT NULL AND expires <= ?", true, false, now.Unix())
+		if err != nil {
+			return err
+		}
+
+		affected, err = res.RowsAffected()
+		return err
+	})
+
+	return affected, err
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"strconv"
 	"testing"
 	"time"
 
@@ -9,10 +10,13 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/coremodel/playlist"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	dashboardsDB "github.com/grafana/grafana/pkg/services/dashboards/database"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
@@ -410,14 +414,23 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		})
 		require.NoError(t, err)
 
+		allowIndexing := true
 		updatedPublicDashboard := PublicDashboard{
-			Uid:          pdUid,
-			DashboardUid: savedDashboard.Uid,
-			OrgId:        savedDashboard.OrgId,
-			IsEnabled:    false,
-			TimeSettings: &TimeSettings{From: "now-8", To: "now"},
-			UpdatedAt:    time.Now().UTC().Round(time.Second),
-			UpdatedBy:    8,
+			Uid:              pdUid,
+			DashboardUid:     savedDashboard.Uid,
+			OrgId:            savedDashboard.OrgId,
+			IsEnabled:        false,
+			TimeSettings:     &TimeSettings{From: "now-8", To: "now"},
+			DashboardVersion: 2,
+			MaskingRules:     MaskingRules{{FieldPattern: "^email$", Action: MaskActionHash}},
+			QueryFilters:     QueryFilters{{DatasourceType: "prometheus", Filter: `tenant="acme"`}},
+			AllowIndexing:    &allowIndexing,
+			RequireChallenge: true,
+			TermsOfUse:       "Do not share",
+			ShareMode:        ShareModeExternalSSO,
+			PrerenderEnabled: true,
+			UpdatedAt:        time.Now().UTC().Round(time.Second),
+			UpdatedBy:        8,
 		}
 		// update initial record
 		err = publicdashboardStore.UpdatePublicDashboardConfig(context.Background(), SavePublicDashboardConfigCommand{
@@ -433,6 +446,14 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		// make sure we're correctly updated IsEnabled because we have to call
 		// UseBool with xorm
 		assert.Equal(t, updatedPublicDashboard.IsEnabled, pdRetrieved.IsEnabled)
+		assert.Equal(t, updatedPublicDashboard.DashboardVersion, pdRetrieved.DashboardVersion)
+		assert.Equal(t, updatedPublicDashboard.MaskingRules, pdRetrieved.MaskingRules)
+		assert.Equal(t, updatedPublicDashboard.QueryFilters, pdRetrieved.QueryFilters)
+		assert.Equal(t, updatedPublicDashboard.AllowIndexing, pdRetrieved.AllowIndexing)
+		assert.Equal(t, updatedPublicDashboard.RequireChallenge, pdRetrieved.RequireChallenge)
+		assert.Equal(t, updatedPublicDashboard.TermsOfUse, pdRetrieved.TermsOfUse)
+		assert.Equal(t, updatedPublicDashboard.ShareMode, pdRetrieved.ShareMode)
+		assert.Equal(t, updatedPublicDashboard.PrerenderEnabled, pdRetrieved.PrerenderEnabled)
 
 		// not updated dashboard shouldn't have changed
 		pdNotUpdatedRetrieved, err := publicdashboardStore.GetPublicDashboardConfig(context.Background(), anotherSavedDashboard.OrgId, anotherSavedDashboard.Uid)
@@ -442,6 +463,87 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 	})
 }
 
+// GetPrerenderedPublicDashboards
+func TestIntegrationGetPrerenderedPublicDashboards(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+	publicdashboardStore := ProvideStore(sqlStore)
+
+	for i, pubdash := range []PublicDashboard{
+		{Uid: "prerendered", IsEnabled: true, PrerenderEnabled: true},
+		{Uid: "disabled", IsEnabled: false, PrerenderEnabled: true},
+		{Uid: "notprerendered", IsEnabled: true, PrerenderEnabled: false},
+	} {
+		savedDashboard := insertTestDashboard(t, dashboardStore, pubdash.Uid, 1, 0, true)
+		pubdash.DashboardUid = savedDashboard.Uid
+		pubdash.OrgId = savedDashboard.OrgId
+		pubdash.AccessToken = "accesstoken" + strconv.Itoa(i)
+		pubdash.CreatedAt = DefaultTime
+		err := publicdashboardStore.SavePublicDashboardConfig(context.Background(), SavePublicDashboardConfigCommand{PublicDashboard: pubdash})
+		require.NoError(t, err)
+	}
+
+	pubdashes, err := publicdashboardStore.GetPrerenderedPublicDashboards(context.Background())
+	require.NoError(t, err)
+	require.Len(t, pubdashes, 1)
+	assert.Equal(t, "prerendered", pubdashes[0].Uid)
+}
+
+func TestIntegrationGetPublicDashboardsByFolderOrTag(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+	publicdashboardStore := ProvideStore(sqlStore)
+
+	folder := insertTestDashboard(t, dashboardStore, "payments", 1, 0, true)
+	for i, dash := range []*models.Dashboard{
+		insertTestDashboard(t, dashboardStore, "checkout", 1, folder.Id, false),
+		insertTestDashboard(t, dashboardStore, "refunds", 1, 0, false, "payments"),
+		insertTestDashboard(t, dashboardStore, "other org", 2, 0, false, "payments"),
+	} {
+		err := publicdashboardStore.SavePublicDashboardConfig(context.Background(), SavePublicDashboardConfigCommand{
+			PublicDashboard: PublicDashboard{
+				Uid:          "pubdash" + strconv.Itoa(i),
+				DashboardUid: dash.Uid,
+				OrgId:        dash.OrgId,
+				IsEnabled:    true,
+				AccessToken:  "accesstoken" + strconv.Itoa(i),
+				CreatedAt:    DefaultTime,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	pubdashes, err := publicdashboardStore.GetPublicDashboardsByFolderOrTag(context.Background(), 1, folder.Uid, "")
+	require.NoError(t, err)
+	require.Len(t, pubdashes, 1)
+	assert.Equal(t, "pubdash0", pubdashes[0].Uid)
+
+	pubdashes, err = publicdashboardStore.GetPublicDashboardsByFolderOrTag(context.Background(), 1, "", "payments")
+	require.NoError(t, err)
+	require.Len(t, pubdashes, 1)
+	assert.Equal(t, "pubdash1", pubdashes[0].Uid)
+
+	_, err = publicdashboardStore.GetPublicDashboardsByFolderOrTag(context.Background(), 1, "", "")
+	assert.ErrorIs(t, err, ErrPublicDashboardBulkSelectorRequired)
+
+	// deleting a public dashboard deletes its scoped access tokens
+	err = publicdashboardStore.SaveScopedAccessToken(context.Background(), SaveScopedAccessTokenCommand{
+		ScopedAccessToken: ScopedAccessToken{Uid: "scoped1", PublicDashboardUid: "pubdash1", OrgId: 1, AccessToken: "scopedToken", CreatedAt: DefaultTime},
+	})
+	require.NoError(t, err)
+
+	err = publicdashboardStore.DeletePublicDashboard(context.Background(), "pubdash1")
+	require.NoError(t, err)
+
+	pubdash, err := publicdashboardStore.GetPublicDashboardByUid(context.Background(), "pubdash1")
+	require.NoError(t, err)
+	assert.Nil(t, pubdash)
+
+	scopedToken, err := publicdashboardStore.GetScopedAccessToken(context.Background(), "scopedToken")
+	require.NoError(t, err)
+	assert.Nil(t, scopedToken)
+}
+
 // GetPublicDashboardOrgId
 func TestIntegrationGetPublicDashboardOrgId(t *testing.T) {
 	var sqlStore *sqlstore.SQLStore
@@ -506,7 +608,563 @@ func TestIntegrationGetPublicDashboardOrgId(t *testing.T) {
 	})
 }
 
+// GetOrgSettings and SaveOrgSettings
+func TestIntegrationOrgSettings(t *testing.T) {
+	var sqlStore *sqlstore.SQLStore
+	var publicdashboardStore *PublicDashboardStoreImpl
+
+	setup := func() {
+		sqlStore = sqlstore.InitTestDB(t)
+		publicdashboardStore = ProvideStore(sqlStore)
+	}
+
+	t.Run("returns default settings when org has none", func(t *testing.T) {
+		setup()
+
+		settings, err := publicdashboardStore.GetOrgSettings(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, &OrgSettings{OrgId: 1}, settings)
+	})
+
+	t.Run("saves and updates org settings", func(t *testing.T) {
+		setup()
+
+		err := publicdashboardStore.SaveOrgSettings(context.Background(), SaveOrgSettingsCommand{
+			Settings: OrgSettings{
+				OrgId:              1,
+				AllowedDatasources: &DatasourceAllowlist{Uids: []string{"ds1"}},
+				UpdatedBy:          7,
+				UpdatedAt:          DefaultTime,
+			},
+		})
+		require.NoError(t, err)
+
+		err = publicdashboardStore.SaveOrgSettings(context.Background(), SaveOrgSettingsCommand{
+			Settings: OrgSettings{
+				OrgId:              1,
+				AllowedDatasources: &DatasourceAllowlist{Types: []string{"prometheus"}},
+				UpdatedBy:          8,
+				UpdatedAt:          DefaultTime,
+			},
+		})
+		require.NoError(t, err)
+
+		settings, err := publicdashboardStore.GetOrgSettings(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, &DatasourceAllowlist{Types: []string{"prometheus"}}, settings.AllowedDatasources)
+		assert.Equal(t, int64(8), settings.UpdatedBy)
+
+		// other orgs are not affected
+		settings, err = publicdashboardStore.GetOrgSettings(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Nil(t, settings.AllowedDatasources)
+	})
+}
+
+func TestIntegrationPublicDashboardHistory(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	publicdashboardStore := ProvideStore(sqlStore)
+
+	saveHistory := func(dashboardUid string, action string, isEnabled bool) {
+		err := publicdashboardStore.SavePublicDashboardHistory(context.Background(), SavePublicDashboardHistoryCommand{
+			History: PublicDashboardHistory{
+				PublicDashboardUid: "pubdash-" + dashboardUid,
+				DashboardUid:       dashboardUid,
+				OrgId:              1,
+				Action:             action,
+				IsEnabled:          isEnabled,
+				TimeSettings:       &TimeSettings{},
+				AccessToken:        "fakeaccesstoken",
+				CreatedBy:          7,
+				CreatedAt:          DefaultTime,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	saveHistory("dash1", HistoryActionCreated, true)
+	saveHistory("dash1", HistoryActionDisabled, false)
+	saveHistory("dash2", HistoryActionCreated, true)
+
+	history, err := publicdashboardStore.GetPublicDashboardHistory(context.Background(), 1, "dash1")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, HistoryActionDisabled, history[0].Action)
+	assert.False(t, history[0].IsEnabled)
+	assert.Equal(t, HistoryActionCreated, history[1].Action)
+	assert.True(t, history[1].IsEnabled)
+	assert.Equal(t, int64(7), history[1].CreatedBy)
+
+	history, err = publicdashboardStore.GetPublicDashboardHistory(context.Background(), 2, "dash1")
+	require.NoError(t, err)
+	assert.Len(t, history, 0)
+
+	_, err = publicdashboardStore.GetPublicDashboardHistory(context.Background(), 1, "")
+	require.ErrorIs(t, err, dashboards.ErrDashboardIdentifierNotSet)
+}
+
+func TestIntegrationDeletePublicDashboardHistoryBefore(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	publicdashboardStore := ProvideStore(sqlStore)
+	now := time.Now().Truncate(time.Second)
+
+	for _, createdAt := range []time.Time{now.Add(-48 * time.Hour), now.Add(-2 * time.Hour), now} {
+		err := publicdashboardStore.SavePublicDashboardHistory(context.Background(), SavePublicDashboardHistoryCommand{
+			History: PublicDashboardHistory{
+				PublicDashboardUid: "pubdash-dash1",
+				DashboardUid:       "dash1",
+				OrgId:              1,
+				Action:             HistoryActionUpdated,
+				TimeSettings:       &TimeSettings{},
+				CreatedAt:          createdAt,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	deleted, err := publicdashboardStore.DeletePublicDashboardHistoryBefore(context.Background(), now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	history, err := publicdashboardStore.GetPublicDashboardHistory(context.Background(), 1, "dash1")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, now.Unix(), history[0].CreatedAt.Unix())
+}
+
+func TestIntegrationPanelAlertStates(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+	publicdashboardStore := ProvideStore(sqlStore)
+	dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true)
+
+	t.Run("counts the unified alerting instances of the panel rules by state", func(t *testing.T) {
+		insertRule := func(uid string, panelId int64) {
+			err := sqlStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+				_, err := sess.Insert(&ngmodels.AlertRule{
+					OrgID:        dashboard.OrgId,
+					UID:          uid,
+					Title:        uid,
+					Updated:      DefaultTime,
+					NamespaceUID: "folder",
+					RuleGroup:    "group",
+					DashboardUID: &dashboard.Uid,
+					PanelID:      &panelId,
+				})
+				return err
+			})
+			require.NoError(t, err)
+		}
+		insertRule("rule1", 2)
+		insertRule("rule2", 3)
+
+		instance := func(ruleUid string, instance string, state ngmodels.InstanceStateType) ngmodels.AlertInstance {
+			return ngmodels.AlertInstance{
+				AlertInstanceKey:  ngmodels.AlertInstanceKey{RuleOrgID: dashboard.OrgId, RuleUID: ruleUid, LabelsHash: instance},
+				Labels:            ngmodels.InstanceLabels{"instance": instance},
+				CurrentState:      state,
+				CurrentStateSince: DefaultTime,
+				LastEvalTime:      DefaultTime,
+			}
+		}
+		ngStore := ngstore.DBstore{SQLStore: sqlStore, FeatureToggles: featuremgmt.WithFeatures()}
+		err := ngStore.SaveAlertInstances(context.Background(),
+			instance("rule1", "a", ngmodels.InstanceStateFiring),
+			instance("rule1", "b", ngmodels.InstanceStateFiring),
+			instance("rule1", "c", ngmodels.InstanceStateNormal),
+			instance("rule2", "a", ngmodels.InstanceStateFiring),
+		)
+		require.NoError(t, err)
+
+		counts, err := publicdashboardStore.GetUnifiedPanelAlertStates(context.Background(), dashboard, 2)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []AlertStateCount{
+			{State: string(ngmodels.InstanceStateFiring), Count: 2},
+			{State: string(ngmodels.InstanceStateNormal), Count: 1},
+		}, counts)
+
+		counts, err = publicdashboardStore.GetUnifiedPanelAlertStates(context.Background(), dashboard, 4)
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+	})
+
+	t.Run("counts the legacy alerts of the panel by state", func(t *testing.T) {
+		insertAlert := func(panelId int64, state models.AlertStateType) {
+			err := sqlStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+				_, err := sess.Insert(&models.Alert{
+					OrgId:        dashboard.OrgId,
+					DashboardId:  dashboard.Id,
+					PanelId:      panelId,
+					Name:         "alert",
+					State:        state,
+					Settings:     simplejson.New(),
+					Created:      DefaultTime,
+					Updated:      DefaultTime,
+					NewStateDate: DefaultTime,
+				})
+				return err
+			})
+			require.NoError(t, err)
+		}
+		insertAlert(2, models.AlertStateAlerting)
+		insertAlert(2, models.AlertStateOK)
+		insertAlert(2, models.AlertStateOK)
+		insertAlert(3, models.AlertStateAlerting)
+
+		counts, err := publicdashboardStore.GetLegacyPanelAlertStates(context.Background(), dashboard, 2)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []AlertStateCount{
+			{State: string(models.AlertStateAlerting), Count: 1},
+			{State: string(models.AlertStateOK), Count: 2},
+		}, counts)
+	})
+}
+
+func TestIntegrationPublicPlaylist(t *testing.T) {
+	var sqlStore *sqlstore.SQLStore
+	var publicdashboardStore *PublicDashboardStoreImpl
+
+	setup := func() {
+		sqlStore = sqlstore.InitTestDB(t)
+		publicdashboardStore = ProvideStore(sqlStore)
+	}
+
+	t.Run("saves, retrieves and updates a public playlist", func(t *testing.T) {
+		setup()
+
+		uid, err := publicdashboardStore.GenerateNewPublicPlaylistUid(context.Background())
+		require.NoError(t, err)
+
+		err = publicdashboardStore.SavePublicPlaylistConfig(context.Background(), SavePublicPlaylistConfigCommand{
+			PublicPlaylist: PublicPlaylist{
+				Uid:         uid,
+				PlaylistUid: "playlist1",
+				OrgId:       1,
+				IsEnabled:   true,
+				AccessToken: "accessToken",
+				CreatedBy:   7,
+				CreatedAt:   DefaultTime,
+			},
+		})
+		require.NoError(t, err)
+
+		pubplaylist, err := publicdashboardStore.GetPublicPlaylist(context.Background(), "accessToken")
+		require.NoError(t, err)
+		require.NotNil(t, pubplaylist)
+		assert.Equal(t, uid, pubplaylist.Uid)
+		assert.Equal(t, "playlist1", pubplaylist.PlaylistUid)
+		assert.True(t, pubplaylist.IsEnabled)
+
+		pubplaylist, err = publicdashboardStore.GetPublicPlaylistConfig(context.Background(), 1, "playlist1")
+		require.NoError(t, err)
+		assert.Equal(t, uid, pubplaylist.Uid)
+
+		err = publicdashboardStore.UpdatePublicPlaylistConfig(context.Background(), SavePublicPlaylistConfigCommand{
+			PublicPlaylist: PublicPlaylist{Uid: uid, IsEnabled: false, UpdatedBy: 8, UpdatedAt: DefaultTime},
+		})
+		require.NoError(t, err)
+
+		pubplaylist, err = publicdashboardStore.GetPublicPlaylistByUid(context.Background(), uid)
+		require.NoError(t, err)
+		assert.False(t, pubplaylist.IsEnabled)
+		assert.Equal(t, int64(8), pubplaylist.UpdatedBy)
+	})
+
+	t.Run("returns nil when no public playlist has the access token", func(t *testing.T) {
+		setup()
+
+		pubplaylist, err := publicdashboardStore.GetPublicPlaylist(context.Background(), "nonExistentAccessToken")
+		require.NoError(t, err)
+		assert.Nil(t, pubplaylist)
+	})
+
+	t.Run("retrieves the enabled public dashboards of playlist items", func(t *testing.T) {
+		setup()
+		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+
+		savePubdash := func(dashboard *models.Dashboard, isEnabled bool) {
+			err := publicdashboardStore.SavePublicDashboardConfig(context.Background(), SavePublicDashboardConfigCommand{
+				PublicDashboard: PublicDashboard{
+					Uid:          util.GenerateShortUID(),
+					DashboardUid: dashboard.Uid,
+					OrgId:        dashboard.OrgId,
+					IsEnabled:    isEnabled,
+					AccessToken:  "token-" + dashboard.Title,
+					CreatedBy:    7,
+					CreatedAt:    DefaultTime,
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		dashA := insertTestDashboard(t, dashboardStore, "A", 1, 0, false, "ops")
+		dashB := insertTestDashboard(t, dashboardStore, "B", 1, 0, false, "ops")
+		dashC := insertTestDashboard(t, dashboardStore, "C", 1, 0, false, "ops")
+		insertTestDashboard(t, dashboardStore, "D", 1, 0, false, "ops")
+		savePubdash(dashA, true)
+		savePubdash(dashB, true)
+		savePubdash(dashC, false)
+
+		items, err := publicdashboardStore.GetPlaylistItemPublicDashboards(context.Background(), 1, playlist.PlaylistItemTypeDashboardByTag, "ops")
+		require.NoError(t, err)
+		assert.Equal(t, []*PublicPlaylistItem{
+			{AccessToken: "token-A", Title: "A"},
+			{AccessToken: "token-B", Title: "B"},
+		}, items)
+
+		items, err = publicdashboardStore.GetPlaylistItemPublicDashboards(context.Background(), 1, playlist.PlaylistItemTypeDashboardByUid, dashB.Uid)
+		require.NoError(t, err)
+		assert.Equal(t, []*PublicPlaylistItem{{AccessToken: "token-B", Title: "B"}}, items)
+
+		items, err = publicdashboardStore.GetPlaylistItemPublicDashboards(context.Background(), 1, playlist.PlaylistItemTypeDashboardById, strconv.FormatInt(dashA.Id, 10))
+		require.NoError(t, err)
+		assert.Equal(t, []*PublicPlaylistItem{{AccessToken: "token-A", Title: "A"}}, items)
+
+		items, err = publicdashboardStore.GetPlaylistItemPublicDashboards(context.Background(), 1, playlist.PlaylistItemTypeDashboardByUid, dashC.Uid)
+		require.NoError(t, err)
+		assert.Empty(t, items)
+
+		items, err = publicdashboardStore.GetPlaylistItemPublicDashboards(context.Background(), 2, playlist.PlaylistItemTypeDashboardByTag, "ops")
+		require.NoError(t, err)
+		assert.Empty(t, items)
+	})
+}
+
 // helper function insertTestDashboard
+func TestIntegrationScopedAccessTokens(t *testing.T) {
+	var sqlStore *sqlstore.SQLStore
+	var dashboardStore *dashboardsDB.DashboardStore
+	var publicdashboardStore *PublicDashboardStoreImpl
+	var savedDashboard *models.Dashboard
+	var pubdash PublicDashboard
+
+	setup := func(isEnabled bool) {
+		sqlStore = sqlstore.InitTestDB(t)
+		dashboardStore = dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+		publicdashboardStore = ProvideStore(sqlStore)
+		savedDashboard = insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true)
+
+		pubdash = PublicDashboard{
+			IsEnabled:    isEnabled,
+			Uid:          "abc1234",
+			DashboardUid: savedDashboard.Uid,
+			OrgId:        savedDashboard.OrgId,
+			TimeSettings: DefaultTimeSettings,
+			CreatedAt:    DefaultTime,
+			CreatedBy:    7,
+			AccessToken:  "unscopedToken",
+		}
+		err := publicdashboardStore.SavePublicDashboardConfig(context.Background(), SavePublicDashboardConfigCommand{PublicDashboard: pubdash})
+		require.NoError(t, err)
+
+		err = publicdashboardStore.SaveScopedAccessToken(context.Background(), SaveScopedAccessTokenCommand{
+			ScopedAccessToken: ScopedAccessToken{
+				Uid:                "scoped1",
+				PublicDashboardUid: pubdash.Uid,
+				OrgId:              savedDashboard.OrgId,
+				Name:               "auditors",
+				AccessToken:        "scopedToken",
+				PanelIds:           PanelIds{1, 2},
+				TimeSettings:       &TimeSettings{From: "now-90d", To: "now"},
+				CreatedBy:          7,
+				CreatedAt:          DefaultTime,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("saves and lists the scoped access tokens of a public dashboard", func(t *testing.T) {
+		setup(true)
+
+		scopedTokens, err := publicdashboardStore.GetScopedAccessTokens(context.Background(), pubdash.Uid)
+		require.NoError(t, err)
+		require.Len(t, scopedTokens, 1)
+		assert.Equal(t, "auditors", scopedTokens[0].Name)
+		assert.Equal(t, PanelIds{1, 2}, scopedTokens[0].PanelIds)
+		assert.Equal(t, &TimeSettings{From: "now-90d", To: "now"}, scopedTokens[0].TimeSettings)
+	})
+
+	t.Run("GetPublicDashboard resolves a scoped access token with its scope", func(t *testing.T) {
+		setup(true)
+
+		pd, d, err := publicdashboardStore.GetPublicDashboard(context.Background(), "scopedToken")
+		require.NoError(t, err)
+		assert.Equal(t, pubdash.Uid, pd.Uid)
+		assert.Equal(t, savedDashboard.Uid, d.Uid)
+		require.NotNil(t, pd.Scope)
+		assert.Equal(t, "scoped1", pd.Scope.Uid)
+		assert.Equal(t, "scopedToken", pd.AccessToken, "the unscoped access token must not be exposed")
+
+		pd, _, err = publicdashboardStore.GetPublicDashboard(context.Background(), "unscopedToken")
+		require.NoError(t, err)
+		assert.Nil(t, pd.Scope)
+	})
+
+	t.Run("AccessTokenExists and GetPublicDashboardOrgId accept scoped access tokens of enabled public dashboards", func(t *testing.T) {
+		setup(true)
+
+		exists, err := publicdashboardStore.AccessTokenExists(context.Background(), "scopedToken")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		orgId, err := publicdashboardStore.GetPublicDashboardOrgId(context.Background(), "scopedToken")
+		require.NoError(t, err)
+		assert.Equal(t, savedDashboard.OrgId, orgId)
+	})
+
+	t.Run("AccessTokenExists and GetPublicDashboardOrgId reject scoped access tokens of disabled public dashboards", func(t *testing.T) {
+		setup(false)
+
+		exists, err := publicdashboardStore.AccessTokenExists(context.Background(), "scopedToken")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		orgId, err := publicdashboardStore.GetPublicDashboardOrgId(context.Background(), "scopedToken")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), orgId)
+	})
+
+	t.Run("GetScopedAccessToken returns the scoped access token or nil when it doesn't exist", func(t *testing.T) {
+		setup(true)
+
+		scopedToken, err := publicdashboardStore.GetScopedAccessToken(context.Background(), "scopedToken")
+		require.NoError(t, err)
+		require.NotNil(t, scopedToken)
+		assert.Equal(t, "scoped1", scopedToken.Uid)
+
+		scopedToken, err = publicdashboardStore.GetScopedAccessToken(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, scopedToken)
+	})
+
+	t.Run("deletes a scoped access token", func(t *testing.T) {
+		setup(true)
+
+		err := publicdashboardStore.DeleteScopedAccessToken(context.Background(), "otherPubdash", "scoped1")
+		require.ErrorIs(t, err, ErrScopedAccessTokenNotFound)
+
+		err = publicdashboardStore.DeleteScopedAccessToken(context.Background(), pubdash.Uid, "scoped1")
+		require.NoError(t, err)
+
+		_, _, err = publicdashboardStore.GetPublicDashboard(context.Background(), "scopedToken")
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+}
+
+func TestIntegrationScopedAccessTokenLimits(t *testing.T) {
+	var publicdashboardStore *PublicDashboardStoreImpl
+
+	setup := func(scopedToken ScopedAccessToken) {
+		sqlStore := sqlstore.InitTestDB(t)
+		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+		publicdashboardStore = ProvideStore(sqlStore)
+		savedDashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true)
+
+		err := publicdashboardStore.SavePublicDashboardConfig(context.Background(), SavePublicDashboardConfigCommand{
+			PublicDashboard: PublicDashboard{
+				IsEnabled:    true,
+				Uid:          "abc1234",
+				DashboardUid: savedDashboard.Uid,
+				OrgId:        savedDashboard.OrgId,
+				CreatedAt:    DefaultTime,
+				CreatedBy:    7,
+				AccessToken:  "unscopedToken",
+			},
+		})
+		require.NoError(t, err)
+
+		scopedToken.Uid = "scoped1"
+		scopedToken.PublicDashboardUid = "abc1234"
+		scopedToken.OrgId = savedDashboard.OrgId
+		scopedToken.AccessToken = "scopedToken"
+		scopedToken.PanelIds = PanelIds{1}
+		scopedToken.CreatedAt = DefaultTime
+		err = publicdashboardStore.SaveScopedAccessToken(context.Background(), SaveScopedAccessTokenCommand{ScopedAccessToken: scopedToken})
+		require.NoError(t, err)
+	}
+
+	assertUsable := func(t *testing.T, usable bool) {
+		t.Helper()
+
+		exists, err := publicdashboardStore.AccessTokenExists(context.Background(), "scopedToken")
+		require.NoError(t, err)
+		assert.Equal(t, usable, exists)
+
+		_, _, err = publicdashboardStore.GetPublicDashboard(context.Background(), "scopedToken")
+		if usable {
+			require.NoError(t, err)
+		} else {
+			require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+		}
+	}
+
+	t.Run("rejects expired tokens", func(t *testing.T) {
+		expired := time.Now().Add(-time.Minute).Unix()
+		setup(ScopedAccessToken{Expires: &expired})
+		assertUsable(t, false)
+	})
+
+	t.Run("accepts tokens that haven't expired yet", func(t *testing.T) {
+		expires := time.Now().Add(time.Hour).Unix()
+		setup(ScopedAccessToken{Expires: &expires})
+		assertUsable(t, true)
+	})
+
+	t.Run("disables expired tokens", func(t *testing.T) {
+		expires := time.Now().Add(time.Hour).Unix()
+		setup(ScopedAccessToken{Expires: &expires})
+
+		disabled, err := publicdashboardStore.DisableExpiredScopedAccessTokens(context.Background(), time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), disabled)
+
+		disabled, err = publicdashboardStore.DisableExpiredScopedAccessTokens(context.Background(), time.Now().Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), disabled)
+
+		scopedTokens, err := publicdashboardStore.GetScopedAccessTokens(context.Background(), "abc1234")
+		require.NoError(t, err)
+		require.Len(t, scopedTokens, 1)
+		assert.True(t, scopedTokens[0].IsDisabled)
+	})
+
+	t.Run("disables the token once its query quota is exhausted", func(t *testing.T) {
+		setup(ScopedAccessToken{QueryQuota: 2})
+
+		require.NoError(t, publicdashboardStore.RecordScopedAccessTokenUsage(context.Background(), "scoped1", 100))
+		assertUsable(t, true)
+
+		require.NoError(t, publicdashboardStore.RecordScopedAccessTokenUsage(context.Background(), "scoped1", 100))
+		assertUsable(t, false)
+
+		scopedTokens, err := publicdashboardStore.GetScopedAccessTokens(context.Background(), "abc1234")
+		require.NoError(t, err)
+		require.Len(t, scopedTokens, 1)
+		assert.Equal(t, int64(2), scopedTokens[0].QueryCount)
+		assert.Equal(t, int64(200), scopedTokens[0].ByteCount)
+		assert.True(t, scopedTokens[0].IsDisabled)
+	})
+
+	t.Run("disables the token once its byte quota is exhausted", func(t *testing.T) {
+		setup(ScopedAccessToken{ByteQuota: 1000})
+
+		require.NoError(t, publicdashboardStore.RecordScopedAccessTokenUsage(context.Background(), "scoped1", 999))
+		assertUsable(t, true)
+
+		require.NoError(t, publicdashboardStore.RecordScopedAccessTokenUsage(context.Background(), "scoped1", 1))
+		assertUsable(t, false)
+	})
+
+	t.Run("never disables tokens without quotas", func(t *testing.T) {
+		setup(ScopedAccessToken{})
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, publicdashboardStore.RecordScopedAccessTokenUsage(context.Background(), "scoped1", 1<<20))
+		}
+		assertUsable(t, true)
+	})
+}
+
 func insertTestDashboard(t *testing.T, dashboardStore *dashboardsDB.DashboardStore, title string, orgId int64,
 	folderId int64, isFolder bool, tags ...interface{}) *models.Dashboard {
 	t.Helper()
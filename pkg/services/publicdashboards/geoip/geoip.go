@@ -0,0 +1,48 @@
+// Package geoip resolves the country of the clients of public dashboards, to
+// enforce the country restrictions of orgs.
+package geoip
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Provider resolves the country of the client making a request. Country
+// returns an ISO 3166-1 alpha-2 code, or an empty string when the country of
+// the client is unknown.
+type Provider interface {
+	Country(req *http.Request) (string, error)
+}
+
+// HeaderProvider reads the country of the client from a request header set by
+// a trusted reverse proxy or CDN, like Cloudflare's CF-IPCountry
+type HeaderProvider struct {
+	Header string
+}
+
+func (p HeaderProvider) Country(req *http.Request) (string, error) {
+	country := strings.ToUpper(strings.TrimSpace(req.Header.Get(p.Header)))
+	// Cloudflare uses XX for unknown countries and T1 for Tor exit nodes
+	if len(country) != 2 || country == "XX" {
+		return "", nil
+	}
+
+	return country, nil
+}
+
+// NoopProvider doesn't know the country of any client
+type NoopProvider struct{}
+
+func (NoopProvider) Country(*http.Request) (string, error) {
+	return "", nil
+}
+
+// ProvideProvider returns a HeaderProvider reading the configured country
+// header, or a NoopProvider when none is configured
+func ProvideProvider(header string) Provider {
+	if header == "" {
+		return NoopProvider{}
+	}
+
+	return HeaderProvider{Header: header}
+}
@@ -0,0 +1,42 @@
+package geoip
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderProvider(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "returns the country", header: "FR", expected: "FR"},
+		{name: "normalizes the country", header: " de ", expected: "DE"},
+		{name: "returns no country without header", header: "", expected: ""},
+		{name: "returns no country for unknown countries", header: "XX", expected: ""},
+		{name: "returns no country for invalid codes", header: "FRA", expected: ""},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			if test.header != "" {
+				req.Header.Set("CF-IPCountry", test.header)
+			}
+
+			country, err := HeaderProvider{Header: "CF-IPCountry"}.Country(req)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, country)
+		})
+	}
+}
+
+func TestProvideProvider(t *testing.T) {
+	assert.Equal(t, NoopProvider{}, ProvideProvider(""))
+	assert.Equal(t, HeaderProvider{Header: "CF-IPCountry"}, ProvideProvider("CF-IPCountry"))
+}
@@ -0,0 +1,138 @@
+package abuse
+
+import (
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics"
+)
+
+const (
+	// DefaultMaxFailures is the number of failed lookups an IP can make within
+	// DefaultWindow before it is blocked
+	DefaultMaxFailures = 20
+	DefaultWindow      = 5 * time.Minute
+	// DefaultBlockDuration is how long an IP stays blocked
+	DefaultBlockDuration = 15 * time.Minute
+	// DefaultMaxClients is the number of IPs tracked at once, bounding the
+	// memory used by the detector
+	DefaultMaxClients = 100000
+)
+
+type client struct {
+	failures     int
+	windowStart  time.Time
+	blockedUntil time.Time
+}
+
+// Detector tracks the failed public dashboard lookups, unknown access tokens
+// or panel ids, per IP and temporarily blocks the IPs making too many of them
+// to slow down access token brute-forcing. State is kept in memory, so each
+// Grafana instance tracks its own clients.
+type Detector struct {
+	mu            sync.Mutex
+	clients       map[string]*client
+	maxFailures   int
+	window        time.Duration
+	blockDuration time.Duration
+	maxClients    int
+	lastCleanup   time.Time
+	now           func() time.Time
+	log           log.Logger
+}
+
+func NewDetector(maxFailures int, window time.Duration, blockDuration time.Duration) *Detector {
+	return &Detector{
+		clients:       make(map[string]*client),
+		maxFailures:   maxFailures,
+		window:        window,
+		blockDuration: blockDuration,
+		maxClients:    DefaultMaxClients,
+		now:           time.Now,
+		log:           log.New("publicdashboards.abuse"),
+	}
+}
+
+// IsBlocked returns true if the IP is currently blocked
+func (d *Detector) IsBlocked(ip string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	c, ok := d.clients[ip]
+	return ok && d.now().Before(c.blockedUntil)
+}
+
+// RecordFailure records a failed lookup from the IP and blocks it when it
+// reaches the maximum number of failures of the window. Returns true if the
+// IP got blocked.
+func (d *Detector) RecordFailure(ip string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	d.cleanup(now, false)
+
+	c, ok := d.clients[ip]
+	if !ok {
+		if !d.makeRoom(now) {
+			d.log.Warn("Not tracking the failed public dashboard lookups of client, too many clients are blocked", "ip", ip, "maxClients", d.maxClients)
+			return false
+		}
+		c = &client{windowStart: now}
+		d.clients[ip] = c
+	}
+	if now.Sub(c.windowStart) > d.window {
+		c.failures = 0
+		c.windowStart = now
+	}
+
+	c.failures++
+	if c.failures < d.maxFailures || now.Before(c.blockedUntil) {
+		return false
+	}
+
+	c.blockedUntil = now.Add(d.blockDuration)
+	c.failures = 0
+	c.windowStart = now
+
+	metrics.MPublicDashboardBlockedClients.Inc()
+	d.log.Error("Blocked client after too many failed public dashboard lookups", "ip", ip, "failures", d.maxFailures, "window", d.window, "blockedUntil", c.blockedUntil)
+
+	return true
+}
+
+// makeRoom makes sure a new client can be tracked without exceeding the
+// maximum number of clients, forgetting the clients that aren't blocked when
+// needed. Returns false when all the tracked clients are blocked.
+func (d *Detector) makeRoom(now time.Time) bool {
+	if len(d.clients) < d.maxClients {
+		return true
+	}
+
+	d.cleanup(now, true)
+	for ip, c := range d.clients {
+		if len(d.clients) < d.maxClients {
+			break
+		}
+		if !now.Before(c.blockedUntil) {
+			delete(d.clients, ip)
+		}
+	}
+	return len(d.clients) < d.maxClients
+}
+
+// cleanup forgets the clients that are neither blocked nor have failures in
+// the current window, at most once per window unless forced
+func (d *Detector) cleanup(now time.Time, force bool) {
+	if !force && now.Sub(d.lastCleanup) < d.window {
+		return
+	}
+	d.lastCleanup = now
+
+	for ip, c := range d.clients {
+		if now.Sub(c.windowStart) > d.window && !now.Before(c.blockedUntil) {
+			delete(d.clients, ip)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package abuse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetector(t *testing.T) {
+	now := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+	newDetector := func() *Detector {
+		d := NewDetector(3, time.Minute, 10*time.Minute)
+		d.now = func() time.Time { return now }
+		return d
+	}
+
+	t.Run("blocks the client once it reaches the maximum failures", func(t *testing.T) {
+		d := newDetector()
+
+		assert.False(t, d.RecordFailure("10.0.0.1"))
+		assert.False(t, d.RecordFailure("10.0.0.1"))
+		assert.False(t, d.IsBlocked("10.0.0.1"))
+
+		assert.True(t, d.RecordFailure("10.0.0.1"))
+		assert.True(t, d.IsBlocked("10.0.0.1"))
+		assert.False(t, d.IsBlocked("10.0.0.2"))
+	})
+
+	t.Run("unblocks the client after the block duration", func(t *testing.T) {
+		d := newDetector()
+		for i := 0; i < 3; i++ {
+			d.RecordFailure("10.0.0.1")
+		}
+
+		d.now = func() time.Time { return now.Add(9 * time.Minute) }
+		assert.True(t, d.IsBlocked("10.0.0.1"))
+
+		d.now = func() time.Time { return now.Add(10 * time.Minute) }
+		assert.False(t, d.IsBlocked("10.0.0.1"))
+	})
+
+	t.Run("forgets the failures outside of the window", func(t *testing.T) {
+		d := newDetector()
+		d.RecordFailure("10.0.0.1")
+		d.RecordFailure("10.0.0.1")
+
+		d.now = func() time.Time { return now.Add(2 * time.Minute) }
+		assert.False(t, d.RecordFailure("10.0.0.1"))
+		assert.False(t, d.IsBlocked("10.0.0.1"))
+	})
+
+	t.Run("removes the expired clients", func(t *testing.T) {
+		d := newDetector()
+		d.RecordFailure("10.0.0.1")
+		for i := 0; i < 3; i++ {
+			d.RecordFailure("10.0.0.2")
+		}
+
+		d.now = func() time.Time { return now.Add(2 * time.Minute) }
+		d.RecordFailure("10.0.0.3")
+
+		assert.NotContains(t, d.clients, "10.0.0.1")
+		assert.Contains(t, d.clients, "10.0.0.2")
+		assert.Contains(t, d.clients, "10.0.0.3")
+	})
+
+	t.Run("tracks at most the maximum number of clients", func(t *testing.T) {
+		d := newDetector()
+		d.maxClients = 2
+		for i := 0; i < 3; i++ {
+			d.RecordFailure("10.0.0.1")
+		}
+		d.RecordFailure("10.0.0.2")
+
+		// the clients that aren't blocked make room for the new ones
+		d.RecordFailure("10.0.0.3")
+		assert.Len(t, d.clients, 2)
+		assert.True(t, d.IsBlocked("10.0.0.1"))
+		assert.Contains(t, d.clients, "10.0.0.3")
+
+		// the blocked clients are kept
+		for i := 0; i < 3; i++ {
+			d.RecordFailure("10.0.0.3")
+		}
+		assert.False(t, d.RecordFailure("10.0.0.4"))
+		assert.Len(t, d.clients, 2)
+		assert.NotContains(t, d.clients, "10.0.0.4")
+	})
+}
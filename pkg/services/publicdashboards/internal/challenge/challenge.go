@@ -0,0 +1,150 @@
+package challenge
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/signature"
+)
+
+const (
+	// DefaultDifficulty is the number of leading zero bits required in the
+	// hash of a solution, about 65k hashes to solve
+	DefaultDifficulty = 16
+	// ChallengeTTL is how long a challenge can be solved
+	ChallengeTTL = 5 * time.Minute
+	// PassTTL is how long a pass obtained by solving a challenge is valid
+	PassTTL = 12 * time.Hour
+
+	challengePrefix = "challenge"
+	passPrefix      = "pass"
+)
+
+var (
+	ErrInvalidChallenge = errors.New("invalid challenge")
+	ErrChallengeExpired = errors.New("challenge expired")
+	ErrInvalidSolution  = errors.New("invalid challenge solution")
+)
+
+// Challenge is a proof-of-work challenge. It is solved by finding a solution
+// string for which the SHA-256 hash of Token followed by the solution starts
+// with Difficulty zero bits.
+type Challenge struct {
+	Token      string `json:"token"`
+	Difficulty int    `json:"difficulty"`
+}
+
+// Challenger issues proof-of-work challenges for public dashboards and the
+// passes given for solving them. Challenges and passes are signed, so no state
+// is kept and they are valid on every Grafana instance sharing the secret.
+// Both are bound to the client they were issued to, identified by its IP, so
+// that a solved challenge can't be shared between clients.
+type Challenger struct {
+	signer     *signature.Signer
+	difficulty int
+	now        func() time.Time
+}
+
+func NewChallenger(signer *signature.Signer, difficulty int) *Challenger {
+	if difficulty <= 0 {
+		difficulty = DefaultDifficulty
+	}
+
+	return &Challenger{
+		signer:     signer,
+		difficulty: difficulty,
+		now:        time.Now,
+	}
+}
+
+// New returns a new challenge for the client to access the public dashboard
+// of the access token
+func (c *Challenger) New(accessToken string, clientIP string) (*Challenge, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	expiresAt := c.now().Add(ChallengeTTL).Unix()
+	payload := fmt.Sprintf("%s:%s:%s:%s:%d", challengePrefix, accessToken, clientHash(clientIP), hex.EncodeToString(nonce), expiresAt)
+
+	return &Challenge{
+		Token:      c.signer.Sign(payload),
+		Difficulty: c.difficulty,
+	}, nil
+}
+
+// Solve checks the solution of a challenge issued to the client for the public
+// dashboard of the access token and returns a pass for them
+func (c *Challenger) Solve(accessToken string, clientIP string, token string, solution string) (string, error) {
+	payload, ok := c.signer.Verify(token)
+	if !ok {
+		return "", ErrInvalidChallenge
+	}
+
+	parts := strings.Split(payload, ":")
+	if len(parts) != 5 || parts[0] != challengePrefix || parts[1] != accessToken || parts[2] != clientHash(clientIP) {
+		return "", ErrInvalidChallenge
+	}
+
+	if c.expired(parts[4]) {
+		return "", ErrChallengeExpired
+	}
+
+	sum := sha256.Sum256([]byte(token + solution))
+	if leadingZeroBits(sum[:]) < c.difficulty {
+		return "", ErrInvalidSolution
+	}
+
+	expiresAt := c.now().Add(PassTTL).Unix()
+	return c.signer.Sign(fmt.Sprintf("%s:%s:%s:%d", passPrefix, accessToken, clientHash(clientIP), expiresAt)), nil
+}
+
+// ValidPass returns true if the pass was given to the client for the public
+// dashboard of the access token and hasn't expired
+func (c *Challenger) ValidPass(accessToken string, clientIP string, pass string) bool {
+	payload, ok := c.signer.Verify(pass)
+	if !ok {
+		return false
+	}
+
+	parts := strings.Split(payload, ":")
+	if len(parts) != 4 || parts[0] != passPrefix || parts[1] != accessToken || parts[2] != clientHash(clientIP) {
+		return false
+	}
+
+	return !c.expired(parts[3])
+}
+
+// clientHash identifies the client in challenges and passes without revealing
+// its IP
+func clientHash(clientIP string) string {
+	sum := sha256.Sum256([]byte(clientIP))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (c *Challenger) expired(expiresAt string) bool {
+	unix, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil {
+		return true
+	}
+	return !c.now().Before(time.Unix(unix, 0))
+}
+
+func leadingZeroBits(sum []byte) int {
+	n := 0
+	for _, b := range sum {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return n
+}
@@ -0,0 +1,120 @@
+package challenge
+
+import (
+	"crypto/sha256"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/signature"
+)
+
+func solve(t *testing.T, c *Challenge) string {
+	t.Helper()
+	for i := 0; ; i++ {
+		solution := strconv.Itoa(i)
+		sum := sha256.Sum256([]byte(c.Token + solution))
+		if leadingZeroBits(sum[:]) >= c.Difficulty {
+			return solution
+		}
+	}
+}
+
+func TestChallenger(t *testing.T) {
+	now := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+	newChallenger := func() *Challenger {
+		c := NewChallenger(signature.NewSigner("secret"), 8)
+		c.now = func() time.Time { return now }
+		return c
+	}
+
+	t.Run("gives a pass for a solved challenge", func(t *testing.T) {
+		c := newChallenger()
+		challenge, err := c.New("token1", "10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, 8, challenge.Difficulty)
+
+		pass, err := c.Solve("token1", "10.0.0.1", challenge.Token, solve(t, challenge))
+		require.NoError(t, err)
+
+		assert.True(t, c.ValidPass("token1", "10.0.0.1", pass))
+		assert.False(t, c.ValidPass("token2", "10.0.0.1", pass))
+		assert.False(t, c.ValidPass("token1", "10.0.0.1", challenge.Token))
+	})
+
+	t.Run("rejects wrong solutions", func(t *testing.T) {
+		c := newChallenger()
+		challenge, err := c.New("token1", "10.0.0.1")
+		require.NoError(t, err)
+
+		solution := solve(t, challenge)
+		for i := 0; ; i++ {
+			wrong := "x" + strconv.Itoa(i)
+			sum := sha256.Sum256([]byte(challenge.Token + wrong))
+			if leadingZeroBits(sum[:]) < challenge.Difficulty {
+				solution = wrong
+				break
+			}
+		}
+
+		_, err = c.Solve("token1", "10.0.0.1", challenge.Token, solution)
+		assert.ErrorIs(t, err, ErrInvalidSolution)
+	})
+
+	t.Run("rejects challenges of another public dashboard", func(t *testing.T) {
+		c := newChallenger()
+		challenge, err := c.New("token1", "10.0.0.1")
+		require.NoError(t, err)
+
+		_, err = c.Solve("token2", "10.0.0.1", challenge.Token, solve(t, challenge))
+		assert.ErrorIs(t, err, ErrInvalidChallenge)
+	})
+
+	t.Run("binds challenges and passes to the client", func(t *testing.T) {
+		c := newChallenger()
+		challenge, err := c.New("token1", "10.0.0.1")
+		require.NoError(t, err)
+		solution := solve(t, challenge)
+
+		_, err = c.Solve("token1", "10.0.0.2", challenge.Token, solution)
+		assert.ErrorIs(t, err, ErrInvalidChallenge)
+
+		pass, err := c.Solve("token1", "10.0.0.1", challenge.Token, solution)
+		require.NoError(t, err)
+		assert.False(t, c.ValidPass("token1", "10.0.0.2", pass))
+	})
+
+	t.Run("rejects challenges signed with another secret", func(t *testing.T) {
+		c := newChallenger()
+		challenge, err := NewChallenger(signature.NewSigner("other secret"), 8).New("token1", "10.0.0.1")
+		require.NoError(t, err)
+
+		_, err = c.Solve("token1", "10.0.0.1", challenge.Token, solve(t, challenge))
+		assert.ErrorIs(t, err, ErrInvalidChallenge)
+	})
+
+	t.Run("rejects expired challenges and passes", func(t *testing.T) {
+		c := newChallenger()
+		challenge, err := c.New("token1", "10.0.0.1")
+		require.NoError(t, err)
+		pass, err := c.Solve("token1", "10.0.0.1", challenge.Token, solve(t, challenge))
+		require.NoError(t, err)
+
+		c.now = func() time.Time { return now.Add(ChallengeTTL) }
+		_, err = c.Solve("token1", "10.0.0.1", challenge.Token, solve(t, challenge))
+		assert.ErrorIs(t, err, ErrChallengeExpired)
+
+		c.now = func() time.Time { return now.Add(PassTTL) }
+		assert.False(t, c.ValidPass("token1", "10.0.0.1", pass))
+	})
+}
+
+func TestLeadingZeroBits(t *testing.T) {
+	assert.Equal(t, 0, leadingZeroBits([]byte{0xff}))
+	assert.Equal(t, 3, leadingZeroBits([]byte{0x10, 0x00}))
+	assert.Equal(t, 12, leadingZeroBits([]byte{0x00, 0x08}))
+	assert.Equal(t, 16, leadingZeroBits([]byte{0x00, 0x00}))
+}
@@ -0,0 +1,48 @@
+package requestid
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// HeaderName is the header holding the request ID, returned to public
+// viewers and sent to the data sources queried for them
+const HeaderName = "X-Grafana-Request-Id"
+
+type contextKey struct{}
+
+var registerLogProvider sync.Once
+
+// New generates a request ID
+func New() string {
+	return uuid.NewString()
+}
+
+// WithRequestID returns a copy of the context holding the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, requestID)
+}
+
+// FromContext returns the request ID of the context, empty when it has none
+func FromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(contextKey{}).(string)
+	return requestID
+}
+
+// RegisterLogProvider adds the request ID of the context to the loggers
+// created with FromContext
+func RegisterLogProvider() {
+	registerLogProvider.Do(func() {
+		log.RegisterContextualLogProvider(func(ctx context.Context) ([]interface{}, bool) {
+			if requestID := FromContext(ctx); requestID != "" {
+				return []interface{}{"requestID", requestID}, true
+			}
+
+			return nil, false
+		})
+	})
+}
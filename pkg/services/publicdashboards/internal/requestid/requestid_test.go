@@ -0,0 +1,20 @@
+package requestid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	requestID := New()
+	_, err := uuid.Parse(requestID)
+	require.NoError(t, err)
+	assert.NotEqual(t, requestID, New())
+
+	assert.Empty(t, FromContext(context.Background()))
+	assert.Equal(t, requestID, FromContext(WithRequestID(context.Background(), requestID)))
+}
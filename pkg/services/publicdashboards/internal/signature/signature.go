@@ -0,0 +1,49 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+)
+
+// Signer signs the values handed to public dashboard viewers, like challenge
+// passes, so that they can be verified without keeping any state
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret)}
+}
+
+// Sign returns the payload followed by its signature
+func (s *Signer) Sign(payload string) string {
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.mac(payload))
+}
+
+// Verify checks the signature of a signed value and returns its payload
+func (s *Signer) Verify(signed string) (string, bool) {
+	i := strings.LastIndex(signed, ".")
+	if i < 0 {
+		return "", false
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(signed[i+1:])
+	if err != nil {
+		return "", false
+	}
+
+	payload := signed[:i]
+	if !hmac.Equal(signature, s.mac(payload)) {
+		return "", false
+	}
+
+	return payload, true
+}
+
+func (s *Signer) mac(payload string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,25 @@
+package signature
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner(t *testing.T) {
+	signer := NewSigner("secret")
+	signed := signer.Sign("pass:token1:1662033600")
+
+	payload, ok := signer.Verify(signed)
+	assert.True(t, ok)
+	assert.Equal(t, "pass:token1:1662033600", payload)
+
+	_, ok = NewSigner("other secret").Verify(signed)
+	assert.False(t, ok)
+
+	_, ok = signer.Verify("pass:token2:1662033600" + signed[len("pass:token1:1662033600"):])
+	assert.False(t, ok)
+
+	_, ok = signer.Verify("pass:token1:1662033600")
+	assert.False(t, ok)
+}
@@ -3,17 +3,23 @@ package models
 import (
 	"encoding/json"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 // PublicDashboardErr represents a dashboard error.
 type PublicDashboardErr struct {
 	StatusCode int
 	Status     string
-	Reason     string
+	// MessageID identifies the error for API clients, which can branch on it
+	// instead of the reason
+	MessageID string
+	Reason    string
 }
 
 // Error returns the error message.
@@ -24,6 +30,16 @@ func (e PublicDashboardErr) Error() string {
 	return "Dashboard Error"
 }
 
+// Public returns the status code, message ID and reason of the error, which
+// are safe to relay to the end user.
+func (e PublicDashboardErr) Public() errutil.PublicError {
+	return errutil.PublicError{
+		StatusCode: e.StatusCode,
+		MessageID:  e.MessageID,
+		Message:    e.Error(),
+	}
+}
+
 const QuerySuccess = "success"
 const QueryFailure = "failure"
 
@@ -31,35 +47,169 @@ var QueryResultStatuses = []string{QuerySuccess, QueryFailure}
 
 var (
 	ErrPublicDashboardFailedGenerateUniqueUid = PublicDashboardErr{
+		MessageID:  "publicdashboards.failedGenerateUniqueUid",
 		Reason:     "failed to generate unique public dashboard id",
 		StatusCode: 500,
 	}
 	ErrPublicDashboardFailedGenerateAccesstoken = PublicDashboardErr{
+		MessageID:  "publicdashboards.failedGenerateAccessToken",
 		Reason:     "failed to public dashboard access token",
 		StatusCode: 500,
 	}
 	ErrPublicDashboardNotFound = PublicDashboardErr{
+		MessageID:  "publicdashboards.notFound",
 		Reason:     "public dashboard not found",
 		StatusCode: 404,
 		Status:     "not-found",
 	}
 	ErrPublicDashboardPanelNotFound = PublicDashboardErr{
+		MessageID:  "publicdashboards.panelNotFound",
 		Reason:     "panel not found in dashboard",
 		StatusCode: 404,
 		Status:     "not-found",
 	}
+	ErrPublicDashboardAlreadyExists = PublicDashboardErr{
+		MessageID:  "publicdashboards.alreadyExists",
+		Reason:     "dashboard already has a public dashboard",
+		StatusCode: 409,
+		Status:     "already-exists",
+	}
 	ErrPublicDashboardIdentifierNotSet = PublicDashboardErr{
+		MessageID:  "publicdashboards.identifierNotSet",
 		Reason:     "no Uid for public dashboard specified",
 		StatusCode: 400,
 	}
 	ErrPublicDashboardHasTemplateVariables = PublicDashboardErr{
+		MessageID:  "publicdashboards.hasTemplateVariables",
 		Reason:     "public dashboard has template variables",
 		StatusCode: 422,
 	}
 	ErrPublicDashboardBadRequest = PublicDashboardErr{
+		MessageID:  "publicdashboards.badRequest",
 		Reason:     "bad Request",
 		StatusCode: 400,
 	}
+	ErrPublicDashboardVersionNotFound = PublicDashboardErr{
+		MessageID:  "publicdashboards.versionNotFound",
+		Reason:     "dashboard version not found",
+		StatusCode: 400,
+	}
+	ErrPublicDashboardDatasourceNotAllowed = PublicDashboardErr{
+		MessageID:  "publicdashboards.datasourceNotAllowed",
+		Reason:     "data source is not allowed for public dashboards",
+		StatusCode: 403,
+	}
+	ErrPublicDashboardInvalidMaskingRule = PublicDashboardErr{
+		MessageID:  "publicdashboards.invalidMaskingRule",
+		Reason:     "invalid masking rule",
+		StatusCode: 400,
+	}
+	ErrPublicDashboardUnavailableInCountry = PublicDashboardErr{
+		MessageID:  "publicdashboards.unavailableInCountry",
+		Reason:     "Public dashboard unavailable in your country",
+		StatusCode: 451,
+	}
+	ErrPublicDashboardInvalidCountryRestrictions = PublicDashboardErr{
+		MessageID:  "publicdashboards.invalidCountryRestrictions",
+		Reason:     "country restrictions need ISO 3166-1 alpha-2 country codes",
+		StatusCode: 400,
+	}
+	ErrPublicDashboardInvalidQueryFilter = PublicDashboardErr{
+		MessageID:  "publicdashboards.invalidQueryFilter",
+		Reason:     "invalid query filter",
+		StatusCode: 400,
+	}
+	ErrPublicDashboardQueryFilterNotApplied = PublicDashboardErr{
+		MessageID:  "publicdashboards.queryFilterNotApplied",
+		Reason:     "query filter could not be applied",
+		StatusCode: 403,
+	}
+	ErrPublicDashboardChallengeRequired = PublicDashboardErr{
+		MessageID:  "publicdashboards.challengeRequired",
+		Reason:     "challenge required",
+		StatusCode: 403,
+		Status:     "challenge-required",
+	}
+	ErrPublicDashboardInvalidChallenge = PublicDashboardErr{
+		MessageID:  "publicdashboards.invalidChallenge",
+		Reason:     "invalid challenge solution",
+		StatusCode: 400,
+	}
+	ErrPublicDashboardExternalViewerRequired = PublicDashboardErr{
+		MessageID:  "publicdashboards.externalViewerRequired",
+		Reason:     "public dashboard requires signing in with the external identity provider",
+		StatusCode: 401,
+		Status:     "external-viewer-required",
+	}
+	ErrPublicDashboardInvalidShareMode = PublicDashboardErr{
+		MessageID:  "publicdashboards.invalidShareMode",
+		Reason:     "invalid share mode",
+		StatusCode: 400,
+	}
+	ErrPublicDashboardInvalidTimeSettings = PublicDashboardErr{
+		MessageID:  "publicdashboards.invalidTimeSettings",
+		Reason:     "invalid time settings",
+		StatusCode: 400,
+	}
+	ErrPublicDashboardTermsNotAcknowledged = PublicDashboardErr{
+		MessageID:  "publicdashboards.termsNotAcknowledged",
+		Reason:     "terms of use not acknowledged",
+		StatusCode: 403,
+		Status:     "terms-not-acknowledged",
+	}
+	ErrPublicDashboardBulkSelectorRequired = PublicDashboardErr{
+		MessageID:  "publicdashboards.bulkSelectorRequired",
+		Reason:     "either a folder or a tag is required",
+		StatusCode: 400,
+	}
+	ErrPublicDashboardQuotaReached = PublicDashboardErr{
+		MessageID:  "publicdashboards.quotaReached",
+		Reason:     "public dashboards quota reached",
+		StatusCode: 403,
+	}
+	ErrPublicPlaylistNotFound = PublicDashboardErr{
+		MessageID:  "publicdashboards.publicPlaylistNotFound",
+		Reason:     "public playlist not found",
+		StatusCode: 404,
+		Status:     "not-found",
+	}
+	ErrPlaylistNotFound = PublicDashboardErr{
+		MessageID:  "publicdashboards.playlistNotFound",
+		Reason:     "playlist not found",
+		StatusCode: 404,
+		Status:     "not-found",
+	}
+	ErrPublicPlaylistFailedGenerateUniqueUid = PublicDashboardErr{
+		MessageID:  "publicdashboards.publicPlaylistFailedGenerateUniqueUid",
+		Reason:     "failed to generate unique public playlist id",
+		StatusCode: 500,
+	}
+	ErrPublicDashboardAlertStateDisabled = PublicDashboardErr{
+		MessageID:  "publicdashboards.alertStateDisabled",
+		Reason:     "alert state is disabled for public dashboards",
+		StatusCode: 403,
+	}
+	ErrScopedAccessTokenNotFound = PublicDashboardErr{
+		MessageID:  "publicdashboards.scopedAccessTokenNotFound",
+		Reason:     "scoped access token not found",
+		StatusCode: 404,
+		Status:     "not-found",
+	}
+	ErrScopedAccessTokenInvalid = PublicDashboardErr{
+		MessageID:  "publicdashboards.scopedAccessTokenInvalid",
+		Reason:     "scoped access token needs at least one panel of the dashboard and a complete time range",
+		StatusCode: 400,
+	}
+	ErrScopedAccessTokenInvalidLimits = PublicDashboardErr{
+		MessageID:  "publicdashboards.scopedAccessTokenInvalidLimits",
+		Reason:     "scoped access token expiry has to be in the future and quotas can't be negative",
+		StatusCode: 400,
+	}
+	ErrScopedAccessTokenFailedGenerateUniqueUid = PublicDashboardErr{
+		MessageID:  "publicdashboards.scopedAccessTokenFailedGenerateUniqueUid",
+		Reason:     "failed to generate unique scoped access token id",
+		StatusCode: 500,
+	}
 )
 
 type PublicDashboard struct {
@@ -69,6 +219,35 @@ type PublicDashboard struct {
 	TimeSettings *TimeSettings `json:"timeSettings" xorm:"time_settings"`
 	IsEnabled    bool          `json:"isEnabled" xorm:"is_enabled"`
 	AccessToken  string        `json:"accessToken" xorm:"access_token"`
+	// DashboardVersion pins the saved dashboard version served to public
+	// viewers. When zero the latest version of the dashboard is served.
+	DashboardVersion int `json:"dashboardVersion" xorm:"dashboard_version"`
+	// MaskingRules are applied to the query results before they are sent to
+	// public viewers
+	MaskingRules MaskingRules `json:"maskingRules" xorm:"masking_rules"`
+	// QueryFilters are injected into every query of the public dashboard to
+	// restrict the data it can read
+	QueryFilters QueryFilters `json:"queryFilters" xorm:"query_filters"`
+	// AllowIndexing lets search engines index the public dashboard. When nil
+	// the instance default is used.
+	AllowIndexing *bool `json:"allowIndexing" xorm:"allow_indexing"`
+	// RequireChallenge makes public viewers solve a proof-of-work challenge
+	// before their first query, to slow down scraping
+	RequireChallenge bool `json:"requireChallenge" xorm:"require_challenge"`
+	// TermsOfUse are shown to public viewers, who have to acknowledge them
+	// before querying the public dashboard
+	TermsOfUse string `json:"termsOfUse" xorm:"terms_of_use"`
+	// ShareMode decides who can view the public dashboard, anyone with the
+	// access token or only viewers authenticated by the external identity
+	// provider. Empty means ShareModePublic.
+	ShareMode string `json:"shareMode" xorm:"share_mode"`
+	// PrerenderEnabled makes anonymous viewers get the query results
+	// pre-rendered in the background instead of querying the data sources
+	PrerenderEnabled bool `json:"prerenderEnabled" xorm:"prerender_enabled"`
+	// Scope is set when the public dashboard was retrieved with a scoped
+	// access token, it restricts the panels and time range public viewers
+	// get. It isn't persisted with the public dashboard.
+	Scope *ScopedAccessToken `json:"-" xorm:"-"`
 
 	CreatedBy int64 `json:"createdBy" xorm:"created_by"`
 	UpdatedBy int64 `json:"updatedBy" xorm:"updated_by"`
@@ -81,9 +260,38 @@ func (pd PublicDashboard) TableName() string {
 	return "dashboard_public"
 }
 
+const (
+	// TimeModeDashboard queries the public dashboard with the time range
+	// saved in the dashboard
+	TimeModeDashboard = "dashboard"
+	// TimeModeFixed queries the public dashboard with the fixed window of its
+	// time settings
+	TimeModeFixed = "fixed"
+)
+
 type TimeSettings struct {
 	From string `json:"from,omitempty"`
 	To   string `json:"to,omitempty"`
+	// Mode chooses between the dashboard time range and the fixed window of
+	// From and To. When empty, the window is used if it is set.
+	Mode string `json:"mode,omitempty"`
+}
+
+// IsFixed returns true when the time settings override the time range of the
+// dashboard
+func (ts *TimeSettings) IsFixed() bool {
+	if ts == nil {
+		return false
+	}
+
+	switch ts.Mode {
+	case TimeModeDashboard:
+		return false
+	case TimeModeFixed:
+		return true
+	default:
+		return ts.From != "" && ts.To != ""
+	}
 }
 
 func (ts *TimeSettings) FromDB(data []byte) error {
@@ -94,11 +302,82 @@ func (ts *TimeSettings) ToDB() ([]byte, error) {
 	return json.Marshal(ts)
 }
 
-// build time settings object from json on public dashboard. If empty, use
-// defaults on the dashboard
+const (
+	// ShareModePublic lets anyone with the access token view the public
+	// dashboard
+	ShareModePublic = "public"
+	// ShareModeExternalSSO only lets viewers authenticated by the configured
+	// external identity provider, without a Grafana account, view the public
+	// dashboard
+	ShareModeExternalSSO = "external_sso"
+)
+
+// RequiresExternalViewer returns true if only viewers authenticated by the
+// external identity provider can view the public dashboard
+func (pd PublicDashboard) RequiresExternalViewer() bool {
+	return pd.ShareMode == ShareModeExternalSSO
+}
+
+const (
+	MaskActionRedact   = "redact"
+	MaskActionHash     = "hash"
+	MaskActionTruncate = "truncate"
+)
+
+// MaskingRule masks the values of the query result fields whose name matches
+// FieldPattern, a regular expression. Length is the number of characters kept
+// by the truncate action.
+type MaskingRule struct {
+	FieldPattern string `json:"fieldPattern"`
+	Action       string `json:"action"`
+	Length       int    `json:"length,omitempty"`
+}
+
+type MaskingRules []MaskingRule
+
+func (r *MaskingRules) FromDB(data []byte) error {
+	return json.Unmarshal(data, r)
+}
+
+func (r *MaskingRules) ToDB() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// QueryFilter is a mandatory filter injected into the queries sent to data
+// sources of DatasourceType. For Prometheus the filter is a list of label
+// matchers (tenant="acme"), for SQL data sources a WHERE condition.
+type QueryFilter struct {
+	DatasourceType string `json:"datasourceType"`
+	Filter         string `json:"filter"`
+}
+
+type QueryFilters []QueryFilter
+
+func (f *QueryFilters) FromDB(data []byte) error {
+	return json.Unmarshal(data, f)
+}
+
+func (f *QueryFilters) ToDB() ([]byte, error) {
+	return json.Marshal(f)
+}
+
+// build time settings object from json on public dashboard. If empty, or in
+// the dashboard mode, use defaults on the dashboard
 func (pd PublicDashboard) BuildTimeSettings(dashboard *models.Dashboard) TimeSettings {
 	from := dashboard.Data.GetPath("time", "from").MustString()
 	to := dashboard.Data.GetPath("time", "to").MustString()
+
+	if pd.TimeSettings.IsFixed() {
+		from = pd.TimeSettings.From
+		to = pd.TimeSettings.To
+	}
+
+	// the time range of a scoped access token can't be changed by anything
+	if pd.Scope != nil && pd.Scope.TimeSettings != nil {
+		from = pd.Scope.TimeSettings.From
+		to = pd.Scope.TimeSettings.To
+	}
+
 	timeRange := legacydata.NewDataTimeRange(from, to)
 
 	// Were using epoch ms because this is used to build a MetricRequest, which is used by query caching, which expected the time range in epoch milliseconds.
@@ -107,11 +386,249 @@ func (pd PublicDashboard) BuildTimeSettings(dashboard *models.Dashboard) TimeSet
 		To:   strconv.FormatInt(timeRange.GetToAsMsEpoch(), 10),
 	}
 
-	if pd.TimeSettings == nil {
-		return ts
+	return ts
+}
+
+// IndexingAllowed returns true if search engines may index the public
+// dashboard, falling back to the instance default when it isn't configured
+func (pd PublicDashboard) IndexingAllowed(instanceDefault bool) bool {
+	if pd.AllowIndexing == nil {
+		return instanceDefault
 	}
+	return *pd.AllowIndexing
+}
 
-	return ts
+const (
+	HistoryActionCreated  = "created"
+	HistoryActionUpdated  = "updated"
+	HistoryActionEnabled  = "enabled"
+	HistoryActionDisabled = "disabled"
+	HistoryActionDeleted  = "deleted"
+)
+
+// PublicDashboardHistory is a snapshot of a public dashboard configuration
+// taken every time it is saved, with the user who saved it
+type PublicDashboardHistory struct {
+	Id                 int64         `json:"id" xorm:"pk autoincr 'id'"`
+	PublicDashboardUid string        `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	DashboardUid       string        `json:"dashboardUid" xorm:"dashboard_uid"`
+	OrgId              int64         `json:"-" xorm:"org_id"`
+	Action             string        `json:"action" xorm:"action"`
+	IsEnabled          bool          `json:"isEnabled" xorm:"is_enabled"`
+	TimeSettings       *TimeSettings `json:"timeSettings" xorm:"time_settings"`
+	DashboardVersion   int           `json:"dashboardVersion" xorm:"dashboard_version"`
+	AccessToken        string        `json:"accessToken" xorm:"access_token"`
+
+	CreatedBy int64     `json:"createdBy" xorm:"created_by"`
+	CreatedAt time.Time `json:"createdAt" xorm:"created_at"`
+}
+
+func (h PublicDashboardHistory) TableName() string {
+	return "dashboard_public_history"
+}
+
+// OrgSettings holds the public dashboards settings of an organization
+type OrgSettings struct {
+	OrgId              int64                `json:"-" xorm:"pk org_id"`
+	AllowedDatasources *DatasourceAllowlist `json:"allowedDatasources" xorm:"allowed_datasources"`
+	// CountryRestrictions restrict the countries public dashboards are served
+	// to, for compliance regimes prohibiting serving data to some regions
+	CountryRestrictions *CountryRestrictions `json:"countryRestrictions" xorm:"country_restrictions"`
+
+	UpdatedBy int64     `json:"updatedBy" xorm:"updated_by"`
+	UpdatedAt time.Time `json:"updatedAt" xorm:"updated_at"`
+}
+
+func (s OrgSettings) TableName() string {
+	return "dashboard_public_settings"
+}
+
+// DatasourceAllowlist lists the data sources, by uid or by type, that may be
+// queried through public dashboards. An empty allowlist allows all data sources.
+type DatasourceAllowlist struct {
+	Uids  []string `json:"uids"`
+	Types []string `json:"types"`
+}
+
+func (l *DatasourceAllowlist) FromDB(data []byte) error {
+	return json.Unmarshal(data, l)
+}
+
+func (l *DatasourceAllowlist) ToDB() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+// IsEmpty returns true when no data source restriction is configured
+func (l *DatasourceAllowlist) IsEmpty() bool {
+	return l == nil || (len(l.Uids) == 0 && len(l.Types) == 0)
+}
+
+// Allows returns true if a data source with the given uid and type may be queried
+func (l *DatasourceAllowlist) Allows(uid string, dsType string) bool {
+	if l.IsEmpty() {
+		return true
+	}
+
+	for _, allowed := range l.Uids {
+		if allowed == uid {
+			return true
+		}
+	}
+
+	for _, allowed := range l.Types {
+		if dsType != "" && allowed == dsType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CountryRestrictions lists the countries, as ISO 3166-1 alpha-2 codes, public
+// dashboards are served to or not. When Allowed isn't empty only the listed
+// countries are served, and clients whose country is unknown are rejected.
+type CountryRestrictions struct {
+	Allowed []string `json:"allowed"`
+	Denied  []string `json:"denied"`
+}
+
+func (r *CountryRestrictions) FromDB(data []byte) error {
+	return json.Unmarshal(data, r)
+}
+
+func (r *CountryRestrictions) ToDB() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// IsEmpty returns true when no country restriction is configured
+func (r *CountryRestrictions) IsEmpty() bool {
+	return r == nil || (len(r.Allowed) == 0 && len(r.Denied) == 0)
+}
+
+// Allows returns true if public dashboards may be served to a client from the
+// given country, an empty country means that it is unknown
+func (r *CountryRestrictions) Allows(country string) bool {
+	if r.IsEmpty() {
+		return true
+	}
+
+	for _, denied := range r.Denied {
+		if strings.EqualFold(denied, country) {
+			return false
+		}
+	}
+
+	if len(r.Allowed) == 0 {
+		return true
+	}
+
+	for _, allowed := range r.Allowed {
+		if country != "" && strings.EqualFold(allowed, country) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ScopedAccessToken is an additional access token of a public dashboard that
+// only gives access to some of its panels, and to a fixed time range when
+// TimeSettings is set. It lets external viewers see exactly what they are
+// entitled to.
+type ScopedAccessToken struct {
+	Uid                string        `json:"uid" xorm:"pk uid"`
+	PublicDashboardUid string        `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	OrgId              int64         `json:"-" xorm:"org_id"`
+	Name               string        `json:"name" xorm:"name"`
+	AccessToken        string        `json:"accessToken" xorm:"access_token"`
+	PanelIds           PanelIds      `json:"panelIds" xorm:"panel_ids"`
+	TimeSettings       *TimeSettings `json:"timeSettings" xorm:"time_settings"`
+	// Expires is the unix timestamp at which the token expires. The token
+	// never expires when nil.
+	Expires *int64 `json:"expires" xorm:"expires"`
+	// QueryQuota and ByteQuota limit the number of queries and the size of
+	// the query results served with the token, zero means unlimited. The
+	// token is disabled once one of them is exhausted.
+	QueryQuota int64 `json:"queryQuota" xorm:"query_quota"`
+	ByteQuota  int64 `json:"byteQuota" xorm:"byte_quota"`
+	QueryCount int64 `json:"queryCount" xorm:"query_count"`
+	ByteCount  int64 `json:"byteCount" xorm:"byte_count"`
+	IsDisabled bool  `json:"isDisabled" xorm:"is_disabled"`
+
+	CreatedBy int64     `json:"createdBy" xorm:"created_by"`
+	CreatedAt time.Time `json:"createdAt" xorm:"created_at"`
+}
+
+func (t ScopedAccessToken) TableName() string {
+	return "dashboard_public_scoped_token"
+}
+
+// IsUsable returns true if the token is neither disabled nor expired
+func (t ScopedAccessToken) IsUsable(now time.Time) bool {
+	if t.IsDisabled {
+		return false
+	}
+	return t.Expires == nil || *t.Expires > now.Unix()
+}
+
+type PanelIds []int64
+
+func (ids *PanelIds) FromDB(data []byte) error {
+	return json.Unmarshal(data, ids)
+}
+
+func (ids *PanelIds) ToDB() ([]byte, error) {
+	return json.Marshal(ids)
+}
+
+// PublicPlaylist shares a playlist with public viewers. The public playlist
+// cycles through the enabled public dashboards of the playlist dashboards,
+// the other dashboards are skipped.
+type PublicPlaylist struct {
+	Uid         string `json:"uid" xorm:"pk uid"`
+	PlaylistUid string `json:"playlistUid" xorm:"playlist_uid"`
+	OrgId       int64  `json:"-" xorm:"org_id"`
+	IsEnabled   bool   `json:"isEnabled" xorm:"is_enabled"`
+	AccessToken string `json:"accessToken" xorm:"access_token"`
+
+	CreatedBy int64 `json:"createdBy" xorm:"created_by"`
+	UpdatedBy int64 `json:"updatedBy" xorm:"updated_by"`
+
+	CreatedAt time.Time `json:"createdAt" xorm:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" xorm:"updated_at"`
+}
+
+func (pp PublicPlaylist) TableName() string {
+	return "dashboard_public_playlist"
+}
+
+// PublicPlaylistItem is a public dashboard shown by a public playlist
+type PublicPlaylistItem struct {
+	AccessToken string `json:"accessToken" xorm:"access_token"`
+	Title       string `json:"title" xorm:"title"`
+}
+
+// PublicPlaylistDTO is the playlist served to public viewers
+type PublicPlaylistDTO struct {
+	Name     string                `json:"name"`
+	Interval string                `json:"interval"`
+	Items    []*PublicPlaylistItem `json:"items"`
+}
+
+// PanelAlertStates counts the alerts of a public dashboard panel by state.
+// Only the counts are exposed to public viewers, never the alert rules.
+type PanelAlertStates struct {
+	Firing  int64 `json:"firing"`
+	Pending int64 `json:"pending"`
+	Ok      int64 `json:"ok"`
+	NoData  int64 `json:"noData"`
+	Error   int64 `json:"error"`
+}
+
+// AlertStateCount is the number of alerts in a state
+type AlertStateCount struct {
+	State string `xorm:"state"`
+	Count int64  `xorm:"state_count"`
 }
 
 // DTO for transforming user input in the api
@@ -122,6 +639,112 @@ type SavePublicDashboardConfigDTO struct {
 	PublicDashboard *PublicDashboard
 }
 
+// DTO for cloning the public dashboard configuration of a dashboard onto
+// another dashboard
+type ClonePublicDashboardConfigDTO struct {
+	SourceDashboardUid string
+	DashboardUid       string
+	OrgId              int64
+	UserId             int64
+}
+
+// DTO for disabling or deleting every public dashboard of the dashboards of a
+// folder or with a tag at once
+type BulkDisablePublicDashboardsDTO struct {
+	FolderUid string `json:"folderUid"`
+	Tag       string `json:"tag"`
+	// Delete deletes the public dashboards along with their scoped access
+	// tokens instead of disabling them
+	Delete bool  `json:"delete"`
+	OrgId  int64 `json:"-"`
+	UserId int64 `json:"-"`
+}
+
+// BulkDisablePublicDashboardsResult lists the dashboards whose public
+// dashboard got disabled or deleted
+type BulkDisablePublicDashboardsResult struct {
+	DashboardUids []string `json:"dashboardUids"`
+}
+
+// PrerenderedQueryDataResponse is the query response of a public dashboard
+// panel executed in the background, served to anonymous viewers along with
+// the time it was rendered at and the request ID of its queries
+type PrerenderedQueryDataResponse struct {
+	Response   *backend.QueryDataResponse
+	RenderedAt time.Time
+	RequestID  string
+}
+
+// PublicDashboardHealth is the status of a public dashboard, for external
+// monitoring of status pages. It doesn't name the data sources of the
+// dashboard, anyone with the access token can get it.
+type PublicDashboardHealth struct {
+	Healthy                bool `json:"healthy"`
+	Enabled                bool `json:"enabled"`
+	Expired                bool `json:"expired"`
+	DatasourcesReachable   bool `json:"datasourcesReachable"`
+	UnreachableDatasources int  `json:"unreachableDatasources"`
+}
+
+const (
+	PreflightSeverityUnsupported = "unsupported"
+	PreflightSeverityDegraded    = "degraded"
+)
+
+const (
+	PreflightIssueTemplateVariables     = "template-variables"
+	PreflightIssueUnsupportedDatasource = "unsupported-datasource"
+	PreflightIssueLibraryPanel          = "library-panel"
+	PreflightIssueAnnotations           = "annotations"
+)
+
+// PublicDashboardPreflight reports what would be unsupported or degraded if
+// a dashboard was shared publicly
+type PublicDashboardPreflight struct {
+	// Shareable is false when the dashboard can't be made public at all
+	Shareable bool             `json:"shareable"`
+	Issues    []PreflightIssue `json:"issues"`
+}
+
+// PreflightIssue is a problem of a panel, or of the whole dashboard when
+// PanelId is zero, when shared publicly
+type PreflightIssue struct {
+	PanelId    int64  `json:"panelId,omitempty"`
+	PanelTitle string `json:"panelTitle,omitempty"`
+	Kind       string `json:"kind"`
+	Severity   string `json:"severity"`
+	Message    string `json:"message"`
+}
+
+// DTO for transforming public playlist user input in the api
+type SavePublicPlaylistConfigDTO struct {
+	PlaylistUid    string
+	OrgId          int64
+	UserId         int64
+	PublicPlaylist *PublicPlaylist
+}
+
+// DTO for transforming scoped access token user input in the api
+type SaveScopedAccessTokenDTO struct {
+	DashboardUid      string
+	OrgId             int64
+	UserId            int64
+	ScopedAccessToken *ScopedAccessToken
+}
+
+// DTO for saving org settings in the api
+type SaveOrgSettingsDTO struct {
+	OrgId    int64
+	UserId   int64
+	Settings *OrgSettings
+}
+
+// DTO for the solution of a public dashboard challenge
+type SolveChallengeDTO struct {
+	Token    string `json:"token"`
+	Solution string `json:"solution"`
+}
+
 type PublicDashboardQueryDTO struct {
 	IntervalMs    int64
 	MaxDataPoints int64
@@ -134,3 +757,19 @@ type PublicDashboardQueryDTO struct {
 type SavePublicDashboardConfigCommand struct {
 	PublicDashboard PublicDashboard
 }
+
+type SavePublicPlaylistConfigCommand struct {
+	PublicPlaylist PublicPlaylist
+}
+
+type SaveScopedAccessTokenCommand struct {
+	ScopedAccessToken ScopedAccessToken
+}
+
+type SaveOrgSettingsCommand struct {
+	Settings OrgSettings
+}
+
+type SavePublicDashboardHistoryCommand struct {
+	History PublicDashboardHistory
+}
@@ -32,14 +32,44 @@ func TestBuildTimeSettings(t *testing.T) {
 			},
 		},
 		{
-			name:      "should use dashboard time even if pubdash time exists",
+			name:      "should use pubdash time if it exists",
 			dashboard: &models.Dashboard{Data: dashboardData},
-			pubdash:   &PublicDashboard{TimeSettings: &TimeSettings{From: "now-12", To: "now"}},
+			pubdash:   &PublicDashboard{TimeSettings: &TimeSettings{From: "1661990400000", To: "1662004800000"}},
+			timeResult: TimeSettings{
+				From: "1661990400000",
+				To:   "1662004800000",
+			},
+		},
+		{
+			name:      "should use pubdash time in the fixed mode",
+			dashboard: &models.Dashboard{Data: dashboardData},
+			pubdash:   &PublicDashboard{TimeSettings: &TimeSettings{Mode: TimeModeFixed, From: "1661990400000", To: "1662004800000"}},
+			timeResult: TimeSettings{
+				From: "1661990400000",
+				To:   "1662004800000",
+			},
+		},
+		{
+			name:      "should use dashboard time in the dashboard mode even if pubdash time exists",
+			dashboard: &models.Dashboard{Data: dashboardData},
+			pubdash:   &PublicDashboard{TimeSettings: &TimeSettings{Mode: TimeModeDashboard, From: "1661990400000", To: "1662004800000"}},
 			timeResult: TimeSettings{
 				From: fromMs,
 				To:   toMs,
 			},
 		},
+		{
+			name:      "should use scoped access token time over pubdash time",
+			dashboard: &models.Dashboard{Data: dashboardData},
+			pubdash: &PublicDashboard{
+				TimeSettings: &TimeSettings{From: "1661990400000", To: "1662004800000"},
+				Scope:        &ScopedAccessToken{TimeSettings: &TimeSettings{From: "1661994000000", To: "1661997600000"}},
+			},
+			timeResult: TimeSettings{
+				From: "1661994000000",
+				To:   "1661997600000",
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -48,3 +78,60 @@ func TestBuildTimeSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestDatasourceAllowlist(t *testing.T) {
+	testCases := []struct {
+		name      string
+		allowlist *DatasourceAllowlist
+		uid       string
+		dsType    string
+		allowed   bool
+	}{
+		{name: "nil allowlist allows everything", allowlist: nil, uid: "ds1", dsType: "mysql", allowed: true},
+		{name: "empty allowlist allows everything", allowlist: &DatasourceAllowlist{}, uid: "ds1", dsType: "mysql", allowed: true},
+		{name: "allows by uid", allowlist: &DatasourceAllowlist{Uids: []string{"ds1"}}, uid: "ds1", dsType: "mysql", allowed: true},
+		{name: "allows by type", allowlist: &DatasourceAllowlist{Types: []string{"prometheus"}}, uid: "ds2", dsType: "prometheus", allowed: true},
+		{name: "rejects unlisted uid", allowlist: &DatasourceAllowlist{Uids: []string{"ds1"}}, uid: "ds2", dsType: "mysql", allowed: false},
+		{name: "rejects unlisted type", allowlist: &DatasourceAllowlist{Types: []string{"prometheus"}}, uid: "ds1", dsType: "mysql", allowed: false},
+		{name: "rejects unknown type", allowlist: &DatasourceAllowlist{Types: []string{"prometheus"}}, uid: "ds1", dsType: "", allowed: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.allowed, test.allowlist.Allows(test.uid, test.dsType))
+		})
+	}
+}
+
+func TestCountryRestrictions(t *testing.T) {
+	testCases := []struct {
+		name         string
+		restrictions *CountryRestrictions
+		country      string
+		allowed      bool
+	}{
+		{name: "nil restrictions allow everything", restrictions: nil, country: "FR", allowed: true},
+		{name: "empty restrictions allow unknown countries", restrictions: &CountryRestrictions{}, country: "", allowed: true},
+		{name: "allows listed country", restrictions: &CountryRestrictions{Allowed: []string{"FR", "DE"}}, country: "de", allowed: true},
+		{name: "rejects unlisted country", restrictions: &CountryRestrictions{Allowed: []string{"FR"}}, country: "US", allowed: false},
+		{name: "rejects unknown country with allowed countries", restrictions: &CountryRestrictions{Allowed: []string{"FR"}}, country: "", allowed: false},
+		{name: "rejects denied country", restrictions: &CountryRestrictions{Denied: []string{"KP"}}, country: "KP", allowed: false},
+		{name: "allows unknown country with denied countries only", restrictions: &CountryRestrictions{Denied: []string{"KP"}}, country: "", allowed: true},
+		{name: "denied countries take precedence", restrictions: &CountryRestrictions{Allowed: []string{"FR"}, Denied: []string{"FR"}}, country: "FR", allowed: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.allowed, test.restrictions.Allows(test.country))
+		})
+	}
+}
+
+func TestIndexingAllowed(t *testing.T) {
+	allow, deny := true, false
+
+	assert.True(t, PublicDashboard{}.IndexingAllowed(true))
+	assert.False(t, PublicDashboard{}.IndexingAllowed(false))
+	assert.True(t, PublicDashboard{AllowIndexing: &allow}.IndexingAllowed(false))
+	assert.False(t, PublicDashboard{AllowIndexing: &deny}.IndexingAllowed(true))
+}
@@ -69,6 +69,152 @@ func (_m *FakePublicDashboardService) BuildAnonymousUser(ctx context.Context, da
 	return r0, r1
 }
 
+// BulkDisablePublicDashboards provides a mock function with given fields: ctx, u, dto
+func (_m *FakePublicDashboardService) BulkDisablePublicDashboards(ctx context.Context, u *user.SignedInUser, dto *publicdashboardsmodels.BulkDisablePublicDashboardsDTO) (*publicdashboardsmodels.BulkDisablePublicDashboardsResult, error) {
+	ret := _m.Called(ctx, u, dto)
+
+	var r0 *publicdashboardsmodels.BulkDisablePublicDashboardsResult
+	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, *publicdashboardsmodels.BulkDisablePublicDashboardsDTO) *publicdashboardsmodels.BulkDisablePublicDashboardsResult); ok {
+		r0 = rf(ctx, u, dto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.BulkDisablePublicDashboardsResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *user.SignedInUser, *publicdashboardsmodels.BulkDisablePublicDashboardsDTO) error); ok {
+		r1 = rf(ctx, u, dto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ChallengeRequired provides a mock function with given fields: ctx, accessToken
+func (_m *FakePublicDashboardService) ChallengeRequired(ctx context.Context, accessToken string) (bool, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClonePublicDashboardConfig provides a mock function with given fields: ctx, u, dto
+func (_m *FakePublicDashboardService) ClonePublicDashboardConfig(ctx context.Context, u *user.SignedInUser, dto *publicdashboardsmodels.ClonePublicDashboardConfigDTO) (*publicdashboardsmodels.PublicDashboard, error) {
+	ret := _m.Called(ctx, u, dto)
+
+	var r0 *publicdashboardsmodels.PublicDashboard
+	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, *publicdashboardsmodels.ClonePublicDashboardConfigDTO) *publicdashboardsmodels.PublicDashboard); ok {
+		r0 = rf(ctx, u, dto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.PublicDashboard)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *user.SignedInUser, *publicdashboardsmodels.ClonePublicDashboardConfigDTO) error); ok {
+		r1 = rf(ctx, u, dto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountryAllowed provides a mock function with given fields: ctx, accessToken, country
+func (_m *FakePublicDashboardService) CountryAllowed(ctx context.Context, accessToken string, country string) (bool, error) {
+	ret := _m.Called(ctx, accessToken, country)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, accessToken, country)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, accessToken, country)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateScopedAccessToken provides a mock function with given fields: ctx, u, dto
+func (_m *FakePublicDashboardService) CreateScopedAccessToken(ctx context.Context, u *user.SignedInUser, dto *publicdashboardsmodels.SaveScopedAccessTokenDTO) (*publicdashboardsmodels.ScopedAccessToken, error) {
+	ret := _m.Called(ctx, u, dto)
+
+	var r0 *publicdashboardsmodels.ScopedAccessToken
+	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, *publicdashboardsmodels.SaveScopedAccessTokenDTO) *publicdashboardsmodels.ScopedAccessToken); ok {
+		r0 = rf(ctx, u, dto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.ScopedAccessToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *user.SignedInUser, *publicdashboardsmodels.SaveScopedAccessTokenDTO) error); ok {
+		r1 = rf(ctx, u, dto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteScopedAccessToken provides a mock function with given fields: ctx, orgId, dashboardUid, uid
+func (_m *FakePublicDashboardService) DeleteScopedAccessToken(ctx context.Context, orgId int64, dashboardUid string, uid string) error {
+	ret := _m.Called(ctx, orgId, dashboardUid, uid)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) error); ok {
+		r0 = rf(ctx, orgId, dashboardUid, uid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExternalViewerRequired provides a mock function with given fields: ctx, accessToken
+func (_m *FakePublicDashboardService) ExternalViewerRequired(ctx context.Context, accessToken string) (bool, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDashboard provides a mock function with given fields: ctx, dashboardUid
 func (_m *FakePublicDashboardService) GetDashboard(ctx context.Context, dashboardUid string) (*models.Dashboard, error) {
 	ret := _m.Called(ctx, dashboardUid)
@@ -113,6 +259,75 @@ func (_m *FakePublicDashboardService) GetMetricRequest(ctx context.Context, dash
 	return r0, r1
 }
 
+// GetOrgSettings provides a mock function with given fields: ctx, orgId
+func (_m *FakePublicDashboardService) GetOrgSettings(ctx context.Context, orgId int64) (*publicdashboardsmodels.OrgSettings, error) {
+	ret := _m.Called(ctx, orgId)
+
+	var r0 *publicdashboardsmodels.OrgSettings
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *publicdashboardsmodels.OrgSettings); ok {
+		r0 = rf(ctx, orgId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.OrgSettings)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orgId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPanelAlertStates provides a mock function with given fields: ctx, accessToken, panelId
+func (_m *FakePublicDashboardService) GetPanelAlertStates(ctx context.Context, accessToken string, panelId int64) (*publicdashboardsmodels.PanelAlertStates, error) {
+	ret := _m.Called(ctx, accessToken, panelId)
+
+	var r0 *publicdashboardsmodels.PanelAlertStates
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) *publicdashboardsmodels.PanelAlertStates); ok {
+		r0 = rf(ctx, accessToken, panelId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.PanelAlertStates)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, accessToken, panelId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPrerenderedQueryDataResponse provides a mock function with given fields: ctx, panelId, accessToken
+func (_m *FakePublicDashboardService) GetPrerenderedQueryDataResponse(ctx context.Context, panelId int64, accessToken string) (*publicdashboardsmodels.PrerenderedQueryDataResponse, error) {
+	ret := _m.Called(ctx, panelId, accessToken)
+
+	var r0 *publicdashboardsmodels.PrerenderedQueryDataResponse
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *publicdashboardsmodels.PrerenderedQueryDataResponse); ok {
+		r0 = rf(ctx, panelId, accessToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.PrerenderedQueryDataResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, panelId, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPublicDashboard provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardService) GetPublicDashboard(ctx context.Context, accessToken string) (*publicdashboardsmodels.PublicDashboard, *models.Dashboard, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -168,6 +383,52 @@ func (_m *FakePublicDashboardService) GetPublicDashboardConfig(ctx context.Conte
 	return r0, r1
 }
 
+// GetPublicDashboardHealth provides a mock function with given fields: ctx, accessToken
+func (_m *FakePublicDashboardService) GetPublicDashboardHealth(ctx context.Context, accessToken string) (*publicdashboardsmodels.PublicDashboardHealth, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	var r0 *publicdashboardsmodels.PublicDashboardHealth
+	if rf, ok := ret.Get(0).(func(context.Context, string) *publicdashboardsmodels.PublicDashboardHealth); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.PublicDashboardHealth)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicDashboardHistory provides a mock function with given fields: ctx, orgId, dashboardUid
+func (_m *FakePublicDashboardService) GetPublicDashboardHistory(ctx context.Context, orgId int64, dashboardUid string) ([]*publicdashboardsmodels.PublicDashboardHistory, error) {
+	ret := _m.Called(ctx, orgId, dashboardUid)
+
+	var r0 []*publicdashboardsmodels.PublicDashboardHistory
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []*publicdashboardsmodels.PublicDashboardHistory); ok {
+		r0 = rf(ctx, orgId, dashboardUid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*publicdashboardsmodels.PublicDashboardHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, orgId, dashboardUid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPublicDashboardOrgId provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardService) GetPublicDashboardOrgId(ctx context.Context, accessToken string) (int64, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -189,6 +450,96 @@ func (_m *FakePublicDashboardService) GetPublicDashboardOrgId(ctx context.Contex
 	return r0, r1
 }
 
+// GetPublicDashboardPreflight provides a mock function with given fields: ctx, orgId, dashboardUid
+func (_m *FakePublicDashboardService) GetPublicDashboardPreflight(ctx context.Context, orgId int64, dashboardUid string) (*publicdashboardsmodels.PublicDashboardPreflight, error) {
+	ret := _m.Called(ctx, orgId, dashboardUid)
+
+	var r0 *publicdashboardsmodels.PublicDashboardPreflight
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *publicdashboardsmodels.PublicDashboardPreflight); ok {
+		r0 = rf(ctx, orgId, dashboardUid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.PublicDashboardPreflight)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, orgId, dashboardUid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicPlaylist provides a mock function with given fields: ctx, accessToken
+func (_m *FakePublicDashboardService) GetPublicPlaylist(ctx context.Context, accessToken string) (*publicdashboardsmodels.PublicPlaylistDTO, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	var r0 *publicdashboardsmodels.PublicPlaylistDTO
+	if rf, ok := ret.Get(0).(func(context.Context, string) *publicdashboardsmodels.PublicPlaylistDTO); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.PublicPlaylistDTO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicPlaylistConfig provides a mock function with given fields: ctx, orgId, playlistUid
+func (_m *FakePublicDashboardService) GetPublicPlaylistConfig(ctx context.Context, orgId int64, playlistUid string) (*publicdashboardsmodels.PublicPlaylist, error) {
+	ret := _m.Called(ctx, orgId, playlistUid)
+
+	var r0 *publicdashboardsmodels.PublicPlaylist
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *publicdashboardsmodels.PublicPlaylist); ok {
+		r0 = rf(ctx, orgId, playlistUid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.PublicPlaylist)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, orgId, playlistUid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicPlaylistOrgId provides a mock function with given fields: ctx, accessToken
+func (_m *FakePublicDashboardService) GetPublicPlaylistOrgId(ctx context.Context, accessToken string) (int64, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetQueryDataResponse provides a mock function with given fields: ctx, skipCache, reqDTO, panelId, accessToken
 func (_m *FakePublicDashboardService) GetQueryDataResponse(ctx context.Context, skipCache bool, reqDTO publicdashboardsmodels.PublicDashboardQueryDTO, panelId int64, accessToken string) (*backend.QueryDataResponse, error) {
 	ret := _m.Called(ctx, skipCache, reqDTO, panelId, accessToken)
@@ -212,6 +563,71 @@ func (_m *FakePublicDashboardService) GetQueryDataResponse(ctx context.Context,
 	return r0, r1
 }
 
+// GetScopedAccessTokens provides a mock function with given fields: ctx, orgId, dashboardUid
+func (_m *FakePublicDashboardService) GetScopedAccessTokens(ctx context.Context, orgId int64, dashboardUid string) ([]*publicdashboardsmodels.ScopedAccessToken, error) {
+	ret := _m.Called(ctx, orgId, dashboardUid)
+
+	var r0 []*publicdashboardsmodels.ScopedAccessToken
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []*publicdashboardsmodels.ScopedAccessToken); ok {
+		r0 = rf(ctx, orgId, dashboardUid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*publicdashboardsmodels.ScopedAccessToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, orgId, dashboardUid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTermsOfUse provides a mock function with given fields: ctx, accessToken
+func (_m *FakePublicDashboardService) GetTermsOfUse(ctx context.Context, accessToken string) (string, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IndexingAllowed provides a mock function with given fields: ctx, accessToken
+func (_m *FakePublicDashboardService) IndexingAllowed(ctx context.Context, accessToken string) (bool, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PublicDashboardEnabled provides a mock function with given fields: ctx, dashboardUid
 func (_m *FakePublicDashboardService) PublicDashboardEnabled(ctx context.Context, dashboardUid string) (bool, error) {
 	ret := _m.Called(ctx, dashboardUid)
@@ -233,6 +649,29 @@ func (_m *FakePublicDashboardService) PublicDashboardEnabled(ctx context.Context
 	return r0, r1
 }
 
+// SaveOrgSettings provides a mock function with given fields: ctx, dto
+func (_m *FakePublicDashboardService) SaveOrgSettings(ctx context.Context, dto *publicdashboardsmodels.SaveOrgSettingsDTO) (*publicdashboardsmodels.OrgSettings, error) {
+	ret := _m.Called(ctx, dto)
+
+	var r0 *publicdashboardsmodels.OrgSettings
+	if rf, ok := ret.Get(0).(func(context.Context, *publicdashboardsmodels.SaveOrgSettingsDTO) *publicdashboardsmodels.OrgSettings); ok {
+		r0 = rf(ctx, dto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.OrgSettings)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *publicdashboardsmodels.SaveOrgSettingsDTO) error); ok {
+		r1 = rf(ctx, dto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SavePublicDashboardConfig provides a mock function with given fields: ctx, u, dto
 func (_m *FakePublicDashboardService) SavePublicDashboardConfig(ctx context.Context, u *user.SignedInUser, dto *publicdashboardsmodels.SavePublicDashboardConfigDTO) (*publicdashboardsmodels.PublicDashboard, error) {
 	ret := _m.Called(ctx, u, dto)
@@ -256,6 +695,29 @@ func (_m *FakePublicDashboardService) SavePublicDashboardConfig(ctx context.Cont
 	return r0, r1
 }
 
+// SavePublicPlaylistConfig provides a mock function with given fields: ctx, u, dto
+func (_m *FakePublicDashboardService) SavePublicPlaylistConfig(ctx context.Context, u *user.SignedInUser, dto *publicdashboardsmodels.SavePublicPlaylistConfigDTO) (*publicdashboardsmodels.PublicPlaylist, error) {
+	ret := _m.Called(ctx, u, dto)
+
+	var r0 *publicdashboardsmodels.PublicPlaylist
+	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, *publicdashboardsmodels.SavePublicPlaylistConfigDTO) *publicdashboardsmodels.PublicPlaylist); ok {
+		r0 = rf(ctx, u, dto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.PublicPlaylist)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *user.SignedInUser, *publicdashboardsmodels.SavePublicPlaylistConfigDTO) error); ok {
+		r1 = rf(ctx, u, dto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewFakePublicDashboardService creates a new instance of FakePublicDashboardService. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
 func NewFakePublicDashboardService(t testing.TB) *FakePublicDashboardService {
 	mock := &FakePublicDashboardService{}
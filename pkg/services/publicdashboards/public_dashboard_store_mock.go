@@ -8,9 +8,13 @@ import (
 	models "github.com/grafana/grafana/pkg/models"
 	mock "github.com/stretchr/testify/mock"
 
+	playlist "github.com/grafana/grafana/pkg/coremodel/playlist"
+
 	publicdashboardsmodels "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 
 	testing "testing"
+
+	time "time"
 )
 
 // FakePublicDashboardStore is an autogenerated mock type for the Store type
@@ -39,6 +43,76 @@ func (_m *FakePublicDashboardStore) AccessTokenExists(ctx context.Context, acces
 	return r0, r1
 }
 
+// DeletePublicDashboard provides a mock function with given fields: ctx, uid
+func (_m *FakePublicDashboardStore) DeletePublicDashboard(ctx context.Context, uid string) error {
+	ret := _m.Called(ctx, uid)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, uid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePublicDashboardHistoryBefore provides a mock function with given fields: ctx, before
+func (_m *FakePublicDashboardStore) DeletePublicDashboardHistoryBefore(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteScopedAccessToken provides a mock function with given fields: ctx, publicDashboardUid, uid
+func (_m *FakePublicDashboardStore) DeleteScopedAccessToken(ctx context.Context, publicDashboardUid string, uid string) error {
+	ret := _m.Called(ctx, publicDashboardUid, uid)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, publicDashboardUid, uid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableExpiredScopedAccessTokens provides a mock function with given fields: ctx, now
+func (_m *FakePublicDashboardStore) DisableExpiredScopedAccessTokens(ctx context.Context, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, now)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GenerateNewPublicDashboardUid provides a mock function with given fields: ctx
 func (_m *FakePublicDashboardStore) GenerateNewPublicDashboardUid(ctx context.Context) (string, error) {
 	ret := _m.Called(ctx)
@@ -60,6 +134,48 @@ func (_m *FakePublicDashboardStore) GenerateNewPublicDashboardUid(ctx context.Co
 	return r0, r1
 }
 
+// GenerateNewPublicPlaylistUid provides a mock function with given fields: ctx
+func (_m *FakePublicDashboardStore) GenerateNewPublicPlaylistUid(ctx context.Context) (string, error) {
+	ret := _m.Called(ctx)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateNewScopedAccessTokenUid provides a mock function with given fields: ctx
+func (_m *FakePublicDashboardStore) GenerateNewScopedAccessTokenUid(ctx context.Context) (string, error) {
+	ret := _m.Called(ctx)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDashboard provides a mock function with given fields: ctx, dashboardUid
 func (_m *FakePublicDashboardStore) GetDashboard(ctx context.Context, dashboardUid string) (*models.Dashboard, error) {
 	ret := _m.Called(ctx, dashboardUid)
@@ -83,6 +199,98 @@ func (_m *FakePublicDashboardStore) GetDashboard(ctx context.Context, dashboardU
 	return r0, r1
 }
 
+// GetLegacyPanelAlertStates provides a mock function with given fields: ctx, dashboard, panelId
+func (_m *FakePublicDashboardStore) GetLegacyPanelAlertStates(ctx context.Context, dashboard *models.Dashboard, panelId int64) ([]publicdashboardsmodels.AlertStateCount, error) {
+	ret := _m.Called(ctx, dashboard, panelId)
+
+	var r0 []publicdashboardsmodels.AlertStateCount
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Dashboard, int64) []publicdashboardsmodels.AlertStateCount); ok {
+		r0 = rf(ctx, dashboard, panelId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]publicdashboardsmodels.AlertStateCount)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Dashboard, int64) error); ok {
+		r1 = rf(ctx, dashboard, panelId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrgSettings provides a mock function with given fields: ctx, orgId
+func (_m *FakePublicDashboardStore) GetOrgSettings(ctx context.Context, orgId int64) (*publicdashboardsmodels.OrgSettings, error) {
+	ret := _m.Called(ctx, orgId)
+
+	var r0 *publicdashboardsmodels.OrgSettings
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *publicdashboardsmodels.OrgSettings); ok {
+		r0 = rf(ctx, orgId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.OrgSettings)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orgId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPlaylistItemPublicDashboards provides a mock function with given fields: ctx, orgId, itemType, value
+func (_m *FakePublicDashboardStore) GetPlaylistItemPublicDashboards(ctx context.Context, orgId int64, itemType playlist.PlaylistItemType, value string) ([]*publicdashboardsmodels.PublicPlaylistItem, error) {
+	ret := _m.Called(ctx, orgId, itemType, value)
+
+	var r0 []*publicdashboardsmodels.PublicPlaylistItem
+	if rf, ok := ret.Get(0).(func(context.Context, int64, playlist.PlaylistItemType, string) []*publicdashboardsmodels.PublicPlaylistItem); ok {
+		r0 = rf(ctx, orgId, itemType, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*publicdashboardsmodels.PublicPlaylistItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, playlist.PlaylistItemType, string) error); ok {
+		r1 = rf(ctx, orgId, itemType, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPrerenderedPublicDashboards provides a mock function with given fields: ctx
+func (_m *FakePublicDashboardStore) GetPrerenderedPublicDashboards(ctx context.Context) ([]*publicdashboardsmodels.PublicDashboard, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*publicdashboardsmodels.PublicDashboard
+	if rf, ok := ret.Get(0).(func(context.Context) []*publicdashboardsmodels.PublicDashboard); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*publicdashboardsmodels.PublicDashboard)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPublicDashboard provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardStore) GetPublicDashboard(ctx context.Context, accessToken string) (*publicdashboardsmodels.PublicDashboard, *models.Dashboard, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -161,6 +369,29 @@ func (_m *FakePublicDashboardStore) GetPublicDashboardConfig(ctx context.Context
 	return r0, r1
 }
 
+// GetPublicDashboardHistory provides a mock function with given fields: ctx, orgId, dashboardUid
+func (_m *FakePublicDashboardStore) GetPublicDashboardHistory(ctx context.Context, orgId int64, dashboardUid string) ([]*publicdashboardsmodels.PublicDashboardHistory, error) {
+	ret := _m.Called(ctx, orgId, dashboardUid)
+
+	var r0 []*publicdashboardsmodels.PublicDashboardHistory
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []*publicdashboardsmodels.PublicDashboardHistory); ok {
+		r0 = rf(ctx, orgId, dashboardUid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*publicdashboardsmodels.PublicDashboardHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, orgId, dashboardUid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPublicDashboardOrgId provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardStore) GetPublicDashboardOrgId(ctx context.Context, accessToken string) (int64, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -182,6 +413,167 @@ func (_m *FakePublicDashboardStore) GetPublicDashboardOrgId(ctx context.Context,
 	return r0, r1
 }
 
+// GetPublicDashboardsByFolderOrTag provides a mock function with given fields: ctx, orgId, folderUid, tag
+func (_m *FakePublicDashboardStore) GetPublicDashboardsByFolderOrTag(ctx context.Context, orgId int64, folderUid string, tag string) ([]*publicdashboardsmodels.PublicDashboard, error) {
+	ret := _m.Called(ctx, orgId, folderUid, tag)
+
+	var r0 []*publicdashboardsmodels.PublicDashboard
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) []*publicdashboardsmodels.PublicDashboard); ok {
+		r0 = rf(ctx, orgId, folderUid, tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*publicdashboardsmodels.PublicDashboard)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, orgId, folderUid, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicPlaylist provides a mock function with given fields: ctx, accessToken
+func (_m *FakePublicDashboardStore) GetPublicPlaylist(ctx context.Context, accessToken string) (*publicdashboardsmodels.PublicPlaylist, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	var r0 *publicdashboardsmodels.PublicPlaylist
+	if rf, ok := ret.Get(0).(func(context.Context, string) *publicdashboardsmodels.PublicPlaylist); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.PublicPlaylist)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicPlaylistByUid provides a mock function with given fields: ctx, uid
+func (_m *FakePublicDashboardStore) GetPublicPlaylistByUid(ctx context.Context, uid string) (*publicdashboardsmodels.PublicPlaylist, error) {
+	ret := _m.Called(ctx, uid)
+
+	var r0 *publicdashboardsmodels.PublicPlaylist
+	if rf, ok := ret.Get(0).(func(context.Context, string) *publicdashboardsmodels.PublicPlaylist); ok {
+		r0 = rf(ctx, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.PublicPlaylist)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicPlaylistConfig provides a mock function with given fields: ctx, orgId, playlistUid
+func (_m *FakePublicDashboardStore) GetPublicPlaylistConfig(ctx context.Context, orgId int64, playlistUid string) (*publicdashboardsmodels.PublicPlaylist, error) {
+	ret := _m.Called(ctx, orgId, playlistUid)
+
+	var r0 *publicdashboardsmodels.PublicPlaylist
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *publicdashboardsmodels.PublicPlaylist); ok {
+		r0 = rf(ctx, orgId, playlistUid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.PublicPlaylist)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, orgId, playlistUid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetScopedAccessToken provides a mock function with given fields: ctx, accessToken
+func (_m *FakePublicDashboardStore) GetScopedAccessToken(ctx context.Context, accessToken string) (*publicdashboardsmodels.ScopedAccessToken, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	var r0 *publicdashboardsmodels.ScopedAccessToken
+	if rf, ok := ret.Get(0).(func(context.Context, string) *publicdashboardsmodels.ScopedAccessToken); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*publicdashboardsmodels.ScopedAccessToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetScopedAccessTokens provides a mock function with given fields: ctx, publicDashboardUid
+func (_m *FakePublicDashboardStore) GetScopedAccessTokens(ctx context.Context, publicDashboardUid string) ([]*publicdashboardsmodels.ScopedAccessToken, error) {
+	ret := _m.Called(ctx, publicDashboardUid)
+
+	var r0 []*publicdashboardsmodels.ScopedAccessToken
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*publicdashboardsmodels.ScopedAccessToken); ok {
+		r0 = rf(ctx, publicDashboardUid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*publicdashboardsmodels.ScopedAccessToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, publicDashboardUid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUnifiedPanelAlertStates provides a mock function with given fields: ctx, dashboard, panelId
+func (_m *FakePublicDashboardStore) GetUnifiedPanelAlertStates(ctx context.Context, dashboard *models.Dashboard, panelId int64) ([]publicdashboardsmodels.AlertStateCount, error) {
+	ret := _m.Called(ctx, dashboard, panelId)
+
+	var r0 []publicdashboardsmodels.AlertStateCount
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Dashboard, int64) []publicdashboardsmodels.AlertStateCount); ok {
+		r0 = rf(ctx, dashboard, panelId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]publicdashboardsmodels.AlertStateCount)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Dashboard, int64) error); ok {
+		r1 = rf(ctx, dashboard, panelId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PublicDashboardEnabled provides a mock function with given fields: ctx, dashboardUid
 func (_m *FakePublicDashboardStore) PublicDashboardEnabled(ctx context.Context, dashboardUid string) (bool, error) {
 	ret := _m.Called(ctx, dashboardUid)
@@ -203,6 +595,34 @@ func (_m *FakePublicDashboardStore) PublicDashboardEnabled(ctx context.Context,
 	return r0, r1
 }
 
+// RecordScopedAccessTokenUsage provides a mock function with given fields: ctx, uid, bytes
+func (_m *FakePublicDashboardStore) RecordScopedAccessTokenUsage(ctx context.Context, uid string, bytes int64) error {
+	ret := _m.Called(ctx, uid, bytes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, uid, bytes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveOrgSettings provides a mock function with given fields: ctx, cmd
+func (_m *FakePublicDashboardStore) SaveOrgSettings(ctx context.Context, cmd publicdashboardsmodels.SaveOrgSettingsCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, publicdashboardsmodels.SaveOrgSettingsCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SavePublicDashboardConfig provides a mock function with given fields: ctx, cmd
 func (_m *FakePublicDashboardStore) SavePublicDashboardConfig(ctx context.Context, cmd publicdashboardsmodels.SavePublicDashboardConfigCommand) error {
 	ret := _m.Called(ctx, cmd)
@@ -217,6 +637,48 @@ func (_m *FakePublicDashboardStore) SavePublicDashboardConfig(ctx context.Contex
 	return r0
 }
 
+// SavePublicDashboardHistory provides a mock function with given fields: ctx, cmd
+func (_m *FakePublicDashboardStore) SavePublicDashboardHistory(ctx context.Context, cmd publicdashboardsmodels.SavePublicDashboardHistoryCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, publicdashboardsmodels.SavePublicDashboardHistoryCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SavePublicPlaylistConfig provides a mock function with given fields: ctx, cmd
+func (_m *FakePublicDashboardStore) SavePublicPlaylistConfig(ctx context.Context, cmd publicdashboardsmodels.SavePublicPlaylistConfigCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, publicdashboardsmodels.SavePublicPlaylistConfigCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveScopedAccessToken provides a mock function with given fields: ctx, cmd
+func (_m *FakePublicDashboardStore) SaveScopedAccessToken(ctx context.Context, cmd publicdashboardsmodels.SaveScopedAccessTokenCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, publicdashboardsmodels.SaveScopedAccessTokenCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdatePublicDashboardConfig provides a mock function with given fields: ctx, cmd
 func (_m *FakePublicDashboardStore) UpdatePublicDashboardConfig(ctx context.Context, cmd publicdashboardsmodels.SavePublicDashboardConfigCommand) error {
 	ret := _m.Called(ctx, cmd)
@@ -231,6 +693,20 @@ func (_m *FakePublicDashboardStore) UpdatePublicDashboardConfig(ctx context.Cont
 	return r0
 }
 
+// UpdatePublicPlaylistConfig provides a mock function with given fields: ctx, cmd
+func (_m *FakePublicDashboardStore) UpdatePublicPlaylistConfig(ctx context.Context, cmd publicdashboardsmodels.SavePublicPlaylistConfigCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, publicdashboardsmodels.SavePublicPlaylistConfigCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewFakePublicDashboardStore creates a new instance of FakePublicDashboardStore. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
 func NewFakePublicDashboardStore(t testing.TB) *FakePublicDashboardStore {
 	mock := &FakePublicDashboardStore{}
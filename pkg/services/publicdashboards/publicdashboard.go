@@ -2,9 +2,11 @@ package publicdashboards
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/coremodel/playlist"
 	"github.com/grafana/grafana/pkg/models"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -16,26 +18,71 @@ import (
 type Service interface {
 	AccessTokenExists(ctx context.Context, accessToken string) (bool, error)
 	BuildAnonymousUser(ctx context.Context, dashboard *models.Dashboard) (*user.SignedInUser, error)
+	BulkDisablePublicDashboards(ctx context.Context, u *user.SignedInUser, dto *BulkDisablePublicDashboardsDTO) (*BulkDisablePublicDashboardsResult, error)
+	ChallengeRequired(ctx context.Context, accessToken string) (bool, error)
+	ClonePublicDashboardConfig(ctx context.Context, u *user.SignedInUser, dto *ClonePublicDashboardConfigDTO) (*PublicDashboard, error)
+	CountryAllowed(ctx context.Context, accessToken string, country string) (bool, error)
+	CreateScopedAccessToken(ctx context.Context, u *user.SignedInUser, dto *SaveScopedAccessTokenDTO) (*ScopedAccessToken, error)
+	DeleteScopedAccessToken(ctx context.Context, orgId int64, dashboardUid string, uid string) error
+	ExternalViewerRequired(ctx context.Context, accessToken string) (bool, error)
 	GetDashboard(ctx context.Context, dashboardUid string) (*models.Dashboard, error)
 	GetMetricRequest(ctx context.Context, dashboard *models.Dashboard, publicDashboard *PublicDashboard, panelId int64, reqDTO PublicDashboardQueryDTO) (dtos.MetricRequest, error)
+	GetOrgSettings(ctx context.Context, orgId int64) (*OrgSettings, error)
+	GetPanelAlertStates(ctx context.Context, accessToken string, panelId int64) (*PanelAlertStates, error)
+	GetPrerenderedQueryDataResponse(ctx context.Context, panelId int64, accessToken string) (*PrerenderedQueryDataResponse, error)
 	GetPublicDashboard(ctx context.Context, accessToken string) (*PublicDashboard, *models.Dashboard, error)
 	GetPublicDashboardConfig(ctx context.Context, orgId int64, dashboardUid string) (*PublicDashboard, error)
+	GetPublicDashboardHealth(ctx context.Context, accessToken string) (*PublicDashboardHealth, error)
+	GetPublicDashboardHistory(ctx context.Context, orgId int64, dashboardUid string) ([]*PublicDashboardHistory, error)
 	GetPublicDashboardOrgId(ctx context.Context, accessToken string) (int64, error)
+	GetPublicDashboardPreflight(ctx context.Context, orgId int64, dashboardUid string) (*PublicDashboardPreflight, error)
+	GetPublicPlaylist(ctx context.Context, accessToken string) (*PublicPlaylistDTO, error)
+	GetPublicPlaylistConfig(ctx context.Context, orgId int64, playlistUid string) (*PublicPlaylist, error)
+	GetPublicPlaylistOrgId(ctx context.Context, accessToken string) (int64, error)
 	GetQueryDataResponse(ctx context.Context, skipCache bool, reqDTO PublicDashboardQueryDTO, panelId int64, accessToken string) (*backend.QueryDataResponse, error)
+	GetScopedAccessTokens(ctx context.Context, orgId int64, dashboardUid string) ([]*ScopedAccessToken, error)
+	GetTermsOfUse(ctx context.Context, accessToken string) (string, error)
+	IndexingAllowed(ctx context.Context, accessToken string) (bool, error)
 	PublicDashboardEnabled(ctx context.Context, dashboardUid string) (bool, error)
+	SaveOrgSettings(ctx context.Context, dto *SaveOrgSettingsDTO) (*OrgSettings, error)
 	SavePublicDashboardConfig(ctx context.Context, u *user.SignedInUser, dto *SavePublicDashboardConfigDTO) (*PublicDashboard, error)
+	SavePublicPlaylistConfig(ctx context.Context, u *user.SignedInUser, dto *SavePublicPlaylistConfigDTO) (*PublicPlaylist, error)
 }
 
 //go:generate mockery --name Store --structname FakePublicDashboardStore --inpackage --filename public_dashboard_store_mock.go
 type Store interface {
 	AccessTokenExists(ctx context.Context, accessToken string) (bool, error)
+	DeletePublicDashboard(ctx context.Context, uid string) error
+	DeletePublicDashboardHistoryBefore(ctx context.Context, before time.Time) (int64, error)
+	DeleteScopedAccessToken(ctx context.Context, publicDashboardUid string, uid string) error
+	DisableExpiredScopedAccessTokens(ctx context.Context, now time.Time) (int64, error)
 	GenerateNewPublicDashboardUid(ctx context.Context) (string, error)
+	GenerateNewPublicPlaylistUid(ctx context.Context) (string, error)
+	GenerateNewScopedAccessTokenUid(ctx context.Context) (string, error)
 	GetDashboard(ctx context.Context, dashboardUid string) (*models.Dashboard, error)
+	GetLegacyPanelAlertStates(ctx context.Context, dashboard *models.Dashboard, panelId int64) ([]AlertStateCount, error)
+	GetOrgSettings(ctx context.Context, orgId int64) (*OrgSettings, error)
+	GetPlaylistItemPublicDashboards(ctx context.Context, orgId int64, itemType playlist.PlaylistItemType, value string) ([]*PublicPlaylistItem, error)
+	GetPrerenderedPublicDashboards(ctx context.Context) ([]*PublicDashboard, error)
 	GetPublicDashboard(ctx context.Context, accessToken string) (*PublicDashboard, *models.Dashboard, error)
 	GetPublicDashboardByUid(ctx context.Context, uid string) (*PublicDashboard, error)
 	GetPublicDashboardConfig(ctx context.Context, orgId int64, dashboardUid string) (*PublicDashboard, error)
+	GetPublicDashboardHistory(ctx context.Context, orgId int64, dashboardUid string) ([]*PublicDashboardHistory, error)
 	GetPublicDashboardOrgId(ctx context.Context, accessToken string) (int64, error)
+	GetPublicDashboardsByFolderOrTag(ctx context.Context, orgId int64, folderUid string, tag string) ([]*PublicDashboard, error)
+	GetPublicPlaylist(ctx context.Context, accessToken string) (*PublicPlaylist, error)
+	GetPublicPlaylistByUid(ctx context.Context, uid string) (*PublicPlaylist, error)
+	GetPublicPlaylistConfig(ctx context.Context, orgId int64, playlistUid string) (*PublicPlaylist, error)
+	GetScopedAccessToken(ctx context.Context, accessToken string) (*ScopedAccessToken, error)
+	GetScopedAccessTokens(ctx context.Context, publicDashboardUid string) ([]*ScopedAccessToken, error)
+	GetUnifiedPanelAlertStates(ctx context.Context, dashboard *models.Dashboard, panelId int64) ([]AlertStateCount, error)
 	PublicDashboardEnabled(ctx context.Context, dashboardUid string) (bool, error)
+	RecordScopedAccessTokenUsage(ctx context.Context, uid string, bytes int64) error
+	SaveOrgSettings(ctx context.Context, cmd SaveOrgSettingsCommand) error
 	SavePublicDashboardConfig(ctx context.Context, cmd SavePublicDashboardConfigCommand) error
+	SavePublicDashboardHistory(ctx context.Context, cmd SavePublicDashboardHistoryCommand) error
+	SavePublicPlaylistConfig(ctx context.Context, cmd SavePublicPlaylistConfigCommand) error
+	SaveScopedAccessToken(ctx context.Context, cmd SaveScopedAccessTokenCommand) error
 	UpdatePublicDashboardConfig(ctx context.Context, cmd SavePublicDashboardConfigCommand) error
+	UpdatePublicPlaylistConfig(ctx context.Context, cmd SavePublicPlaylistConfigCommand) error
 }
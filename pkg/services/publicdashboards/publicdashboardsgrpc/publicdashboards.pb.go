@@ -0,0 +1,496 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.21.7
+// source: publicdashboards.proto
+
+package publicdashboardsgrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// The public dashboard configuration of a dashboard
+type PublicDashboard struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Unique ID of the public dashboard
+	Uid string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	// UID of the shared dashboard
+	DashboardUid string `protobuf:"bytes,2,opt,name=dashboard_uid,json=dashboardUid,proto3" json:"dashboard_uid,omitempty"`
+	// Token public viewers access the dashboard with
+	AccessToken string `protobuf:"bytes,3,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	IsEnabled   bool   `protobuf:"varint,4,opt,name=is_enabled,json=isEnabled,proto3" json:"is_enabled,omitempty"`
+	// The complete configuration, JSON encoded like in the HTTP API
+	Body []byte `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+}
+
+func (x *PublicDashboard) Reset() {
+	*x = PublicDashboard{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publicdashboards_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublicDashboard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublicDashboard) ProtoMessage() {}
+
+func (x *PublicDashboard) ProtoReflect() protoreflect.Message {
+	mi := &file_publicdashboards_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublicDashboard.ProtoReflect.Descriptor instead.
+func (*PublicDashboard) Descriptor() ([]byte, []int) {
+	return file_publicdashboards_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PublicDashboard) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *PublicDashboard) GetDashboardUid() string {
+	if x != nil {
+		return x.DashboardUid
+	}
+	return ""
+}
+
+func (x *PublicDashboard) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *PublicDashboard) GetIsEnabled() bool {
+	if x != nil {
+		return x.IsEnabled
+	}
+	return false
+}
+
+func (x *PublicDashboard) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+type GetPublicDashboardRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DashboardUid string `protobuf:"bytes,1,opt,name=dashboard_uid,json=dashboardUid,proto3" json:"dashboard_uid,omitempty"`
+}
+
+func (x *GetPublicDashboardRequest) Reset() {
+	*x = GetPublicDashboardRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publicdashboards_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPublicDashboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublicDashboardRequest) ProtoMessage() {}
+
+func (x *GetPublicDashboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_publicdashboards_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPublicDashboardRequest.ProtoReflect.Descriptor instead.
+func (*GetPublicDashboardRequest) Descriptor() ([]byte, []int) {
+	return file_publicdashboards_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetPublicDashboardRequest) GetDashboardUid() string {
+	if x != nil {
+		return x.DashboardUid
+	}
+	return ""
+}
+
+type SavePublicDashboardRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DashboardUid string `protobuf:"bytes,1,opt,name=dashboard_uid,json=dashboardUid,proto3" json:"dashboard_uid,omitempty"`
+	// The configuration, JSON encoded like the body of the HTTP API
+	Body []byte `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+}
+
+func (x *SavePublicDashboardRequest) Reset() {
+	*x = SavePublicDashboardRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publicdashboards_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SavePublicDashboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SavePublicDashboardRequest) ProtoMessage() {}
+
+func (x *SavePublicDashboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_publicdashboards_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SavePublicDashboardRequest.ProtoReflect.Descriptor instead.
+func (*SavePublicDashboardRequest) Descriptor() ([]byte, []int) {
+	return file_publicdashboards_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SavePublicDashboardRequest) GetDashboardUid() string {
+	if x != nil {
+		return x.DashboardUid
+	}
+	return ""
+}
+
+func (x *SavePublicDashboardRequest) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+type DisablePublicDashboardsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Disable the public dashboards of the dashboards in this folder
+	FolderUid string `protobuf:"bytes,1,opt,name=folder_uid,json=folderUid,proto3" json:"folder_uid,omitempty"`
+	// Disable the public dashboards of the dashboards with this tag
+	Tag string `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+}
+
+func (x *DisablePublicDashboardsRequest) Reset() {
+	*x = DisablePublicDashboardsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publicdashboards_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisablePublicDashboardsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisablePublicDashboardsRequest) ProtoMessage() {}
+
+func (x *DisablePublicDashboardsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_publicdashboards_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisablePublicDashboardsRequest.ProtoReflect.Descriptor instead.
+func (*DisablePublicDashboardsRequest) Descriptor() ([]byte, []int) {
+	return file_publicdashboards_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DisablePublicDashboardsRequest) GetFolderUid() string {
+	if x != nil {
+		return x.FolderUid
+	}
+	return ""
+}
+
+func (x *DisablePublicDashboardsRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+type DisablePublicDashboardsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// UIDs of the dashboards whose public dashboard got disabled
+	DashboardUids []string `protobuf:"bytes,1,rep,name=dashboard_uids,json=dashboardUids,proto3" json:"dashboard_uids,omitempty"`
+}
+
+func (x *DisablePublicDashboardsResponse) Reset() {
+	*x = DisablePublicDashboardsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publicdashboards_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisablePublicDashboardsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisablePublicDashboardsResponse) ProtoMessage() {}
+
+func (x *DisablePublicDashboardsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_publicdashboards_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisablePublicDashboardsResponse.ProtoReflect.Descriptor instead.
+func (*DisablePublicDashboardsResponse) Descriptor() ([]byte, []int) {
+	return file_publicdashboards_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DisablePublicDashboardsResponse) GetDashboardUids() []string {
+	if x != nil {
+		return x.DashboardUids
+	}
+	return nil
+}
+
+var File_publicdashboards_proto protoreflect.FileDescriptor
+
+var file_publicdashboards_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72,
+	0x64, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x10, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x64, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x22, 0x9e, 0x01, 0x0a, 0x0f, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x63, 0x44, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x12, 0x10,
+	0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64,
+	0x12, 0x23, 0x0a, 0x0d, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x5f, 0x75, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61,
+	0x72, 0x64, 0x55, 0x69, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f, 0x65,
+	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73,
+	0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0x40, 0x0a, 0x19, 0x47,
+	0x65, 0x74, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x44, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x61, 0x73, 0x68,
+	0x62, 0x6f, 0x61, 0x72, 0x64, 0x5f, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x55, 0x69, 0x64, 0x22, 0x55, 0x0a,
+	0x1a, 0x53, 0x61, 0x76, 0x65, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x44, 0x61, 0x73, 0x68, 0x62,
+	0x6f, 0x61, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x64,
+	0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x5f, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x55, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x62, 0x6f, 0x64, 0x79, 0x22, 0x51, 0x0a, 0x1e, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x63, 0x44, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72,
+	0x5f, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x6f, 0x6c, 0x64,
+	0x65, 0x72, 0x55, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x22, 0x48, 0x0a, 0x1f, 0x44, 0x69, 0x73, 0x61, 0x62,
+	0x6c, 0x65, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x44, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72,
+	0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x64, 0x61,
+	0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x5f, 0x75, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0d, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x55, 0x69, 0x64,
+	0x73, 0x32, 0xe0, 0x02, 0x0a, 0x10, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x44, 0x61, 0x73, 0x68,
+	0x62, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x12, 0x64, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x50, 0x75, 0x62,
+	0x6c, 0x69, 0x63, 0x44, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x12, 0x2b, 0x2e, 0x70,
+	0x75, 0x62, 0x6c, 0x69, 0x63, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x2e,
+	0x47, 0x65, 0x74, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x44, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61,
+	0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x70, 0x75, 0x62, 0x6c,
+	0x69, 0x63, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x50, 0x75, 0x62,
+	0x6c, 0x69, 0x63, 0x44, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x12, 0x66, 0x0a, 0x13,
+	0x53, 0x61, 0x76, 0x65, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x44, 0x61, 0x73, 0x68, 0x62, 0x6f,
+	0x61, 0x72, 0x64, 0x12, 0x2c, 0x2e, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x64, 0x61, 0x73, 0x68,
+	0x62, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x50, 0x75, 0x62, 0x6c, 0x69,
+	0x63, 0x44, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x21, 0x2e, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f,
+	0x61, 0x72, 0x64, 0x73, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x44, 0x61, 0x73, 0x68, 0x62,
+	0x6f, 0x61, 0x72, 0x64, 0x12, 0x7e, 0x0a, 0x17, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x63, 0x44, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x12,
+	0x30, 0x2e, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72,
+	0x64, 0x73, 0x2e, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x44, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x31, 0x2e, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f,
+	0x61, 0x72, 0x64, 0x73, 0x2e, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x50, 0x75, 0x62, 0x6c,
+	0x69, 0x63, 0x44, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x42, 0x19, 0x5a, 0x17, 0x2e, 0x2f, 0x3b, 0x70, 0x75, 0x62, 0x6c, 0x69,
+	0x63, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x67, 0x72, 0x70, 0x63, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_publicdashboards_proto_rawDescOnce sync.Once
+	file_publicdashboards_proto_rawDescData = file_publicdashboards_proto_rawDesc
+)
+
+func file_publicdashboards_proto_rawDescGZIP() []byte {
+	file_publicdashboards_proto_rawDescOnce.Do(func() {
+		file_publicdashboards_proto_rawDescData = protoimpl.X.CompressGZIP(file_publicdashboards_proto_rawDescData)
+	})
+	return file_publicdashboards_proto_rawDescData
+}
+
+var file_publicdashboards_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_publicdashboards_proto_goTypes = []interface{}{
+	(*PublicDashboard)(nil),                 // 0: publicdashboards.PublicDashboard
+	(*GetPublicDashboardRequest)(nil),       // 1: publicdashboards.GetPublicDashboardRequest
+	(*SavePublicDashboardRequest)(nil),      // 2: publicdashboards.SavePublicDashboardRequest
+	(*DisablePublicDashboardsRequest)(nil),  // 3: publicdashboards.DisablePublicDashboardsRequest
+	(*DisablePublicDashboardsResponse)(nil), // 4: publicdashboards.DisablePublicDashboardsResponse
+}
+var file_publicdashboards_proto_depIdxs = []int32{
+	1, // 0: publicdashboards.PublicDashboards.GetPublicDashboard:input_type -> publicdashboards.GetPublicDashboardRequest
+	2, // 1: publicdashboards.PublicDashboards.SavePublicDashboard:input_type -> publicdashboards.SavePublicDashboardRequest
+	3, // 2: publicdashboards.PublicDashboards.DisablePublicDashboards:input_type -> publicdashboards.DisablePublicDashboardsRequest
+	0, // 3: publicdashboards.PublicDashboards.GetPublicDashboard:output_type -> publicdashboards.PublicDashboard
+	0, // 4: publicdashboards.PublicDashboards.SavePublicDashboard:output_type -> publicdashboards.PublicDashboard
+	4, // 5: publicdashboards.PublicDashboards.DisablePublicDashboards:output_type -> publicdashboards.DisablePublicDashboardsResponse
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_publicdashboards_proto_init() }
+func file_publicdashboards_proto_init() {
+	if File_publicdashboards_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_publicdashboards_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublicDashboard); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_publicdashboards_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPublicDashboardRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_publicdashboards_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SavePublicDashboardRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_publicdashboards_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DisablePublicDashboardsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_publicdashboards_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DisablePublicDashboardsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_publicdashboards_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_publicdashboards_proto_goTypes,
+		DependencyIndexes: file_publicdashboards_proto_depIdxs,
+		MessageInfos:      file_publicdashboards_proto_msgTypes,
+	}.Build()
+	File_publicdashboards_proto = out.File
+	file_publicdashboards_proto_rawDesc = nil
+	file_publicdashboards_proto_goTypes = nil
+	file_publicdashboards_proto_depIdxs = nil
+}
@@ -0,0 +1,137 @@
+package publicdashboardsgrpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/grpcserver"
+	grpccontext "github.com/grafana/grafana/pkg/services/grpcserver/context"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	publicdashboardModels "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProvideServer registers the public dashboards service on the gRPC server.
+// The calls are authenticated by the gRPC server as a service account, which
+// needs the same permissions as through the HTTP API.
+func ProvideServer(grpcServerProvider grpcserver.Provider, publicDashboardService publicdashboards.Service, accessControl accesscontrol.AccessControl, contextHandler grpccontext.ContextHandler) PublicDashboardsServer {
+	s := &server{
+		publicDashboards: publicDashboardService,
+		accessControl:    accessControl,
+		contextHandler:   contextHandler,
+	}
+	RegisterPublicDashboardsServer(grpcServerProvider.GetServer(), s)
+	return s
+}
+
+type server struct {
+	publicDashboards publicdashboards.Service
+	accessControl    accesscontrol.AccessControl
+	contextHandler   grpccontext.ContextHandler
+}
+
+// GetPublicDashboard returns the public dashboard of the dashboard, unlike
+// the HTTP API it fails with NotFound when the dashboard isn't shared.
+func (s *server) GetPublicDashboard(ctx context.Context, req *GetPublicDashboardRequest) (*PublicDashboard, error) {
+	u, err := s.authorize(ctx, accesscontrol.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(req.DashboardUid)))
+	if err != nil {
+		return nil, err
+	}
+
+	pubdash, err := s.publicDashboards.GetPublicDashboardConfig(ctx, u.OrgID, req.DashboardUid)
+	if err != nil {
+		return nil, grpcserver.ErrorToStatus(err)
+	}
+	if pubdash.Uid == "" {
+		return nil, status.Error(codes.NotFound, "public dashboard not found")
+	}
+
+	return toProto(pubdash)
+}
+
+func (s *server) SavePublicDashboard(ctx context.Context, req *SavePublicDashboardRequest) (*PublicDashboard, error) {
+	u, err := s.authorize(ctx, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(req.DashboardUid)))
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DashboardUid == "" || !util.IsValidShortUID(req.DashboardUid) {
+		return nil, status.Error(codes.InvalidArgument, "invalid dashboard uid")
+	}
+
+	pubdash := &publicdashboardModels.PublicDashboard{}
+	if err := json.Unmarshal(req.Body, pubdash); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid body: %s", err)
+	}
+	pubdash.OrgId = u.OrgID
+
+	pubdash, err = s.publicDashboards.SavePublicDashboardConfig(ctx, u, &publicdashboardModels.SavePublicDashboardConfigDTO{
+		UserId:          u.UserID,
+		OrgId:           u.OrgID,
+		DashboardUid:    req.DashboardUid,
+		PublicDashboard: pubdash,
+	})
+	if err != nil {
+		return nil, grpcserver.ErrorToStatus(err)
+	}
+
+	return toProto(pubdash)
+}
+
+func (s *server) DisablePublicDashboards(ctx context.Context, req *DisablePublicDashboardsRequest) (*DisablePublicDashboardsResponse, error) {
+	u, err := s.authorize(ctx, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.publicDashboards.BulkDisablePublicDashboards(ctx, u, &publicdashboardModels.BulkDisablePublicDashboardsDTO{
+		FolderUid: req.FolderUid,
+		Tag:       req.Tag,
+		OrgId:     u.OrgID,
+		UserId:    u.UserID,
+	})
+	if err != nil {
+		return nil, grpcserver.ErrorToStatus(err)
+	}
+
+	return &DisablePublicDashboardsResponse{DashboardUids: result.DashboardUids}, nil
+}
+
+// authorize returns the service account of the call if it has the permissions
+func (s *server) authorize(ctx context.Context, evaluator accesscontrol.Evaluator) (*user.SignedInUser, error) {
+	u := s.contextHandler.GetUser(ctx)
+	if u == nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+
+	ok, err := s.accessControl.Evaluate(ctx, u, evaluator)
+	if err != nil {
+		return nil, grpcserver.ErrorToStatus(err)
+	}
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	}
+
+	return u, nil
+}
+
+func toProto(pubdash *publicdashboardModels.PublicDashboard) (*PublicDashboard, error) {
+	body, err := json.Marshal(pubdash)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode the public dashboard: %s", err)
+	}
+
+	return &PublicDashboard{
+		Uid:          pubdash.Uid,
+		DashboardUid: pubdash.DashboardUid,
+		AccessToken:  pubdash.AccessToken,
+		IsEnabled:    pubdash.IsEnabled,
+		Body:         body,
+	}, nil
+}
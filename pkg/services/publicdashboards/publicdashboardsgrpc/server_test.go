@@ -0,0 +1,136 @@
+package publicdashboardsgrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/grpcserver/grpcservertest"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	publicdashboardModels "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func newClient(t *testing.T, service publicdashboards.Service, u *user.SignedInUser) PublicDashboardsClient {
+	srv := grpcservertest.NewServer(t, u)
+	ProvideServer(srv, service, acimpl.ProvideAccessControl(setting.NewCfg()), srv.ContextHandler)
+	return NewPublicDashboardsClient(srv.Dial(t))
+}
+
+func TestGetPublicDashboard(t *testing.T) {
+	u := &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: {
+		dashboards.ActionDashboardsRead: {dashboards.ScopeDashboardsProvider.GetResourceScopeUID("shared")},
+	}}}
+
+	service := publicdashboards.NewFakePublicDashboardService(t)
+	service.On("GetPublicDashboardConfig", mock.Anything, int64(1), "shared").
+		Return(&publicdashboardModels.PublicDashboard{Uid: "pubdash", DashboardUid: "shared", AccessToken: "token", IsEnabled: true}, nil)
+	client := newClient(t, service, u)
+
+	t.Run("returns the public dashboard", func(t *testing.T) {
+		pubdash, err := client.GetPublicDashboard(context.Background(), &GetPublicDashboardRequest{DashboardUid: "shared"})
+		require.NoError(t, err)
+		require.Equal(t, "pubdash", pubdash.Uid)
+		require.Equal(t, "token", pubdash.AccessToken)
+		require.True(t, pubdash.IsEnabled)
+		require.Contains(t, string(pubdash.Body), `"dashboardUid":"shared"`)
+	})
+
+	t.Run("fails with NotFound when the dashboard isn't shared", func(t *testing.T) {
+		service.ExpectedCalls = nil
+		service.On("GetPublicDashboardConfig", mock.Anything, int64(1), "shared").
+			Return(&publicdashboardModels.PublicDashboard{}, nil)
+
+		_, err := client.GetPublicDashboard(context.Background(), &GetPublicDashboardRequest{DashboardUid: "shared"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("requires read permissions on the dashboard", func(t *testing.T) {
+		_, err := client.GetPublicDashboard(context.Background(), &GetPublicDashboardRequest{DashboardUid: "other"})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestSavePublicDashboard(t *testing.T) {
+	u := &user.SignedInUser{OrgID: 1, UserID: 2, Permissions: map[int64]map[string][]string{1: {
+		dashboards.ActionDashboardsPublicWrite: {dashboards.ScopeDashboardsAll},
+	}}}
+
+	t.Run("saves the public dashboard", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("SavePublicDashboardConfig", mock.Anything, mock.Anything, mock.MatchedBy(func(dto *publicdashboardModels.SavePublicDashboardConfigDTO) bool {
+			return dto.DashboardUid == "shared" && dto.OrgId == 1 && dto.UserId == 2 && dto.PublicDashboard.IsEnabled
+		})).Return(&publicdashboardModels.PublicDashboard{Uid: "pubdash", DashboardUid: "shared", IsEnabled: true}, nil)
+
+		pubdash, err := newClient(t, service, u).SavePublicDashboard(context.Background(), &SavePublicDashboardRequest{
+			DashboardUid: "shared",
+			Body:         []byte(`{"isEnabled": true}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "pubdash", pubdash.Uid)
+		require.True(t, pubdash.IsEnabled)
+	})
+
+	t.Run("maps the service errors", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("SavePublicDashboardConfig", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, publicdashboardModels.ErrPublicDashboardHasTemplateVariables)
+
+		_, err := newClient(t, service, u).SavePublicDashboard(context.Background(), &SavePublicDashboardRequest{
+			DashboardUid: "shared",
+			Body:         []byte(`{"isEnabled": true}`),
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		client := newClient(t, publicdashboards.NewFakePublicDashboardService(t), u)
+
+		_, err := client.SavePublicDashboard(context.Background(), &SavePublicDashboardRequest{DashboardUid: "shared", Body: []byte(`{`)})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.SavePublicDashboard(context.Background(), &SavePublicDashboardRequest{DashboardUid: "not a uid!", Body: []byte(`{}`)})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("requires write permissions on the dashboard", func(t *testing.T) {
+		viewer := &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: {
+			dashboards.ActionDashboardsRead: {dashboards.ScopeDashboardsAll},
+		}}}
+		_, err := newClient(t, publicdashboards.NewFakePublicDashboardService(t), viewer).SavePublicDashboard(context.Background(), &SavePublicDashboardRequest{
+			DashboardUid: "shared",
+			Body:         []byte(`{"isEnabled": true}`),
+		})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestDisablePublicDashboards(t *testing.T) {
+	u := &user.SignedInUser{OrgID: 1, UserID: 2, Permissions: map[int64]map[string][]string{1: {
+		dashboards.ActionDashboardsPublicWrite: {dashboards.ScopeDashboardsAll},
+	}}}
+
+	service := publicdashboards.NewFakePublicDashboardService(t)
+	service.On("BulkDisablePublicDashboards", mock.Anything, mock.Anything, &publicdashboardModels.BulkDisablePublicDashboardsDTO{
+		FolderUid: "folder",
+		OrgId:     1,
+		UserId:    2,
+	}).Return(&publicdashboardModels.BulkDisablePublicDashboardsResult{DashboardUids: []string{"a", "b"}}, nil)
+
+	res, err := newClient(t, service, u).DisablePublicDashboards(context.Background(), &DisablePublicDashboardsRequest{FolderUid: "folder"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, res.DashboardUids)
+}
+
+func TestUnauthenticated(t *testing.T) {
+	_, err := newClient(t, publicdashboards.NewFakePublicDashboardService(t), nil).
+		GetPublicDashboard(context.Background(), &GetPublicDashboardRequest{DashboardUid: "shared"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -6,6 +6,10 @@ import (
 	"github.com/grafana/grafana/pkg/expr"
 )
 
+// MixedDatasourceUid is the datasource of panels whose targets each query
+// their own datasource
+const MixedDatasourceUid = "-- Mixed --"
+
 func GetUniqueDashboardDatasourceUids(dashboard *simplejson.Json) []string {
 	var datasourceUids []string
 	exists := map[string]bool{}
@@ -15,10 +19,13 @@ func GetUniqueDashboardDatasourceUids(dashboard *simplejson.Json) []string {
 		uid := GetDataSourceUidFromJson(panel)
 
 		// if uid is for a mixed datasource, get the datasource uids from the targets
-		if uid == "-- Mixed --" {
+		if uid == MixedDatasourceUid {
 			for _, target := range panel.Get("targets").MustArray() {
 				target := simplejson.NewFromAny(target)
-				datasourceUid := target.Get("datasource").Get("uid").MustString()
+				datasourceUid := GetDataSourceUidFromJson(target)
+				if datasourceUid == "" {
+					continue
+				}
 				if _, ok := exists[datasourceUid]; !ok {
 					datasourceUids = append(datasourceUids, datasourceUid)
 					exists[datasourceUid] = true
@@ -42,6 +49,7 @@ func GroupQueriesByPanelId(dashboard *simplejson.Json) map[int64][]*simplejson.J
 		panel := simplejson.NewFromAny(panelObj)
 
 		var panelQueries []*simplejson.Json
+		panelUid := GetDataSourceUidFromJson(panel)
 
 		for _, queryObj := range panel.Get("targets").MustArray() {
 			query := simplejson.NewFromAny(queryObj)
@@ -51,8 +59,12 @@ func GroupQueriesByPanelId(dashboard *simplejson.Json) map[int64][]*simplejson.J
 
 			// if query target has no datasource, set it to have the datasource on the panel
 			if _, ok := query.CheckGet("datasource"); !ok {
-				uid := GetDataSourceUidFromJson(panel)
-				datasource := map[string]interface{}{"type": "public-ds", "uid": uid}
+				// targets of mixed panels are routed to their own datasource,
+				// the ones without a datasource can't be routed anywhere
+				if panelUid == MixedDatasourceUid {
+					continue
+				}
+				datasource := map[string]interface{}{"type": "public-ds", "uid": panelUid}
 				query.Set("datasource", datasource)
 			}
 
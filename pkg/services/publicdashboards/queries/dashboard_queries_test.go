@@ -437,6 +437,29 @@ func TestGroupQueriesByPanelId(t *testing.T) {
 		}`, string(query))
 	})
 
+	t.Run("can extract queries from mixed datasource panels", func(t *testing.T) {
+		json, err := simplejson.NewJson([]byte(`{
+  "panels": [
+    {
+      "datasource": {"type": "datasource", "uid": "-- Mixed --"},
+      "id": 1,
+      "targets": [
+        {"datasource": {"type": "prometheus", "uid": "abc123"}, "refId": "A"},
+        {"datasource": {"type": "mysql", "uid": "def456"}, "refId": "B"},
+        {"refId": "C"}
+      ]
+    }
+  ]
+}`))
+		require.NoError(t, err)
+
+		queries := GroupQueriesByPanelId(json)
+		require.Len(t, queries[1], 2)
+		require.Equal(t, "abc123", GetDataSourceUidFromJson(queries[1][0]))
+		require.Equal(t, "def456", GetDataSourceUidFromJson(queries[1][1]))
+		require.Len(t, GroupQueriesByDataSource(queries[1]), 2)
+	})
+
 	t.Run("can extract queries from old-style panels", func(t *testing.T) {
 		json, err := simplejson.NewJson([]byte(oldStyleDashboard))
 		require.NoError(t, err)
@@ -0,0 +1,105 @@
+package queries
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+var ErrQueryFilterNotSupported = errors.New("query filters are not supported for this data source type")
+
+// matches the Grafana variables that are interpolated by the Prometheus data
+// source, like $__rate_interval and ${__range_s}
+var grafanaVariableRegex = regexp.MustCompile(`\$\{?__\w+\}?`)
+
+// QueryFilterSupported returns true if query filters can be injected into
+// queries of the data source type
+func QueryFilterSupported(dsType string) bool {
+	switch dsType {
+	case datasources.DS_PROMETHEUS, datasources.DS_MYSQL, datasources.DS_POSTGRES, datasources.DS_MSSQL:
+		return true
+	}
+	return false
+}
+
+// ParsePrometheusFilter parses a Prometheus query filter, a comma separated
+// list of label matchers
+func ParsePrometheusFilter(filter string) error {
+	_, err := parser.ParseMetricSelector("{" + filter + "}")
+	return err
+}
+
+// InjectQueryFilter rewrites the query so that it only returns the data
+// matching the filter
+func InjectQueryFilter(query *simplejson.Json, dsType string, filter string) error {
+	switch dsType {
+	case datasources.DS_PROMETHEUS:
+		expr, err := injectPrometheusFilter(query.Get("expr").MustString(), filter)
+		if err != nil {
+			return err
+		}
+		query.Set("expr", expr)
+	case datasources.DS_MYSQL, datasources.DS_POSTGRES, datasources.DS_MSSQL:
+		rawSql := strings.TrimSuffix(strings.TrimSpace(query.Get("rawSql").MustString()), ";")
+		if rawSql == "" {
+			return fmt.Errorf("query has no raw SQL")
+		}
+		query.Set("rawSql", fmt.Sprintf("SELECT * FROM (\n%s\n) AS public_dashboard_filtered WHERE %s", rawSql, filter))
+	default:
+		return ErrQueryFilterNotSupported
+	}
+
+	return nil
+}
+
+// injectPrometheusFilter adds the label matchers of the filter to every
+// selector of the expression
+func injectPrometheusFilter(expr string, filter string) (string, error) {
+	matchers, err := parser.ParseMetricSelector("{" + filter + "}")
+	if err != nil {
+		return "", err
+	}
+
+	// the Grafana variables aren't valid PromQL, swap them with placeholders
+	// that survive parsing and printing the expression
+	placeholders := map[string]string{}
+	expr = grafanaVariableRegex.ReplaceAllStringFunc(expr, func(variable string) string {
+		if placeholder, ok := placeholders[variable]; ok {
+			return placeholder
+		}
+
+		// variables ending in _s or _ms are numbers, the other ones durations
+		name := strings.TrimSuffix(variable, "}")
+		placeholder := fmt.Sprintf("%dy", 250+len(placeholders))
+		if strings.HasSuffix(name, "_s") || strings.HasSuffix(name, "_ms") {
+			placeholder = fmt.Sprintf("98765.4%d1", len(placeholders))
+		}
+		placeholders[variable] = placeholder
+		return placeholder
+	})
+
+	parsed, err := parser.ParseExpr(expr)
+	if err != nil {
+		return "", err
+	}
+
+	parser.Inspect(parsed, func(node parser.Node, _ []parser.Node) error {
+		if selector, ok := node.(*parser.VectorSelector); ok {
+			selector.LabelMatchers = append(selector.LabelMatchers, matchers...)
+		}
+		return nil
+	})
+
+	result := parsed.String()
+	for variable, placeholder := range placeholders {
+		result = strings.ReplaceAll(result, placeholder, variable)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,64 @@
+package queries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestInjectQueryFilter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dsType   string
+		query    map[string]interface{}
+		filter   string
+		key      string
+		expected string
+	}{
+		{
+			name:     "adds label matchers to prometheus selectors",
+			dsType:   "prometheus",
+			query:    map[string]interface{}{"expr": `sum(rate(http_requests_total{job="api"}[5m])) / sum(up)`},
+			filter:   `tenant="acme"`,
+			key:      "expr",
+			expected: `sum(rate(http_requests_total{job="api",tenant="acme"}[5m])) / sum(up{tenant="acme"})`,
+		},
+		{
+			name:     "keeps grafana variables in prometheus expressions",
+			dsType:   "prometheus",
+			query:    map[string]interface{}{"expr": `rate(http_requests_total[$__rate_interval]) * $__range_s`},
+			filter:   `tenant="acme"`,
+			key:      "expr",
+			expected: `rate(http_requests_total{tenant="acme"}[$__rate_interval]) * $__range_s`,
+		},
+		{
+			name:     "wraps sql queries",
+			dsType:   "postgres",
+			query:    map[string]interface{}{"rawSql": "SELECT time, value FROM metrics;"},
+			filter:   "tenant = 'acme'",
+			key:      "rawSql",
+			expected: "SELECT * FROM (\nSELECT time, value FROM metrics\n) AS public_dashboard_filtered WHERE tenant = 'acme'",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			query := simplejson.NewFromAny(test.query)
+			require.NoError(t, InjectQueryFilter(query, test.dsType, test.filter))
+			assert.Equal(t, test.expected, query.Get(test.key).MustString())
+		})
+	}
+
+	t.Run("returns an error for unsupported data source types", func(t *testing.T) {
+		query := simplejson.NewFromAny(map[string]interface{}{"expr": "{job=\"api\"}"})
+		require.ErrorIs(t, InjectQueryFilter(query, "loki", `tenant="acme"`), ErrQueryFilterNotSupported)
+	})
+
+	t.Run("returns an error for invalid prometheus expressions", func(t *testing.T) {
+		query := simplejson.NewFromAny(map[string]interface{}{"expr": "sum("})
+		require.Error(t, InjectQueryFilter(query, "prometheus", `tenant="acme"`))
+	})
+}
@@ -0,0 +1,81 @@
+package queries
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+type compiledMaskingRule struct {
+	pattern *regexp.Regexp
+	rule    models.MaskingRule
+}
+
+// MaskQueryData applies the masking rules to the fields of the response
+// frames. Masked fields are replaced by nullable string fields holding the
+// masked values, null values stay null.
+func MaskQueryData(res *backend.QueryDataResponse, rules models.MaskingRules) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	compiled := make([]compiledMaskingRule, 0, len(rules))
+	for _, rule := range rules {
+		pattern, err := regexp.Compile(rule.FieldPattern)
+		if err != nil {
+			return models.ErrPublicDashboardInvalidMaskingRule
+		}
+		compiled = append(compiled, compiledMaskingRule{pattern: pattern, rule: rule})
+	}
+
+	for k := range res.Responses {
+		for _, frame := range res.Responses[k].Frames {
+			for i, field := range frame.Fields {
+				for _, c := range compiled {
+					if c.pattern.MatchString(field.Name) {
+						frame.Fields[i] = maskField(field, c.rule)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func maskField(field *data.Field, rule models.MaskingRule) *data.Field {
+	values := make([]*string, field.Len())
+	for i := range values {
+		value, ok := field.ConcreteAt(i)
+		if !ok {
+			continue
+		}
+
+		var masked string
+		switch rule.Action {
+		case models.MaskActionHash:
+			sum := sha256.Sum256([]byte(fmt.Sprint(value)))
+			masked = hex.EncodeToString(sum[:])
+		case models.MaskActionTruncate:
+			runes := []rune(fmt.Sprint(value))
+			if len(runes) > rule.Length {
+				runes = runes[:rule.Length]
+			}
+			masked = string(runes)
+		default:
+			// redact
+			continue
+		}
+		values[i] = &masked
+	}
+
+	masked := data.NewField(field.Name, field.Labels, values)
+	masked.Config = field.Config
+	return masked
+}
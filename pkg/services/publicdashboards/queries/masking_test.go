@@ -0,0 +1,76 @@
+package queries
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+func TestMaskQueryData(t *testing.T) {
+	email := "user@example.com"
+	buildResponse := func() *backend.QueryDataResponse {
+		return &backend.QueryDataResponse{
+			Responses: backend.Responses{
+				"A": backend.DataResponse{
+					Frames: data.Frames{
+						data.NewFrame("users",
+							data.NewField("email", nil, []*string{&email, nil}),
+							data.NewField("userId", nil, []int64{1234, 5678}),
+							data.NewField("value", nil, []float64{1, 2}),
+						),
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("redacts values of matching fields", func(t *testing.T) {
+		res := buildResponse()
+		err := MaskQueryData(res, models.MaskingRules{{FieldPattern: "^email$", Action: models.MaskActionRedact}})
+		require.NoError(t, err)
+
+		field := res.Responses["A"].Frames[0].Fields[0]
+		assert.Equal(t, "email", field.Name)
+		assert.Nil(t, field.At(0))
+		assert.Nil(t, field.At(1))
+	})
+
+	t.Run("hashes values of matching fields", func(t *testing.T) {
+		res := buildResponse()
+		err := MaskQueryData(res, models.MaskingRules{{FieldPattern: "(?i)userid", Action: models.MaskActionHash}})
+		require.NoError(t, err)
+
+		field := res.Responses["A"].Frames[0].Fields[1]
+		hashed, ok := field.ConcreteAt(0)
+		require.True(t, ok)
+		assert.Len(t, hashed, 64)
+		assert.NotEqual(t, "1234", hashed)
+	})
+
+	t.Run("truncates values of matching fields", func(t *testing.T) {
+		res := buildResponse()
+		err := MaskQueryData(res, models.MaskingRules{{FieldPattern: "email", Action: models.MaskActionTruncate, Length: 4}})
+		require.NoError(t, err)
+
+		field := res.Responses["A"].Frames[0].Fields[0]
+		truncated, ok := field.ConcreteAt(0)
+		require.True(t, ok)
+		assert.Equal(t, "user", truncated)
+		_, ok = field.ConcreteAt(1)
+		assert.False(t, ok)
+	})
+
+	t.Run("leaves other fields untouched", func(t *testing.T) {
+		res := buildResponse()
+		err := MaskQueryData(res, models.MaskingRules{{FieldPattern: "email", Action: models.MaskActionRedact}})
+		require.NoError(t, err)
+
+		field := res.Responses["A"].Frames[0].Fields[2]
+		assert.Equal(t, float64(1), field.At(0))
+	})
+}
@@ -0,0 +1,38 @@
+package queries
+
+import (
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// FilterPanels removes the panels whose id isn't in panelIds from the
+// dashboard. Repeated panels are kept when the panel they repeat is. Rows are
+// always kept, collapsed rows only with their remaining panels.
+func FilterPanels(dashboard *simplejson.Json, panelIds []int64) {
+	allowed := make(map[int64]bool, len(panelIds))
+	for _, id := range panelIds {
+		allowed[id] = true
+	}
+
+	dashboard.Set("panels", filterPanels(dashboard.Get("panels").MustArray(), allowed))
+}
+
+func filterPanels(panels []interface{}, allowed map[int64]bool) []interface{} {
+	result := make([]interface{}, 0, len(panels))
+	for _, panelObj := range panels {
+		panel := simplejson.NewFromAny(panelObj)
+
+		if isRow(panel) {
+			if _, ok := panel.CheckGet("panels"); ok {
+				panel.Set("panels", filterPanels(panel.Get("panels").MustArray(), allowed))
+			}
+			result = append(result, panel.Interface())
+			continue
+		}
+
+		if allowed[panel.Get("id").MustInt64()] || allowed[panel.Get("repeatPanelId").MustInt64()] {
+			result = append(result, panel.Interface())
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,71 @@
+package queries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const dashboardWithCollapsedRow = `
+{
+  "panels": [
+    {"id": 1, "type": "timeseries"},
+    {"id": 2, "type": "timeseries"},
+    {"id": 3, "type": "timeseries", "repeatPanelId": 1},
+    {
+      "id": 4,
+      "collapsed": true,
+      "type": "row",
+      "panels": [
+        {"id": 5, "type": "timeseries"},
+        {"id": 6, "type": "timeseries"}
+      ]
+    }
+  ]
+}`
+
+func panelIds(panels []interface{}) []int64 {
+	ids := make([]int64, 0, len(panels))
+	for _, panel := range panels {
+		ids = append(ids, simplejson.NewFromAny(panel).Get("id").MustInt64())
+	}
+	return ids
+}
+
+func TestFilterPanels(t *testing.T) {
+	t.Run("keeps allowed panels and their repeats", func(t *testing.T) {
+		dashboard, err := simplejson.NewJson([]byte(dashboardWithCollapsedRow))
+		require.NoError(t, err)
+
+		FilterPanels(dashboard, []int64{1, 6})
+
+		panels := dashboard.Get("panels").MustArray()
+		assert.Equal(t, []int64{1, 3, 4}, panelIds(panels))
+		assert.Equal(t, []int64{6}, panelIds(simplejson.NewFromAny(panels[2]).Get("panels").MustArray()))
+	})
+
+	t.Run("keeps only rows when no panel is allowed", func(t *testing.T) {
+		dashboard, err := simplejson.NewJson([]byte(dashboardWithCollapsedRow))
+		require.NoError(t, err)
+
+		FilterPanels(dashboard, nil)
+
+		panels := dashboard.Get("panels").MustArray()
+		assert.Equal(t, []int64{4}, panelIds(panels))
+		assert.Empty(t, simplejson.NewFromAny(panels[0]).Get("panels").MustArray())
+	})
+
+	t.Run("removed panels can't be queried", func(t *testing.T) {
+		dashboard, err := simplejson.NewJson([]byte(dashboardWithCollapsedRow))
+		require.NoError(t, err)
+
+		FilterPanels(dashboard, []int64{2})
+
+		byPanel := GroupQueriesByPanelId(dashboard)
+		assert.Contains(t, byPanel, int64(2))
+		assert.NotContains(t, byPanel, int64(1))
+	})
+}
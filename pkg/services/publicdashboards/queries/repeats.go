@@ -0,0 +1,363 @@
+package queries
+
+import (
+	"regexp"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const (
+	gridColumnCount  = 24
+	allVariableValue = "$__all"
+)
+
+// matches $var, ${var} and [[var]]
+var variableRegex = regexp.MustCompile(`\$(\w+)|\$\{(\w+)\}|\[\[(\w+)\]\]`)
+
+type variableValue struct {
+	Text  string
+	Value string
+}
+
+// ExpandRepeats repeats the panels and rows of the dashboard that have a
+// repeat variable once per value saved for that variable. This is what the
+// frontend does for regular dashboards, but public dashboard queries are
+// built on the server so the repeated panels need to exist in the dashboard
+// JSON. Repeated panels get new ids, the scoped variable value and their
+// queries interpolated with it.
+func ExpandRepeats(dashboard *simplejson.Json) {
+	variables := getVariableValues(dashboard)
+	if len(variables) == 0 {
+		return
+	}
+
+	e := &repeatExpander{
+		variables: variables,
+		nextId:    getMaxPanelId(dashboard.Get("panels").MustArray()) + 1,
+	}
+
+	panels := dashboard.Get("panels").MustArray()
+	var result []interface{}
+	for i := 0; i < len(panels); i++ {
+		panel := simplejson.NewFromAny(panels[i])
+		if !isRow(panel) {
+			e.shift(panel)
+			result = append(result, e.expandPanel(panel, nil)...)
+			continue
+		}
+
+		// panels of an expanded row follow the row in the panel list
+		var rowPanels []*simplejson.Json
+		if !panel.Get("collapsed").MustBool() {
+			for i+1 < len(panels) && !isRow(simplejson.NewFromAny(panels[i+1])) {
+				i++
+				rowPanels = append(rowPanels, simplejson.NewFromAny(panels[i]))
+			}
+		}
+		result = append(result, e.expandRow(panel, rowPanels)...)
+	}
+
+	dashboard.Set("panels", result)
+}
+
+type repeatExpander struct {
+	variables map[string][]variableValue
+	nextId    int64
+	// height added by the repeats so far, used to move the following panels down
+	yOffset int
+}
+
+func (e *repeatExpander) expandRow(row *simplejson.Json, rowPanels []*simplejson.Json) []interface{} {
+	name := row.Get("repeat").MustString()
+	values := e.variables[name]
+	if len(values) == 0 {
+		e.shift(row)
+		result := []interface{}{row.Interface()}
+		for _, panel := range rowPanels {
+			e.shift(panel)
+			result = append(result, e.expandPanel(panel, nil)...)
+		}
+		if collapsed := row.Get("panels").MustArray(); len(collapsed) > 0 {
+			row.Set("panels", e.expandCollapsedPanels(collapsed, nil))
+		}
+		return result
+	}
+
+	// rows are one unit high, followed by their panels
+	rowY := row.GetPath("gridPos", "y").MustInt()
+	rowHeight := 1
+	for _, panel := range rowPanels {
+		bottom := panel.GetPath("gridPos", "y").MustInt() + panel.GetPath("gridPos", "h").MustInt() - rowY
+		if bottom > rowHeight {
+			rowHeight = bottom
+		}
+	}
+
+	// the first repetition reuses the row and its panels, keep the originals
+	// around for the other ones
+	original := copyJson(row)
+	originalPanels := make([]*simplejson.Json, 0, len(rowPanels))
+	for _, panel := range rowPanels {
+		originalPanels = append(originalPanels, copyJson(panel))
+	}
+
+	var result []interface{}
+	for k, value := range values {
+		scopedVars := map[string]interface{}{name: map[string]interface{}{"text": value.Text, "value": value.Value}}
+
+		rowCopy := row
+		if k > 0 {
+			rowCopy = e.copyPanel(original)
+			rowCopy.Set("repeatIteration", k)
+			e.yOffset += rowHeight
+		}
+		e.shift(rowCopy)
+		rowCopy.Set("scopedVars", scopedVars)
+		interpolatePanel(rowCopy, scopedVars)
+		result = append(result, rowCopy.Interface())
+
+		for i, panel := range rowPanels {
+			panelCopy := panel
+			if k > 0 {
+				panelCopy = e.copyPanel(originalPanels[i])
+			}
+			e.shift(panelCopy)
+			result = append(result, e.expandPanel(panelCopy, scopedVars)...)
+		}
+
+		if collapsed := rowCopy.Get("panels").MustArray(); len(collapsed) > 0 {
+			if k > 0 {
+				collapsed = e.copyPanels(collapsed)
+			}
+			rowCopy.Set("panels", e.expandCollapsedPanels(collapsed, scopedVars))
+		}
+	}
+
+	return result
+}
+
+// expandCollapsedPanels expands the panels of a collapsed row, they are not
+// part of the dashboard layout so they don't move the following panels
+func (e *repeatExpander) expandCollapsedPanels(panels []interface{}, scopedVars map[string]interface{}) []interface{} {
+	yOffset := e.yOffset
+	var result []interface{}
+	for _, panel := range panels {
+		result = append(result, e.expandPanel(simplejson.NewFromAny(panel), scopedVars)...)
+	}
+	e.yOffset = yOffset
+
+	return result
+}
+
+func (e *repeatExpander) expandPanel(panel *simplejson.Json, scopedVars map[string]interface{}) []interface{} {
+	name := panel.Get("repeat").MustString()
+	values := e.variables[name]
+	if len(values) == 0 {
+		if scopedVars != nil {
+			panel.Set("scopedVars", scopedVars)
+			interpolatePanel(panel, scopedVars)
+		}
+		return []interface{}{panel.Interface()}
+	}
+
+	x := panel.GetPath("gridPos", "x").MustInt()
+	y := panel.GetPath("gridPos", "y").MustInt()
+	w := panel.GetPath("gridPos", "w").MustInt()
+	h := panel.GetPath("gridPos", "h").MustInt()
+
+	horizontal := panel.Get("repeatDirection").MustString("h") == "h"
+	perRow := len(values)
+	maxPerRow := panel.Get("maxPerRow").MustInt(4)
+	if maxPerRow <= 0 {
+		// like the frontend, unset or invalid maximums use the default
+		maxPerRow = 4
+	}
+	if horizontal && perRow > maxPerRow {
+		perRow = maxPerRow
+	}
+
+	// the first repetition reuses the panel, keep the original around for the
+	// other ones
+	original := copyJson(panel)
+
+	var result []interface{}
+	for k, value := range values {
+		panelScopedVars := map[string]interface{}{}
+		for key, val := range scopedVars {
+			panelScopedVars[key] = val
+		}
+		panelScopedVars[name] = map[string]interface{}{"text": value.Text, "value": value.Value}
+
+		panelCopy := panel
+		if k > 0 {
+			panelCopy = e.copyPanel(original)
+			panelCopy.Set("repeatPanelId", panel.Get("id").MustInt64())
+		}
+
+		if horizontal {
+			w = gridColumnCount / perRow
+			panelCopy.SetPath([]string{"gridPos", "x"}, (k%perRow)*w)
+			panelCopy.SetPath([]string{"gridPos", "y"}, y+(k/perRow)*h)
+		} else {
+			panelCopy.SetPath([]string{"gridPos", "x"}, x)
+			panelCopy.SetPath([]string{"gridPos", "y"}, y+k*h)
+		}
+		panelCopy.SetPath([]string{"gridPos", "w"}, w)
+
+		panelCopy.Set("scopedVars", panelScopedVars)
+		interpolatePanel(panelCopy, panelScopedVars)
+		result = append(result, panelCopy.Interface())
+	}
+
+	if horizontal {
+		e.yOffset += ((len(values) + perRow - 1) / perRow * h) - h
+	} else {
+		e.yOffset += (len(values) - 1) * h
+	}
+
+	return result
+}
+
+// shift moves the panel down by the height added by the repeats above it
+func (e *repeatExpander) shift(panel *simplejson.Json) {
+	if e.yOffset == 0 {
+		return
+	}
+	panel.SetPath([]string{"gridPos", "y"}, panel.GetPath("gridPos", "y").MustInt()+e.yOffset)
+}
+
+// copyPanel returns a deep copy of the panel with a new id
+func (e *repeatExpander) copyPanel(panel *simplejson.Json) *simplejson.Json {
+	panelCopy := copyJson(panel)
+	panelCopy.Set("id", e.nextId)
+	e.nextId++
+	return panelCopy
+}
+
+func (e *repeatExpander) copyPanels(panels []interface{}) []interface{} {
+	result := make([]interface{}, 0, len(panels))
+	for _, panel := range panels {
+		result = append(result, e.copyPanel(simplejson.NewFromAny(panel)).Interface())
+	}
+	return result
+}
+
+func getVariableValues(dashboard *simplejson.Json) map[string][]variableValue {
+	result := make(map[string][]variableValue)
+
+	for _, variableObj := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		name := variable.Get("name").MustString()
+		current := variable.Get("current")
+
+		values := jsonStrings(current.Get("value"))
+		texts := jsonStrings(current.Get("text"))
+
+		// "All" repeats the panel for each option of the variable
+		if len(values) == 1 && values[0] == allVariableValue {
+			values, texts = nil, nil
+			for _, optionObj := range variable.Get("options").MustArray() {
+				option := simplejson.NewFromAny(optionObj)
+				value := option.Get("value").MustString()
+				if value == allVariableValue {
+					continue
+				}
+				values = append(values, value)
+				texts = append(texts, option.Get("text").MustString(value))
+			}
+		}
+
+		for i, value := range values {
+			text := value
+			if i < len(texts) {
+				text = texts[i]
+			}
+			result[name] = append(result[name], variableValue{Text: text, Value: value})
+		}
+	}
+
+	return result
+}
+
+// jsonStrings returns the value as a list of strings, variables that allow
+// multiple values have their current value saved as an array
+func jsonStrings(value *simplejson.Json) []string {
+	if arr, err := value.StringArray(); err == nil {
+		return arr
+	}
+	if str, err := value.String(); err == nil {
+		return []string{str}
+	}
+	return nil
+}
+
+func getMaxPanelId(panels []interface{}) int64 {
+	var maxId int64
+	for _, panelObj := range panels {
+		panel := simplejson.NewFromAny(panelObj)
+		if id := panel.Get("id").MustInt64(); id > maxId {
+			maxId = id
+		}
+		if id := getMaxPanelId(panel.Get("panels").MustArray()); id > maxId {
+			maxId = id
+		}
+	}
+	return maxId
+}
+
+// interpolatePanel replaces the scoped variables in the panel title and queries
+func interpolatePanel(panel *simplejson.Json, scopedVars map[string]interface{}) {
+	if title, err := panel.Get("title").String(); err == nil {
+		panel.Set("title", interpolate(title, scopedVars))
+	}
+
+	targets := panel.Get("targets").MustArray()
+	for i := range targets {
+		targets[i] = interpolateValue(targets[i], scopedVars)
+	}
+}
+
+func interpolateValue(value interface{}, scopedVars map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return interpolate(v, scopedVars)
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = interpolateValue(v[key], scopedVars)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = interpolateValue(v[i], scopedVars)
+		}
+	}
+	return value
+}
+
+func interpolate(str string, scopedVars map[string]interface{}) string {
+	return variableRegex.ReplaceAllStringFunc(str, func(match string) string {
+		groups := variableRegex.FindStringSubmatch(match)
+		name := groups[1] + groups[2] + groups[3]
+
+		scopedVar, ok := scopedVars[name].(map[string]interface{})
+		if !ok {
+			return match
+		}
+		return scopedVar["value"].(string)
+	})
+}
+
+func isRow(panel *simplejson.Json) bool {
+	return panel.Get("type").MustString() == "row"
+}
+
+func copyJson(value *simplejson.Json) *simplejson.Json {
+	data, err := value.Encode()
+	if err != nil {
+		return simplejson.New()
+	}
+	copied, err := simplejson.NewJson(data)
+	if err != nil {
+		return simplejson.New()
+	}
+	return copied
+}
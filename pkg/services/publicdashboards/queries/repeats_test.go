@@ -0,0 +1,172 @@
+package queries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const (
+	dashboardWithRepeatedPanel = `
+{
+  "panels": [
+    {
+      "id": 1,
+      "gridPos": {"h": 8, "w": 12, "x": 0, "y": 0},
+      "repeat": "server",
+      "repeatDirection": "h",
+      "title": "Server $server",
+      "targets": [{"refId": "A", "expr": "up{instance=\"$server\"}"}],
+      "type": "timeseries"
+    },
+    {
+      "id": 2,
+      "gridPos": {"h": 8, "w": 24, "x": 0, "y": 8},
+      "targets": [{"refId": "A", "expr": "up"}],
+      "type": "timeseries"
+    }
+  ],
+  "templating": {
+    "list": [
+      {
+        "name": "server",
+        "current": {"text": ["a", "b", "c"], "value": ["a", "b", "c"]}
+      }
+    ]
+  }
+}`
+
+	dashboardWithRepeatedRow = `
+{
+  "panels": [
+    {
+      "id": 1,
+      "gridPos": {"h": 1, "w": 24, "x": 0, "y": 0},
+      "repeat": "server",
+      "title": "Row $server",
+      "collapsed": false,
+      "type": "row"
+    },
+    {
+      "id": 2,
+      "gridPos": {"h": 8, "w": 24, "x": 0, "y": 1},
+      "targets": [{"refId": "A", "expr": "up{instance=\"[[server]]\"}"}],
+      "type": "timeseries"
+    },
+    {
+      "id": 3,
+      "gridPos": {"h": 1, "w": 24, "x": 0, "y": 9},
+      "collapsed": false,
+      "type": "row"
+    },
+    {
+      "id": 4,
+      "gridPos": {"h": 8, "w": 24, "x": 0, "y": 10},
+      "targets": [{"refId": "A", "expr": "up"}],
+      "type": "timeseries"
+    }
+  ],
+  "templating": {
+    "list": [
+      {
+        "name": "server",
+        "current": {"text": "All", "value": "$__all"},
+        "options": [
+          {"text": "All", "value": "$__all"},
+          {"text": "a", "value": "a"},
+          {"text": "b", "value": "b"}
+        ]
+      }
+    ]
+  }
+}`
+)
+
+func TestExpandRepeats(t *testing.T) {
+	t.Run("repeats panels horizontally for each variable value", func(t *testing.T) {
+		json, err := simplejson.NewJson([]byte(dashboardWithRepeatedPanel))
+		require.NoError(t, err)
+
+		ExpandRepeats(json)
+
+		panels := json.Get("panels")
+		require.Len(t, panels.MustArray(), 4)
+
+		for i, server := range []string{"a", "b", "c"} {
+			panel := panels.GetIndex(i)
+			assert.Equal(t, "Server "+server, panel.Get("title").MustString())
+			assert.Equal(t, `up{instance="`+server+`"}`, panel.Get("targets").GetIndex(0).Get("expr").MustString())
+			assert.Equal(t, server, panel.GetPath("scopedVars", "server", "value").MustString())
+			assert.Equal(t, 8, panel.GetPath("gridPos", "w").MustInt())
+			assert.Equal(t, i*8, panel.GetPath("gridPos", "x").MustInt())
+		}
+
+		assert.Equal(t, int64(1), panels.GetIndex(0).Get("id").MustInt64())
+		assert.Equal(t, int64(3), panels.GetIndex(1).Get("id").MustInt64())
+		assert.Equal(t, int64(1), panels.GetIndex(1).Get("repeatPanelId").MustInt64())
+		assert.Equal(t, int64(4), panels.GetIndex(2).Get("id").MustInt64())
+
+		// the other panel is left untouched
+		assert.Equal(t, int64(2), panels.GetIndex(3).Get("id").MustInt64())
+		assert.Equal(t, 8, panels.GetIndex(3).GetPath("gridPos", "y").MustInt())
+	})
+
+	t.Run("uses the default maximum per row when the one of the panel is invalid", func(t *testing.T) {
+		json, err := simplejson.NewJson([]byte(dashboardWithRepeatedPanel))
+		require.NoError(t, err)
+		json.Get("panels").GetIndex(0).Set("maxPerRow", 0)
+		json.Get("templating").Get("list").GetIndex(0).Set("current", map[string]interface{}{"text": []interface{}{"a", "b"}, "value": []interface{}{"a", "b"}})
+
+		ExpandRepeats(json)
+
+		panels := json.Get("panels")
+		require.Len(t, panels.MustArray(), 3)
+		for i := 0; i < 2; i++ {
+			assert.Equal(t, 12, panels.GetIndex(i).GetPath("gridPos", "w").MustInt())
+			assert.Equal(t, i*12, panels.GetIndex(i).GetPath("gridPos", "x").MustInt())
+			assert.Equal(t, 0, panels.GetIndex(i).GetPath("gridPos", "y").MustInt())
+		}
+	})
+
+	t.Run("repeats rows and their panels for all variable options", func(t *testing.T) {
+		json, err := simplejson.NewJson([]byte(dashboardWithRepeatedRow))
+		require.NoError(t, err)
+
+		ExpandRepeats(json)
+
+		panels := json.Get("panels")
+		require.Len(t, panels.MustArray(), 6)
+
+		assert.Equal(t, "Row a", panels.GetIndex(0).Get("title").MustString())
+		assert.Equal(t, `up{instance="a"}`, panels.GetIndex(1).Get("targets").GetIndex(0).Get("expr").MustString())
+		assert.Equal(t, "Row b", panels.GetIndex(2).Get("title").MustString())
+		assert.Equal(t, 9, panels.GetIndex(2).GetPath("gridPos", "y").MustInt())
+		assert.Equal(t, `up{instance="b"}`, panels.GetIndex(3).Get("targets").GetIndex(0).Get("expr").MustString())
+		assert.Equal(t, 10, panels.GetIndex(3).GetPath("gridPos", "y").MustInt())
+
+		// panels repeated by the row get new ids
+		assert.Equal(t, int64(5), panels.GetIndex(2).Get("id").MustInt64())
+		assert.Equal(t, int64(6), panels.GetIndex(3).Get("id").MustInt64())
+
+		// the following row moves down
+		assert.Equal(t, int64(3), panels.GetIndex(4).Get("id").MustInt64())
+		assert.Equal(t, 18, panels.GetIndex(4).GetPath("gridPos", "y").MustInt())
+		assert.Equal(t, 19, panels.GetIndex(5).GetPath("gridPos", "y").MustInt())
+	})
+
+	t.Run("leaves dashboards without variables untouched", func(t *testing.T) {
+		json, err := simplejson.NewJson([]byte(dashboardWithMixedDatasource))
+		require.NoError(t, err)
+		expected, err := json.Encode()
+		require.NoError(t, err)
+
+		ExpandRepeats(json)
+
+		actual, err := json.Encode()
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(actual))
+	})
+}
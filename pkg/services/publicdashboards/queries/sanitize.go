@@ -0,0 +1,156 @@
+package queries
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+)
+
+// SanitizePanels removes what public viewers must not see from the panels of
+// the dashboard: legacy alert rule definitions, server-side expressions that
+// reference queries the panel doesn't expose, and links to internal routes of
+// the instance, whose public URL is appURL.
+func SanitizePanels(dashboard *simplejson.Json, appURL string) {
+	sanitizePanels(dashboard.Get("panels").MustArray(), appURL)
+}
+
+func sanitizePanels(panels []interface{}, appURL string) {
+	for _, panelObj := range panels {
+		panel := simplejson.NewFromAny(panelObj)
+
+		if isRow(panel) {
+			sanitizePanels(panel.Get("panels").MustArray(), appURL)
+			continue
+		}
+
+		panel.Del("alert")
+
+		if targets, ok := panel.CheckGet("targets"); ok {
+			panel.Set("targets", removeUnexposedExpressions(targets.MustArray(), GetDataSourceUidFromJson(panel)))
+		}
+
+		if links, ok := panel.CheckGet("links"); ok {
+			panel.Set("links", removeInternalLinks(links.MustArray(), appURL))
+		}
+
+		if links, ok := panel.Get("fieldConfig").Get("defaults").CheckGet("links"); ok {
+			panel.Get("fieldConfig").Get("defaults").Set("links", removeInternalLinks(links.MustArray(), appURL))
+		}
+	}
+}
+
+// removeUnexposedExpressions removes the expressions referencing queries that
+// aren't part of the panel, directly or through other expressions. Data
+// source queries without a data source are only exposed when the panel isn't
+// mixed, the query of a public dashboard panel drops them otherwise.
+func removeUnexposedExpressions(targets []interface{}, panelUid string) []interface{} {
+	exposed := map[string]bool{}
+	var expressions []*simplejson.Json
+
+	for _, targetObj := range targets {
+		target := simplejson.NewFromAny(targetObj)
+		uid := GetDataSourceUidFromJson(target)
+
+		if expr.IsDataSource(uid) {
+			expressions = append(expressions, target)
+			continue
+		}
+
+		if _, ok := target.CheckGet("datasource"); ok || panelUid != MixedDatasourceUid {
+			exposed[target.Get("refId").MustString()] = true
+		}
+	}
+
+	// expressions can reference each other, expose them until none is left
+	// whose references are all exposed
+	for changed := true; changed; {
+		changed = false
+		for _, expression := range expressions {
+			refId := expression.Get("refId").MustString()
+			if exposed[refId] {
+				continue
+			}
+
+			if refs, ok := expressionReferences(expression); ok && allExposed(refs, exposed) {
+				exposed[refId] = true
+				changed = true
+			}
+		}
+	}
+
+	result := make([]interface{}, 0, len(targets))
+	for _, targetObj := range targets {
+		target := simplejson.NewFromAny(targetObj)
+		if expr.IsDataSource(GetDataSourceUidFromJson(target)) && !exposed[target.Get("refId").MustString()] {
+			continue
+		}
+		result = append(result, targetObj)
+	}
+
+	return result
+}
+
+// expressionReferences returns the refIds of the queries referenced by the
+// expression, it returns false for unknown or invalid expressions
+func expressionReferences(expression *simplejson.Json) ([]string, bool) {
+	switch expression.Get("type").MustString() {
+	case "math":
+		cmd, err := expr.NewMathCommand(expression.Get("refId").MustString(), expression.Get("expression").MustString())
+		if err != nil {
+			return nil, false
+		}
+		return cmd.NeedsVars(), true
+	case "reduce", "resample", "threshold":
+		ref := strings.TrimPrefix(expression.Get("expression").MustString(), "$")
+		return []string{ref}, ref != ""
+	case "classic_conditions":
+		var refs []string
+		for _, condition := range expression.Get("conditions").MustArray() {
+			ref := simplejson.NewFromAny(condition).Get("query").Get("params").GetIndex(0).MustString()
+			if ref == "" {
+				return nil, false
+			}
+			refs = append(refs, ref)
+		}
+		return refs, true
+	default:
+		return nil, false
+	}
+}
+
+func allExposed(refs []string, exposed map[string]bool) bool {
+	for _, ref := range refs {
+		if !exposed[ref] {
+			return false
+		}
+	}
+	return true
+}
+
+// removeInternalLinks removes the links to relative URLs or to the instance
+// itself, they point at routes public viewers can't access and leak their
+// names, uids and parameters
+func removeInternalLinks(links []interface{}, appURL string) []interface{} {
+	result := make([]interface{}, 0, len(links))
+	for _, linkObj := range links {
+		link := simplejson.NewFromAny(linkObj)
+		if isInternalURL(link.Get("url").MustString(), appURL) {
+			continue
+		}
+		result = append(result, linkObj)
+	}
+
+	return result
+}
+
+func isInternalURL(rawURL string, appURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return true
+	}
+
+	app, err := url.Parse(appURL)
+	return err == nil && app.Host != "" && strings.EqualFold(u.Host, app.Host)
+}
@@ -0,0 +1,97 @@
+package queries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const dashboardWithSensitivePanels = `
+{
+  "panels": [
+    {
+      "id": 1,
+      "type": "graph",
+      "datasource": {"type": "prometheus", "uid": "prom"},
+      "alert": {"name": "High CPU", "conditions": []},
+      "targets": [
+        {"refId": "A", "expr": "cpu"},
+        {"refId": "B", "datasource": {"type": "__expr__", "uid": "__expr__"}, "type": "math", "expression": "$A * 2"},
+        {"refId": "C", "datasource": {"type": "__expr__", "uid": "__expr__"}, "type": "reduce", "expression": "$B", "reducer": "mean"},
+        {"refId": "D", "datasource": {"type": "__expr__", "uid": "__expr__"}, "type": "math", "expression": "$A + $secret"},
+        {"refId": "E", "datasource": {"type": "__expr__", "uid": "__expr__"}, "type": "classic_conditions", "conditions": [{"query": {"params": ["D"]}}]}
+      ],
+      "links": [
+        {"title": "Runbook", "url": "https://wiki.example.com/runbook"},
+        {"title": "Details", "url": "/d/internal/details?var-host=db1"},
+        {"title": "Admin", "url": "https://grafana.example.com/admin/users"}
+      ],
+      "fieldConfig": {"defaults": {"links": [{"title": "Explore", "url": "/explore?left=..."}]}}
+    },
+    {
+      "id": 2,
+      "collapsed": true,
+      "type": "row",
+      "panels": [
+        {
+          "id": 3,
+          "type": "timeseries",
+          "datasource": {"uid": "-- Mixed --"},
+          "alert": {"name": "Disk full"},
+          "targets": [
+            {"refId": "A", "datasource": {"type": "mysql", "uid": "mysql"}},
+            {"refId": "B"},
+            {"refId": "C", "datasource": {"type": "__expr__", "uid": "__expr__"}, "type": "reduce", "expression": "B", "reducer": "max"},
+            {"refId": "D", "datasource": {"type": "__expr__", "uid": "__expr__"}, "type": "unknown", "expression": "A"}
+          ]
+        }
+      ]
+    }
+  ]
+}`
+
+func refIds(panel *simplejson.Json) []string {
+	var ids []string
+	for _, target := range panel.Get("targets").MustArray() {
+		ids = append(ids, simplejson.NewFromAny(target).Get("refId").MustString())
+	}
+	return ids
+}
+
+func linkTitles(links *simplejson.Json) []string {
+	titles := []string{}
+	for _, link := range links.MustArray() {
+		titles = append(titles, simplejson.NewFromAny(link).Get("title").MustString())
+	}
+	return titles
+}
+
+func TestSanitizePanels(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardWithSensitivePanels))
+	require.NoError(t, err)
+
+	SanitizePanels(dashboard, "https://grafana.example.com/")
+
+	panel := dashboard.Get("panels").GetIndex(0)
+	nested := dashboard.Get("panels").GetIndex(1).Get("panels").GetIndex(0)
+
+	t.Run("removes alert rule definitions", func(t *testing.T) {
+		_, ok := panel.CheckGet("alert")
+		assert.False(t, ok)
+		_, ok = nested.CheckGet("alert")
+		assert.False(t, ok)
+	})
+
+	t.Run("removes expressions referencing unexposed queries", func(t *testing.T) {
+		assert.Equal(t, []string{"A", "B", "C"}, refIds(panel))
+		assert.Equal(t, []string{"A", "B"}, refIds(nested))
+	})
+
+	t.Run("removes links to internal routes", func(t *testing.T) {
+		assert.Equal(t, []string{"Runbook"}, linkTitles(panel.Get("links")))
+		assert.Empty(t, linkTitles(panel.Get("fieldConfig").Get("defaults").Get("links")))
+	})
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// BulkDisablePublicDashboards disables, or deletes, the public dashboards of
+// every dashboard in a folder or with a tag at once, to pull them all from
+// public view during an incident. Every change is audited and notified like
+// when saved one by one.
+func (pd *PublicDashboardServiceImpl) BulkDisablePublicDashboards(ctx context.Context, u *user.SignedInUser, dto *BulkDisablePublicDashboardsDTO) (*BulkDisablePublicDashboardsResult, error) {
+	pubdashes, err := pd.store.GetPublicDashboardsByFolderOrTag(ctx, dto.OrgId, dto.FolderUid, dto.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkDisablePublicDashboardsResult{DashboardUids: make([]string, 0, len(pubdashes))}
+	for _, pubdash := range pubdashes {
+		if dto.Delete {
+			err = pd.deletePublicDashboard(ctx, u, pubdash, dto.UserId)
+		} else if pubdash.IsEnabled {
+			err = pd.disablePublicDashboard(ctx, u, pubdash, dto.UserId)
+		} else {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.DashboardUids = append(result.DashboardUids, pubdash.DashboardUid)
+	}
+
+	return result, nil
+}
+
+func (pd *PublicDashboardServiceImpl) disablePublicDashboard(ctx context.Context, u *user.SignedInUser, existingPubdash *PublicDashboard, userId int64) error {
+	disabled := *existingPubdash
+	disabled.IsEnabled = false
+
+	if _, err := pd.updatePublicDashboardConfig(ctx, &SavePublicDashboardConfigDTO{
+		DashboardUid:    existingPubdash.DashboardUid,
+		OrgId:           existingPubdash.OrgId,
+		UserId:          userId,
+		PublicDashboard: &disabled,
+	}); err != nil {
+		return err
+	}
+
+	newPubdash, err := pd.store.GetPublicDashboardByUid(ctx, existingPubdash.Uid)
+	if err != nil {
+		return err
+	}
+
+	pd.logIsEnabledChanged(existingPubdash, newPubdash, u)
+
+	if err := pd.publishIsEnabledChanged(ctx, existingPubdash, newPubdash, userId); err != nil {
+		return err
+	}
+
+	return pd.savePublicDashboardHistory(ctx, existingPubdash, newPubdash, userId)
+}
+
+func (pd *PublicDashboardServiceImpl) deletePublicDashboard(ctx context.Context, u *user.SignedInUser, existingPubdash *PublicDashboard, userId int64) error {
+	if err := pd.store.DeletePublicDashboard(ctx, existingPubdash.Uid); err != nil {
+		return err
+	}
+
+	// a deleted public dashboard is no longer exposed, which is notified
+	// like disabling it
+	deleted := *existingPubdash
+	deleted.IsEnabled = false
+
+	pd.log.Info("Public dashboard deleted", "uid", existingPubdash.Uid, "dashboardUid", existingPubdash.DashboardUid, "user", u.Login)
+
+	if err := pd.publishIsEnabledChanged(ctx, existingPubdash, &deleted, userId); err != nil {
+		return err
+	}
+
+	return pd.recordPublicDashboardHistory(ctx, &deleted, HistoryActionDeleted, userId)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const (
+	// library panels resolved into a cached dashboard are refreshed after
+	// this long at the latest
+	dashboardCacheTTL             = time.Minute
+	dashboardCacheCleanupInterval = 5 * time.Minute
+)
+
+// cachedDashboard is a dashboard processed for public viewers. The JSON is
+// kept encoded so that every request gets its own copy to modify.
+type cachedDashboard struct {
+	dashboard models.Dashboard
+	data      []byte
+}
+
+// dashboardCacheKey identifies the processed dashboard of a public dashboard.
+// Saving the dashboard bumps its version, and saving the public dashboard its
+// update time, so the processed dashboard of previous versions is never served
+// again.
+func dashboardCacheKey(pubdash *PublicDashboard, dash *models.Dashboard) string {
+	scopeUid := ""
+	if pubdash.Scope != nil {
+		scopeUid = pubdash.Scope.Uid
+	}
+	return fmt.Sprintf("%s/%s/%d/%d/%d/%d", pubdash.Uid, scopeUid, pubdash.UpdatedAt.UnixNano(), pubdash.DashboardVersion, dash.Id, dash.Version)
+}
+
+// getCachedDashboard returns the processed dashboard cached under key
+func (pd *PublicDashboardServiceImpl) getCachedDashboard(key string) (*models.Dashboard, bool) {
+	if pd.dashboardCache == nil {
+		return nil, false
+	}
+
+	value, ok := pd.dashboardCache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	cached := value.(*cachedDashboard)
+	data, err := simplejson.NewJson(cached.data)
+	if err != nil {
+		return nil, false
+	}
+
+	dash := cached.dashboard
+	dash.Data = data
+	return &dash, true
+}
+
+// cacheDashboard caches the processed dashboard under key
+func (pd *PublicDashboardServiceImpl) cacheDashboard(key string, dash *models.Dashboard) {
+	if pd.dashboardCache == nil {
+		return
+	}
+
+	data, err := dash.Data.Encode()
+	if err != nil {
+		pd.log.Warn("Failed to cache public dashboard", "dashboardUid", dash.Uid, "error", err)
+		return
+	}
+
+	cached := &cachedDashboard{dashboard: *dash, data: data}
+	cached.dashboard.Data = nil
+	pd.dashboardCache.SetDefault(key, cached)
+}
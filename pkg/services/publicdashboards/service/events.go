@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/models"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const (
+	EventPublicDashboardEnabled  = "public_dashboard_enabled"
+	EventPublicDashboardDisabled = "public_dashboard_disabled"
+)
+
+// publicDashboardWebhookPayload is the body of the request sent to the sharing
+// webhook. The access token is never sent.
+type publicDashboardWebhookPayload struct {
+	Event        string    `json:"event"`
+	Timestamp    time.Time `json:"timestamp"`
+	UID          string    `json:"uid"`
+	DashboardUID string    `json:"dashboard_uid"`
+	OrgID        int64     `json:"org_id"`
+	UserID       int64     `json:"user_id"`
+}
+
+// publishIsEnabledChanged publishes an event on the bus when the public
+// dashboard got enabled or disabled, so that every new external exposure can
+// be reviewed
+func (pd *PublicDashboardServiceImpl) publishIsEnabledChanged(ctx context.Context, existingPubdash *PublicDashboard, newPubdash *PublicDashboard, userId int64) error {
+	if !publicDashboardIsEnabledChanged(existingPubdash, newPubdash) {
+		return nil
+	}
+
+	if newPubdash.IsEnabled {
+		return pd.bus.Publish(ctx, &events.PublicDashboardEnabled{
+			Timestamp:    time.Now(),
+			UID:          newPubdash.Uid,
+			DashboardUID: newPubdash.DashboardUid,
+			OrgID:        newPubdash.OrgId,
+			UserID:       userId,
+		})
+	}
+
+	return pd.bus.Publish(ctx, &events.PublicDashboardDisabled{
+		Timestamp:    time.Now(),
+		UID:          newPubdash.Uid,
+		DashboardUID: newPubdash.DashboardUid,
+		OrgID:        newPubdash.OrgId,
+		UserID:       userId,
+	})
+}
+
+func (pd *PublicDashboardServiceImpl) sendPublicDashboardEnabledWebhook(ctx context.Context, e *events.PublicDashboardEnabled) error {
+	pd.sendSharingWebhook(ctx, publicDashboardWebhookPayload{
+		Event:        EventPublicDashboardEnabled,
+		Timestamp:    e.Timestamp,
+		UID:          e.UID,
+		DashboardUID: e.DashboardUID,
+		OrgID:        e.OrgID,
+		UserID:       e.UserID,
+	})
+	return nil
+}
+
+func (pd *PublicDashboardServiceImpl) sendPublicDashboardDisabledWebhook(ctx context.Context, e *events.PublicDashboardDisabled) error {
+	pd.sendSharingWebhook(ctx, publicDashboardWebhookPayload{
+		Event:        EventPublicDashboardDisabled,
+		Timestamp:    e.Timestamp,
+		UID:          e.UID,
+		DashboardUID: e.DashboardUID,
+		OrgID:        e.OrgID,
+		UserID:       e.UserID,
+	})
+	return nil
+}
+
+// sendSharingWebhook posts the payload to the sharing webhook. Failures are
+// only logged, they must not fail saving the public dashboard.
+func (pd *PublicDashboardServiceImpl) sendSharingWebhook(ctx context.Context, payload publicDashboardWebhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		pd.log.Error("Failed to marshal public dashboard sharing webhook payload", "error", err)
+		return
+	}
+
+	err = pd.webhooks.SendWebhookSync(ctx, &models.SendWebhookSync{
+		Url:         pd.cfg.PublicDashboards.SharingWebhookUrl,
+		Body:        string(body),
+		HttpMethod:  "POST",
+		ContentType: "application/json",
+	})
+	if err != nil {
+		pd.log.Error("Failed to send public dashboard sharing webhook", "event", payload.Event, "uid", payload.UID, "error", err)
+	}
+}
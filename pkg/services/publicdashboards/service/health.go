@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/plugins/adapters"
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/queries"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+)
+
+// the health endpoint is anonymous, cache its result so that it can't be used
+// to flood data sources with health checks
+const healthCacheTTL = 30 * time.Second
+
+// GetPublicDashboardHealth returns whether the public dashboard of the access
+// token is enabled, not expired and all its data sources are reachable
+func (pd *PublicDashboardServiceImpl) GetPublicDashboardHealth(ctx context.Context, accessToken string) (*PublicDashboardHealth, error) {
+	cacheKey := "health/" + accessToken
+	if pd.dashboardCache != nil {
+		if cached, ok := pd.dashboardCache.Get(cacheKey); ok {
+			health := cached.(PublicDashboardHealth)
+			return &health, nil
+		}
+	}
+
+	health, err := pd.getPublicDashboardHealth(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if pd.dashboardCache != nil {
+		pd.dashboardCache.Set(cacheKey, *health, healthCacheTTL)
+	}
+
+	return health, nil
+}
+
+func (pd *PublicDashboardServiceImpl) getPublicDashboardHealth(ctx context.Context, accessToken string) (*PublicDashboardHealth, error) {
+	pubdash, _, err := pd.store.GetPublicDashboard(ctx, accessToken)
+	if errors.Is(err, ErrPublicDashboardNotFound) {
+		// expired or exhausted scoped access tokens aren't found anymore
+		scope, scopeErr := pd.store.GetScopedAccessToken(ctx, accessToken)
+		if scopeErr != nil || scope == nil {
+			return nil, err
+		}
+		return &PublicDashboardHealth{Enabled: true, Expired: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if pubdash == nil {
+		return nil, ErrPublicDashboardNotFound
+	}
+
+	if !pubdash.IsEnabled {
+		return &PublicDashboardHealth{}, nil
+	}
+
+	_, dash, err := pd.GetPublicDashboard(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	anonymousUser, err := pd.BuildAnonymousUser(ctx, dash)
+	if err != nil {
+		return nil, err
+	}
+
+	health := &PublicDashboardHealth{Enabled: true}
+	for _, uid := range queries.GetUniqueDashboardDatasourceUids(dash.Data) {
+		if uid == "" || uid == grafanads.DatasourceUID || uid == dashboardDatasourceUid || uid == queries.MixedDatasourceUid || expr.IsDataSource(uid) {
+			continue
+		}
+
+		if err := pd.checkDatasourceHealth(ctx, dash.OrgId, uid, anonymousUser); err != nil {
+			pd.log.Warn("Public dashboard data source is unreachable", "datasource", uid, "error", err)
+			health.UnreachableDatasources++
+		}
+	}
+
+	health.DatasourcesReachable = health.UnreachableDatasources == 0
+	health.Healthy = health.DatasourcesReachable
+
+	return health, nil
+}
+
+// checkDatasourceHealth runs the health check of the data source plugin,
+// data sources whose plugin doesn't implement health checks are deemed
+// reachable
+func (pd *PublicDashboardServiceImpl) checkDatasourceHealth(ctx context.Context, orgId int64, uid string, u *user.SignedInUser) error {
+	ds, err := pd.getDataSource(ctx, orgId, uid)
+	if err != nil {
+		return err
+	}
+
+	settings, err := adapters.ModelToInstanceSettings(ds, func(ds *datasources.DataSource) (map[string]string, error) {
+		return pd.datasourceService.DecryptedValues(ctx, ds)
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := pd.pluginClient.CheckHealth(ctx, &backend.CheckHealthRequest{
+		PluginContext: backend.PluginContext{
+			OrgID:                      orgId,
+			PluginID:                   ds.Type,
+			User:                       adapters.BackendUserFromSignedInUser(u),
+			DataSourceInstanceSettings: settings,
+		},
+	})
+	if errors.Is(err, backendplugin.ErrMethodNotImplemented) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if res.Status != backend.HealthStatusOk {
+		return fmt.Errorf("health check status %s: %s", res.Status, res.Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/jobs"
+)
+
+const (
+	expireScopedAccessTokensInterval = 10 * time.Minute
+	pruneHistoryInterval             = time.Hour
+)
+
+// registerJobs schedules the background maintenance of public dashboards:
+//   - pre-rendering, which warms the dashboard cache of every instance
+//   - disabling the scoped access tokens past their expiry
+//   - pruning the configuration history older than the retention
+func (pd *PublicDashboardServiceImpl) registerJobs(scheduler jobs.Scheduler) {
+	scheduler.Register(jobs.Job{
+		Name:         "public dashboards pre-rendering",
+		Interval:     pd.cfg.PublicDashboards.PrerenderInterval,
+		AllInstances: true,
+		Run:          pd.prerenderPublicDashboards,
+	})
+
+	scheduler.Register(jobs.Job{
+		Name:     "public dashboards scoped access tokens expiry",
+		Interval: expireScopedAccessTokensInterval,
+		Run:      pd.disableExpiredScopedAccessTokens,
+	})
+
+	if pd.cfg.PublicDashboards.HistoryRetention > 0 {
+		scheduler.Register(jobs.Job{
+			Name:     "public dashboards history pruning",
+			Interval: pruneHistoryInterval,
+			Run:      pd.prunePublicDashboardHistory,
+		})
+	}
+}
+
+// disableExpiredScopedAccessTokens disables the expired tokens, so that the
+// token list shows them as disabled rather than only rejecting them on use.
+func (pd *PublicDashboardServiceImpl) disableExpiredScopedAccessTokens(ctx context.Context) error {
+	disabled, err := pd.store.DisableExpiredScopedAccessTokens(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if disabled > 0 {
+		pd.log.Info("Disabled expired scoped access tokens", "count", disabled)
+	}
+	return nil
+}
+
+func (pd *PublicDashboardServiceImpl) prunePublicDashboardHistory(ctx context.Context) error {
+	deleted, err := pd.store.DeletePublicDashboardHistoryBefore(ctx, time.Now().Add(-pd.cfg.PublicDashboards.HistoryRetention))
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		pd.log.Info("Pruned public dashboard history", "count", deleted)
+	}
+	return nil
+}
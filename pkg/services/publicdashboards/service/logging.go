@@ -1,6 +1,8 @@
 package service
 
 import (
+	"time"
+
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
@@ -10,14 +12,14 @@ import (
 A place to record relevant logs and metrics within the service layer of public dashboards
 */
 
-func LogQuerySuccess(datasources []string, log log.Logger) {
-	log.Info("Successfully queried datasources for public dashboard", "datasources", datasources)
+func LogQuerySuccess(datasources []string, log log.Logger, duration time.Duration) {
+	log.Info("Successfully queried datasources for public dashboard", "datasources", datasources, "duration", duration)
 	label := getLabelName(datasources)
 	metrics.MPublicDashboardDatasourceQuerySuccess.WithLabelValues(label, models.QuerySuccess).Inc()
 }
 
-func LogQueryFailure(datasources []string, log log.Logger, err error) {
-	log.Error("Error querying datasources for public dashboard", "error", err.Error(), "datasources", datasources)
+func LogQueryFailure(datasources []string, log log.Logger, duration time.Duration, err error) {
+	log.Error("Error querying datasources for public dashboard", "error", err.Error(), "datasources", datasources, "duration", duration)
 	label := getLabelName(datasources)
 	metrics.MPublicDashboardDatasourceQuerySuccess.WithLabelValues(label, models.QueryFailure).Inc()
 }
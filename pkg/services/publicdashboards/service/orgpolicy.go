@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/queries"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+)
+
+// checkDashboardOrgPolicies returns an error when a panel of the dashboard
+// queries a data source that the org policies deny on public dashboards
+func (pd *PublicDashboardServiceImpl) checkDashboardOrgPolicies(ctx context.Context, dashboard *models.Dashboard) error {
+	if pd.orgPolicies == nil {
+		return nil
+	}
+
+	if err := pd.libraryPanels.LoadLibraryPanelsForDashboard(ctx, dashboard); err != nil {
+		return err
+	}
+
+	var uids []string
+	for _, panel := range flattenPanels(dashboard.Data.Get("panels").MustArray()) {
+		uids = append(uids, panelDatasourceUids(panel)...)
+	}
+
+	return pd.checkDatasourcesOrgPolicies(ctx, dashboard.OrgId, uids)
+}
+
+// checkMetricRequestOrgPolicies returns an error when the metric request
+// queries a data source that the org policies deny on public dashboards, so
+// that public dashboards enabled before the policy was set stop serving data
+func (pd *PublicDashboardServiceImpl) checkMetricRequestOrgPolicies(ctx context.Context, orgId int64, metricReq dtos.MetricRequest) error {
+	if pd.orgPolicies == nil {
+		return nil
+	}
+
+	uids := make([]string, 0, len(metricReq.Queries))
+	for _, query := range metricReq.Queries {
+		uids = append(uids, queries.GetDataSourceUidFromJson(query))
+	}
+
+	return pd.checkDatasourcesOrgPolicies(ctx, orgId, uids)
+}
+
+func (pd *PublicDashboardServiceImpl) checkDatasourcesOrgPolicies(ctx context.Context, orgId int64, uids []string) error {
+	checked := map[string]bool{}
+	for _, uid := range uids {
+		if checked[uid] || uid == dashboardDatasourceUid || uid == grafanads.DatasourceUID || expr.IsDataSource(uid) {
+			continue
+		}
+		checked[uid] = true
+
+		// missing data sources are reported by the other checks
+		ds, err := pd.getDataSource(ctx, orgId, uid)
+		if err != nil {
+			continue
+		}
+
+		if err := pd.orgPolicies.CheckPublicDashboardDatasource(ctx, orgId, ds); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/playlist"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/tokens"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// GetPublicPlaylistConfig returns the public playlist configuration of a
+// playlist
+func (pd *PublicDashboardServiceImpl) GetPublicPlaylistConfig(ctx context.Context, orgId int64, playlistUid string) (*PublicPlaylist, error) {
+	return pd.store.GetPublicPlaylistConfig(ctx, orgId, playlistUid)
+}
+
+// SavePublicPlaylistConfig creates or updates the public playlist of a
+// playlist
+func (pd *PublicDashboardServiceImpl) SavePublicPlaylistConfig(ctx context.Context, u *user.SignedInUser, dto *SavePublicPlaylistConfigDTO) (*PublicPlaylist, error) {
+	// validate if the playlist exists
+	_, err := pd.playlistService.GetWithoutItems(ctx, &playlist.GetPlaylistByUidQuery{UID: dto.PlaylistUid, OrgId: dto.OrgId})
+	if err != nil {
+		if errors.Is(err, playlist.ErrPlaylistNotFound) {
+			return nil, ErrPlaylistNotFound
+		}
+		return nil, err
+	}
+
+	// a playlist has at most one public playlist
+	existing, err := pd.store.GetPublicPlaylistConfig(ctx, dto.OrgId, dto.PlaylistUid)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Uid == "" {
+		existing = nil
+	}
+
+	var uid string
+	if existing == nil {
+		uid, err = pd.savePublicPlaylistConfig(ctx, dto)
+	} else {
+		uid = existing.Uid
+		err = pd.store.UpdatePublicPlaylistConfig(ctx, SavePublicPlaylistConfigCommand{
+			PublicPlaylist: PublicPlaylist{
+				Uid:       existing.Uid,
+				IsEnabled: dto.PublicPlaylist.IsEnabled,
+				UpdatedBy: dto.UserId,
+				UpdatedAt: time.Now(),
+			},
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	newPubplaylist, err := pd.store.GetPublicPlaylistByUid(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing == nil || existing.IsEnabled != newPubplaylist.IsEnabled {
+		pd.log.Info("Public playlist saved", "uid", newPubplaylist.Uid, "playlistUid", dto.PlaylistUid, "isEnabled", newPubplaylist.IsEnabled, "user", u.Login)
+	}
+
+	return newPubplaylist, nil
+}
+
+func (pd *PublicDashboardServiceImpl) savePublicPlaylistConfig(ctx context.Context, dto *SavePublicPlaylistConfigDTO) (string, error) {
+	uid, err := pd.store.GenerateNewPublicPlaylistUid(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	accessToken, err := tokens.GenerateAccessToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = pd.store.SavePublicPlaylistConfig(ctx, SavePublicPlaylistConfigCommand{
+		PublicPlaylist: PublicPlaylist{
+			Uid:         uid,
+			PlaylistUid: dto.PlaylistUid,
+			OrgId:       dto.OrgId,
+			IsEnabled:   dto.PublicPlaylist.IsEnabled,
+			AccessToken: accessToken,
+			CreatedBy:   dto.UserId,
+			CreatedAt:   time.Now(),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return uid, nil
+}
+
+// GetPublicPlaylist returns the playlist of the access token as served to
+// public viewers. Only the dashboards with an enabled public dashboard are
+// part of it, in the playlist order.
+func (pd *PublicDashboardServiceImpl) GetPublicPlaylist(ctx context.Context, accessToken string) (*PublicPlaylistDTO, error) {
+	pubplaylist, err := pd.store.GetPublicPlaylist(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if pubplaylist == nil || !pubplaylist.IsEnabled {
+		return nil, ErrPublicPlaylistNotFound
+	}
+
+	p, err := pd.playlistService.Get(ctx, &playlist.GetPlaylistByUidQuery{UID: pubplaylist.PlaylistUid, OrgId: pubplaylist.OrgId})
+	if err != nil {
+		if errors.Is(err, playlist.ErrPlaylistNotFound) {
+			return nil, ErrPublicPlaylistNotFound
+		}
+		return nil, err
+	}
+
+	dto := &PublicPlaylistDTO{
+		Name:     p.Name,
+		Interval: p.Interval,
+		Items:    make([]*PublicPlaylistItem, 0),
+	}
+
+	if p.Items == nil {
+		return dto, nil
+	}
+
+	// a dashboard is only shown once, even when several items match it
+	seen := make(map[string]bool)
+	for _, item := range *p.Items {
+		items, err := pd.store.GetPlaylistItemPublicDashboards(ctx, pubplaylist.OrgId, item.Type, item.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, i := range items {
+			if seen[i.AccessToken] {
+				continue
+			}
+			seen[i.AccessToken] = true
+			dto.Items = append(dto.Items, i)
+		}
+	}
+
+	return dto, nil
+}
+
+// GetPublicPlaylistOrgId returns the org of the enabled public playlist of the
+// access token, or 0 when there is none
+func (pd *PublicDashboardServiceImpl) GetPublicPlaylistOrgId(ctx context.Context, accessToken string) (int64, error) {
+	pubplaylist, err := pd.store.GetPublicPlaylist(ctx, accessToken)
+	if err != nil {
+		return 0, err
+	}
+
+	if pubplaylist == nil || !pubplaylist.IsEnabled {
+		return 0, nil
+	}
+
+	return pubplaylist.OrgId, nil
+}
+
+// playlistExternalViewerRequired returns whether one of the public dashboards
+// of the enabled public playlist of the access token requires an external
+// viewer, the playlist lists their access tokens
+func (pd *PublicDashboardServiceImpl) playlistExternalViewerRequired(ctx context.Context, accessToken string) (bool, error) {
+	pubplaylist, err := pd.GetPublicPlaylist(ctx, accessToken)
+	if errors.Is(err, ErrPublicPlaylistNotFound) {
+		return false, ErrPublicDashboardNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, item := range pubplaylist.Items {
+		pubdash, _, err := pd.store.GetPublicDashboard(ctx, item.AccessToken)
+		if err != nil {
+			return false, err
+		}
+		if pubdash.IsEnabled && pubdash.RequiresExternalViewer() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/queries"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+)
+
+// dashboardDatasourceUid is the frontend data source reusing the results of
+// another panel of the dashboard
+const dashboardDatasourceUid = "-- Dashboard --"
+
+// GetPublicDashboardPreflight reports what would be unsupported or degraded
+// if the dashboard was shared publicly, so that the share dialog can explain
+// problems before enabling the public dashboard
+func (pd *PublicDashboardServiceImpl) GetPublicDashboardPreflight(ctx context.Context, orgId int64, dashboardUid string) (*PublicDashboardPreflight, error) {
+	dash, err := pd.GetDashboard(ctx, dashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	if dash.OrgId != orgId {
+		return nil, ErrPublicDashboardNotFound
+	}
+
+	if err := pd.libraryPanels.LoadLibraryPanelsForDashboard(ctx, dash); err != nil {
+		return nil, err
+	}
+
+	settings, err := pd.store.GetOrgSettings(ctx, orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	preflight := &PublicDashboardPreflight{Shareable: true, Issues: []PreflightIssue{}}
+
+	if variables := dash.Data.Get("templating").Get("list").MustArray(); len(variables) > 0 {
+		preflight.Shareable = false
+		preflight.Issues = append(preflight.Issues, PreflightIssue{
+			Kind:     PreflightIssueTemplateVariables,
+			Severity: PreflightSeverityUnsupported,
+			Message:  "Dashboards with template variables can't be made public",
+		})
+	}
+
+	if count := countAnnotations(dash.Data); count > 0 {
+		preflight.Issues = append(preflight.Issues, PreflightIssue{
+			Kind:     PreflightIssueAnnotations,
+			Severity: PreflightSeverityDegraded,
+			Message:  fmt.Sprintf("%d annotation queries won't be displayed on the public dashboard", count),
+		})
+	}
+
+	checked := map[string]string{}
+	for _, panel := range flattenPanels(dash.Data.Get("panels").MustArray()) {
+		issue := PreflightIssue{PanelId: panel.Get("id").MustInt64(), PanelTitle: panel.Get("title").MustString()}
+
+		if _, ok := panel.CheckGet("libraryPanel"); ok && panel.Get("type").MustString() == "" {
+			issue.Kind = PreflightIssueLibraryPanel
+			issue.Severity = PreflightSeverityUnsupported
+			issue.Message = "Library panel not found"
+			preflight.Issues = append(preflight.Issues, issue)
+			continue
+		}
+
+		for _, uid := range panelDatasourceUids(panel) {
+			reason, ok := checked[uid]
+			if !ok {
+				reason = pd.unsupportedDatasourceReason(ctx, orgId, settings.AllowedDatasources, uid)
+				checked[uid] = reason
+			}
+
+			if reason != "" {
+				issue.Kind = PreflightIssueUnsupportedDatasource
+				issue.Severity = PreflightSeverityUnsupported
+				issue.Message = reason
+				preflight.Issues = append(preflight.Issues, issue)
+			}
+		}
+	}
+
+	return preflight, nil
+}
+
+// unsupportedDatasourceReason explains why public dashboard panels can't
+// query the data source, it returns an empty string when they can
+func (pd *PublicDashboardServiceImpl) unsupportedDatasourceReason(ctx context.Context, orgId int64, allowlist *DatasourceAllowlist, uid string) string {
+	switch uid {
+	case dashboardDatasourceUid:
+		return "Panels reusing the results of another panel aren't supported"
+	case grafanads.DatasourceUID:
+		return ""
+	}
+
+	ds, err := pd.getDataSource(ctx, orgId, uid)
+	if err != nil {
+		return fmt.Sprintf("Data source %s not found", uid)
+	}
+
+	if !allowlist.Allows(ds.Uid, ds.Type) {
+		return fmt.Sprintf("Data source %s isn't allowed on public dashboards of this organization", ds.Name)
+	}
+
+	if pd.orgPolicies != nil {
+		if err := pd.orgPolicies.CheckPublicDashboardDatasource(ctx, orgId, ds); err != nil {
+			return fmt.Sprintf("Data source %s is denied on public dashboards by an organization policy", ds.Name)
+		}
+	}
+
+	if pd.pluginStore != nil {
+		if plugin, exists := pd.pluginStore.Plugin(ctx, ds.Type); exists && !plugin.Backend {
+			return fmt.Sprintf("Data source %s only runs in the browser, public dashboards only support backend data sources", ds.Name)
+		}
+	}
+
+	return ""
+}
+
+// flattenPanels returns the panels of the dashboard, including the ones of
+// collapsed rows, without the rows
+func flattenPanels(panelObjs []interface{}) []*simplejson.Json {
+	var panels []*simplejson.Json
+	for _, panelObj := range panelObjs {
+		panel := simplejson.NewFromAny(panelObj)
+		if panel.Get("type").MustString() == "row" {
+			panels = append(panels, flattenPanels(panel.Get("panels").MustArray())...)
+			continue
+		}
+		panels = append(panels, panel)
+	}
+
+	return panels
+}
+
+// panelDatasourceUids returns the data sources queried by the panel, without
+// expressions
+func panelDatasourceUids(panel *simplejson.Json) []string {
+	var uids []string
+	exists := map[string]bool{}
+	add := func(uid string) {
+		if uid == "" || uid == queries.MixedDatasourceUid || expr.IsDataSource(uid) || exists[uid] {
+			return
+		}
+		exists[uid] = true
+		uids = append(uids, uid)
+	}
+
+	add(queries.GetDataSourceUidFromJson(panel))
+	for _, target := range panel.Get("targets").MustArray() {
+		add(queries.GetDataSourceUidFromJson(simplejson.NewFromAny(target)))
+	}
+
+	return uids
+}
+
+// countAnnotations counts the enabled annotation queries of the dashboard,
+// without the built-in one
+func countAnnotations(dashboard *simplejson.Json) int {
+	count := 0
+	for _, annotationObj := range dashboard.Get("annotations").Get("list").MustArray() {
+		annotation := simplejson.NewFromAny(annotationObj)
+		if annotation.Get("builtIn").MustInt() == 1 || !annotation.Get("enable").MustBool(true) {
+			continue
+		}
+		count++
+	}
+
+	return count
+}
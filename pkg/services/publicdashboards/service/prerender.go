@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/requestid"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/queries"
+)
+
+// GetPrerenderedQueryDataResponse returns the latest pre-rendered query
+// response of the panel, or nil when the public dashboard isn't pre-rendered
+// or the panel wasn't rendered yet. Scoped access tokens are never served
+// pre-rendered responses since their usage is recorded per query.
+func (pd *PublicDashboardServiceImpl) GetPrerenderedQueryDataResponse(ctx context.Context, panelId int64, accessToken string) (*PrerenderedQueryDataResponse, error) {
+	pubdash, dash, err := pd.GetPublicDashboard(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pubdash.PrerenderEnabled || pubdash.Scope != nil || pd.dashboardCache == nil {
+		return nil, nil
+	}
+
+	cached, ok := pd.dashboardCache.Get(prerenderCacheKey(dashboardCacheKey(pubdash, dash), panelId))
+	if !ok {
+		return nil, nil
+	}
+
+	return cached.(*PrerenderedQueryDataResponse), nil
+}
+
+// prerenderPublicDashboards executes the queries of every panel of the
+// public dashboards with pre-rendering enabled. Failures of single public
+// dashboards are logged and their previous responses are kept until they
+// expire.
+func (pd *PublicDashboardServiceImpl) prerenderPublicDashboards(ctx context.Context) error {
+	pubdashes, err := pd.store.GetPrerenderedPublicDashboards(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get public dashboards to pre-render: %w", err)
+	}
+
+	for _, pubdash := range pubdashes {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err := pd.prerenderPublicDashboard(ctx, pubdash.AccessToken); err != nil {
+			pd.log.Warn("Failed to pre-render public dashboard", "uid", pubdash.Uid, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (pd *PublicDashboardServiceImpl) prerenderPublicDashboard(ctx context.Context, accessToken string) error {
+	pubdash, dash, err := pd.GetPublicDashboard(ctx, accessToken)
+	if err != nil {
+		return err
+	}
+
+	// results of the previous run stay available until the next one, plus
+	// some margin for slow queries
+	ttl := 2 * pd.cfg.PublicDashboards.PrerenderInterval
+	dashKey := dashboardCacheKey(pubdash, dash)
+
+	for panelId := range queries.GroupQueriesByPanelId(dash.Data) {
+		renderedAt := time.Now()
+		requestID := requestid.New()
+		res, err := pd.GetQueryDataResponse(requestid.WithRequestID(ctx, requestID), true, PublicDashboardQueryDTO{}, panelId, accessToken)
+		if err != nil {
+			pd.log.Warn("Failed to pre-render public dashboard panel", "uid", pubdash.Uid, "panelId", panelId, "requestID", requestID, "error", err)
+			continue
+		}
+
+		pd.dashboardCache.Set(prerenderCacheKey(dashKey, panelId), &PrerenderedQueryDataResponse{
+			Response:   res,
+			RenderedAt: renderedAt,
+			RequestID:  requestID,
+		}, ttl)
+	}
+
+	return nil
+}
+
+// prerenderCacheKey identifies the pre-rendered response of a panel of the
+// processed dashboard, responses of previous dashboard versions are never
+// served
+func prerenderCacheKey(dashKey string, panelId int64) string {
+	return fmt.Sprintf("prerender/%s/%d", dashKey, panelId)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/tokens"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// GetScopedAccessTokens returns the scoped access tokens of the public
+// dashboard of a dashboard
+func (pd *PublicDashboardServiceImpl) GetScopedAccessTokens(ctx context.Context, orgId int64, dashboardUid string) ([]*ScopedAccessToken, error) {
+	pubdash, err := pd.getPublicDashboardConfig(ctx, orgId, dashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	return pd.store.GetScopedAccessTokens(ctx, pubdash.Uid)
+}
+
+// CreateScopedAccessToken creates an access token to the public dashboard of a
+// dashboard that is restricted to some of its panels and optionally to a fixed
+// time range, an expiry and query quotas
+func (pd *PublicDashboardServiceImpl) CreateScopedAccessToken(ctx context.Context, u *user.SignedInUser, dto *SaveScopedAccessTokenDTO) (*ScopedAccessToken, error) {
+	pubdash, err := pd.getPublicDashboardConfig(ctx, dto.OrgId, dto.DashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard, err := pd.GetDashboard(ctx, dto.DashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateScopedAccessToken(dto.ScopedAccessToken, dashboard); err != nil {
+		return nil, err
+	}
+
+	uid, err := pd.store.GenerateNewScopedAccessTokenUid(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := tokens.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := SaveScopedAccessTokenCommand{
+		ScopedAccessToken: ScopedAccessToken{
+			Uid:                uid,
+			PublicDashboardUid: pubdash.Uid,
+			OrgId:              dto.OrgId,
+			Name:               dto.ScopedAccessToken.Name,
+			AccessToken:        accessToken,
+			PanelIds:           dto.ScopedAccessToken.PanelIds,
+			TimeSettings:       dto.ScopedAccessToken.TimeSettings,
+			Expires:            dto.ScopedAccessToken.Expires,
+			QueryQuota:         dto.ScopedAccessToken.QueryQuota,
+			ByteQuota:          dto.ScopedAccessToken.ByteQuota,
+			CreatedBy:          dto.UserId,
+			CreatedAt:          time.Now(),
+		},
+	}
+
+	if err := pd.store.SaveScopedAccessToken(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	pd.log.Info("Scoped access token created", "uid", uid, "publicDashboardUid", pubdash.Uid, "dashboardUid", dto.DashboardUid, "user", u.Login)
+
+	return &cmd.ScopedAccessToken, nil
+}
+
+// DeleteScopedAccessToken revokes a scoped access token of the public
+// dashboard of a dashboard
+func (pd *PublicDashboardServiceImpl) DeleteScopedAccessToken(ctx context.Context, orgId int64, dashboardUid string, uid string) error {
+	pubdash, err := pd.getPublicDashboardConfig(ctx, orgId, dashboardUid)
+	if err != nil {
+		return err
+	}
+
+	return pd.store.DeleteScopedAccessToken(ctx, pubdash.Uid, uid)
+}
+
+// getPublicDashboardConfig returns the public dashboard of a dashboard or
+// ErrPublicDashboardNotFound when the dashboard has none
+func (pd *PublicDashboardServiceImpl) getPublicDashboardConfig(ctx context.Context, orgId int64, dashboardUid string) (*PublicDashboard, error) {
+	pubdash, err := pd.store.GetPublicDashboardConfig(ctx, orgId, dashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	if pubdash == nil || pubdash.Uid == "" {
+		return nil, ErrPublicDashboardNotFound
+	}
+
+	return pubdash, nil
+}
+
+// recordScopedAccessTokenUsage counts a query served with a scoped access
+// token against its quotas
+func (pd *PublicDashboardServiceImpl) recordScopedAccessTokenUsage(ctx context.Context, scope *ScopedAccessToken, res *backend.QueryDataResponse) error {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	return pd.store.RecordScopedAccessTokenUsage(ctx, scope.Uid, int64(len(data)))
+}
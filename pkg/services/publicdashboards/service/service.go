@@ -2,20 +2,36 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
 	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/httpclient/httpclientprovider"
+	"github.com/grafana/grafana/pkg/infra/jobs"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/librarypanels"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/orgpolicy"
+	"github.com/grafana/grafana/pkg/services/playlist"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/requestid"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/tokens"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/queries"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
 	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
@@ -30,6 +46,17 @@ type PublicDashboardServiceImpl struct {
 	store              publicdashboards.Store
 	intervalCalculator intervalv2.Calculator
 	QueryDataService   *query.Service
+	dashboardVersions  dashver.Service
+	datasourceService  datasources.DataSourceService
+	libraryPanels      librarypanels.Service
+	quotaService       quota.Service
+	bus                bus.Bus
+	webhooks           notifications.WebhookSender
+	playlistService    playlist.Service
+	pluginStore        plugins.Store
+	pluginClient       plugins.Client
+	dashboardCache     *localcache.CacheService
+	orgPolicies        orgpolicy.Service
 }
 
 var LogPrefix = "publicdashboards.service"
@@ -44,14 +71,46 @@ func ProvideService(
 	cfg *setting.Cfg,
 	store publicdashboards.Store,
 	qds *query.Service,
+	dashboardVersions dashver.Service,
+	datasourceService datasources.DataSourceService,
+	libraryPanels librarypanels.Service,
+	quotaService quota.Service,
+	bus bus.Bus,
+	webhooks notifications.WebhookSender,
+	playlistService playlist.Service,
+	pluginStore plugins.Store,
+	pluginClient plugins.Client,
+	orgPolicies orgpolicy.Service,
+	scheduler jobs.Scheduler,
 ) *PublicDashboardServiceImpl {
-	return &PublicDashboardServiceImpl{
+	pd := &PublicDashboardServiceImpl{
 		log:                log.New(LogPrefix),
 		cfg:                cfg,
 		store:              store,
 		intervalCalculator: intervalv2.NewCalculator(),
 		QueryDataService:   qds,
+		dashboardVersions:  dashboardVersions,
+		datasourceService:  datasourceService,
+		libraryPanels:      libraryPanels,
+		quotaService:       quotaService,
+		bus:                bus,
+		webhooks:           webhooks,
+		playlistService:    playlistService,
+		pluginStore:        pluginStore,
+		pluginClient:       pluginClient,
+		dashboardCache:     localcache.New(dashboardCacheTTL, dashboardCacheCleanupInterval),
+		orgPolicies:        orgPolicies,
 	}
+
+	requestid.RegisterLogProvider()
+	pd.registerJobs(scheduler)
+
+	if cfg.PublicDashboards.SharingWebhookUrl != "" {
+		bus.AddEventListener(pd.sendPublicDashboardEnabledWebhook)
+		bus.AddEventListener(pd.sendPublicDashboardDisabledWebhook)
+	}
+
+	return pd
 }
 
 func (pd *PublicDashboardServiceImpl) GetDashboard(ctx context.Context, dashboardUid string) (*models.Dashboard, error) {
@@ -80,9 +139,93 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboard(ctx context.Context, ac
 		return nil, nil, ErrPublicDashboardNotFound
 	}
 
+	// processing the dashboard is a hot path for anonymous requests, reuse
+	// the result for the same public dashboard and dashboard version
+	cacheKey := dashboardCacheKey(pubdash, dash)
+	if cached, ok := pd.getCachedDashboard(cacheKey); ok {
+		return pubdash, cached, nil
+	}
+
+	dash, err = pd.getPinnedDashboardVersion(ctx, pubdash, dash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the dashboard JSON only holds references to library panels, resolve
+	// them so that panels and their queries are available to public viewers
+	if err := pd.libraryPanels.LoadLibraryPanelsForDashboard(ctx, dash); err != nil {
+		return nil, nil, err
+	}
+
+	// public viewers can't change variables, repeat panels and rows for the
+	// saved variable values
+	queries.ExpandRepeats(dash.Data)
+
+	// public viewers get the fixed window of the time settings instead of the
+	// dashboard time range
+	if pubdash.TimeSettings.IsFixed() {
+		dash.Data.Set("time", map[string]interface{}{"from": pubdash.TimeSettings.From, "to": pubdash.TimeSettings.To})
+	}
+
+	// viewers of a scoped access token only get the panels and time range of
+	// the scope, the other panels can't be queried since they are gone
+	if pubdash.Scope != nil {
+		queries.FilterPanels(dash.Data, pubdash.Scope.PanelIds)
+		if ts := pubdash.Scope.TimeSettings; ts != nil {
+			dash.Data.Set("time", map[string]interface{}{"from": ts.From, "to": ts.To})
+		}
+	}
+
+	// strip alert rules, expressions and internal links the dashboard JSON
+	// would otherwise leak to public viewers
+	appURL := ""
+	if pd.cfg != nil {
+		appURL = pd.cfg.AppURL
+	}
+	queries.SanitizePanels(dash.Data, appURL)
+
+	pd.cacheDashboard(cacheKey, dash)
+
 	return pubdash, dash, nil
 }
 
+// getPinnedDashboardVersion returns the dashboard as it was saved in the
+// version pinned by the public dashboard. When no version is pinned, or the
+// pinned version is the latest one, the given dashboard is returned as is.
+func (pd *PublicDashboardServiceImpl) getPinnedDashboardVersion(ctx context.Context, pubdash *PublicDashboard, dash *models.Dashboard) (*models.Dashboard, error) {
+	if pubdash.DashboardVersion == 0 || pubdash.DashboardVersion == dash.Version {
+		return dash, nil
+	}
+
+	version, err := pd.getDashboardVersion(ctx, dash, pubdash.DashboardVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned := *dash
+	pinned.Data = version.Data
+	pinned.Version = version.Version
+	pinned.Updated = version.Created
+
+	return &pinned, nil
+}
+
+func (pd *PublicDashboardServiceImpl) getDashboardVersion(ctx context.Context, dash *models.Dashboard, version int) (*dashver.DashboardVersion, error) {
+	res, err := pd.dashboardVersions.Get(ctx, &dashver.GetDashboardVersionQuery{
+		DashboardID: dash.Id,
+		OrgID:       dash.OrgId,
+		Version:     version,
+	})
+	if err != nil {
+		if errors.Is(err, dashver.ErrDashboardVersionNotFound) {
+			return nil, ErrPublicDashboardVersionNotFound
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // GetPublicDashboardConfig is a helper method to retrieve the public dashboard configuration for a given dashboard from the database
 func (pd *PublicDashboardServiceImpl) GetPublicDashboardConfig(ctx context.Context, orgId int64, dashboardUid string) (*PublicDashboard, error) {
 	pdc, err := pd.store.GetPublicDashboardConfig(ctx, orgId, dashboardUid)
@@ -107,12 +250,52 @@ func (pd *PublicDashboardServiceImpl) SavePublicDashboardConfig(ctx context.Cont
 		dto.PublicDashboard.TimeSettings = &TimeSettings{}
 	}
 
+	// validate the pinned dashboard version exists
+	if dto.PublicDashboard.DashboardVersion < 0 {
+		return nil, ErrPublicDashboardBadRequest
+	}
+	if dto.PublicDashboard.DashboardVersion > 0 && dto.PublicDashboard.DashboardVersion != dashboard.Version {
+		if _, err := pd.getDashboardVersion(ctx, dashboard, dto.PublicDashboard.DashboardVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validation.ValidateMaskingRules(dto.PublicDashboard.MaskingRules); err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateQueryFilters(dto.PublicDashboard.QueryFilters); err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateShareMode(dto.PublicDashboard.ShareMode); err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateTimeSettings(dto.PublicDashboard.TimeSettings); err != nil {
+		return nil, err
+	}
+
 	// get existing public dashboard if exists
 	existingPubdash, err := pd.store.GetPublicDashboardByUid(ctx, dto.PublicDashboard.Uid)
 	if err != nil {
 		return nil, err
 	}
 
+	// enabled public dashboards must comply with the org policies
+	if dto.PublicDashboard.IsEnabled {
+		if err := pd.checkDashboardOrgPolicies(ctx, dashboard); err != nil {
+			return nil, err
+		}
+	}
+
+	// enabling a public dashboard counts against the org quota
+	if dto.PublicDashboard.IsEnabled && (existingPubdash == nil || !existingPubdash.IsEnabled) {
+		if err := pd.checkQuota(ctx, dto); err != nil {
+			return nil, err
+		}
+	}
+
 	// save changes
 	var pubdashUid string
 	if existingPubdash == nil {
@@ -136,9 +319,115 @@ func (pd *PublicDashboardServiceImpl) SavePublicDashboardConfig(ctx context.Cont
 
 	pd.logIsEnabledChanged(existingPubdash, newPubdash, u)
 
+	if err := pd.publishIsEnabledChanged(ctx, existingPubdash, newPubdash, dto.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := pd.savePublicDashboardHistory(ctx, existingPubdash, newPubdash, dto.UserId); err != nil {
+		return nil, err
+	}
+
 	return newPubdash, err
 }
 
+// ClonePublicDashboardConfig copies the public dashboard configuration of a
+// dashboard onto another dashboard, for copies made with "Save As" or
+// imports. The clone gets a fresh access token and serves the latest version
+// of its dashboard, scoped access tokens aren't cloned.
+func (pd *PublicDashboardServiceImpl) ClonePublicDashboardConfig(ctx context.Context, u *user.SignedInUser, dto *ClonePublicDashboardConfigDTO) (*PublicDashboard, error) {
+	source, err := pd.store.GetPublicDashboardConfig(ctx, dto.OrgId, dto.SourceDashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.Uid == "" {
+		return nil, ErrPublicDashboardNotFound
+	}
+
+	existing, err := pd.store.GetPublicDashboardConfig(ctx, dto.OrgId, dto.DashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.Uid != "" {
+		return nil, ErrPublicDashboardAlreadyExists
+	}
+
+	return pd.SavePublicDashboardConfig(ctx, u, &SavePublicDashboardConfigDTO{
+		DashboardUid: dto.DashboardUid,
+		OrgId:        dto.OrgId,
+		UserId:       dto.UserId,
+		PublicDashboard: &PublicDashboard{
+			IsEnabled:        source.IsEnabled,
+			TimeSettings:     source.TimeSettings,
+			MaskingRules:     source.MaskingRules,
+			QueryFilters:     source.QueryFilters,
+			AllowIndexing:    source.AllowIndexing,
+			RequireChallenge: source.RequireChallenge,
+			TermsOfUse:       source.TermsOfUse,
+			ShareMode:        source.ShareMode,
+			PrerenderEnabled: source.PrerenderEnabled,
+		},
+	})
+}
+
+// checkQuota returns an error when the org reached its quota of enabled public
+// dashboards
+func (pd *PublicDashboardServiceImpl) checkQuota(ctx context.Context, dto *SavePublicDashboardConfigDTO) error {
+	limitReached, err := pd.quotaService.CheckQuotaReached(ctx, "dashboard_public", &quota.ScopeParameters{
+		OrgID:  dto.OrgId,
+		UserID: dto.UserId,
+	})
+	if err != nil {
+		return err
+	}
+	if limitReached {
+		return ErrPublicDashboardQuotaReached
+	}
+	return nil
+}
+
+// savePublicDashboardHistory records a snapshot of the saved public dashboard
+// configuration so that its changes can be audited
+func (pd *PublicDashboardServiceImpl) savePublicDashboardHistory(ctx context.Context, existingPubdash *PublicDashboard, newPubdash *PublicDashboard, userId int64) error {
+	action := HistoryActionUpdated
+	switch {
+	case existingPubdash == nil:
+		action = HistoryActionCreated
+	case publicDashboardIsEnabledChanged(existingPubdash, newPubdash) && newPubdash.IsEnabled:
+		action = HistoryActionEnabled
+	case publicDashboardIsEnabledChanged(existingPubdash, newPubdash):
+		action = HistoryActionDisabled
+	}
+
+	return pd.recordPublicDashboardHistory(ctx, newPubdash, action, userId)
+}
+
+// recordPublicDashboardHistory records a snapshot of the public dashboard
+// configuration with the action that led to it
+func (pd *PublicDashboardServiceImpl) recordPublicDashboardHistory(ctx context.Context, pubdash *PublicDashboard, action string, userId int64) error {
+	return pd.store.SavePublicDashboardHistory(ctx, SavePublicDashboardHistoryCommand{
+		History: PublicDashboardHistory{
+			PublicDashboardUid: pubdash.Uid,
+			DashboardUid:       pubdash.DashboardUid,
+			OrgId:              pubdash.OrgId,
+			Action:             action,
+			IsEnabled:          pubdash.IsEnabled,
+			TimeSettings:       pubdash.TimeSettings,
+			DashboardVersion:   pubdash.DashboardVersion,
+			AccessToken:        pubdash.AccessToken,
+			CreatedBy:          userId,
+			CreatedAt:          time.Now(),
+		},
+	})
+}
+
+// GetPublicDashboardHistory returns the configuration changes of the public
+// dashboard of a dashboard, most recent first
+func (pd *PublicDashboardServiceImpl) GetPublicDashboardHistory(ctx context.Context, orgId int64, dashboardUid string) ([]*PublicDashboardHistory, error) {
+	return pd.store.GetPublicDashboardHistory(ctx, orgId, dashboardUid)
+}
+
 // Called by SavePublicDashboardConfig this handles business logic
 // to generate token and calls create at the database layer
 func (pd *PublicDashboardServiceImpl) savePublicDashboardConfig(ctx context.Context, dto *SavePublicDashboardConfigDTO) (string, error) {
@@ -154,14 +443,22 @@ func (pd *PublicDashboardServiceImpl) savePublicDashboardConfig(ctx context.Cont
 
 	cmd := SavePublicDashboardConfigCommand{
 		PublicDashboard: PublicDashboard{
-			Uid:          uid,
-			DashboardUid: dto.DashboardUid,
-			OrgId:        dto.OrgId,
-			IsEnabled:    dto.PublicDashboard.IsEnabled,
-			TimeSettings: dto.PublicDashboard.TimeSettings,
-			CreatedBy:    dto.UserId,
-			CreatedAt:    time.Now(),
-			AccessToken:  accessToken,
+			Uid:              uid,
+			DashboardUid:     dto.DashboardUid,
+			OrgId:            dto.OrgId,
+			IsEnabled:        dto.PublicDashboard.IsEnabled,
+			TimeSettings:     dto.PublicDashboard.TimeSettings,
+			DashboardVersion: dto.PublicDashboard.DashboardVersion,
+			MaskingRules:     dto.PublicDashboard.MaskingRules,
+			QueryFilters:     dto.PublicDashboard.QueryFilters,
+			AllowIndexing:    dto.PublicDashboard.AllowIndexing,
+			RequireChallenge: dto.PublicDashboard.RequireChallenge,
+			TermsOfUse:       dto.PublicDashboard.TermsOfUse,
+			ShareMode:        dto.PublicDashboard.ShareMode,
+			PrerenderEnabled: dto.PublicDashboard.PrerenderEnabled,
+			CreatedBy:        dto.UserId,
+			CreatedAt:        time.Now(),
+			AccessToken:      accessToken,
 		},
 	}
 
@@ -178,11 +475,19 @@ func (pd *PublicDashboardServiceImpl) savePublicDashboardConfig(ctx context.Cont
 func (pd *PublicDashboardServiceImpl) updatePublicDashboardConfig(ctx context.Context, dto *SavePublicDashboardConfigDTO) (string, error) {
 	cmd := SavePublicDashboardConfigCommand{
 		PublicDashboard: PublicDashboard{
-			Uid:          dto.PublicDashboard.Uid,
-			IsEnabled:    dto.PublicDashboard.IsEnabled,
-			TimeSettings: dto.PublicDashboard.TimeSettings,
-			UpdatedBy:    dto.UserId,
-			UpdatedAt:    time.Now(),
+			Uid:              dto.PublicDashboard.Uid,
+			IsEnabled:        dto.PublicDashboard.IsEnabled,
+			TimeSettings:     dto.PublicDashboard.TimeSettings,
+			DashboardVersion: dto.PublicDashboard.DashboardVersion,
+			MaskingRules:     dto.PublicDashboard.MaskingRules,
+			QueryFilters:     dto.PublicDashboard.QueryFilters,
+			AllowIndexing:    dto.PublicDashboard.AllowIndexing,
+			RequireChallenge: dto.PublicDashboard.RequireChallenge,
+			TermsOfUse:       dto.PublicDashboard.TermsOfUse,
+			ShareMode:        dto.PublicDashboard.ShareMode,
+			PrerenderEnabled: dto.PublicDashboard.PrerenderEnabled,
+			UpdatedBy:        dto.UserId,
+			UpdatedAt:        time.Now(),
 		},
 	}
 
@@ -200,22 +505,52 @@ func (pd *PublicDashboardServiceImpl) GetQueryDataResponse(ctx context.Context,
 		return nil, err
 	}
 
+	if err := pd.checkDatasourcesAllowed(ctx, dashboard.OrgId, metricReq); err != nil {
+		return nil, err
+	}
+
+	if err := pd.checkMetricRequestOrgPolicies(ctx, dashboard.OrgId, metricReq); err != nil {
+		return nil, err
+	}
+
+	if err := pd.injectQueryFilters(ctx, dashboard.OrgId, publicDashboard.QueryFilters, metricReq); err != nil {
+		return nil, err
+	}
+
 	anonymousUser, err := pd.BuildAnonymousUser(ctx, dashboard)
 	if err != nil {
 		return nil, err
 	}
 
+	// the request ID lets support trace a public panel query end to end, it
+	// is sent to the data sources and added to the logs
+	if requestID := requestid.FromContext(ctx); requestID != "" {
+		ctx = httpclient.WithContextualMiddleware(ctx, httpclientprovider.ForwardedRequestIDMiddleware(requestid.HeaderName, requestID))
+	}
+	logger := pd.log.FromContext(ctx).New("uid", publicDashboard.Uid, "panelId", panelId)
+
+	start := time.Now()
 	res, err := pd.QueryDataService.QueryDataMultipleSources(ctx, anonymousUser, skipCache, metricReq, true)
 
 	reqDatasources := metricReq.GetUniqueDatasourceTypes()
 	if err != nil {
-		LogQueryFailure(reqDatasources, pd.log, err)
+		LogQueryFailure(reqDatasources, logger, time.Since(start), err)
 		return nil, err
 	}
-	LogQuerySuccess(reqDatasources, pd.log)
+	LogQuerySuccess(reqDatasources, logger, time.Since(start))
 
 	queries.SanitizeMetadataFromQueryData(res)
 
+	if err := queries.MaskQueryData(res, publicDashboard.MaskingRules); err != nil {
+		return nil, err
+	}
+
+	if publicDashboard.Scope != nil {
+		if err := pd.recordScopedAccessTokenUsage(ctx, publicDashboard.Scope, res); err != nil {
+			return nil, err
+		}
+	}
+
 	return res, nil
 }
 
@@ -264,6 +599,116 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(ctx context.Context, da
 	}, nil
 }
 
+// checkDatasourcesAllowed returns ErrPublicDashboardDatasourceNotAllowed when
+// the metric request queries a data source that is not on the org allowlist
+func (pd *PublicDashboardServiceImpl) checkDatasourcesAllowed(ctx context.Context, orgId int64, metricReq dtos.MetricRequest) error {
+	settings, err := pd.store.GetOrgSettings(ctx, orgId)
+	if err != nil {
+		return err
+	}
+
+	allowlist := settings.AllowedDatasources
+	if allowlist.IsEmpty() {
+		return nil
+	}
+
+	for _, query := range metricReq.Queries {
+		uid := queries.GetDataSourceUidFromJson(query)
+
+		// expressions only operate on the results of the other queries of the panel
+		if expr.IsDataSource(uid) || allowlist.Allows(uid, "") {
+			continue
+		}
+
+		ds, err := pd.getDataSource(ctx, orgId, uid)
+		if err != nil {
+			return ErrPublicDashboardDatasourceNotAllowed
+		}
+
+		if !allowlist.Allows(ds.Uid, ds.Type) {
+			return ErrPublicDashboardDatasourceNotAllowed
+		}
+	}
+
+	return nil
+}
+
+// injectQueryFilters adds the query filter of the data source type to every
+// query of the metric request. Queries to data sources without a filter, or
+// for which the filter can't be applied, are rejected.
+func (pd *PublicDashboardServiceImpl) injectQueryFilters(ctx context.Context, orgId int64, filters QueryFilters, metricReq dtos.MetricRequest) error {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	filtersByType := make(map[string]string, len(filters))
+	for _, filter := range filters {
+		filtersByType[filter.DatasourceType] = filter.Filter
+	}
+
+	for _, query := range metricReq.Queries {
+		uid := queries.GetDataSourceUidFromJson(query)
+		if expr.IsDataSource(uid) {
+			continue
+		}
+
+		ds, err := pd.getDataSource(ctx, orgId, uid)
+		if err != nil {
+			return ErrPublicDashboardQueryFilterNotApplied
+		}
+
+		filter, ok := filtersByType[ds.Type]
+		if !ok {
+			return ErrPublicDashboardQueryFilterNotApplied
+		}
+
+		if err := queries.InjectQueryFilter(query, ds.Type, filter); err != nil {
+			pd.log.Warn("Failed to apply public dashboard query filter", "datasource", uid, "error", err)
+			return ErrPublicDashboardQueryFilterNotApplied
+		}
+	}
+
+	return nil
+}
+
+func (pd *PublicDashboardServiceImpl) getDataSource(ctx context.Context, orgId int64, uid string) (*datasources.DataSource, error) {
+	dsQuery := &datasources.GetDataSourceQuery{OrgId: orgId, Uid: uid}
+	if err := pd.datasourceService.GetDataSource(ctx, dsQuery); err != nil {
+		pd.log.Warn("Failed to get data source for public dashboard query", "datasource", uid, "error", err)
+		return nil, err
+	}
+
+	return dsQuery.Result, nil
+}
+
+// GetOrgSettings returns the public dashboards settings of an org
+func (pd *PublicDashboardServiceImpl) GetOrgSettings(ctx context.Context, orgId int64) (*OrgSettings, error) {
+	return pd.store.GetOrgSettings(ctx, orgId)
+}
+
+// SaveOrgSettings persists the public dashboards settings of an org
+func (pd *PublicDashboardServiceImpl) SaveOrgSettings(ctx context.Context, dto *SaveOrgSettingsDTO) (*OrgSettings, error) {
+	if err := validation.ValidateCountryRestrictions(dto.Settings.CountryRestrictions); err != nil {
+		return nil, err
+	}
+
+	cmd := SaveOrgSettingsCommand{
+		Settings: OrgSettings{
+			OrgId:               dto.OrgId,
+			AllowedDatasources:  dto.Settings.AllowedDatasources,
+			CountryRestrictions: dto.Settings.CountryRestrictions,
+			UpdatedBy:           dto.UserId,
+			UpdatedAt:           time.Now(),
+		},
+	}
+
+	if err := pd.store.SaveOrgSettings(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	return pd.store.GetOrgSettings(ctx, dto.OrgId)
+}
+
 // BuildAnonymousUser creates a user with permissions to read from all datasources used in the dashboard
 func (pd *PublicDashboardServiceImpl) BuildAnonymousUser(ctx context.Context, dashboard *models.Dashboard) (*user.SignedInUser, error) {
 	datasourceUids := queries.GetUniqueDashboardDatasourceUids(dashboard.Data)
@@ -292,6 +737,157 @@ func (pd *PublicDashboardServiceImpl) AccessTokenExists(ctx context.Context, acc
 	return pd.store.AccessTokenExists(ctx, accessToken)
 }
 
+// IndexingAllowed returns true if search engines may index the public
+// dashboard of the access token
+func (pd *PublicDashboardServiceImpl) IndexingAllowed(ctx context.Context, accessToken string) (bool, error) {
+	pubdash, _, err := pd.store.GetPublicDashboard(ctx, accessToken)
+	if err != nil {
+		return false, err
+	}
+
+	if pubdash == nil || !pubdash.IsEnabled {
+		return false, nil
+	}
+
+	return pubdash.IndexingAllowed(pd.cfg.PublicDashboards.AllowIndexing), nil
+}
+
+// ChallengeRequired returns true if public viewers have to solve a challenge
+// before querying the public dashboard of the access token
+func (pd *PublicDashboardServiceImpl) ChallengeRequired(ctx context.Context, accessToken string) (bool, error) {
+	pubdash, _, err := pd.store.GetPublicDashboard(ctx, accessToken)
+	if err != nil {
+		return false, err
+	}
+
+	if pubdash == nil || !pubdash.IsEnabled {
+		return false, ErrPublicDashboardNotFound
+	}
+
+	return pubdash.RequireChallenge, nil
+}
+
+// ExternalViewerRequired returns true if only viewers authenticated by the
+// external identity provider can view the public dashboard of the access token
+func (pd *PublicDashboardServiceImpl) ExternalViewerRequired(ctx context.Context, accessToken string) (bool, error) {
+	pubdash, _, err := pd.store.GetPublicDashboard(ctx, accessToken)
+	if errors.Is(err, ErrPublicDashboardNotFound) {
+		// the access token may be the one of a public playlist
+		return pd.playlistExternalViewerRequired(ctx, accessToken)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if pubdash == nil || !pubdash.IsEnabled {
+		return false, ErrPublicDashboardNotFound
+	}
+
+	return pubdash.RequiresExternalViewer(), nil
+}
+
+// GetTermsOfUse returns the terms of use public viewers have to acknowledge
+// before querying the public dashboard of the access token
+func (pd *PublicDashboardServiceImpl) GetTermsOfUse(ctx context.Context, accessToken string) (string, error) {
+	pubdash, _, err := pd.store.GetPublicDashboard(ctx, accessToken)
+	if err != nil {
+		return "", err
+	}
+
+	if pubdash == nil || !pubdash.IsEnabled {
+		return "", ErrPublicDashboardNotFound
+	}
+
+	return pubdash.TermsOfUse, nil
+}
+
+// GetPanelAlertStates counts the alerts of a panel of the public dashboard of
+// the access token by state. Only the counts are returned so that public
+// viewers don't learn anything about the alert rules.
+func (pd *PublicDashboardServiceImpl) GetPanelAlertStates(ctx context.Context, accessToken string, panelId int64) (*PanelAlertStates, error) {
+	if !pd.cfg.PublicDashboards.AlertStateEnabled {
+		return nil, ErrPublicDashboardAlertStateDisabled
+	}
+
+	_, dashboard, err := pd.GetPublicDashboard(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := queries.GroupQueriesByPanelId(dashboard.Data)[panelId]; !ok {
+		return nil, ErrPublicDashboardPanelNotFound
+	}
+
+	states := &PanelAlertStates{}
+	if pd.cfg.UnifiedAlerting.IsEnabled() {
+		counts, err := pd.store.GetUnifiedPanelAlertStates(ctx, dashboard, panelId)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range counts {
+			switch ngmodels.InstanceStateType(c.State) {
+			case ngmodels.InstanceStateFiring:
+				states.Firing += c.Count
+			case ngmodels.InstanceStatePending:
+				states.Pending += c.Count
+			case ngmodels.InstanceStateNormal:
+				states.Ok += c.Count
+			case ngmodels.InstanceStateNoData:
+				states.NoData += c.Count
+			case ngmodels.InstanceStateError:
+				states.Error += c.Count
+			}
+		}
+		return states, nil
+	}
+
+	counts, err := pd.store.GetLegacyPanelAlertStates(ctx, dashboard, panelId)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		switch models.AlertStateType(c.State) {
+		case models.AlertStateAlerting:
+			states.Firing += c.Count
+		case models.AlertStatePending:
+			states.Pending += c.Count
+		case models.AlertStateOK:
+			states.Ok += c.Count
+		case models.AlertStateNoData:
+			states.NoData += c.Count
+		}
+	}
+	return states, nil
+}
+
+// CountryAllowed returns true if the public dashboard of the access token may
+// be served to a client from the given country, according to the country
+// restrictions of its org. An empty country means that it is unknown.
+func (pd *PublicDashboardServiceImpl) CountryAllowed(ctx context.Context, accessToken string, country string) (bool, error) {
+	orgId, err := pd.store.GetPublicDashboardOrgId(ctx, accessToken)
+	if err != nil {
+		return false, err
+	}
+
+	// the access token may be the one of a public playlist
+	if orgId == 0 {
+		if orgId, err = pd.GetPublicPlaylistOrgId(ctx, accessToken); err != nil {
+			return false, err
+		}
+	}
+
+	if orgId == 0 {
+		return false, ErrPublicDashboardNotFound
+	}
+
+	settings, err := pd.store.GetOrgSettings(ctx, orgId)
+	if err != nil {
+		return false, err
+	}
+
+	return settings.CountryRestrictions.Allows(country), nil
+}
+
 func (pd *PublicDashboardServiceImpl) GetPublicDashboardOrgId(ctx context.Context, accessToken string) (int64, error) {
 	return pd.store.GetPublicDashboardOrgId(ctx, accessToken)
 }
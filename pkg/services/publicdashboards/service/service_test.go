@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -11,18 +13,39 @@ import (
 	"github.com/grafana/grafana/pkg/services/user"
 
 	"github.com/google/uuid"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/jobs/jobstest"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	dashboardsDB "github.com/grafana/grafana/pkg/services/dashboards/database"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/dashboardversion/dashverimpl"
+	"github.com/grafana/grafana/pkg/services/dashboardversion/dashvertest"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/librarypanels"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/playlist"
+	"github.com/grafana/grafana/pkg/services/playlist/playlistimpl"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/database"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
 )
 
@@ -91,8 +114,9 @@ func TestGetPublicDashboard(t *testing.T) {
 		t.Run(test.Name, func(t *testing.T) {
 			fakeStore := FakePublicDashboardStore{}
 			service := &PublicDashboardServiceImpl{
-				log:   log.New("test.logger"),
-				store: &fakeStore,
+				log:           log.New("test.logger"),
+				store:         &fakeStore,
+				libraryPanels: &fakeLibraryPanelService{},
 			}
 
 			fakeStore.On("GetPublicDashboard", mock.Anything, mock.Anything).
@@ -115,6 +139,522 @@ func TestGetPublicDashboard(t *testing.T) {
 	}
 }
 
+func TestGetPublicDashboardPinnedVersion(t *testing.T) {
+	latestData := simplejson.NewFromAny(map[string]interface{}{"title": "latest"})
+	pinnedData := simplejson.NewFromAny(map[string]interface{}{"title": "pinned"})
+	dash := &models.Dashboard{Id: 1, Uid: "mydashboard", OrgId: 1, Version: 3, Data: latestData}
+
+	t.Run("returns the pinned dashboard version", func(t *testing.T) {
+		fakeStore := FakePublicDashboardStore{}
+		fakeVersions := dashvertest.NewDashboardVersionServiceFake()
+		fakeVersions.ExpectedDashboardVersion = &dashver.DashboardVersion{DashboardID: 1, Version: 2, Data: pinnedData}
+		service := &PublicDashboardServiceImpl{
+			log:               log.New("test.logger"),
+			store:             &fakeStore,
+			dashboardVersions: fakeVersions,
+			libraryPanels:     &fakeLibraryPanelService{},
+		}
+
+		fakeStore.On("GetPublicDashboard", mock.Anything, mock.Anything).
+			Return(&PublicDashboard{AccessToken: "abcdToken", IsEnabled: true, DashboardVersion: 2}, dash, nil)
+
+		_, pinned, err := service.GetPublicDashboard(context.Background(), "abcdToken")
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, pinned.Version)
+		assert.Equal(t, pinnedData, pinned.Data)
+		assert.Equal(t, dash.Uid, pinned.Uid)
+		// original dashboard is not modified
+		assert.Equal(t, 3, dash.Version)
+		assert.Equal(t, latestData, dash.Data)
+	})
+
+	t.Run("returns the latest dashboard when no version is pinned", func(t *testing.T) {
+		fakeStore := FakePublicDashboardStore{}
+		service := &PublicDashboardServiceImpl{
+			log:           log.New("test.logger"),
+			store:         &fakeStore,
+			libraryPanels: &fakeLibraryPanelService{},
+		}
+
+		fakeStore.On("GetPublicDashboard", mock.Anything, mock.Anything).
+			Return(&PublicDashboard{AccessToken: "abcdToken", IsEnabled: true}, dash, nil)
+
+		_, latest, err := service.GetPublicDashboard(context.Background(), "abcdToken")
+		require.NoError(t, err)
+		assert.Equal(t, dash, latest)
+	})
+
+	t.Run("returns ErrPublicDashboardVersionNotFound when the pinned version is missing", func(t *testing.T) {
+		fakeStore := FakePublicDashboardStore{}
+		fakeVersions := dashvertest.NewDashboardVersionServiceFake()
+		fakeVersions.ExpectedError = dashver.ErrDashboardVersionNotFound
+		service := &PublicDashboardServiceImpl{
+			log:               log.New("test.logger"),
+			store:             &fakeStore,
+			dashboardVersions: fakeVersions,
+		}
+
+		fakeStore.On("GetPublicDashboard", mock.Anything, mock.Anything).
+			Return(&PublicDashboard{AccessToken: "abcdToken", IsEnabled: true, DashboardVersion: 1}, dash, nil)
+
+		_, _, err := service.GetPublicDashboard(context.Background(), "abcdToken")
+		require.ErrorIs(t, err, ErrPublicDashboardVersionNotFound)
+	})
+}
+
+func TestGetPublicDashboardLibraryPanels(t *testing.T) {
+	dashData := simplejson.NewFromAny(map[string]interface{}{
+		"panels": []interface{}{
+			map[string]interface{}{"id": 1, "type": "timeseries"},
+			map[string]interface{}{"id": 2, "gridPos": map[string]interface{}{"x": 0}, "libraryPanel": map[string]interface{}{"uid": "lib1"}},
+		},
+	})
+	dash := &models.Dashboard{Id: 1, Uid: "mydashboard", OrgId: 1, Data: dashData}
+
+	fakeStore := FakePublicDashboardStore{}
+	service := &PublicDashboardServiceImpl{
+		log:   log.New("test.logger"),
+		store: &fakeStore,
+		libraryPanels: &fakeLibraryPanelService{
+			panels: map[string]map[string]interface{}{
+				"lib1": {"type": "stat", "targets": []interface{}{map[string]interface{}{"refId": "A"}}},
+			},
+		},
+	}
+
+	fakeStore.On("GetPublicDashboard", mock.Anything, mock.Anything).
+		Return(&PublicDashboard{AccessToken: "abcdToken", IsEnabled: true}, dash, nil)
+
+	_, resolved, err := service.GetPublicDashboard(context.Background(), "abcdToken")
+	require.NoError(t, err)
+
+	panels := resolved.Data.Get("panels")
+	assert.Equal(t, "timeseries", panels.GetIndex(0).Get("type").MustString())
+	assert.Equal(t, "stat", panels.GetIndex(1).Get("type").MustString())
+	assert.Equal(t, int64(2), panels.GetIndex(1).Get("id").MustInt64())
+	assert.Len(t, panels.GetIndex(1).Get("targets").MustArray(), 1)
+}
+
+func TestGetPublicDashboardSanitizesPanels(t *testing.T) {
+	dashData := simplejson.NewFromAny(map[string]interface{}{
+		"panels": []interface{}{
+			map[string]interface{}{
+				"id":    1,
+				"alert": map[string]interface{}{"name": "High CPU"},
+				"targets": []interface{}{
+					map[string]interface{}{"refId": "A"},
+					map[string]interface{}{"refId": "B", "datasource": map[string]interface{}{"uid": "__expr__"}, "type": "reduce", "expression": "$hidden"},
+				},
+				"links": []interface{}{
+					map[string]interface{}{"title": "Internal", "url": "http://localhost:3000/d/internal"},
+					map[string]interface{}{"title": "External", "url": "https://example.com"},
+				},
+			},
+		},
+	})
+	dash := &models.Dashboard{Id: 1, Uid: "mydashboard", OrgId: 1, Data: dashData}
+
+	cfg := setting.NewCfg()
+	cfg.AppURL = "http://localhost:3000/"
+	fakeStore := FakePublicDashboardStore{}
+	service := &PublicDashboardServiceImpl{
+		log:           log.New("test.logger"),
+		cfg:           cfg,
+		store:         &fakeStore,
+		libraryPanels: &fakeLibraryPanelService{},
+	}
+
+	fakeStore.On("GetPublicDashboard", mock.Anything, mock.Anything).
+		Return(&PublicDashboard{AccessToken: "abcdToken", IsEnabled: true}, dash, nil)
+
+	_, sanitized, err := service.GetPublicDashboard(context.Background(), "abcdToken")
+	require.NoError(t, err)
+
+	panel := sanitized.Data.Get("panels").GetIndex(0)
+	_, hasAlert := panel.CheckGet("alert")
+	assert.False(t, hasAlert)
+	assert.Len(t, panel.Get("targets").MustArray(), 1)
+	require.Len(t, panel.Get("links").MustArray(), 1)
+	assert.Equal(t, "External", panel.Get("links").GetIndex(0).Get("title").MustString())
+}
+
+func TestGetPublicDashboardPreflight(t *testing.T) {
+	dashData := simplejson.NewFromAny(map[string]interface{}{
+		"annotations": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{"builtIn": 1, "enable": true},
+				map[string]interface{}{"name": "Deploys", "enable": true},
+				map[string]interface{}{"name": "Disabled", "enable": false},
+			},
+		},
+		"panels": []interface{}{
+			map[string]interface{}{"id": 1, "title": "CPU", "datasource": map[string]interface{}{"uid": "prom"}},
+			map[string]interface{}{"id": 2, "title": "Browser", "datasource": map[string]interface{}{"uid": "frontend"}},
+			map[string]interface{}{
+				"id": 3, "type": "row", "collapsed": true,
+				"panels": []interface{}{
+					map[string]interface{}{"id": 4, "title": "Mixed", "datasource": map[string]interface{}{"uid": "-- Mixed --"}, "targets": []interface{}{
+						map[string]interface{}{"refId": "A", "datasource": map[string]interface{}{"uid": "deleted"}},
+						map[string]interface{}{"refId": "B", "datasource": map[string]interface{}{"uid": "-- Dashboard --"}},
+					}},
+					map[string]interface{}{"id": 5, "libraryPanel": map[string]interface{}{"uid": "missing"}},
+				},
+			},
+		},
+	})
+	dash := &models.Dashboard{Id: 1, Uid: "mydashboard", OrgId: 1, Data: dashData}
+
+	fakeStore := FakePublicDashboardStore{}
+	fakeStore.On("GetDashboard", mock.Anything, "mydashboard").Return(dash, nil)
+	fakeStore.On("GetOrgSettings", mock.Anything, int64(1)).Return(&OrgSettings{OrgId: 1}, nil)
+
+	service := &PublicDashboardServiceImpl{
+		log:           log.New("test.logger"),
+		store:         &fakeStore,
+		libraryPanels: &fakeLibraryPanelService{},
+		datasourceService: &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+			{Uid: "prom", Name: "Prometheus", Type: "prometheus", OrgId: 1},
+			{Uid: "frontend", Name: "Browser", Type: "frontend-ds", OrgId: 1},
+		}},
+		pluginStore: plugins.FakePluginStore{PluginList: []plugins.PluginDTO{
+			{JSONData: plugins.JSONData{ID: "prometheus", Backend: true}},
+			{JSONData: plugins.JSONData{ID: "frontend-ds", Backend: false}},
+		}},
+	}
+
+	t.Run("reports unsupported panels and degraded features", func(t *testing.T) {
+		preflight, err := service.GetPublicDashboardPreflight(context.Background(), 1, "mydashboard")
+		require.NoError(t, err)
+
+		assert.True(t, preflight.Shareable)
+		kinds := map[int64][]string{}
+		for _, issue := range preflight.Issues {
+			kinds[issue.PanelId] = append(kinds[issue.PanelId], issue.Kind)
+		}
+		assert.Equal(t, map[int64][]string{
+			0: {PreflightIssueAnnotations},
+			2: {PreflightIssueUnsupportedDatasource},
+			4: {PreflightIssueUnsupportedDatasource, PreflightIssueUnsupportedDatasource},
+			5: {PreflightIssueLibraryPanel},
+		}, kinds)
+	})
+
+	t.Run("reports dashboards with template variables as not shareable", func(t *testing.T) {
+		dashData.Set("templating", map[string]interface{}{"list": []interface{}{map[string]interface{}{"name": "host"}}})
+		defer dashData.Del("templating")
+
+		preflight, err := service.GetPublicDashboardPreflight(context.Background(), 1, "mydashboard")
+		require.NoError(t, err)
+
+		assert.False(t, preflight.Shareable)
+		assert.Equal(t, PreflightIssueTemplateVariables, preflight.Issues[0].Kind)
+	})
+
+	t.Run("returns not found for dashboards of other orgs", func(t *testing.T) {
+		_, err := service.GetPublicDashboardPreflight(context.Background(), 2, "mydashboard")
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+}
+
+func TestGetPublicDashboardHealth(t *testing.T) {
+	dash := &models.Dashboard{Id: 1, Uid: "mydashboard", OrgId: 1, Data: simplejson.NewFromAny(map[string]interface{}{
+		"panels": []interface{}{
+			map[string]interface{}{"id": 1, "datasource": map[string]interface{}{"uid": "prom"}},
+			map[string]interface{}{"id": 2, "datasource": map[string]interface{}{"uid": "loki"}},
+			map[string]interface{}{"id": 3, "datasource": map[string]interface{}{"uid": "browser"}},
+		},
+	})}
+
+	setup := func(pubdash *PublicDashboard, results map[string]*backend.CheckHealthResult) (*PublicDashboardServiceImpl, *FakePublicDashboardStore) {
+		fakeStore := &FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abc123").Return(pubdash, dash, nil).Maybe()
+
+		service := &PublicDashboardServiceImpl{
+			log:           log.New("test.logger"),
+			store:         fakeStore,
+			libraryPanels: &fakeLibraryPanelService{},
+			datasourceService: &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+				{Uid: "prom", Type: "prometheus", OrgId: 1},
+				{Uid: "loki", Type: "loki", OrgId: 1},
+				{Uid: "browser", Type: "frontend-ds", OrgId: 1},
+			}},
+			pluginClient:   &fakePluginClient{results: results},
+			dashboardCache: localcache.New(time.Minute, time.Minute),
+		}
+		return service, fakeStore
+	}
+
+	t.Run("is healthy when all data sources are reachable", func(t *testing.T) {
+		service, _ := setup(&PublicDashboard{IsEnabled: true}, map[string]*backend.CheckHealthResult{
+			"prometheus": {Status: backend.HealthStatusOk},
+			"loki":       {Status: backend.HealthStatusOk},
+		})
+
+		health, err := service.GetPublicDashboardHealth(context.Background(), "abc123")
+		require.NoError(t, err)
+		assert.Equal(t, &PublicDashboardHealth{Healthy: true, Enabled: true, DatasourcesReachable: true}, health)
+	})
+
+	t.Run("is unhealthy when a data source is unreachable", func(t *testing.T) {
+		service, _ := setup(&PublicDashboard{IsEnabled: true}, map[string]*backend.CheckHealthResult{
+			"prometheus": {Status: backend.HealthStatusOk},
+			"loki":       {Status: backend.HealthStatusError, Message: "connection refused"},
+		})
+
+		health, err := service.GetPublicDashboardHealth(context.Background(), "abc123")
+		require.NoError(t, err)
+		assert.Equal(t, &PublicDashboardHealth{Enabled: true, UnreachableDatasources: 1}, health)
+	})
+
+	t.Run("is unhealthy when the public dashboard is disabled", func(t *testing.T) {
+		service, _ := setup(&PublicDashboard{IsEnabled: false}, nil)
+
+		health, err := service.GetPublicDashboardHealth(context.Background(), "abc123")
+		require.NoError(t, err)
+		assert.Equal(t, &PublicDashboardHealth{}, health)
+	})
+
+	t.Run("is unhealthy when the scoped access token expired", func(t *testing.T) {
+		fakeStore := &FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "expired").Return(nil, nil, ErrPublicDashboardNotFound)
+		fakeStore.On("GetScopedAccessToken", mock.Anything, "expired").Return(&ScopedAccessToken{AccessToken: "expired"}, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: fakeStore}
+
+		health, err := service.GetPublicDashboardHealth(context.Background(), "expired")
+		require.NoError(t, err)
+		assert.Equal(t, &PublicDashboardHealth{Enabled: true, Expired: true}, health)
+	})
+
+	t.Run("returns not found for unknown access tokens", func(t *testing.T) {
+		fakeStore := &FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "unknown").Return(nil, nil, ErrPublicDashboardNotFound)
+		fakeStore.On("GetScopedAccessToken", mock.Anything, "unknown").Return(nil, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: fakeStore}
+
+		_, err := service.GetPublicDashboardHealth(context.Background(), "unknown")
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+
+	t.Run("caches the result", func(t *testing.T) {
+		service, fakeStore := setup(&PublicDashboard{IsEnabled: true}, map[string]*backend.CheckHealthResult{
+			"prometheus": {Status: backend.HealthStatusOk},
+			"loki":       {Status: backend.HealthStatusOk},
+		})
+
+		_, err := service.GetPublicDashboardHealth(context.Background(), "abc123")
+		require.NoError(t, err)
+		calls := len(fakeStore.Calls)
+
+		_, err = service.GetPublicDashboardHealth(context.Background(), "abc123")
+		require.NoError(t, err)
+		assert.Len(t, fakeStore.Calls, calls)
+	})
+}
+
+func TestGetPrerenderedQueryDataResponse(t *testing.T) {
+	dash := &models.Dashboard{Id: 1, Uid: "mydashboard", OrgId: 1, Version: 2, Data: simplejson.NewFromAny(map[string]interface{}{
+		"panels": []interface{}{map[string]interface{}{"id": 1}},
+	})}
+	prerendered := &PrerenderedQueryDataResponse{Response: &backend.QueryDataResponse{}, RenderedAt: time.Now()}
+
+	setup := func(pubdash *PublicDashboard) *PublicDashboardServiceImpl {
+		fakeStore := &FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abc123").Return(pubdash, dash, nil)
+
+		service := &PublicDashboardServiceImpl{
+			log:            log.New("test.logger"),
+			store:          fakeStore,
+			libraryPanels:  &fakeLibraryPanelService{},
+			dashboardCache: localcache.New(time.Minute, time.Minute),
+		}
+		service.dashboardCache.Set(prerenderCacheKey(dashboardCacheKey(pubdash, dash), 1), prerendered, time.Minute)
+		return service
+	}
+
+	t.Run("returns the pre-rendered response of the panel", func(t *testing.T) {
+		service := setup(&PublicDashboard{Uid: "pubdash", IsEnabled: true, PrerenderEnabled: true})
+
+		res, err := service.GetPrerenderedQueryDataResponse(context.Background(), 1, "abc123")
+		require.NoError(t, err)
+		assert.Same(t, prerendered, res)
+
+		res, err = service.GetPrerenderedQueryDataResponse(context.Background(), 2, "abc123")
+		require.NoError(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("returns nil when pre-rendering is disabled", func(t *testing.T) {
+		service := setup(&PublicDashboard{Uid: "pubdash", IsEnabled: true})
+
+		res, err := service.GetPrerenderedQueryDataResponse(context.Background(), 1, "abc123")
+		require.NoError(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("returns nil for scoped access tokens", func(t *testing.T) {
+		service := setup(&PublicDashboard{Uid: "pubdash", IsEnabled: true, PrerenderEnabled: true, Scope: &ScopedAccessToken{Uid: "scoped"}})
+
+		res, err := service.GetPrerenderedQueryDataResponse(context.Background(), 1, "abc123")
+		require.NoError(t, err)
+		assert.Nil(t, res)
+	})
+
+}
+
+func TestRegisterJobs(t *testing.T) {
+	jobNames := func(scheduler *jobstest.FakeScheduler) []string {
+		names := make([]string, 0, len(scheduler.Jobs))
+		for _, job := range scheduler.Jobs {
+			names = append(names, job.Name)
+		}
+		return names
+	}
+
+	t.Run("pre-renders on every instance at the configured interval", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.PublicDashboards.PrerenderInterval = time.Minute
+		scheduler := jobstest.NewFakeScheduler()
+		service := &PublicDashboardServiceImpl{cfg: cfg}
+		service.registerJobs(scheduler)
+
+		require.Equal(t, []string{"public dashboards pre-rendering", "public dashboards scoped access tokens expiry"}, jobNames(scheduler))
+		assert.Equal(t, time.Minute, scheduler.Jobs[0].Interval)
+		assert.True(t, scheduler.Jobs[0].AllInstances)
+		assert.False(t, scheduler.Jobs[1].AllInstances)
+	})
+
+	t.Run("prunes the history with a retention", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.PublicDashboards.HistoryRetention = 24 * time.Hour
+		scheduler := jobstest.NewFakeScheduler()
+		fakeStore := &FakePublicDashboardStore{}
+		fakeStore.On("DeletePublicDashboardHistoryBefore", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+			return time.Since(before) >= 24*time.Hour
+		})).Return(int64(2), nil)
+		service := &PublicDashboardServiceImpl{cfg: cfg, store: fakeStore, log: log.New("test.logger")}
+		service.registerJobs(scheduler)
+
+		require.Len(t, scheduler.Jobs, 3)
+		require.Equal(t, "public dashboards history pruning", scheduler.Jobs[2].Name)
+		require.NoError(t, scheduler.Jobs[2].Run(context.Background()))
+		fakeStore.AssertExpectations(t)
+	})
+
+	t.Run("disables the expired scoped access tokens", func(t *testing.T) {
+		fakeStore := &FakePublicDashboardStore{}
+		fakeStore.On("DisableExpiredScopedAccessTokens", mock.Anything, mock.AnythingOfType("time.Time")).Return(int64(1), nil)
+		service := &PublicDashboardServiceImpl{store: fakeStore, log: log.New("test.logger")}
+
+		require.NoError(t, service.disableExpiredScopedAccessTokens(context.Background()))
+		fakeStore.AssertExpectations(t)
+	})
+}
+
+func TestGetPublicDashboardCache(t *testing.T) {
+	newDashboard := func(version int) *models.Dashboard {
+		dashData := simplejson.NewFromAny(map[string]interface{}{
+			"panels": []interface{}{
+				map[string]interface{}{"id": 1, "libraryPanel": map[string]interface{}{"uid": "lib1"}},
+			},
+		})
+		return &models.Dashboard{Id: 1, Uid: "mydashboard", OrgId: 1, Version: version, Data: dashData}
+	}
+
+	setup := func() (*PublicDashboardServiceImpl, *FakePublicDashboardStore, *fakeLibraryPanelService) {
+		fakeStore := &FakePublicDashboardStore{}
+		libraryPanels := &fakeLibraryPanelService{
+			panels: map[string]map[string]interface{}{
+				"lib1": {"type": "stat", "targets": []interface{}{map[string]interface{}{"refId": "A"}}},
+			},
+		}
+		service := &PublicDashboardServiceImpl{
+			log:            log.New("test.logger"),
+			store:          fakeStore,
+			libraryPanels:  libraryPanels,
+			dashboardCache: localcache.New(dashboardCacheTTL, dashboardCacheCleanupInterval),
+		}
+		return service, fakeStore, libraryPanels
+	}
+
+	pubdash := &PublicDashboard{Uid: "pubdash1", AccessToken: "abcdToken", IsEnabled: true}
+
+	t.Run("processes the dashboard once per version", func(t *testing.T) {
+		service, fakeStore, libraryPanels := setup()
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").Return(pubdash, newDashboard(1), nil).Twice()
+
+		_, first, err := service.GetPublicDashboard(context.Background(), "abcdToken")
+		require.NoError(t, err)
+		_, second, err := service.GetPublicDashboard(context.Background(), "abcdToken")
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, libraryPanels.loads)
+		assert.Equal(t, "stat", second.Data.Get("panels").GetIndex(0).Get("type").MustString())
+		firstJSON, err := first.Data.Encode()
+		require.NoError(t, err)
+		secondJSON, err := second.Data.Encode()
+		require.NoError(t, err)
+		assert.JSONEq(t, string(firstJSON), string(secondJSON))
+
+		// saving the dashboard bumps its version
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").Return(pubdash, newDashboard(2), nil).Once()
+
+		_, third, err := service.GetPublicDashboard(context.Background(), "abcdToken")
+		require.NoError(t, err)
+		assert.Equal(t, 2, libraryPanels.loads)
+		assert.Equal(t, 2, third.Version)
+	})
+
+	t.Run("requests get their own copy of the cached dashboard", func(t *testing.T) {
+		service, fakeStore, _ := setup()
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").Return(pubdash, newDashboard(1), nil)
+
+		_, first, err := service.GetPublicDashboard(context.Background(), "abcdToken")
+		require.NoError(t, err)
+		first.Data.Get("panels").GetIndex(0).Set("type", "modified")
+
+		_, second, err := service.GetPublicDashboard(context.Background(), "abcdToken")
+		require.NoError(t, err)
+		assert.Equal(t, "stat", second.Data.Get("panels").GetIndex(0).Get("type").MustString())
+	})
+
+	t.Run("caches scoped access tokens separately", func(t *testing.T) {
+		service, fakeStore, _ := setup()
+		scopedPubdash := *pubdash
+		scopedPubdash.AccessToken = "scopedToken"
+		scopedPubdash.Scope = &ScopedAccessToken{Uid: "scoped1", PanelIds: PanelIds{42}}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").Return(pubdash, newDashboard(1), nil)
+		fakeStore.On("GetPublicDashboard", mock.Anything, "scopedToken").Return(&scopedPubdash, newDashboard(1), nil)
+
+		_, dash, err := service.GetPublicDashboard(context.Background(), "abcdToken")
+		require.NoError(t, err)
+		assert.Len(t, dash.Data.Get("panels").MustArray(), 1)
+
+		_, scopedDash, err := service.GetPublicDashboard(context.Background(), "scopedToken")
+		require.NoError(t, err)
+		assert.Empty(t, scopedDash.Data.Get("panels").MustArray())
+	})
+
+	t.Run("serves the fixed time window and reprocesses the dashboard when the public dashboard is saved", func(t *testing.T) {
+		service, fakeStore, _ := setup()
+		fixedPubdash := *pubdash
+		fixedPubdash.TimeSettings = &TimeSettings{Mode: TimeModeFixed, From: "now-7d", To: "now"}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").Return(pubdash, newDashboard(1), nil).Once()
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").Return(&fixedPubdash, newDashboard(1), nil).Once()
+
+		_, dash, err := service.GetPublicDashboard(context.Background(), "abcdToken")
+		require.NoError(t, err)
+		_, hasTime := dash.Data.CheckGet("time")
+		assert.False(t, hasTime)
+
+		fixedPubdash.UpdatedAt = time.Now()
+		_, dash, err = service.GetPublicDashboard(context.Background(), "abcdToken")
+		require.NoError(t, err)
+		assert.Equal(t, "now-7d", dash.Data.GetPath("time", "from").MustString())
+		assert.Equal(t, "now", dash.Data.GetPath("time", "to").MustString())
+	})
+}
+
 func TestSavePublicDashboard(t *testing.T) {
 	t.Run("Saving public dashboard", func(t *testing.T) {
 		sqlStore := sqlstore.InitTestDB(t)
@@ -122,211 +662,1187 @@ func TestSavePublicDashboard(t *testing.T) {
 		publicdashboardStore := database.ProvideStore(sqlStore)
 		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{})
 
-		service := &PublicDashboardServiceImpl{
-			log:   log.New("test.logger"),
-			store: publicdashboardStore,
-		}
+		service := &PublicDashboardServiceImpl{
+			log:          log.New("test.logger"),
+			store:        publicdashboardStore,
+			quotaService: quotatest.NewQuotaServiceFake(),
+			bus:          newTestBus(t),
+		}
+
+		dto := &SavePublicDashboardConfigDTO{
+			DashboardUid: dashboard.Uid,
+			OrgId:        dashboard.OrgId,
+			UserId:       7,
+			PublicDashboard: &PublicDashboard{
+				IsEnabled:    true,
+				DashboardUid: "NOTTHESAME",
+				OrgId:        9999999,
+				TimeSettings: timeSettings,
+			},
+		}
+
+		_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+
+		pubdash, err := service.GetPublicDashboardConfig(context.Background(), dashboard.OrgId, dashboard.Uid)
+		require.NoError(t, err)
+
+		// DashboardUid/OrgId/CreatedBy set by the command, not parameters
+		assert.Equal(t, dashboard.Uid, pubdash.DashboardUid)
+		assert.Equal(t, dashboard.OrgId, pubdash.OrgId)
+		assert.Equal(t, dto.UserId, pubdash.CreatedBy)
+		// IsEnabled set by parameters
+		assert.Equal(t, dto.PublicDashboard.IsEnabled, pubdash.IsEnabled)
+		// CreatedAt set to non-zero time
+		assert.NotEqual(t, &time.Time{}, pubdash.CreatedAt)
+		// Time settings set by db
+		assert.Equal(t, timeSettings, pubdash.TimeSettings)
+		// accessToken is valid uuid
+		_, err = uuid.Parse(pubdash.AccessToken)
+		require.NoError(t, err, "expected a valid UUID, got %s", pubdash.AccessToken)
+
+		// creation is recorded in the history
+		history, err := service.GetPublicDashboardHistory(context.Background(), dashboard.OrgId, dashboard.Uid)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, HistoryActionCreated, history[0].Action)
+		assert.Equal(t, dto.UserId, history[0].CreatedBy)
+		assert.Equal(t, pubdash.Uid, history[0].PublicDashboardUid)
+	})
+
+	t.Run("Validate pubdash has default time setting value", func(t *testing.T) {
+		sqlStore := sqlstore.InitTestDB(t)
+		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+		publicdashboardStore := database.ProvideStore(sqlStore)
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{})
+
+		service := &PublicDashboardServiceImpl{
+			log:          log.New("test.logger"),
+			store:        publicdashboardStore,
+			quotaService: quotatest.NewQuotaServiceFake(),
+			bus:          newTestBus(t),
+		}
+
+		dto := &SavePublicDashboardConfigDTO{
+			DashboardUid: dashboard.Uid,
+			OrgId:        dashboard.OrgId,
+			UserId:       7,
+			PublicDashboard: &PublicDashboard{
+				IsEnabled:    true,
+				DashboardUid: "NOTTHESAME",
+				OrgId:        9999999,
+			},
+		}
+
+		_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+
+		pubdash, err := service.GetPublicDashboardConfig(context.Background(), dashboard.OrgId, dashboard.Uid)
+		require.NoError(t, err)
+		assert.Equal(t, defaultPubdashTimeSettings, pubdash.TimeSettings)
+	})
+
+	t.Run("Validate pubdash with invalid time settings returns error", func(t *testing.T) {
+		sqlStore := sqlstore.InitTestDB(t)
+		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+		publicdashboardStore := database.ProvideStore(sqlStore)
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{})
+
+		service := &PublicDashboardServiceImpl{
+			log:          log.New("test.logger"),
+			store:        publicdashboardStore,
+			quotaService: quotatest.NewQuotaServiceFake(),
+			bus:          newTestBus(t),
+		}
+
+		dto := &SavePublicDashboardConfigDTO{
+			DashboardUid: dashboard.Uid,
+			OrgId:        dashboard.OrgId,
+			UserId:       7,
+			PublicDashboard: &PublicDashboard{
+				IsEnabled:    true,
+				TimeSettings: &TimeSettings{Mode: TimeModeFixed, From: "now", To: "now-12h"},
+			},
+		}
+
+		_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.ErrorIs(t, err, ErrPublicDashboardInvalidTimeSettings)
+	})
+
+	t.Run("Validate pubdash whose dashboard has template variables returns error", func(t *testing.T) {
+		sqlStore := sqlstore.InitTestDB(t)
+		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+		publicdashboardStore := database.ProvideStore(sqlStore)
+		templateVars := make([]map[string]interface{}, 1)
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, templateVars)
+
+		service := &PublicDashboardServiceImpl{
+			log:          log.New("test.logger"),
+			store:        publicdashboardStore,
+			quotaService: quotatest.NewQuotaServiceFake(),
+			bus:          newTestBus(t),
+		}
+
+		dto := &SavePublicDashboardConfigDTO{
+			DashboardUid: dashboard.Uid,
+			OrgId:        dashboard.OrgId,
+			UserId:       7,
+			PublicDashboard: &PublicDashboard{
+				IsEnabled:    true,
+				DashboardUid: "NOTTHESAME",
+				OrgId:        9999999,
+			},
+		}
+
+		_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.Error(t, err)
+	})
+
+	t.Run("Saving public dashboard pinned to a dashboard version", func(t *testing.T) {
+		sqlStore := sqlstore.InitTestDB(t)
+		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+		publicdashboardStore := database.ProvideStore(sqlStore)
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{})
+
+		service := &PublicDashboardServiceImpl{
+			log:               log.New("test.logger"),
+			store:             publicdashboardStore,
+			dashboardVersions: dashverimpl.ProvideService(sqlStore),
+			quotaService:      quotatest.NewQuotaServiceFake(),
+			bus:               newTestBus(t),
+		}
+
+		dto := &SavePublicDashboardConfigDTO{
+			DashboardUid: dashboard.Uid,
+			OrgId:        dashboard.OrgId,
+			UserId:       7,
+			PublicDashboard: &PublicDashboard{
+				IsEnabled:        true,
+				DashboardVersion: dashboard.Version,
+			},
+		}
+
+		pubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+		assert.Equal(t, dashboard.Version, pubdash.DashboardVersion)
+
+		dto.PublicDashboard.Uid = pubdash.Uid
+		dto.PublicDashboard.DashboardVersion = dashboard.Version + 10
+		_, err = service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.ErrorIs(t, err, ErrPublicDashboardVersionNotFound)
+
+		dto.PublicDashboard.DashboardVersion = -1
+		_, err = service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.ErrorIs(t, err, ErrPublicDashboardBadRequest)
+	})
+}
+
+func TestUpdatePublicDashboard(t *testing.T) {
+	t.Run("Updating public dashboard", func(t *testing.T) {
+		sqlStore := sqlstore.InitTestDB(t)
+		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+		publicdashboardStore := database.ProvideStore(sqlStore)
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{})
+
+		service := &PublicDashboardServiceImpl{
+			log:          log.New("test.logger"),
+			store:        publicdashboardStore,
+			quotaService: quotatest.NewQuotaServiceFake(),
+			bus:          newTestBus(t),
+		}
+
+		dto := &SavePublicDashboardConfigDTO{
+			DashboardUid: dashboard.Uid,
+			OrgId:        dashboard.OrgId,
+			UserId:       7,
+			PublicDashboard: &PublicDashboard{
+				IsEnabled:    true,
+				TimeSettings: timeSettings,
+			},
+		}
+
+		savedPubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+
+		// attempt to overwrite settings
+		dto = &SavePublicDashboardConfigDTO{
+			DashboardUid: dashboard.Uid,
+			OrgId:        dashboard.OrgId,
+			UserId:       8,
+			PublicDashboard: &PublicDashboard{
+				Uid:          savedPubdash.Uid,
+				OrgId:        9,
+				DashboardUid: "abc1234",
+				CreatedBy:    9,
+				CreatedAt:    time.Time{},
+
+				IsEnabled:    true,
+				TimeSettings: timeSettings,
+				AccessToken:  "NOTAREALUUID",
+			},
+		}
+
+		// Since the dto.PublicDashboard has a uid, this will call
+		// service.updatePublicDashboardConfig
+		updatedPubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+
+		// don't get updated
+		assert.Equal(t, savedPubdash.DashboardUid, updatedPubdash.DashboardUid)
+		assert.Equal(t, savedPubdash.OrgId, updatedPubdash.OrgId)
+		assert.Equal(t, savedPubdash.CreatedAt, updatedPubdash.CreatedAt)
+		assert.Equal(t, savedPubdash.CreatedBy, updatedPubdash.CreatedBy)
+		assert.Equal(t, savedPubdash.AccessToken, updatedPubdash.AccessToken)
+
+		// gets updated
+		assert.Equal(t, dto.PublicDashboard.IsEnabled, updatedPubdash.IsEnabled)
+		assert.Equal(t, dto.PublicDashboard.TimeSettings, updatedPubdash.TimeSettings)
+		assert.Equal(t, dto.UserId, updatedPubdash.UpdatedBy)
+		assert.NotEqual(t, &time.Time{}, updatedPubdash.UpdatedAt)
+
+		// both changes are recorded in the history, most recent first
+		history, err := service.GetPublicDashboardHistory(context.Background(), dashboard.OrgId, dashboard.Uid)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, HistoryActionUpdated, history[0].Action)
+		assert.Equal(t, dto.UserId, history[0].CreatedBy)
+		assert.Equal(t, HistoryActionCreated, history[1].Action)
+	})
+
+	t.Run("Updating set empty time settings", func(t *testing.T) {
+		sqlStore := sqlstore.InitTestDB(t)
+		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+		publicdashboardStore := database.ProvideStore(sqlStore)
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{})
+
+		service := &PublicDashboardServiceImpl{
+			log:          log.New("test.logger"),
+			store:        publicdashboardStore,
+			quotaService: quotatest.NewQuotaServiceFake(),
+			bus:          newTestBus(t),
+		}
+
+		dto := &SavePublicDashboardConfigDTO{
+			DashboardUid: dashboard.Uid,
+			OrgId:        dashboard.OrgId,
+			UserId:       7,
+			PublicDashboard: &PublicDashboard{
+				IsEnabled:    true,
+				TimeSettings: timeSettings,
+			},
+		}
+
+		// Since the dto.PublicDashboard has a uid, this will call
+		// service.updatePublicDashboardConfig
+		savedPubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+
+		// attempt to overwrite settings
+		dto = &SavePublicDashboardConfigDTO{
+			DashboardUid: dashboard.Uid,
+			OrgId:        dashboard.OrgId,
+			UserId:       8,
+			PublicDashboard: &PublicDashboard{
+				Uid:          savedPubdash.Uid,
+				OrgId:        9,
+				DashboardUid: "abc1234",
+				CreatedBy:    9,
+				CreatedAt:    time.Time{},
+
+				IsEnabled:   true,
+				AccessToken: "NOTAREALUUID",
+			},
+		}
+
+		updatedPubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+
+		assert.Equal(t, &TimeSettings{}, updatedPubdash.TimeSettings)
+	})
+}
+
+func TestClonePublicDashboardConfig(t *testing.T) {
+	setup := func(t *testing.T) (*PublicDashboardServiceImpl, *models.Dashboard, *models.Dashboard) {
+		sqlStore := sqlstore.InitTestDB(t)
+		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+		original := insertTestDashboard(t, dashboardStore, "original", 1, 0, false, []map[string]interface{}{})
+		copied := insertTestDashboard(t, dashboardStore, "copy", 1, 0, false, []map[string]interface{}{})
+
+		service := &PublicDashboardServiceImpl{
+			log:          log.New("test.logger"),
+			store:        database.ProvideStore(sqlStore),
+			quotaService: quotatest.NewQuotaServiceFake(),
+			bus:          newTestBus(t),
+		}
+
+		return service, original, copied
+	}
+
+	t.Run("clones the public dashboard with a fresh access token", func(t *testing.T) {
+		service, original, copied := setup(t)
+
+		source, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, &SavePublicDashboardConfigDTO{
+			DashboardUid: original.Uid,
+			OrgId:        original.OrgId,
+			UserId:       7,
+			PublicDashboard: &PublicDashboard{
+				IsEnabled:        true,
+				TimeSettings:     timeSettings,
+				RequireChallenge: true,
+				TermsOfUse:       "Do not share",
+				MaskingRules:     MaskingRules{{FieldPattern: "^email$", Action: MaskActionRedact}},
+			},
+		})
+		require.NoError(t, err)
+
+		clone, err := service.ClonePublicDashboardConfig(context.Background(), SignedInUser, &ClonePublicDashboardConfigDTO{
+			SourceDashboardUid: original.Uid,
+			DashboardUid:       copied.Uid,
+			OrgId:              copied.OrgId,
+			UserId:             8,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, copied.Uid, clone.DashboardUid)
+		assert.NotEqual(t, source.Uid, clone.Uid)
+		assert.NotEqual(t, source.AccessToken, clone.AccessToken)
+		assert.Equal(t, int64(8), clone.CreatedBy)
+		assert.True(t, clone.IsEnabled)
+		assert.True(t, clone.RequireChallenge)
+		assert.Equal(t, source.TermsOfUse, clone.TermsOfUse)
+		assert.Equal(t, source.TimeSettings, clone.TimeSettings)
+		assert.Equal(t, source.MaskingRules, clone.MaskingRules)
+	})
+
+	t.Run("returns not found when the source dashboard isn't public", func(t *testing.T) {
+		service, original, copied := setup(t)
+
+		_, err := service.ClonePublicDashboardConfig(context.Background(), SignedInUser, &ClonePublicDashboardConfigDTO{
+			SourceDashboardUid: original.Uid,
+			DashboardUid:       copied.Uid,
+			OrgId:              copied.OrgId,
+		})
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+
+	t.Run("returns already exists when the dashboard is public", func(t *testing.T) {
+		service, original, copied := setup(t)
+
+		for _, dash := range []*models.Dashboard{original, copied} {
+			_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, &SavePublicDashboardConfigDTO{
+				DashboardUid:    dash.Uid,
+				OrgId:           dash.OrgId,
+				PublicDashboard: &PublicDashboard{IsEnabled: true},
+			})
+			require.NoError(t, err)
+		}
+
+		_, err := service.ClonePublicDashboardConfig(context.Background(), SignedInUser, &ClonePublicDashboardConfigDTO{
+			SourceDashboardUid: original.Uid,
+			DashboardUid:       copied.Uid,
+			OrgId:              copied.OrgId,
+		})
+		require.ErrorIs(t, err, ErrPublicDashboardAlreadyExists)
+	})
+}
+
+func TestSavePublicDashboardQuota(t *testing.T) {
+	setup := func(t *testing.T) (*PublicDashboardServiceImpl, *fakeQuotaService, *SavePublicDashboardConfigDTO) {
+		sqlStore := sqlstore.InitTestDB(t)
+		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{})
+
+		quotaService := &fakeQuotaService{}
+		service := &PublicDashboardServiceImpl{
+			log:          log.New("test.logger"),
+			store:        database.ProvideStore(sqlStore),
+			quotaService: quotaService,
+			bus:          newTestBus(t),
+		}
+
+		dto := &SavePublicDashboardConfigDTO{
+			DashboardUid: dashboard.Uid,
+			OrgId:        dashboard.OrgId,
+			UserId:       7,
+			PublicDashboard: &PublicDashboard{
+				IsEnabled:    false,
+				TimeSettings: timeSettings,
+			},
+		}
+
+		return service, quotaService, dto
+	}
+
+	t.Run("returns quota error when enabling a new public dashboard", func(t *testing.T) {
+		service, quotaService, dto := setup(t)
+		quotaService.reached = true
+		dto.PublicDashboard.IsEnabled = true
+
+		_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.ErrorIs(t, err, ErrPublicDashboardQuotaReached)
+		assert.Equal(t, "dashboard_public", quotaService.target)
+		assert.Equal(t, dto.OrgId, quotaService.params.OrgID)
+	})
+
+	t.Run("saves a disabled public dashboard when quota is reached", func(t *testing.T) {
+		service, quotaService, dto := setup(t)
+		quotaService.reached = true
+
+		_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+		assert.Empty(t, quotaService.target)
+	})
+
+	t.Run("returns quota error when enabling an existing public dashboard", func(t *testing.T) {
+		service, quotaService, dto := setup(t)
+		pubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+
+		quotaService.reached = true
+		dto.PublicDashboard = &PublicDashboard{Uid: pubdash.Uid, IsEnabled: true}
+
+		_, err = service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.ErrorIs(t, err, ErrPublicDashboardQuotaReached)
+	})
+
+	t.Run("updates an enabled public dashboard when quota is reached", func(t *testing.T) {
+		service, quotaService, dto := setup(t)
+		dto.PublicDashboard.IsEnabled = true
+		pubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+
+		quotaService.reached = true
+		dto.PublicDashboard = &PublicDashboard{Uid: pubdash.Uid, IsEnabled: true, TimeSettings: timeSettings}
+
+		_, err = service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+	})
+}
+
+func TestSavePublicDashboardEvents(t *testing.T) {
+	setup := func(t *testing.T, webhookUrl string) (*PublicDashboardServiceImpl, bus.Bus, *notifications.NotificationServiceMock, *SavePublicDashboardConfigDTO) {
+		sqlStore := sqlstore.InitTestDB(t)
+		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{})
+
+		cfg := setting.NewCfg()
+		cfg.PublicDashboards.SharingWebhookUrl = webhookUrl
+		eventBus := newTestBus(t)
+		webhooks := notifications.MockNotificationService()
+		service := ProvideService(cfg, database.ProvideStore(sqlStore), nil, nil, nil, nil, quotatest.NewQuotaServiceFake(), eventBus, webhooks, nil, nil, nil, nil, jobstest.NewFakeScheduler())
+
+		dto := &SavePublicDashboardConfigDTO{
+			DashboardUid: dashboard.Uid,
+			OrgId:        dashboard.OrgId,
+			UserId:       7,
+			PublicDashboard: &PublicDashboard{
+				IsEnabled:    true,
+				TimeSettings: timeSettings,
+			},
+		}
+
+		return service, eventBus, webhooks, dto
+	}
+
+	t.Run("publishes events when the public dashboard is enabled and disabled", func(t *testing.T) {
+		service, eventBus, _, dto := setup(t, "")
+
+		var enabled []*events.PublicDashboardEnabled
+		var disabled []*events.PublicDashboardDisabled
+		eventBus.AddEventListener(func(ctx context.Context, e *events.PublicDashboardEnabled) error {
+			enabled = append(enabled, e)
+			return nil
+		})
+		eventBus.AddEventListener(func(ctx context.Context, e *events.PublicDashboardDisabled) error {
+			disabled = append(disabled, e)
+			return nil
+		})
+
+		pubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+		require.Len(t, enabled, 1)
+		assert.Equal(t, pubdash.Uid, enabled[0].UID)
+		assert.Equal(t, dto.DashboardUid, enabled[0].DashboardUID)
+		assert.Equal(t, dto.OrgId, enabled[0].OrgID)
+		assert.Equal(t, int64(7), enabled[0].UserID)
+		assert.Empty(t, disabled)
+
+		// saving without changing isEnabled publishes nothing
+		dto.PublicDashboard = &PublicDashboard{Uid: pubdash.Uid, IsEnabled: true, TimeSettings: timeSettings}
+		_, err = service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+		assert.Len(t, enabled, 1)
+		assert.Empty(t, disabled)
+
+		dto.PublicDashboard = &PublicDashboard{Uid: pubdash.Uid, IsEnabled: false, TimeSettings: timeSettings}
+		_, err = service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+		assert.Len(t, enabled, 1)
+		require.Len(t, disabled, 1)
+		assert.Equal(t, pubdash.Uid, disabled[0].UID)
+	})
+
+	t.Run("sends the sharing webhook without the access token", func(t *testing.T) {
+		service, _, webhooks, dto := setup(t, "http://localhost/hook")
+
+		pubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost/hook", webhooks.Webhook.Url)
+		assert.Equal(t, "POST", webhooks.Webhook.HttpMethod)
+		assert.NotContains(t, webhooks.Webhook.Body, pubdash.AccessToken)
+
+		payload := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(webhooks.Webhook.Body), &payload))
+		assert.Equal(t, EventPublicDashboardEnabled, payload["event"])
+		assert.Equal(t, pubdash.Uid, payload["uid"])
+		assert.Equal(t, dto.DashboardUid, payload["dashboard_uid"])
+	})
+
+	t.Run("does not send the sharing webhook when not configured", func(t *testing.T) {
+		service, _, webhooks, dto := setup(t, "")
+
+		_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+		assert.Empty(t, webhooks.Webhook.Url)
+	})
+
+	t.Run("saves the public dashboard when the sharing webhook fails", func(t *testing.T) {
+		service, _, webhooks, dto := setup(t, "http://localhost/hook")
+		webhooks.ShouldError = errors.New("webhook failed")
+
+		_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+	})
+}
+
+func TestBulkDisablePublicDashboards(t *testing.T) {
+	setup := func(t *testing.T) (*PublicDashboardServiceImpl, *models.Dashboard, *models.Dashboard, *models.Dashboard) {
+		sqlStore := sqlstore.InitTestDB(t)
+		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+		folder := insertTestDashboard(t, dashboardStore, "payments", 1, 0, true, []map[string]interface{}{})
+		inFolder := insertTestDashboard(t, dashboardStore, "checkout", 1, folder.Id, false, []map[string]interface{}{})
+		tagged := insertTestDashboard(t, dashboardStore, "refunds", 1, 0, false, []map[string]interface{}{}, "payments")
+
+		service := &PublicDashboardServiceImpl{
+			log:          log.New("test.logger"),
+			store:        database.ProvideStore(sqlStore),
+			quotaService: quotatest.NewQuotaServiceFake(),
+			bus:          newTestBus(t),
+		}
+
+		for _, dash := range []*models.Dashboard{inFolder, tagged} {
+			_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, &SavePublicDashboardConfigDTO{
+				DashboardUid:    dash.Uid,
+				OrgId:           dash.OrgId,
+				UserId:          7,
+				PublicDashboard: &PublicDashboard{IsEnabled: true, TimeSettings: timeSettings},
+			})
+			require.NoError(t, err)
+		}
+
+		return service, folder, inFolder, tagged
+	}
+
+	t.Run("disables the public dashboards of a folder", func(t *testing.T) {
+		service, folder, inFolder, tagged := setup(t)
+
+		var disabled []*events.PublicDashboardDisabled
+		service.bus.AddEventListener(func(ctx context.Context, e *events.PublicDashboardDisabled) error {
+			disabled = append(disabled, e)
+			return nil
+		})
+
+		result, err := service.BulkDisablePublicDashboards(context.Background(), SignedInUser, &BulkDisablePublicDashboardsDTO{
+			FolderUid: folder.Uid,
+			OrgId:     1,
+			UserId:    8,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{inFolder.Uid}, result.DashboardUids)
+
+		pubdash, err := service.GetPublicDashboardConfig(context.Background(), 1, inFolder.Uid)
+		require.NoError(t, err)
+		assert.False(t, pubdash.IsEnabled)
+		assert.Equal(t, int64(8), pubdash.UpdatedBy)
+		require.Len(t, disabled, 1)
+		assert.Equal(t, pubdash.Uid, disabled[0].UID)
+
+		history, err := service.GetPublicDashboardHistory(context.Background(), 1, inFolder.Uid)
+		require.NoError(t, err)
+		assert.Equal(t, HistoryActionDisabled, history[0].Action)
+
+		other, err := service.GetPublicDashboardConfig(context.Background(), 1, tagged.Uid)
+		require.NoError(t, err)
+		assert.True(t, other.IsEnabled)
+
+		// already disabled public dashboards are left alone
+		result, err = service.BulkDisablePublicDashboards(context.Background(), SignedInUser, &BulkDisablePublicDashboardsDTO{
+			FolderUid: folder.Uid,
+			OrgId:     1,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, result.DashboardUids)
+		assert.Len(t, disabled, 1)
+	})
+
+	t.Run("deletes the public dashboards with a tag", func(t *testing.T) {
+		service, _, inFolder, tagged := setup(t)
+
+		result, err := service.BulkDisablePublicDashboards(context.Background(), SignedInUser, &BulkDisablePublicDashboardsDTO{
+			Tag:    "payments",
+			Delete: true,
+			OrgId:  1,
+			UserId: 8,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{tagged.Uid}, result.DashboardUids)
+
+		pubdash, err := service.GetPublicDashboardConfig(context.Background(), 1, tagged.Uid)
+		require.NoError(t, err)
+		assert.Empty(t, pubdash.Uid)
+
+		history, err := service.GetPublicDashboardHistory(context.Background(), 1, tagged.Uid)
+		require.NoError(t, err)
+		assert.Equal(t, HistoryActionDeleted, history[0].Action)
+		assert.False(t, history[0].IsEnabled)
+
+		other, err := service.GetPublicDashboardConfig(context.Background(), 1, inFolder.Uid)
+		require.NoError(t, err)
+		assert.True(t, other.IsEnabled)
+	})
+
+	t.Run("requires a folder or a tag", func(t *testing.T) {
+		service, _, _, _ := setup(t)
+
+		_, err := service.BulkDisablePublicDashboards(context.Background(), SignedInUser, &BulkDisablePublicDashboardsDTO{OrgId: 1})
+		assert.ErrorIs(t, err, ErrPublicDashboardBulkSelectorRequired)
+	})
+}
+
+func TestIndexingAllowed(t *testing.T) {
+	allow := true
+	dash := &models.Dashboard{Uid: "mydashboard", OrgId: 1}
+
+	testCases := []struct {
+		name            string
+		pubdash         *PublicDashboard
+		instanceDefault bool
+		allowed         bool
+	}{
+		{name: "uses the instance default", pubdash: &PublicDashboard{IsEnabled: true}, instanceDefault: true, allowed: true},
+		{name: "uses the public dashboard setting", pubdash: &PublicDashboard{IsEnabled: true, AllowIndexing: &allow}, instanceDefault: false, allowed: true},
+		{name: "denies disabled public dashboards", pubdash: &PublicDashboard{IsEnabled: false, AllowIndexing: &allow}, instanceDefault: true, allowed: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			fakeStore := FakePublicDashboardStore{}
+			fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").Return(test.pubdash, dash, nil)
+
+			cfg := setting.NewCfg()
+			cfg.PublicDashboards.AllowIndexing = test.instanceDefault
+			service := &PublicDashboardServiceImpl{
+				log:   log.New("test.logger"),
+				cfg:   cfg,
+				store: &fakeStore,
+			}
+
+			allowed, err := service.IndexingAllowed(context.Background(), "abcdToken")
+			require.NoError(t, err)
+			assert.Equal(t, test.allowed, allowed)
+		})
+	}
+}
+
+func TestChallengeRequired(t *testing.T) {
+	dash := &models.Dashboard{Uid: "mydashboard", OrgId: 1}
+
+	t.Run("returns the public dashboard setting", func(t *testing.T) {
+		fakeStore := FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").
+			Return(&PublicDashboard{IsEnabled: true, RequireChallenge: true}, dash, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: &fakeStore}
+
+		required, err := service.ChallengeRequired(context.Background(), "abcdToken")
+		require.NoError(t, err)
+		assert.True(t, required)
+	})
+
+	t.Run("returns not found for disabled public dashboards", func(t *testing.T) {
+		fakeStore := FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").
+			Return(&PublicDashboard{IsEnabled: false, RequireChallenge: true}, dash, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: &fakeStore}
+
+		_, err := service.ChallengeRequired(context.Background(), "abcdToken")
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+}
+
+func TestCountryAllowed(t *testing.T) {
+	t.Run("applies the country restrictions of the org", func(t *testing.T) {
+		fakeStore := FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboardOrgId", mock.Anything, "abcdToken").Return(int64(1), nil)
+		fakeStore.On("GetOrgSettings", mock.Anything, int64(1)).
+			Return(&OrgSettings{OrgId: 1, CountryRestrictions: &CountryRestrictions{Denied: []string{"KP"}}}, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: &fakeStore}
+
+		allowed, err := service.CountryAllowed(context.Background(), "abcdToken", "FR")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+
+		allowed, err = service.CountryAllowed(context.Background(), "abcdToken", "KP")
+		require.NoError(t, err)
+		assert.False(t, allowed)
+	})
+
+	t.Run("returns not found for unknown access tokens", func(t *testing.T) {
+		fakeStore := FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboardOrgId", mock.Anything, "abcdToken").Return(int64(0), nil)
+		fakeStore.On("GetPublicPlaylist", mock.Anything, "abcdToken").Return(nil, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: &fakeStore}
+
+		_, err := service.CountryAllowed(context.Background(), "abcdToken", "FR")
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+}
+
+func TestExternalViewerRequired(t *testing.T) {
+	dash := &models.Dashboard{Uid: "mydashboard", OrgId: 1}
+
+	t.Run("returns true for the external SSO share mode", func(t *testing.T) {
+		fakeStore := FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").
+			Return(&PublicDashboard{IsEnabled: true, ShareMode: ShareModeExternalSSO}, dash, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: &fakeStore}
+
+		required, err := service.ExternalViewerRequired(context.Background(), "abcdToken")
+		require.NoError(t, err)
+		assert.True(t, required)
+	})
+
+	t.Run("returns false for the default share mode", func(t *testing.T) {
+		fakeStore := FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").
+			Return(&PublicDashboard{IsEnabled: true}, dash, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: &fakeStore}
+
+		required, err := service.ExternalViewerRequired(context.Background(), "abcdToken")
+		require.NoError(t, err)
+		assert.False(t, required)
+	})
+
+	t.Run("returns not found for disabled public dashboards", func(t *testing.T) {
+		fakeStore := FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").
+			Return(&PublicDashboard{IsEnabled: false, ShareMode: ShareModeExternalSSO}, dash, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: &fakeStore}
+
+		_, err := service.ExternalViewerRequired(context.Background(), "abcdToken")
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+}
+
+func TestGetTermsOfUse(t *testing.T) {
+	dash := &models.Dashboard{Uid: "mydashboard", OrgId: 1}
+
+	t.Run("returns the terms of use", func(t *testing.T) {
+		fakeStore := FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").
+			Return(&PublicDashboard{IsEnabled: true, TermsOfUse: "Do not share"}, dash, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: &fakeStore}
+
+		terms, err := service.GetTermsOfUse(context.Background(), "abcdToken")
+		require.NoError(t, err)
+		assert.Equal(t, "Do not share", terms)
+	})
+
+	t.Run("returns not found for disabled public dashboards", func(t *testing.T) {
+		fakeStore := FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").
+			Return(&PublicDashboard{IsEnabled: false, TermsOfUse: "Do not share"}, dash, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: &fakeStore}
+
+		_, err := service.GetTermsOfUse(context.Background(), "abcdToken")
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+}
+
+func TestGetPanelAlertStates(t *testing.T) {
+	dash := &models.Dashboard{Id: 1, Uid: "mydashboard", OrgId: 1, Data: simplejson.NewFromAny(map[string]interface{}{
+		"panels": []interface{}{map[string]interface{}{"id": 2}},
+	})}
+
+	setup := func(alertStateEnabled bool, unifiedAlerting bool) (*PublicDashboardServiceImpl, *FakePublicDashboardStore) {
+		cfg := setting.NewCfg()
+		cfg.PublicDashboards.AlertStateEnabled = alertStateEnabled
+		cfg.UnifiedAlerting.Enabled = &unifiedAlerting
+
+		fakeStore := &FakePublicDashboardStore{}
+		fakeStore.On("GetPublicDashboard", mock.Anything, "abcdToken").
+			Return(&PublicDashboard{IsEnabled: true}, dash, nil).Maybe()
+
+		return &PublicDashboardServiceImpl{
+			log:           log.New("test.logger"),
+			cfg:           cfg,
+			store:         fakeStore,
+			libraryPanels: &fakeLibraryPanelService{},
+		}, fakeStore
+	}
+
+	t.Run("returns an error when alert state is disabled", func(t *testing.T) {
+		service, _ := setup(false, true)
+
+		_, err := service.GetPanelAlertStates(context.Background(), "abcdToken", 2)
+		require.ErrorIs(t, err, ErrPublicDashboardAlertStateDisabled)
+	})
+
+	t.Run("returns panel not found for unknown panels", func(t *testing.T) {
+		service, _ := setup(true, true)
+
+		_, err := service.GetPanelAlertStates(context.Background(), "abcdToken", 3)
+		require.ErrorIs(t, err, ErrPublicDashboardPanelNotFound)
+	})
+
+	t.Run("counts the unified alerting instances by state", func(t *testing.T) {
+		service, fakeStore := setup(true, true)
+		fakeStore.On("GetUnifiedPanelAlertStates", mock.Anything, dash, int64(2)).Return([]AlertStateCount{
+			{State: "Alerting", Count: 2},
+			{State: "Pending", Count: 1},
+			{State: "Normal", Count: 4},
+			{State: "NoData", Count: 1},
+			{State: "Error", Count: 3},
+		}, nil)
+
+		states, err := service.GetPanelAlertStates(context.Background(), "abcdToken", 2)
+		require.NoError(t, err)
+		assert.Equal(t, &PanelAlertStates{Firing: 2, Pending: 1, Ok: 4, NoData: 1, Error: 3}, states)
+	})
+
+	t.Run("counts the legacy alerts by state", func(t *testing.T) {
+		service, fakeStore := setup(true, false)
+		fakeStore.On("GetLegacyPanelAlertStates", mock.Anything, dash, int64(2)).Return([]AlertStateCount{
+			{State: "alerting", Count: 1},
+			{State: "ok", Count: 2},
+			{State: "paused", Count: 5},
+		}, nil)
+
+		states, err := service.GetPanelAlertStates(context.Background(), "abcdToken", 2)
+		require.NoError(t, err)
+		assert.Equal(t, &PanelAlertStates{Firing: 1, Ok: 2}, states)
+	})
+}
+
+func TestPublicPlaylist(t *testing.T) {
+	setup := func(t *testing.T) (*PublicDashboardServiceImpl, *playlist.Playlist, []*models.Dashboard) {
+		sqlStore := sqlstore.InitTestDB(t)
+		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
+		playlistService := playlistimpl.ProvideService(sqlStore, sqlStore.Cfg)
+		service := &PublicDashboardServiceImpl{
+			log:             log.New("test.logger"),
+			store:           database.ProvideStore(sqlStore),
+			quotaService:    quotatest.NewQuotaServiceFake(),
+			bus:             newTestBus(t),
+			playlistService: playlistService,
+		}
+
+		dashboards := []*models.Dashboard{
+			insertTestDashboard(t, dashboardStore, "First", 1, 0, false, []map[string]interface{}{}),
+			insertTestDashboard(t, dashboardStore, "Second", 1, 0, false, []map[string]interface{}{}, "ops"),
+			insertTestDashboard(t, dashboardStore, "Private", 1, 0, false, []map[string]interface{}{}, "ops"),
+		}
+		for _, dash := range dashboards[:2] {
+			_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, &SavePublicDashboardConfigDTO{
+				DashboardUid:    dash.Uid,
+				OrgId:           dash.OrgId,
+				PublicDashboard: &PublicDashboard{IsEnabled: true},
+			})
+			require.NoError(t, err)
+		}
+
+		p, err := playlistService.Create(context.Background(), &playlist.CreatePlaylistCommand{
+			Name:     "NOC",
+			Interval: "5m",
+			OrgId:    1,
+			Items: []playlist.PlaylistItem{
+				{Type: "dashboard_by_uid", Value: dashboards[0].Uid},
+				{Type: "dashboard_by_tag", Value: "ops"},
+				{Type: "dashboard_by_uid", Value: dashboards[1].Uid},
+			},
+		})
+		require.NoError(t, err)
+
+		return service, p, dashboards
+	}
+
+	t.Run("serves the public dashboards of the playlist", func(t *testing.T) {
+		service, p, dashboards := setup(t)
+
+		pubplaylist, err := service.SavePublicPlaylistConfig(context.Background(), SignedInUser, &SavePublicPlaylistConfigDTO{
+			PlaylistUid:    p.UID,
+			OrgId:          1,
+			UserId:         7,
+			PublicPlaylist: &PublicPlaylist{IsEnabled: true},
+		})
+		require.NoError(t, err)
+		assert.True(t, pubplaylist.IsEnabled)
+		assert.NotEmpty(t, pubplaylist.AccessToken)
+
+		dto, err := service.GetPublicPlaylist(context.Background(), pubplaylist.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "NOC", dto.Name)
+		assert.Equal(t, "5m", dto.Interval)
+		require.Len(t, dto.Items, 2)
+		assert.Equal(t, dashboards[0].Title, dto.Items[0].Title)
+		assert.Equal(t, dashboards[1].Title, dto.Items[1].Title)
+
+		pubdash, err := service.GetPublicDashboardConfig(context.Background(), 1, dashboards[0].Uid)
+		require.NoError(t, err)
+		assert.Equal(t, pubdash.AccessToken, dto.Items[0].AccessToken)
 
-		dto := &SavePublicDashboardConfigDTO{
-			DashboardUid: dashboard.Uid,
-			OrgId:        dashboard.OrgId,
-			UserId:       7,
-			PublicDashboard: &PublicDashboard{
-				IsEnabled:    true,
-				DashboardUid: "NOTTHESAME",
-				OrgId:        9999999,
-				TimeSettings: timeSettings,
-			},
-		}
+		orgId, err := service.GetPublicPlaylistOrgId(context.Background(), pubplaylist.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), orgId)
+	})
 
-		_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+	t.Run("returns not found for disabled public playlists", func(t *testing.T) {
+		service, p, _ := setup(t)
+
+		pubplaylist, err := service.SavePublicPlaylistConfig(context.Background(), SignedInUser, &SavePublicPlaylistConfigDTO{
+			PlaylistUid:    p.UID,
+			OrgId:          1,
+			PublicPlaylist: &PublicPlaylist{IsEnabled: true},
+		})
 		require.NoError(t, err)
 
-		pubdash, err := service.GetPublicDashboardConfig(context.Background(), dashboard.OrgId, dashboard.Uid)
+		updated, err := service.SavePublicPlaylistConfig(context.Background(), SignedInUser, &SavePublicPlaylistConfigDTO{
+			PlaylistUid:    p.UID,
+			OrgId:          1,
+			PublicPlaylist: &PublicPlaylist{IsEnabled: false},
+		})
 		require.NoError(t, err)
+		assert.Equal(t, pubplaylist.Uid, updated.Uid)
+		assert.Equal(t, pubplaylist.AccessToken, updated.AccessToken)
 
-		// DashboardUid/OrgId/CreatedBy set by the command, not parameters
-		assert.Equal(t, dashboard.Uid, pubdash.DashboardUid)
-		assert.Equal(t, dashboard.OrgId, pubdash.OrgId)
-		assert.Equal(t, dto.UserId, pubdash.CreatedBy)
-		// IsEnabled set by parameters
-		assert.Equal(t, dto.PublicDashboard.IsEnabled, pubdash.IsEnabled)
-		// CreatedAt set to non-zero time
-		assert.NotEqual(t, &time.Time{}, pubdash.CreatedAt)
-		// Time settings set by db
-		assert.Equal(t, timeSettings, pubdash.TimeSettings)
-		// accessToken is valid uuid
-		_, err = uuid.Parse(pubdash.AccessToken)
-		require.NoError(t, err, "expected a valid UUID, got %s", pubdash.AccessToken)
+		_, err = service.GetPublicPlaylist(context.Background(), pubplaylist.AccessToken)
+		require.ErrorIs(t, err, ErrPublicPlaylistNotFound)
+
+		orgId, err := service.GetPublicPlaylistOrgId(context.Background(), pubplaylist.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), orgId)
+
+		_, err = service.CountryAllowed(context.Background(), pubplaylist.AccessToken, "FR")
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+
+		_, err = service.ExternalViewerRequired(context.Background(), pubplaylist.AccessToken)
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
 	})
 
-	t.Run("Validate pubdash has default time setting value", func(t *testing.T) {
-		sqlStore := sqlstore.InitTestDB(t)
-		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
-		publicdashboardStore := database.ProvideStore(sqlStore)
-		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{})
+	t.Run("applies the restrictions of the public dashboards of the playlist", func(t *testing.T) {
+		service, p, dashboards := setup(t)
 
-		service := &PublicDashboardServiceImpl{
-			log:   log.New("test.logger"),
-			store: publicdashboardStore,
-		}
+		pubplaylist, err := service.SavePublicPlaylistConfig(context.Background(), SignedInUser, &SavePublicPlaylistConfigDTO{
+			PlaylistUid:    p.UID,
+			OrgId:          1,
+			PublicPlaylist: &PublicPlaylist{IsEnabled: true},
+		})
+		require.NoError(t, err)
 
-		dto := &SavePublicDashboardConfigDTO{
-			DashboardUid: dashboard.Uid,
-			OrgId:        dashboard.OrgId,
-			UserId:       7,
-			PublicDashboard: &PublicDashboard{
-				IsEnabled:    true,
-				DashboardUid: "NOTTHESAME",
-				OrgId:        9999999,
-			},
-		}
+		err = service.store.SaveOrgSettings(context.Background(), SaveOrgSettingsCommand{
+			Settings: OrgSettings{OrgId: 1, CountryRestrictions: &CountryRestrictions{Denied: []string{"KP"}}},
+		})
+		require.NoError(t, err)
 
-		_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		allowed, err := service.CountryAllowed(context.Background(), pubplaylist.AccessToken, "FR")
 		require.NoError(t, err)
+		assert.True(t, allowed)
 
-		pubdash, err := service.GetPublicDashboardConfig(context.Background(), dashboard.OrgId, dashboard.Uid)
+		allowed, err = service.CountryAllowed(context.Background(), pubplaylist.AccessToken, "KP")
 		require.NoError(t, err)
-		assert.Equal(t, defaultPubdashTimeSettings, pubdash.TimeSettings)
-	})
+		assert.False(t, allowed)
 
-	t.Run("Validate pubdash whose dashboard has template variables returns error", func(t *testing.T) {
-		sqlStore := sqlstore.InitTestDB(t)
-		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
-		publicdashboardStore := database.ProvideStore(sqlStore)
-		templateVars := make([]map[string]interface{}, 1)
-		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, templateVars)
+		required, err := service.ExternalViewerRequired(context.Background(), pubplaylist.AccessToken)
+		require.NoError(t, err)
+		assert.False(t, required)
 
-		service := &PublicDashboardServiceImpl{
-			log:   log.New("test.logger"),
-			store: publicdashboardStore,
-		}
+		_, err = service.SavePublicDashboardConfig(context.Background(), SignedInUser, &SavePublicDashboardConfigDTO{
+			DashboardUid:    dashboards[1].Uid,
+			OrgId:           1,
+			PublicDashboard: &PublicDashboard{IsEnabled: true, ShareMode: ShareModeExternalSSO},
+		})
+		require.NoError(t, err)
 
-		dto := &SavePublicDashboardConfigDTO{
-			DashboardUid: dashboard.Uid,
-			OrgId:        dashboard.OrgId,
-			UserId:       7,
-			PublicDashboard: &PublicDashboard{
-				IsEnabled:    true,
-				DashboardUid: "NOTTHESAME",
-				OrgId:        9999999,
-			},
-		}
+		required, err = service.ExternalViewerRequired(context.Background(), pubplaylist.AccessToken)
+		require.NoError(t, err)
+		assert.True(t, required)
+	})
 
-		_, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
-		require.Error(t, err)
+	t.Run("returns an error when the playlist does not exist", func(t *testing.T) {
+		service, _, _ := setup(t)
+
+		_, err := service.SavePublicPlaylistConfig(context.Background(), SignedInUser, &SavePublicPlaylistConfigDTO{
+			PlaylistUid:    "nonexistent",
+			OrgId:          1,
+			PublicPlaylist: &PublicPlaylist{IsEnabled: true},
+		})
+		require.ErrorIs(t, err, ErrPlaylistNotFound)
 	})
 }
 
-func TestUpdatePublicDashboard(t *testing.T) {
-	t.Run("Updating public dashboard", func(t *testing.T) {
+func TestScopedAccessTokens(t *testing.T) {
+	setup := func(t *testing.T) (*PublicDashboardServiceImpl, *models.Dashboard, *PublicDashboard) {
 		sqlStore := sqlstore.InitTestDB(t)
 		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
-		publicdashboardStore := database.ProvideStore(sqlStore)
-		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{})
-
 		service := &PublicDashboardServiceImpl{
-			log:   log.New("test.logger"),
-			store: publicdashboardStore,
-		}
-
-		dto := &SavePublicDashboardConfigDTO{
-			DashboardUid: dashboard.Uid,
-			OrgId:        dashboard.OrgId,
-			UserId:       7,
-			PublicDashboard: &PublicDashboard{
-				IsEnabled:    true,
-				TimeSettings: timeSettings,
-			},
+			log:                log.New("test.logger"),
+			store:              database.ProvideStore(sqlStore),
+			libraryPanels:      &fakeLibraryPanelService{},
+			quotaService:       quotatest.NewQuotaServiceFake(),
+			bus:                newTestBus(t),
+			intervalCalculator: intervalv2.NewCalculator(),
 		}
 
-		savedPubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		dashboard := insertTestDashboard(t, dashboardStore, "Audited", 1, 0, false, []map[string]interface{}{})
+		pubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, &SavePublicDashboardConfigDTO{
+			DashboardUid:    dashboard.Uid,
+			OrgId:           dashboard.OrgId,
+			PublicDashboard: &PublicDashboard{IsEnabled: true},
+		})
 		require.NoError(t, err)
 
-		// attempt to overwrite settings
-		dto = &SavePublicDashboardConfigDTO{
-			DashboardUid: dashboard.Uid,
-			OrgId:        dashboard.OrgId,
-			UserId:       8,
-			PublicDashboard: &PublicDashboard{
-				Uid:          savedPubdash.Uid,
-				OrgId:        9,
-				DashboardUid: "abc1234",
-				CreatedBy:    9,
-				CreatedAt:    time.Time{},
+		return service, dashboard, pubdash
+	}
 
-				IsEnabled:    true,
-				TimeSettings: timeSettings,
-				AccessToken:  "NOTAREALUUID",
-			},
-		}
+	// first quarter of 2022
+	quarter := &TimeSettings{From: "1640995200000", To: "1648771199000"}
 
-		// Since the dto.PublicDashboard has a uid, this will call
-		// service.updatePublicDashboardConfig
-		updatedPubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+	t.Run("restricts the public dashboard to the panels and time range of the scope", func(t *testing.T) {
+		service, dashboard, pubdash := setup(t)
+
+		scopedToken, err := service.CreateScopedAccessToken(context.Background(), SignedInUser, &SaveScopedAccessTokenDTO{
+			DashboardUid:      dashboard.Uid,
+			OrgId:             dashboard.OrgId,
+			UserId:            7,
+			ScopedAccessToken: &ScopedAccessToken{Name: "auditors", PanelIds: PanelIds{2}, TimeSettings: quarter},
+		})
 		require.NoError(t, err)
+		assert.Equal(t, pubdash.Uid, scopedToken.PublicDashboardUid)
+		assert.NotEmpty(t, scopedToken.AccessToken)
+		assert.NotEqual(t, pubdash.AccessToken, scopedToken.AccessToken)
 
-		// don't get updated
-		assert.Equal(t, savedPubdash.DashboardUid, updatedPubdash.DashboardUid)
-		assert.Equal(t, savedPubdash.OrgId, updatedPubdash.OrgId)
-		assert.Equal(t, savedPubdash.CreatedAt, updatedPubdash.CreatedAt)
-		assert.Equal(t, savedPubdash.CreatedBy, updatedPubdash.CreatedBy)
-		assert.Equal(t, savedPubdash.AccessToken, updatedPubdash.AccessToken)
+		scopedPubdash, scopedDash, err := service.GetPublicDashboard(context.Background(), scopedToken.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, scopedToken.AccessToken, scopedPubdash.AccessToken)
+		require.Len(t, scopedDash.Data.Get("panels").MustArray(), 1)
+		assert.Equal(t, int64(2), scopedDash.Data.Get("panels").GetIndex(0).Get("id").MustInt64())
+		assert.Equal(t, quarter.From, scopedDash.Data.GetPath("time", "from").MustString())
 
-		// gets updated
-		assert.Equal(t, dto.PublicDashboard.IsEnabled, updatedPubdash.IsEnabled)
-		assert.Equal(t, dto.PublicDashboard.TimeSettings, updatedPubdash.TimeSettings)
-		assert.Equal(t, dto.UserId, updatedPubdash.UpdatedBy)
-		assert.NotEqual(t, &time.Time{}, updatedPubdash.UpdatedAt)
+		_, err = service.GetMetricRequest(context.Background(), scopedDash, scopedPubdash, 1, PublicDashboardQueryDTO{})
+		require.ErrorIs(t, err, ErrPublicDashboardPanelNotFound)
+
+		metricReq, err := service.GetMetricRequest(context.Background(), scopedDash, scopedPubdash, 2, PublicDashboardQueryDTO{})
+		require.NoError(t, err)
+		assert.Equal(t, quarter.From, metricReq.From)
+		assert.Equal(t, quarter.To, metricReq.To)
+
+		// the unscoped access token still gives access to every panel
+		_, dash, err := service.GetPublicDashboard(context.Background(), pubdash.AccessToken)
+		require.NoError(t, err)
+		assert.Len(t, dash.Data.Get("panels").MustArray(), 2)
 	})
 
-	t.Run("Updating set empty time settings", func(t *testing.T) {
-		sqlStore := sqlstore.InitTestDB(t)
-		dashboardStore := dashboardsDB.ProvideDashboardStore(sqlStore, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg))
-		publicdashboardStore := database.ProvideStore(sqlStore)
-		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{})
+	t.Run("lists and deletes scoped access tokens", func(t *testing.T) {
+		service, dashboard, _ := setup(t)
 
-		service := &PublicDashboardServiceImpl{
-			log:   log.New("test.logger"),
-			store: publicdashboardStore,
-		}
+		scopedToken, err := service.CreateScopedAccessToken(context.Background(), SignedInUser, &SaveScopedAccessTokenDTO{
+			DashboardUid:      dashboard.Uid,
+			OrgId:             dashboard.OrgId,
+			ScopedAccessToken: &ScopedAccessToken{Name: "auditors", PanelIds: PanelIds{1}},
+		})
+		require.NoError(t, err)
 
-		dto := &SavePublicDashboardConfigDTO{
-			DashboardUid: dashboard.Uid,
-			OrgId:        dashboard.OrgId,
-			UserId:       7,
-			PublicDashboard: &PublicDashboard{
-				IsEnabled:    true,
-				TimeSettings: timeSettings,
-			},
-		}
+		scopedTokens, err := service.GetScopedAccessTokens(context.Background(), dashboard.OrgId, dashboard.Uid)
+		require.NoError(t, err)
+		require.Len(t, scopedTokens, 1)
+		assert.Equal(t, scopedToken.Uid, scopedTokens[0].Uid)
 
-		// Since the dto.PublicDashboard has a uid, this will call
-		// service.updatePublicDashboardConfig
-		savedPubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		err = service.DeleteScopedAccessToken(context.Background(), dashboard.OrgId, dashboard.Uid, scopedToken.Uid)
 		require.NoError(t, err)
 
-		// attempt to overwrite settings
-		dto = &SavePublicDashboardConfigDTO{
-			DashboardUid: dashboard.Uid,
-			OrgId:        dashboard.OrgId,
-			UserId:       8,
-			PublicDashboard: &PublicDashboard{
-				Uid:          savedPubdash.Uid,
-				OrgId:        9,
-				DashboardUid: "abc1234",
-				CreatedBy:    9,
-				CreatedAt:    time.Time{},
+		_, _, err = service.GetPublicDashboard(context.Background(), scopedToken.AccessToken)
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
 
-				IsEnabled:   true,
-				AccessToken: "NOTAREALUUID",
-			},
+	t.Run("rejects invalid scopes", func(t *testing.T) {
+		service, dashboard, _ := setup(t)
+
+		for _, scope := range []*ScopedAccessToken{
+			{Name: "no panels"},
+			{Name: "unknown panel", PanelIds: PanelIds{1, 42}},
+			{Name: "half a time range", PanelIds: PanelIds{1}, TimeSettings: &TimeSettings{From: "now-90d"}},
+		} {
+			_, err := service.CreateScopedAccessToken(context.Background(), SignedInUser, &SaveScopedAccessTokenDTO{
+				DashboardUid:      dashboard.Uid,
+				OrgId:             dashboard.OrgId,
+				ScopedAccessToken: scope,
+			})
+			require.ErrorIs(t, err, ErrScopedAccessTokenInvalid, scope.Name)
 		}
 
-		updatedPubdash, err := service.SavePublicDashboardConfig(context.Background(), SignedInUser, dto)
+		expired := time.Now().Add(-time.Hour).Unix()
+		for _, scope := range []*ScopedAccessToken{
+			{Name: "expired", PanelIds: PanelIds{1}, Expires: &expired},
+			{Name: "negative query quota", PanelIds: PanelIds{1}, QueryQuota: -1},
+			{Name: "negative byte quota", PanelIds: PanelIds{1}, ByteQuota: -1},
+		} {
+			_, err := service.CreateScopedAccessToken(context.Background(), SignedInUser, &SaveScopedAccessTokenDTO{
+				DashboardUid:      dashboard.Uid,
+				OrgId:             dashboard.OrgId,
+				ScopedAccessToken: scope,
+			})
+			require.ErrorIs(t, err, ErrScopedAccessTokenInvalidLimits, scope.Name)
+		}
+	})
+
+	t.Run("disables the token once the queries served exhaust its quota", func(t *testing.T) {
+		service, dashboard, _ := setup(t)
+
+		expires := time.Now().Add(time.Hour).Unix()
+		scopedToken, err := service.CreateScopedAccessToken(context.Background(), SignedInUser, &SaveScopedAccessTokenDTO{
+			DashboardUid:      dashboard.Uid,
+			OrgId:             dashboard.OrgId,
+			ScopedAccessToken: &ScopedAccessToken{Name: "auditors", PanelIds: PanelIds{1}, Expires: &expires, QueryQuota: 2},
+		})
 		require.NoError(t, err)
+		assert.Equal(t, &expires, scopedToken.Expires)
+		assert.Equal(t, int64(2), scopedToken.QueryQuota)
+
+		res := &backend.QueryDataResponse{Responses: map[string]backend.DataResponse{"A": {}}}
+		for i := 0; i < 2; i++ {
+			scopedPubdash, _, err := service.GetPublicDashboard(context.Background(), scopedToken.AccessToken)
+			require.NoError(t, err)
+			require.NoError(t, service.recordScopedAccessTokenUsage(context.Background(), scopedPubdash.Scope, res))
+		}
 
-		assert.Equal(t, &TimeSettings{}, updatedPubdash.TimeSettings)
+		_, _, err = service.GetPublicDashboard(context.Background(), scopedToken.AccessToken)
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+
+		scopedTokens, err := service.GetScopedAccessTokens(context.Background(), dashboard.OrgId, dashboard.Uid)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), scopedTokens[0].QueryCount)
+		assert.Positive(t, scopedTokens[0].ByteCount)
+		assert.True(t, scopedTokens[0].IsDisabled)
+	})
+
+	t.Run("returns not found when the dashboard isn't public", func(t *testing.T) {
+		service, _, _ := setup(t)
+
+		_, err := service.CreateScopedAccessToken(context.Background(), SignedInUser, &SaveScopedAccessTokenDTO{
+			DashboardUid:      "notpublic",
+			OrgId:             1,
+			ScopedAccessToken: &ScopedAccessToken{PanelIds: PanelIds{1}},
+		})
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
 	})
 }
 
@@ -336,8 +1852,10 @@ func TestBuildAnonymousUser(t *testing.T) {
 	dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{})
 	publicdashboardStore := database.ProvideStore(sqlStore)
 	service := &PublicDashboardServiceImpl{
-		log:   log.New("test.logger"),
-		store: publicdashboardStore,
+		log:          log.New("test.logger"),
+		store:        publicdashboardStore,
+		quotaService: quotatest.NewQuotaServiceFake(),
+		bus:          newTestBus(t),
 	}
 
 	t.Run("will add datasource read and query permissions to user for each datasource in dashboard", func(t *testing.T) {
@@ -366,6 +1884,8 @@ func TestGetMetricRequest(t *testing.T) {
 		log:                log.New("test.logger"),
 		store:              publicdashboardStore,
 		intervalCalculator: intervalv2.NewCalculator(),
+		quotaService:       quotatest.NewQuotaServiceFake(),
+		bus:                newTestBus(t),
 	}
 
 	t.Run("will return an error when validation fails", func(t *testing.T) {
@@ -401,12 +1921,15 @@ func TestBuildMetricRequest(t *testing.T) {
 
 	publicDashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{})
 	nonPublicDashboard := insertTestDashboard(t, dashboardStore, "testNonPublicDashie", 1, 0, true, []map[string]interface{}{})
-	from, to := internal.GetTimeRangeFromDashboard(t, publicDashboard.Data)
+	// the public dashboard time settings override the dashboard time range
+	from, to := "1661990400000", "1662004800000"
 
 	service := &PublicDashboardServiceImpl{
 		log:                log.New("test.logger"),
 		store:              publicdashboardStore,
 		intervalCalculator: intervalv2.NewCalculator(),
+		quotaService:       quotatest.NewQuotaServiceFake(),
+		bus:                newTestBus(t),
 	}
 
 	publicDashboardQueryDTO := PublicDashboardQueryDTO{
@@ -421,7 +1944,7 @@ func TestBuildMetricRequest(t *testing.T) {
 			IsEnabled:    true,
 			DashboardUid: "NOTTHESAME",
 			OrgId:        9999999,
-			TimeSettings: timeSettings,
+			TimeSettings: &TimeSettings{Mode: TimeModeFixed, From: from, To: to},
 		},
 	}
 
@@ -504,6 +2027,103 @@ func TestBuildMetricRequest(t *testing.T) {
 	})
 }
 
+func TestCheckDatasourcesAllowed(t *testing.T) {
+	metricReq := dtos.MetricRequest{
+		Queries: []*simplejson.Json{
+			simplejson.NewFromAny(map[string]interface{}{"datasource": map[string]interface{}{"uid": "ds1"}}),
+			simplejson.NewFromAny(map[string]interface{}{"datasource": map[string]interface{}{"uid": "ds2"}}),
+			simplejson.NewFromAny(map[string]interface{}{"datasource": map[string]interface{}{"uid": "__expr__", "type": "__expr__"}}),
+		},
+	}
+	dsService := &fakeDatasources.FakeDataSourceService{
+		DataSources: []*datasources.DataSource{
+			{Uid: "ds1", Type: "mysql", OrgId: 1},
+			{Uid: "ds2", Type: "prometheus", OrgId: 1},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		allowlist *DatasourceAllowlist
+		err       error
+	}{
+		{name: "allows all data sources without allowlist", allowlist: nil, err: nil},
+		{name: "allows data sources listed by uid", allowlist: &DatasourceAllowlist{Uids: []string{"ds1", "ds2"}}, err: nil},
+		{name: "allows data sources listed by uid or type", allowlist: &DatasourceAllowlist{Uids: []string{"ds1"}, Types: []string{"prometheus"}}, err: nil},
+		{name: "rejects data sources not listed", allowlist: &DatasourceAllowlist{Uids: []string{"ds1"}}, err: ErrPublicDashboardDatasourceNotAllowed},
+		{name: "rejects data source types not listed", allowlist: &DatasourceAllowlist{Types: []string{"loki"}}, err: ErrPublicDashboardDatasourceNotAllowed},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			fakeStore := FakePublicDashboardStore{}
+			fakeStore.On("GetOrgSettings", mock.Anything, int64(1)).
+				Return(&OrgSettings{OrgId: 1, AllowedDatasources: test.allowlist}, nil)
+
+			service := &PublicDashboardServiceImpl{
+				log:               log.New("test.logger"),
+				store:             &fakeStore,
+				datasourceService: dsService,
+			}
+
+			err := service.checkDatasourcesAllowed(context.Background(), 1, metricReq)
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestInjectQueryFilters(t *testing.T) {
+	buildMetricRequest := func() dtos.MetricRequest {
+		return dtos.MetricRequest{
+			Queries: []*simplejson.Json{
+				simplejson.NewFromAny(map[string]interface{}{"datasource": map[string]interface{}{"uid": "ds1"}, "rawSql": "SELECT * FROM orders"}),
+				simplejson.NewFromAny(map[string]interface{}{"datasource": map[string]interface{}{"uid": "ds2"}, "expr": "up"}),
+				simplejson.NewFromAny(map[string]interface{}{"datasource": map[string]interface{}{"uid": "__expr__", "type": "__expr__"}}),
+			},
+		}
+	}
+	dsService := &fakeDatasources.FakeDataSourceService{
+		DataSources: []*datasources.DataSource{
+			{Uid: "ds1", Type: "mysql", OrgId: 1},
+			{Uid: "ds2", Type: "prometheus", OrgId: 1},
+		},
+	}
+	service := &PublicDashboardServiceImpl{
+		log:               log.New("test.logger"),
+		datasourceService: dsService,
+	}
+
+	t.Run("leaves queries untouched without filters", func(t *testing.T) {
+		metricReq := buildMetricRequest()
+		require.NoError(t, service.injectQueryFilters(context.Background(), 1, nil, metricReq))
+		assert.Equal(t, "up", metricReq.Queries[1].Get("expr").MustString())
+	})
+
+	t.Run("injects the filter of each data source type", func(t *testing.T) {
+		metricReq := buildMetricRequest()
+		err := service.injectQueryFilters(context.Background(), 1, QueryFilters{
+			{DatasourceType: "mysql", Filter: "tenant = 'acme'"},
+			{DatasourceType: "prometheus", Filter: `tenant="acme"`},
+		}, metricReq)
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT * FROM (\nSELECT * FROM orders\n) AS public_dashboard_filtered WHERE tenant = 'acme'", metricReq.Queries[0].Get("rawSql").MustString())
+		assert.Equal(t, `up{tenant="acme"}`, metricReq.Queries[1].Get("expr").MustString())
+	})
+
+	t.Run("rejects queries to data source types without a filter", func(t *testing.T) {
+		metricReq := buildMetricRequest()
+		err := service.injectQueryFilters(context.Background(), 1, QueryFilters{
+			{DatasourceType: "prometheus", Filter: `tenant="acme"`},
+		}, metricReq)
+		require.ErrorIs(t, err, ErrPublicDashboardQueryFilterNotApplied)
+	})
+}
+
 func insertTestDashboard(t *testing.T, dashboardStore *dashboardsDB.DashboardStore, title string, orgId int64,
 	folderId int64, isFolder bool, templateVars []map[string]interface{}, tags ...interface{}) *models.Dashboard {
 	t.Helper()
@@ -669,3 +2289,65 @@ func TestDashboardEnabledChanged(t *testing.T) {
 		assert.True(t, publicDashboardIsEnabledChanged(&PublicDashboard{IsEnabled: false}, &PublicDashboard{IsEnabled: true}))
 	})
 }
+
+// fakeLibraryPanelService replaces library panel references with the models
+// registered in panels, keyed by library panel uid
+type fakeLibraryPanelService struct {
+	librarypanels.Service
+	panels map[string]map[string]interface{}
+	loads  int
+}
+
+func (f *fakeLibraryPanelService) LoadLibraryPanelsForDashboard(c context.Context, dash *models.Dashboard) error {
+	f.loads++
+	for i, panel := range dash.Data.Get("panels").MustArray() {
+		panelAsJSON := simplejson.NewFromAny(panel)
+		model, ok := f.panels[panelAsJSON.GetPath("libraryPanel", "uid").MustString()]
+		if !ok {
+			continue
+		}
+
+		elem := simplejson.NewFromAny(map[string]interface{}{})
+		for k, v := range model {
+			elem.Set(k, v)
+		}
+		elem.Set("id", panelAsJSON.Get("id").MustInt64())
+		elem.Set("gridPos", panelAsJSON.Get("gridPos").MustMap())
+		dash.Data.Get("panels").SetIndex(i, elem.Interface())
+	}
+	return nil
+}
+
+// fakeQuotaService records the last quota check and reports the quota as
+// reached when reached is set
+// fakePluginClient returns the health check result of the plugin id, plugins
+// without a result don't implement health checks
+type fakePluginClient struct {
+	plugins.Client
+	results map[string]*backend.CheckHealthResult
+}
+
+func (f *fakePluginClient) CheckHealth(_ context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	if res, ok := f.results[req.PluginContext.PluginID]; ok {
+		return res, nil
+	}
+	return nil, backendplugin.ErrMethodNotImplemented
+}
+
+type fakeQuotaService struct {
+	quota.Service
+	reached bool
+	target  string
+	params  *quota.ScopeParameters
+}
+
+func (f *fakeQuotaService) CheckQuotaReached(c context.Context, target string, params *quota.ScopeParameters) (bool, error) {
+	f.target = target
+	f.params = params
+	return f.reached, nil
+}
+
+func newTestBus(t *testing.T) bus.Bus {
+	t.Helper()
+	return bus.ProvideBus(tracing.InitializeTracerForTest())
+}
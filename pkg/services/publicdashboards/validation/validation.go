@@ -2,9 +2,15 @@ package validation
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/queries"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
 )
 
 func ValidateSavePublicDashboard(dto *SavePublicDashboardConfigDTO, dashboard *models.Dashboard) error {
@@ -32,3 +38,145 @@ func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO) error {
 
 	return nil
 }
+
+func ValidateMaskingRules(rules MaskingRules) error {
+	for _, rule := range rules {
+		if _, err := regexp.Compile(rule.FieldPattern); err != nil || rule.FieldPattern == "" {
+			return ErrPublicDashboardInvalidMaskingRule
+		}
+
+		switch rule.Action {
+		case MaskActionRedact, MaskActionHash:
+		case MaskActionTruncate:
+			if rule.Length < 0 {
+				return ErrPublicDashboardInvalidMaskingRule
+			}
+		default:
+			return ErrPublicDashboardInvalidMaskingRule
+		}
+	}
+
+	return nil
+}
+
+func ValidateQueryFilters(filters QueryFilters) error {
+	seen := make(map[string]bool, len(filters))
+	for _, filter := range filters {
+		if seen[filter.DatasourceType] || !queries.QueryFilterSupported(filter.DatasourceType) {
+			return ErrPublicDashboardInvalidQueryFilter
+		}
+		seen[filter.DatasourceType] = true
+
+		if strings.TrimSpace(filter.Filter) == "" {
+			return ErrPublicDashboardInvalidQueryFilter
+		}
+
+		if filter.DatasourceType == datasources.DS_PROMETHEUS {
+			if err := queries.ParsePrometheusFilter(filter.Filter); err != nil {
+				return ErrPublicDashboardInvalidQueryFilter
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateScopedAccessToken checks that the scoped access token gives access
+// to at least one panel, that all its panels are panels of the dashboard and
+// that its time range is complete when it has one. Its expiry has to be in the
+// future and its quotas can't be negative.
+func ValidateScopedAccessToken(scopedToken *ScopedAccessToken, dashboard *models.Dashboard) error {
+	if len(scopedToken.PanelIds) == 0 {
+		return ErrScopedAccessTokenInvalid
+	}
+
+	panels := queries.GroupQueriesByPanelId(dashboard.Data)
+	for _, id := range scopedToken.PanelIds {
+		if _, ok := panels[id]; !ok {
+			return ErrScopedAccessTokenInvalid
+		}
+	}
+
+	if ts := scopedToken.TimeSettings; ts != nil && (ts.From == "" || ts.To == "") {
+		return ErrScopedAccessTokenInvalid
+	}
+
+	if scopedToken.Expires != nil && *scopedToken.Expires <= time.Now().Unix() {
+		return ErrScopedAccessTokenInvalidLimits
+	}
+
+	if scopedToken.QueryQuota < 0 || scopedToken.ByteQuota < 0 {
+		return ErrScopedAccessTokenInvalidLimits
+	}
+
+	return nil
+}
+
+var countryCodeRegex = regexp.MustCompile(`^[A-Za-z]{2}$`)
+
+// ValidateCountryRestrictions checks that the restrictions only list ISO
+// 3166-1 alpha-2 country codes
+func ValidateCountryRestrictions(restrictions *CountryRestrictions) error {
+	if restrictions == nil {
+		return nil
+	}
+
+	for _, codes := range [][]string{restrictions.Allowed, restrictions.Denied} {
+		for _, code := range codes {
+			if !countryCodeRegex.MatchString(code) {
+				return ErrPublicDashboardInvalidCountryRestrictions
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateTimeSettings checks that the mode is a known one and that the fixed
+// window, when used, is a valid relative or absolute time range
+func ValidateTimeSettings(ts *TimeSettings) error {
+	if ts == nil {
+		return nil
+	}
+
+	switch ts.Mode {
+	case "", TimeModeDashboard, TimeModeFixed:
+	default:
+		return ErrPublicDashboardInvalidTimeSettings
+	}
+
+	if ts.Mode == TimeModeDashboard || (ts.Mode == "" && ts.From == "" && ts.To == "") {
+		return nil
+	}
+
+	if ts.From == "" || ts.To == "" {
+		return ErrPublicDashboardInvalidTimeSettings
+	}
+
+	timeRange := legacydata.NewDataTimeRange(ts.From, ts.To)
+	from, err := timeRange.ParseFrom()
+	if err != nil {
+		return ErrPublicDashboardInvalidTimeSettings
+	}
+	to, err := timeRange.ParseTo()
+	if err != nil {
+		return ErrPublicDashboardInvalidTimeSettings
+	}
+
+	if !from.Before(to) {
+		return ErrPublicDashboardInvalidTimeSettings
+	}
+
+	return nil
+}
+
+// ValidateShareMode checks that the share mode is a known one, empty is the
+// public share mode
+func ValidateShareMode(shareMode string) error {
+	switch shareMode {
+	case "", ShareModePublic, ShareModeExternalSSO:
+		return nil
+	default:
+		return ErrPublicDashboardInvalidShareMode
+	}
+}
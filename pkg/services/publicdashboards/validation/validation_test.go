@@ -42,3 +42,126 @@ func TestValidateSavePublicDashboard(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestValidateMaskingRules(t *testing.T) {
+	testCases := []struct {
+		name  string
+		rules MaskingRules
+		valid bool
+	}{
+		{name: "no rules", rules: nil, valid: true},
+		{name: "valid rules", rules: MaskingRules{
+			{FieldPattern: "^email$", Action: MaskActionRedact},
+			{FieldPattern: "user.*", Action: MaskActionHash},
+			{FieldPattern: "ip", Action: MaskActionTruncate, Length: 4},
+		}, valid: true},
+		{name: "empty pattern", rules: MaskingRules{{FieldPattern: "", Action: MaskActionRedact}}, valid: false},
+		{name: "invalid pattern", rules: MaskingRules{{FieldPattern: "(", Action: MaskActionRedact}}, valid: false},
+		{name: "unknown action", rules: MaskingRules{{FieldPattern: "email", Action: "encrypt"}}, valid: false},
+		{name: "negative truncate length", rules: MaskingRules{{FieldPattern: "email", Action: MaskActionTruncate, Length: -1}}, valid: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateMaskingRules(test.rules)
+			if test.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrPublicDashboardInvalidMaskingRule)
+			}
+		})
+	}
+}
+
+func TestValidateQueryFilters(t *testing.T) {
+	testCases := []struct {
+		name    string
+		filters QueryFilters
+		valid   bool
+	}{
+		{name: "no filters", filters: nil, valid: true},
+		{name: "valid filters", filters: QueryFilters{
+			{DatasourceType: "prometheus", Filter: `tenant="acme", env=~"prod|staging"`},
+			{DatasourceType: "postgres", Filter: "tenant = 'acme'"},
+		}, valid: true},
+		{name: "unsupported data source type", filters: QueryFilters{{DatasourceType: "loki", Filter: `tenant="acme"`}}, valid: false},
+		{name: "empty filter", filters: QueryFilters{{DatasourceType: "mysql", Filter: " "}}, valid: false},
+		{name: "invalid label matchers", filters: QueryFilters{{DatasourceType: "prometheus", Filter: "tenant acme"}}, valid: false},
+		{name: "duplicated data source type", filters: QueryFilters{
+			{DatasourceType: "mysql", Filter: "tenant = 'acme'"},
+			{DatasourceType: "mysql", Filter: "tenant = 'other'"},
+		}, valid: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateQueryFilters(test.filters)
+			if test.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrPublicDashboardInvalidQueryFilter)
+			}
+		})
+	}
+}
+
+func TestValidateCountryRestrictions(t *testing.T) {
+	testCases := []struct {
+		name         string
+		restrictions *CountryRestrictions
+		valid        bool
+	}{
+		{name: "no restrictions", restrictions: nil, valid: true},
+		{name: "valid restrictions", restrictions: &CountryRestrictions{Allowed: []string{"FR", "de"}, Denied: []string{"KP"}}, valid: true},
+		{name: "alpha-3 code", restrictions: &CountryRestrictions{Allowed: []string{"FRA"}}, valid: false},
+		{name: "empty code", restrictions: &CountryRestrictions{Denied: []string{""}}, valid: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateCountryRestrictions(test.restrictions)
+			if test.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrPublicDashboardInvalidCountryRestrictions)
+			}
+		})
+	}
+}
+
+func TestValidateTimeSettings(t *testing.T) {
+	testCases := []struct {
+		name  string
+		ts    *TimeSettings
+		valid bool
+	}{
+		{name: "nil time settings", ts: nil, valid: true},
+		{name: "empty time settings", ts: &TimeSettings{}, valid: true},
+		{name: "dashboard mode ignores the window", ts: &TimeSettings{Mode: TimeModeDashboard, From: "invalid"}, valid: true},
+		{name: "relative window", ts: &TimeSettings{From: "now-7d", To: "now"}, valid: true},
+		{name: "absolute window", ts: &TimeSettings{Mode: TimeModeFixed, From: "1661990400000", To: "1662033600000"}, valid: true},
+		{name: "unknown mode", ts: &TimeSettings{Mode: "sliding"}, valid: false},
+		{name: "fixed mode without window", ts: &TimeSettings{Mode: TimeModeFixed}, valid: false},
+		{name: "half window", ts: &TimeSettings{From: "now-7d"}, valid: false},
+		{name: "unparsable window", ts: &TimeSettings{From: "last week", To: "now"}, valid: false},
+		{name: "reversed window", ts: &TimeSettings{From: "now", To: "now-7d"}, valid: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateTimeSettings(test.ts)
+			if test.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrPublicDashboardInvalidTimeSettings)
+			}
+		})
+	}
+}
+
+func TestValidateShareMode(t *testing.T) {
+	require.NoError(t, ValidateShareMode(""))
+	require.NoError(t, ValidateShareMode(ShareModePublic))
+	require.NoError(t, ValidateShareMode(ShareModeExternalSSO))
+	require.ErrorIs(t, ValidateShareMode("org_members"), ErrPublicDashboardInvalidShareMode)
+}
@@ -94,15 +94,15 @@ func (s *Service) QueryDataMultipleSources(ctx context.Context, user *user.Signe
 		g, ctx := errgroup.WithContext(ctx)
 		results := make([]backend.Responses, len(byDataSource))
 
-		for _, queries := range byDataSource {
-			dataSourceQueries := queries
+		for i, queries := range byDataSource {
+			i, dataSourceQueries := i, queries
 			g.Go(func() error {
 				subDTO := reqDTO.CloneWithQueries(dataSourceQueries)
 
 				subResp, err := s.QueryData(ctx, user, skipCache, subDTO, handleExpressions)
 
 				if err == nil {
-					results = append(results, subResp.Responses)
+					results[i] = subResp.Responses
 				}
 
 				return err
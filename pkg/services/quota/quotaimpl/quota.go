@@ -190,6 +190,17 @@ func (s *Service) getQuotaScopes(target string) ([]models.QuotaScope, error) {
 			models.QuotaScope{Name: "org", Target: target, DefaultLimit: s.Cfg.Quota.Org.AlertRule},
 		)
 		return scopes, nil
+	case "dashboard_public":
+		scopes = append(scopes,
+			models.QuotaScope{Name: "org", Target: target, DefaultLimit: s.Cfg.Quota.Org.PublicDashboard},
+		)
+		return scopes, nil
+	case "correlation":
+		scopes = append(scopes,
+			models.QuotaScope{Name: "global", Target: target, DefaultLimit: s.Cfg.Quota.Global.Correlation},
+			models.QuotaScope{Name: "org", Target: target, DefaultLimit: s.Cfg.Quota.Org.Correlation},
+		)
+		return scopes, nil
 	case "file":
 		scopes = append(scopes,
 			models.QuotaScope{Name: "global", Target: target, DefaultLimit: s.Cfg.Quota.Global.File},
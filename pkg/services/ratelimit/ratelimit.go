@@ -0,0 +1,198 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/network"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// Names of the rate limits, configured in the [rate_limit.<name>] sections.
+const (
+	PublicDashboards  = "public_dashboards"
+	Correlations      = "correlations"
+	NotificationTests = "notification_tests"
+)
+
+// lockStripes is the number of locks serializing the updates of the buckets,
+// the buckets sharing a lock wait for each other's round trip to the cache.
+const lockStripes = 64
+
+var ErrRateLimited = errutil.NewBase(errutil.StatusTooManyRequests, "ratelimit.exceeded", errutil.WithPublicMessage("Too many requests, try again later"))
+
+func init() {
+	remotecache.Register(bucket{})
+}
+
+// bucket is the token bucket of a client, stored in the remote cache.
+type bucket struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+// KeyFunc returns the key identifying the client of the request.
+type KeyFunc func(c *models.ReqContext) string
+
+// ByClientIP identifies clients by IP, for anonymous endpoints. The IP is the
+// address of the connection, or the one forwarded by the trusted proxies, so
+// that clients can't pick their bucket.
+func ByClientIP(trustedProxies []*net.IPNet) KeyFunc {
+	return func(c *models.ReqContext) string {
+		return network.ClientIP(c.Req, trustedProxies)
+	}
+}
+
+// ByUser identifies clients by signed in user.
+func ByUser(c *models.ReqContext) string {
+	return fmt.Sprintf("%d:%d", c.OrgID, c.UserID)
+}
+
+// Service rate limits the requests of clients to endpoints sharing a named
+// rate limit.
+type Service interface {
+	// Allow takes a token from the bucket of the client for the rate limit.
+	// When the bucket is empty it returns false and how long the client
+	// should wait before retrying.
+	Allow(ctx context.Context, name string, key string) (bool, time.Duration, error)
+	// Check returns the response rejecting the request with the status 429
+	// when its client exceeds the rate limit, or nil.
+	Check(c *models.ReqContext, name string, key KeyFunc) response.Response
+	// Middleware rejects the requests of the clients exceeding the rate limit
+	// with the status 429.
+	Middleware(name string, key KeyFunc) web.Handler
+}
+
+func ProvideService(cfg *setting.Cfg, store *remotecache.RemoteCache) *RateLimitService {
+	return newService(cfg, store)
+}
+
+func newService(cfg *setting.Cfg, store remotecache.CacheStorage) *RateLimitService {
+	return &RateLimitService{
+		cfg:   cfg,
+		store: store,
+		log:   log.New("ratelimit"),
+		now:   time.Now,
+	}
+}
+
+// RateLimitService keeps the buckets in the remote cache, so that instances
+// sharing it share the buckets. The updates of a bucket are serialized within
+// an instance only, concurrent requests to several instances may get a few
+// extra tokens.
+type RateLimitService struct {
+	cfg   *setting.Cfg
+	store remotecache.CacheStorage
+	log   log.Logger
+	locks [lockStripes]sync.Mutex
+	now   func() time.Time
+}
+
+func (s *RateLimitService) Allow(ctx context.Context, name string, key string) (bool, time.Duration, error) {
+	limit, ok := s.limit(name)
+	if !ok {
+		return true, 0, nil
+	}
+	return s.take(ctx, name, limit, key)
+}
+
+func (s *RateLimitService) Check(c *models.ReqContext, name string, key KeyFunc) response.Response {
+	limit, ok := s.limit(name)
+	if !ok {
+		return nil
+	}
+	return s.check(c, name, limit, key)
+}
+
+func (s *RateLimitService) Middleware(name string, key KeyFunc) web.Handler {
+	limit, ok := s.limit(name)
+	if !ok {
+		return func(c *models.ReqContext) {}
+	}
+
+	return func(c *models.ReqContext) {
+		if res := s.check(c, name, limit, key); res != nil {
+			res.WriteTo(c)
+		}
+	}
+}
+
+func (s *RateLimitService) check(c *models.ReqContext, name string, limit setting.RateLimit, key KeyFunc) response.Response {
+	allowed, retryAfter, err := s.take(c.Req.Context(), name, limit, key(c))
+	if err != nil {
+		// the requests are let through rather than failing when the cache is unavailable
+		s.log.Warn("Failed to check rate limit", "limit", name, "error", err)
+		return nil
+	}
+	if allowed {
+		return nil
+	}
+
+	c.Resp.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	return response.Err(ErrRateLimited.Errorf("rate limit %s exceeded", name))
+}
+
+func (s *RateLimitService) limit(name string) (setting.RateLimit, bool) {
+	limit, ok := s.cfg.RateLimits[name]
+	return limit, ok && limit.Rate > 0
+}
+
+func (s *RateLimitService) take(ctx context.Context, name string, limit setting.RateLimit, key string) (bool, time.Duration, error) {
+	cacheKey := fmt.Sprintf("ratelimit-%s-%s", name, key)
+	mu := s.lock(cacheKey)
+	mu.Lock()
+	defer mu.Unlock()
+
+	now := s.now()
+
+	b := bucket{Tokens: float64(limit.Burst), Updated: now}
+	stored, err := s.store.Get(ctx, cacheKey)
+	switch {
+	case err == nil:
+		if previous, ok := stored.(bucket); ok {
+			b = previous
+		}
+	case !errors.Is(err, remotecache.ErrCacheItemNotFound):
+		return false, 0, err
+	}
+
+	// refill the bucket for the time elapsed since the last request
+	b.Tokens = math.Min(float64(limit.Burst), b.Tokens+now.Sub(b.Updated).Seconds()*limit.Rate)
+	b.Updated = now
+
+	allowed := b.Tokens >= 1
+	var retryAfter time.Duration
+	if allowed {
+		b.Tokens--
+	} else {
+		retryAfter = time.Duration((1 - b.Tokens) / limit.Rate * float64(time.Second))
+	}
+
+	// the bucket is full again once it expires
+	expire := time.Duration(float64(limit.Burst)/limit.Rate*float64(time.Second)) + time.Second
+	if err := s.store.Set(ctx, cacheKey, b, expire); err != nil {
+		return false, 0, err
+	}
+	return allowed, retryAfter, nil
+}
+
+// lock returns the lock of the bucket, so that the clients only wait for the
+// requests of the clients sharing its stripe.
+func (s *RateLimitService) lock(cacheKey string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(cacheKey))
+	return &s.locks[h.Sum32()%lockStripes]
+}
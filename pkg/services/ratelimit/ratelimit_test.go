@@ -0,0 +1,296 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/network"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	items map[string]interface{}
+}
+
+func (f *fakeStore) Get(_ context.Context, key string) (interface{}, error) {
+	item, ok := f.items[key]
+	if !ok {
+		return nil, remotecache.ErrCacheItemNotFound
+	}
+	return item, nil
+}
+
+func (f *fakeStore) Set(_ context.Context, key string, value interface{}, _ time.Duration) error {
+	f.items[key] = value
+	return nil
+}
+
+func (f *fakeStore) Delete(_ context.Context, key string) error {
+	delete(f.items, key)
+	return nil
+}
+
+// blockingStore is safe for concurrent use, its Get of the blocked key waits
+// until the channel is closed
+type blockingStore struct {
+	mu      sync.Mutex
+	items   map[string]interface{}
+	blocked string
+	release chan struct{}
+}
+
+func (f *blockingStore) Get(_ context.Context, key string) (interface{}, error) {
+	if key == f.blocked {
+		<-f.release
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	item, ok := f.items[key]
+	if !ok {
+		return nil, remotecache.ErrCacheItemNotFound
+	}
+	return item, nil
+}
+
+func (f *blockingStore) Set(_ context.Context, key string, value interface{}, _ time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items[key] = value
+	return nil
+}
+
+func (f *blockingStore) Delete(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.items, key)
+	return nil
+}
+
+func newTestService(limits map[string]setting.RateLimit) (*RateLimitService, *time.Time) {
+	cfg := setting.NewCfg()
+	cfg.RateLimits = limits
+	s := newService(cfg, &fakeStore{items: map[string]interface{}{}})
+
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestAllow(t *testing.T) {
+	ctx := context.Background()
+	s, now := newTestService(map[string]setting.RateLimit{Correlations: {Rate: 0.5, Burst: 2}})
+
+	t.Run("allows the burst and then rejects", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			allowed, _, err := s.Allow(ctx, Correlations, "1:1")
+			require.NoError(t, err)
+			require.True(t, allowed)
+		}
+
+		allowed, retryAfter, err := s.Allow(ctx, Correlations, "1:1")
+		require.NoError(t, err)
+		require.False(t, allowed)
+		require.Equal(t, 2*time.Second, retryAfter)
+	})
+
+	t.Run("keeps a bucket per key", func(t *testing.T) {
+		allowed, _, err := s.Allow(ctx, Correlations, "1:2")
+		require.NoError(t, err)
+		require.True(t, allowed)
+	})
+
+	t.Run("refills the bucket over time", func(t *testing.T) {
+		*now = now.Add(2 * time.Second)
+
+		allowed, _, err := s.Allow(ctx, Correlations, "1:1")
+		require.NoError(t, err)
+		require.True(t, allowed)
+
+		allowed, _, err = s.Allow(ctx, Correlations, "1:1")
+		require.NoError(t, err)
+		require.False(t, allowed)
+	})
+
+	t.Run("allows requests without rate limit", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			allowed, _, err := s.Allow(ctx, PublicDashboards, "127.0.0.1")
+			require.NoError(t, err)
+			require.True(t, allowed)
+		}
+	})
+}
+
+func TestConcurrentAllow(t *testing.T) {
+	ctx := context.Background()
+	cfg := setting.NewCfg()
+	cfg.RateLimits = map[string]setting.RateLimit{Correlations: {Rate: 0.5, Burst: 5}}
+
+	t.Run("doesn't make the clients wait for a slow bucket of another stripe", func(t *testing.T) {
+		store := &blockingStore{items: map[string]interface{}{}, blocked: "ratelimit-correlations-1:1", release: make(chan struct{})}
+		s := newService(cfg, store)
+
+		other := ""
+		for i := 2; i < 100 && other == ""; i++ {
+			if key := "1:" + strconv.Itoa(i); s.lock("ratelimit-correlations-"+key) != s.lock(store.blocked) {
+				other = key
+			}
+		}
+		require.NotEmpty(t, other, "no key on another stripe")
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _, _ = s.Allow(ctx, Correlations, "1:1")
+		}()
+
+		allowed := make(chan bool)
+		go func() {
+			ok, _, _ := s.Allow(ctx, Correlations, other)
+			allowed <- ok
+		}()
+
+		select {
+		case ok := <-allowed:
+			require.True(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("the request waited for the bucket of another client")
+		}
+
+		close(store.release)
+		<-done
+	})
+
+	t.Run("serializes the updates of a bucket", func(t *testing.T) {
+		s := newService(cfg, &blockingStore{items: map[string]interface{}{}})
+		now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+		s.now = func() time.Time { return now }
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		count := 0
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ok, _, err := s.Allow(ctx, Correlations, "1:1")
+				require.NoError(t, err)
+				if ok {
+					mu.Lock()
+					count++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		require.Equal(t, 5, count)
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	s, _ := newTestService(map[string]setting.RateLimit{
+		NotificationTests: {Rate: 1, Burst: 1},
+		Correlations:      {Rate: 0, Burst: 1},
+	})
+
+	run := func(name string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		c := &models.ReqContext{
+			Context:      &web.Context{Req: httptest.NewRequest(http.MethodPost, "/", nil), Resp: web.NewResponseWriter(http.MethodPost, recorder)},
+			SignedInUser: &user.SignedInUser{OrgID: 1, UserID: 1},
+			Logger:       log.NewNopLogger(),
+		}
+		s.Middleware(name, ByUser).(func(c *models.ReqContext))(c)
+		return recorder
+	}
+
+	t.Run("rejects the requests exceeding the rate limit", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, run(NotificationTests).Code)
+
+		recorder := run(NotificationTests)
+		require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		require.Equal(t, "1", recorder.Header().Get("Retry-After"))
+		require.Contains(t, recorder.Body.String(), "ratelimit.exceeded")
+	})
+
+	t.Run("lets requests through when the rate limit is disabled", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			require.Equal(t, http.StatusOK, run(Correlations).Code)
+		}
+	})
+}
+
+func TestByClientIP(t *testing.T) {
+	trustedProxies, err := network.ParseTrustedProxies([]string{"10.0.0.1"})
+	require.NoError(t, err)
+	key := ByClientIP(trustedProxies)
+
+	request := func(remoteAddr string) *models.ReqContext {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "198.51.100.1")
+		return &models.ReqContext{Context: &web.Context{Req: req}}
+	}
+
+	require.Equal(t, "203.0.113.1", key(request("203.0.113.1:5000")))
+	require.Equal(t, "198.51.100.1", key(request("10.0.0.1:5000")))
+}
+
+func TestCheck(t *testing.T) {
+	s, _ := newTestService(map[string]setting.RateLimit{NotificationTests: {Rate: 1, Burst: 1}})
+
+	check := func(name string) (response.Response, *httptest.ResponseRecorder) {
+		recorder := httptest.NewRecorder()
+		c := &models.ReqContext{
+			Context:      &web.Context{Req: httptest.NewRequest(http.MethodPost, "/", nil), Resp: web.NewResponseWriter(http.MethodPost, recorder)},
+			SignedInUser: &user.SignedInUser{OrgID: 1, UserID: 1},
+			Logger:       log.NewNopLogger(),
+		}
+		return s.Check(c, name, ByUser), recorder
+	}
+
+	t.Run("returns the response rejecting the requests exceeding the rate limit", func(t *testing.T) {
+		res, _ := check(NotificationTests)
+		require.Nil(t, res)
+
+		res, recorder := check(NotificationTests)
+		require.NotNil(t, res)
+		require.Equal(t, http.StatusTooManyRequests, res.Status())
+		require.Equal(t, "1", recorder.Header().Get("Retry-After"))
+	})
+
+	t.Run("returns nil without rate limit", func(t *testing.T) {
+		res, _ := check(Correlations)
+		require.Nil(t, res)
+	})
+}
+
+func TestIntegrationRemoteCacheBuckets(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	cfg := setting.NewCfg()
+	cfg.RateLimits = map[string]setting.RateLimit{PublicDashboards: {Rate: 0.1, Burst: 1}}
+	s := ProvideService(cfg, remotecache.NewFakeStore(t))
+
+	allowed, _, err := s.Allow(context.Background(), PublicDashboards, "127.0.0.1")
+	require.NoError(t, err)
+	require.True(t, allowed)
+
+	allowed, _, err = s.Allow(context.Background(), PublicDashboards, "127.0.0.1")
+	require.NoError(t, err)
+	require.False(t, allowed)
+}
@@ -35,6 +35,7 @@ type Query struct {
 	Limit         int64
 	Page          int64
 	IsStarred     bool
+	IsPublic      bool
 	Type          string
 	DashboardUIDs []string
 	DashboardIds  []int64
@@ -63,6 +64,7 @@ func (s *SearchService) SearchHandler(ctx context.Context, query *Query) error {
 		Title:         query.Title,
 		SignedInUser:  query.SignedInUser,
 		IsStarred:     query.IsStarred,
+		IsPublic:      query.IsPublic,
 		DashboardUIDs: query.DashboardUIDs,
 		DashboardIds:  query.DashboardIds,
 		Type:          query.Type,
@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/secrets/manager"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
@@ -19,6 +20,8 @@ type SecretsMigrator struct {
 	sqlStore      *sqlstore.SQLStore
 	settings      setting.Provider
 	features      featuremgmt.FeatureToggles
+
+	alertingProgress progressTracker
 }
 
 func ProvideSecretsMigrator(
@@ -67,6 +70,31 @@ func (m *SecretsMigrator) ReEncryptSecrets(ctx context.Context) (bool, error) {
 	return !anyFailure, nil
 }
 
+// ReEncryptAlertingSecrets re-encrypts the secure settings of the alerting
+// contact points in the background, so that large installations can follow
+// the progress through AlertingSecretsReEncryptionProgress.
+func (m *SecretsMigrator) ReEncryptAlertingSecrets(_ context.Context) error {
+	if err := m.initProvidersIfNeeded(); err != nil {
+		return err
+	}
+
+	if !m.alertingProgress.start() {
+		return secrets.ErrReEncryptionInProgress
+	}
+
+	// The re-encryption must outlive the request that started it
+	go func() {
+		defer m.alertingProgress.finish()
+		alertingSecret{progress: &m.alertingProgress}.reencrypt(context.Background(), m.secretsSrv, m.sqlStore)
+	}()
+
+	return nil
+}
+
+func (m *SecretsMigrator) AlertingSecretsReEncryptionProgress() secrets.ReEncryptionProgress {
+	return m.alertingProgress.get()
+}
+
 func (m *SecretsMigrator) RollBackSecrets(ctx context.Context) (bool, error) {
 	err := m.initProvidersIfNeeded()
 	if err != nil {
@@ -143,7 +171,9 @@ type jsonSecret struct {
 	tableName string
 }
 
-type alertingSecret struct{}
+type alertingSecret struct {
+	progress *progressTracker
+}
 
 func nowInUTC() string {
 	return time.Now().UTC().Format("2006-01-02 15:04:05")
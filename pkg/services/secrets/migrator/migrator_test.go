@@ -0,0 +1,98 @@
+package migrator
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+const alertmanagerConfigTemplate = `{
+	"alertmanager_config": {
+		"route": {"receiver": "airflow"},
+		"receivers": [{
+			"name": "airflow",
+			"grafana_managed_receiver_configs": [{
+				"uid": "airflow-uid",
+				"name": "airflow",
+				"type": "webhook",
+				"settings": {"url": "http://localhost/api/v1/dags/alert/dagRuns"},
+				"secureSettings": {"password": %q}
+			}]
+		}]
+	}
+}`
+
+func TestReEncryptAlertingSecrets(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	secretsSrv := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	migrator := ProvideSecretsMigrator(nil, secretsSrv, sqlStore, nil, featuremgmt.WithFeatures())
+	ctx := context.Background()
+
+	encrypted, err := secretsSrv.Encrypt(ctx, []byte("airflow-password"), secrets.WithoutScope())
+	require.NoError(t, err)
+	encoded := base64.StdEncoding.EncodeToString(encrypted)
+
+	configs := []*ngmodels.AlertConfiguration{
+		{OrgID: 1, AlertmanagerConfiguration: fmt.Sprintf(alertmanagerConfigTemplate, encoded)},
+		{OrgID: 2, AlertmanagerConfiguration: fmt.Sprintf(alertmanagerConfigTemplate, "not base64")},
+	}
+	err = sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Table("alert_configuration").Insert(configs)
+		return err
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, secretsSrv.RotateDataKeys(ctx))
+	require.NoError(t, migrator.ReEncryptAlertingSecrets(ctx))
+
+	require.Eventually(t, func() bool {
+		return !migrator.AlertingSecretsReEncryptionProgress().Running
+	}, time.Second*10, time.Millisecond*10)
+
+	progress := migrator.AlertingSecretsReEncryptionProgress()
+	assert.Equal(t, 2, progress.Total)
+	assert.Equal(t, 2, progress.Processed)
+	assert.Equal(t, 1, progress.Failed)
+	assert.Equal(t, 1, progress.Secrets)
+	assert.NotNil(t, progress.StartedAt)
+	assert.NotNil(t, progress.FinishedAt)
+
+	var stored ngmodels.AlertConfiguration
+	err = sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Table("alert_configuration").Where("org_id = ?", 1).Get(&stored)
+		return err
+	})
+	require.NoError(t, err)
+
+	cfg, err := notifier.Load([]byte(stored.AlertmanagerConfiguration))
+	require.NoError(t, err)
+	reencoded := cfg.AlertmanagerConfig.Receivers[0].GrafanaManagedReceivers[0].SecureSettings["password"]
+	assert.NotEqual(t, encoded, reencoded)
+
+	reencrypted, err := base64.StdEncoding.DecodeString(reencoded)
+	require.NoError(t, err)
+	decrypted, err := secretsSrv.Decrypt(ctx, reencrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "airflow-password", string(decrypted))
+}
+
+func TestReEncryptAlertingSecretsInProgress(t *testing.T) {
+	migrator := &SecretsMigrator{features: featuremgmt.WithFeatures()}
+	require.True(t, migrator.alertingProgress.start())
+
+	err := migrator.ReEncryptAlertingSecrets(context.Background())
+	require.ErrorIs(t, err, secrets.ErrReEncryptionInProgress)
+}
@@ -0,0 +1,71 @@
+package migrator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
+
+// progressTracker tracks the progress of a re-encryption running in the
+// background. Its methods can be called on a nil tracker, so that the secrets
+// re-encrypted in the foreground don't need one.
+type progressTracker struct {
+	mu       sync.Mutex
+	progress secrets.ReEncryptionProgress
+}
+
+// start resets the progress for a new run, and returns false if the previous
+// run has not finished yet
+func (p *progressTracker) start() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.progress.Running {
+		return false
+	}
+
+	now := time.Now()
+	p.progress = secrets.ReEncryptionProgress{Running: true, StartedAt: &now}
+	return true
+}
+
+func (p *progressTracker) setTotal(total int) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Total = total
+}
+
+func (p *progressTracker) itemDone(secretsCount int, err error) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Processed++
+	if err != nil {
+		p.progress.Failed++
+		return
+	}
+	p.progress.Secrets += secretsCount
+}
+
+func (p *progressTracker) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.progress.Running = false
+	p.progress.FinishedAt = &now
+}
+
+func (p *progressTracker) get() secrets.ReEncryptionProgress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.progress
+}
@@ -212,10 +212,13 @@ func (s alertingSecret) reencrypt(ctx context.Context, secretsSrv *manager.Secre
 		return false
 	}
 
+	s.progress.setTotal(len(results))
+
 	var anyFailure bool
 
 	for _, result := range results {
 		result := result
+		var reencryptedCount int
 
 		err := sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
 			postableUserConfig, err := notifier.Load([]byte(result.AlertmanagerConfiguration))
@@ -246,6 +249,7 @@ func (s alertingSecret) reencrypt(ctx context.Context, secretsSrv *manager.Secre
 						}
 
 						gmr.SecureSettings[k] = base64.StdEncoding.EncodeToString(reencrypted)
+						reencryptedCount++
 					}
 				}
 			}
@@ -265,6 +269,7 @@ func (s alertingSecret) reencrypt(ctx context.Context, secretsSrv *manager.Secre
 			return nil
 		})
 
+		s.progress.itemDone(reencryptedCount, err)
 		if err != nil {
 			anyFailure = true
 		}
@@ -82,4 +82,12 @@ type Migrator interface {
 	// does not stop, but returns false as the first return (success or not)
 	// at the end of the process.
 	RollBackSecrets(ctx context.Context) (bool, error)
+	// ReEncryptAlertingSecrets starts re-encrypting the secure settings of the
+	// alerting contact points with the most recent available data key, in the
+	// background. It returns ErrReEncryptionInProgress if the previous run has
+	// not finished yet.
+	ReEncryptAlertingSecrets(ctx context.Context) error
+	// AlertingSecretsReEncryptionProgress returns the progress of the last run
+	// of ReEncryptAlertingSecrets.
+	AlertingSecretsReEncryptionProgress() ReEncryptionProgress
 }
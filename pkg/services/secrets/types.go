@@ -6,6 +6,7 @@ import (
 )
 
 var ErrDataKeyNotFound = errors.New("data key not found")
+var ErrReEncryptionInProgress = errors.New("secrets re-encryption already in progress")
 
 type DataKey struct {
 	Active        bool
@@ -35,3 +36,16 @@ func WithScope(scope string) EncryptionOptions {
 		return scope
 	}
 }
+
+// ReEncryptionProgress reports the progress of a secrets re-encryption running
+// in the background. Total, Processed and Failed count the re-encrypted items,
+// while Secrets counts the secrets these items hold.
+type ReEncryptionProgress struct {
+	Running    bool       `json:"running"`
+	Total      int        `json:"total"`
+	Processed  int        `json:"processed"`
+	Failed     int        `json:"failed"`
+	Secrets    int        `json:"secrets"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
@@ -29,4 +29,140 @@ func addCorrelationsMigrations(mg *Migrator) {
 	mg.AddMigration("add correlation config column", NewAddColumnMigration(correlationsV1, &Column{
 		Name: "config", Type: DB_Text, Nullable: true,
 	}))
+
+	correlationResolutionV1 := Table{
+		Name: "correlation_resolution",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "correlation_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "source_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "resolved_at", Type: DB_BigInt, Nullable: false},
+		},
+	}
+
+	mg.AddMigration("create correlation_resolution table v1", NewAddTableMigration(correlationResolutionV1))
+
+	correlationUsageV1 := Table{
+		Name: "correlation_usage",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "correlation_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "source_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "day", Type: DB_NVarchar, Length: 10, Nullable: false},
+			{Name: "resolutions", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"correlation_uid", "source_uid", "day"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create correlation_usage table v1", NewAddTableMigration(correlationUsageV1))
+	mg.AddMigration("add unique index correlation_usage.correlation_uid_source_uid_day", NewAddIndexMigration(correlationUsageV1, correlationUsageV1.Indices[0]))
+
+	mg.AddMigration("add correlation provisioned column", NewAddColumnMigration(correlationsV1, &Column{
+		Name: "provisioned", Type: DB_Bool, Nullable: false, Default: "0",
+	}))
+
+	correlationVersionV1 := Table{
+		Name: "correlation_version",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "correlation_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "source_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "version", Type: DB_Int, Nullable: false},
+			{Name: "action", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "restored_from", Type: DB_Int, Nullable: false, Default: "0"},
+			{Name: "target_uid", Type: DB_NVarchar, Length: 40, Nullable: true},
+			{Name: "label", Type: DB_Text, Nullable: false},
+			{Name: "description", Type: DB_Text, Nullable: false},
+			{Name: "config", Type: DB_Text, Nullable: true},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"correlation_uid", "source_uid", "version"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create correlation_version table v1", NewAddTableMigration(correlationVersionV1))
+	mg.AddMigration("add unique index correlation_version.correlation_uid_source_uid_version", NewAddIndexMigration(correlationVersionV1, correlationVersionV1.Indices[0]))
+
+	correlationUsageSummaryV1 := Table{
+		Name: "correlation_usage_summary",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "correlation_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "source_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "resolutions", Type: DB_BigInt, Nullable: false},
+			{Name: "last_resolved_at", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id"}},
+			{Cols: []string{"correlation_uid", "source_uid"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create correlation_usage_summary table v1", NewAddTableMigration(correlationUsageSummaryV1))
+	mg.AddMigration("add index correlation_usage_summary.org_id", NewAddIndexMigration(correlationUsageSummaryV1, correlationUsageSummaryV1.Indices[0]))
+	mg.AddMigration("add unique index correlation_usage_summary.correlation_uid_source_uid", NewAddIndexMigration(correlationUsageSummaryV1, correlationUsageSummaryV1.Indices[1]))
+
+	mg.AddMigration("add correlation order column", NewAddColumnMigration(correlationsV1, &Column{
+		Name: "order", Type: DB_Int, Nullable: false, Default: "0",
+	}))
+
+	// the correlations of a data source type have no source data source to belong to an org through
+	mg.AddMigration("add correlation org_id column", NewAddColumnMigration(correlationsV1, &Column{
+		Name: "org_id", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add correlation source_type column", NewAddColumnMigration(correlationsV1, &Column{
+		Name: "source_type", Type: DB_NVarchar, Length: 255, Nullable: false, Default: "''",
+	}))
+	mg.AddMigration("add index correlations.org_id_source_type", NewAddIndexMigration(correlationsV1, &Index{
+		Cols: []string{"org_id", "source_type"},
+	}))
+
+	mg.AddMigration("add correlation visibility column", NewAddColumnMigration(correlationsV1, &Column{
+		Name: "visibility", Type: DB_Text, Nullable: true,
+	}))
+
+	correlationTagV1 := Table{
+		Name: "correlation_tag",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "correlation_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "source_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "term", Type: DB_NVarchar, Length: 50, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "term"}},
+			{Cols: []string{"correlation_uid", "source_uid", "term"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create correlation_tag table v1", NewAddTableMigration(correlationTagV1))
+	mg.AddMigration("add index correlation_tag.org_id_term", NewAddIndexMigration(correlationTagV1, correlationTagV1.Indices[0]))
+	mg.AddMigration("add unique index correlation_tag.correlation_uid_source_uid_term", NewAddIndexMigration(correlationTagV1, correlationTagV1.Indices[1]))
+
+	// the correlations of data sources are stored with their org, so that the lists of an org use the indexes.
+	// Data source UIDs are only unique within an org, the correlations whose source UID exists in several orgs,
+	// or in none, keep org 0.
+	mg.AddMigration("set the org of the correlations of data sources", NewRawSQLMigration(`UPDATE correlation SET org_id =
+		(SELECT MIN(ds.org_id) FROM data_source AS ds WHERE ds.uid = correlation.source_uid)
+		WHERE org_id = 0 AND source_uid <> '' AND
+		(SELECT COUNT(*) FROM data_source AS ds WHERE ds.uid = correlation.source_uid) = 1`))
+	mg.AddMigration("add index correlations.org_id_source_uid", NewAddIndexMigration(correlationsV1, &Index{
+		Cols: []string{"org_id", "source_uid"},
+	}))
+	mg.AddMigration("add index correlations.org_id_target_uid", NewAddIndexMigration(correlationsV1, &Index{
+		Cols: []string{"org_id", "target_uid"},
+	}))
+	mg.AddMigration("add index correlation_tag.org_id_source_uid", NewAddIndexMigration(correlationTagV1, &Index{
+		Cols: []string{"org_id", "source_uid"},
+	}))
 }
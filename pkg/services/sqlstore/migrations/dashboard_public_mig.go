@@ -65,4 +65,147 @@ func addPublicDashboardMigration(mg *Migrator) {
 
 	// rename table
 	addTableRenameMigration(mg, "dashboard_public_config", "dashboard_public", "v2")
+
+	dashboardPublicV2 := Table{Name: "dashboard_public"}
+
+	mg.AddMigration("add dashboard_version column to dashboard_public", NewAddColumnMigration(dashboardPublicV2, &Column{
+		Name: "dashboard_version", Type: DB_Int, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add masking_rules column to dashboard_public", NewAddColumnMigration(dashboardPublicV2, &Column{
+		Name: "masking_rules", Type: DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add query_filters column to dashboard_public", NewAddColumnMigration(dashboardPublicV2, &Column{
+		Name: "query_filters", Type: DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add allow_indexing column to dashboard_public", NewAddColumnMigration(dashboardPublicV2, &Column{
+		Name: "allow_indexing", Type: DB_Bool, Nullable: true,
+	}))
+
+	mg.AddMigration("add require_challenge column to dashboard_public", NewAddColumnMigration(dashboardPublicV2, &Column{
+		Name: "require_challenge", Type: DB_Bool, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add terms_of_use column to dashboard_public", NewAddColumnMigration(dashboardPublicV2, &Column{
+		Name: "terms_of_use", Type: DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add share_mode column to dashboard_public", NewAddColumnMigration(dashboardPublicV2, &Column{
+		Name: "share_mode", Type: DB_NVarchar, Length: 32, Nullable: true,
+	}))
+
+	mg.AddMigration("add prerender_enabled column to dashboard_public", NewAddColumnMigration(dashboardPublicV2, &Column{
+		Name: "prerender_enabled", Type: DB_Bool, Nullable: false, Default: "0",
+	}))
+
+	var dashboardPublicSettingsV1 = Table{
+		Name: "dashboard_public_settings",
+		Columns: []*Column{
+			{Name: "org_id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true},
+			{Name: "allowed_datasources", Type: DB_Text, Nullable: true},
+			{Name: "updated_by", Type: DB_Int, Nullable: true},
+			{Name: "updated_at", Type: DB_DateTime, Nullable: true},
+		},
+	}
+
+	mg.AddMigration("create dashboard public settings table v1", NewAddTableMigration(dashboardPublicSettingsV1))
+
+	var dashboardPublicHistoryV1 = Table{
+		Name: "dashboard_public_history",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "action", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "is_enabled", Type: DB_Bool, Nullable: false, Default: "0"},
+			{Name: "time_settings", Type: DB_Text, Nullable: true},
+			{Name: "dashboard_version", Type: DB_Int, Nullable: false, Default: "0"},
+			{Name: "access_token", Type: DB_NVarchar, Length: 32, Nullable: false},
+			{Name: "created_by", Type: DB_Int, Nullable: false},
+			{Name: "created_at", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "dashboard_uid"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public history table v1", NewAddTableMigration(dashboardPublicHistoryV1))
+	addTableIndicesMigrations(mg, "v1", dashboardPublicHistoryV1)
+
+	var dashboardPublicPlaylistV1 = Table{
+		Name: "dashboard_public_playlist",
+		Columns: []*Column{
+			{Name: "uid", Type: DB_NVarchar, Length: 40, IsPrimaryKey: true},
+			{Name: "playlist_uid", Type: DB_NVarchar, Length: 80, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "is_enabled", Type: DB_Bool, Nullable: false, Default: "0"},
+			{Name: "access_token", Type: DB_NVarchar, Length: 32, Nullable: false},
+			{Name: "created_by", Type: DB_Int, Nullable: false},
+			{Name: "updated_by", Type: DB_Int, Nullable: true},
+			{Name: "created_at", Type: DB_DateTime, Nullable: false},
+			{Name: "updated_at", Type: DB_DateTime, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "playlist_uid"}, Type: UniqueIndex},
+			{Cols: []string{"access_token"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create dashboard public playlist table v1", NewAddTableMigration(dashboardPublicPlaylistV1))
+	addTableIndicesMigrations(mg, "v1", dashboardPublicPlaylistV1)
+
+	var dashboardPublicScopedTokenV1 = Table{
+		Name: "dashboard_public_scoped_token",
+		Columns: []*Column{
+			{Name: "uid", Type: DB_NVarchar, Length: 40, IsPrimaryKey: true},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "access_token", Type: DB_NVarchar, Length: 32, Nullable: false},
+			{Name: "panel_ids", Type: DB_Text, Nullable: false},
+			{Name: "time_settings", Type: DB_Text, Nullable: true},
+			{Name: "created_by", Type: DB_Int, Nullable: false},
+			{Name: "created_at", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"public_dashboard_uid"}},
+			{Cols: []string{"access_token"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create dashboard public scoped token table v1", NewAddTableMigration(dashboardPublicScopedTokenV1))
+	addTableIndicesMigrations(mg, "v1", dashboardPublicScopedTokenV1)
+
+	dashboardPublicScopedTokenV1Table := Table{Name: "dashboard_public_scoped_token"}
+
+	mg.AddMigration("add expires column to dashboard_public_scoped_token", NewAddColumnMigration(dashboardPublicScopedTokenV1Table, &Column{
+		Name: "expires", Type: DB_BigInt, Nullable: true,
+	}))
+
+	mg.AddMigration("add query_quota column to dashboard_public_scoped_token", NewAddColumnMigration(dashboardPublicScopedTokenV1Table, &Column{
+		Name: "query_quota", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add byte_quota column to dashboard_public_scoped_token", NewAddColumnMigration(dashboardPublicScopedTokenV1Table, &Column{
+		Name: "byte_quota", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add query_count column to dashboard_public_scoped_token", NewAddColumnMigration(dashboardPublicScopedTokenV1Table, &Column{
+		Name: "query_count", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add byte_count column to dashboard_public_scoped_token", NewAddColumnMigration(dashboardPublicScopedTokenV1Table, &Column{
+		Name: "byte_count", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add is_disabled column to dashboard_public_scoped_token", NewAddColumnMigration(dashboardPublicScopedTokenV1Table, &Column{
+		Name: "is_disabled", Type: DB_Bool, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add country_restrictions column to dashboard_public_settings", NewAddColumnMigration(dashboardPublicSettingsV1, &Column{
+		Name: "country_restrictions", Type: DB_Text, Nullable: true,
+	}))
 }
@@ -98,6 +98,8 @@ func (*OSSMigrations) AddMigration(mg *Migrator) {
 	ualert.UpdateRuleGroupIndexMigration(mg)
 	accesscontrol.AddManagedFolderAlertActionsRepeatMigration(mg)
 	accesscontrol.AddAdminOnlyMigration(mg)
+
+	addOrgPolicyMigrations(mg)
 }
 
 func addMigrationLogMigrations(mg *Migrator) {
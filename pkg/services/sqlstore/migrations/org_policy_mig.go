@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addOrgPolicyMigrations(mg *Migrator) {
+	orgPolicyV1 := Table{
+		Name: "org_policy",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "kind", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "value", Type: DB_Text, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "kind"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create org_policy table v1", NewAddTableMigration(orgPolicyV1))
+
+	mg.AddMigration("add unique index org_policy.org_id-kind", NewAddIndexMigration(orgPolicyV1, orgPolicyV1.Indices[0]))
+}
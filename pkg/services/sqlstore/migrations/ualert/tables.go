@@ -33,6 +33,8 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 	AddProvisioningMigrations(mg)
 
 	AddAlertImageMigrations(mg)
+
+	AddContactPointHistoryMigrations(mg)
 }
 
 // AddAlertDefinitionMigrations should not be modified.
@@ -410,3 +412,27 @@ func AddAlertImageMigrations(mg *migrator.Migrator) {
 		Postgres("ALTER TABLE alert_image ALTER COLUMN url TYPE VARCHAR(2048);").
 		Mysql("ALTER TABLE alert_image MODIFY url VARCHAR(2048) NOT NULL;"))
 }
+
+func AddContactPointHistoryMigrations(mg *migrator.Migrator) {
+	historyTable := migrator.Table{
+		Name: "alert_contact_point_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "type", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "action", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "changes", Type: migrator.DB_Text, Nullable: true},
+			{Name: "secure_settings_changed", Type: migrator.DB_Text, Nullable: true},
+			{Name: "user_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}},
+		},
+	}
+
+	mg.AddMigration("create alert_contact_point_history table", migrator.NewAddTableMigration(historyTable))
+	mg.AddMigration("add index on org_id and uid to alert_contact_point_history table", migrator.NewAddIndexMigration(historyTable, historyTable.Indices[0]))
+}
@@ -10,11 +10,17 @@ import (
 )
 
 const (
-	alertRuleTarget = "alert_rule"
-	dashboardTarget = "dashboard"
-	filesTarget     = "file"
+	alertRuleTarget       = "alert_rule"
+	dashboardTarget       = "dashboard"
+	filesTarget           = "file"
+	publicDashboardTarget = "dashboard_public"
+	correlationTarget     = "correlation"
 )
 
+// correlationsUsedSQL counts the correlations of an org, which belong to the org of their source data source,
+// or are stored with their org when they apply to a data source type
+const correlationsUsedSQL = "SELECT COUNT(*) AS count FROM correlation LEFT JOIN data_source ON correlation.source_uid = data_source.uid WHERE COALESCE(data_source.org_id, correlation.org_id)=?"
+
 type targetCount struct {
 	Count int64
 }
@@ -41,6 +47,13 @@ func (ss *SQLStore) GetOrgQuotaByTarget(ctx context.Context, query *models.GetOr
 			if query.Target == dashboardTarget {
 				rawSQL += fmt.Sprintf(" AND is_folder=%s", dialect.BooleanStr(false))
 			}
+			// only the enabled public dashboards are exposed
+			if query.Target == publicDashboardTarget {
+				rawSQL += fmt.Sprintf(" AND is_enabled=%s", dialect.BooleanStr(true))
+			}
+			if query.Target == correlationTarget {
+				rawSQL = correlationsUsedSQL
+			}
 
 			resp := make([]*targetCount, 0)
 			if err := sess.SQL(rawSQL, query.OrgId).Find(&resp); err != nil {
@@ -90,6 +103,13 @@ func (ss *SQLStore) GetOrgQuotas(ctx context.Context, query *models.GetOrgQuotas
 			if q.Target != alertRuleTarget || query.UnifiedAlertingEnabled {
 				// get quota used.
 				rawSQL := fmt.Sprintf("SELECT COUNT(*) as count from %s where org_id=?", dialect.Quote(q.Target))
+				// only the enabled public dashboards are exposed
+				if q.Target == publicDashboardTarget {
+					rawSQL += fmt.Sprintf(" AND is_enabled=%s", dialect.BooleanStr(true))
+				}
+				if q.Target == correlationTarget {
+					rawSQL = correlationsUsedSQL
+				}
 				resp := make([]*targetCount, 0)
 				if err := sess.SQL(rawSQL, q.OrgId).Find(&resp); err != nil {
 					return err
@@ -21,11 +21,13 @@ func TestIntegrationQuotaCommandsAndQueries(t *testing.T) {
 	setting.Quota = setting.QuotaSettings{
 		Enabled: true,
 		Org: &setting.OrgQuota{
-			User:       5,
-			Dashboard:  5,
-			DataSource: 5,
-			ApiKey:     5,
-			AlertRule:  5,
+			User:            5,
+			Dashboard:       5,
+			DataSource:      5,
+			ApiKey:          5,
+			AlertRule:       5,
+			PublicDashboard: 5,
+			Correlation:     5,
 		},
 		User: &setting.UserQuota{
 			Org: 5,
@@ -107,7 +109,7 @@ func TestIntegrationQuotaCommandsAndQueries(t *testing.T) {
 			err = sqlStore.GetOrgQuotas(context.Background(), &query)
 
 			require.NoError(t, err)
-			require.Len(t, query.Result, 5)
+			require.Len(t, query.Result, 7)
 			for _, res := range query.Result {
 				limit := int64(5) // default quota limit
 				used := int64(0)
@@ -140,6 +142,86 @@ func TestIntegrationQuotaCommandsAndQueries(t *testing.T) {
 		})
 	})
 
+	t.Run("Given public dashboards", func(t *testing.T) {
+		err := sqlStore.WithDbSession(context.Background(), func(sess *DBSession) error {
+			rawSQL := "INSERT INTO dashboard_public (uid, dashboard_uid, org_id, is_enabled, access_token, created_by, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+			if _, err := sess.Exec(rawSQL, "pubdash1", "dash1", 3, true, "token1", 1, time.Now()); err != nil {
+				return err
+			}
+			_, err := sess.Exec(rawSQL, "pubdash2", "dash2", 3, false, "token2", 1, time.Now())
+			return err
+		})
+		require.NoError(t, err)
+
+		t.Run("Should only count the enabled public dashboards", func(t *testing.T) {
+			query := models.GetOrgQuotaByTargetQuery{OrgId: 3, Target: publicDashboardTarget, Default: 5}
+			err = sqlStore.GetOrgQuotaByTarget(context.Background(), &query)
+
+			require.NoError(t, err)
+			require.Equal(t, int64(5), query.Result.Limit)
+			require.Equal(t, int64(1), query.Result.Used)
+		})
+
+		t.Run("Should only list the enabled public dashboards as used", func(t *testing.T) {
+			query := models.GetOrgQuotasQuery{OrgId: 3}
+			err = sqlStore.GetOrgQuotas(context.Background(), &query)
+			require.NoError(t, err)
+
+			var found bool
+			for _, res := range query.Result {
+				if res.Target == publicDashboardTarget {
+					found = true
+					require.Equal(t, int64(1), res.Used)
+				}
+			}
+			require.True(t, found)
+		})
+	})
+
+	t.Run("Given correlations", func(t *testing.T) {
+		err := sqlStore.WithDbSession(context.Background(), func(sess *DBSession) error {
+			dsSQL := "INSERT INTO data_source (org_id, version, type, name, access, url, basic_auth, is_default, json_data, created, updated, uid) VALUES (?, 1, 'loki', ?, 'proxy', '', ?, ?, '{}', ?, ?, ?)"
+			for _, ds := range []struct {
+				orgID int64
+				name  string
+			}{{4, "loki"}, {5, "other-loki"}} {
+				if _, err := sess.Exec(dsSQL, ds.orgID, ds.name, false, false, time.Now(), time.Now(), "loki"); err != nil {
+					return err
+				}
+			}
+			correlationSQL := "INSERT INTO correlation (uid, source_uid, label, description) VALUES (?, ?, '', '')"
+			for _, uid := range []string{"correlation1", "correlation2"} {
+				if _, err := sess.Exec(correlationSQL, uid, "loki"); err != nil {
+					return err
+				}
+			}
+			_, err := sess.Exec("INSERT INTO correlation (uid, source_uid, org_id, source_type, label, description) VALUES ('correlation3', '', 6, 'loki', '', '')")
+			return err
+		})
+		require.NoError(t, err)
+
+		t.Run("Should count the correlations of the data sources and data source types of the org", func(t *testing.T) {
+			query := models.GetOrgQuotaByTargetQuery{OrgId: 4, Target: correlationTarget, Default: 5}
+			err = sqlStore.GetOrgQuotaByTarget(context.Background(), &query)
+
+			require.NoError(t, err)
+			require.Equal(t, int64(5), query.Result.Limit)
+			require.Equal(t, int64(2), query.Result.Used)
+
+			query = models.GetOrgQuotaByTargetQuery{OrgId: 6, Target: correlationTarget, Default: 5}
+			err = sqlStore.GetOrgQuotaByTarget(context.Background(), &query)
+
+			require.NoError(t, err)
+			require.Equal(t, int64(1), query.Result.Used)
+
+			query = models.GetOrgQuotaByTargetQuery{OrgId: 7, Target: correlationTarget, Default: 5}
+			err = sqlStore.GetOrgQuotaByTarget(context.Background(), &query)
+
+			require.NoError(t, err)
+			require.Equal(t, int64(0), query.Result.Used)
+		})
+	})
+
 	t.Run("Given saved user quota for org", func(t *testing.T) {
 		userQuotaCmd := models.UpdateUserQuotaCmd{
 			UserId: userId,
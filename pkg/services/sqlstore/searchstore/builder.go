@@ -55,7 +55,10 @@ func (b *Builder) buildSelect() {
 			dashboard.folder_id,
 			folder.uid AS folder_uid,
 			folder.slug AS folder_slug,
-			folder.title AS folder_title `)
+			folder.title AS folder_title,
+			EXISTS (SELECT 1
+				FROM dashboard_public
+				WHERE dashboard_public.org_id = dashboard.org_id AND dashboard_public.dashboard_uid = dashboard.uid AND dashboard_public.is_enabled = ` + b.Dialect.BooleanStr(true) + `) AS is_public `)
 
 	for _, f := range b.Filters {
 		if f, ok := f.(FilterSelect); ok {
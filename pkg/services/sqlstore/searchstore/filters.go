@@ -78,6 +78,16 @@ func (f StarredFilter) Where() (string, []interface{}) {
 			 WHERE star.dashboard_id = dashboard.id AND star.user_id = ?) > 0`, []interface{}{f.UserId}
 }
 
+type PublicFilter struct {
+	Dialect migrator.Dialect
+}
+
+func (f PublicFilter) Where() (string, []interface{}) {
+	return `EXISTS (SELECT 1
+			 FROM dashboard_public
+			 WHERE dashboard_public.org_id = dashboard.org_id AND dashboard_public.dashboard_uid = dashboard.uid AND dashboard_public.is_enabled = ` + f.Dialect.BooleanStr(true) + `)`, nil
+}
+
 type TitleFilter struct {
 	Dialect migrator.Dialect
 	Title   string
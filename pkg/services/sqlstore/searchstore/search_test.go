@@ -4,6 +4,7 @@ package searchstore_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -149,6 +150,56 @@ func TestBuilder_Permissions(t *testing.T) {
 	assert.Len(t, res, 0)
 }
 
+func TestBuilder_Public(t *testing.T) {
+	user := &user.SignedInUser{
+		UserID:  1,
+		OrgID:   1,
+		OrgRole: org.RoleViewer,
+	}
+
+	db := setupTestEnvironment(t)
+	dashIds := createDashboards(t, db, 0, 3, user.OrgID)
+	require.Len(t, dashIds, 3)
+
+	// the first dashboard has an enabled public dashboard, the second
+	// one a disabled public dashboard and the third none at all.
+	createPublicDashboard(t, db, dashIds[0], true)
+	createPublicDashboard(t, db, dashIds[1], false)
+
+	search := func(filters ...interface{}) []dashboards.DashboardSearchProjection {
+		builder := &searchstore.Builder{
+			Filters: append([]interface{}{
+				searchstore.OrgFilter{OrgId: user.OrgID},
+				searchstore.TitleSorter{},
+			}, filters...),
+			Dialect: db.Dialect,
+		}
+
+		res := []dashboards.DashboardSearchProjection{}
+		err := db.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+			sql, params := builder.ToSQL(limit, page)
+			return sess.SQL(sql, params...).Find(&res)
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("flags dashboards with an enabled public dashboard", func(t *testing.T) {
+		res := search()
+		require.Len(t, res, 3)
+		assert.True(t, res[0].IsPublic)
+		assert.False(t, res[1].IsPublic)
+		assert.False(t, res[2].IsPublic)
+	})
+
+	t.Run("filters on dashboards with an enabled public dashboard", func(t *testing.T) {
+		res := search(searchstore.PublicFilter{Dialect: db.Dialect})
+		require.Len(t, res, 1)
+		assert.Equal(t, dashIds[0], res[0].ID)
+		assert.True(t, res[0].IsPublic)
+	})
+}
+
 func setupTestEnvironment(t *testing.T) *sqlstore.SQLStore {
 	t.Helper()
 	store := sqlstore.InitTestDB(t)
@@ -202,6 +253,21 @@ func createDashboards(t *testing.T, db *sqlstore.SQLStore, startID, endID int, o
 	return createdIds
 }
 
+func createPublicDashboard(t *testing.T, db *sqlstore.SQLStore, dashboardID int64, isEnabled bool) {
+	t.Helper()
+
+	err := db.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		dash := &models.Dashboard{}
+		if _, err := sess.ID(dashboardID).Get(dash); err != nil {
+			return err
+		}
+		_, err := sess.Exec(`INSERT INTO dashboard_public (uid, dashboard_uid, org_id, access_token, created_by, created_at, is_enabled) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			util.GenerateShortUID(), dash.Uid, dash.OrgId, util.GenerateShortUID(), 1, time.Now(), isEnabled)
+		return err
+	})
+	require.NoError(t, err)
+}
+
 // lexiCounter counts in a lexicographically sortable order.
 func lexiCounter(n int) string {
 	alphabet := "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -26,6 +27,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/network"
 	"github.com/grafana/grafana/pkg/util"
 
 	"github.com/gobwas/glob"
@@ -248,6 +250,7 @@ type Cfg struct {
 	// Security
 	DisableInitAdminCreation          bool
 	DisableBruteForceLoginProtection  bool
+	TrustedProxies                    []*net.IPNet
 	CookieSecure                      bool
 	CookieSameSiteDisabled            bool
 	CookieSameSiteMode                http.SameSite
@@ -464,6 +467,15 @@ type Cfg struct {
 
 	Search SearchSettings
 
+	PublicDashboards PublicDashboardsSettings
+
+	ExportBundles ExportBundlesSettings
+
+	Correlations CorrelationsSettings
+
+	// RateLimits holds the rate limits of the endpoints by name
+	RateLimits map[string]RateLimit
+
 	// Access Control
 	RBACEnabled         bool
 	RBACPermissionCache bool
@@ -1062,6 +1074,10 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	cfg.DashboardPreviews = readDashboardPreviewsSettings(iniFile)
 	cfg.Storage = readStorageSettings(iniFile)
 	cfg.Search = readSearchSettings(iniFile)
+	cfg.PublicDashboards = readPublicDashboardsSettings(iniFile)
+	cfg.ExportBundles = readExportBundlesSettings(iniFile, cfg.SecretKey)
+	cfg.Correlations = readCorrelationsSettings(iniFile)
+	cfg.RateLimits = readRateLimits(iniFile)
 
 	if VerifyEmailEnabled && !cfg.Smtp.Enabled {
 		cfg.Logger.Warn("require_email_validation is enabled but smtp is disabled")
@@ -1288,6 +1304,12 @@ func readSecuritySettings(iniFile *ini.File, cfg *Cfg) error {
 		DataProxyWhiteList[hostAndIP] = true
 	}
 
+	trustedProxies, err := network.ParseTrustedProxies(util.SplitString(valueAsString(security, "trusted_proxies", "")))
+	if err != nil {
+		return fmt.Errorf("invalid security.trusted_proxies: %w", err)
+	}
+	cfg.TrustedProxies = trustedProxies
+
 	// admin
 	cfg.DisableInitAdminCreation = security.Key("disable_initial_admin_creation").MustBool(false)
 	cfg.AdminUser = valueAsString(security, "admin_user", "")
@@ -0,0 +1,19 @@
+package setting
+
+import (
+	"gopkg.in/ini.v1"
+)
+
+type CorrelationsSettings struct {
+	// RejectDuplicates rejects the creation of a correlation with the same
+	// source, target, field and target query as an existing one, unless it's
+	// forced.
+	RejectDuplicates bool
+}
+
+func readCorrelationsSettings(iniFile *ini.File) CorrelationsSettings {
+	section := iniFile.Section("correlations")
+	return CorrelationsSettings{
+		RejectDuplicates: section.Key("reject_duplicates").MustBool(false),
+	}
+}
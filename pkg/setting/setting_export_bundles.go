@@ -0,0 +1,18 @@
+package setting
+
+import (
+	"gopkg.in/ini.v1"
+)
+
+type ExportBundlesSettings struct {
+	// SigningKey signs the exported bundles and verifies the imported ones.
+	// It has to be the same on the instances bundles are promoted between.
+	SigningKey string
+}
+
+func readExportBundlesSettings(iniFile *ini.File, secretKey string) ExportBundlesSettings {
+	section := iniFile.Section("export_bundles")
+	return ExportBundlesSettings{
+		SigningKey: section.Key("signing_key").MustString(secretKey),
+	}
+}
@@ -0,0 +1,53 @@
+package setting
+
+import (
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"gopkg.in/ini.v1"
+)
+
+type PublicDashboardsSettings struct {
+	// AllowIndexing lets search engines index the public dashboards that don't
+	// override it
+	AllowIndexing bool
+	// ChallengeDifficulty is the number of leading zero bits required in the
+	// hash of proof-of-work challenge solutions
+	ChallengeDifficulty int
+	// SharingWebhookUrl receives a request every time a public dashboard is
+	// enabled or disabled
+	SharingWebhookUrl string
+	// AlertStateEnabled exposes the alert state counts of the panels of public
+	// dashboards to public viewers
+	AlertStateEnabled bool
+	// GeoIPCountryHeader is the request header, set by a trusted reverse proxy,
+	// holding the country of public dashboard viewers
+	GeoIPCountryHeader string
+	// ExternalViewerHeaderName is the request header holding the JWT of the
+	// viewers of public dashboards shared with the external SSO share mode,
+	// verified with the [auth.jwt] settings
+	ExternalViewerHeaderName string
+	// PrerenderInterval is how often the queries of the public dashboards
+	// with pre-rendering enabled are executed in the background. Zero
+	// disables pre-rendering.
+	PrerenderInterval time.Duration
+	// HistoryRetention is how long the sharing history of public dashboards
+	// is kept. Zero keeps it forever.
+	HistoryRetention time.Duration
+}
+
+func readPublicDashboardsSettings(iniFile *ini.File) PublicDashboardsSettings {
+	section := iniFile.Section("public_dashboards")
+	// invalid retentions keep the history forever rather than deleting it
+	historyRetention, _ := gtime.ParseDuration(section.Key("history_retention").MustString("0"))
+	return PublicDashboardsSettings{
+		AllowIndexing:            section.Key("allow_indexing").MustBool(false),
+		ChallengeDifficulty:      section.Key("challenge_difficulty").MustInt(16),
+		SharingWebhookUrl:        section.Key("sharing_webhook_url").MustString(""),
+		AlertStateEnabled:        section.Key("alert_state_enabled").MustBool(false),
+		GeoIPCountryHeader:       section.Key("geoip_country_header").MustString(""),
+		ExternalViewerHeaderName: section.Key("external_viewer_header_name").MustString("X-Grafana-External-Viewer-Token"),
+		PrerenderInterval:        section.Key("prerender_interval").MustDuration(0),
+		HistoryRetention:         historyRetention,
+	}
+}
@@ -5,11 +5,13 @@ import (
 )
 
 type OrgQuota struct {
-	User       int64 `target:"org_user"`
-	DataSource int64 `target:"data_source"`
-	Dashboard  int64 `target:"dashboard"`
-	ApiKey     int64 `target:"api_key"`
-	AlertRule  int64 `target:"alert_rule"`
+	User            int64 `target:"org_user"`
+	DataSource      int64 `target:"data_source"`
+	Dashboard       int64 `target:"dashboard"`
+	ApiKey          int64 `target:"api_key"`
+	AlertRule       int64 `target:"alert_rule"`
+	PublicDashboard int64 `target:"dashboard_public"`
+	Correlation     int64 `target:"correlation"`
 }
 
 type UserQuota struct {
@@ -17,14 +19,19 @@ type UserQuota struct {
 }
 
 type GlobalQuota struct {
-	Org        int64 `target:"org"`
-	User       int64 `target:"user"`
-	DataSource int64 `target:"data_source"`
-	Dashboard  int64 `target:"dashboard"`
-	ApiKey     int64 `target:"api_key"`
-	Session    int64 `target:"-"`
-	AlertRule  int64 `target:"alert_rule"`
-	File       int64 `target:"file"`
+	Org         int64 `target:"org"`
+	User        int64 `target:"user"`
+	DataSource  int64 `target:"data_source"`
+	Dashboard   int64 `target:"dashboard"`
+	ApiKey      int64 `target:"api_key"`
+	Session     int64 `target:"-"`
+	AlertRule   int64 `target:"alert_rule"`
+	File        int64 `target:"file"`
+	Correlation int64 `target:"correlation"`
+}
+
+type DataSourceQuota struct {
+	Correlation int64 `target:"correlation"`
 }
 
 func (q *OrgQuota) ToMap() map[string]int64 {
@@ -56,10 +63,11 @@ func quotaToMap(q interface{}) map[string]int64 {
 }
 
 type QuotaSettings struct {
-	Enabled bool
-	Org     *OrgQuota
-	User    *UserQuota
-	Global  *GlobalQuota
+	Enabled    bool
+	Org        *OrgQuota
+	User       *UserQuota
+	Global     *GlobalQuota
+	DataSource *DataSourceQuota
 }
 
 func (cfg *Cfg) readQuotaSettings() {
@@ -75,11 +83,13 @@ func (cfg *Cfg) readQuotaSettings() {
 	}
 	// per ORG Limits
 	Quota.Org = &OrgQuota{
-		User:       quota.Key("org_user").MustInt64(10),
-		DataSource: quota.Key("org_data_source").MustInt64(10),
-		Dashboard:  quota.Key("org_dashboard").MustInt64(10),
-		ApiKey:     quota.Key("org_api_key").MustInt64(10),
-		AlertRule:  alertOrgQuota,
+		User:            quota.Key("org_user").MustInt64(10),
+		DataSource:      quota.Key("org_data_source").MustInt64(10),
+		Dashboard:       quota.Key("org_dashboard").MustInt64(10),
+		ApiKey:          quota.Key("org_api_key").MustInt64(10),
+		AlertRule:       alertOrgQuota,
+		PublicDashboard: quota.Key("org_public_dashboard").MustInt64(10),
+		Correlation:     quota.Key("org_correlation").MustInt64(100),
 	}
 
 	// per User limits
@@ -89,14 +99,20 @@ func (cfg *Cfg) readQuotaSettings() {
 
 	// Global Limits
 	Quota.Global = &GlobalQuota{
-		User:       quota.Key("global_user").MustInt64(-1),
-		Org:        quota.Key("global_org").MustInt64(-1),
-		DataSource: quota.Key("global_data_source").MustInt64(-1),
-		Dashboard:  quota.Key("global_dashboard").MustInt64(-1),
-		ApiKey:     quota.Key("global_api_key").MustInt64(-1),
-		Session:    quota.Key("global_session").MustInt64(-1),
-		File:       quota.Key("global_file").MustInt64(-1),
-		AlertRule:  alertGlobalQuota,
+		User:        quota.Key("global_user").MustInt64(-1),
+		Org:         quota.Key("global_org").MustInt64(-1),
+		DataSource:  quota.Key("global_data_source").MustInt64(-1),
+		Dashboard:   quota.Key("global_dashboard").MustInt64(-1),
+		ApiKey:      quota.Key("global_api_key").MustInt64(-1),
+		Session:     quota.Key("global_session").MustInt64(-1),
+		File:        quota.Key("global_file").MustInt64(-1),
+		AlertRule:   alertGlobalQuota,
+		Correlation: quota.Key("global_correlation").MustInt64(-1),
+	}
+
+	// per data source limits
+	Quota.DataSource = &DataSourceQuota{
+		Correlation: quota.Key("data_source_correlation").MustInt64(-1),
 	}
 
 	cfg.Quota = Quota
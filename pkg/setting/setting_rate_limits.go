@@ -0,0 +1,38 @@
+package setting
+
+import (
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+const rateLimitSectionPrefix = "rate_limit."
+
+// RateLimit is the token bucket of a rate limit: Burst requests can be made
+// at once, and the bucket refills at Rate requests per second.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// readRateLimits reads the [rate_limit.<name>] sections. A rate of 0 disables
+// the rate limit.
+func readRateLimits(iniFile *ini.File) map[string]RateLimit {
+	limits := make(map[string]RateLimit)
+	for _, section := range iniFile.Sections() {
+		name := strings.TrimPrefix(section.Name(), rateLimitSectionPrefix)
+		if name == section.Name() || name == "" {
+			continue
+		}
+
+		limit := RateLimit{
+			Rate:  section.Key("rate").MustFloat64(0),
+			Burst: section.Key("burst").MustInt(1),
+		}
+		if limit.Burst < 1 {
+			limit.Burst = 1
+		}
+		limits[name] = limit
+	}
+	return limits
+}
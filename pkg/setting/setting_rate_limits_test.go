@@ -0,0 +1,33 @@
+package setting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestReadRateLimits(t *testing.T) {
+	cfg := NewCfg()
+	err := cfg.Load(CommandLineArgs{HomePath: "../../", Config: "../../conf/defaults.ini"})
+	require.NoError(t, err)
+
+	// It sets the correct defaults, the rate limits being disabled.
+	require.Equal(t, RateLimit{Rate: 0, Burst: 200}, cfg.RateLimits["public_dashboards"])
+	require.Equal(t, RateLimit{Rate: 0, Burst: 50}, cfg.RateLimits["correlations"])
+	require.Equal(t, RateLimit{Rate: 0, Burst: 10}, cfg.RateLimits["notification_tests"])
+
+	// It reads custom rate limits, and burst is at least 1.
+	iniFile, err := ini.Load([]byte(`
+[rate_limit.custom]
+rate = 2
+burst = 0
+
+[rate_limits]
+rate = 3
+`))
+	require.NoError(t, err)
+
+	limits := readRateLimits(iniFile)
+	require.Equal(t, map[string]RateLimit{"custom": {Rate: 2, Burst: 1}}, limits)
+}
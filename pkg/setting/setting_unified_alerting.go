@@ -52,6 +52,9 @@ const (
 	screenshotsDefaultCapture               = false
 	screenshotsDefaultMaxConcurrent         = 5
 	screenshotsDefaultUploadImageStorage    = false
+	dispatchDefaultMaxConcurrent            = 0
+	dispatchDefaultMaxQueuedInformational   = 100
+	dispatchDefaultPagingTypes              = "pagerduty, opsgenie, victorops, pushover, threema"
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
@@ -83,6 +86,10 @@ type UnifiedAlertingSettings struct {
 	DefaultRuleEvaluationInterval time.Duration
 	Screenshots                   UnifiedAlertingScreenshotSettings
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
+	// ReceiverRollouts maps the receiver types being rolled out to the orgs
+	// they are available to before their feature toggle is enabled.
+	ReceiverRollouts map[string]map[int64]struct{}
+	Dispatch         UnifiedAlertingDispatchSettings
 }
 
 type UnifiedAlertingScreenshotSettings struct {
@@ -91,6 +98,16 @@ type UnifiedAlertingScreenshotSettings struct {
 	UploadExternalImageStorage bool
 }
 
+type UnifiedAlertingDispatchSettings struct {
+	// MaxConcurrentNotifications is the number of notifications sent at the same time. 0 means no limit.
+	MaxConcurrentNotifications int64
+	// MaxQueuedInformational is the number of informational notifications waiting to be sent
+	// before new ones are shed.
+	MaxQueuedInformational int64
+	// PagingTypes are the contact point types delivered first and never shed.
+	PagingTypes map[string]struct{}
+}
+
 type UnifiedAlertingReservedLabelSettings struct {
 	DisabledLabels map[string]struct{}
 }
@@ -294,6 +311,34 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	}
 	uaCfg.ReservedLabels = uaCfgReservedLabels
 
+	receiverRollouts := iniFile.Section("unified_alerting.receiver_rollouts")
+	uaCfg.ReceiverRollouts = make(map[string]map[int64]struct{})
+	for _, key := range receiverRollouts.Keys() {
+		orgs := make(map[int64]struct{})
+		for _, org := range util.SplitString(key.String()) {
+			orgID, err := strconv.ParseInt(org, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid org ID for the rollout of receiver %s: %w", key.Name(), err)
+			}
+			orgs[orgID] = struct{}{}
+		}
+		uaCfg.ReceiverRollouts[strings.ToLower(key.Name())] = orgs
+	}
+
+	dispatch := iniFile.Section("unified_alerting.dispatch")
+	uaCfgDispatch := UnifiedAlertingDispatchSettings{
+		MaxConcurrentNotifications: dispatch.Key("max_concurrent_notifications").MustInt64(dispatchDefaultMaxConcurrent),
+		MaxQueuedInformational:     dispatch.Key("max_queued_informational").MustInt64(dispatchDefaultMaxQueuedInformational),
+		PagingTypes:                make(map[string]struct{}),
+	}
+	if uaCfgDispatch.MaxConcurrentNotifications < 0 {
+		return fmt.Errorf("value of setting 'max_concurrent_notifications' should be greater than or equal to 0")
+	}
+	for _, t := range util.SplitString(valueAsString(dispatch, "paging_types", dispatchDefaultPagingTypes)) {
+		uaCfgDispatch.PagingTypes[strings.ToLower(t)] = struct{}{}
+	}
+	uaCfg.Dispatch = uaCfgDispatch
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
@@ -39,6 +39,35 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 		require.Len(t, cfg.UnifiedAlerting.HAPeers, 3)
 		require.ElementsMatch(t, []string{"hostname1:9090", "hostname2:9090", "hostname3:9090"}, cfg.UnifiedAlerting.HAPeers)
 	}
+
+	// With receiver rollouts set, it correctly parses the allowed orgs.
+	{
+		require.Empty(t, cfg.UnifiedAlerting.ReceiverRollouts)
+		s, err := cfg.Raw.NewSection("unified_alerting.receiver_rollouts")
+		require.NoError(t, err)
+		_, err = s.NewKey("Airflow", "1, 12")
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, map[string]map[int64]struct{}{"airflow": {1: {}, 12: {}}}, cfg.UnifiedAlerting.ReceiverRollouts)
+	}
+
+	// With dispatch settings set, it correctly parses the paging types.
+	{
+		require.Equal(t, int64(0), cfg.UnifiedAlerting.Dispatch.MaxConcurrentNotifications)
+		require.Contains(t, cfg.UnifiedAlerting.Dispatch.PagingTypes, "pagerduty")
+		s, err := cfg.Raw.NewSection("unified_alerting.dispatch")
+		require.NoError(t, err)
+		_, err = s.NewKey("max_concurrent_notifications", "10")
+		require.NoError(t, err)
+		_, err = s.NewKey("paging_types", "PagerDuty, line")
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, int64(10), cfg.UnifiedAlerting.Dispatch.MaxConcurrentNotifications)
+		require.Equal(t, int64(100), cfg.UnifiedAlerting.Dispatch.MaxQueuedInformational)
+		require.Equal(t, map[string]struct{}{"pagerduty": {}, "line": {}}, cfg.UnifiedAlerting.Dispatch.PagingTypes)
+	}
 }
 
 func TestUnifiedAlertingSettings(t *testing.T) {
@@ -6,18 +6,23 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/server"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/correlations"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/grafana/grafana/pkg/util"
 	"github.com/stretchr/testify/require"
 )
 
 type errorResponseBody struct {
-	Message string `json:"message"`
-	Error   string `json:"error"`
+	Message   string `json:"message"`
+	MessageID string `json:"messageId"`
+	Error     string `json:"error"`
 }
 
 type TestContext struct {
@@ -97,6 +102,24 @@ func (c TestContext) Patch(params PatchParams) *http.Response {
 	return resp
 }
 
+type PutParams struct {
+	url  string
+	body string
+	user User
+}
+
+func (c TestContext) Put(params PutParams) *http.Response {
+	c.t.Helper()
+
+	req, err := http.NewRequest(http.MethodPut, c.getURL(params.url, params.user), bytes.NewBuffer([]byte(params.body)))
+	require.NoError(c.t, err)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(c.t, err)
+
+	return resp
+}
+
 type DeleteParams struct {
 	url  string
 	user User
@@ -128,13 +151,40 @@ func (c TestContext) getURL(url string, user User) string {
 	)
 }
 
-func (c TestContext) createUser(cmd user.CreateUserCommand) {
+func (c TestContext) createUser(cmd user.CreateUserCommand) *user.User {
 	c.t.Helper()
 
 	c.env.SQLStore.Cfg.AutoAssignOrg = true
 	c.env.SQLStore.Cfg.AutoAssignOrgId = 1
 
-	_, err := c.env.SQLStore.CreateUser(context.Background(), cmd)
+	u, err := c.env.SQLStore.CreateUser(context.Background(), cmd)
+	require.NoError(c.t, err)
+	return u
+}
+
+// grantPermission assigns the user of org 1 a custom role with the permission.
+// Grant it before the first request of the user, whose permissions are cached.
+func (c TestContext) grantPermission(userID int64, action string, scope string) {
+	c.t.Helper()
+
+	err := c.env.SQLStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		now := time.Now()
+		role := accesscontrol.Role{
+			OrgID:   1,
+			UID:     util.GenerateShortUID(),
+			Name:    fmt.Sprintf("custom:%s:%s", action, scope),
+			Created: now,
+			Updated: now,
+		}
+		if _, err := sess.Insert(&role); err != nil {
+			return err
+		}
+		if _, err := sess.Insert(&accesscontrol.Permission{RoleID: role.ID, Action: action, Scope: scope, Created: now, Updated: now}); err != nil {
+			return err
+		}
+		_, err := sess.Insert(&accesscontrol.UserRole{OrgID: 1, RoleID: role.ID, UserID: userID, Created: now})
+		return err
+	})
 	require.NoError(c.t, err)
 }
 
@@ -0,0 +1,233 @@
+package correlations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationBulkCorrelations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := NewTestEnv(t)
+
+	adminUser := User{
+		username: "admin",
+		password: "admin",
+	}
+	editorUser := User{
+		username: "editor",
+		password: "editor",
+	}
+
+	ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleEditor),
+		Password:       editorUser.password,
+		Login:          editorUser.username,
+	})
+	ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleAdmin),
+		Password:       adminUser.password,
+		Login:          adminUser.username,
+	})
+
+	createDsCommand := &datasources.AddDataSourceCommand{
+		Name:     "read-only",
+		Type:     "loki",
+		ReadOnly: true,
+		OrgId:    1,
+	}
+	ctx.createDs(createDsCommand)
+	readOnlyDS := createDsCommand.Result.Uid
+
+	createDsCommand = &datasources.AddDataSourceCommand{
+		Name:  "writable",
+		Type:  "loki",
+		OrgId: 1,
+	}
+	ctx.createDs(createDsCommand)
+	writableDs := createDsCommand.Result.Uid
+	writableDsOrgId := createDsCommand.Result.OrgId
+
+	t.Run("non org admin shouldn't be able to bulk create correlations", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  "/api/datasources/correlations/bulk",
+			body: `{"correlations": []}`,
+			user: editorUser,
+		})
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("an empty batch should result in a 400", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  "/api/datasources/correlations/bulk",
+			body: `{"correlations": []}`,
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response errorResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "correlations.invalidBulkCommand", response.MessageID)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should create all the correlations", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url: "/api/datasources/correlations/bulk",
+			body: fmt.Sprintf(`{"correlations": [
+				{"sourceUID": "%[1]s", "targetUID": "%[1]s", "label": "first", "config": {"field": "traceId", "type": "query", "target": {}}},
+				{"sourceUID": "%[1]s", "targetUID": "%[2]s", "label": "second", "config": {"field": "traceId", "type": "query", "target": {}}}
+			]}`, writableDs, readOnlyDS),
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response correlations.BulkCreateCorrelationsResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "Correlations created", response.Message)
+		require.Len(t, response.Result, 2)
+		require.Equal(t, "first", response.Result[0].Label)
+		require.Equal(t, writableDs, response.Result[0].SourceUID)
+		require.Equal(t, "second", response.Result[1].Label)
+		require.Equal(t, readOnlyDS, *response.Result[1].TargetUID)
+		require.Empty(t, response.Errors)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should create none of the correlations if one fails", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url: "/api/datasources/correlations/bulk",
+			body: fmt.Sprintf(`{"correlations": [
+				{"sourceUID": "%[1]s", "targetUID": "%[1]s", "label": "valid", "config": {"field": "traceId", "type": "query", "target": {}}},
+				{"sourceUID": "%[2]s", "targetUID": "%[1]s", "label": "read-only", "config": {"field": "traceId", "type": "query", "target": {}}}
+			]}`, writableDs, readOnlyDS),
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response correlations.BulkCreateCorrelationsResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "No correlations created", response.Message)
+		require.Len(t, response.Errors, 1)
+		require.Equal(t, 1, response.Errors[0].Index)
+		require.Equal(t, readOnlyDS, response.Errors[0].SourceUID)
+		require.Equal(t, "correlations.sourceDataSourceReadOnly", response.Errors[0].MessageID)
+		require.NoError(t, res.Body.Close())
+
+		res = ctx.Get(GetParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations", writableDs),
+			user: adminUser,
+		})
+		responseBody, err = io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var result []correlations.Correlation
+		err = json.Unmarshal(responseBody, &result)
+		require.NoError(t, err)
+		for _, c := range result {
+			require.NotEqual(t, "valid", c.Label)
+		}
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should delete all the correlations", func(t *testing.T) {
+		first := ctx.createCorrelation(correlations.CreateCorrelationCommand{
+			SourceUID: writableDs,
+			TargetUID: &writableDs,
+			OrgId:     writableDsOrgId,
+		})
+		second := ctx.createCorrelation(correlations.CreateCorrelationCommand{
+			SourceUID: writableDs,
+			TargetUID: &readOnlyDS,
+			OrgId:     writableDsOrgId,
+		})
+
+		res := ctx.Post(PostParams{
+			url: "/api/datasources/correlations/bulk/delete",
+			body: fmt.Sprintf(`{"correlations": [{"sourceUID": "%s", "uid": "%s"}, {"sourceUID": "%s", "uid": "%s"}]}`,
+				first.SourceUID, first.UID, second.SourceUID, second.UID),
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response correlations.BulkDeleteCorrelationsResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "Correlations deleted", response.Message)
+		require.NoError(t, res.Body.Close())
+
+		res = ctx.Get(GetParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", first.SourceUID, first.UID),
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should delete none of the correlations if one fails", func(t *testing.T) {
+		correlation := ctx.createCorrelation(correlations.CreateCorrelationCommand{
+			SourceUID: writableDs,
+			TargetUID: &writableDs,
+			OrgId:     writableDsOrgId,
+		})
+
+		res := ctx.Post(PostParams{
+			url: "/api/datasources/correlations/bulk/delete",
+			body: fmt.Sprintf(`{"correlations": [{"sourceUID": "%s", "uid": "%s"}, {"sourceUID": "%s", "uid": "nonexistent-correlation-uid"}]}`,
+				correlation.SourceUID, correlation.UID, writableDs),
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response correlations.BulkDeleteCorrelationsResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "No correlations deleted", response.Message)
+		require.Len(t, response.Errors, 1)
+		require.Equal(t, 1, response.Errors[0].Index)
+		require.Equal(t, "nonexistent-correlation-uid", response.Errors[0].UID)
+		require.Equal(t, "correlations.notFound", response.Errors[0].MessageID)
+		require.NoError(t, res.Body.Close())
+
+		res = ctx.Get(GetParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", correlation.SourceUID, correlation.UID),
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
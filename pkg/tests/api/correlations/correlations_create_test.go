@@ -91,7 +91,7 @@ func TestIntegrationCreateCorrelation(t *testing.T) {
 		err = json.Unmarshal(responseBody, &response)
 		require.NoError(t, err)
 
-		require.Contains(t, response.Message, "Permissions needed: datasources:write")
+		require.Contains(t, response.Message, "Permissions needed: any of correlations:create, datasources:write")
 
 		require.NoError(t, res.Body.Close())
 	})
@@ -139,7 +139,7 @@ func TestIntegrationCreateCorrelation(t *testing.T) {
 		require.NoError(t, err)
 
 		require.Equal(t, "Data source not found", response.Message)
-		require.Equal(t, correlations.ErrSourceDataSourceDoesNotExists.Error(), response.Error)
+		require.Equal(t, "correlations.sourceDataSourceNotFound", response.MessageID)
 
 		require.NoError(t, res.Body.Close())
 	})
@@ -167,7 +167,7 @@ func TestIntegrationCreateCorrelation(t *testing.T) {
 		require.NoError(t, err)
 
 		require.Equal(t, "Data source not found", response.Message)
-		require.Equal(t, correlations.ErrTargetDataSourceDoesNotExists.Error(), response.Error)
+		require.Equal(t, "correlations.targetDataSourceNotFound", response.MessageID)
 
 		require.NoError(t, res.Body.Close())
 	})
@@ -195,7 +195,7 @@ func TestIntegrationCreateCorrelation(t *testing.T) {
 		require.NoError(t, err)
 
 		require.Equal(t, "Data source is read only", response.Message)
-		require.Equal(t, correlations.ErrSourceDataSourceReadOnly.Error(), response.Error)
+		require.Equal(t, "correlations.sourceDataSourceReadOnly", response.MessageID)
 
 		require.NoError(t, res.Body.Close())
 	})
@@ -265,7 +265,7 @@ func TestIntegrationCreateCorrelation(t *testing.T) {
 		require.Equal(t, description, response.Result.Description)
 		require.Equal(t, label, response.Result.Label)
 		require.Equal(t, configType, response.Result.Config.Type)
-		require.Equal(t, fieldName, response.Result.Config.Field)
+		require.Equal(t, correlations.CorrelationFields{fieldName}, response.Result.Config.Field)
 		require.Equal(t, map[string]interface{}{"expr": "foo"}, response.Result.Config.Target)
 
 		require.NoError(t, res.Body.Close())
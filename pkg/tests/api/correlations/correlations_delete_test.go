@@ -1,6 +1,7 @@
 package correlations
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/correlations"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/stretchr/testify/require"
 )
@@ -90,7 +92,7 @@ func TestIntegrationDeleteCorrelation(t *testing.T) {
 		err = json.Unmarshal(responseBody, &response)
 		require.NoError(t, err)
 
-		require.Contains(t, response.Message, "Permissions needed: datasources:write")
+		require.Contains(t, response.Message, "Permissions needed: any of correlations:delete, datasources:write")
 
 		require.NoError(t, res.Body.Close())
 	})
@@ -111,7 +113,7 @@ func TestIntegrationDeleteCorrelation(t *testing.T) {
 		require.NoError(t, err)
 
 		require.Equal(t, "Data source not found", response.Message)
-		require.Equal(t, correlations.ErrSourceDataSourceDoesNotExists.Error(), response.Error)
+		require.Equal(t, "correlations.sourceDataSourceNotFound", response.MessageID)
 
 		require.NoError(t, res.Body.Close())
 	})
@@ -131,7 +133,7 @@ func TestIntegrationDeleteCorrelation(t *testing.T) {
 		require.NoError(t, err)
 
 		require.Equal(t, "Correlation not found", response.Message)
-		require.Equal(t, correlations.ErrCorrelationNotFound.Error(), response.Error)
+		require.Equal(t, "correlations.notFound", response.MessageID)
 
 		require.NoError(t, res.Body.Close())
 	})
@@ -151,7 +153,7 @@ func TestIntegrationDeleteCorrelation(t *testing.T) {
 		require.NoError(t, err)
 
 		require.Equal(t, "Data source is read only", response.Message)
-		require.Equal(t, correlations.ErrSourceDataSourceReadOnly.Error(), response.Error)
+		require.Equal(t, "correlations.sourceDataSourceReadOnly", response.MessageID)
 
 		require.NoError(t, res.Body.Close())
 	})
@@ -220,3 +222,89 @@ func TestIntegrationDeleteCorrelation(t *testing.T) {
 		require.Equal(t, http.StatusNotFound, res.StatusCode)
 	})
 }
+
+func TestIntegrationCleanupCorrelations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := NewTestEnv(t)
+
+	grafanaAdminUser := User{
+		username: "grafana-admin",
+		password: "grafana-admin",
+	}
+	adminUser := User{
+		username: "admin",
+		password: "admin",
+	}
+
+	ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleAdmin),
+		Password:       grafanaAdminUser.password,
+		Login:          grafanaAdminUser.username,
+		IsAdmin:        true,
+	})
+	ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleAdmin),
+		Password:       adminUser.password,
+		Login:          adminUser.username,
+	})
+
+	createDsCommand := &datasources.AddDataSourceCommand{
+		Name:  "loki",
+		Type:  "loki",
+		OrgId: 1,
+	}
+	ctx.createDs(createDsCommand)
+	dataSource := createDsCommand.Result
+
+	correlation := ctx.createCorrelation(correlations.CreateCorrelationCommand{
+		SourceUID: dataSource.Uid,
+		TargetUID: &dataSource.Uid,
+		OrgId:     dataSource.OrgId,
+	})
+	deletedUID := "deleted-uid"
+	err := ctx.env.SQLStore.WithDbSession(context.Background(), func(session *sqlstore.DBSession) error {
+		_, err := session.Insert(
+			&correlations.Correlation{UID: "orphaned-source", SourceUID: deletedUID, TargetUID: &dataSource.Uid},
+			&correlations.Correlation{UID: "orphaned-target", SourceUID: dataSource.Uid, TargetUID: &deletedUID},
+		)
+		return err
+	})
+	require.NoError(t, err)
+
+	t.Run("non Grafana admin shouldn't be able to clean up correlations", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  "/api/datasources/correlations/cleanup",
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should delete the orphaned correlations", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  "/api/datasources/correlations/cleanup",
+			user: grafanaAdminUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response correlations.CleanupCorrelationsResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "Orphaned correlations deleted", response.Message)
+		require.Equal(t, int64(2), response.Deleted)
+		require.NoError(t, res.Body.Close())
+
+		res = ctx.Get(GetParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", correlation.SourceUID, correlation.UID),
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
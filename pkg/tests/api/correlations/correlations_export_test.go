@@ -0,0 +1,149 @@
+package correlations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationExportImportCorrelations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := NewTestEnv(t)
+
+	adminUser := User{
+		username: "admin",
+		password: "admin",
+	}
+	ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleAdmin),
+		Password:       adminUser.password,
+		Login:          adminUser.username,
+	})
+
+	createDsCommand := &datasources.AddDataSourceCommand{
+		Name:  "Loki",
+		Type:  "loki",
+		OrgId: 1,
+	}
+	ctx.createDs(createDsCommand)
+	sourceDs := createDsCommand.Result.Uid
+
+	createDsCommand = &datasources.AddDataSourceCommand{
+		Name:  "Tempo",
+		Type:  "tempo",
+		OrgId: 1,
+	}
+	ctx.createDs(createDsCommand)
+	targetDs := createDsCommand.Result.Uid
+
+	createDsCommand = &datasources.AddDataSourceCommand{
+		Name:  "Tempo prod",
+		Type:  "tempo",
+		OrgId: 1,
+	}
+	ctx.createDs(createDsCommand)
+	prodTargetDs := createDsCommand.Result.Uid
+
+	ctx.createCorrelation(correlations.CreateCorrelationCommand{
+		SourceUID: sourceDs,
+		TargetUID: &targetDs,
+		OrgId:     1,
+		Label:     "logs to traces",
+		Config:    correlations.CorrelationConfig{Field: correlations.CorrelationFields{"traceId"}, Type: correlations.ConfigTypeQuery},
+	})
+
+	var bundle correlations.CorrelationsBundle
+	t.Run("should export the correlations with the data source names", func(t *testing.T) {
+		res := ctx.Get(GetParams{
+			url:  "/api/datasources/correlations/export",
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		err = json.Unmarshal(responseBody, &bundle)
+		require.NoError(t, err)
+
+		require.Len(t, bundle.Correlations, 1)
+		require.Equal(t, "Loki", bundle.Correlations[0].SourceName)
+		require.Equal(t, "Tempo", *bundle.Correlations[0].TargetName)
+		require.Equal(t, "logs to traces", bundle.Correlations[0].Label)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should import the bundle with remapped data sources", func(t *testing.T) {
+		body, err := json.Marshal(correlations.ImportCorrelationsCommand{
+			CorrelationsBundle: bundle,
+			DataSources:        map[string]string{"Tempo": prodTargetDs},
+		})
+		require.NoError(t, err)
+
+		res := ctx.Post(PostParams{
+			url:  "/api/datasources/correlations/import",
+			body: string(body),
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response correlations.BulkCreateCorrelationsResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "Correlations imported", response.Message)
+		require.Len(t, response.Result, 1)
+		require.Equal(t, sourceDs, response.Result[0].SourceUID)
+		require.Equal(t, prodTargetDs, *response.Result[0].TargetUID)
+		require.Equal(t, "logs to traces", response.Result[0].Label)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("unknown data source names should result in a 404", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  "/api/datasources/correlations/import",
+			body: `{"correlations": [{"sourceName": "Unknown", "label": "unknown", "config": {"field": "id", "type": "external", "target": {"url": "https://example.com"}}}]}`,
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response correlations.BulkCreateCorrelationsResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "No correlations imported", response.Message)
+		require.Len(t, response.Errors, 1)
+		require.Equal(t, "correlations.bundleDataSourceNotFound", response.Errors[0].MessageID)
+		require.Equal(t, "Unknown", response.Errors[0].Extra["name"])
+		require.NoError(t, res.Body.Close())
+
+		res = ctx.Get(GetParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations", sourceDs),
+			user: adminUser,
+		})
+		responseBody, err = io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var result []correlations.Correlation
+		err = json.Unmarshal(responseBody, &result)
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		require.NoError(t, res.Body.Close())
+	})
+}
@@ -0,0 +1,94 @@
+package correlations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationCorrelationHealth(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := NewTestEnv(t)
+
+	viewerUser := User{
+		username: "viewer",
+		password: "viewer",
+	}
+
+	ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleViewer),
+		Password:       viewerUser.password,
+		Login:          viewerUser.username,
+	})
+
+	createDsCommand := &datasources.AddDataSourceCommand{
+		Name:  "loki",
+		Type:  "loki",
+		OrgId: 1,
+	}
+	ctx.createDs(createDsCommand)
+	dsUid := createDsCommand.Result.Uid
+
+	correlation := ctx.createCorrelation(correlations.CreateCorrelationCommand{
+		SourceUID: dsUid,
+		TargetUID: &dsUid,
+		OrgId:     1,
+		Config: correlations.CorrelationConfig{
+			Type:   correlations.ConfigTypeQuery,
+			Field:  correlations.CorrelationFields{"traceId"},
+			Target: map[string]interface{}{"expr": "{traceId=\"${traceId}\"}"},
+		},
+	})
+
+	t.Run("Unauthenticated users shouldn't be able to check the health of correlations", func(t *testing.T) {
+		res := ctx.Get(GetParams{
+			url: fmt.Sprintf("/api/datasources/uid/%s/correlations/%s/health", dsUid, correlation.UID),
+		})
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should return 404 for a missing correlation", func(t *testing.T) {
+		res := ctx.Get(GetParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s/health", dsUid, "missing"),
+			user: viewerUser,
+		})
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should return the health report of the correlation", func(t *testing.T) {
+		res := ctx.Get(GetParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s/health", dsUid, correlation.UID),
+			user: viewerUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response correlations.CorrelationHealth
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.True(t, response.Healthy)
+		require.Equal(t, correlation.UID, response.UID)
+		require.Len(t, response.Checks, 5)
+		require.Equal(t, correlations.HealthCheckSource, response.Checks[0].Name)
+		require.Equal(t, correlations.HealthOK, response.Checks[0].Status)
+		require.Equal(t, correlations.HealthCheckTargetQuery, response.Checks[4].Name)
+		require.Equal(t, correlations.HealthSkipped, response.Checks[4].Status)
+
+		require.NoError(t, res.Body.Close())
+	})
+}
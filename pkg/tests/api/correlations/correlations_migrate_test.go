@@ -0,0 +1,127 @@
+package correlations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationMigrateDerivedFields(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := NewTestEnv(t)
+
+	adminUser := User{
+		username: "admin",
+		password: "admin",
+	}
+	editorUser := User{
+		username: "editor",
+		password: "editor",
+	}
+	ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleEditor),
+		Password:       editorUser.password,
+		Login:          editorUser.username,
+	})
+	ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleAdmin),
+		Password:       adminUser.password,
+		Login:          adminUser.username,
+	})
+
+	createDsCommand := &datasources.AddDataSourceCommand{
+		Name:  "Tempo",
+		Type:  "tempo",
+		OrgId: 1,
+	}
+	ctx.createDs(createDsCommand)
+	targetDs := createDsCommand.Result.Uid
+
+	createDsCommand = &datasources.AddDataSourceCommand{
+		Name:  "Loki",
+		Type:  "loki",
+		OrgId: 1,
+		JsonData: simplejson.NewFromAny(map[string]interface{}{
+			"derivedFields": []interface{}{
+				map[string]interface{}{"name": "traceID", "matcherRegex": `traceID=(\w+)`, "url": "${__value.raw}", "datasourceUid": targetDs},
+				map[string]interface{}{"name": "ticket", "matcherRegex": `ticket=(\d+)`, "url": "https://tickets.example.com/${__value.raw}"},
+			},
+		}),
+	}
+	ctx.createDs(createDsCommand)
+	sourceDs := createDsCommand.Result.Uid
+
+	t.Run("Unauthenticated users shouldn't be able to migrate derived fields", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/migrate", sourceDs),
+			body: `{}`,
+		})
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("non org admin shouldn't be able to migrate derived fields", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/migrate", sourceDs),
+			body: `{}`,
+			user: editorUser,
+		})
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should create the correlations of the derived fields linking to a data source", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/migrate", sourceDs),
+			body: `{"deleteDerivedFields": true}`,
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response correlations.MigrateDerivedFieldsResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "Derived fields migrated", response.Message)
+		require.Len(t, response.Result, 1)
+		require.Equal(t, targetDs, *response.Result[0].TargetUID)
+		require.Len(t, response.Skipped, 1)
+		require.Equal(t, "ticket", response.Skipped[0].Name)
+
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should return 400 for a data source other than Loki", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/migrate", targetDs),
+			body: `{}`,
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response errorResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "Only the derived fields of Loki data sources can be migrated", response.Message)
+
+		require.NoError(t, res.Body.Close())
+	})
+}
@@ -0,0 +1,105 @@
+package correlations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationDataSourceWriterCorrelations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := NewTestEnv(t)
+
+	editorUser := User{
+		username: "editor",
+		password: "editor",
+	}
+	editor := ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleEditor),
+		Password:       editorUser.password,
+		Login:          editorUser.username,
+	})
+
+	createDsCommand := &datasources.AddDataSourceCommand{
+		Name:  "editable",
+		Type:  "loki",
+		OrgId: 1,
+	}
+	ctx.createDs(createDsCommand)
+	editableDs := createDsCommand.Result.Uid
+
+	createDsCommand = &datasources.AddDataSourceCommand{
+		Name:  "other",
+		Type:  "loki",
+		OrgId: 1,
+	}
+	ctx.createDs(createDsCommand)
+	otherDs := createDsCommand.Result.Uid
+
+	ctx.grantPermission(editor.ID, datasources.ActionWrite, datasources.ScopeProvider.GetResourceScopeUID(editableDs))
+
+	body := fmt.Sprintf(`{
+		"targetUID": "%s",
+		"label": "sibling",
+		"config": {"type": "query", "field": "message", "target": {}}
+	}`, otherDs)
+
+	var correlationUID string
+	t.Run("Editor who can write a data source should be able to create its correlations", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations", editableDs),
+			body: body,
+			user: editorUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response correlations.CreateCorrelationResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, editableDs, response.Result.SourceUID)
+		correlationUID = response.Result.UID
+
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Editor who can write a data source should be able to update and delete its correlations", func(t *testing.T) {
+		res := ctx.Patch(PatchParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", editableDs, correlationUID),
+			body: `{"label": "renamed"}`,
+			user: editorUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+
+		res = ctx.Delete(DeleteParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", editableDs, correlationUID),
+			user: editorUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Editor shouldn't be able to create correlations of a data source they can't write", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations", otherDs),
+			body: fmt.Sprintf(`{"targetUID": "%s", "config": {"type": "query", "field": "message", "target": {}}}`, editableDs),
+			user: editorUser,
+		})
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
@@ -77,7 +77,7 @@ func TestIntegrationReadCorrelation(t *testing.T) {
 		OrgId:     dsWithCorrelations.OrgId,
 		Config: correlations.CorrelationConfig{
 			Type:   correlations.ConfigTypeQuery,
-			Field:  "foo",
+			Field:  correlations.CorrelationFields{"foo"},
 			Target: map[string]interface{}{},
 		},
 	})
@@ -156,8 +156,11 @@ func TestIntegrationReadCorrelation(t *testing.T) {
 			err = json.Unmarshal(responseBody, &response)
 			require.NoError(t, err)
 
+			// the correlation was never followed
+			expected := correlation
+			expected.Usage = &correlations.CorrelationUsageSummary{}
 			require.Len(t, response, 1)
-			require.EqualValues(t, correlation, response[0])
+			require.EqualValues(t, expected, response[0])
 
 			require.NoError(t, res.Body.Close())
 		})
@@ -207,7 +210,7 @@ func TestIntegrationReadCorrelation(t *testing.T) {
 			err = json.Unmarshal(responseBody, &response)
 			require.NoError(t, err)
 
-			require.Equal(t, "Source data source not found", response.Message)
+			require.Equal(t, "Data source not found", response.Message)
 
 			require.NoError(t, res.Body.Close())
 		})
@@ -252,6 +255,86 @@ func TestIntegrationReadCorrelation(t *testing.T) {
 		})
 	})
 
+	t.Run("Get all correlations pointing at a given data source", func(t *testing.T) {
+		t.Run("Unauthenticated users shouldn't be able to read correlations", func(t *testing.T) {
+			res := ctx.Get(GetParams{
+				url: fmt.Sprintf("/api/datasources/uid/%s/correlations/targets", "some-uid"),
+			})
+			require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+			require.NoError(t, res.Body.Close())
+		})
+
+		t.Run("Authenticated users shouldn't get unauthorized or forbidden errors", func(t *testing.T) {
+			res := ctx.Get(GetParams{
+				url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/targets", "some-uid"),
+				user: viewerUser,
+			})
+			require.NotEqual(t, http.StatusUnauthorized, res.StatusCode)
+			require.NotEqual(t, http.StatusForbidden, res.StatusCode)
+
+			require.NoError(t, res.Body.Close())
+		})
+
+		t.Run("if datasource does not exist it should return 404", func(t *testing.T) {
+			res := ctx.Get(GetParams{
+				url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/targets", "some-uid"),
+				user: adminUser,
+			})
+			require.Equal(t, http.StatusNotFound, res.StatusCode)
+
+			responseBody, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			var response errorResponseBody
+			err = json.Unmarshal(responseBody, &response)
+			require.NoError(t, err)
+
+			require.Equal(t, "Data source not found", response.Message)
+
+			require.NoError(t, res.Body.Close())
+		})
+
+		t.Run("Correlations whose source doesn't exist should not be returned", func(t *testing.T) {
+			res := ctx.Get(GetParams{
+				url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/targets", dsWithoutCorrelations.Uid),
+				user: adminUser,
+			})
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			responseBody, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			var response []correlations.Correlation
+			err = json.Unmarshal(responseBody, &response)
+			require.NoError(t, err)
+
+			require.Len(t, response, 0)
+
+			require.NoError(t, res.Body.Close())
+		})
+
+		t.Run("Should correctly return correlations", func(t *testing.T) {
+			res := ctx.Get(GetParams{
+				url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/targets", dsWithCorrelations.Uid),
+				user: adminUser,
+			})
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			responseBody, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			var response []correlations.Correlation
+			err = json.Unmarshal(responseBody, &response)
+			require.NoError(t, err)
+
+			require.Len(t, response, 1)
+			require.EqualValues(t, correlation, response[0])
+
+			require.NoError(t, res.Body.Close())
+		})
+	})
+
 	t.Run("Get a single correlation", func(t *testing.T) {
 		t.Run("Unauthenticated users shouldn't be able to read correlations", func(t *testing.T) {
 			res := ctx.Get(GetParams{
@@ -296,7 +379,7 @@ func TestIntegrationReadCorrelation(t *testing.T) {
 			err = json.Unmarshal(responseBody, &response)
 			require.NoError(t, err)
 
-			require.Equal(t, "Source data source not found", response.Message)
+			require.Equal(t, "Data source not found", response.Message)
 
 			require.NoError(t, res.Body.Close())
 		})
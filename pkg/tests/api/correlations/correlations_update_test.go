@@ -92,7 +92,7 @@ func TestIntegrationUpdateCorrelation(t *testing.T) {
 		err = json.Unmarshal(responseBody, &response)
 		require.NoError(t, err)
 
-		require.Contains(t, response.Message, "Permissions needed: datasources:write")
+		require.Contains(t, response.Message, "Permissions needed: any of correlations:write, datasources:write")
 
 		require.NoError(t, res.Body.Close())
 	})
@@ -113,7 +113,7 @@ func TestIntegrationUpdateCorrelation(t *testing.T) {
 		require.NoError(t, err)
 
 		require.Equal(t, "Data source not found", response.Message)
-		require.Equal(t, correlations.ErrSourceDataSourceDoesNotExists.Error(), response.Error)
+		require.Equal(t, "correlations.sourceDataSourceNotFound", response.MessageID)
 
 		require.NoError(t, res.Body.Close())
 	})
@@ -136,7 +136,7 @@ func TestIntegrationUpdateCorrelation(t *testing.T) {
 		require.NoError(t, err)
 
 		require.Equal(t, "Correlation not found", response.Message)
-		require.Equal(t, correlations.ErrCorrelationNotFound.Error(), response.Error)
+		require.Equal(t, "correlations.notFound", response.MessageID)
 
 		require.NoError(t, res.Body.Close())
 	})
@@ -157,75 +157,69 @@ func TestIntegrationUpdateCorrelation(t *testing.T) {
 		require.NoError(t, err)
 
 		require.Equal(t, "Data source is read only", response.Message)
-		require.Equal(t, correlations.ErrSourceDataSourceReadOnly.Error(), response.Error)
+		require.Equal(t, "correlations.sourceDataSourceReadOnly", response.MessageID)
 
 		require.NoError(t, res.Body.Close())
 	})
 
-	t.Run("updating a correlation without data should result in a 400", func(t *testing.T) {
+	t.Run("updating a correlation without data should leave it unchanged", func(t *testing.T) {
 		correlation := ctx.createCorrelation(correlations.CreateCorrelationCommand{
-			SourceUID: writableDs,
-			TargetUID: &writableDs,
-			OrgId:     writableDsOrgId,
-		})
-
-		// no params
-		res := ctx.Patch(PatchParams{
-			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", correlation.SourceUID, correlation.UID),
-			user: adminUser,
-			body: `{}`,
+			SourceUID:   writableDs,
+			TargetUID:   &writableDs,
+			OrgId:       writableDsOrgId,
+			Label:       "a label",
+			Description: "a description",
 		})
-		require.Equal(t, http.StatusBadRequest, res.StatusCode)
-
-		responseBody, err := io.ReadAll(res.Body)
-		require.NoError(t, err)
 
-		var response errorResponseBody
-		err = json.Unmarshal(responseBody, &response)
-		require.NoError(t, err)
-
-		require.Equal(t, "At least one of label, description or config is required", response.Message)
-		require.Equal(t, correlations.ErrUpdateCorrelationEmptyParams.Error(), response.Error)
-		require.NoError(t, res.Body.Close())
+		for _, body := range []string{`{}`, ``, `{"config": null}`} {
+			res := ctx.Patch(PatchParams{
+				url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", correlation.SourceUID, correlation.UID),
+				user: adminUser,
+				body: body,
+			})
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			responseBody, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			var response correlations.UpdateCorrelationResponseBody
+			err = json.Unmarshal(responseBody, &response)
+			require.NoError(t, err)
+
+			require.Equal(t, "a label", response.Result.Label)
+			require.Equal(t, "a description", response.Result.Description)
+			require.NoError(t, res.Body.Close())
+		}
+	})
 
-		// empty body
-		res = ctx.Patch(PatchParams{
-			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", correlation.SourceUID, correlation.UID),
-			user: adminUser,
-			body: ``,
+	t.Run("updating fields to null should clear them", func(t *testing.T) {
+		correlation := ctx.createCorrelation(correlations.CreateCorrelationCommand{
+			SourceUID:   writableDs,
+			TargetUID:   &writableDs,
+			OrgId:       writableDsOrgId,
+			Label:       "a label",
+			Description: "a description",
 		})
-		require.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-		responseBody, err = io.ReadAll(res.Body)
-		require.NoError(t, err)
-
-		err = json.Unmarshal(responseBody, &response)
-		require.NoError(t, err)
-
-		require.Equal(t, "At least one of label, description or config is required", response.Message)
-		require.Equal(t, correlations.ErrUpdateCorrelationEmptyParams.Error(), response.Error)
-		require.NoError(t, res.Body.Close())
-
-		// all set to null
-		res = ctx.Patch(PatchParams{
+		res := ctx.Patch(PatchParams{
 			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", correlation.SourceUID, correlation.UID),
 			user: adminUser,
 			body: `{
 						"label": null,
-						"description": null,
-						"config": null
+						"description": null
 					}`,
 		})
-		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.Equal(t, http.StatusOK, res.StatusCode)
 
-		responseBody, err = io.ReadAll(res.Body)
+		responseBody, err := io.ReadAll(res.Body)
 		require.NoError(t, err)
 
+		var response correlations.UpdateCorrelationResponseBody
 		err = json.Unmarshal(responseBody, &response)
 		require.NoError(t, err)
 
-		require.Equal(t, "At least one of label, description or config is required", response.Message)
-		require.Equal(t, correlations.ErrUpdateCorrelationEmptyParams.Error(), response.Error)
+		require.Empty(t, response.Result.Label)
+		require.Empty(t, response.Result.Description)
 		require.NoError(t, res.Body.Close())
 	})
 
@@ -266,7 +260,7 @@ func TestIntegrationUpdateCorrelation(t *testing.T) {
 			Label:       "0",
 			Description: "0",
 			Config: correlations.CorrelationConfig{
-				Field:  "fieldName",
+				Field:  correlations.CorrelationFields{"fieldName"},
 				Type:   "query",
 				Target: map[string]interface{}{"expr": "foo"},
 			},
@@ -298,7 +292,7 @@ func TestIntegrationUpdateCorrelation(t *testing.T) {
 		require.Equal(t, "Correlation updated", response.Message)
 		require.Equal(t, "1", response.Result.Label)
 		require.Equal(t, "1", response.Result.Description)
-		require.Equal(t, "field", response.Result.Config.Field)
+		require.Equal(t, correlations.CorrelationFields{"field"}, response.Result.Config.Field)
 		require.Equal(t, map[string]interface{}{"expr": "bar"}, response.Result.Config.Target)
 		require.NoError(t, res.Body.Close())
 
@@ -321,7 +315,7 @@ func TestIntegrationUpdateCorrelation(t *testing.T) {
 		require.Equal(t, "Correlation updated", response.Message)
 		require.Equal(t, "2", response.Result.Label)
 		require.Equal(t, "1", response.Result.Description)
-		require.Equal(t, "field", response.Result.Config.Field)
+		require.Equal(t, correlations.CorrelationFields{"field"}, response.Result.Config.Field)
 		require.Equal(t, map[string]interface{}{"expr": "bar"}, response.Result.Config.Target)
 		require.NoError(t, res.Body.Close())
 
@@ -344,7 +338,7 @@ func TestIntegrationUpdateCorrelation(t *testing.T) {
 		require.Equal(t, "Correlation updated", response.Message)
 		require.Equal(t, "2", response.Result.Label)
 		require.Equal(t, "2", response.Result.Description)
-		require.Equal(t, "field", response.Result.Config.Field)
+		require.Equal(t, correlations.CorrelationFields{"field"}, response.Result.Config.Field)
 		require.Equal(t, map[string]interface{}{"expr": "bar"}, response.Result.Config.Target)
 		require.NoError(t, res.Body.Close())
 
@@ -371,7 +365,7 @@ func TestIntegrationUpdateCorrelation(t *testing.T) {
 		require.Equal(t, "Correlation updated", response.Message)
 		require.Equal(t, "2", response.Result.Label)
 		require.Equal(t, "2", response.Result.Description)
-		require.Equal(t, "name", response.Result.Config.Field)
+		require.Equal(t, correlations.CorrelationFields{"name"}, response.Result.Config.Field)
 		require.Equal(t, map[string]interface{}{"expr": "baz"}, response.Result.Config.Target)
 		require.NoError(t, res.Body.Close())
 
@@ -396,7 +390,7 @@ func TestIntegrationUpdateCorrelation(t *testing.T) {
 		require.Equal(t, "Correlation updated", response.Message)
 		require.Equal(t, "2", response.Result.Label)
 		require.Equal(t, "2", response.Result.Description)
-		require.Equal(t, "newName", response.Result.Config.Field)
+		require.Equal(t, correlations.CorrelationFields{"newName"}, response.Result.Config.Field)
 		require.Equal(t, map[string]interface{}{"expr": "baz"}, response.Result.Config.Target)
 		require.NoError(t, res.Body.Close())
 
@@ -421,7 +415,7 @@ func TestIntegrationUpdateCorrelation(t *testing.T) {
 		require.Equal(t, "Correlation updated", response.Message)
 		require.Equal(t, "2", response.Result.Label)
 		require.Equal(t, "2", response.Result.Description)
-		require.Equal(t, "newName", response.Result.Config.Field)
+		require.Equal(t, correlations.CorrelationFields{"newName"}, response.Result.Config.Field)
 		require.Equal(t, map[string]interface{}{"expr": "foo"}, response.Result.Config.Target)
 		require.NoError(t, res.Body.Close())
 
@@ -448,7 +442,7 @@ func TestIntegrationUpdateCorrelation(t *testing.T) {
 		require.Equal(t, "Correlation updated", response.Message)
 		require.Equal(t, "", response.Result.Label)
 		require.Equal(t, "", response.Result.Description)
-		require.Equal(t, "", response.Result.Config.Field)
+		require.Empty(t, response.Result.Config.Field)
 		require.NoError(t, res.Body.Close())
 	})
 }
@@ -0,0 +1,202 @@
+package correlations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationUpsertCorrelation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := NewTestEnv(t)
+
+	adminUser := User{
+		username: "admin",
+		password: "admin",
+	}
+	editorUser := User{
+		username: "editor",
+		password: "editor",
+	}
+
+	ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleEditor),
+		Password:       editorUser.password,
+		Login:          editorUser.username,
+	})
+	ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleAdmin),
+		Password:       adminUser.password,
+		Login:          adminUser.username,
+	})
+
+	createDsCommand := &datasources.AddDataSourceCommand{
+		Name:     "read-only",
+		Type:     "loki",
+		ReadOnly: true,
+		OrgId:    1,
+	}
+	ctx.createDs(createDsCommand)
+	readOnlyDS := createDsCommand.Result.Uid
+
+	createDsCommand = &datasources.AddDataSourceCommand{
+		Name:  "writable",
+		Type:  "loki",
+		OrgId: 1,
+	}
+	ctx.createDs(createDsCommand)
+	writableDs := createDsCommand.Result.Uid
+
+	body := fmt.Sprintf(`{
+		"targetUID": "%s",
+		"label": "%%s",
+		"config": {
+			"type": "query",
+			"field": "message",
+			"target": {}
+		}
+	}`, writableDs)
+
+	t.Run("Unauthenticated users shouldn't be able to upsert correlations", func(t *testing.T) {
+		res := ctx.Put(PutParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", "some-ds-uid", "some-correlation-uid"),
+			body: ``,
+		})
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response errorResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "Unauthorized", response.Message)
+
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("non org admin shouldn't be able to upsert correlations", func(t *testing.T) {
+		res := ctx.Put(PutParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", "some-ds-uid", "some-correlation-uid"),
+			body: fmt.Sprintf(body, "a label"),
+			user: editorUser,
+		})
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response errorResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Contains(t, response.Message, "correlations:create")
+		require.Contains(t, response.Message, "correlations:write")
+
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("inexistent source data source should result in a 404", func(t *testing.T) {
+		res := ctx.Put(PutParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", "some-ds-uid", "some-correlation-uid"),
+			body: fmt.Sprintf(body, "a label"),
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response errorResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "correlations.sourceDataSourceNotFound", response.MessageID)
+
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("upserting a correlation originating from a read-only data source should result in a 403", func(t *testing.T) {
+		res := ctx.Put(PutParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", readOnlyDS, "some-correlation-uid"),
+			body: fmt.Sprintf(body, "a label"),
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response errorResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "correlations.sourceDataSourceReadOnly", response.MessageID)
+
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("a UID in the body different from the path should result in a 400", func(t *testing.T) {
+		res := ctx.Put(PutParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", writableDs, "some-correlation-uid"),
+			body: `{"uid": "other-uid", "config": {"type": "query", "field": "message", "target": {}}}`,
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response errorResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "correlations.invalidUID", response.MessageID)
+
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should create the correlation, then replace it", func(t *testing.T) {
+		upsert := func(label string) correlations.UpsertCorrelationResponseBody {
+			res := ctx.Put(PutParams{
+				url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", writableDs, "logs-to-logs"),
+				body: fmt.Sprintf(body, label),
+				user: adminUser,
+			})
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			responseBody, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			var response correlations.UpsertCorrelationResponseBody
+			err = json.Unmarshal(responseBody, &response)
+			require.NoError(t, err)
+
+			require.NoError(t, res.Body.Close())
+			return response
+		}
+
+		created := upsert("a label")
+		require.True(t, created.Created)
+		require.Equal(t, "Correlation created", created.Message)
+		require.Equal(t, "logs-to-logs", created.Result.UID)
+		require.Equal(t, "a label", created.Result.Label)
+
+		replaced := upsert("another label")
+		require.False(t, replaced.Created)
+		require.Equal(t, "Correlation updated", replaced.Message)
+		require.Equal(t, "logs-to-logs", replaced.Result.UID)
+		require.Equal(t, "another label", replaced.Result.Label)
+	})
+}
@@ -0,0 +1,101 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/plugins"
+)
+
+// FakeDataSources is a plugin client answering the queries of every data
+// source with the frames configured for its UID and the query refID. It can
+// be passed wherever a plugins.Client is expected, e.g. to the query or the
+// expressions service.
+type FakeDataSources struct {
+	plugins.Client
+
+	mtx      sync.Mutex
+	frames   map[string]map[string]data.Frames
+	requests []*backend.QueryDataRequest
+}
+
+var _ plugins.Client = &FakeDataSources{}
+
+func NewFakeDataSources() *FakeDataSources {
+	return &FakeDataSources{
+		frames: map[string]map[string]data.Frames{},
+	}
+}
+
+// SetFrames sets the frames returned for the queries with the refID sent to
+// the data source with the UID.
+func (f *FakeDataSources) SetFrames(uid, refID string, frames ...*data.Frame) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.frames[uid] == nil {
+		f.frames[uid] = map[string]data.Frames{}
+	}
+	f.frames[uid][refID] = frames
+}
+
+// Requests returns the query requests received so far.
+func (f *FakeDataSources) Requests() []*backend.QueryDataRequest {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return append([]*backend.QueryDataRequest(nil), f.requests...)
+}
+
+// QueryData answers every query with copies of the configured frames, queries
+// without frames get an error response.
+func (f *FakeDataSources) QueryData(_ context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.requests = append(f.requests, req)
+
+	var uid string
+	if req.PluginContext.DataSourceInstanceSettings != nil {
+		uid = req.PluginContext.DataSourceInstanceSettings.UID
+	}
+
+	res := backend.NewQueryDataResponse()
+	for _, q := range req.Queries {
+		frames, ok := f.frames[uid][q.RefID]
+		if !ok {
+			res.Responses[q.RefID] = backend.DataResponse{
+				Error: fmt.Errorf("no frames configured for data source %q and refID %q", uid, q.RefID),
+			}
+			continue
+		}
+
+		// copy the frames, the callers can modify them, e.g. when masking
+		copies := make(data.Frames, 0, len(frames))
+		for _, frame := range frames {
+			copies = append(copies, copyFrame(frame))
+		}
+		res.Responses[q.RefID] = backend.DataResponse{Frames: copies}
+	}
+	return res, nil
+}
+
+func copyFrame(frame *data.Frame) *data.Frame {
+	fields := make([]*data.Field, 0, len(frame.Fields))
+	for _, field := range frame.Fields {
+		copied := data.NewFieldFromFieldType(field.Type(), field.Len())
+		copied.Name = field.Name
+		copied.Labels = field.Labels.Copy()
+		copied.Config = field.Config
+		for i := 0; i < field.Len(); i++ {
+			copied.Set(i, field.CopyAt(i))
+		}
+		fields = append(fields, copied)
+	}
+	copied := data.NewFrame(frame.Name, fields...)
+	copied.RefID = frame.RefID
+	copied.Meta = frame.Meta
+	return copied
+}
@@ -0,0 +1,120 @@
+package harness
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func post(t *testing.T, url, body string) (int, string) {
+	t.Helper()
+
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(b)
+}
+
+func TestReceivers(t *testing.T) {
+	t.Run("webhook receiver captures the requests", func(t *testing.T) {
+		r := NewWebhookReceiver(t)
+
+		status, _ := post(t, r.URL()+"/hook", `{"status":"firing"}`)
+		require.Equal(t, http.StatusOK, status)
+
+		requests := r.WaitForRequests(t, 1, time.Second)
+		require.Len(t, requests, 1)
+		require.Equal(t, http.MethodPost, requests[0].Method)
+		require.Equal(t, "/hook", requests[0].Path)
+		require.Equal(t, "application/json", requests[0].Header.Get("Content-Type"))
+
+		var body map[string]string
+		require.NoError(t, requests[0].JSON(&body))
+		require.Equal(t, "firing", body["status"])
+	})
+
+	t.Run("slack receiver answers ok", func(t *testing.T) {
+		r := NewSlackReceiver(t)
+
+		status, body := post(t, r.URL()+"/api/chat.postMessage", `{"channel":"#alerts"}`)
+		require.Equal(t, http.StatusOK, status)
+		require.JSONEq(t, `{"ok":true}`, body)
+	})
+
+	t.Run("airflow receiver triggers dag runs", func(t *testing.T) {
+		r := NewAirflowReceiver(t)
+
+		status, body := post(t, r.URL()+"/api/v1/dags/cleanup/dagRuns", `{"dag_run_id":"run-1","conf":{"alert":"high"}}`)
+		require.Equal(t, http.StatusOK, status)
+		require.JSONEq(t, `{"dag_id":"cleanup","dag_run_id":"run-1","conf":{"alert":"high"},"state":"queued"}`, body)
+
+		status, _ = post(t, r.URL()+"/api/v1/dags", `{}`)
+		require.Equal(t, http.StatusNotFound, status)
+		require.Len(t, r.Requests(), 2)
+	})
+
+	t.Run("failing receiver still captures the requests", func(t *testing.T) {
+		r := NewSlackReceiver(t)
+		r.FailWith(http.StatusServiceUnavailable)
+
+		status, _ := post(t, r.URL(), `{}`)
+		require.Equal(t, http.StatusServiceUnavailable, status)
+		require.Len(t, r.Requests(), 1)
+
+		r.FailWith(0)
+		status, _ = post(t, r.URL(), `{}`)
+		require.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("waiting for requests", func(t *testing.T) {
+		r := NewWebhookReceiver(t)
+
+		go func() {
+			for i := 0; i < 3; i++ {
+				resp, err := http.Post(r.URL(), "application/json", strings.NewReader(`{}`))
+				if err == nil {
+					_ = resp.Body.Close()
+				}
+			}
+		}()
+
+		require.Len(t, r.WaitForRequests(t, 3, 5*time.Second), 3)
+	})
+}
+
+func TestFakeDataSources(t *testing.T) {
+	fds := NewFakeDataSources()
+	fds.SetFrames("ds1", "A", data.NewFrame("A", data.NewField("value", nil, []float64{1, 2})))
+
+	query := func(uid string) *backend.QueryDataResponse {
+		res, err := fds.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: uid},
+			},
+			Queries: []backend.DataQuery{{RefID: "A"}},
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	res := query("ds1")
+	require.NoError(t, res.Responses["A"].Error)
+	require.Len(t, res.Responses["A"].Frames, 1)
+
+	// the frames are copies, modifying them does not change the configured ones
+	res.Responses["A"].Frames[0].Fields[0].Set(0, float64(10))
+	require.Equal(t, float64(1), query("ds1").Responses["A"].Frames[0].Fields[0].At(0))
+
+	require.Error(t, query("ds2").Responses["A"].Error)
+	require.Len(t, fds.Requests(), 3)
+}
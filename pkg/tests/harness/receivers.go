@@ -0,0 +1,185 @@
+// Package harness provides in-process fakes of the external systems Grafana
+// talks to, so that integration tests can run a feature end to end and assert
+// on what left Grafana: receivers standing in for webhook, Slack and Airflow
+// endpoints, and data sources serving canned frames.
+package harness
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Request is a request captured by a Receiver.
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// JSON decodes the body of the request into v.
+func (r Request) JSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Receiver is an HTTP server capturing the requests it receives. The server
+// is closed when the test finishes.
+type Receiver struct {
+	server  *httptest.Server
+	respond http.HandlerFunc
+
+	mtx        sync.Mutex
+	requests   []Request
+	failStatus int
+	received   chan struct{}
+}
+
+func newReceiver(t testing.TB, respond http.HandlerFunc) *Receiver {
+	t.Helper()
+
+	r := &Receiver{
+		respond:  respond,
+		received: make(chan struct{}, 1),
+	}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+// NewWebhookReceiver returns a receiver answering every request with 200 OK
+// and an empty body, like most webhook endpoints.
+func NewWebhookReceiver(t testing.TB) *Receiver {
+	return newReceiver(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// NewSlackReceiver returns a receiver answering like the Slack chat API and
+// incoming webhooks do on success.
+func NewSlackReceiver(t testing.TB) *Receiver {
+	return newReceiver(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	})
+}
+
+// NewAirflowReceiver returns a receiver implementing the DAG run trigger of
+// the Airflow REST API, POST /api/v1/dags/{dag_id}/dagRuns. Any other request
+// is answered with 404 Not Found.
+func NewAirflowReceiver(t testing.TB) *Receiver {
+	return newReceiver(t, func(w http.ResponseWriter, req *http.Request) {
+		dagID, ok := airflowDAGID(req.URL.Path)
+		if !ok || req.Method != http.MethodPost {
+			http.NotFound(w, req)
+			return
+		}
+
+		var body struct {
+			DAGRunID string                 `json:"dag_run_id"`
+			Conf     map[string]interface{} `json:"conf"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil && err != io.EOF {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if body.DAGRunID == "" {
+			body.DAGRunID = fmt.Sprintf("manual__%s", time.Now().UTC().Format(time.RFC3339))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"dag_id":     dagID,
+			"dag_run_id": body.DAGRunID,
+			"conf":       body.Conf,
+			"state":      "queued",
+		})
+	})
+}
+
+func airflowDAGID(path string) (string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 5 || parts[0] != "api" || parts[1] != "v1" || parts[2] != "dags" || parts[4] != "dagRuns" {
+		return "", false
+	}
+	return parts[3], parts[3] != ""
+}
+
+// URL returns the base URL of the receiver.
+func (r *Receiver) URL() string {
+	return r.server.URL
+}
+
+// FailWith makes the receiver answer the following requests with the status
+// code. The requests are still captured. A status of 0 restores the regular
+// responses.
+func (r *Receiver) FailWith(status int) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.failStatus = status
+}
+
+// Requests returns a copy of the requests captured so far.
+func (r *Receiver) Requests() []Request {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return append([]Request(nil), r.requests...)
+}
+
+// WaitForRequests waits until the receiver captured at least n requests and
+// returns them, it fails the test once the timeout elapses.
+func (r *Receiver) WaitForRequests(t testing.TB, n int, timeout time.Duration) []Request {
+	t.Helper()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		if requests := r.Requests(); len(requests) >= n {
+			return requests
+		}
+		select {
+		case <-r.received:
+		case <-deadline.C:
+			t.Fatalf("expected %d requests within %s, got %d", n, timeout, len(r.Requests()))
+			return nil
+		}
+	}
+}
+
+func (r *Receiver) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.mtx.Lock()
+	r.requests = append(r.requests, Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Header: req.Header.Clone(),
+		Body:   body,
+	})
+	failStatus := r.failStatus
+	r.mtx.Unlock()
+
+	select {
+	case r.received <- struct{}{}:
+	default:
+	}
+
+	if failStatus != 0 {
+		http.Error(w, http.StatusText(failStatus), failStatus)
+		return
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	r.respond(w, req)
+}
@@ -222,6 +222,15 @@ func (e Error) Is(other error) bool {
 	}
 }
 
+// PublicErr is implemented by errors that can be relayed to the end
+// user as a [PublicError]. [Error] implements it, as can the error
+// types of services that predate [Base], which lets API clients branch
+// on the message ID of any of them.
+type PublicErr interface {
+	error
+	Public() PublicError
+}
+
 // PublicError is derived from Error and only contains information
 // available to the end user.
 type PublicError struct {
@@ -28,6 +28,10 @@ const (
 	// parameters or payload for the request.
 	// HTTP status code 400.
 	StatusBadRequest CoreStatus = "Bad request"
+	// StatusConflict means that the request conflicts with the current
+	// state of the server, for example a resource that already exists.
+	// HTTP status code 409.
+	StatusConflict CoreStatus = "Conflict"
 	// StatusValidationFailed means that the server was able to parse
 	// the payload for the request but it failed one or more validation
 	// checks.
@@ -75,6 +79,8 @@ func (s CoreStatus) HTTPStatus() int {
 		return http.StatusTooManyRequests
 	case StatusBadRequest, StatusValidationFailed:
 		return http.StatusBadRequest
+	case StatusConflict:
+		return http.StatusConflict
 	case StatusNotImplemented:
 		return http.StatusNotImplemented
 	case StatusUnknown, StatusInternal:
@@ -99,6 +105,8 @@ func (s CoreStatus) LogLevel() LogLevel {
 		return LevelDebug
 	case StatusBadRequest:
 		return LevelDebug
+	case StatusConflict:
+		return LevelDebug
 	case StatusValidationFailed:
 		return LevelDebug
 	case StatusNotImplemented:
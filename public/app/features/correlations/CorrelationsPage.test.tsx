@@ -365,14 +365,14 @@ describe('CorrelationsPage', () => {
             targetUID: 'loki',
             uid: '1',
             label: 'Some label',
-            config: { field: 'line', target: {}, type: 'query' },
+            config: { field: ['line'], target: {}, type: 'query' },
           },
           {
             sourceUID: 'prometheus',
             targetUID: 'loki',
             uid: '2',
             label: 'Prometheus to Loki',
-            config: { field: 'label', target: {}, type: 'query' },
+            config: { field: ['label'], target: {}, type: 'query' },
           },
         ]
       );
@@ -492,7 +492,7 @@ describe('CorrelationsPage', () => {
         targetUID: 'loki',
         uid: '1',
         label: 'Some label',
-        config: { field: 'line', target: {}, type: 'query' },
+        config: { field: ['line'], target: {}, type: 'query' },
       },
     ];
 
@@ -542,4 +542,51 @@ describe('CorrelationsPage', () => {
       expect(screen.queryByRole('button', { name: 'save' })).not.toBeInTheDocument();
     });
   });
+
+  describe('Provisioned correlations', () => {
+    const correlations: Correlation[] = [
+      {
+        sourceUID: 'loki',
+        targetUID: 'loki',
+        uid: '1',
+        label: 'Some label',
+        config: { field: ['line'], target: {}, type: 'query' },
+        provisioned: true,
+      },
+    ];
+
+    beforeEach(async () => {
+      await renderWithContext(
+        {
+          loki: mockDataSource({
+            uid: 'loki',
+            name: 'loki',
+            readOnly: false,
+            jsonData: {},
+            access: 'direct',
+            meta: { info: { logos: {} } } as DataSourcePluginMeta,
+            type: 'datasource',
+          }),
+        },
+        correlations
+      );
+    });
+
+    it("doesn't render delete button", async () => {
+      expect(screen.getByRole('cell', { name: /some label/i })).toBeInTheDocument();
+      expect(screen.getByText('Provisioned')).toBeInTheDocument();
+
+      expect(screen.queryByRole('button', { name: /delete correlation/i })).not.toBeInTheDocument();
+    });
+
+    it('edit form is read only', async () => {
+      fireEvent.click(screen.getByRole('button', { name: /toggle row expanded/i }));
+
+      await waitForElementToBeRemoved(() => screen.queryByText(/loading query editor/i));
+
+      expect(screen.getByRole('textbox', { name: /label/i })).toHaveAttribute('readonly');
+      expect(screen.getByRole('textbox', { name: /description/i })).toHaveAttribute('readonly');
+      expect(screen.queryByRole('button', { name: 'save' })).not.toBeInTheDocument();
+    });
+  });
 });
@@ -20,7 +20,9 @@ import { CorrelationData, useCorrelations } from './useCorrelations';
 const sortDatasource: SortByFn<CorrelationData> = (a, b, column) =>
   a.values[column].name.localeCompare(b.values[column].name);
 
-const isSourceReadOnly = ({ source }: Pick<CorrelationData, 'source'>) => source.readOnly;
+// provisioned correlations can only be edited through provisioning, like the ones of read only data sources
+const isReadOnly = ({ source, provisioned }: Pick<CorrelationData, 'source' | 'provisioned'>) =>
+  source.readOnly || Boolean(provisioned);
 
 const loaderWrapper = css`
   display: flex;
@@ -41,7 +43,11 @@ export default function CorrelationsPage() {
     // eslint-disable-next-line react-hooks/exhaustive-deps
   }, []);
 
-  const canWriteCorrelations = contextSrv.hasPermission(AccessControlAction.DataSourcesWrite);
+  // users who can edit a data source can manage its correlations
+  const canWriteDataSources = contextSrv.hasPermission(AccessControlAction.DataSourcesWrite);
+  const canCreateCorrelations = canWriteDataSources || contextSrv.hasPermission(AccessControlAction.CorrelationsCreate);
+  const canWriteCorrelations = canWriteDataSources || contextSrv.hasPermission(AccessControlAction.CorrelationsWrite);
+  const canDeleteCorrelations = canWriteDataSources || contextSrv.hasPermission(AccessControlAction.CorrelationsDelete);
 
   const handleAdd = useCallback(() => {
     fetchCorrelations();
@@ -55,18 +61,11 @@ export default function CorrelationsPage() {
   }, [remove.error, remove.loading, remove.value, fetchCorrelations]);
 
   const RowActions = useCallback(
-    ({
-      row: {
-        original: {
-          source: { uid: sourceUID, readOnly },
-          uid,
-        },
-      },
-    }: CellProps<CorrelationData, void>) =>
-      !readOnly && (
+    ({ row: { original: correlation } }: CellProps<CorrelationData, void>) =>
+      !isReadOnly(correlation) && (
         <DeleteButton
           aria-label="delete correlation"
-          onConfirm={() => remove.execute({ sourceUID, uid })}
+          onConfirm={() => remove.execute({ sourceUID: correlation.source.uid, uid: correlation.uid })}
           closeOnConfirm
         />
       ),
@@ -78,7 +77,7 @@ export default function CorrelationsPage() {
       {
         cell: InfoCell,
         shrink: true,
-        visible: (data) => data.some(isSourceReadOnly),
+        visible: (data) => data.some(isReadOnly),
       },
       {
         id: 'source',
@@ -96,10 +95,10 @@ export default function CorrelationsPage() {
       {
         cell: RowActions,
         shrink: true,
-        visible: (data) => canWriteCorrelations && data.some(negate(isSourceReadOnly)),
+        visible: (data) => canDeleteCorrelations && data.some(negate(isReadOnly)),
       },
     ],
-    [RowActions, canWriteCorrelations]
+    [RowActions, canDeleteCorrelations]
   );
 
   const data = useMemo(() => get.value, [get.value]);
@@ -115,7 +114,7 @@ export default function CorrelationsPage() {
               <h4>Correlations</h4>
               <p>Define how data living in different data sources relates to each other.</p>
             </div>
-            {canWriteCorrelations && data?.length !== 0 && data !== undefined && !isAdding && (
+            {canCreateCorrelations && data?.length !== 0 && data !== undefined && !isAdding && (
               <Button icon="plus" onClick={() => setIsAdding(true)}>
                 Add new
               </Button>
@@ -150,7 +149,7 @@ export default function CorrelationsPage() {
                 <EditCorrelationForm
                   correlation={{ ...correlation, sourceUID: source.uid, targetUID: target.uid }}
                   onUpdated={fetchCorrelations}
-                  readOnly={isSourceReadOnly({ source }) || !canWriteCorrelations}
+                  readOnly={isReadOnly({ source, provisioned: correlation.provisioned }) || !canWriteCorrelations}
                 />
               )}
               columns={columns}
@@ -200,13 +199,17 @@ const noWrap = css`
 
 const InfoCell = memo(
   function InfoCell({ ...props }: CellProps<CorrelationData, void>) {
-    const readOnly = props.row.original.source.readOnly;
+    const { source, provisioned } = props.row.original;
 
-    if (readOnly) {
+    if (provisioned) {
+      return <Badge text="Provisioned" color="purple" className={noWrap} />;
+    } else if (source.readOnly) {
       return <Badge text="Read only" color="purple" className={noWrap} />;
     } else {
       return null;
     }
   },
-  (props, prevProps) => props.row.original.source.readOnly === prevProps.row.original.source.readOnly
+  (props, prevProps) =>
+    props.row.original.source.readOnly === prevProps.row.original.source.readOnly &&
+    props.row.original.provisioned === prevProps.row.original.provisioned
 );
@@ -18,6 +18,15 @@ const getInputId = (inputName: string, correlation?: CorrelationBaseData) => {
   return `${inputName}_${correlation.sourceUID}-${correlation.uid}`;
 };
 
+// The target fields are edited as a comma separated list
+const parseFields = (value: string | string[]) =>
+  Array.isArray(value)
+    ? value
+    : value
+        .split(',')
+        .map((field) => field.trim())
+        .filter((field) => field !== '');
+
 const getStyles = (theme: GrafanaTheme2) => ({
   label: css`
     max-width: ${theme.spacing(32)};
@@ -64,13 +73,15 @@ export function CorrelationDetailsFormPart({ readOnly = false, correlation }: Pr
 
       <Field
         label="Target field"
+        description="Comma separated list of the fields the target can use, the link is attached to the first one"
         className={styles.label}
         invalid={!!errors?.config?.field}
+        // @ts-expect-error react-hook-form's errors do not work well with array types
         error={errors?.config?.field?.message}
       >
         <Input
           id={getInputId('field', correlation)}
-          {...register('config.field', { required: 'This field is required.' })}
+          {...register('config.field', { required: 'This field is required.', setValueAs: parseFields })}
           readOnly={readOnly}
         />
       </Field>
@@ -5,19 +5,69 @@ export interface AddCorrelationResponse {
 export type GetCorrelationsResponse = Correlation[];
 
 type CorrelationConfigType = 'query';
-export interface CorrelationConfig {
+
+export type TransformationType = 'regex' | 'logfmt' | 'mapping';
+export interface Transformation {
+  type: TransformationType;
+  expression?: string;
+  field?: string;
+  mapValue?: string;
+  mapping?: Record<string, string>;
+}
+
+export type CorrelationMatcherOperator = 'equals' | 'notEquals' | 'regex' | 'notRegex';
+export interface CorrelationMatcher {
   field: string;
-  target: object;
+  operator: CorrelationMatcherOperator;
+  value: string;
+}
+
+export interface CorrelationDisplay {
+  openIn?: 'split' | 'newTab';
+  icon?: string;
+  title?: string;
+}
+
+export interface CorrelationConfig {
+  field: string[];
+  target: object | object[];
   type: CorrelationConfigType;
+  transformations?: Transformation[];
+  matchers?: CorrelationMatcher[];
+  display?: CorrelationDisplay;
+}
+
+export interface CorrelationUsage {
+  resolutions: number;
+  lastResolvedAt: number;
+}
+
+export interface CorrelationDataSource {
+  uid: string;
+  name: string;
+  type: string;
+}
+
+export interface CorrelationVisibility {
+  roles?: Array<'Viewer' | 'Editor' | 'Admin'>;
+  teams?: number[];
 }
 
 export interface Correlation {
   uid: string;
   sourceUID: string;
+  sourceType?: string;
   targetUID: string;
   label?: string;
   description?: string;
   config: CorrelationConfig;
+  order?: number;
+  provisioned?: boolean;
+  tags?: string[];
+  usage?: CorrelationUsage;
+  sourceDataSource?: CorrelationDataSource;
+  targetDataSource?: CorrelationDataSource;
+  visibility?: CorrelationVisibility;
 }
 
 export type RemoveCorrelationParams = Pick<Correlation, 'sourceUID' | 'uid'>;
@@ -53,6 +53,11 @@ export enum AccessControlAction {
   DataSourcesCachingRead = 'datasources.caching:read',
   DataSourcesInsightsRead = 'datasources.insights:read',
 
+  CorrelationsRead = 'correlations:read',
+  CorrelationsCreate = 'correlations:create',
+  CorrelationsWrite = 'correlations:write',
+  CorrelationsDelete = 'correlations:delete',
+
   ActionServerStatsRead = 'server.stats:read',
 
   ActionTeamsCreate = 'teams:create',